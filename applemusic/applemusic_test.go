@@ -65,3 +65,29 @@ func TestParseAppleMusicURL(t *testing.T) {
 		})
 	}
 }
+
+func TestParseArtistLookup(t *testing.T) {
+	body := []byte(`{"resultCount":4,"results":[
+		{"wrapperType":"artist","artistName":"Radiohead"},
+		{"wrapperType":"track","artistName":"Radiohead","trackName":"Creep","collectionName":"Pablo Honey"},
+		{"wrapperType":"track","artistName":"Radiohead","trackName":"Karma Police","collectionName":"OK Computer"},
+		{"wrapperType":"track","artistName":"Radiohead","trackName":"No Surprises","collectionName":"OK Computer"}]}`)
+
+	result, err := parseArtistLookup(body, 2)
+	if err != nil {
+		t.Fatalf("parseArtistLookup: %v", err)
+	}
+	if result.Name != "Radiohead" {
+		t.Errorf("Name = %q", result.Name)
+	}
+	if len(result.Tracks) != 2 {
+		t.Fatalf("got %d tracks, want 2", len(result.Tracks))
+	}
+	if tr := result.Tracks[1]; tr.Title != "Karma Police" || tr.Album != "OK Computer" || tr.Position != 1 {
+		t.Errorf("second track = %+v", tr)
+	}
+
+	if _, err := parseArtistLookup([]byte(`{"resultCount":0,"results":[]}`), 5); err == nil {
+		t.Error("expected an error for an unknown artist")
+	}
+}
@@ -147,9 +147,46 @@ func GetPlaylistTracks(ctx context.Context, country, playlistID string, limit in
 	return playlistResult, nil
 }
 
-// GetArtistTopSongs fetches top songs for an artist from Apple Music
-// Phase 3 - Not implemented yet
-func GetArtistTopSongs(ctx context.Context, country, artistID string) ([]TrackInfo, error) {
-	log.Warnf("GetArtistTopSongs not yet implemented for artist %s", artistID)
-	return nil, errors.New("artist top songs not implemented yet")
+// GetArtistTopSongs fetches an artist's name and top limit songs from the
+// iTunes lookup API, which lists an artist's songs most popular first.
+func GetArtistTopSongs(ctx context.Context, country, artistID string, limit int) (*ArtistResult, error) {
+	log.Tracef("Fetching artist top songs from Apple Music: country=%s, artist=%s", country, artistID)
+
+	span := sentry.StartSpan(ctx, "applemusic.get_artist_top_songs")
+	span.Description = "Get artist top songs from the iTunes lookup API"
+	span.SetTag("country", country)
+	span.SetTag("artist_id", artistID)
+	defer span.Finish()
+
+	if country == "" {
+		country = "us"
+	}
+	if artistID == "" {
+		err := errors.New("artistID is required")
+		sentry.CaptureException(err)
+		span.Status = sentry.SpanStatusInvalidArgument
+		return nil, err
+	}
+
+	artistResult, err := lookupArtistSongs(ctx, country, artistID, limit)
+	if err != nil {
+		log.Errorf("Failed to fetch Apple Music artist: %v", err)
+		sentry.CaptureException(err)
+		span.Status = sentry.SpanStatusInternalError
+		return nil, err
+	}
+
+	if len(artistResult.Tracks) == 0 {
+		err := errors.New("artist has no playable tracks")
+		log.Warnf("Artist %s has no songs", artistID)
+		span.Status = sentry.SpanStatusNotFound
+		return nil, err
+	}
+
+	log.Debugf("Successfully fetched Apple Music artist: '%s' (%d songs)", artistResult.Name, len(artistResult.Tracks))
+	span.Status = sentry.SpanStatusOK
+	span.SetData("artist", artistResult.Name)
+	span.SetData("tracks_count", len(artistResult.Tracks))
+
+	return artistResult, nil
 }
@@ -0,0 +1,85 @@
+package applemusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// lookupResponse is the part of an iTunes lookup response we use. The first
+// result is the artist; the rest are their songs.
+type lookupResponse struct {
+	Results []struct {
+		WrapperType    string `json:"wrapperType"`
+		ArtistName     string `json:"artistName"`
+		TrackName      string `json:"trackName"`
+		CollectionName string `json:"collectionName"`
+	} `json:"results"`
+}
+
+// lookupArtistSongs asks the iTunes lookup API for an artist's songs.
+func lookupArtistSongs(ctx context.Context, country, artistID string, limit int) (*ArtistResult, error) {
+	query := url.Values{
+		"id":      {artistID},
+		"entity":  {"song"},
+		"limit":   {strconv.Itoa(limit)},
+		"country": {country},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://itunes.apple.com/lookup?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return parseArtistLookup(body, limit)
+}
+
+// parseArtistLookup turns a lookup response into the artist's name and up
+// to limit songs. An unknown artist ID comes back as an empty result list.
+func parseArtistLookup(body []byte, limit int) (*ArtistResult, error) {
+	var lookup lookupResponse
+	if err := json.Unmarshal(body, &lookup); err != nil {
+		return nil, fmt.Errorf("failed to parse lookup response: %w", err)
+	}
+	if len(lookup.Results) == 0 {
+		return nil, fmt.Errorf("HTTP 404: artist not found")
+	}
+
+	result := &ArtistResult{}
+	for _, item := range lookup.Results {
+		switch {
+		case item.WrapperType == "artist":
+			result.Name = item.ArtistName
+		case item.WrapperType == "track" && item.TrackName != "" && len(result.Tracks) < limit:
+			result.Tracks = append(result.Tracks, PlaylistTrackInfo{
+				TrackInfo: TrackInfo{
+					Title:   item.TrackName,
+					Artists: []string{item.ArtistName},
+					Album:   item.CollectionName,
+				},
+				Position: len(result.Tracks),
+			})
+		}
+	}
+	if result.Name == "" && len(result.Tracks) > 0 {
+		result.Name = result.Tracks[0].Artists[0]
+	}
+	return result, nil
+}
@@ -36,3 +36,9 @@ type PlaylistResult struct {
 	Tracks      []PlaylistTrackInfo
 	TotalTracks int
 }
+
+// ArtistResult represents an artist and their top songs
+type ArtistResult struct {
+	Name   string
+	Tracks []PlaylistTrackInfo
+}
@@ -0,0 +1,121 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"gopkg.in/hraban/opus.v2"
+)
+
+// ClipSampleRate is the rate clips are decoded and saved at, which is also
+// the rate of the RTP clock on received voice.
+const ClipSampleRate = 48000
+
+// maxFrameSamples is the longest Opus frame (120ms) at ClipSampleRate.
+const maxFrameSamples = ClipSampleRate * 120 / 1000
+
+// clipDrift is how far a speaker's RTP clock may stray from when their
+// frames arrived before a frame is placed by arrival instead, as after they
+// stop talking for a while.
+const clipDrift = ClipSampleRate / 5
+
+// ClipPacket is one received Opus frame kept for a clip.
+type ClipPacket struct {
+	SSRC      uint32
+	Timestamp uint32 // RTP timestamp
+	Arrived   time.Time
+	Opus      []byte
+}
+
+// clipAnchor ties a speaker's RTP clock to a sample in the clip.
+type clipAnchor struct {
+	offset    int
+	timestamp uint32
+}
+
+// MixClip decodes packets, in the order they arrived, and mixes every
+// speaker into one mono track of length starting at start. Frames that
+// don't decode are left as gaps.
+func MixClip(packets []ClipPacket, start time.Time, length time.Duration) ([]int16, error) {
+	mix := make([]int32, samplesIn(length))
+	decoders := make(map[uint32]*opus.Decoder)
+	anchors := make(map[uint32]clipAnchor)
+	pcm := make([]int16, maxFrameSamples)
+
+	for _, pkt := range packets {
+		dec, ok := decoders[pkt.SSRC]
+		if !ok {
+			var err error
+			if dec, err = opus.NewDecoder(ClipSampleRate, 1); err != nil {
+				return nil, err
+			}
+			decoders[pkt.SSRC] = dec
+		}
+		n, err := dec.Decode(pkt.Opus, pcm)
+		if err != nil {
+			continue
+		}
+		addFrame(mix, pcm[:n], clipOffset(anchors, pkt, start))
+	}
+	return clampMix(mix), nil
+}
+
+// clipOffset is the sample pkt starts at: where its speaker's RTP clock puts
+// it, or where it arrived when that's too far off.
+func clipOffset(anchors map[uint32]clipAnchor, pkt ClipPacket, start time.Time) int {
+	arrived := samplesIn(pkt.Arrived.Sub(start))
+	if a, ok := anchors[pkt.SSRC]; ok {
+		predicted := a.offset + int(int32(pkt.Timestamp-a.timestamp))
+		if d := predicted - arrived; d > -clipDrift && d < clipDrift {
+			return predicted
+		}
+	}
+	anchors[pkt.SSRC] = clipAnchor{offset: arrived, timestamp: pkt.Timestamp}
+	return arrived
+}
+
+// addFrame adds frame into mix at offset, dropping what falls outside.
+func addFrame(mix []int32, frame []int16, offset int) {
+	for i, s := range frame {
+		if j := offset + i; j >= 0 && j < len(mix) {
+			mix[j] += int32(s)
+		}
+	}
+}
+
+// clampMix converts a mix back to 16-bit, clipping where speakers overlap
+// past full scale.
+func clampMix(mix []int32) []int16 {
+	out := make([]int16, len(mix))
+	for i, s := range mix {
+		out[i] = int16(max(math.MinInt16, min(math.MaxInt16, s)))
+	}
+	return out
+}
+
+func samplesIn(d time.Duration) int {
+	return int(d * ClipSampleRate / time.Second)
+}
+
+// EncodeWAV wraps 16-bit PCM in a WAV file.
+func EncodeWAV(pcm []int16, sampleRate, channels int) []byte {
+	dataSize := len(pcm) * 2
+	var buf bytes.Buffer
+	buf.Grow(44 + dataSize)
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16)) // fmt chunk size
+	binary.Write(&buf, binary.LittleEndian, uint16(1))  // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*2)) // byte rate
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*2))            // block align
+	binary.Write(&buf, binary.LittleEndian, uint16(16))                    // bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	binary.Write(&buf, binary.LittleEndian, pcm)
+	return buf.Bytes()
+}
@@ -0,0 +1,65 @@
+package audio
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestClipOffset(t *testing.T) {
+	start := time.Unix(1000, 0)
+	anchors := make(map[uint32]clipAnchor)
+	pkt := func(ssrc, ts uint32, after time.Duration) ClipPacket {
+		return ClipPacket{SSRC: ssrc, Timestamp: ts, Arrived: start.Add(after)}
+	}
+
+	// The first frame is placed where it arrived.
+	if got := clipOffset(anchors, pkt(1, 5000, time.Second), start); got != ClipSampleRate {
+		t.Errorf("first frame at %d, want %d", got, ClipSampleRate)
+	}
+	// Later frames follow the RTP clock despite arrival jitter.
+	if got := clipOffset(anchors, pkt(1, 5960, time.Second+35*time.Millisecond), start); got != ClipSampleRate+960 {
+		t.Errorf("next frame at %d, want %d", got, ClipSampleRate+960)
+	}
+	// After a long pause with no matching clock jump, arrival wins.
+	if got := clipOffset(anchors, pkt(1, 6920, 3*time.Second), start); got != 3*ClipSampleRate {
+		t.Errorf("frame after pause at %d, want %d", got, 3*ClipSampleRate)
+	}
+	// Each speaker has their own clock.
+	if got := clipOffset(anchors, pkt(2, 1, 2*time.Second), start); got != 2*ClipSampleRate {
+		t.Errorf("second speaker at %d, want %d", got, 2*ClipSampleRate)
+	}
+}
+
+func TestAddFrameAndClamp(t *testing.T) {
+	mix := make([]int32, 4)
+	addFrame(mix, []int16{30000, 30000, 1}, -1) // first sample falls before the clip
+	addFrame(mix, []int16{32000, -5, 7}, 1)
+	addFrame(mix, []int16{1000, -32768}, 1)
+	got := clampMix(mix)
+	want := []int16{30000, 32767, -32768, 7}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("mix = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestEncodeWAV(t *testing.T) {
+	wav := EncodeWAV([]int16{1, -1, 2}, ClipSampleRate, 1)
+	if len(wav) != 44+6 {
+		t.Fatalf("len = %d, want 50", len(wav))
+	}
+	if string(wav[0:4]) != "RIFF" || string(wav[8:16]) != "WAVEfmt " || string(wav[36:40]) != "data" {
+		t.Errorf("bad chunk IDs in %q", wav[:40])
+	}
+	if rate := binary.LittleEndian.Uint32(wav[24:28]); rate != ClipSampleRate {
+		t.Errorf("sample rate = %d", rate)
+	}
+	if size := binary.LittleEndian.Uint32(wav[40:44]); size != 6 {
+		t.Errorf("data size = %d, want 6", size)
+	}
+	if s := int16(binary.LittleEndian.Uint16(wav[46:48])); s != -1 {
+		t.Errorf("second sample = %d, want -1", s)
+	}
+}
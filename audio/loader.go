@@ -1,12 +1,15 @@
 package audio
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
 	"io"
 	"os/exec"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	sentry "github.com/getsentry/sentry-go"
@@ -19,6 +22,24 @@ type Loader struct {
 	Notifications chan PlaybackNotification
 	canceled      chan bool
 	logger        *log.Entry
+	pending       atomic.Int32 // Load calls running or waiting on mutex
+	ffmpegPID     atomic.Int64 // PID of the running ffmpeg, 0 when idle
+}
+
+// LoaderStats is a snapshot of the loader for diagnostics.
+type LoaderStats struct {
+	FFmpegPID     int // 0 when no ffmpeg is running
+	PendingLoads  int // loads running or waiting for the one ahead of them
+	Notifications int // events not yet picked up by the controller
+}
+
+// Stats reports what the loader is doing right now.
+func (l *Loader) Stats() LoaderStats {
+	return LoaderStats{
+		FFmpegPID:     int(l.ffmpegPID.Load()),
+		PendingLoads:  int(l.pending.Load()),
+		Notifications: len(l.Notifications),
+	}
 }
 
 type LoadJob struct {
@@ -26,6 +47,34 @@ type LoadJob struct {
 	VideoID  string
 	Title    string
 	Duration time.Duration
+	Start    time.Duration // seek here before decoding; 0 for the beginning
+	End      time.Duration // stop here; 0 for the end of the stream
+	Live     bool          // endless stream: handed to the player unbuffered
+}
+
+// ffmpegArgs builds the decode command for a job. Start and End are input
+// options so ffmpeg seeks the source rather than decoding up to the start.
+func ffmpegArgs(job LoadJob) []string {
+	var args []string
+	if job.Live {
+		// Radio servers drop idle listeners now and then; reconnect rather
+		// than ending the stream.
+		args = append(args, "-reconnect", "1", "-reconnect_streamed", "1", "-reconnect_delay_max", "5")
+	}
+	if job.Start > 0 {
+		args = append(args, "-ss", strconv.FormatFloat(job.Start.Seconds(), 'f', 3, 64))
+	}
+	if job.End > 0 {
+		args = append(args, "-to", strconv.FormatFloat(job.End.Seconds(), 'f', 3, 64))
+	}
+	return append(args,
+		"-i", job.URL,
+		"-f", "s16le",
+		"-ar", "48000",
+		"-ac", "2",
+		"-af", "aresample=48000",
+		"-loglevel", "error",
+		"pipe:1")
 }
 
 type LoadResult struct {
@@ -34,8 +83,15 @@ type LoadResult struct {
 	Title     string
 	Error     *error
 	Duration  time.Duration
+	Live      bool // ffmpegOut is the running ffmpeg's output, not a buffer
+	// VolumeOffset is added to the player's volume while this track plays:
+	// the requester's own adjustment for their songs.
+	VolumeOffset int
 }
 
+// liveStartTimeout is how long a live stream gets to produce audio.
+const liveStartTimeout = 30 * time.Second
+
 func NewLoader() *Loader {
 	return &Loader{
 		Notifications: make(chan PlaybackNotification, 100),
@@ -59,8 +115,11 @@ func (l *Loader) Load(ctx context.Context, job LoadJob) {
 	span.SetTag("video_id", job.VideoID)
 	span.SetTag("title", job.Title)
 
+	l.pending.Add(1)
 	l.mutex.Lock()
 	defer func() {
+		l.ffmpegPID.Store(0)
+		l.pending.Add(-1)
 		l.mutex.Unlock()
 		select {
 		case l.completed <- true:
@@ -90,14 +149,7 @@ func (l *Loader) Load(ctx context.Context, job LoadJob) {
 	// - Go 1.24+ GC handles ~55MB allocations well without noticeable pauses
 	// - Simpler player.go code with binary.Read()
 
-	ffmpeg := exec.Command("ffmpeg",
-		"-i", job.URL,
-		"-f", "s16le",
-		"-ar", "48000",
-		"-ac", "2",
-		"-af", "aresample=48000",
-		"-loglevel", "error",
-		"pipe:1")
+	ffmpeg := exec.Command("ffmpeg", ffmpegArgs(job)...)
 
 	var stderr bytes.Buffer
 	ffmpeg.Stderr = &stderr
@@ -132,6 +184,12 @@ func (l *Loader) Load(ctx context.Context, job LoadJob) {
 		}
 		return
 	}
+	l.ffmpegPID.Store(int64(ffmpeg.Process.Pid))
+
+	if job.Live {
+		l.startLive(span, job, ffmpeg, stdout, &stderr)
+		return
+	}
 
 	// Buffer the entire audio output into memory
 	type result struct {
@@ -271,6 +329,84 @@ func (l *Loader) Load(ctx context.Context, job LoadJob) {
 	}
 }
 
+// startLive hands a live stream to the player. A stream never ends, so it
+// can't be buffered like a song: once the first frame arrives the ffmpeg
+// pipe itself becomes the LoadResult, read in real time by the player and
+// closed (killing ffmpeg) when playback ends.
+func (l *Loader) startLive(span *sentry.Span, job LoadJob, ffmpeg *exec.Cmd, stdout io.ReadCloser, stderr *bytes.Buffer) {
+	reader := bufio.NewReader(stdout)
+	ready := make(chan error, 1)
+	go func() {
+		_, err := reader.Peek(960 * 2 * 2) // one 20ms frame
+		ready <- err
+	}()
+
+	stop := func() {
+		ffmpeg.Process.Kill()
+		ffmpeg.Wait() // Reap zombie process
+	}
+	fail := func(errMsg string) {
+		if stderr.Len() > 0 {
+			errMsg += " | ffmpeg stderr: " + stderr.String()
+		}
+		detailedErr := errors.New(errMsg)
+		log.Errorf("error starting live stream %s: %v", job.VideoID, detailedErr)
+		sentry.CaptureException(detailedErr)
+		l.Notifications <- PlaybackNotification{
+			Event:   PlaybackLoadError,
+			VideoID: &job.VideoID,
+			Error:   &detailedErr,
+		}
+	}
+
+	select {
+	case <-l.canceled:
+		l.logger.Debugf("live load for %s canceled", job.VideoID)
+		span.Status = sentry.SpanStatusCanceled
+		stop()
+		l.Notifications <- PlaybackNotification{
+			Event:   PlaybackLoadCanceled,
+			VideoID: &job.VideoID,
+		}
+
+	case err := <-ready:
+		if err != nil {
+			stop()
+			span.Status = sentry.SpanStatusInternalError
+			fail("live stream ended before any audio: " + err.Error())
+			return
+		}
+		span.Status = sentry.SpanStatusOK
+		span.SetData("live", true)
+		l.Notifications <- PlaybackNotification{
+			Event:   PlaybackLoaded,
+			VideoID: &job.VideoID,
+			LoadResult: &LoadResult{
+				ffmpegOut: &liveOutput{Reader: reader, cmd: ffmpeg},
+				VideoID:   job.VideoID,
+				Title:     job.Title,
+				Live:      true,
+			},
+		}
+
+	case <-time.After(liveStartTimeout):
+		stop()
+		span.Status = sentry.SpanStatusDeadlineExceeded
+		fail("live stream sent no audio within " + liveStartTimeout.String())
+	}
+}
+
+// liveOutput is a live stream's ffmpeg output. Closing it stops ffmpeg.
+type liveOutput struct {
+	*bufio.Reader
+	cmd *exec.Cmd
+}
+
+func (o *liveOutput) Close() error {
+	o.cmd.Process.Kill()
+	return o.cmd.Wait()
+}
+
 func (l *Loader) Cancel() {
 	// Non-blocking send to the buffered(1) canceled channel. If Load() is
 	// already in its select, it picks this up immediately. If Load() hasn't
@@ -283,3 +419,13 @@ func (l *Loader) Cancel() {
 	default:
 	}
 }
+
+// Discard releases a LoadResult that will never be played, closing its
+// FFmpeg output so the buffered audio can be collected.
+func (r *LoadResult) Discard() {
+	if r == nil || r.ffmpegOut == nil {
+		return
+	}
+	r.ffmpegOut.Close()
+	r.ffmpegOut = nil
+}
@@ -2,6 +2,7 @@ package audio
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -184,3 +185,29 @@ func TestLoaderNotificationsChannelBuffered(t *testing.T) {
 		}
 	}
 }
+
+func TestFFmpegArgsClip(t *testing.T) {
+	args := ffmpegArgs(LoadJob{URL: "u"})
+	if args[0] != "-i" {
+		t.Errorf("unclipped args start with %q, want -i", args[0])
+	}
+
+	args = ffmpegArgs(LoadJob{URL: "u", Start: 90 * time.Second, End: 225500 * time.Millisecond})
+	want := []string{"-ss", "90.000", "-to", "225.500", "-i", "u"}
+	for i, w := range want {
+		if args[i] != w {
+			t.Fatalf("args = %v, want prefix %v", args, want)
+		}
+	}
+}
+
+func TestFFmpegArgsLive(t *testing.T) {
+	args := ffmpegArgs(LoadJob{URL: "u", Live: true})
+	joined := strings.Join(args, " ")
+	if !strings.HasPrefix(joined, "-reconnect 1 -reconnect_streamed 1") || !strings.Contains(joined, "-i u") {
+		t.Errorf("live args = %v, want reconnect options before the input", args)
+	}
+	if strings.Contains(strings.Join(ffmpegArgs(LoadJob{URL: "u"}), " "), "-reconnect") {
+		t.Error("non-live args should not reconnect")
+	}
+}
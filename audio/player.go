@@ -24,6 +24,10 @@ type TTSConsumer interface {
 	HasTTS() bool
 }
 
+// EncoderSettings describes how NewPlayer configures the Opus encoders, for
+// diagnostics. Keep it in sync with NewPlayer.
+const EncoderSettings = "Opus 48kHz stereo, audio mode, complexity 10, max bitrate"
+
 type Player struct {
 	Notifications     chan PlaybackNotification
 	logger            *log.Entry
@@ -40,6 +44,9 @@ type Player struct {
 	silenceBuffer     []int16      // Pre-allocated for pause loop
 	silenceOpus       []byte       // Pre-allocated for pause loop
 	ttsConsumer       TTSConsumer  // reads pre-generated TTS for song transitions
+
+	sound      atomic.Pointer[TTSPlayback] // soundboard sound mixed over the song
+	soundFrame []int16                     // Pre-allocated for mixSound
 }
 
 func NewPlayer() (*Player, error) {
@@ -71,6 +78,7 @@ func NewPlayer() (*Player, error) {
 		mutex:         sync.Mutex{},
 		silenceBuffer: make([]int16, 960*2),
 		silenceOpus:   make([]byte, 960*4),
+		soundFrame:    make([]int16, 960*2),
 	}
 	player.paused.Store(false)
 	player.stopping.Store(false)
@@ -87,6 +95,11 @@ func (p *Player) Play(ctx context.Context, data *LoadResult, voiceChannel *disco
 
 	p.mutex.Lock()
 
+	if data.Live {
+		// Nothing else will read this stream again; stop ffmpeg with it.
+		defer data.Discard()
+	}
+
 	defer func() {
 		// Recover from send on closed channel (voice connection closed during playback)
 		if r := recover(); r != nil {
@@ -94,6 +107,7 @@ func (p *Player) Play(ctx context.Context, data *LoadResult, voiceChannel *disco
 			sentry.CaptureMessage(fmt.Sprintf("Recovered from playback panic: %v", r))
 		}
 		p.playing.Store(false)
+		p.sound.Store(nil)
 		p.mutex.Unlock()
 		span.Finish()
 	}()
@@ -259,7 +273,20 @@ func (p *Player) Play(ctx context.Context, data *LoadResult, voiceChannel *disco
 			// memory-buffered, so there's no pipe backpressure to relieve.
 			// Reading during pause desynchronizes position tracking from
 			// actual buffer state, causing TTS transitions to never trigger.
-			encoded, err := p.encoder.Encode(p.silenceBuffer, p.silenceOpus)
+			// Live streams are the exception: they're read straight from
+			// ffmpeg, so keep draining them or the server drops us, and
+			// resume picks up at the live edge like a radio would.
+			if data.Live {
+				_, _ = io.ReadFull(data.ffmpegOut, rawBuf)
+			}
+			pauseFrame := p.silenceBuffer
+			if p.sound.Load() != nil {
+				// Mix a soundboard sound into a fresh frame of silence.
+				clear(buffer)
+				p.mixSound(buffer)
+				pauseFrame = buffer
+			}
+			encoded, err := p.encoder.Encode(pauseFrame, p.silenceOpus)
 			if err != nil {
 				p.logger.Warnf("Error encoding silence during pause: %v", err)
 				sentry.CaptureException(err)
@@ -317,7 +344,7 @@ func (p *Player) Play(ctx context.Context, data *LoadResult, voiceChannel *disco
 			firstPacket = false
 		}
 
-		vol := int(p.volume.Load())
+		vol := max(0, min(150, int(p.volume.Load())+data.VolumeOffset))
 		if vol != 100 {
 			for i := range buffer {
 				sample := float64(buffer[i]) * float64(vol) / 100.0
@@ -330,6 +357,7 @@ func (p *Player) Play(ctx context.Context, data *LoadResult, voiceChannel *disco
 				buffer[i] = int16(sample)
 			}
 		}
+		p.mixSound(buffer)
 
 		// Trigger announcement when approaching end of song.
 		// Check the time window BEFORE consuming: ConsumeTTS() is destructive
@@ -444,6 +472,12 @@ func (p *Player) SetTTSConsumer(c TTSConsumer) {
 }
 
 func (p *Player) PlayAnnouncement(tts *TTSPlayback, vc *discordgo.VoiceConnection) error {
+	return p.playAlone(tts, vc, ttsVolumeBoost)
+}
+
+// playAlone plays pre-converted audio with nothing under it, amplified by
+// boost.
+func (p *Player) playAlone(tts *TTSPlayback, vc *discordgo.VoiceConnection, boost float64) error {
 	p.playing.Store(true)
 	defer p.playing.Store(false)
 
@@ -457,7 +491,9 @@ func (p *Player) PlayAnnouncement(tts *TTSPlayback, vc *discordgo.VoiceConnectio
 	defer ticker.Stop()
 
 	for tts.ReadFrame(frameBuf) {
-		amplifySamples(frameBuf, ttsVolumeBoost)
+		if boost != 1 {
+			amplifySamples(frameBuf, boost)
+		}
 		encoded, err := p.ttsEncoder.Encode(frameBuf, opusBuf)
 		if err != nil {
 			return err
@@ -531,6 +567,13 @@ func (p *Player) GetVolume() int {
 	return int(p.volume.Load())
 }
 
+// LastPosition returns how far the current or most recent track got. Unlike
+// GetPosition it survives the track stopping, so an interrupted song can be
+// picked back up where it dropped.
+func (p *Player) LastPosition() time.Duration {
+	return time.Duration(p.playbackPosition.Load()) * time.Microsecond
+}
+
 func (p *Player) GetPosition() time.Duration {
 	if !p.playing.Load() {
 		return 0
@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxSoundLength is the longest soundboard sound that can be uploaded.
+const MaxSoundLength = 10 * time.Second
+
+// soundDuck is how loud a song plays under a soundboard sound.
+const soundDuck = 0.3
+
+var (
+	ErrSoundTooLong = errors.New("sound is too long")
+	ErrPlayerBusy   = errors.New("an announcement is playing")
+)
+
+// ConvertSound decodes an uploaded audio file to 48kHz stereo PCM, the
+// format Player sends. Anything past MaxSoundLength is ErrSoundTooLong.
+func ConvertSound(data []byte) ([]int16, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty sound file")
+	}
+
+	// Decode a little past the limit so a sound that's too long can be told
+	// apart from one that's exactly long enough.
+	limit := MaxSoundLength + time.Second
+	cmd := exec.Command("ffmpeg",
+		"-i", "pipe:0",
+		"-t", fmt.Sprintf("%.1f", limit.Seconds()),
+		"-vn",
+		"-f", "s16le",
+		"-ar", "48000",
+		"-ac", "2",
+		"-loglevel", "error",
+		"pipe:1")
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("FFmpeg sound conversion failed: %w (stderr: %s)", err, stderr.String())
+	}
+	if len(output) < 4 {
+		return nil, errors.New("sound file has no audio")
+	}
+
+	samples := make([]int16, len(output)/2)
+	if _, err := binary.Decode(output, binary.LittleEndian, samples); err != nil {
+		return nil, fmt.Errorf("failed to read FFmpeg output as int16: %w", err)
+	}
+	if SoundLength(samples) > MaxSoundLength {
+		return nil, ErrSoundTooLong
+	}
+	return samples, nil
+}
+
+// SoundLength is how long 48kHz stereo samples play for.
+func SoundLength(samples []int16) time.Duration {
+	return time.Duration(len(samples)/2) * time.Second / 48000
+}
+
+// PlaySound plays a soundboard sound. While a song is loaded, playing or
+// paused, the sound is mixed over it with the song ducked until the sound
+// ends; otherwise it plays on its own, and a song that starts meanwhile
+// waits for it to finish.
+func (p *Player) PlaySound(samples []int16, vc *discordgo.VoiceConnection) error {
+	if !p.mutex.TryLock() {
+		p.sound.Store(&TTSPlayback{Samples: samples})
+		return nil
+	}
+	defer p.mutex.Unlock()
+	// Announcements play without the lock; don't talk over one.
+	if p.playing.Load() {
+		return ErrPlayerBusy
+	}
+	return p.playAlone(&TTSPlayback{Samples: samples}, vc, 1)
+}
+
+// mixSound ducks buf and adds the next frame of the pending soundboard
+// sound to it. Does nothing when there's no sound.
+func (p *Player) mixSound(buf []int16) {
+	sound := p.sound.Load()
+	if sound == nil {
+		return
+	}
+	if !sound.ReadFrame(p.soundFrame) {
+		p.sound.CompareAndSwap(sound, nil)
+	}
+	for i := range buf {
+		sample := float64(buf[i])*soundDuck + float64(p.soundFrame[i])
+		if sample > 32767 {
+			sample = 32767
+		} else if sample < -32768 {
+			sample = -32768
+		}
+		buf[i] = int16(sample)
+	}
+}
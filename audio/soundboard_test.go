@@ -0,0 +1,83 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMixSoundDucksSong verifies a soundboard sound is added over the song
+// with the song ducked, and that the song comes back once the sound ends.
+func TestMixSoundDucksSong(t *testing.T) {
+	p, err := NewPlayer()
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+
+	// One and a half frames of sound.
+	sound := make([]int16, ttsFrameSamples*3/2)
+	for i := range sound {
+		sound[i] = 1000
+	}
+	p.sound.Store(&TTSPlayback{Samples: sound})
+
+	song := func() []int16 {
+		buf := make([]int16, ttsFrameSamples)
+		for i := range buf {
+			buf[i] = 10000
+		}
+		return buf
+	}
+
+	buf := song()
+	p.mixSound(buf)
+	if want := int16(10000*soundDuck + 1000); buf[0] != want || buf[len(buf)-1] != want {
+		t.Errorf("first frame = %d..%d, want %d", buf[0], buf[len(buf)-1], want)
+	}
+
+	// The last, partial frame is still ducked; past the sound it's silence.
+	buf = song()
+	p.mixSound(buf)
+	if want := int16(10000*soundDuck + 1000); buf[0] != want {
+		t.Errorf("last frame start = %d, want %d", buf[0], want)
+	}
+	if want := int16(10000 * soundDuck); buf[len(buf)-1] != want {
+		t.Errorf("last frame end = %d, want %d", buf[len(buf)-1], want)
+	}
+	if p.sound.Load() != nil {
+		t.Error("sound still pending after its last frame")
+	}
+
+	buf = song()
+	p.mixSound(buf)
+	if buf[0] != 10000 {
+		t.Errorf("after the sound = %d, want the song untouched", buf[0])
+	}
+}
+
+// TestMixSoundClamps verifies a loud sound over a loud song doesn't wrap.
+func TestMixSoundClamps(t *testing.T) {
+	p, err := NewPlayer()
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	sound := make([]int16, ttsFrameSamples)
+	for i := range sound {
+		sound[i] = -32000
+	}
+	p.sound.Store(&TTSPlayback{Samples: sound})
+
+	buf := make([]int16, ttsFrameSamples)
+	for i := range buf {
+		buf[i] = -32000
+	}
+	p.mixSound(buf)
+	if buf[0] != -32768 {
+		t.Errorf("mixed = %d, want -32768", buf[0])
+	}
+}
+
+func TestSoundLength(t *testing.T) {
+	if got := SoundLength(make([]int16, 48000*2*3)); got != 3*time.Second {
+		t.Errorf("SoundLength = %v, want 3s", got)
+	}
+}
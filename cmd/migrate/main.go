@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+
+	"beatbot/database"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Migrates the configured database (see database.New) to a schema version,
+// e.g. down before rolling back to an older build. Opening the database
+// always brings it up to the latest version first, so -to only matters for
+// going down. Without -to it just reports the version.
+func main() {
+	to := flag.Int("to", -1, "schema version to migrate to")
+	flag.Parse()
+
+	db, err := database.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	if *to >= 0 {
+		if err := db.MigrateTo(*to); err != nil {
+			log.Fatal(err)
+		}
+	}
+	version, err := db.SchemaVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+	latest, err := db.LatestSchemaVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("Schema is at version %d of %d", version, latest)
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"beatbot/config"
+	"beatbot/handlers"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Registers handlers.Commands with Discord without starting the bot, e.g.
+// against a dev application. Reads DISCORD_BOT_TOKEN and DISCORD_APP_ID from
+// the environment; see set-commands.sh.
+func main() {
+	config.NewConfig()
+
+	if err := handlers.RegisterCommands(config.Config.Discord.BotToken, config.Config.Discord.AppID); err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("Registered %d commands", len(handlers.Commands))
+}
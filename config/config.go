@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type ConfigStruct struct {
@@ -15,12 +17,27 @@ type ConfigStruct struct {
 	Deezer      DeezerConfig
 	GrokTTS     GrokTTSConfig
 	TTSProvider string // "gemini" (default) or "grok"
+	LLM         LLMConfig
 }
 
 type DiscordConfig struct {
 	BotToken  string
 	AppID     string
 	PublicKey string
+	Mode      string // how interactions arrive: InteractionsWebhook or InteractionsGateway
+}
+
+// Interaction delivery modes. Webhook mode needs Discord to reach the bot's
+// /discord/interactions endpoint; gateway mode reads interactions off the
+// bot's own websocket, so it works behind NAT with no tunnel.
+const (
+	InteractionsWebhook = "webhook"
+	InteractionsGateway = "gateway"
+)
+
+// UseGateway reports whether interactions come in over the gateway.
+func (d *DiscordConfig) UseGateway() bool {
+	return d.Mode == InteractionsGateway
 }
 
 type TunnelConfig struct {
@@ -30,13 +47,23 @@ type TunnelConfig struct {
 type YoutubeConfig struct {
 	APIKey        string
 	PlaylistLimit int
+	CookiesFile   string        // Netscape cookies.txt passed to yt-dlp, for age-restricted videos
+	POToken       string        // yt-dlp PO token, e.g. "web.gvs+<token>"
+	YtdlpVersion  string        // pins yt-dlp to a version or channel; "" follows the latest release
+	YtdlpUpdate   time.Duration // how often yt-dlp updates itself; 0 never
+	SearchCache   time.Duration // how long search results are reused; 0 never
 }
 
 type GeminiConfig struct {
-	Enabled  bool
-	APIKey   string
-	Model    string
-	TTSModel string
+	Enabled         bool
+	APIKey          string
+	Model           string
+	TTSModel        string
+	Temperature     *float32      // text model temperature; nil leaves it to the model
+	MaxOutputTokens int32         // cap on text replies; 0 leaves it to the model
+	Timeout         time.Duration // how long a text request may take
+	RateLimit       int           // text requests per guild per minute; 0 for no limit
+	CacheTTL        time.Duration // how long identical prompts reuse a reply; 0 disables
 }
 
 type SpotifyConfig struct {
@@ -51,6 +78,16 @@ type DeezerConfig struct {
 	BPMMatching bool
 }
 
+// LLMConfig picks what writes the bot's text replies. Any provider other
+// than Gemini turns the AI features on by itself; Gemini TTS voices still
+// need GEMINI_API_KEY.
+type LLMConfig struct {
+	Provider string // "gemini" (default), "openai", "anthropic" or "ollama"
+	Model    string // "" uses the provider's default, GEMINI_MODEL for Gemini
+	APIKey   string
+	BaseURL  string // "" uses the provider's public API, or Ollama on localhost
+}
+
 type GrokTTSConfig struct {
 	APIKey string
 	Speed  float64
@@ -60,9 +97,23 @@ type Options struct {
 	EnforceVoiceChannel bool
 	Port                string
 	IdleTimeoutMinutes  int
-	AudioBitrate        int // Audio bitrate in bps (e.g., 96000 for 96 kbps)
+	AudioBitrate        int                      // Audio bitrate in bps (e.g., 96000 for 96 kbps)
+	RegisterCommands    bool                     // Overwrite Discord's slash commands with handlers.Commands on boot
+	CommandCooldowns    map[string]time.Duration // Per-user wait between uses of a command; commands not listed have none
+	ScheduleTimezone    *time.Location           // Zone /playat reads clock times like 21:00 in
+	ArtistTopTracks     int                      // How many top tracks /topsongs and artist links queue
+	AdminToken          string                   // Bearer token for operator endpoints; they're off when unset
+	Presence            string                   // What the bot's "Listening to" status shows: PresenceTrack, PresenceGuilds or PresenceOff
+	HistoryRetention    time.Duration            // How long play history is kept; 0 keeps it forever
 }
 
+// Presence modes for the bot's status.
+const (
+	PresenceTrack  = "track"  // the most recently started track
+	PresenceGuilds = "guilds" // how many servers are playing music
+	PresenceOff    = "off"
+)
+
 func (t *TunnelConfig) IsCloudflare() bool {
 	return t.CloudflareTunnelURL != ""
 }
@@ -74,11 +125,19 @@ func (options *Options) EnforceVoiceChannelEnabled() bool {
 var Config *ConfigStruct
 
 func NewConfig() {
+	llm := LLMConfig{
+		Provider: getLLMProvider(),
+		Model:    strings.TrimSpace(os.Getenv("LLM_MODEL")),
+		APIKey:   os.Getenv("LLM_API_KEY"),
+		BaseURL:  strings.TrimRight(strings.TrimSpace(os.Getenv("LLM_BASE_URL")), "/"),
+	}
+
 	config := &ConfigStruct{
 		Discord: DiscordConfig{
 			BotToken:  os.Getenv("DISCORD_BOT_TOKEN"),
 			AppID:     os.Getenv("DISCORD_APP_ID"),
 			PublicKey: os.Getenv("DISCORD_PUBLIC_KEY"),
+			Mode:      getInteractionMode(),
 		},
 		Tunnel: TunnelConfig{
 			CloudflareTunnelURL: os.Getenv("CLOUDFLARE_TUNNEL_URL"),
@@ -88,16 +147,33 @@ func NewConfig() {
 			Port:                os.Getenv("PORT"),
 			IdleTimeoutMinutes:  getIdleTimeout(),
 			AudioBitrate:        getAudioBitrate(),
+			RegisterCommands:    os.Getenv("REGISTER_COMMANDS") == "true",
+			CommandCooldowns:    getCommandCooldowns(),
+			ScheduleTimezone:    getScheduleTimezone(),
+			ArtistTopTracks:     getArtistTopTracks(),
+			AdminToken:          os.Getenv("ADMIN_TOKEN"),
+			Presence:            getPresenceMode(),
+			HistoryRetention:    getHistoryRetention(),
 		},
 		Youtube: YoutubeConfig{
 			APIKey:        os.Getenv("YOUTUBE_API_KEY"),
 			PlaylistLimit: getYouTubePlaylistLimit(),
+			CookiesFile:   os.Getenv("YTDLP_COOKIES_FILE"),
+			POToken:       os.Getenv("YTDLP_PO_TOKEN"),
+			YtdlpVersion:  strings.TrimSpace(os.Getenv("YTDLP_VERSION")),
+			YtdlpUpdate:   getYtdlpUpdateInterval(),
+			SearchCache:   getSearchCacheTTL(),
 		},
 		Gemini: GeminiConfig{
-			Enabled:  os.Getenv("GEMINI_ENABLED") == "true",
-			APIKey:   os.Getenv("GEMINI_API_KEY"),
-			Model:    getGeminiModel(),
-			TTSModel: getGeminiTTSModel(),
+			Enabled:         os.Getenv("GEMINI_ENABLED") == "true" || llm.Provider != LLMGemini,
+			APIKey:          os.Getenv("GEMINI_API_KEY"),
+			Model:           getGeminiModel(),
+			TTSModel:        getGeminiTTSModel(),
+			Temperature:     getGeminiTemperature(),
+			MaxOutputTokens: getGeminiMaxOutputTokens(),
+			Timeout:         getGeminiTimeout(),
+			RateLimit:       getGeminiRateLimit(),
+			CacheTTL:        getGeminiCacheTTL(),
 		},
 		Spotify: SpotifyConfig{
 			ClientID:      os.Getenv("SPOTIFY_CLIENT_ID"),
@@ -114,6 +190,7 @@ func NewConfig() {
 			Speed:  getGrokTTSSpeed(),
 		},
 		TTSProvider: getTTSProvider(),
+		LLM:         llm,
 	}
 
 	Config = config
@@ -131,6 +208,77 @@ func getIdleTimeout() int {
 	return timeout
 }
 
+// defaultCommandCooldowns cover the commands that hit yt-dlp, Gemini or
+// the Discord API hardest.
+var defaultCommandCooldowns = map[string]time.Duration{
+	"play":      3 * time.Second,
+	"queue":     3 * time.Second,
+	"search":    3 * time.Second,
+	"playlist":  3 * time.Second,
+	"recommend": 5 * time.Second,
+	"request":   5 * time.Second,
+	"dj":        5 * time.Second,
+	"lyrics":    5 * time.Second,
+	"clear":     10 * time.Second,
+	"reset":     10 * time.Second,
+}
+
+// getCommandCooldowns reads COMMAND_COOLDOWNS, a comma-separated list of
+// command=seconds pairs (e.g. "play=3,clear=10") layered over the defaults.
+// A value of 0 turns a command's cooldown off; malformed pairs are ignored.
+func getCommandCooldowns() map[string]time.Duration {
+	cooldowns := make(map[string]time.Duration, len(defaultCommandCooldowns))
+	for command, d := range defaultCommandCooldowns {
+		cooldowns[command] = d
+	}
+	for _, pair := range strings.Split(os.Getenv("COMMAND_COOLDOWNS"), ",") {
+		command, value, ok := strings.Cut(pair, "=")
+		command = strings.ToLower(strings.TrimSpace(command))
+		if !ok || command == "" {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || seconds < 0 {
+			continue
+		}
+		if seconds == 0 {
+			delete(cooldowns, command)
+			continue
+		}
+		cooldowns[command] = time.Duration(seconds * float64(time.Second))
+	}
+	return cooldowns
+}
+
+// getScheduleTimezone reads SCHEDULE_TIMEZONE, an IANA zone name such as
+// "America/New_York". Unset or unknown zones fall back to the host's zone.
+func getScheduleTimezone() *time.Location {
+	name := strings.TrimSpace(os.Getenv("SCHEDULE_TIMEZONE"))
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// getHistoryRetention reads HISTORY_RETENTION_DAYS, how long plays stay in
+// the history behind /history, /stats and radio seeds (default 365). 0 keeps
+// them forever.
+func getHistoryRetention() time.Duration {
+	daysStr := os.Getenv("HISTORY_RETENTION_DAYS")
+	if daysStr == "" {
+		return 365 * 24 * time.Hour
+	}
+	days, err := strconv.Atoi(daysStr)
+	if err != nil || days < 0 {
+		return 365 * 24 * time.Hour
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 func getPlaylistLimit() int {
 	limitStr := os.Getenv("SPOTIFY_PLAYLIST_LIMIT")
 	if limitStr == "" {
@@ -146,6 +294,23 @@ func getPlaylistLimit() int {
 	return limit
 }
 
+// getArtistTopTracks reads ARTIST_TOP_TRACKS. Spotify only returns an
+// artist's top 10, so that's the cap.
+func getArtistTopTracks() int {
+	limitStr := os.Getenv("ARTIST_TOP_TRACKS")
+	if limitStr == "" {
+		return 5
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		return 5
+	}
+	if limit > 10 {
+		return 10
+	}
+	return limit
+}
+
 func getYouTubePlaylistLimit() int {
 	limitStr := os.Getenv("YOUTUBE_PLAYLIST_LIMIT")
 	if limitStr == "" {
@@ -161,6 +326,38 @@ func getYouTubePlaylistLimit() int {
 	return limit
 }
 
+// getYtdlpUpdateInterval reads YTDLP_UPDATE_HOURS, how often yt-dlp updates
+// itself while the bot runs (default 24, at least 1). 0 turns scheduled
+// updates off.
+func getYtdlpUpdateInterval() time.Duration {
+	hoursStr := os.Getenv("YTDLP_UPDATE_HOURS")
+	if hoursStr == "" {
+		return 24 * time.Hour
+	}
+	hours, err := strconv.ParseFloat(hoursStr, 64)
+	if err != nil || hours < 0 {
+		return 24 * time.Hour
+	}
+	if hours > 0 && hours < 1 {
+		hours = 1
+	}
+	return time.Duration(hours * float64(time.Hour))
+}
+
+// getSearchCacheTTL reads SEARCH_CACHE_HOURS, how long a YouTube search's
+// results are reused for the same query (default 6). 0 searches every time.
+func getSearchCacheTTL() time.Duration {
+	hoursStr := os.Getenv("SEARCH_CACHE_HOURS")
+	if hoursStr == "" {
+		return 6 * time.Hour
+	}
+	hours, err := strconv.ParseFloat(hoursStr, 64)
+	if err != nil || hours < 0 {
+		return 6 * time.Hour
+	}
+	return time.Duration(hours * float64(time.Hour))
+}
+
 func getAudioBitrate() int {
 	bitrateStr := os.Getenv("AUDIO_BITRATE")
 	if bitrateStr == "" {
@@ -197,6 +394,106 @@ func getGeminiTTSModel() string {
 	return model
 }
 
+// getGeminiTemperature reads GEMINI_TEMPERATURE, from 0 (most predictable)
+// to 2 (most varied). Unset or out of range keeps the model's default.
+func getGeminiTemperature() *float32 {
+	s := os.Getenv("GEMINI_TEMPERATURE")
+	if s == "" {
+		return nil
+	}
+	temperature, err := strconv.ParseFloat(s, 32)
+	if err != nil || temperature < 0 || temperature > 2 {
+		return nil
+	}
+	t := float32(temperature)
+	return &t
+}
+
+// getGeminiMaxOutputTokens reads GEMINI_MAX_OUTPUT_TOKENS. Unset or invalid
+// keeps the model's default.
+func getGeminiMaxOutputTokens() int32 {
+	tokens, err := strconv.ParseInt(os.Getenv("GEMINI_MAX_OUTPUT_TOKENS"), 10, 32)
+	if err != nil || tokens < 0 {
+		return 0
+	}
+	return int32(tokens)
+}
+
+// getGeminiTimeout reads GEMINI_TIMEOUT_SECONDS, how long a text request
+// may take before it's given up on (default 20).
+func getGeminiTimeout() time.Duration {
+	seconds, err := strconv.ParseFloat(os.Getenv("GEMINI_TIMEOUT_SECONDS"), 64)
+	if err != nil || seconds <= 0 {
+		return 20 * time.Second
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// getGeminiRateLimit reads GEMINI_RATE_LIMIT, how many text requests a
+// guild may make per minute before replies fall back to canned ones
+// (default 20, 0 for no limit).
+func getGeminiRateLimit() int {
+	v := strings.TrimSpace(os.Getenv("GEMINI_RATE_LIMIT"))
+	if v == "" {
+		return 20
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 20
+	}
+	return n
+}
+
+// getGeminiCacheTTL reads GEMINI_CACHE_SECONDS, how long a reply is reused
+// for the exact same prompt (default 60, 0 disables).
+func getGeminiCacheTTL() time.Duration {
+	v := strings.TrimSpace(os.Getenv("GEMINI_CACHE_SECONDS"))
+	if v == "" {
+		return time.Minute
+	}
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 0 {
+		return time.Minute
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// getInteractionMode reads DISCORD_MODE, "webhook" (default) or "gateway".
+func getInteractionMode() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("DISCORD_MODE")), InteractionsGateway) {
+		return InteractionsGateway
+	}
+	return InteractionsWebhook
+}
+
+// getPresenceMode reads PRESENCE, "track" (default), "guilds" or "off".
+func getPresenceMode() string {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("PRESENCE"))); mode {
+	case PresenceGuilds, PresenceOff:
+		return mode
+	default:
+		return PresenceTrack
+	}
+}
+
+// Text providers for LLM_PROVIDER.
+const (
+	LLMGemini    = "gemini"
+	LLMOpenAI    = "openai"
+	LLMAnthropic = "anthropic"
+	LLMOllama    = "ollama"
+)
+
+// getLLMProvider reads LLM_PROVIDER. Unset means Gemini; anything else is
+// kept as given so startup can reject it by name.
+func getLLMProvider() string {
+	p := strings.ToLower(strings.TrimSpace(os.Getenv("LLM_PROVIDER")))
+	if p == "" {
+		return LLMGemini
+	}
+	return p
+}
+
 func getTTSProvider() string {
 	p := os.Getenv("TTS_PROVIDER")
 	if p == "" {
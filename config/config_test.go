@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestGetIdleTimeout(t *testing.T) {
 	tests := []struct {
@@ -76,6 +79,141 @@ func TestGetYouTubePlaylistLimit(t *testing.T) {
 	}
 }
 
+func TestGetArtistTopTracks(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want int
+	}{
+		{"empty", "", 5},
+		{"invalid", "foo", 5},
+		{"zero", "0", 5},
+		{"min", "1", 1},
+		{"max", "10", 10},
+		{"over", "25", 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ARTIST_TOP_TRACKS", tt.env)
+			if got := getArtistTopTracks(); got != tt.want {
+				t.Errorf("getArtistTopTracks() = %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetYtdlpUpdateInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want time.Duration
+	}{
+		{"empty", "", 24 * time.Hour},
+		{"invalid", "daily", 24 * time.Hour},
+		{"negative", "-3", 24 * time.Hour},
+		{"off", "0", 0},
+		{"under an hour", "0.25", time.Hour},
+		{"six hours", "6", 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("YTDLP_UPDATE_HOURS", tt.env)
+			if got := getYtdlpUpdateInterval(); got != tt.want {
+				t.Errorf("getYtdlpUpdateInterval() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSearchCacheTTL(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want time.Duration
+	}{
+		{"empty", "", 6 * time.Hour},
+		{"invalid", "forever", 6 * time.Hour},
+		{"negative", "-1", 6 * time.Hour},
+		{"off", "0", 0},
+		{"half an hour", "0.5", 30 * time.Minute},
+		{"a day", "24", 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SEARCH_CACHE_HOURS", tt.env)
+			if got := getSearchCacheTTL(); got != tt.want {
+				t.Errorf("getSearchCacheTTL() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetHistoryRetention(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want time.Duration
+	}{
+		{"empty", "", 365 * 24 * time.Hour},
+		{"invalid", "a year", 365 * 24 * time.Hour},
+		{"negative", "-30", 365 * 24 * time.Hour},
+		{"forever", "0", 0},
+		{"ninety days", "90", 90 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HISTORY_RETENTION_DAYS", tt.env)
+			if got := getHistoryRetention(); got != tt.want {
+				t.Errorf("getHistoryRetention() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetInteractionMode(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{"empty", "", InteractionsWebhook},
+		{"webhook", "webhook", InteractionsWebhook},
+		{"gateway", "gateway", InteractionsGateway},
+		{"mixed case", " Gateway ", InteractionsGateway},
+		{"unknown", "websocket", InteractionsWebhook},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DISCORD_MODE", tt.env)
+			if got := getInteractionMode(); got != tt.want {
+				t.Errorf("getInteractionMode() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPresenceMode(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{"empty", "", PresenceTrack},
+		{"track", "track", PresenceTrack},
+		{"guilds", "guilds", PresenceGuilds},
+		{"off", " OFF ", PresenceOff},
+		{"unknown", "song", PresenceTrack},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PRESENCE", tt.env)
+			if got := getPresenceMode(); got != tt.want {
+				t.Errorf("getPresenceMode() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetAudioBitrate(t *testing.T) {
 	tests := []struct {
 		name string
@@ -101,3 +239,130 @@ func TestGetAudioBitrate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCommandCooldowns(t *testing.T) {
+	t.Setenv("COMMAND_COOLDOWNS", "")
+	if got := getCommandCooldowns(); got["play"] != 3*time.Second || got["clear"] != 10*time.Second {
+		t.Errorf("defaults = %v", got)
+	}
+
+	t.Setenv("COMMAND_COOLDOWNS", " Play = 5 ,clear=0,skip=1.5,bad,view=abc,help=-1")
+	got := getCommandCooldowns()
+	if got["play"] != 5*time.Second {
+		t.Errorf("play = %v, want 5s", got["play"])
+	}
+	if _, ok := got["clear"]; ok {
+		t.Error("clear=0 should remove the cooldown")
+	}
+	if got["skip"] != 1500*time.Millisecond {
+		t.Errorf("skip = %v, want 1.5s", got["skip"])
+	}
+	for _, command := range []string{"bad", "view", "help"} {
+		if _, ok := got[command]; ok {
+			t.Errorf("malformed entry %q was kept", command)
+		}
+	}
+	if got["search"] != 3*time.Second {
+		t.Errorf("unlisted default search = %v, want 3s", got["search"])
+	}
+}
+
+func TestGetScheduleTimezone(t *testing.T) {
+	t.Setenv("SCHEDULE_TIMEZONE", "")
+	if got := getScheduleTimezone(); got != time.Local {
+		t.Errorf("unset = %v, want Local", got)
+	}
+	t.Setenv("SCHEDULE_TIMEZONE", "Nowhere/Special")
+	if got := getScheduleTimezone(); got != time.Local {
+		t.Errorf("unknown zone = %v, want Local", got)
+	}
+	t.Setenv("SCHEDULE_TIMEZONE", "UTC")
+	if got := getScheduleTimezone(); got.String() != "UTC" {
+		t.Errorf("UTC = %v", got)
+	}
+}
+
+func TestGetGeminiTemperature(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want float32 // -1 for the model's default
+	}{
+		{"empty", "", -1},
+		{"invalid", "warm", -1},
+		{"too high", "2.5", -1},
+		{"negative", "-0.1", -1},
+		{"zero", "0", 0},
+		{"set", "0.7", 0.7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GEMINI_TEMPERATURE", tt.env)
+			got := getGeminiTemperature()
+			switch {
+			case tt.want < 0 && got != nil:
+				t.Errorf("getGeminiTemperature() = %v; want nil", *got)
+			case tt.want >= 0 && (got == nil || *got != tt.want):
+				t.Errorf("getGeminiTemperature() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGeminiLimits(t *testing.T) {
+	t.Setenv("GEMINI_MAX_OUTPUT_TOKENS", "")
+	t.Setenv("GEMINI_TIMEOUT_SECONDS", "")
+	if got := getGeminiMaxOutputTokens(); got != 0 {
+		t.Errorf("default max output tokens = %d; want 0", got)
+	}
+	if got := getGeminiTimeout(); got != 20*time.Second {
+		t.Errorf("default timeout = %v; want 20s", got)
+	}
+
+	t.Setenv("GEMINI_MAX_OUTPUT_TOKENS", "512")
+	t.Setenv("GEMINI_TIMEOUT_SECONDS", "7.5")
+	if got := getGeminiMaxOutputTokens(); got != 512 {
+		t.Errorf("max output tokens = %d; want 512", got)
+	}
+	if got := getGeminiTimeout(); got != 7500*time.Millisecond {
+		t.Errorf("timeout = %v; want 7.5s", got)
+	}
+
+	t.Setenv("GEMINI_MAX_OUTPUT_TOKENS", "-5")
+	t.Setenv("GEMINI_TIMEOUT_SECONDS", "0")
+	if got := getGeminiMaxOutputTokens(); got != 0 {
+		t.Errorf("negative max output tokens = %d; want 0", got)
+	}
+	if got := getGeminiTimeout(); got != 20*time.Second {
+		t.Errorf("zero timeout = %v; want 20s", got)
+	}
+}
+
+func TestGetGeminiRateLimitAndCache(t *testing.T) {
+	t.Setenv("GEMINI_RATE_LIMIT", "")
+	t.Setenv("GEMINI_CACHE_SECONDS", "")
+	if got := getGeminiRateLimit(); got != 20 {
+		t.Errorf("default rate limit = %d; want 20", got)
+	}
+	if got := getGeminiCacheTTL(); got != time.Minute {
+		t.Errorf("default cache TTL = %v; want 1m", got)
+	}
+
+	t.Setenv("GEMINI_RATE_LIMIT", "0")
+	t.Setenv("GEMINI_CACHE_SECONDS", "0")
+	if got := getGeminiRateLimit(); got != 0 {
+		t.Errorf("rate limit = %d; want 0 (off)", got)
+	}
+	if got := getGeminiCacheTTL(); got != 0 {
+		t.Errorf("cache TTL = %v; want 0 (off)", got)
+	}
+
+	t.Setenv("GEMINI_RATE_LIMIT", "-3")
+	t.Setenv("GEMINI_CACHE_SECONDS", "soon")
+	if got := getGeminiRateLimit(); got != 20 {
+		t.Errorf("invalid rate limit = %d; want 20", got)
+	}
+	if got := getGeminiCacheTTL(); got != time.Minute {
+		t.Errorf("invalid cache TTL = %v; want 1m", got)
+	}
+}
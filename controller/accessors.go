@@ -4,6 +4,14 @@ package controller
 // accessed from multiple goroutines. All external callers (handlers, etc.) must use
 // these instead of reading struct fields directly to avoid data races.
 
+import (
+	"slices"
+	"time"
+
+	"beatbot/config"
+	"beatbot/youtube"
+)
+
 // --- CurrentSong ---
 
 // GetCurrentSong returns the title of the currently playing song, or nil if nothing is playing.
@@ -30,6 +38,17 @@ func (p *GuildPlayer) GetCurrentItem() *GuildQueueItem {
 	return p.CurrentItem
 }
 
+// GetDeezerArtist returns the artist Deezer enrichment resolved for item, or
+// "" when it hasn't resolved (or found) one.
+func (p *GuildPlayer) GetDeezerArtist(item *GuildQueueItem) string {
+	p.currentItemMutex.RLock()
+	defer p.currentItemMutex.RUnlock()
+	if item == nil || item.DeezerMeta == nil {
+		return ""
+	}
+	return item.DeezerMeta.ArtistName
+}
+
 // --- VoiceConnection / VoiceChannelID ---
 
 // GetVoiceChannelID returns the current voice channel ID under the read lock.
@@ -62,6 +81,36 @@ func (p *GuildPlayer) SetLastTextChannelID(id string) {
 	p.LastTextChannelID = id
 }
 
+// GetGuildLocale returns the guild's Discord locale as of the latest interaction.
+func (p *GuildPlayer) GetGuildLocale() string {
+	p.lastTextChannelMu.RLock()
+	defer p.lastTextChannelMu.RUnlock()
+	return p.GuildLocale
+}
+
+// SetGuildLocale records the guild's Discord locale under the write lock.
+func (p *GuildPlayer) SetGuildLocale(locale string) {
+	p.lastTextChannelMu.Lock()
+	defer p.lastTextChannelMu.Unlock()
+	p.GuildLocale = locale
+}
+
+// --- MusicChannelID ---
+
+// GetMusicChannelID returns the bound music channel ID ("" when unbound) under the read lock.
+func (p *GuildPlayer) GetMusicChannelID() string {
+	p.lastTextChannelMu.RLock()
+	defer p.lastTextChannelMu.RUnlock()
+	return p.MusicChannelID
+}
+
+// SetMusicChannelID binds (or, with "", unbinds) the music channel under the write lock.
+func (p *GuildPlayer) SetMusicChannelID(id string) {
+	p.lastTextChannelMu.Lock()
+	defer p.lastTextChannelMu.Unlock()
+	p.MusicChannelID = id
+}
+
 // --- Queue snapshot ---
 
 // GetQueueSnapshot returns a copy of the current queue items slice under the queue mutex.
@@ -107,6 +156,407 @@ func (p *GuildPlayer) SetAnnounceVoice(voice string) {
 	p.announceMu.Unlock()
 }
 
+// --- Queue mode ---
+
+// GetQueueMode returns the guild's queue ordering mode.
+func (p *GuildPlayer) GetQueueMode() QueueMode {
+	p.Queue.Mutex.Lock()
+	defer p.Queue.Mutex.Unlock()
+	if p.Queue.Mode == "" {
+		return QueueModeFIFO
+	}
+	return p.Queue.Mode
+}
+
+// SetQueueMode switches the queue ordering mode. Switching to fair mode
+// rebalances the songs already waiting so the change takes effect immediately.
+func (p *GuildPlayer) SetQueueMode(mode QueueMode) {
+	p.Queue.Mutex.Lock()
+	p.Queue.Mode = mode
+	if mode == QueueModeFair {
+		p.Queue.rebalanceFair()
+	}
+	p.Queue.Mutex.Unlock()
+
+	if mode == QueueModeFair && p.playbackState != nil {
+		p.syncNextFromQueue()
+	}
+}
+
+// --- TranslateTitles ---
+
+// GetTranslateTitles returns whether inline title translation is enabled for the guild.
+func (p *GuildPlayer) GetTranslateTitles() bool {
+	p.translateMu.RLock()
+	defer p.translateMu.RUnlock()
+	return p.TranslateTitles
+}
+
+// SetTranslateTitles sets TranslateTitles under the translate mutex.
+func (p *GuildPlayer) SetTranslateTitles(v bool) {
+	p.translateMu.Lock()
+	p.TranslateTitles = v
+	p.translateMu.Unlock()
+}
+
+// --- FollowRequester ---
+
+// GetFollowRequester returns whether the bot follows the requester between
+// voice channels.
+func (p *GuildPlayer) GetFollowRequester() bool {
+	p.followMu.RLock()
+	defer p.followMu.RUnlock()
+	return p.FollowRequester
+}
+
+// SetFollowRequester sets FollowRequester under the follow mutex.
+func (p *GuildPlayer) SetFollowRequester(v bool) {
+	p.followMu.Lock()
+	p.FollowRequester = v
+	p.followMu.Unlock()
+}
+
+// --- DashboardEnabled ---
+
+// GetDashboardEnabled returns whether the guild keeps a dashboard message in
+// its music channel.
+func (p *GuildPlayer) GetDashboardEnabled() bool {
+	p.dashboardMu.Lock()
+	defer p.dashboardMu.Unlock()
+	return p.DashboardEnabled
+}
+
+// SetDashboardEnabled sets DashboardEnabled under the dashboard mutex.
+func (p *GuildPlayer) SetDashboardEnabled(v bool) {
+	p.dashboardMu.Lock()
+	p.DashboardEnabled = v
+	p.dashboardMu.Unlock()
+}
+
+// --- SessionThreads ---
+
+// GetSessionThreads returns whether each listening session gets its own
+// thread.
+func (p *GuildPlayer) GetSessionThreads() bool {
+	p.sessionThreadMu.Lock()
+	defer p.sessionThreadMu.Unlock()
+	return p.SessionThreads
+}
+
+// SetSessionThreads sets SessionThreads under the session thread mutex.
+// Turning it off leaves an open thread in use until its session ends.
+func (p *GuildPlayer) SetSessionThreads(v bool) {
+	p.sessionThreadMu.Lock()
+	p.SessionThreads = v
+	p.sessionThreadMu.Unlock()
+}
+
+// --- ClipsEnabled ---
+
+// GetClipsEnabled returns whether the bot keeps recent voice channel audio
+// for /clip.
+func (p *GuildPlayer) GetClipsEnabled() bool {
+	p.clipsMu.RLock()
+	defer p.clipsMu.RUnlock()
+	return p.ClipsEnabled
+}
+
+// SetClipsEnabled sets ClipsEnabled under its mutex. Turning clips on takes
+// effect the next time the bot joins voice; see StopClips for turning off.
+func (p *GuildPlayer) SetClipsEnabled(v bool) {
+	p.clipsMu.Lock()
+	p.ClipsEnabled = v
+	p.clipsMu.Unlock()
+}
+
+// --- ReactionControls ---
+
+// GetReactionControls returns whether now-playing cards get playback
+// reactions.
+func (p *GuildPlayer) GetReactionControls() bool {
+	p.reactionControlsMu.RLock()
+	defer p.reactionControlsMu.RUnlock()
+	return p.ReactionControls
+}
+
+// SetReactionControls sets ReactionControls under its mutex.
+func (p *GuildPlayer) SetReactionControls(v bool) {
+	p.reactionControlsMu.Lock()
+	p.ReactionControls = v
+	p.reactionControlsMu.Unlock()
+}
+
+// --- StripTitleEmoji ---
+
+// GetStripTitleEmoji returns whether emoji are stripped from queued titles.
+func (p *GuildPlayer) GetStripTitleEmoji() bool {
+	p.stripEmojiMu.RLock()
+	defer p.stripEmojiMu.RUnlock()
+	return p.StripTitleEmoji
+}
+
+// SetStripTitleEmoji sets StripTitleEmoji under its mutex. Only affects
+// songs queued afterwards.
+func (p *GuildPlayer) SetStripTitleEmoji(v bool) {
+	p.stripEmojiMu.Lock()
+	p.StripTitleEmoji = v
+	p.stripEmojiMu.Unlock()
+}
+
+// DisplayTitle returns the item's title with its translation appended in
+// parentheses when one has been resolved, e.g. "真夜中のドア (Mayonaka no Door)".
+func (p *GuildPlayer) DisplayTitle(item *GuildQueueItem) string {
+	p.currentItemMutex.RLock()
+	translated := item.TranslatedTitle
+	p.currentItemMutex.RUnlock()
+
+	if translated == "" || !p.GetTranslateTitles() {
+		return item.Video.Title
+	}
+	return item.Video.Title + " (" + translated + ")"
+}
+
+// --- /settings ---
+
+// GetAIStyle returns the guild's custom style notes for Gemini prompts.
+func (p *GuildPlayer) GetAIStyle() string {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
+	return p.aiStyle
+}
+
+// SetAIStyle sets the guild's custom style notes; "" clears them.
+func (p *GuildPlayer) SetAIStyle(style string) {
+	p.settingsMu.Lock()
+	p.aiStyle = style
+	p.settingsMu.Unlock()
+}
+
+// GetMaxTrackDuration returns the longest song members may queue, or 0 for
+// no limit.
+func (p *GuildPlayer) GetMaxTrackDuration() time.Duration {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
+	return p.maxTrackDuration
+}
+
+// SetMaxTrackDuration sets the longest song members may queue; 0 removes the limit.
+func (p *GuildPlayer) SetMaxTrackDuration(d time.Duration) {
+	p.settingsMu.Lock()
+	p.maxTrackDuration = max(d, 0)
+	p.settingsMu.Unlock()
+}
+
+// TooLong reports whether video is over the guild's length limit. Songs of
+// unknown length are let through.
+func (p *GuildPlayer) TooLong(video youtube.VideoResponse) bool {
+	limit := p.GetMaxTrackDuration()
+	return limit > 0 && video.Duration > limit
+}
+
+// GetEnforceVoiceMode returns "on", "off", or "" when the guild follows the
+// ENFORCE_VOICE_CHANNEL default.
+func (p *GuildPlayer) GetEnforceVoiceMode() string {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
+	return p.enforceVoice
+}
+
+// SetEnforceVoiceMode sets "on" or "off"; anything else follows the default.
+func (p *GuildPlayer) SetEnforceVoiceMode(mode string) {
+	if mode != "on" && mode != "off" {
+		mode = ""
+	}
+	p.settingsMu.Lock()
+	p.enforceVoice = mode
+	p.settingsMu.Unlock()
+}
+
+// EnforcesVoiceChannel reports whether members must be in the bot's voice
+// channel to control playback.
+func (p *GuildPlayer) EnforcesVoiceChannel() bool {
+	switch p.GetEnforceVoiceMode() {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+	return config.Config != nil && config.Config.Options.EnforceVoiceChannelEnabled()
+}
+
+// GetQuietResponses reports whether non-essential replies (AI confirmations
+// and hints) should be kept out of the channel.
+func (p *GuildPlayer) GetQuietResponses() bool {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
+	return p.quietResponses
+}
+
+// SetQuietResponses sets whether non-essential replies are ephemeral.
+func (p *GuildPlayer) SetQuietResponses(quiet bool) {
+	p.settingsMu.Lock()
+	p.quietResponses = quiet
+	p.settingsMu.Unlock()
+}
+
+// GetSearchSource returns where /play searches when the member doesn't pick
+// a source, or "" for the automatic order.
+func (p *GuildPlayer) GetSearchSource() string {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
+	return p.searchSource
+}
+
+// SetSearchSource sets the guild's default search source; "" or "auto"
+// restores the automatic order.
+func (p *GuildPlayer) SetSearchSource(source string) {
+	if source == "auto" {
+		source = ""
+	}
+	p.settingsMu.Lock()
+	p.searchSource = source
+	p.settingsMu.Unlock()
+}
+
+// --- DJ role ---
+
+// GetDJRoleID returns the guild's configured DJ role ID, or "" if none is set.
+func (p *GuildPlayer) GetDJRoleID() string {
+	p.djMu.RLock()
+	defer p.djMu.RUnlock()
+	return p.DJRoleID
+}
+
+// SetDJRoleID sets the guild's DJ role ID under the DJ mutex. Pass "" to clear it.
+func (p *GuildPlayer) SetDJRoleID(roleID string) {
+	p.djMu.Lock()
+	p.DJRoleID = roleID
+	p.djMu.Unlock()
+}
+
+// SetMemberRoles records the roles a member had on their latest interaction.
+func (p *GuildPlayer) SetMemberRoles(userID string, roles []string) {
+	if userID == "" {
+		return
+	}
+	p.djMu.Lock()
+	if p.memberRoles == nil {
+		p.memberRoles = make(map[string][]string)
+	}
+	p.memberRoles[userID] = roles
+	p.djMu.Unlock()
+}
+
+// IsDJ returns true if the member held the guild's DJ role on their latest
+// interaction, or currently has an unexpired guest DJ grant.
+func (p *GuildPlayer) IsDJ(userID string) bool {
+	p.djMu.RLock()
+	defer p.djMu.RUnlock()
+	if userID == "" {
+		return false
+	}
+	if expiry, ok := p.guestDJs[userID]; ok && time.Now().Before(expiry) {
+		return true
+	}
+	return p.hasDJRoleLocked(userID)
+}
+
+// HasDJRole is IsDJ without guest grants: whether the member held the
+// guild's DJ role on their latest interaction.
+func (p *GuildPlayer) HasDJRole(userID string) bool {
+	p.djMu.RLock()
+	defer p.djMu.RUnlock()
+	return p.hasDJRoleLocked(userID)
+}
+
+func (p *GuildPlayer) hasDJRoleLocked(userID string) bool {
+	if userID == "" || p.DJRoleID == "" {
+		return false
+	}
+	for _, role := range p.memberRoles[userID] {
+		if role == p.DJRoleID {
+			return true
+		}
+	}
+	return false
+}
+
+// DJRestrictableCommands are the commands /djrole restrict can limit to DJs
+// and server managers, in display order.
+var DJRestrictableCommands = []string{"skip", "clear", "reset", "volume"}
+
+// SetDJOnlyCommands replaces the set of commands restricted to DJs. Names
+// outside DJRestrictableCommands are ignored.
+func (p *GuildPlayer) SetDJOnlyCommands(commands []string) {
+	djOnly := make(map[string]bool)
+	for _, command := range commands {
+		if slices.Contains(DJRestrictableCommands, command) {
+			djOnly[command] = true
+		}
+	}
+	p.djMu.Lock()
+	p.djOnly = djOnly
+	p.djMu.Unlock()
+}
+
+// GetDJOnlyCommands returns the commands restricted to DJs, in
+// DJRestrictableCommands order.
+func (p *GuildPlayer) GetDJOnlyCommands() []string {
+	p.djMu.RLock()
+	defer p.djMu.RUnlock()
+	var commands []string
+	for _, command := range DJRestrictableCommands {
+		if p.djOnly[command] {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// IsDJOnly reports whether command is restricted to DJs in this guild.
+func (p *GuildPlayer) IsDJOnly(command string) bool {
+	p.djMu.RLock()
+	defer p.djMu.RUnlock()
+	return p.djOnly[command]
+}
+
+// GrantGuestDJ gives a member DJ permissions until now+d, replacing any
+// existing grant. Returns the expiry time.
+func (p *GuildPlayer) GrantGuestDJ(userID string, d time.Duration) time.Time {
+	expiry := time.Now().Add(d)
+	p.djMu.Lock()
+	if p.guestDJs == nil {
+		p.guestDJs = make(map[string]time.Time)
+	}
+	p.guestDJs[userID] = expiry
+	p.djMu.Unlock()
+	return expiry
+}
+
+// RevokeGuestDJ ends a member's guest DJ grant early. Returns false if they
+// didn't have an active one.
+func (p *GuildPlayer) RevokeGuestDJ(userID string) bool {
+	p.djMu.Lock()
+	defer p.djMu.Unlock()
+	expiry, ok := p.guestDJs[userID]
+	delete(p.guestDJs, userID)
+	return ok && time.Now().Before(expiry)
+}
+
+// ExpireGuestDJ removes a guest DJ grant if it still has the given expiry,
+// i.e. it wasn't revoked or re-granted in the meantime. Returns true if the
+// grant was removed.
+func (p *GuildPlayer) ExpireGuestDJ(userID string, expiry time.Time) bool {
+	p.djMu.Lock()
+	defer p.djMu.Unlock()
+	current, ok := p.guestDJs[userID]
+	if !ok || !current.Equal(expiry) {
+		return false
+	}
+	delete(p.guestDJs, userID)
+	return true
+}
+
 // TriggerTTSRegen signals the TTS watcher to attempt generation for the current transition.
 // Safe to call when no next song is set — generateTransitionTTS will no-op.
 func (p *GuildPlayer) TriggerTTSRegen() {
@@ -128,6 +578,10 @@ func (p *GuildPlayer) ShouldJoinVoice(requesterChannelID string) bool {
 	if vc == nil || vcID == nil {
 		return true
 	}
-	// Move to the requester's channel if we're stopped and in a different one.
-	return p.IsEmpty() && !p.Player.IsPlaying() && *vcID != requesterChannelID
+	if *vcID == requesterChannelID {
+		return false
+	}
+	// Move to the requester's channel if we're stopped, or if nobody's left
+	// listening where we are. Otherwise the bot is busy for someone else.
+	return (p.IsEmpty() && !p.Player.IsPlaying()) || p.listenerCount() == 0
 }
@@ -3,6 +3,7 @@ package controller
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"beatbot/youtube"
 )
@@ -274,3 +275,38 @@ func TestShouldJoinVoiceEmptyAndStopped(t *testing.T) {
 func TestShouldJoinVoicePlaying(t *testing.T) {
 	t.Skip("Requires real Player instance")
 }
+
+func TestGuestDJGrantAndExpiry(t *testing.T) {
+	p := &GuildPlayer{Queue: &GuildQueue{}}
+
+	if p.IsDJ("guest") {
+		t.Fatal("guest should not be a DJ before a grant")
+	}
+
+	expiry := p.GrantGuestDJ("guest", time.Hour)
+	if !p.IsDJ("guest") {
+		t.Error("guest should be a DJ during the grant")
+	}
+
+	// Re-granting replaces the expiry, so the old timer must not expire it.
+	p.GrantGuestDJ("guest", 2*time.Hour)
+	if p.ExpireGuestDJ("guest", expiry) {
+		t.Error("ExpireGuestDJ should ignore a stale expiry after a re-grant")
+	}
+	if !p.IsDJ("guest") {
+		t.Error("guest should still be a DJ after the re-grant")
+	}
+
+	if !p.RevokeGuestDJ("guest") {
+		t.Error("RevokeGuestDJ should report an active grant")
+	}
+	if p.IsDJ("guest") {
+		t.Error("guest should not be a DJ after revoke")
+	}
+
+	// Lapsed grants no longer count.
+	p.GrantGuestDJ("guest", -time.Second)
+	if p.IsDJ("guest") {
+		t.Error("expired grant should not count as DJ")
+	}
+}
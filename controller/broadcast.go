@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BroadcastResult counts where an operator announcement went.
+type BroadcastResult struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+	Paused int `json:"paused"`
+}
+
+// Broadcast posts an operator announcement, like a restart warning, to
+// every guild's music channel and to the text channel of any guild in voice
+// without one. With pause, everything playing is paused too, so a restart
+// doesn't cut songs off mid-way.
+func (c *Controller) Broadcast(message string, pause bool) BroadcastResult {
+	var result BroadcastResult
+	if pause {
+		// Pause can block on a full notification channel, so it runs on a
+		// copy rather than under c.mu.
+		c.mu.RLock()
+		players := make([]*GuildPlayer, 0, len(c.sessions))
+		for _, player := range c.sessions {
+			players = append(players, player)
+		}
+		c.mu.RUnlock()
+
+		for _, player := range players {
+			if player.Player != nil && player.Player.IsPlaying() && !player.Player.IsPaused() {
+				player.Player.Pause(context.Background())
+				result.Paused++
+			}
+		}
+	}
+
+	for _, channelID := range c.broadcastChannels() {
+		if _, err := c.discord.ChannelMessageSend(channelID, "📢 "+message); err != nil {
+			log.Warnf("Failed to broadcast to channel %s: %v", channelID, err)
+			result.Failed++
+			continue
+		}
+		result.Sent++
+	}
+	log.Infof("Broadcast sent to %d channels (%d failed), paused %d players", result.Sent, result.Failed, result.Paused)
+	return result
+}
+
+// broadcastChannels lists each channel a broadcast goes to once: every bound
+// music channel, including guilds without a player yet, and where each
+// player in voice is posting.
+func (c *Controller) broadcastChannels() []string {
+	channels := make(map[string]bool)
+	if c.db != nil {
+		bound, err := c.db.GuildsWithSetting("music_channel_id")
+		if err != nil {
+			log.Warnf("Failed to list music channels for broadcast: %v", err)
+		}
+		for _, channelID := range bound {
+			channels[channelID] = true
+		}
+	}
+
+	c.mu.RLock()
+	for _, player := range c.sessions {
+		if id := player.GetMusicChannelID(); id != "" {
+			channels[id] = true
+			continue
+		}
+		if player.GetVoiceChannelID() == nil {
+			continue
+		}
+		if id := player.textChannelID(); id != "" {
+			channels[id] = true
+		}
+	}
+	c.mu.RUnlock()
+
+	list := make([]string, 0, len(channels))
+	for id := range channels {
+		list = append(list, id)
+	}
+	sort.Strings(list)
+	return list
+}
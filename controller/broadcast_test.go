@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"beatbot/database"
+)
+
+func TestBroadcastChannels(t *testing.T) {
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := database.New()
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	defer db.Close()
+	db.SetGuildSetting("g1", "music_channel_id", "c1")
+	db.SetGuildSetting("g9", "music_channel_id", "c9") // no player since startup
+
+	bound := &GuildPlayer{GuildID: "g1"}
+	bound.SetMusicChannelID("c1")
+	vc := "vc2"
+	inVoice := &GuildPlayer{GuildID: "g2", VoiceChannelID: &vc}
+	inVoice.SetLastTextChannelID("t2")
+	idle := &GuildPlayer{GuildID: "g3"}
+	idle.SetLastTextChannelID("t3")
+
+	c := &Controller{db: db, sessions: map[string]*GuildPlayer{"g1": bound, "g2": inVoice, "g3": idle}}
+	if got, want := c.broadcastChannels(), []string{"c1", "c9", "t2"}; !slices.Equal(got, want) {
+		t.Errorf("broadcastChannels = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/audio"
+	"beatbot/discord"
+)
+
+// clipsSetting is the guild setting ("true"/"false") that lets the bot keep
+// the last clipWindow of its voice channel for /clip.
+const clipsSetting = "clips_enabled"
+
+// ClipConsentPrefix starts the user setting holding whether a member agreed
+// to be in a server's clips; the server's ID follows. Consent is per server.
+const ClipConsentPrefix = "clip_consent:"
+
+// clipWindow is how far back a clip reaches.
+const clipWindow = 30 * time.Second
+
+// ErrNoClipAudio means nobody who agreed to be recorded has spoken lately.
+var ErrNoClipAudio = errors.New("no clip audio")
+
+// clipRecorder keeps the last clipWindow of what members who consented said
+// in the bot's voice channel. Nobody else's audio is kept, and everything
+// is dropped when the bot leaves or clips are turned off.
+type clipRecorder struct {
+	enabled func() bool
+	consent func(userID string) bool
+
+	mu        sync.Mutex
+	vc        *discordgo.VoiceConnection
+	stop      chan struct{}
+	packets   []audio.ClipPacket
+	users     map[uint32]string // SSRC -> user ID, from speaking updates
+	consented map[string]bool   // user ID -> consent, cached from user settings
+}
+
+func newClipRecorder(enabled func() bool, consent func(userID string) bool) *clipRecorder {
+	return &clipRecorder{
+		enabled:   enabled,
+		consent:   consent,
+		users:     make(map[uint32]string),
+		consented: make(map[string]bool),
+	}
+}
+
+// listen starts keeping audio from vc, replacing any earlier connection.
+// Connections made deafened have no audio to keep.
+func (r *clipRecorder) listen(vc *discordgo.VoiceConnection) {
+	if r == nil || vc == nil || vc.OpusRecv == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.vc == vc {
+		return
+	}
+	r.stopLocked()
+	r.vc = vc
+	r.stop = make(chan struct{})
+	vc.AddHandler(r.onSpeaking)
+	go r.receive(vc, vc.OpusRecv, r.stop)
+}
+
+// close stops listening and drops everything kept.
+func (r *clipRecorder) close() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stopLocked()
+}
+
+func (r *clipRecorder) stopLocked() {
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+	r.vc = nil
+	r.packets = nil
+	clear(r.users)
+	// Re-read consent next time, in case it was withdrawn or deleted meanwhile.
+	clear(r.consented)
+}
+
+// onSpeaking learns which user sends on which SSRC.
+func (r *clipRecorder) onSpeaking(vc *discordgo.VoiceConnection, update *discordgo.VoiceSpeakingUpdate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.vc == vc && update.UserID != "" {
+		r.users[uint32(update.SSRC)] = update.UserID
+	}
+}
+
+func (r *clipRecorder) receive(vc *discordgo.VoiceConnection, packets <-chan *discordgo.Packet, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case pkt, ok := <-packets:
+			if !ok {
+				return
+			}
+			r.keep(vc, pkt, time.Now())
+		}
+	}
+}
+
+// keep adds pkt if its sender agreed to be recorded, and drops anything
+// older than clipWindow.
+func (r *clipRecorder) keep(vc *discordgo.VoiceConnection, pkt *discordgo.Packet, now time.Time) {
+	if !r.enabled() {
+		return
+	}
+	r.mu.Lock()
+	userID, known := r.users[pkt.SSRC]
+	r.mu.Unlock()
+	if !known || !r.hasConsent(userID) {
+		return
+	}
+
+	frame, err := discord.DecryptVoiceFrame(vc, userID, pkt.Opus)
+	if err != nil {
+		log.Debugf("Dropping voice frame from %s: %v", userID, err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.vc != vc {
+		return
+	}
+	r.packets = append(r.packets, audio.ClipPacket{
+		SSRC:      pkt.SSRC,
+		Timestamp: pkt.Timestamp,
+		Arrived:   now,
+		Opus:      frame,
+	})
+	r.pruneLocked(now)
+}
+
+func (r *clipRecorder) pruneLocked(now time.Time) {
+	cutoff := now.Add(-clipWindow)
+	i := 0
+	for i < len(r.packets) && r.packets[i].Arrived.Before(cutoff) {
+		i++
+	}
+	r.packets = r.packets[i:]
+}
+
+func (r *clipRecorder) hasConsent(userID string) bool {
+	r.mu.Lock()
+	consented, cached := r.consented[userID]
+	r.mu.Unlock()
+	if cached {
+		return consented
+	}
+	consented = r.consent(userID)
+	r.mu.Lock()
+	r.consented[userID] = consented
+	r.mu.Unlock()
+	return consented
+}
+
+// setConsent records a change of mind. Withdrawing drops what was kept of
+// userID straight away.
+func (r *clipRecorder) setConsent(userID string, consented bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.consented[userID] = consented
+	if consented {
+		return
+	}
+	kept := r.packets[:0]
+	for _, pkt := range r.packets {
+		if r.users[pkt.SSRC] != userID {
+			kept = append(kept, pkt)
+		}
+	}
+	r.packets = kept
+}
+
+// snapshot returns the packets kept as of now and when the clip starts:
+// clipWindow ago, or at the first kept packet if that's later.
+func (r *clipRecorder) snapshot(now time.Time) ([]audio.ClipPacket, time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked(now)
+	if len(r.packets) == 0 {
+		return nil, now
+	}
+	packets := make([]audio.ClipPacket, len(r.packets))
+	copy(packets, r.packets)
+	return packets, packets[0].Arrived
+}
+
+// Clip returns the last clipWindow of the voice channel as a WAV file, with
+// only the members who agreed to be recorded in it.
+func (p *GuildPlayer) Clip() ([]byte, error) {
+	now := time.Now()
+	packets, start := p.clips.snapshot(now)
+	if len(packets) == 0 {
+		return nil, ErrNoClipAudio
+	}
+	pcm, err := audio.MixClip(packets, start, now.Sub(start))
+	if err != nil {
+		return nil, err
+	}
+	return audio.EncodeWAV(pcm, audio.ClipSampleRate, 1), nil
+}
+
+// HasClipConsent reports whether userID agreed to be in this server's clips.
+func (p *GuildPlayer) HasClipConsent(userID string) bool {
+	if p.DB == nil || userID == "" {
+		return false
+	}
+	value, err := p.DB.GetUserSetting(userID, ClipConsentPrefix+p.GuildID)
+	if err != nil {
+		log.Warnf("Failed to read clip consent for %s: %v", userID, err)
+		return false
+	}
+	consented, _ := strconv.ParseBool(value)
+	return consented
+}
+
+// ForgetClipConsent withdraws userID's clip consent in every active player
+// and drops any of their audio being kept, after their stored consent was
+// deleted along with the rest of their data.
+func (c *Controller) ForgetClipConsent(userID string) {
+	c.mu.RLock()
+	players := make([]*GuildPlayer, 0, len(c.sessions))
+	for _, p := range c.sessions {
+		players = append(players, p)
+	}
+	c.mu.RUnlock()
+
+	for _, p := range players {
+		if p.clips != nil {
+			p.clips.setConsent(userID, false)
+		}
+	}
+}
+
+// SetClipConsent saves whether userID agrees to be in this server's clips.
+func (p *GuildPlayer) SetClipConsent(userID string, consented bool) error {
+	if p.DB == nil {
+		return errors.New("no database")
+	}
+	if err := p.DB.SetUserSetting(userID, ClipConsentPrefix+p.GuildID, strconv.FormatBool(consented)); err != nil {
+		return err
+	}
+	p.clips.setConsent(userID, consented)
+	return nil
+}
+
+// StopClips drops the clip audio kept and deafens the bot, so Discord stops
+// sending the channel's audio until clips are turned back on and the bot
+// rejoins.
+func (p *GuildPlayer) StopClips() {
+	p.clips.close()
+	p.VoiceChannelMutex.RLock()
+	channelID := p.VoiceChannelID
+	p.VoiceChannelMutex.RUnlock()
+	if channelID == nil || p.Discord == nil {
+		return
+	}
+	if err := p.Discord.ChannelVoiceJoinManual(p.GuildID, *channelID, false, true); err != nil {
+		log.Warnf("Failed to deafen after turning clips off in %s: %v", p.GuildID, err)
+	}
+}
+
+// listenForClips starts keeping clip audio from vc.
+func (p *GuildPlayer) listenForClips(vc *discordgo.VoiceConnection) {
+	if p.GetClipsEnabled() {
+		p.clips.listen(vc)
+	}
+}
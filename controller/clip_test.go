@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestClipRecorderKeepsOnlyConsented(t *testing.T) {
+	enabled := true
+	r := newClipRecorder(func() bool { return enabled }, func(userID string) bool { return userID == "yes" })
+	vc := &discordgo.VoiceConnection{}
+	r.vc = vc
+	r.onSpeaking(vc, &discordgo.VoiceSpeakingUpdate{UserID: "yes", SSRC: 1})
+	r.onSpeaking(vc, &discordgo.VoiceSpeakingUpdate{UserID: "no", SSRC: 2})
+
+	now := time.Now()
+	r.keep(vc, &discordgo.Packet{SSRC: 1, Opus: []byte{1}}, now)
+	r.keep(vc, &discordgo.Packet{SSRC: 2, Opus: []byte{2}}, now)
+	r.keep(vc, &discordgo.Packet{SSRC: 3, Opus: []byte{3}}, now) // unknown sender
+	r.keep(&discordgo.VoiceConnection{}, &discordgo.Packet{SSRC: 1, Opus: []byte{4}}, now)
+
+	packets, _ := r.snapshot(now)
+	if len(packets) != 1 || packets[0].SSRC != 1 {
+		t.Fatalf("kept %+v, want only the consenting speaker's frame", packets)
+	}
+
+	enabled = false
+	r.keep(vc, &discordgo.Packet{SSRC: 1, Opus: []byte{5}}, now)
+	if packets, _ := r.snapshot(now); len(packets) != 1 {
+		t.Errorf("kept %d packets with clips off, want 1", len(packets))
+	}
+}
+
+func TestClipRecorderWindow(t *testing.T) {
+	r := newClipRecorder(func() bool { return true }, func(string) bool { return true })
+	vc := &discordgo.VoiceConnection{}
+	r.vc = vc
+	r.onSpeaking(vc, &discordgo.VoiceSpeakingUpdate{UserID: "u", SSRC: 1})
+
+	start := time.Now()
+	r.keep(vc, &discordgo.Packet{SSRC: 1, Opus: []byte{1}}, start)
+	r.keep(vc, &discordgo.Packet{SSRC: 1, Opus: []byte{2}}, start.Add(20*time.Second))
+
+	packets, clipStart := r.snapshot(start.Add(40 * time.Second))
+	if len(packets) != 1 || packets[0].Opus[0] != 2 {
+		t.Fatalf("kept %+v, want only the frame inside the window", packets)
+	}
+	if !clipStart.Equal(start.Add(20 * time.Second)) {
+		t.Errorf("clip starts at %v, want the first kept frame", clipStart)
+	}
+}
+
+func TestClipRecorderOptOutDropsAudio(t *testing.T) {
+	r := newClipRecorder(func() bool { return true }, func(string) bool { return true })
+	vc := &discordgo.VoiceConnection{}
+	r.vc = vc
+	r.onSpeaking(vc, &discordgo.VoiceSpeakingUpdate{UserID: "a", SSRC: 1})
+	r.onSpeaking(vc, &discordgo.VoiceSpeakingUpdate{UserID: "b", SSRC: 2})
+
+	now := time.Now()
+	r.keep(vc, &discordgo.Packet{SSRC: 1, Opus: []byte{1}}, now)
+	r.keep(vc, &discordgo.Packet{SSRC: 2, Opus: []byte{2}}, now)
+	r.setConsent("a", false)
+	r.keep(vc, &discordgo.Packet{SSRC: 1, Opus: []byte{3}}, now)
+
+	packets, _ := r.snapshot(now)
+	if len(packets) != 1 || packets[0].SSRC != 2 {
+		t.Fatalf("kept %+v after opt-out, want only b's frame", packets)
+	}
+
+	r.close()
+	if packets, _ := r.snapshot(now); len(packets) != 0 {
+		t.Errorf("kept %d packets after close", len(packets))
+	}
+	if len(r.consented) != 0 {
+		t.Errorf("consent cache kept %v after close", r.consented)
+	}
+}
+
+func TestForgetClipConsent(t *testing.T) {
+	consent := true
+	r := newClipRecorder(func() bool { return true }, func(string) bool { return consent })
+	vc := &discordgo.VoiceConnection{}
+	r.vc = vc
+	r.onSpeaking(vc, &discordgo.VoiceSpeakingUpdate{UserID: "a", SSRC: 1})
+	now := time.Now()
+	r.keep(vc, &discordgo.Packet{SSRC: 1, Opus: []byte{1}}, now)
+
+	// The stored consent is deleted; the cache must not outlive it.
+	consent = false
+	c := &Controller{sessions: map[string]*GuildPlayer{"g1": {clips: r}}}
+	c.ForgetClipConsent("a")
+	r.keep(vc, &discordgo.Packet{SSRC: 1, Opus: []byte{2}}, now)
+	if packets, _ := r.snapshot(now); len(packets) != 0 {
+		t.Errorf("kept %d packets of a user whose data was deleted", len(packets))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"beatbot/deezer"
 	"beatbot/discord"
 	"beatbot/gemini"
+	"beatbot/i18n"
 	"beatbot/sentryhelper"
 	"beatbot/spotify"
 	"beatbot/tts"
@@ -115,12 +116,18 @@ type GuildPlayer struct {
 	VoiceConnection        *discordgo.VoiceConnection
 	reconnectAttempts      int
 	maxReconnectAttempts   int
+	recovering             atomic.Bool // a voice recovery (and its retries) is in flight
 	voiceMonitorStop       chan struct{}
 	Loader                 *audio.Loader
 	Player                 *audio.Player
 	LastActivityAt         time.Time
 	LastTextChannelID      string
-	lastTextChannelMu      sync.RWMutex // protects LastTextChannelID
+	MusicChannelID         string       // admin-bound channel for now-playing and queue posts (persisted via guild_settings)
+	GuildLocale            string       // Discord locale from the latest interaction, for messages the player sends on its own
+	lastTextChannelMu      sync.RWMutex // protects LastTextChannelID, MusicChannelID and GuildLocale
+	pendingAdds            []*GuildQueueItem
+	addAnnounceTimer       *time.Timer
+	addAnnounceMu          sync.Mutex // protects pendingAdds and addAnnounceTimer
 	idleCheckStop          chan struct{}
 	queueListenerStop      chan struct{}
 	playbackListenerStop   chan struct{}
@@ -150,46 +157,160 @@ type GuildPlayer struct {
 	AnnounceVoice   string
 	announceMu      sync.RWMutex // protects AnnounceEnabled + AnnounceVoice (read by TTS watcher goroutine, written by command handlers)
 
+	// Inline Gemini translation of non-Latin titles (persisted via guild_settings)
+	TranslateTitles bool
+	translateMu     sync.RWMutex
+
+	// Move with the requester when they switch voice channels (persisted via guild_settings)
+	FollowRequester bool
+	followMu        sync.RWMutex
+
+	// Set through /settings (persisted via guild_settings)
+	aiStyle          string        // extra style notes for Gemini prompts
+	maxTrackDuration time.Duration // longest song a member can queue; 0 means no limit
+	enforceVoice     string        // "on", "off", or "" to follow ENFORCE_VOICE_CHANNEL
+	quietResponses   bool          // AI confirmations go only to whoever asked, and no hints
+	searchSource     string        // where /play searches when no source is given; "" means auto
+	settingsMu       sync.RWMutex
+
+	// Strip emoji from titles as songs are queued (persisted via guild_settings)
+	StripTitleEmoji bool
+	stripEmojiMu    sync.RWMutex
+
+	// DJ role (persisted via guild_settings) and the roles of members seen in
+	// recent interactions, so Add can prioritize DJ requests without a REST call.
+	DJRoleID    string
+	memberRoles map[string][]string
+	guestDJs    map[string]time.Time // userID -> expiry of a temporary /guestdj grant (in-memory only)
+	djOnly      map[string]bool      // commands restricted to DJs and server managers (persisted via guild_settings)
+	djMu        sync.RWMutex
+
 	// Now-playing card tracking
 	NowPlayingMessageID   *string
 	NowPlayingChannelID   *string
 	nowPlayingMutex       sync.Mutex
 	nowPlayingUpdateStop  chan struct{}
 	nowPlayingCurrentItem *GuildQueueItem
-	permFallbackSent      atomic.Bool // prevents per-song spam of the reinstall notice
+	permFallbackSent      atomic.Bool     // prevents per-song spam of the reinstall notice
+	nowPlayingPost        *nowPlayingPost // card posted by /nowplaying, refreshed until its track ends
+	nowPlayingPostMu      sync.Mutex
+
+	// Music channel dashboard (persisted via guild_settings); see RefreshDashboard
+	DashboardEnabled   bool
+	dashboardChannelID string
+	dashboardMessageID string
+	dashboardTimer     *time.Timer
+	dashboardMu        sync.Mutex // protects the fields above
+	dashboardPostMu    sync.Mutex // serializes dashboard edits
+
+	// Per-session threads (persisted via guild_settings); see openSessionThread
+	SessionThreads     bool
+	sessionThreadID    string
+	sessionThreadTried bool       // this session already made (or failed to make) its thread
+	sessionThreadMu    sync.Mutex // protects the fields above
+
+	// Voice clips (persisted via guild_settings); see clipRecorder
+	ClipsEnabled bool
+	clipsMu      sync.RWMutex
+	clips        *clipRecorder
+
+	// Playback reactions on now-playing cards (persisted via guild_settings); see addControlReactions
+	ReactionControls   bool
+	reactionControlsMu sync.RWMutex
+
+	// Listen-along link on now-playing cards, from /listen-along until the
+	// bot leaves voice; see StartListenAlong
+	listenAlong   bool
+	listenAlongMu sync.Mutex
+
+	// Pending write of the volume to guild_settings; see SaveVolumeSoon
+	volumeSaveTimer *time.Timer
+	volumeSaveMu    sync.Mutex
 
 	// Player-scoped context: cancelled by Reset() to stop all ad-hoc goroutines
 	// (e.g. voice recovery retries) that don't have a dedicated stop channel.
 	playerCtx    context.Context
 	playerCancel context.CancelFunc
+
+	// Only one play() may own the voice connection at a time.
+	playback playbackGuard
+
+	// One-level snapshot for /undo, taken before destructive queue edits.
+	undoSnapshot *queueSnapshot
+	undoMu       sync.Mutex
+
+	// Listener tracking; see checkListeners
+	autoPaused bool        // paused because the channel emptied, not by /pause
+	emptyTimer *time.Timer // leaves once the channel has stayed empty
+	muted      bool        // the bot is muted in voice; see checkMuted
+	mutePaused bool        // paused because the bot was muted
+	deafened   bool        // the bot is server deafened
+	presenceMu sync.Mutex
+	leaveHook  func(p *GuildPlayer) int // saves the queue before leaving on its own; see SetLeaveHook
+
+	status *botStatus // the bot's presence, shared with every player
 }
 
 type GuildQueueItemInteraction struct {
 	UserID           string
 	InteractionToken string
 	AppID            string
+	IssuedAt         time.Time // roughly when the token was issued (set on queueing); see TokenExpired
 }
 
 type GuildQueueItem struct {
-	Video          youtube.VideoResponse
-	Stream         *youtube.YoutubeStream
-	streamReady    chan struct{} // closed by handleAdd when Stream is set; replaces the busy-poll
-	LoadResult     *audio.LoadResult
-	ProbedDuration time.Duration
-	AddedAt        time.Time
-	Interaction    *GuildQueueItemInteraction
-	LoadAttempts   int
-	MaxAttempts    int
-	Context        context.Context         // Sentry context for tracing
-	Commentary     string                  // AI-generated commentary for this song
-	IsRadioPick    bool                    // Whether this song was auto-queued by radio mode
-	FallbackVideos []youtube.VideoResponse // Alternate candidates to try if primary is age-restricted (search results only)
-	DeezerMeta     *deezer.TrackMeta       // Deezer enrichment metadata (BPM, genre, album art, etc.)
+	Video           youtube.VideoResponse
+	Stream          *youtube.YoutubeStream
+	streamReady     chan struct{} // closed by handleAdd when Stream is set; replaces the busy-poll
+	LoadResult      *audio.LoadResult
+	ProbedDuration  time.Duration
+	AddedAt         time.Time
+	Interaction     *GuildQueueItemInteraction
+	LoadAttempts    int
+	MaxAttempts     int
+	Context         context.Context         // Sentry context for tracing
+	Commentary      string                  // AI-generated commentary for this song
+	IsRadioPick     bool                    // Whether this song was auto-queued by radio mode
+	IsDJPick        bool                    // Queued by a member holding the guild's DJ role; jumps ahead of regular requests
+	announceAdd     bool                    // post to the music channel once the stream resolves
+	state           int32                   // ItemState; read via State(), written by the controller
+	playID          int64                   // song_history row of the current play; 0 once its end is recorded
+	FallbackVideos  []youtube.VideoResponse // Alternate candidates to try if primary is age-restricted (search results only)
+	DeezerMeta      *deezer.TrackMeta       // Deezer enrichment metadata (BPM, genre, album art, etc.)
+	TranslatedTitle string                  // Gemini translation of a non-Latin title; guarded by currentItemMutex
+	SongLink        string                  // song.link page for /listen-along; guarded by currentItemMutex
+	cancelLoad      context.CancelFunc      // cancels Context, aborting the yt-dlp lookup if the item is dropped
+	upNextNotified  atomic.Bool             // the requester was DMed that this song is next; see notifyUpNext
+}
+
+// releaseLoad cancels any in-flight stream lookup for an item that was removed
+// from the queue and drops its buffered audio so GC can reclaim it. Caller
+// must hold the queue mutex.
+func (item *GuildQueueItem) releaseLoad() {
+	if item == nil {
+		return
+	}
+	if item.cancelLoad != nil {
+		item.cancelLoad()
+	}
+	item.LoadResult = nil
 }
 
+// QueueMode controls how regular user requests are ordered in the queue.
+type QueueMode string
+
+const (
+	// QueueModeFIFO plays requests in the order they were made.
+	QueueModeFIFO QueueMode = "fifo"
+	// QueueModeFair interleaves requests round-robin by requester
+	// (A, B, C, A, B, …) so one person can't monopolize the queue.
+	QueueModeFair QueueMode = "fair"
+)
+
 type GuildQueue struct {
 	Items         []*GuildQueueItem
 	Listening     bool
+	Mode          QueueMode // guarded by Mutex; "" behaves like QueueModeFIFO
 	Mutex         sync.Mutex
 	notifications chan QueueEvent
 }
@@ -200,7 +321,10 @@ type Controller struct {
 	discord  *discordgo.Session
 	spotify  *spotifyclient.Client
 	db       *database.Database
-	mu       sync.RWMutex // protects sessions map
+	mu       sync.RWMutex // protects sessions map and leaveHook
+
+	leaveHook func(p *GuildPlayer) int
+	status    *botStatus
 }
 
 func NewController(db *database.Database) (*Controller, error) {
@@ -231,12 +355,18 @@ func NewController(db *database.Database) (*Controller, error) {
 		}
 	}
 
-	return &Controller{
+	c := &Controller{
 		sessions: make(map[string]*GuildPlayer),
 		discord:  discord,
 		spotify:  spotify.Spotify,
 		db:       db,
-	}, nil
+		status:   newBotStatus(discord, config.Config.Options.Presence),
+	}
+	// Pause when everyone leaves the bot's channel, resume when they return.
+	discord.AddHandler(c.onVoiceStateUpdate)
+	discord.AddHandler(c.onGuildCreate)
+	discord.AddHandler(c.onGuildDelete)
+	return c, nil
 }
 
 type ActiveSession struct {
@@ -268,6 +398,19 @@ func (c *Controller) GetDB() *database.Database {
 	return c.db
 }
 
+// GetSession returns the bot's gateway session.
+func (c *Controller) GetSession() *discordgo.Session {
+	return c.discord
+}
+
+// LookupPlayer returns guildID's player if it has one, without creating it.
+func (c *Controller) LookupPlayer(guildID string) (*GuildPlayer, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	player, ok := c.sessions[guildID]
+	return player, ok
+}
+
 func (c *Controller) GetPlayer(guildID string) *GuildPlayer {
 	// Fast path: read lock allows concurrent lookups without contention.
 	c.mu.RLock()
@@ -294,7 +437,7 @@ func (c *Controller) GetPlayer(guildID string) *GuildPlayer {
 		return nil
 	}
 
-	playerCtx, playerCancel := context.WithCancel(context.Background())
+	playerCtx, playerCancel := context.WithCancel(gemini.WithGuild(context.Background(), guildID))
 	ps := newPlaybackState()
 	player.SetTTSConsumer(ps)
 
@@ -319,7 +462,10 @@ func (c *Controller) GetPlayer(guildID string) *GuildPlayer {
 		SongHistory:          NewSongHistory(20),
 		playerCtx:            playerCtx,
 		playerCancel:         playerCancel,
+		leaveHook:            c.leaveHook,
+		status:               c.status,
 	}
+	session.clips = newClipRecorder(session.GetClipsEnabled, session.HasClipConsent)
 
 	// Load announce settings from DB — default to enabled; only disable when explicitly stored as "false"
 	if val, _ := c.db.GetGuildSetting(guildID, "announce_enabled"); val != "false" {
@@ -334,6 +480,75 @@ func (c *Controller) GetPlayer(guildID string) *GuildPlayer {
 		}
 		session.SetAnnounceVoice(defaultVoice)
 	}
+	// Title translation is opt-in — it costs a Gemini call per foreign-language song
+	if val, _ := c.db.GetGuildSetting(guildID, "translate_titles"); val == "true" {
+		session.SetTranslateTitles(true)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "follow_requester"); val == "true" {
+		session.SetFollowRequester(true)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "dj_role_id"); val != "" {
+		session.SetDJRoleID(val)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "dj_only_commands"); val != "" {
+		session.SetDJOnlyCommands(strings.Split(val, ","))
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "queue_mode"); val == string(QueueModeFair) {
+		session.Queue.Mode = QueueModeFair
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "strip_title_emoji"); val == "true" {
+		session.SetStripTitleEmoji(true)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "music_channel_id"); val != "" {
+		session.SetMusicChannelID(val)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "dashboard_enabled"); val == "true" {
+		session.SetDashboardEnabled(true)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, sessionThreadSetting); val == "true" {
+		session.SetSessionThreads(true)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, clipsSetting); val == "true" {
+		session.SetClipsEnabled(true)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, reactionControlsSetting); val == "true" {
+		session.SetReactionControls(true)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "branding"); val != "" {
+		if branding, err := discord.DecodeBranding(val); err == nil {
+			discord.SetBranding(guildID, branding)
+		} else {
+			log.Warnf("Ignoring unreadable branding for guild %s: %v", guildID, err)
+		}
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, dashboardMessageSetting); val != "" {
+		session.dashboardChannelID, session.dashboardMessageID = parseDashboardMessage(val)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "ai_style"); val != "" {
+		session.SetAIStyle(val)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "max_duration_minutes"); val != "" {
+		if minutes, err := strconv.Atoi(val); err == nil {
+			session.SetMaxTrackDuration(time.Duration(minutes) * time.Minute)
+		}
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "enforce_voice_channel"); val != "" {
+		session.SetEnforceVoiceMode(val)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "quiet_responses"); val == "true" {
+		session.SetQuietResponses(true)
+	}
+	if val, _ := c.db.GetGuildSetting(guildID, "search_source"); val != "" {
+		session.SetSearchSource(val)
+	}
+	// The last volume anyone set wins over the /settings default
+	volumeSetting, _ := c.db.GetGuildSetting(guildID, lastVolumeSetting)
+	if volumeSetting == "" {
+		volumeSetting, _ = c.db.GetGuildSetting(guildID, "default_volume")
+	}
+	if volume, err := strconv.Atoi(volumeSetting); err == nil {
+		player.SetVolume(volume)
+	}
 
 	session.listenForQueueEvents()
 	session.listenForPlaybackEvents()
@@ -367,7 +582,7 @@ func (p *GuildPlayer) Reset(ctx context.Context, interaction *GuildQueueItemInte
 	// goroutines (e.g. voice recovery retries sleeping on a timer) that
 	// select on playerCtx.Done(). Replace with a fresh context immediately.
 	p.playerCancel()
-	p.playerCtx, p.playerCancel = context.WithCancel(context.Background())
+	p.playerCtx, p.playerCancel = context.WithCancel(gemini.WithGuild(context.Background(), p.GuildID))
 
 	p.radioStartMu.Lock()
 	p.radioStartAnnouncement = nil
@@ -400,6 +615,9 @@ func (p *GuildPlayer) Reset(ctx context.Context, interaction *GuildQueueItemInte
 	if p.Player != nil {
 		p.Player.Stop()
 	}
+	// The playback listener is stopped, so it won't see this stop.
+	p.status.trackEnded(p.GuildID)
+	go p.closeSessionThread()
 	if p.playbackState != nil {
 		p.playbackState.ClearNext()
 	}
@@ -417,6 +635,7 @@ func (p *GuildPlayer) Reset(ctx context.Context, interaction *GuildQueueItemInte
 	}
 	p.VoiceChannelID = nil
 	p.VoiceChannelMutex.Unlock()
+	p.clips.close()
 	p.reconnectAttempts = 0
 
 	// Reset queue and song state under their respective locks.
@@ -425,6 +644,7 @@ func (p *GuildPlayer) Reset(ctx context.Context, interaction *GuildQueueItemInte
 	p.Queue.Listening = false
 	p.Queue.Items = nil
 	p.Queue.Mutex.Unlock()
+	p.discardUndo()
 
 	if p.playbackState != nil {
 		p.playbackState.ClearCurrent()
@@ -495,6 +715,11 @@ func (p *GuildPlayer) popQueue() {
 
 func (p *GuildPlayer) loadNext() {
 	next := p.GetNext()
+	// A live stream can't be preloaded: ffmpeg would sit on an open
+	// connection it isn't allowed to read. playNext starts it on its turn.
+	if next != nil && next.Video.Live {
+		return
+	}
 	if next != nil {
 		log.Tracef("loading next song: %s", next.Video.Title)
 
@@ -520,6 +745,9 @@ func (p *GuildPlayer) loadNext() {
 			VideoID:  next.Video.VideoID,
 			Title:    next.Video.Title,
 			Duration: next.Video.Duration,
+			Start:    next.Video.StartAt,
+			End:      next.Video.EndAt,
+			Live:     next.Video.Live,
 		})
 	}
 }
@@ -539,13 +767,7 @@ func (p *GuildPlayer) playNext() {
 		if next.Stream == nil {
 			log.Debugf("waiting for stream to be ready for %s", next.Video.Title)
 
-			go discord.UpdateMessage(&discord.FollowUpRequest{
-				Token:           next.Interaction.InteractionToken,
-				AppID:           next.Interaction.AppID,
-				UserID:          next.Interaction.UserID,
-				Content:         "loading " + next.Video.Title + "...",
-				GenerateContent: false,
-			})
+			go p.progressToRequester(next, "loading "+next.Video.Title+"...", true)
 
 			if !next.WaitForStreamURL() {
 				select {
@@ -570,10 +792,15 @@ func (p *GuildPlayer) playNext() {
 				VideoID:  next.Video.VideoID,
 				Title:    next.Video.Title,
 				Duration: next.Video.Duration,
+				Start:    next.Video.StartAt,
+				End:      next.Video.EndAt,
+				Live:     next.Video.Live,
 			})
 		} else {
 			// if song has already been loaded, play it
 			log.Tracef("next song is already loaded, playing")
+			next.setState(ItemPlaying)
+			next.LoadResult.VolumeOffset = p.volumeOffsetFor(next)
 			go p.play(ctx, next.LoadResult)
 		}
 	} else {
@@ -585,6 +812,19 @@ func (p *GuildPlayer) playNext() {
 }
 
 func (p *GuildPlayer) play(ctx context.Context, data *audio.LoadResult) {
+	token, ok := p.playback.claim(data)
+	if !ok {
+		// Another play() already owns the voice connection. A duplicate of the
+		// active result is left alone; anything else is dropped so two streams
+		// never interleave on OpusSend.
+		if !p.playback.owns(data) {
+			log.Warnf("playback already active, discarding load result for %s", data.Title)
+			data.Discard()
+		}
+		return
+	}
+	defer p.playback.release(token)
+
 	log.Debugf("playing: %s", data.Title)
 	p.LastActivityAt = time.Now()
 
@@ -593,6 +833,7 @@ func (p *GuildPlayer) play(ctx context.Context, data *audio.LoadResult) {
 	p.VoiceChannelMutex.RUnlock()
 	if vc == nil {
 		log.Warn("No voice connection available for playback")
+		p.unlockStartingItem(data.VideoID)
 		return
 	}
 
@@ -608,6 +849,20 @@ func (p *GuildPlayer) play(ctx context.Context, data *audio.LoadResult) {
 	if err := p.Player.Play(ctx, data, vc); err != nil {
 		sentryhelper.CaptureException(ctx, err)
 		log.Errorf("Error starting stream: %v", err)
+		p.unlockStartingItem(data.VideoID)
+	}
+}
+
+// unlockStartingItem moves an item that never started playing back to
+// loaded so it can be edited again.
+func (p *GuildPlayer) unlockStartingItem(videoID string) {
+	p.Queue.Mutex.Lock()
+	defer p.Queue.Mutex.Unlock()
+	for _, item := range p.Queue.Items {
+		if item.Video.VideoID == videoID && item.locked() {
+			item.setState(ItemLoaded)
+			return
+		}
 	}
 }
 
@@ -635,12 +890,20 @@ func (p *GuildPlayer) handleAdd(event QueueEvent) {
 
 	stream, err := youtube.GetVideoStream(ctx, event.Item.Video)
 	if err != nil {
-		if errors.Is(err, youtube.ErrAgeRestricted) {
+		// The item was removed (Clear/Remove) while yt-dlp was running.
+		if ctx.Err() != nil {
+			log.Debugf("stream lookup canceled for %s", event.Item.Video.Title)
+			if event.Item.streamReady != nil {
+				close(event.Item.streamReady)
+			}
+			return
+		}
+		if youtube.IsRestricted(err) {
 			// Try fallback search results before giving up (search-result path only;
 			// direct URL requests have no fallbacks so FallbackVideos will be nil).
 			for i, fallback := range event.Item.FallbackVideos {
-				log.Infof("Primary video age-restricted, trying fallback %d/%d: %s (%s)",
-					i+1, len(event.Item.FallbackVideos), fallback.Title, fallback.VideoID)
+				log.Infof("Primary video restricted (%v), trying fallback %d/%d: %s (%s)",
+					err, i+1, len(event.Item.FallbackVideos), fallback.Title, fallback.VideoID)
 				fallbackStream, fallbackErr := youtube.GetVideoStream(ctx, fallback)
 				if fallbackErr == nil {
 					// Fallback worked — swap in the new video silently and continue
@@ -649,24 +912,23 @@ func (p *GuildPlayer) handleAdd(event QueueEvent) {
 					stream = fallbackStream
 					goto streamReady
 				}
-				if !errors.Is(fallbackErr, youtube.ErrAgeRestricted) {
-					// Non-age-gate failure on a fallback — stop trying, fall through to error message
-					log.Warnf("Fallback %d failed with non-age-gate error: %s", i+1, fallbackErr)
+				if !youtube.IsRestricted(fallbackErr) {
+					// Non-restriction failure on a fallback — stop trying, fall through to error message
+					log.Warnf("Fallback %d failed with non-restriction error: %s", i+1, fallbackErr)
 					break
 				}
-				log.Warnf("Fallback %d also age-restricted: %s", i+1, fallback.Title)
+				log.Warnf("Fallback %d also restricted: %s", i+1, fallback.Title)
 			}
 
-			// All options exhausted — send a user-friendly Gemini message
-			directRequest := len(event.Item.FallbackVideos) == 0
-			msg := gemini.GenerateAgeRestrictedResponse(ctx, directRequest)
-			go discord.UpdateMessage(&discord.FollowUpRequest{
-				Token:   event.Item.Interaction.InteractionToken,
-				AppID:   event.Item.Interaction.AppID,
-				UserID:  event.Item.Interaction.UserID,
-				Content: msg,
-				Flags:   64,
-			})
+			// All options exhausted — tell the user why, not just that it failed
+			var msg string
+			if errors.Is(err, youtube.ErrRegionLocked) {
+				msg = fmt.Sprintf("❌ Can't play **%s**: the uploader has blocked it in the country I'm running from.", event.Item.Video.Title)
+			} else {
+				directRequest := len(event.Item.FallbackVideos) == 0
+				msg = gemini.GenerateAgeRestrictedResponse(ctx, directRequest)
+			}
+			go p.reportToRequester(event.Item, msg, true)
 			if event.Item.streamReady != nil {
 				close(event.Item.streamReady)
 			}
@@ -675,13 +937,7 @@ func (p *GuildPlayer) handleAdd(event QueueEvent) {
 
 		log.Errorf("Error getting video stream: %s", err)
 		sentryhelper.CaptureException(ctx, err)
-		go discord.UpdateMessage(&discord.FollowUpRequest{
-			Token:   event.Item.Interaction.InteractionToken,
-			AppID:   event.Item.Interaction.AppID,
-			UserID:  event.Item.Interaction.UserID,
-			Content: fmt.Sprintf("❌ Can't play **%s**: %s", event.Item.Video.Title, err.Error()),
-			Flags:   64,
-		})
+		go p.reportToRequester(event.Item, fmt.Sprintf("❌ Can't play **%s**: %s", event.Item.Video.Title, err.Error()), true)
 		if event.Item.streamReady != nil {
 			close(event.Item.streamReady)
 		}
@@ -690,10 +946,14 @@ func (p *GuildPlayer) handleAdd(event QueueEvent) {
 streamReady:
 	log.Tracef("got stream for %s", event.Item.Video.Title)
 	event.Item.Stream = stream
+	event.Item.setState(ItemResolved)
 	// Signal any goroutine waiting in WaitForStreamURL.
 	if event.Item.streamReady != nil {
 		close(event.Item.streamReady)
 	}
+	if event.Item.announceAdd {
+		p.announceQueued(event.Item)
+	}
 
 	p.VoiceChannelMutex.RLock()
 	vc := p.VoiceConnection
@@ -721,6 +981,9 @@ streamReady:
 			VideoID:  next.Video.VideoID,
 			Title:    next.Video.Title,
 			Duration: next.Video.Duration,
+			Start:    next.Video.StartAt,
+			End:      next.Video.EndAt,
+			Live:     next.Video.Live,
 		})
 		return
 	}
@@ -742,9 +1005,6 @@ streamReady:
 }
 
 func (p *GuildPlayer) JoinVoiceChannel(userID string) error {
-	p.VoiceChannelMutex.Lock()
-	defer p.VoiceChannelMutex.Unlock()
-
 	voiceState, err := discord.GetMemberVoiceState(&userID, &p.GuildID)
 	if err != nil {
 		sentry.CaptureException(err)
@@ -756,9 +1016,21 @@ func (p *GuildPlayer) JoinVoiceChannel(userID string) error {
 		return errors.New("voice state not found")
 	}
 
-	vc, err := discord.JoinVoiceChannel(p.Discord, p.GuildID, voiceState.ChannelID)
+	return p.JoinChannel(voiceState.ChannelID)
+}
+
+// JoinChannel joins a voice channel by ID, for callers with no user to
+// follow (e.g. scheduled plays).
+func (p *GuildPlayer) JoinChannel(channelID string) error {
+	p.VoiceChannelMutex.Lock()
+	defer p.VoiceChannelMutex.Unlock()
+
+	vc, err := discord.JoinVoiceChannel(p.Discord, p.GuildID, channelID, !p.GetClipsEnabled())
 	if err != nil {
-		sentry.CaptureException(err)
+		// Missing permissions and full channels are the server's to fix.
+		if !discord.IsVoiceAccessError(err) {
+			sentry.CaptureException(err)
+		}
 		log.Errorf("Error joining voice channel: %s", err)
 		return err
 	}
@@ -766,32 +1038,69 @@ func (p *GuildPlayer) JoinVoiceChannel(userID string) error {
 	now := time.Now()
 
 	p.VoiceConnection = vc
-	p.VoiceChannelID = &voiceState.ChannelID
+	p.VoiceChannelID = &channelID
 	p.VoiceJoinedAt = &now
+	p.listenForClips(vc)
+	p.LastActivityAt = now
 	p.reconnectAttempts = 0
-	p.maxReconnectAttempts = 3
+	p.maxReconnectAttempts = maxVoiceReconnectAttempts
 
 	// Start monitoring voice connection health
 	p.startVoiceConnectionMonitor()
 
+	go p.takeStage(channelID)
+
 	// Add breadcrumb for voice channel join (uses global scope since this is a guild-level operation)
 	sentry.AddBreadcrumb(&sentry.Breadcrumb{
 		Category: "voice",
-		Message:  "Joined voice channel: " + p.getChannelName(voiceState.ChannelID),
+		Message:  "Joined voice channel: " + p.getChannelName(channelID),
 		Level:    sentry.LevelInfo,
 		Data: map[string]interface{}{
-			"channel_id":   voiceState.ChannelID,
-			"channel_name": p.getChannelName(voiceState.ChannelID),
+			"channel_id":   channelID,
+			"channel_name": p.getChannelName(channelID),
 			"guild_id":     p.GuildID,
 			"guild_name":   p.getGuildName(),
 		},
 	})
 
-	log.Tracef("joined voice channel: %s", voiceState.ChannelID)
+	log.Tracef("joined voice channel: %s", channelID)
 
 	return nil
 }
 
+// Disconnect stops playback, leaves the voice channel and clears the queue.
+// Used by /disconnect, the idle checker and leaving an empty channel.
+func (p *GuildPlayer) Disconnect() {
+	if p.Player != nil {
+		p.Player.Stop()
+	}
+
+	// Player.Stop() does not emit PlaybackStopped, so the
+	// now-playing ticker won't self-terminate. Stop it explicitly.
+	p.stopNowPlayingUpdates()
+	p.clearNowPlayingCard()
+
+	// Stop voice monitoring before cleanup
+	p.stopVoiceConnectionMonitor()
+
+	p.VoiceChannelMutex.Lock()
+	if p.VoiceConnection != nil {
+		if err := p.VoiceConnection.Disconnect(); err != nil {
+			log.Errorf("Error disconnecting from voice: %v", err)
+		}
+		p.VoiceConnection = nil
+	}
+	p.VoiceChannelID = nil
+	p.VoiceChannelMutex.Unlock()
+	p.clips.close()
+	p.stopListenAlong()
+
+	p.stopEmptyTimer()
+	p.Clear()
+	p.discardUndo()
+	go p.closeSessionThread()
+}
+
 // speakOnVC safely calls Speaking() on the current voice connection under RLock.
 // Safe to call from any goroutine; no-ops if the connection is nil.
 func (p *GuildPlayer) speakOnVC(speaking bool) {
@@ -925,6 +1234,7 @@ func (p *GuildPlayer) listenForQueueEvents() {
 					return
 				}
 				log.Tracef("Queue event: %s", event.Type)
+				p.RefreshDashboard()
 				switch event.Type {
 				case EventAdd:
 					// Dispatch to a worker goroutine so yt-dlp (~1-2s) doesn't block
@@ -943,7 +1253,9 @@ func (p *GuildPlayer) listenForQueueEvents() {
 							"guild_name": p.getGuildName(),
 						},
 					})
+					p.recordPlayEnd(p.GetCurrentItem(), p.Player.GetPosition(), true)
 					p.Player.Stop()
+					p.playback.handOff("")
 					p.playNext()
 					// If radio is on and the skip drained the queue, auto-fill like a natural song end would
 					if p.IsRadioEnabled() && p.IsEmpty() && p.SongHistory.Len() > 0 {
@@ -987,11 +1299,14 @@ func (p *GuildPlayer) listenForLoadEvents() {
 							if ctx == nil {
 								ctx = context.Background()
 							}
+							queueItem.setState(ItemPlaying)
+							event.LoadResult.VolumeOffset = p.volumeOffsetFor(queueItem)
 							go p.play(ctx, event.LoadResult)
 						} else {
 							log.Tracef("loaded song ready for index %d, setting load result", queueIndex)
 							queueItem.LoadResult = event.LoadResult
 							queueItem.ProbedDuration = event.LoadResult.Duration
+							queueItem.setState(ItemLoaded)
 						}
 					}
 				case audio.PlaybackLoadCanceled:
@@ -1031,13 +1346,7 @@ func (p *GuildPlayer) listenForLoadEvents() {
 									strconv.Itoa(queueItem.MaxAttempts) + " failed attempts"
 							}
 
-							go discord.SendFollowup(&discord.FollowUpRequest{
-								Token:           queueItem.Interaction.InteractionToken,
-								AppID:           queueItem.Interaction.AppID,
-								UserID:          queueItem.Interaction.UserID,
-								Content:         msg,
-								GenerateContent: false,
-							})
+							go p.reportToRequester(queueItem, msg, false)
 
 							log.Infof("Removed %s from queue after %d failed load attempts",
 								queueItem.Video.Title, queueItem.MaxAttempts)
@@ -1045,17 +1354,12 @@ func (p *GuildPlayer) listenForLoadEvents() {
 							// Check if this is a 403 error - refresh stream URL before retry
 							if strings.Contains(errStr, "403 Forbidden") {
 								log.Infof("Got 403, refreshing stream URL for %s", queueItem.Video.Title)
+								youtube.ForgetStream(queueItem.Video)
 
 								// Notify user we're reloading the track (with attempt count for consistency)
-								discord.SendFollowup(&discord.FollowUpRequest{
-									Token:  queueItem.Interaction.InteractionToken,
-									AppID:  queueItem.Interaction.AppID,
-									UserID: queueItem.Interaction.UserID,
-									Content: "🔄 YouTube rejected the stream, reloading **" + queueItem.Video.Title +
-										"** (attempt " + strconv.Itoa(queueItem.LoadAttempts) + "/" +
-										strconv.Itoa(queueItem.MaxAttempts) + ")...",
-									GenerateContent: false,
-								})
+								p.progressToRequester(queueItem, "🔄 YouTube rejected the stream, reloading **"+queueItem.Video.Title+
+									"** (attempt "+strconv.Itoa(queueItem.LoadAttempts)+"/"+
+									strconv.Itoa(queueItem.MaxAttempts)+")...", false)
 
 								retryCtx := queueItem.Context
 								if retryCtx == nil {
@@ -1064,25 +1368,14 @@ func (p *GuildPlayer) listenForLoadEvents() {
 								newStream, streamErr := youtube.GetVideoStream(retryCtx, queueItem.Video)
 								if streamErr == nil {
 									queueItem.Stream = newStream
+									queueItem.setState(ItemResolved)
 									log.Infof("Successfully refreshed stream URL for %s", queueItem.Video.Title)
 
-									go discord.SendFollowup(&discord.FollowUpRequest{
-										Token:           queueItem.Interaction.InteractionToken,
-										AppID:           queueItem.Interaction.AppID,
-										UserID:          queueItem.Interaction.UserID,
-										Content:         "✅ Stream reloaded successfully, retrying...",
-										GenerateContent: false,
-									})
+									go p.progressToRequester(queueItem, "✅ Stream reloaded successfully, retrying...", false)
 								} else {
 									log.Warnf("Failed to refresh stream URL: %v", streamErr)
 
-									go discord.SendFollowup(&discord.FollowUpRequest{
-										Token:           queueItem.Interaction.InteractionToken,
-										AppID:           queueItem.Interaction.AppID,
-										UserID:          queueItem.Interaction.UserID,
-										Content:         "⚠️ Could not reload stream, will retry with original URL",
-										GenerateContent: false,
-									})
+									go p.progressToRequester(queueItem, "⚠️ Could not reload stream, will retry with original URL", false)
 								}
 							} else {
 								// Retry - notify user we're retrying (non-403 errors)
@@ -1101,13 +1394,7 @@ func (p *GuildPlayer) listenForLoadEvents() {
 										"), retrying..."
 								}
 
-								go discord.SendFollowup(&discord.FollowUpRequest{
-									Token:           queueItem.Interaction.InteractionToken,
-									AppID:           queueItem.Interaction.AppID,
-									UserID:          queueItem.Interaction.UserID,
-									Content:         msg,
-									GenerateContent: false,
-								})
+								go p.progressToRequester(queueItem, msg, false)
 							}
 
 							log.Infof("Retrying load for %s (attempt %d/%d)",
@@ -1166,6 +1453,7 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 							}
 						}()
 					}
+					p.RefreshDashboard()
 				case audio.PlaybackResumed:
 					sentry.AddBreadcrumb(&sentry.Breadcrumb{
 						Category: "playback",
@@ -1186,6 +1474,7 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 							}
 						}()
 					}
+					p.RefreshDashboard()
 				case audio.PlaybackStopped:
 					sentry.AddBreadcrumb(&sentry.Breadcrumb{
 						Category: "playback",
@@ -1196,6 +1485,10 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 							"guild_name": p.getGuildName(),
 						},
 					})
+					if videoID != nil {
+						p.playback.handOff(*videoID)
+					}
+					p.recordPlayEnd(p.GetCurrentItem(), p.Player.GetPosition(), false)
 					// Clear stale TTS and current/next state. The TTS watcher
 					// will regenerate when PlaybackStarted fires again.
 					p.playbackState.ClearNext()
@@ -1205,6 +1498,15 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 					// Stop now-playing updates
 					p.stopNowPlayingUpdates()
 					p.clearNowPlayingCard()
+					p.RefreshDashboard()
+					p.status.trackEnded(p.GuildID)
+
+					// Play() only stops on its own when OpusSend closed under
+					// it. If we're still meant to be in a channel, Discord
+					// dropped the connection (usually a voice region move).
+					if p.GetVoiceChannelID() != nil && p.GetCurrentItem() != nil {
+						go p.startVoiceRecovery()
+					}
 				case audio.PlaybackCompleted:
 					sentry.AddBreadcrumb(&sentry.Breadcrumb{
 						Category: "playback",
@@ -1216,12 +1518,24 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 							"video_id":   videoID,
 						},
 					})
+					if videoID != nil {
+						p.playback.handOff(*videoID)
+					}
+					if item := p.GetCurrentItem(); item != nil {
+						listened := item.Duration()
+						if listened == 0 {
+							listened = p.Player.GetPosition()
+						}
+						p.recordPlayEnd(item, listened, false)
+					}
 					p.playbackState.ClearCurrent()
 					p.speakOnVC(false)
 
 					// Stop now-playing updates
 					p.stopNowPlayingUpdates()
 					p.clearNowPlayingCard()
+					p.RefreshDashboard()
+					p.status.trackEnded(p.GuildID)
 
 					// Loop current song if enabled
 					p.currentItemMutex.RLock()
@@ -1271,6 +1585,7 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 				case audio.PlaybackStarted:
 					if queueItem != nil {
 						log.Tracef("playback started for %s", queueItem.Video.Title)
+						queueItem.setState(ItemPlaying)
 						p.playbackState.SetCurrent(SongInfo{
 							Title:       queueItem.Video.Title,
 							VideoID:     queueItem.Video.VideoID,
@@ -1293,8 +1608,14 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 							},
 						})
 
-						// Send now-playing card
-						go p.sendNowPlayingCard(queueItem)
+						// Send now-playing card, into a new session thread if
+						// this song starts the session
+						go func(item *GuildQueueItem) {
+							p.openSessionThread(item)
+							p.sendNowPlayingCard(item)
+						}(queueItem)
+						p.RefreshDashboard()
+						p.status.trackStarted(p.GuildID, queueItem.Video.Title)
 
 						// Resolve Deezer metadata (BPM, genre, album art) in the background.
 						// Best-effort: the now-playing card and DJ commentary render fine
@@ -1331,21 +1652,7 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 						})
 
 						// Record play in database
-						if p.DB != nil {
-							userID := ""
-							username := ""
-							if queueItem.Interaction != nil {
-								userID = queueItem.Interaction.UserID
-								// Fetch username from cache or Discord API
-								if userID != "" {
-									username = p.DB.GetOrFetchUsername(p.GuildID, userID)
-								}
-							}
-							url := "https://www.youtube.com/watch?v=" + queueItem.Video.VideoID
-							if err := p.DB.RecordPlay(p.GuildID, queueItem.Video.VideoID, queueItem.Video.Title, url, userID, username, 0); err != nil {
-								log.Errorf("Failed to record play in database: %v", err)
-							}
-						}
+						p.recordPlayStart(queueItem)
 					}
 					p.speakOnVC(true)
 					// once a song starts playback, we can pop it from the queue
@@ -1356,6 +1663,7 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 					p.syncNextFromQueue()
 					// if there are more songs in the queue, load the next one
 					p.loadNext()
+					go p.notifyUpNext(queueItem)
 
 					// Pre-queue the next radio song NOW (not at PlaybackCompleted)
 					// so it loads during playback and TTS has time to generate.
@@ -1363,6 +1671,10 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 						go p.tryQueueRadioSong()
 					}
 				case audio.PlaybackError:
+					if videoID != nil {
+						p.playback.handOff(*videoID)
+					}
+					p.recordPlayEnd(p.GetCurrentItem(), p.Player.GetPosition(), false)
 					p.playbackState.ClearCurrent()
 					p.currentItemMutex.Lock()
 					p.CurrentItem = nil
@@ -1389,12 +1701,7 @@ func (p *GuildPlayer) listenForPlaybackEvents() {
 							msg = "Something went wrong while playing " + queueItem.Video.Title
 						}
 
-						go discord.UpdateMessage(&discord.FollowUpRequest{
-							Token:   queueItem.Interaction.InteractionToken,
-							AppID:   queueItem.Interaction.AppID,
-							UserID:  queueItem.Interaction.UserID,
-							Content: msg,
-						})
+						go p.reportToRequester(queueItem, msg, true)
 					}
 
 					p.playNext()
@@ -1420,17 +1727,22 @@ func (p *GuildPlayer) startIdleChecker() {
 			select {
 			case <-ticker.C:
 				idleDuration := time.Since(p.LastActivityAt)
-				if idleDuration >= idleTimeout {
+				// Nothing to do if /disconnect already left the channel.
+				if idleDuration >= idleTimeout && p.GetVoiceChannelID() != nil {
 					log.Infof("Guild %s has been idle for %v, disconnecting", p.GuildID, idleDuration)
+					saved := p.saveBeforeLeave()
 
-					if textCh := p.GetLastTextChannelID(); textCh != "" {
+					if textCh := p.textChannelID(); textCh != "" {
 						prompt := fmt.Sprintf("The bot has been idle in the voice channel for %d minutes with no activity, so it's disconnecting now", config.Config.Options.IdleTimeoutMinutes)
 						// Use background context since this is from the idle checker goroutine
-						message := gemini.GenerateResponse(context.Background(), prompt)
+						message := gemini.GenerateResponse(gemini.WithGuild(context.Background(), p.GuildID), prompt)
 
 						if message == "" {
 							message = fmt.Sprintf("Been sitting here idle for %d minutes with nothing to do. I'm out - let me know when you actually want to hear something.", config.Config.Options.IdleTimeoutMinutes)
 						}
+						if saved > 0 {
+							message += savedQueueNote(saved)
+						}
 
 						_, err := p.Discord.ChannelMessageSend(textCh, message)
 						if err != nil {
@@ -1438,27 +1750,7 @@ func (p *GuildPlayer) startIdleChecker() {
 						}
 					}
 
-					if p.Player != nil {
-						p.Player.Stop()
-					}
-
-					// Player.Stop() does not emit PlaybackStopped, so the
-					// now-playing ticker won't self-terminate. Stop it explicitly.
-					p.stopNowPlayingUpdates()
-					p.clearNowPlayingCard()
-
-					// Stop voice monitoring before cleanup
-					p.stopVoiceConnectionMonitor()
-
-					if p.VoiceConnection != nil {
-						if err := p.VoiceConnection.Disconnect(); err != nil {
-							log.Errorf("Error disconnecting from voice: %v", err)
-						}
-						p.VoiceConnection = nil
-					}
-
-					p.VoiceChannelID = nil
-					p.Clear()
+					p.Disconnect()
 					return
 				}
 			case <-p.idleCheckStop:
@@ -1692,7 +1984,7 @@ func (p *GuildPlayer) startRadioMode() {
 
 	// 2. Generate TTS announcement BEFORE queuing, so it's ready when play() starts.
 	if p.GetAnnounceEnabled() && config.Config.Gemini.Enabled && tts.Get() != nil {
-		ctx := context.Background()
+		ctx := gemini.WithGuild(context.Background(), p.GuildID)
 		scriptCtx, scriptCancel := context.WithTimeout(ctx, 10*time.Second)
 		defer scriptCancel()
 		script := gemini.GenerateDJScript(scriptCtx, gemini.DJScriptContext{
@@ -1869,7 +2161,7 @@ func (p *GuildPlayer) pickRadioSong() *youtube.VideoResponse {
 		return nil
 	}
 
-	ctx := context.Background()
+	ctx := gemini.WithGuild(context.Background(), p.GuildID)
 
 	sentry.AddBreadcrumb(&sentry.Breadcrumb{
 		Category: "radio",
@@ -1905,6 +2197,14 @@ func (p *GuildPlayer) pickRadioSong() *youtube.VideoResponse {
 		} else {
 			logger.WithError(err).Warn("failed to fetch blocked video IDs for radio dedup")
 		}
+		// Songs this guild nearly always skips are left out the same way.
+		if skipped, err := p.DB.GetUsuallySkippedVideoIDs(p.GuildID); err == nil {
+			for id := range skipped {
+				historyIDs[id] = true
+			}
+		} else {
+			logger.WithError(err).Warn("failed to fetch skipped video IDs for radio dedup")
+		}
 	}
 
 	// Genre radio mode
@@ -2270,7 +2570,7 @@ func (p *GuildPlayer) enqueueRadioPick(picked *youtube.VideoResponse) {
 		},
 	})
 
-	if textCh := p.GetLastTextChannelID(); textCh != "" && p.Discord != nil {
+	if textCh := p.textChannelID(); textCh != "" && p.Discord != nil {
 		msg := "📻 **Radio:** queued **" + picked.Title + "**"
 		if _, err := p.Discord.ChannelMessageSend(textCh, msg); err != nil {
 			log.Errorf("Failed to send radio announcement: %v", err)
@@ -2291,18 +2591,131 @@ func (p *GuildPlayer) queueRadioSong() string {
 	return picked.Title
 }
 
+// insertPosition returns where a new item belongs in the queue. Caller must
+// hold the queue mutex.
+//
+//   - DJ picks go ahead of everything except earlier DJ picks (FIFO among DJs)
+//   - regular user songs go before the first radio pick, or in fair mode,
+//     after the last song from the same round of requests
+//   - radio picks always append to the end
+func (q *GuildQueue) insertPosition(item *GuildQueueItem) int {
+	switch {
+	case item.IsRadioPick:
+		return len(q.Items)
+	case item.IsDJPick:
+		for i, qitem := range q.Items {
+			if !qitem.IsDJPick {
+				return i
+			}
+		}
+	case q.Mode == QueueModeFair:
+		return q.fairInsertPosition(item)
+	default:
+		for i, qitem := range q.Items {
+			if qitem.IsRadioPick {
+				return i
+			}
+		}
+	}
+	return len(q.Items)
+}
+
+// requesterID returns the user who queued the item, or "" for radio picks.
+func (item *GuildQueueItem) requesterID() string {
+	if item.Interaction == nil {
+		return ""
+	}
+	return item.Interaction.UserID
+}
+
+// fairInsertPosition places a user request round-robin: if the requester
+// already has n songs waiting, the new one goes at the end of round n+1,
+// i.e. after every other requester's (n+1)th song. Caller must hold the
+// queue mutex.
+func (q *GuildQueue) fairInsertPosition(item *GuildQueueItem) int {
+	userID := item.requesterID()
+	round := 0
+	for _, qitem := range q.Items {
+		if !qitem.IsRadioPick && !qitem.IsDJPick && qitem.requesterID() == userID {
+			round++
+		}
+	}
+
+	seen := make(map[string]int)
+	for i, qitem := range q.Items {
+		if qitem.IsRadioPick {
+			return i
+		}
+		if qitem.IsDJPick {
+			continue
+		}
+		uid := qitem.requesterID()
+		if seen[uid] > round {
+			return i
+		}
+		seen[uid]++
+	}
+	return len(q.Items)
+}
+
+// rebalanceFair reorders existing user requests round-robin by requester,
+// keeping DJ picks at the front and radio picks at the back. Used when a
+// guild switches to fair mode with a queue already built up FIFO. Caller must
+// hold the queue mutex.
+func (q *GuildQueue) rebalanceFair() {
+	pinned := q.pinned()
+	var djPicks, userPicks, radioPicks []*GuildQueueItem
+	for _, item := range q.Items[pinned:] {
+		switch {
+		case item.IsDJPick:
+			djPicks = append(djPicks, item)
+		case item.IsRadioPick:
+			radioPicks = append(radioPicks, item)
+		default:
+			userPicks = append(userPicks, item)
+		}
+	}
+
+	// Stable sort by per-requester round keeps request order within a round.
+	rounds := make(map[*GuildQueueItem]int, len(userPicks))
+	seen := make(map[string]int)
+	for _, item := range userPicks {
+		uid := item.requesterID()
+		rounds[item] = seen[uid]
+		seen[uid]++
+	}
+	sort.SliceStable(userPicks, func(i, j int) bool {
+		return rounds[userPicks[i]] < rounds[userPicks[j]]
+	})
+
+	items := make([]*GuildQueueItem, 0, len(q.Items))
+	items = append(items, q.Items[:pinned]...)
+	items = append(items, djPicks...)
+	items = append(items, userPicks...)
+	items = append(items, radioPicks...)
+	q.Items = items
+}
+
 func (p *GuildPlayer) Add(ctx context.Context, video youtube.VideoResponse, userID string, interactionToken string, appID string, fallbackVideos []youtube.VideoResponse, isRadioPick ...bool) {
 	p.Queue.Mutex.Lock()
 	defer p.Queue.Mutex.Unlock()
 
 	p.LastActivityAt = time.Now()
 
+	// Titles are cleaned once here and stored on the item; RawTitle keeps
+	// the upstream version.
+	video.Normalize(p.GetStripTitleEmoji())
+
 	// Check if this is a radio pick (optional parameter)
 	radioPick := false
 	if len(isRadioPick) > 0 {
 		radioPick = isRadioPick[0]
 	}
 
+	// Detach from original transaction since load/playback happens later.
+	// The hub is preserved for breadcrumb isolation.
+	itemCtx, cancelLoad := context.WithCancel(sentryhelper.DetachFromTransaction(ctx))
+
 	item := &GuildQueueItem{
 		Video:       video,
 		AddedAt:     time.Now(),
@@ -2311,28 +2724,26 @@ func (p *GuildPlayer) Add(ctx context.Context, video youtube.VideoResponse, user
 			UserID:           userID,
 			InteractionToken: interactionToken,
 			AppID:            appID,
+			IssuedAt:         time.Now(),
 		},
 		LoadAttempts:   0,
 		MaxAttempts:    3, // Circuit breaker: max 3 attempts per item
 		FallbackVideos: fallbackVideos,
-		// Detach from original transaction since load/playback happens later.
-		// The hub is preserved for breadcrumb isolation.
-		Context:     sentryhelper.DetachFromTransaction(ctx),
-		IsRadioPick: radioPick,
+		Context:        itemCtx,
+		cancelLoad:     cancelLoad,
+		IsRadioPick:    radioPick,
+		IsDJPick:       !radioPick && p.IsDJ(userID),
+	}
+	// Requests from the announce channel itself already get a followup there.
+	if announceCh := p.announceChannelID(); announceCh != "" && !radioPick {
+		item.announceAdd = announceCh != p.GetLastTextChannelID()
 	}
 
-	// Priority insertion: user-queued songs go before radio-queued songs
-	insertIdx := len(p.Queue.Items)
-	if !radioPick {
-		// User song: insert before first radio pick (if any)
-		for i, qitem := range p.Queue.Items {
-			if qitem.IsRadioPick {
-				insertIdx = i
-				break
-			}
-		}
+	// Priority insertion: DJ songs > user songs > radio songs
+	insertIdx := p.Queue.insertPosition(item)
+	if pinned := p.Queue.pinned(); insertIdx < pinned {
+		insertIdx = pinned
 	}
-	// Radio songs always append to end (insertIdx = len already)
 
 	// Insert at calculated position using slice idiom
 	if insertIdx == len(p.Queue.Items) {
@@ -2343,6 +2754,10 @@ func (p *GuildPlayer) Add(ctx context.Context, video youtube.VideoResponse, user
 		p.Queue.Items = append(p.Queue.Items[:insertIdx], append([]*GuildQueueItem{item}, p.Queue.Items[insertIdx:]...)...)
 	}
 
+	if p.GetTranslateTitles() && gemini.NeedsTranslation(video.Title) {
+		go p.resolveTitleTranslation(item)
+	}
+
 	select {
 	case p.Queue.notifications <- QueueEvent{
 		Type: EventAdd,
@@ -2370,14 +2785,21 @@ func (p *GuildPlayer) Remove(index int) string {
 	}
 
 	removed := p.Queue.Items[index-1]
-	// Clear LoadResult before removing to allow GC to reclaim memory
-	if removed != nil && removed.LoadResult != nil {
-		removed.LoadResult = nil
+	if removed.locked() {
+		// The player is starting it; /skip once it's playing instead.
+		p.Queue.Mutex.Unlock()
+		return ""
 	}
+	removed.releaseLoad()
 	copy(p.Queue.Items[index-1:], p.Queue.Items[index:])
 	p.Queue.Items[len(p.Queue.Items)-1] = nil // Clear trailing reference
 	p.Queue.Items = p.Queue.Items[:len(p.Queue.Items)-1]
 
+	// The loader only ever preloads the head of the queue.
+	if index == 1 && p.Loader != nil {
+		p.Loader.Cancel()
+	}
+
 	// Snapshot next info before releasing queue lock so PlaybackState
 	// is updated without nesting two mutexes.
 	var nextTitle, nextVideoID, nextChannelName, nextUserID string
@@ -2422,6 +2844,90 @@ func (p *GuildPlayer) Remove(index int) string {
 	return removed.Video.Title
 }
 
+// RemoveRange drops queue items from..to (1-based, inclusive), keeping a
+// snapshot so /undo can bring them back. Returns how many were removed, or
+// 0 when the range is out of bounds.
+func (p *GuildPlayer) RemoveRange(from, to int) int {
+	p.Queue.Mutex.Lock()
+	if from <= p.Queue.pinned() || to < from || to > len(p.Queue.Items) {
+		p.Queue.Mutex.Unlock()
+		return 0
+	}
+
+	removed := p.Queue.Items[from-1 : to]
+	p.snapshotQueue(UndoRemove, removed)
+	for _, item := range removed {
+		item.releaseLoad()
+	}
+
+	items := make([]*GuildQueueItem, 0, len(p.Queue.Items)-len(removed))
+	items = append(items, p.Queue.Items[:from-1]...)
+	items = append(items, p.Queue.Items[to:]...)
+	n := len(removed)
+	p.Queue.Items = items
+	p.Queue.Mutex.Unlock()
+
+	p.LastActivityAt = time.Now()
+
+	// The loader only ever preloads the head of the queue.
+	if from == 1 && p.Loader != nil {
+		p.Loader.Cancel()
+	}
+
+	if p.playbackState != nil {
+		p.syncNextFromQueue()
+	}
+	return n
+}
+
+// Jump makes queue item n (1-based) the next song and skips to it. Items
+// 1..n-1 are discarded, or moved to the end of the queue when moveToEnd is
+// set. Any in-flight preload is canceled since it belongs to the old head.
+// Returns the title jumped to and how many items were passed over, or ""
+// when n is out of range.
+func (p *GuildPlayer) Jump(n int, moveToEnd bool) (string, int) {
+	p.Queue.Mutex.Lock()
+	if n < 1 || n > len(p.Queue.Items) {
+		p.Queue.Mutex.Unlock()
+		return "", 0
+	}
+
+	// A song the player is already starting can't be jumped over.
+	if n > 1 && p.Queue.pinned() > 0 {
+		p.Queue.Mutex.Unlock()
+		return "", 0
+	}
+
+	target := p.Queue.Items[n-1]
+	passed := make([]*GuildQueueItem, n-1)
+	copy(passed, p.Queue.Items[:n-1])
+
+	items := make([]*GuildQueueItem, 0, len(p.Queue.Items))
+	items = append(items, p.Queue.Items[n-1:]...)
+	for _, item := range passed {
+		if moveToEnd {
+			// Keep the stream URL but drop buffered audio; it'll reload later.
+			item.dropLoadResult()
+			items = append(items, item)
+		} else {
+			item.releaseLoad()
+		}
+	}
+	p.Queue.Items = items
+	p.Queue.Mutex.Unlock()
+
+	p.LastActivityAt = time.Now()
+
+	if len(passed) > 0 && p.Loader != nil {
+		p.Loader.Cancel()
+	}
+
+	// Skip stops the current song (if any) and plays the new head.
+	p.Skip()
+
+	return target.Video.Title, len(passed)
+}
+
 func (p *GuildPlayer) Skip() {
 	p.LastActivityAt = time.Now()
 
@@ -2434,9 +2940,21 @@ func (p *GuildPlayer) Skip() {
 	}
 }
 
-func (p *GuildPlayer) Clear() {
+// Clear drops every queued song while the current one keeps playing (it's no
+// longer in the queue), as does a song the player is already starting.
+// Stream lookups and preloads for the dropped songs are canceled and their
+// buffered audio released. Returns how many were removed.
+func (p *GuildPlayer) Clear() int {
 	p.Queue.Mutex.Lock()
-	p.Queue.Items = []*GuildQueueItem{}
+	pinned := p.Queue.pinned()
+	removed := p.Queue.Items[pinned:]
+	if len(removed) > 0 {
+		p.snapshotQueue(UndoClear, removed)
+	}
+	p.Queue.Items = append([]*GuildQueueItem{}, p.Queue.Items[:pinned]...)
+	for _, item := range removed {
+		item.releaseLoad()
+	}
 	select {
 	case p.Queue.notifications <- QueueEvent{Type: EventClear}:
 	default:
@@ -2446,9 +2964,18 @@ func (p *GuildPlayer) Clear() {
 	}
 	p.Queue.Mutex.Unlock()
 
+	// Every load is for a queued item, so anything in flight now belongs to
+	// a dropped song.
+	if len(removed) > 0 && p.Loader != nil {
+		p.Loader.Cancel()
+	}
+
 	// Update PlaybackState outside the queue lock.
 	// ClearNext triggers SignalRegen automatically.
-	p.playbackState.ClearNext()
+	if p.playbackState != nil {
+		p.playbackState.ClearNext()
+	}
+	return len(removed)
 }
 
 func (p *GuildPlayer) IsEmpty() bool {
@@ -2465,9 +2992,13 @@ func (p *GuildPlayer) Shuffle() int {
 		return len(p.Queue.Items)
 	}
 
-	// Shuffle only affects queued songs; currently playing song (if any) is not in queue
-	rand.Shuffle(len(p.Queue.Items), func(i, j int) {
-		p.Queue.Items[i], p.Queue.Items[j] = p.Queue.Items[j], p.Queue.Items[i]
+	p.snapshotQueue(UndoShuffle, nil)
+
+	// Shuffle only affects queued songs; currently playing song (if any) is
+	// not in queue, and one the player is starting stays at the head.
+	shuffled := p.Queue.Items[p.Queue.pinned():]
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 
 	// Snapshot next info before releasing queue lock.
@@ -2518,13 +3049,13 @@ func (p *GuildPlayer) startVoiceConnectionMonitor() {
 					if !vc.Ready {
 						log.Warnf("Voice connection not ready for guild %s", p.GuildID)
 
-						// If we're actively playing (not paused), attempt recovery.
-						// IsPlaying() is true even when paused (silence loop still runs),
-						// so we guard with !IsPaused() to avoid reconnecting when the user
-						// intentionally disconnected us while paused.
-						if p.Player.IsPlaying() && !p.Player.IsPaused() {
-							log.Infof("Attempting voice connection recovery for guild %s", p.GuildID)
-							p.attemptVoiceRecovery()
+						// Recover if a song was interrupted. Play() exits once
+						// OpusSend closes, so IsPlaying() can already be false;
+						// the current item is the reliable signal. A paused
+						// player is left alone, since the user may have
+						// disconnected us on purpose while paused.
+						if p.GetCurrentItem() != nil && !p.Player.IsPaused() {
+							p.startVoiceRecovery()
 						}
 					}
 				}
@@ -2541,7 +3072,59 @@ func (p *GuildPlayer) stopVoiceConnectionMonitor() {
 	}
 }
 
-// attemptVoiceRecovery attempts to recover from a failed voice connection
+// startVoiceRecovery begins reconnecting after the voice connection dropped,
+// unless a recovery is already running.
+func (p *GuildPlayer) startVoiceRecovery() {
+	if !p.recovering.CompareAndSwap(false, true) {
+		return
+	}
+	log.Infof("Attempting voice connection recovery for guild %s", p.GuildID)
+	if p.textChannelID() != "" {
+		go p.sendRecoveryMessage("⚠️ Lost the voice connection — reconnecting…")
+	}
+	p.attemptVoiceRecovery()
+}
+
+// reconnectDelay is how long to wait before retrying after the given failed
+// attempt: 1s, 2s, 4s... capped at maxReconnectDelay.
+func reconnectDelay(attempt int) time.Duration {
+	delay := time.Second << max(attempt-1, 0)
+	if delay <= 0 || delay > maxReconnectDelay {
+		return maxReconnectDelay
+	}
+	return delay
+}
+
+const (
+	maxVoiceReconnectAttempts = 5
+	maxReconnectDelay         = 30 * time.Second
+	// minResumeOffset: a song that barely started just plays again from
+	// the top.
+	minResumeOffset = 5 * time.Second
+	// resumeLeadIn is how far before the end a nearly finished song resumes,
+	// so there's still something to hear.
+	resumeLeadIn = 5 * time.Second
+)
+
+// resumeVideo returns video clipped to pick up after played, how far the
+// interrupted play got. Live streams just rejoin.
+func resumeVideo(video youtube.VideoResponse, played time.Duration) youtube.VideoResponse {
+	if video.Live || played < minResumeOffset {
+		return video
+	}
+	if video.Duration > 0 && played > video.Duration-resumeLeadIn {
+		played = max(video.Duration-resumeLeadIn, 0)
+	}
+	// Clip works from the full length when there's no end mark.
+	if video.EndAt == 0 && video.Duration > 0 {
+		video.Duration += video.StartAt
+	}
+	video.Clip(video.StartAt+played, video.EndAt)
+	return video
+}
+
+// attemptVoiceRecovery attempts to recover from a failed voice connection.
+// Callers go through startVoiceRecovery so only one chain of retries runs.
 func (p *GuildPlayer) attemptVoiceRecovery() {
 	if p.reconnectAttempts >= p.maxReconnectAttempts {
 		log.Errorf("Max voice reconnection attempts reached for guild %s", p.GuildID)
@@ -2560,6 +3143,9 @@ func (p *GuildPlayer) attemptVoiceRecovery() {
 	p.currentItemMutex.RLock()
 	savedItem := p.CurrentItem
 	p.currentItemMutex.RUnlock()
+	// Read before Stop(): the position is only reset when the next song
+	// starts, so it still says where the interrupted one dropped.
+	played := p.Player.LastPosition()
 
 	p.VoiceChannelMutex.RLock()
 	currentChannelID := p.VoiceChannelID
@@ -2587,17 +3173,18 @@ func (p *GuildPlayer) attemptVoiceRecovery() {
 	if currentChannelID != nil {
 		log.Infof("Attempting to rejoin voice channel %s in guild %s", *currentChannelID, p.GuildID)
 
-		vc, err := discord.JoinVoiceChannel(p.Discord, p.GuildID, *currentChannelID)
+		vc, err := discord.JoinVoiceChannel(p.Discord, p.GuildID, *currentChannelID, !p.GetClipsEnabled())
 		if err != nil {
 			log.Errorf("Failed to rejoin voice channel for guild %s: %v", p.GuildID, err)
 
 			// Schedule retry after delay, but honour the player-scoped context so
 			// Reset() can cancel this goroutine before it fires.
-			delay := time.Duration(p.reconnectAttempts*2) * time.Second
+			delay := reconnectDelay(p.reconnectAttempts)
 			go func() {
 				select {
 				case <-p.playerCtx.Done():
 					log.Debugf("Voice recovery retry cancelled for guild %s (player reset)", p.GuildID)
+					p.recovering.Store(false)
 					return
 				case <-time.After(delay):
 				}
@@ -2610,18 +3197,31 @@ func (p *GuildPlayer) attemptVoiceRecovery() {
 		p.VoiceChannelMutex.Lock()
 		p.VoiceConnection = vc
 		p.VoiceChannelMutex.Unlock()
+		p.listenForClips(vc)
 
 		log.Infof("Successfully reconnected to voice channel for guild %s", p.GuildID)
 		p.reconnectAttempts = 0
+		go p.takeStage(*currentChannelID)
+		defer p.recovering.Store(false)
 
 		// Re-queue the interrupted song at the front of the queue for a
 		// fresh load. We never replay savedItem.LoadResult — it's a one-shot
 		// pipe that Play() partially consumed; trying to reuse it hits EOF.
 		// Prepending a fresh item and calling playNext() is safe and clean.
+		message := "🔄 Voice connection restored! Playback resumed."
 		if savedItem != nil {
+			video := resumeVideo(savedItem.Video, played)
+			if video.StartAt > savedItem.Video.StartAt {
+				message = fmt.Sprintf("🔄 Voice connection restored! Picking **%s** back up at %s.",
+					video.Title, discord.FormatDuration(video.StartAt))
+			}
+			probed := savedItem.ProbedDuration
+			if video.StartAt != savedItem.Video.StartAt {
+				probed = 0 // the resumed part is probed again when it loads
+			}
 			freshItem := &GuildQueueItem{
-				Video:          savedItem.Video,
-				ProbedDuration: savedItem.ProbedDuration,
+				Video:          video,
+				ProbedDuration: probed,
 				AddedAt:        time.Now(),
 				LoadAttempts:   0,
 				MaxAttempts:    3,
@@ -2647,9 +3247,13 @@ func (p *GuildPlayer) attemptVoiceRecovery() {
 		}
 
 		// Send notification to channel about recovery
-		if p.GetLastTextChannelID() != "" {
-			go p.sendRecoveryMessage("🔄 Voice connection restored! Playback resumed.")
+		if p.textChannelID() != "" {
+			go p.sendRecoveryMessage(message)
 		}
+	} else {
+		// Left on purpose (/disconnect) while we were reconnecting.
+		p.reconnectAttempts = 0
+		p.recovering.Store(false)
 	}
 }
 
@@ -2669,19 +3273,26 @@ func (p *GuildPlayer) handleVoiceRecoveryFailure() {
 	p.VoiceConnection = nil
 	p.VoiceChannelID = nil
 	p.VoiceChannelMutex.Unlock()
+	p.clips.close()
 	p.reconnectAttempts = 0
-
-	// Send notification to channel about failure
-	if p.GetLastTextChannelID() != "" {
-		go p.sendRecoveryMessage("❌ Voice connection lost and recovery failed. Use a play command to reconnect.")
+	p.recovering.Store(false)
+
+	// Send notification to channel about failure, then wrap up the session
+	if p.textChannelID() != "" {
+		go func() {
+			p.sendRecoveryMessage("❌ Voice connection lost and recovery failed. Use a play command to reconnect.")
+			p.closeSessionThread()
+		}()
+	} else {
+		go p.closeSessionThread()
 	}
 
 	p.stopVoiceConnectionMonitor()
 }
 
-// sendRecoveryMessage sends a message to the last active text channel
+// sendRecoveryMessage sends a message to the music channel, or the last active text channel
 func (p *GuildPlayer) sendRecoveryMessage(message string) {
-	textCh := p.GetLastTextChannelID()
+	textCh := p.textChannelID()
 	if p.Discord != nil && textCh != "" {
 		_, err := p.Discord.ChannelMessageSend(textCh, message)
 		if err != nil {
@@ -2778,7 +3389,7 @@ func (p *GuildPlayer) generateTransitionTTS() {
 	// Use a detached context so Reset/playerCtx cancellation doesn't
 	// abort mid-flight TTS generation. The TTS watcher goroutine is
 	// stopped separately via ttsWatcherStop.
-	ctx := context.Background()
+	ctx := gemini.WithGuild(context.Background(), p.GuildID)
 	span := sentry.StartSpan(ctx, "tts.pre_generate")
 	defer span.Finish()
 	ctx = span.Context()
@@ -2882,16 +3493,75 @@ func (p *GuildPlayer) playNoMoreSongsMessage() {
 	}
 }
 
+// resolveTitleTranslation fills in item.TranslatedTitle, preferring the
+// per-video DB cache over a fresh Gemini call. Best-effort: /view and the
+// now-playing card simply show the original title until this completes.
+func (p *GuildPlayer) resolveTitleTranslation(item *GuildQueueItem) {
+	videoID := item.Video.VideoID
+
+	if p.DB != nil && videoID != "" {
+		if cached, found, err := p.DB.GetTitleTranslation(videoID); err != nil {
+			log.Warnf("Failed to read cached title translation: %v", err)
+		} else if found {
+			p.currentItemMutex.Lock()
+			item.TranslatedTitle = cached
+			p.currentItemMutex.Unlock()
+			return
+		}
+	}
+
+	translateCtx, translateCancel := context.WithTimeout(p.playerCtx, 10*time.Second)
+	defer translateCancel()
+
+	translation, err := gemini.TranslateTitle(translateCtx, item.Video.Title)
+	if err != nil {
+		// Failed, timed out, rate limited or player reset — don't cache a
+		// miss we never really checked; the next play tries again.
+		log.Debugf("Title translation for %s skipped: %v", videoID, err)
+		return
+	}
+
+	p.currentItemMutex.Lock()
+	item.TranslatedTitle = translation
+	p.currentItemMutex.Unlock()
+
+	if p.DB != nil && videoID != "" {
+		if err := p.DB.SetTitleTranslation(videoID, translation); err != nil {
+			log.Warnf("Failed to cache title translation: %v", err)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"module":      "controller",
+		"method":      "resolveTitleTranslation",
+		"song":        item.Video.Title,
+		"translation": translation,
+	}).Debug("Title translation resolved")
+}
+
 // enrichNowPlayingMetadata layers Deezer enrichment (genre, BPM, album, artist,
 // artwork) onto an already-built NowPlayingMetadata when available. DeezerMeta
 // resolves in the background (see PlaybackStarted handling), so it's typically
 // nil on the initial card and only populated by the time of a later update
 // (periodic refresh or the commentary update).
 func (p *GuildPlayer) enrichNowPlayingMetadata(metadata *discord.NowPlayingMetadata, queueItem *GuildQueueItem) {
+	if !queueItem.Video.IsYouTube() {
+		metadata.URL = queueItem.Video.PageURL()
+	}
+	metadata.Live = queueItem.Video.Live
+
 	p.currentItemMutex.RLock()
 	dm := queueItem.DeezerMeta
+	translated := queueItem.TranslatedTitle
 	p.currentItemMutex.RUnlock()
 
+	if p.GetTranslateTitles() {
+		metadata.TranslatedTitle = translated
+	}
+	if p.listenAlongOn() {
+		metadata.ListenAlongURL = p.cachedSongLink(queueItem)
+	}
+
 	if dm == nil {
 		return
 	}
@@ -2913,7 +3583,7 @@ func (p *GuildPlayer) enrichNowPlayingMetadata(metadata *discord.NowPlayingMetad
 
 // sendNowPlayingCard creates and sends a now-playing embed
 func (p *GuildPlayer) sendNowPlayingCard(queueItem *GuildQueueItem) {
-	textCh := p.GetLastTextChannelID()
+	textCh := p.textChannelID()
 	if textCh == "" {
 		log.Debug("No text channel to send now-playing card")
 		return
@@ -2923,14 +3593,7 @@ func (p *GuildPlayer) sendNowPlayingCard(queueItem *GuildQueueItem) {
 	defer p.nowPlayingMutex.Unlock()
 
 	// Get duration preferring probed > load result > YouTube metadata
-	var duration time.Duration
-	if queueItem.ProbedDuration > 0 {
-		duration = queueItem.ProbedDuration
-	} else if queueItem.LoadResult != nil && queueItem.LoadResult.Duration > 0 {
-		duration = queueItem.LoadResult.Duration
-	} else if queueItem.Video.Duration > 0 {
-		duration = queueItem.Video.Duration
-	}
+	duration := queueItem.Duration()
 
 	// Build metadata
 	metadata := &discord.NowPlayingMetadata{
@@ -2975,6 +3638,14 @@ func (p *GuildPlayer) sendNowPlayingCard(queueItem *GuildQueueItem) {
 
 	log.Debugf("Sent now-playing card: %s", message.ID)
 
+	if p.GetReactionControls() {
+		go p.addControlReactions(textCh, message.ID)
+	}
+	if p.listenAlongOn() {
+		// The card shows the track's own page until song.link answers.
+		go p.songLink(queueItem)
+	}
+
 	// Start periodic updates
 	p.startNowPlayingUpdates(queueItem)
 
@@ -3014,7 +3685,8 @@ func (p *GuildPlayer) generateAndUpdateCommentary(queueItem *GuildQueueItem) {
 	}
 
 	// Generate commentary using Gemini
-	ctx := context.Background()
+	ctx := gemini.WithGuild(gemini.WithGuildStyle(context.Background(), p.GetAIStyle()), p.GuildID)
+	ctx = gemini.WithLanguage(ctx, i18n.LanguageName(p.GetGuildLocale()))
 	commentary := gemini.GenerateNowPlayingCommentary(ctx, queueItem.Video.Title, queueItem.Video.ChannelName, recentSongs, queueItem.IsRadioPick, songCtx)
 
 	if commentary == "" {
@@ -3041,14 +3713,7 @@ func (p *GuildPlayer) generateAndUpdateCommentary(queueItem *GuildQueueItem) {
 
 	// Get current position and duration
 	currentPosition := p.Player.GetPosition()
-	var duration time.Duration
-	if queueItem.ProbedDuration > 0 {
-		duration = queueItem.ProbedDuration
-	} else if queueItem.LoadResult != nil && queueItem.LoadResult.Duration > 0 {
-		duration = queueItem.LoadResult.Duration
-	} else if queueItem.Video.Duration > 0 {
-		duration = queueItem.Video.Duration
-	}
+	duration := queueItem.Duration()
 
 	// Build updated metadata with commentary
 	metadata := &discord.NowPlayingMetadata{
@@ -3094,14 +3759,7 @@ func (p *GuildPlayer) updateNowPlayingCard(queueItem *GuildQueueItem) error {
 	currentPosition := p.Player.GetPosition()
 
 	// Get duration preferring probed > load result > YouTube metadata
-	var duration time.Duration
-	if queueItem.ProbedDuration > 0 {
-		duration = queueItem.ProbedDuration
-	} else if queueItem.LoadResult != nil && queueItem.LoadResult.Duration > 0 {
-		duration = queueItem.LoadResult.Duration
-	} else if queueItem.Video.Duration > 0 {
-		duration = queueItem.Video.Duration
-	}
+	duration := queueItem.Duration()
 
 	// Build updated metadata
 	metadata := &discord.NowPlayingMetadata{
@@ -3162,6 +3820,8 @@ func (p *GuildPlayer) stopNowPlayingUpdates() {
 
 // clearNowPlayingCard clears the now-playing card state and updates Discord to show completion
 func (p *GuildPlayer) clearNowPlayingCard() {
+	p.endNowPlayingPost()
+
 	p.nowPlayingMutex.Lock()
 	defer p.nowPlayingMutex.Unlock()
 
@@ -3174,6 +3834,7 @@ func (p *GuildPlayer) clearNowPlayingCard() {
 
 		metadata := &discord.NowPlayingMetadata{
 			VideoID:         p.nowPlayingCurrentItem.Video.VideoID,
+			URL:             p.nowPlayingCurrentItem.Video.URL,
 			Title:           p.nowPlayingCurrentItem.Video.Title,
 			Duration:        duration,
 			CurrentPosition: duration, // 100% complete
@@ -3181,6 +3842,7 @@ func (p *GuildPlayer) clearNowPlayingCard() {
 			Volume:          p.Player.GetVolume(),
 			GuildID:         p.GuildID,
 			Commentary:      "✅ Completed",
+			Live:            p.nowPlayingCurrentItem.Video.Live,
 		}
 
 		embed := discord.BuildNowPlayingEmbed(metadata)
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -134,6 +135,48 @@ func TestCurrentItemConcurrentAccess(t *testing.T) {
 	wg.Wait()
 }
 
+func TestReconnectDelay(t *testing.T) {
+	want := []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for attempt, w := range want {
+		if got := reconnectDelay(attempt); got != w {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", attempt, got, w)
+		}
+	}
+	if got := reconnectDelay(80); got != maxReconnectDelay {
+		t.Errorf("reconnectDelay(80) = %v, want the cap", got)
+	}
+}
+
+func TestResumeVideo(t *testing.T) {
+	song := youtube.VideoResponse{VideoID: "abc123", Duration: 4 * time.Minute}
+	clip := song
+	clip.Clip(time.Minute, 3*time.Minute)
+
+	tests := []struct {
+		name      string
+		video     youtube.VideoResponse
+		played    time.Duration
+		wantStart time.Duration
+		wantEnd   time.Duration
+		wantDur   time.Duration
+	}{
+		{"barely started", song, 2 * time.Second, 0, 0, 4 * time.Minute},
+		{"midway", song, 90 * time.Second, 90 * time.Second, 0, 150 * time.Second},
+		{"nearly done", song, 4*time.Minute - time.Second, 4*time.Minute - 5*time.Second, 0, 5 * time.Second},
+		{"clip midway", clip, 30 * time.Second, 90 * time.Second, 3 * time.Minute, 90 * time.Second},
+		{"live", youtube.VideoResponse{VideoID: "live1", Live: true}, time.Hour, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resumeVideo(tt.video, tt.played)
+			if got.StartAt != tt.wantStart || got.EndAt != tt.wantEnd || got.Duration != tt.wantDur {
+				t.Errorf("resumeVideo = start %v end %v duration %v; want %v %v %v",
+					got.StartAt, got.EndAt, got.Duration, tt.wantStart, tt.wantEnd, tt.wantDur)
+			}
+		})
+	}
+}
+
 // TestRecoveryRequeueFreshItem verifies the recovery re-queue logic:
 // when savedItem != nil, a fresh GuildQueueItem with nil LoadResult and
 // Stream is prepended to the front of the queue, and an EventAdd notification
@@ -451,3 +494,218 @@ func TestVoiceMonitorSkipsRecoveryWhenPaused(t *testing.T) {
 		t.Error("expected NO recovery when not playing")
 	}
 }
+
+func TestAddPriorityOrdering(t *testing.T) {
+	player := &GuildPlayer{
+		Queue: &GuildQueue{notifications: make(chan QueueEvent, 100)},
+	}
+	player.SetDJRoleID("dj-role")
+	player.SetMemberRoles("dj1", []string{"other", "dj-role"})
+	player.SetMemberRoles("dj2", []string{"dj-role"})
+	player.SetMemberRoles("user1", []string{"other"})
+
+	ctx := context.Background()
+	add := func(id, userID string, radio bool) {
+		player.Add(ctx, youtube.VideoResponse{VideoID: id}, userID, "", "", nil, radio)
+	}
+
+	add("radio1", "", true)
+	add("user1-a", "user1", false)
+	add("dj1-a", "dj1", false)
+	add("user1-b", "user1", false)
+	add("dj2-a", "dj2", false)
+	add("radio2", "", true)
+
+	want := []string{"dj1-a", "dj2-a", "user1-a", "user1-b", "radio1", "radio2"}
+	snap := player.GetQueueSnapshot()
+	if len(snap) != len(want) {
+		t.Fatalf("queue len = %d, want %d", len(snap), len(want))
+	}
+	for i, id := range want {
+		if snap[i].Video.VideoID != id {
+			t.Errorf("queue[%d] = %s, want %s", i, snap[i].Video.VideoID, id)
+		}
+	}
+
+	// Without a DJ role configured, DJ members are treated like everyone else.
+	player.SetDJRoleID("")
+	if player.IsDJ("dj1") {
+		t.Error("IsDJ should be false once the DJ role is cleared")
+	}
+}
+
+func TestDJOnlyCommands(t *testing.T) {
+	player := &GuildPlayer{}
+	if player.IsDJOnly("skip") {
+		t.Error("nothing should be restricted by default")
+	}
+
+	player.SetDJOnlyCommands([]string{"volume", "bogus", "skip"})
+	if !player.IsDJOnly("skip") || !player.IsDJOnly("volume") || player.IsDJOnly("bogus") {
+		t.Error("expected skip and volume restricted and unknown names ignored")
+	}
+	got := player.GetDJOnlyCommands()
+	if len(got) != 2 || got[0] != "skip" || got[1] != "volume" {
+		t.Errorf("GetDJOnlyCommands = %v, want [skip volume]", got)
+	}
+
+	player.SetDJOnlyCommands(nil)
+	if player.IsDJOnly("skip") {
+		t.Error("restrictions should be lifted")
+	}
+}
+
+func TestTooLong(t *testing.T) {
+	player := &GuildPlayer{}
+	long := youtube.VideoResponse{Duration: 2 * time.Hour}
+	if player.TooLong(long) {
+		t.Error("nothing is too long without a limit")
+	}
+
+	player.SetMaxTrackDuration(10 * time.Minute)
+	if !player.TooLong(long) {
+		t.Error("a 2h song should exceed a 10m limit")
+	}
+	if player.TooLong(youtube.VideoResponse{Duration: 10 * time.Minute}) || player.TooLong(youtube.VideoResponse{}) {
+		t.Error("songs at the limit or of unknown length should be allowed")
+	}
+}
+
+func TestFairQueueInterleavesRequesters(t *testing.T) {
+	player := &GuildPlayer{
+		Queue: &GuildQueue{notifications: make(chan QueueEvent, 100), Mode: QueueModeFair},
+	}
+
+	ctx := context.Background()
+	add := func(id, userID string) {
+		player.Add(ctx, youtube.VideoResponse{VideoID: id}, userID, "", "", nil)
+	}
+
+	add("a1", "A")
+	add("a2", "A")
+	add("a3", "A")
+	add("b1", "B")
+	add("c1", "C")
+	add("b2", "B")
+	player.Add(ctx, youtube.VideoResponse{VideoID: "radio"}, "", "", "", nil, true)
+
+	want := []string{"a1", "b1", "c1", "a2", "b2", "a3", "radio"}
+	snap := player.GetQueueSnapshot()
+	for i, id := range want {
+		if snap[i].Video.VideoID != id {
+			t.Errorf("queue[%d] = %s, want %s", i, snap[i].Video.VideoID, id)
+		}
+	}
+}
+
+func TestSetQueueModeFairRebalances(t *testing.T) {
+	player := &GuildPlayer{
+		Queue: &GuildQueue{notifications: make(chan QueueEvent, 100)},
+	}
+
+	ctx := context.Background()
+	for _, req := range []struct{ id, user string }{
+		{"a1", "A"}, {"a2", "A"}, {"a3", "A"}, {"b1", "B"}, {"b2", "B"}, {"c1", "C"},
+	} {
+		player.Add(ctx, youtube.VideoResponse{VideoID: req.id}, req.user, "", "", nil)
+	}
+
+	player.SetQueueMode(QueueModeFair)
+
+	if got := player.GetQueueMode(); got != QueueModeFair {
+		t.Fatalf("GetQueueMode() = %s, want fair", got)
+	}
+	want := []string{"a1", "b1", "c1", "a2", "b2", "a3"}
+	snap := player.GetQueueSnapshot()
+	for i, id := range want {
+		if snap[i].Video.VideoID != id {
+			t.Errorf("queue[%d] = %s, want %s", i, snap[i].Video.VideoID, id)
+		}
+	}
+}
+
+func TestClearCancelsRemovedItems(t *testing.T) {
+	player := &GuildPlayer{
+		Queue: &GuildQueue{notifications: make(chan QueueEvent, 100)},
+	}
+
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		player.Add(ctx, youtube.VideoResponse{VideoID: id}, "user", "", "", nil)
+	}
+	items := player.GetQueueSnapshot()
+	items[0].LoadResult = &audio.LoadResult{VideoID: "a"}
+
+	if got := player.Clear(); got != 3 {
+		t.Errorf("Clear() = %d, want 3", got)
+	}
+	if !player.IsEmpty() {
+		t.Error("queue should be empty after Clear")
+	}
+	for _, item := range items {
+		if item.Context.Err() == nil {
+			t.Errorf("context for %s should be canceled after Clear", item.Video.VideoID)
+		}
+		if item.LoadResult != nil {
+			t.Errorf("LoadResult for %s should be released after Clear", item.Video.VideoID)
+		}
+	}
+
+	if got := player.Clear(); got != 0 {
+		t.Errorf("Clear() on empty queue = %d, want 0", got)
+	}
+}
+
+func TestJump(t *testing.T) {
+	newPlayer := func() *GuildPlayer {
+		player := &GuildPlayer{
+			Queue: &GuildQueue{notifications: make(chan QueueEvent, 100)},
+		}
+		for _, id := range []string{"a", "b", "c", "d"} {
+			player.Add(context.Background(), youtube.VideoResponse{VideoID: id, Title: id}, "user", "", "", nil)
+		}
+		// Drain the add events so only the jump's skip remains.
+		for len(player.Queue.notifications) > 0 {
+			<-player.Queue.notifications
+		}
+		return player
+	}
+
+	ids := func(p *GuildPlayer) string {
+		var out []string
+		for _, item := range p.GetQueueSnapshot() {
+			out = append(out, item.Video.VideoID)
+		}
+		return fmt.Sprint(out)
+	}
+
+	player := newPlayer()
+	dropped := player.GetQueueSnapshot()[:2]
+	title, passed := player.Jump(3, false)
+	if title != "c" || passed != 2 {
+		t.Errorf("Jump(3, drop) = (%q, %d), want (c, 2)", title, passed)
+	}
+	if got := ids(player); got != "[c d]" {
+		t.Errorf("queue after drop = %s, want [c d]", got)
+	}
+	for _, item := range dropped {
+		if item.Context.Err() == nil {
+			t.Errorf("dropped item %s should have its load canceled", item.Video.VideoID)
+		}
+	}
+	if ev := <-player.Queue.notifications; ev.Type != EventSkip {
+		t.Errorf("Jump should send a skip event, got %s", ev.Type)
+	}
+
+	player = newPlayer()
+	if title, passed := player.Jump(3, true); title != "c" || passed != 2 {
+		t.Errorf("Jump(3, move) = (%q, %d), want (c, 2)", title, passed)
+	}
+	if got := ids(player); got != "[c d a b]" {
+		t.Errorf("queue after move = %s, want [c d a b]", got)
+	}
+
+	if title, _ := player.Jump(9, false); title != "" {
+		t.Errorf("Jump out of range should return empty title, got %q", title)
+	}
+}
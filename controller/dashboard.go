@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+)
+
+// dashboardMessageSetting is the guild setting holding the dashboard's
+// "channelID/messageID", so a restart edits the same message rather than
+// posting a new one.
+const dashboardMessageSetting = "dashboard_message"
+
+// dashboardDelay batches changes that land together (a skip is a stop and
+// a start, a playlist is many adds) into one edit.
+const dashboardDelay = time.Second
+
+// RefreshDashboard schedules an edit of the music channel dashboard, if the
+// guild has one. Safe to call as often as anything changes.
+func (p *GuildPlayer) RefreshDashboard() {
+	if !p.GetDashboardEnabled() {
+		return
+	}
+	p.dashboardMu.Lock()
+	defer p.dashboardMu.Unlock()
+	if p.dashboardTimer == nil {
+		p.dashboardTimer = time.AfterFunc(dashboardDelay, p.updateDashboard)
+	}
+}
+
+// updateDashboard edits the dashboard in place, posting and pinning a new
+// one when there isn't one in the music channel yet or it was deleted.
+func (p *GuildPlayer) updateDashboard() {
+	p.dashboardMu.Lock()
+	p.dashboardTimer = nil
+	p.dashboardMu.Unlock()
+
+	channelID := p.GetMusicChannelID()
+	if !p.GetDashboardEnabled() || channelID == "" || p.Discord == nil {
+		return
+	}
+
+	// One update at a time, so two can't both decide to post.
+	p.dashboardPostMu.Lock()
+	defer p.dashboardPostMu.Unlock()
+
+	embed, buttons := p.dashboardContent()
+	oldChannel, oldMessage := p.dashboardMessage()
+	if oldMessage != "" && oldChannel == channelID {
+		err := discord.EditChannelMessage(channelID, oldMessage, "", embed, buttons)
+		if err == nil {
+			return
+		}
+		var apiErr *discord.APIError
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+			log.Warnf("Failed to update dashboard for guild %s: %v", p.GuildID, err)
+			return
+		}
+		log.Infof("Dashboard message for guild %s is gone, posting a new one", p.GuildID)
+	} else if oldMessage != "" {
+		// The music channel moved; take the old dashboard down.
+		p.deleteDashboard(oldChannel, oldMessage)
+	}
+
+	message, err := discord.SendChannelMessage(channelID, "", embed, buttons)
+	if err != nil {
+		log.Errorf("Failed to post dashboard for guild %s: %v", p.GuildID, err)
+		return
+	}
+	if err := p.Discord.ChannelMessagePin(channelID, message.ID); err != nil {
+		log.Warnf("Failed to pin dashboard for guild %s: %v", p.GuildID, err)
+	}
+	p.setDashboardMessage(channelID, message.ID)
+}
+
+// dashboardContent renders the dashboard for the current state: the song
+// playing with its playback buttons, and what's up next.
+func (p *GuildPlayer) dashboardContent() (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	var nowPlaying *discordgo.MessageEmbed
+	// An empty (not nil) list clears the buttons when nothing is playing.
+	buttons := []discordgo.MessageComponent{}
+	if item := p.GetCurrentItem(); item != nil && p.Player != nil {
+		metadata := p.nowPlayingMetadata(item)
+		nowPlaying = discord.BuildNowPlayingEmbed(metadata)
+		buttons = discord.BuildPlaybackButtons(p.GuildID, metadata.IsPlaying)
+	}
+
+	var upNext []string
+	queued := 0
+	if p.Queue != nil {
+		p.Queue.Mutex.Lock()
+		queued = len(p.Queue.Items)
+		for _, item := range p.Queue.Items {
+			if len(upNext) == discord.DashboardUpNext {
+				break
+			}
+			upNext = append(upNext, item.Video.Title)
+		}
+		p.Queue.Mutex.Unlock()
+	}
+
+	return discord.BuildDashboardEmbed(nowPlaying, upNext, queued), buttons
+}
+
+// RemoveDashboard deletes the dashboard message and forgets it. Used when
+// /dashboard is turned off.
+func (p *GuildPlayer) RemoveDashboard() {
+	p.dashboardMu.Lock()
+	if p.dashboardTimer != nil {
+		p.dashboardTimer.Stop()
+		p.dashboardTimer = nil
+	}
+	p.dashboardMu.Unlock()
+
+	p.dashboardPostMu.Lock()
+	defer p.dashboardPostMu.Unlock()
+	if channelID, messageID := p.dashboardMessage(); messageID != "" {
+		p.deleteDashboard(channelID, messageID)
+	}
+	p.setDashboardMessage("", "")
+}
+
+func (p *GuildPlayer) deleteDashboard(channelID, messageID string) {
+	if p.Discord == nil {
+		return
+	}
+	if err := p.Discord.ChannelMessageDelete(channelID, messageID); err != nil {
+		log.Warnf("Failed to delete old dashboard for guild %s: %v", p.GuildID, err)
+	}
+}
+
+func (p *GuildPlayer) dashboardMessage() (channelID, messageID string) {
+	p.dashboardMu.Lock()
+	defer p.dashboardMu.Unlock()
+	return p.dashboardChannelID, p.dashboardMessageID
+}
+
+// setDashboardMessage remembers where the dashboard is, in memory and in
+// guild_settings.
+func (p *GuildPlayer) setDashboardMessage(channelID, messageID string) {
+	p.dashboardMu.Lock()
+	p.dashboardChannelID, p.dashboardMessageID = channelID, messageID
+	p.dashboardMu.Unlock()
+
+	if p.DB == nil {
+		return
+	}
+	value := ""
+	if messageID != "" {
+		value = channelID + "/" + messageID
+	}
+	if err := p.DB.SetGuildSetting(p.GuildID, dashboardMessageSetting, value); err != nil {
+		log.Warnf("Failed to save dashboard message for guild %s: %v", p.GuildID, err)
+	}
+}
+
+// parseDashboardMessage splits a dashboardMessageSetting value.
+func parseDashboardMessage(value string) (channelID, messageID string) {
+	channelID, messageID, ok := strings.Cut(value, "/")
+	if !ok {
+		return "", ""
+	}
+	return channelID, messageID
+}
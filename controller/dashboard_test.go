@@ -0,0 +1,32 @@
+package controller
+
+import "testing"
+
+func TestParseDashboardMessage(t *testing.T) {
+	if ch, msg := parseDashboardMessage("123/456"); ch != "123" || msg != "456" {
+		t.Errorf("parseDashboardMessage = %q, %q", ch, msg)
+	}
+	if ch, msg := parseDashboardMessage("garbage"); ch != "" || msg != "" {
+		t.Errorf("parseDashboardMessage(garbage) = %q, %q", ch, msg)
+	}
+}
+
+func TestRefreshDashboardDisabled(t *testing.T) {
+	p := &GuildPlayer{GuildID: "g1"}
+	p.RefreshDashboard()
+	if p.dashboardTimer != nil {
+		t.Error("scheduled a dashboard edit with the dashboard off")
+	}
+
+	p.SetDashboardEnabled(true)
+	p.RefreshDashboard()
+	p.RefreshDashboard()
+	p.dashboardMu.Lock()
+	timer := p.dashboardTimer
+	p.dashboardMu.Unlock()
+	if timer == nil {
+		t.Fatal("no dashboard edit scheduled")
+	}
+	// Without a music channel the edit does nothing when it fires.
+	timer.Stop()
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"time"
+
+	"beatbot/audio"
+)
+
+// PipelineStatus is a snapshot of a guild's playback pipeline for
+// /audiodebug: voice connection, player, loader and queue.
+type PipelineStatus struct {
+	VoiceChannelID string // "" when not connected
+	VoiceReady     bool
+	OpusBuffered   int // frames waiting in the voice send buffer
+	OpusCapacity   int
+
+	Playing      bool
+	Paused       bool
+	Volume       int
+	CurrentTitle string
+	Position     time.Duration
+	Duration     time.Duration
+	Encoder      string
+	PlayerEvents int // notifications not yet picked up by the controller
+
+	Loader audio.LoaderStats
+
+	QueueLength int
+	ItemStates  map[ItemState]int // queued items by state
+}
+
+// PipelineStatus gathers the pipeline state without holding any lock for
+// longer than it takes to copy a field.
+func (p *GuildPlayer) PipelineStatus() PipelineStatus {
+	status := PipelineStatus{
+		Encoder:    audio.EncoderSettings,
+		ItemStates: make(map[ItemState]int),
+	}
+
+	p.VoiceChannelMutex.RLock()
+	if p.VoiceChannelID != nil {
+		status.VoiceChannelID = *p.VoiceChannelID
+	}
+	vc := p.VoiceConnection
+	p.VoiceChannelMutex.RUnlock()
+	if vc != nil {
+		vc.RLock()
+		status.VoiceReady = vc.Ready
+		vc.RUnlock()
+		status.OpusBuffered, status.OpusCapacity = len(vc.OpusSend), cap(vc.OpusSend)
+	}
+
+	if p.Player != nil {
+		status.Playing = p.Player.IsPlaying()
+		status.Paused = p.Player.IsPaused()
+		status.Volume = p.Player.GetVolume()
+		status.Position = p.Player.GetPosition()
+		status.PlayerEvents = len(p.Player.Notifications)
+	}
+	if item := p.GetCurrentItem(); item != nil {
+		status.CurrentTitle = item.Video.Title
+		status.Duration = item.Duration()
+	}
+	if p.Loader != nil {
+		status.Loader = p.Loader.Stats()
+	}
+
+	for _, item := range p.GetQueueSnapshot() {
+		status.ItemStates[item.State()]++
+		status.QueueLength++
+	}
+	return status
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// onGuildCreate records each guild the bot is in. Discord sends it for
+// every guild on connecting, not just new ones.
+func (c *Controller) onGuildCreate(s *discordgo.Session, g *discordgo.GuildCreate) {
+	if c.db == nil || g.Guild == nil {
+		return
+	}
+	added, err := c.db.RecordGuildJoin(g.ID, g.Name)
+	if err != nil {
+		log.Errorf("Failed to record guild %s: %v", g.ID, err)
+		return
+	}
+	if added {
+		log.Infof("Added to guild %s (%s)", g.ID, g.Name)
+	}
+}
+
+// onGuildDelete stops playback in a guild the bot was removed from and
+// marks its data for deletion. A guild that's only unavailable, during a
+// Discord outage, is left alone.
+func (c *Controller) onGuildDelete(s *discordgo.Session, g *discordgo.GuildDelete) {
+	if g.Guild == nil || g.Unavailable {
+		return
+	}
+	log.Infof("Removed from guild %s", g.ID)
+
+	if player, ok := c.LookupPlayer(g.ID); ok {
+		player.Disconnect()
+	}
+	if c.db == nil {
+		return
+	}
+	if err := c.db.RecordGuildLeave(g.ID); err != nil {
+		log.Errorf("Failed to record leaving guild %s: %v", g.ID, err)
+	}
+}
@@ -0,0 +1,83 @@
+package controller
+
+import "sync/atomic"
+
+// ItemState tracks how far a queue item has progressed towards playback.
+// The controller moves items forward as their stream resolves, their audio
+// buffers and playback starts; handlers read it for badges and to refuse
+// edits that would pull a starting song out from under the player.
+type ItemState int32
+
+const (
+	// ItemPending is a freshly queued item whose stream URL is still being resolved.
+	ItemPending ItemState = iota
+	// ItemResolved has a stream URL but no buffered audio yet.
+	ItemResolved
+	// ItemLoaded has its audio fully buffered and is ready to play.
+	ItemLoaded
+	// ItemPlaying has been handed to the player. It leaves the queue once
+	// playback actually starts.
+	ItemPlaying
+)
+
+func (s ItemState) String() string {
+	switch s {
+	case ItemPending:
+		return "pending"
+	case ItemResolved:
+		return "resolved"
+	case ItemLoaded:
+		return "loaded"
+	case ItemPlaying:
+		return "playing"
+	}
+	return "unknown"
+}
+
+// Badge is the /view marker for the state, or "" for states not worth
+// calling out.
+func (s ItemState) Badge() string {
+	switch s {
+	case ItemPending:
+		return "⏳"
+	case ItemLoaded:
+		return "📦"
+	case ItemPlaying:
+		return "▶️"
+	}
+	return ""
+}
+
+// State returns the item's current state. Safe to call without locks.
+func (item *GuildQueueItem) State() ItemState {
+	return ItemState(atomic.LoadInt32(&item.state))
+}
+
+func (item *GuildQueueItem) setState(s ItemState) {
+	atomic.StoreInt32(&item.state, int32(s))
+}
+
+// dropLoadResult releases buffered audio but keeps the stream URL, moving a
+// loaded item back to resolved so it reloads when its turn comes.
+func (item *GuildQueueItem) dropLoadResult() {
+	item.LoadResult = nil
+	if item.State() == ItemLoaded {
+		item.setState(ItemResolved)
+	}
+}
+
+// locked reports whether the item is being started by the player and must
+// not be moved or removed until playback begins and pops it.
+func (item *GuildQueueItem) locked() bool {
+	return item.State() == ItemPlaying
+}
+
+// pinned returns how many items at the head of the queue are locked in
+// place (0 or 1). Reorders and inserts must start at this index. Caller
+// holds the queue mutex.
+func (q *GuildQueue) pinned() int {
+	if len(q.Items) > 0 && q.Items[0].locked() {
+		return 1
+	}
+	return 0
+}
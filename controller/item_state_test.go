@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"beatbot/audio"
+	"beatbot/youtube"
+)
+
+func TestItemStateBadges(t *testing.T) {
+	tests := []struct {
+		state ItemState
+		badge string
+	}{
+		{ItemPending, "⏳"},
+		{ItemResolved, ""},
+		{ItemLoaded, "📦"},
+		{ItemPlaying, "▶️"},
+	}
+	for _, tt := range tests {
+		if got := tt.state.Badge(); got != tt.badge {
+			t.Errorf("%s.Badge() = %q, want %q", tt.state, got, tt.badge)
+		}
+	}
+}
+
+func TestDropLoadResultRevertsToResolved(t *testing.T) {
+	item := &GuildQueueItem{LoadResult: &audio.LoadResult{}}
+	item.setState(ItemLoaded)
+
+	item.dropLoadResult()
+	if item.LoadResult != nil || item.State() != ItemResolved {
+		t.Errorf("after dropLoadResult: LoadResult=%v state=%s, want nil/resolved", item.LoadResult, item.State())
+	}
+}
+
+// A song the player is starting is still at the head of the queue until
+// PlaybackStarted pops it; edits must leave it there.
+func TestStartingItemIsPinned(t *testing.T) {
+	newPlayer := func() *GuildPlayer {
+		player := newUndoTestPlayer("a", "b", "c", "d")
+		player.GetQueueSnapshot()[0].setState(ItemPlaying)
+		return player
+	}
+
+	player := newPlayer()
+	if got := player.Remove(1); got != "" {
+		t.Errorf("Remove(1) = %q, want refusal", got)
+	}
+	if got := player.RemoveRange(1, 2); got != 0 {
+		t.Errorf("RemoveRange(1, 2) = %d, want refusal", got)
+	}
+	if title, _ := player.Jump(3, false); title != "" {
+		t.Errorf("Jump(3) = %q, want refusal", title)
+	}
+
+	for i := 0; i < 10; i++ {
+		player.Shuffle()
+		if head := player.GetQueueSnapshot()[0].Video.VideoID; head != "a" {
+			t.Fatalf("Shuffle moved the starting song: head = %s", head)
+		}
+	}
+
+	player.Add(context.Background(), youtube.VideoResponse{VideoID: "dj"}, "dj-user", "", "", nil)
+	player.SetDJRoleID("role")
+	player.SetMemberRoles("dj-user", []string{"role"})
+	player.Add(context.Background(), youtube.VideoResponse{VideoID: "dj2"}, "dj-user", "", "", nil)
+	if head := player.GetQueueSnapshot()[0].Video.VideoID; head != "a" {
+		t.Errorf("DJ request jumped ahead of the starting song: head = %s", head)
+	}
+
+	player = newPlayer()
+	if got := player.Clear(); got != 3 {
+		t.Errorf("Clear() = %d, want 3 (starting song kept)", got)
+	}
+	if got := queueIDs(player); got != "[a]" {
+		t.Errorf("queue after Clear = %s, want [a]", got)
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/unfurl"
+)
+
+// songLinkTimeout bounds a song.link lookup, which is rate limited and
+// occasionally slow.
+const songLinkTimeout = 10 * time.Second
+
+// ListenAlongInfo is where the current song is up to, for members playing
+// it on their own player instead of in voice.
+type ListenAlongInfo struct {
+	Title    string
+	Link     string // song.link page, or the track's own page when song.link doesn't know it
+	SeekURL  string // YouTube link starting at Position; "" for other sources and live streams
+	Position time.Duration
+	Playing  bool
+	Live     bool
+}
+
+// StartListenAlong puts a listen-along link on now-playing cards until the
+// bot leaves voice, and returns where the current song is up to. Returns
+// ErrNothingPlaying when there is no current song.
+func (p *GuildPlayer) StartListenAlong() (*ListenAlongInfo, error) {
+	item := p.GetCurrentItem()
+	if item == nil {
+		return nil, ErrNothingPlaying
+	}
+	p.listenAlongMu.Lock()
+	p.listenAlong = true
+	p.listenAlongMu.Unlock()
+
+	info := &ListenAlongInfo{
+		Title: p.DisplayTitle(item),
+		Link:  p.songLink(item),
+		// Read after the lookup, which can take a moment.
+		Position: p.Player.GetPosition(),
+		Playing:  !p.Player.IsPaused(),
+		Live:     item.Video.Live,
+	}
+	if item.Video.IsYouTube() && !info.Live {
+		info.SeekURL = fmt.Sprintf("%s&t=%ds", item.Video.PageURL(), int(info.Position.Seconds()))
+	}
+	return info, nil
+}
+
+func (p *GuildPlayer) listenAlongOn() bool {
+	p.listenAlongMu.Lock()
+	defer p.listenAlongMu.Unlock()
+	return p.listenAlong
+}
+
+// stopListenAlong takes the link off cards when the listening session ends.
+func (p *GuildPlayer) stopListenAlong() {
+	p.listenAlongMu.Lock()
+	p.listenAlong = false
+	p.listenAlongMu.Unlock()
+}
+
+// songLink returns item's song.link page, looking it up the first time.
+// When song.link doesn't know the song the track's own page is kept instead,
+// so it isn't asked again.
+func (p *GuildPlayer) songLink(item *GuildQueueItem) string {
+	p.currentItemMutex.RLock()
+	link := item.SongLink
+	p.currentItemMutex.RUnlock()
+	if link != "" {
+		return link
+	}
+	link = item.Video.PageURL()
+	if !item.Video.Live {
+		ctx, cancel := context.WithTimeout(context.Background(), songLinkTimeout)
+		defer cancel()
+		found, err := unfurl.SongLink(ctx, link)
+		if err != nil {
+			log.Debugf("No song.link page for %s: %v", link, err)
+		} else {
+			link = found
+		}
+	}
+
+	p.currentItemMutex.Lock()
+	item.SongLink = link
+	p.currentItemMutex.Unlock()
+	return link
+}
+
+// cachedSongLink is item's song.link page if it has been looked up, and
+// otherwise the track's own page.
+func (p *GuildPlayer) cachedSongLink(item *GuildQueueItem) string {
+	p.currentItemMutex.RLock()
+	link := item.SongLink
+	p.currentItemMutex.RUnlock()
+	if link == "" {
+		return item.Video.PageURL()
+	}
+	return link
+}
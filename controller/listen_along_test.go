@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"beatbot/audio"
+	"beatbot/discord"
+	"beatbot/youtube"
+)
+
+func TestStartListenAlong(t *testing.T) {
+	player, err := audio.NewPlayer()
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	p := &GuildPlayer{Player: player}
+
+	if _, err := p.StartListenAlong(); !errors.Is(err, ErrNothingPlaying) {
+		t.Fatalf("nothing playing err = %v, want ErrNothingPlaying", err)
+	}
+	if p.listenAlongOn() {
+		t.Error("listen-along turned on with nothing playing")
+	}
+
+	// A song.link page already looked up isn't asked for again.
+	item := &GuildQueueItem{
+		Video:    youtube.VideoResponse{VideoID: "abc", Title: "Song"},
+		SongLink: "https://song.link/y/abc",
+	}
+	p.CurrentItem = item
+	info, err := p.StartListenAlong()
+	if err != nil {
+		t.Fatalf("StartListenAlong: %v", err)
+	}
+	if info.Link != "https://song.link/y/abc" || info.SeekURL != "https://www.youtube.com/watch?v=abc&t=0s" || info.Live {
+		t.Errorf("info = %+v", info)
+	}
+
+	metadata := &discord.NowPlayingMetadata{}
+	p.enrichNowPlayingMetadata(metadata, item)
+	if metadata.ListenAlongURL != "https://song.link/y/abc" {
+		t.Errorf("card link = %q, want the song.link page", metadata.ListenAlongURL)
+	}
+
+	p.stopListenAlong()
+	metadata = &discord.NowPlayingMetadata{}
+	p.enrichNowPlayingMetadata(metadata, item)
+	if metadata.ListenAlongURL != "" {
+		t.Errorf("card link after leaving = %q, want none", metadata.ListenAlongURL)
+	}
+}
+
+func TestStartListenAlongLive(t *testing.T) {
+	player, err := audio.NewPlayer()
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	// Live streams are never looked up on song.link.
+	p := &GuildPlayer{Player: player, CurrentItem: &GuildQueueItem{
+		Video: youtube.VideoResponse{URL: "https://radio.example/stream", Title: "Radio", Source: "http", Live: true},
+	}}
+	info, err := p.StartListenAlong()
+	if err != nil {
+		t.Fatalf("StartListenAlong: %v", err)
+	}
+	if info.Link != "https://radio.example/stream" || info.SeekURL != "" || !info.Live {
+		t.Errorf("info = %+v", info)
+	}
+}
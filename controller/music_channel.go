@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// addAnnounceDelay batches adds that arrive together (playlists, imports)
+// into a single music channel post.
+const addAnnounceDelay = 2 * time.Second
+
+// textChannelID returns where the bot posts on its own: the session thread
+// while one is open, else the bound music channel, or else the channel it
+// was last used from.
+func (p *GuildPlayer) textChannelID() string {
+	if id := p.sessionThread(); id != "" {
+		return id
+	}
+	return p.baseTextChannelID()
+}
+
+// baseTextChannelID is textChannelID without the session thread.
+func (p *GuildPlayer) baseTextChannelID() string {
+	if id := p.GetMusicChannelID(); id != "" {
+		return id
+	}
+	return p.GetLastTextChannelID()
+}
+
+// announceChannelID returns where queue updates are posted: the session
+// thread, or the bound music channel. "" means they aren't posted.
+func (p *GuildPlayer) announceChannelID() string {
+	if id := p.sessionThread(); id != "" {
+		return id
+	}
+	return p.GetMusicChannelID()
+}
+
+// announceQueued schedules an "added to queue" post in the music channel.
+// Interaction followups expire after 15 minutes, so on long sessions this
+// is the only record of what got queued.
+func (p *GuildPlayer) announceQueued(item *GuildQueueItem) {
+	p.addAnnounceMu.Lock()
+	defer p.addAnnounceMu.Unlock()
+	p.pendingAdds = append(p.pendingAdds, item)
+	if p.addAnnounceTimer == nil {
+		p.addAnnounceTimer = time.AfterFunc(addAnnounceDelay, p.flushQueuedAnnouncement)
+	}
+}
+
+func (p *GuildPlayer) flushQueuedAnnouncement() {
+	p.addAnnounceMu.Lock()
+	items := p.pendingAdds
+	p.pendingAdds = nil
+	p.addAnnounceTimer = nil
+	p.addAnnounceMu.Unlock()
+
+	channelID := p.announceChannelID()
+	if channelID == "" || p.Discord == nil || len(items) == 0 {
+		return
+	}
+
+	msg := formatQueuedAnnouncement(items, p.resolveQueuedBy)
+	if _, err := p.Discord.ChannelMessageSend(channelID, msg); err != nil {
+		log.Errorf("Failed to post queue update to music channel %s: %v", channelID, err)
+	}
+}
+
+// formatQueuedAnnouncement renders one post for a batch of queued items,
+// naming up to three songs.
+func formatQueuedAnnouncement(items []*GuildQueueItem, queuedBy func(*GuildQueueItem) string) string {
+	const maxNamed = 3
+
+	requester := queuedBy(items[0])
+	for _, item := range items[1:] {
+		if queuedBy(item) != requester {
+			requester = ""
+			break
+		}
+	}
+
+	if len(items) == 1 {
+		msg := "➕ Queued **" + items[0].Video.Title + "**"
+		if requester != "" {
+			msg += " for " + requester
+		}
+		return msg
+	}
+
+	var titles []string
+	for i, item := range items {
+		if i == maxNamed {
+			break
+		}
+		titles = append(titles, "**"+item.Video.Title+"**")
+	}
+	list := strings.Join(titles, ", ")
+	if extra := len(items) - maxNamed; extra > 0 {
+		list += fmt.Sprintf(" and %d more", extra)
+	}
+
+	msg := fmt.Sprintf("➕ Queued %d songs", len(items))
+	if requester != "" {
+		msg += " for " + requester
+	}
+	return msg + ": " + list
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"testing"
+
+	"beatbot/youtube"
+)
+
+func TestFormatQueuedAnnouncement(t *testing.T) {
+	item := func(title, user string) *GuildQueueItem {
+		return &GuildQueueItem{
+			Video:       youtube.VideoResponse{Title: title},
+			Interaction: &GuildQueueItemInteraction{UserID: user},
+		}
+	}
+	queuedBy := func(i *GuildQueueItem) string { return i.Interaction.UserID }
+
+	tests := []struct {
+		name  string
+		items []*GuildQueueItem
+		want  string
+	}{
+		{
+			name:  "single",
+			items: []*GuildQueueItem{item("Song A", "ana")},
+			want:  "➕ Queued **Song A** for ana",
+		},
+		{
+			name:  "same requester",
+			items: []*GuildQueueItem{item("A", "ana"), item("B", "ana")},
+			want:  "➕ Queued 2 songs for ana: **A**, **B**",
+		},
+		{
+			name: "mixed requesters with overflow",
+			items: []*GuildQueueItem{
+				item("A", "ana"), item("B", "bo"), item("C", "ana"), item("D", "ana"), item("E", "bo"),
+			},
+			want: "➕ Queued 5 songs: **A**, **B**, **C** and 2 more",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatQueuedAnnouncement(tt.items, queuedBy); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
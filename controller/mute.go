@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// checkMuted pauses playback when the bot is muted, by a moderator or by
+// itself, so songs don't play out to nobody, and resumes once it's unmuted.
+// Being server deafened doesn't stop anyone hearing the bot, so it only
+// matters to clips, which can't hear the channel while it lasts.
+func (p *GuildPlayer) checkMuted(vs *discordgo.VoiceState) {
+	muted := vs.ChannelID != "" && (vs.Mute || vs.SelfMute)
+	deafened := vs.ChannelID != "" && vs.Deaf
+
+	p.presenceMu.Lock()
+	wasMuted, wasDeafened := p.muted, p.deafened
+	p.muted, p.deafened = muted, deafened
+	var paused, resumed bool
+	switch {
+	case muted && !wasMuted:
+		if p.Player != nil && p.Player.IsPlaying() && !p.Player.IsPaused() {
+			log.Infof("Pausing guild %s: the bot was muted", p.GuildID)
+			p.mutePaused = true
+			paused = true
+			p.Player.Pause(context.Background())
+		}
+	case !muted && wasMuted:
+		// Listeners may have come back while it was muted, too.
+		resume := p.mutePaused || (p.autoPaused && p.emptyTimer == nil)
+		p.mutePaused = false
+		switch {
+		case !resume || p.Player == nil || !p.Player.IsPaused():
+		case p.emptyTimer != nil:
+			// Nobody's here to hear it; resume with the next listener.
+			p.autoPaused = true
+		default:
+			log.Infof("Resuming guild %s: the bot was unmuted", p.GuildID)
+			p.autoPaused = false
+			resumed = true
+			p.Player.Resume(context.Background())
+		}
+	}
+	p.presenceMu.Unlock()
+
+	switch {
+	case paused:
+		p.sendPresenceMessage("🔇 I've been muted, so I paused the music rather than play to nobody. Unmute me and I'll pick up where I left off.")
+	case resumed:
+		p.sendPresenceMessage("🔊 Unmuted — picking up where I left off.")
+	}
+	if deafened && !wasDeafened && p.GetClipsEnabled() {
+		p.sendPresenceMessage("🙉 I've been server deafened, so `/clip` can't hear the channel until I'm undeafened. The music isn't affected.")
+	}
+}
+
+// sendPresenceMessage posts a note about the bot's voice status to the
+// player's text channel, if it has one.
+func (p *GuildPlayer) sendPresenceMessage(message string) {
+	textCh := p.textChannelID()
+	if textCh == "" || p.Discord == nil {
+		return
+	}
+	if _, err := p.Discord.ChannelMessageSend(textCh, message); err != nil {
+		log.Errorf("Failed to send voice status message: %v", err)
+	}
+}
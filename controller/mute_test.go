@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"beatbot/audio"
+)
+
+func mutePlayer(t *testing.T, voiceStates ...*discordgo.VoiceState) *GuildPlayer {
+	t.Helper()
+	p := presencePlayer(t, voiceStates...)
+	player, err := audio.NewPlayer()
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	player.SetPlaying(true)
+	p.Player = player
+	return p
+}
+
+func TestCheckMutedPausesAndResumes(t *testing.T) {
+	p := mutePlayer(t,
+		&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"},
+		&discordgo.VoiceState{UserID: "ana", ChannelID: "vc1"},
+	)
+
+	p.checkMuted(&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1", Mute: true})
+	if !p.Player.IsPaused() {
+		t.Fatal("still playing after being server muted")
+	}
+
+	// A listener coming and going doesn't resume while muted.
+	p.checkListeners()
+	if !p.Player.IsPaused() {
+		t.Fatal("resumed while muted")
+	}
+
+	p.checkMuted(&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"})
+	if p.Player.IsPaused() {
+		t.Error("still paused after being unmuted")
+	}
+}
+
+func TestCheckMutedLeavesManualPause(t *testing.T) {
+	p := mutePlayer(t,
+		&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"},
+		&discordgo.VoiceState{UserID: "ana", ChannelID: "vc1"},
+	)
+	p.Player.SetPaused(true) // /pause
+
+	p.checkMuted(&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1", SelfMute: true})
+	p.checkMuted(&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"})
+	if !p.Player.IsPaused() {
+		t.Error("unmuting resumed a pause someone asked for")
+	}
+}
+
+func TestCheckMutedEmptyChannel(t *testing.T) {
+	p := mutePlayer(t, &discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"})
+
+	p.checkMuted(&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1", Mute: true})
+	p.checkListeners() // everyone's gone
+	p.checkMuted(&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"})
+	if !p.Player.IsPaused() {
+		t.Fatal("resumed with nobody listening")
+	}
+
+	// The next listener picks it back up.
+	if err := p.Discord.State.OnInterface(p.Discord, &discordgo.VoiceStateUpdate{
+		VoiceState: &discordgo.VoiceState{GuildID: "g1", UserID: "ana", ChannelID: "vc1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	p.checkListeners()
+	if p.Player.IsPaused() {
+		t.Error("still paused once a listener came back")
+	}
+}
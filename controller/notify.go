@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+)
+
+// interactionTokenLifetime is how long Discord accepts followups on an
+// interaction token. Tokens are treated as expired a little early so a
+// followup sent right at the end doesn't race the cutoff.
+const (
+	interactionTokenLifetime = 15 * time.Minute
+	tokenExpiryMargin        = 30 * time.Second
+)
+
+// TokenExpired reports whether the interaction's token can no longer be used
+// for followups. An interaction with no token (a scheduled play) counts as
+// expired.
+func (i *GuildQueueItemInteraction) TokenExpired() bool {
+	if i == nil || i.InteractionToken == "" {
+		return true
+	}
+	if i.IssuedAt.IsZero() {
+		return false
+	}
+	return time.Since(i.IssuedAt) > interactionTokenLifetime-tokenExpiryMargin
+}
+
+// reportToRequester tells whoever queued item about a problem with it, as a
+// followup to their command (or an edit of its response, with edit set).
+// Songs deep in a long queue outlive their token, so once it has expired
+// the message goes to the music channel instead, naming the requester.
+// Items nobody queued (radio picks) have no one to tell.
+func (p *GuildPlayer) reportToRequester(item *GuildQueueItem, msg string, edit bool) {
+	if item.Interaction == nil {
+		return
+	}
+	if !item.Interaction.TokenExpired() {
+		p.followupRequester(item, msg, edit)
+		return
+	}
+	if item.Interaction.UserID == "" || p.Discord == nil {
+		return
+	}
+	textCh := p.textChannelID()
+	if textCh == "" {
+		return
+	}
+	_, err := p.Discord.ChannelMessageSendComplex(textCh, &discordgo.MessageSend{
+		Content: msg + "\n-# Queued by <@" + item.Interaction.UserID + ">",
+		// Name the requester without pinging them.
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Errorf("Failed to post problem with %s to the music channel: %v", item.Video.Title, err)
+	}
+}
+
+// progressToRequester sends progress chatter (loading, retrying) about item
+// to whoever queued it. Unlike problems it isn't worth a channel post, so it
+// is dropped once the token has expired.
+func (p *GuildPlayer) progressToRequester(item *GuildQueueItem, msg string, edit bool) {
+	if item.Interaction.TokenExpired() {
+		return
+	}
+	p.followupRequester(item, msg, edit)
+}
+
+func (p *GuildPlayer) followupRequester(item *GuildQueueItem, msg string, edit bool) {
+	request := &discord.FollowUpRequest{
+		Token:   item.Interaction.InteractionToken,
+		AppID:   item.Interaction.AppID,
+		UserID:  item.Interaction.UserID,
+		Content: msg,
+	}
+	if edit {
+		discord.UpdateMessage(request)
+	} else {
+		discord.SendFollowup(request)
+	}
+}
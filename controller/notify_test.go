@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenExpired(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *GuildQueueItemInteraction
+		want        bool
+	}{
+		{"nil", nil, true},
+		{"no token", &GuildQueueItemInteraction{UserID: "u"}, true},
+		{"fresh", &GuildQueueItemInteraction{InteractionToken: "t", IssuedAt: time.Now()}, false},
+		{"unknown age", &GuildQueueItemInteraction{InteractionToken: "t"}, false},
+		{"inside the margin", &GuildQueueItemInteraction{InteractionToken: "t", IssuedAt: time.Now().Add(-(interactionTokenLifetime - tokenExpiryMargin/2))}, true},
+		{"old", &GuildQueueItemInteraction{InteractionToken: "t", IssuedAt: time.Now().Add(-time.Hour)}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.interaction.TokenExpired(); got != tt.want {
+			t.Errorf("%s: TokenExpired = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReportToRequesterWithoutRequester(t *testing.T) {
+	// Loop requeues and radio picks have nobody to tell; neither may panic.
+	p := &GuildPlayer{GuildID: "g1"}
+	p.reportToRequester(&GuildQueueItem{}, "gone", false)
+	p.progressToRequester(&GuildQueueItem{}, "loading", true)
+	p.reportToRequester(&GuildQueueItem{Interaction: &GuildQueueItemInteraction{}}, "gone", false)
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+)
+
+// ErrNothingPlaying is returned when a command needs a current song and
+// there is none.
+var ErrNothingPlaying = errors.New("nothing is playing")
+
+// nowPlayingPostInterval is how often a /nowplaying card's progress bar is
+// refreshed. Slower than the automatic card since these can pile up in chat.
+const nowPlayingPostInterval = 10 * time.Second
+
+// nowPlayingPost is a card posted on request by /nowplaying. Unlike the
+// automatic card it carries playback buttons, and it only refreshes its
+// progress bar. At most one is live per guild; posting another retires it.
+type nowPlayingPost struct {
+	channelID string
+	messageID string
+	item      *GuildQueueItem
+	embed     *discordgo.MessageEmbed // owned by the refresh goroutine once posted
+	stop      chan struct{}           // closed to retire the card
+}
+
+// nowPlayingMetadata describes item at the current playback position.
+func (p *GuildPlayer) nowPlayingMetadata(item *GuildQueueItem) *discord.NowPlayingMetadata {
+	metadata := &discord.NowPlayingMetadata{
+		VideoID:         item.Video.VideoID,
+		Title:           item.Video.Title,
+		Duration:        item.Duration(),
+		CurrentPosition: p.Player.GetPosition(),
+		IsPlaying:       !p.Player.IsPaused(),
+		Volume:          p.Player.GetVolume(),
+		GuildID:         p.GuildID,
+		Commentary:      item.Commentary,
+	}
+	p.enrichNowPlayingMetadata(metadata, item)
+	return metadata
+}
+
+// PostNowPlayingCard posts a now-playing card with playback buttons to
+// channelID and keeps its progress bar moving until the track ends. Returns
+// ErrNothingPlaying when there is no current song.
+func (p *GuildPlayer) PostNowPlayingCard(channelID string) error {
+	item := p.GetCurrentItem()
+	if item == nil {
+		return ErrNothingPlaying
+	}
+
+	metadata := p.nowPlayingMetadata(item)
+	embed := discord.BuildNowPlayingEmbed(metadata)
+	message, err := discord.SendChannelMessage(channelID, "", embed, discord.BuildPlaybackButtons(p.GuildID, metadata.IsPlaying))
+	if err != nil {
+		return err
+	}
+
+	post := &nowPlayingPost{
+		channelID: channelID,
+		messageID: message.ID,
+		item:      item,
+		embed:     embed,
+		stop:      make(chan struct{}),
+	}
+	p.nowPlayingPostMu.Lock()
+	previous := p.nowPlayingPost
+	p.nowPlayingPost = post
+	p.nowPlayingPostMu.Unlock()
+	if previous != nil {
+		close(previous.stop)
+	}
+
+	go p.refreshNowPlayingPost(post)
+	return nil
+}
+
+// endNowPlayingPost retires the live /nowplaying card, if any. Its refresh
+// goroutine then freezes it and strips the buttons.
+func (p *GuildPlayer) endNowPlayingPost() {
+	p.nowPlayingPostMu.Lock()
+	post := p.nowPlayingPost
+	p.nowPlayingPost = nil
+	p.nowPlayingPostMu.Unlock()
+	if post != nil {
+		close(post.stop)
+	}
+}
+
+func (p *GuildPlayer) refreshNowPlayingPost(post *nowPlayingPost) {
+	ticker := time.NewTicker(nowPlayingPostInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-post.stop:
+			p.freezeNowPlayingPost(post)
+			return
+		case <-ticker.C:
+			if p.GetCurrentItem() != post.item {
+				// The track ended without clearNowPlayingCard running (or
+				// before this card was registered).
+				p.nowPlayingPostMu.Lock()
+				if p.nowPlayingPost == post {
+					p.nowPlayingPost = nil
+				}
+				p.nowPlayingPostMu.Unlock()
+				p.freezeNowPlayingPost(post)
+				return
+			}
+
+			embed := p.updatePostProgress(post, p.Player.GetPosition())
+			embed = discord.UpdateNowPlayingVolume(embed, p.Player.GetVolume())
+			buttons := discord.BuildPlaybackButtons(p.GuildID, !p.Player.IsPaused())
+			if err := discord.EditChannelMessage(post.channelID, post.messageID, "", embed, buttons); err != nil {
+				log.Warnf("Failed to update /nowplaying card: %v", err)
+				if discord.IsMissingPermissions(err) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// updatePostProgress moves the card's progress bar to position, or for a
+// live stream shows how long it has been on.
+func (p *GuildPlayer) updatePostProgress(post *nowPlayingPost, position time.Duration) *discordgo.MessageEmbed {
+	if post.item.Video.Live {
+		return discord.UpdateNowPlayingLive(post.embed, position)
+	}
+	return discord.UpdateNowPlayingProgress(post.embed, p.GuildID, position, post.item.Duration())
+}
+
+// freezeNowPlayingPost leaves a retired card with its final progress and no
+// buttons, so stale controls can't be clicked.
+func (p *GuildPlayer) freezeNowPlayingPost(post *nowPlayingPost) {
+	// A live stream that already ended keeps the last time it showed.
+	embed := post.embed
+	switch {
+	case p.GetCurrentItem() == post.item:
+		embed = p.updatePostProgress(post, p.Player.GetPosition())
+	case !post.item.Video.Live:
+		embed = p.updatePostProgress(post, post.item.Duration())
+	}
+	if err := discord.EditChannelMessage(post.channelID, post.messageID, "", embed, []discordgo.MessageComponent{}); err != nil {
+		log.Warnf("Failed to finalize /nowplaying card: %v", err)
+	}
+}
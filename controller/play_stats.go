@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// recordPlayStart writes the song_history row for an item that just started
+// playing and remembers its ID so the end of the play can be filled in.
+func (p *GuildPlayer) recordPlayStart(item *GuildQueueItem) {
+	if p.DB == nil {
+		return
+	}
+	userID := ""
+	username := ""
+	if item.Interaction != nil {
+		userID = item.Interaction.UserID
+		// Fetch username from cache or Discord API
+		if userID != "" {
+			username = p.DB.GetOrFetchUsername(p.GuildID, userID)
+		}
+	}
+	playID, err := p.DB.RecordPlay(p.GuildID, item.Video.VideoID, item.Video.Title, item.Video.PageURL(), userID, username, int(item.Video.Duration.Seconds()))
+	if err != nil {
+		log.Errorf("Failed to record play in database: %v", err)
+		return
+	}
+	atomic.StoreInt64(&item.playID, playID)
+}
+
+// recordPlayEnd fills in how long item was listened to and whether it was
+// skipped. Only the first call per play counts: a skip also stops playback,
+// and the PlaybackStopped that follows must not overwrite it.
+func (p *GuildPlayer) recordPlayEnd(item *GuildQueueItem, listened time.Duration, skipped bool) {
+	if p.DB == nil || item == nil {
+		return
+	}
+	playID := atomic.SwapInt64(&item.playID, 0)
+	if playID == 0 {
+		return
+	}
+	if err := p.DB.FinishPlay(playID, int(listened.Seconds()), skipped); err != nil {
+		log.Errorf("Failed to record end of play in database: %v", err)
+	}
+}
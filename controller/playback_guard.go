@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"sync"
+
+	"beatbot/audio"
+)
+
+// playbackGuard makes sure only one play() drives the voice connection at a
+// time. Two PlaybackLoaded events (or a load racing playNext) can otherwise
+// both start Play() for the head of the queue and interleave frames on the
+// same OpusSend, which is audible as garbling.
+//
+// A play() claims a token before starting and releases it when it returns.
+// Song transitions (completion, skip, stop, error) hand off ownership
+// explicitly, because the player emits those events before Play() returns
+// and the next song may already be on its way.
+type playbackGuard struct {
+	mu     sync.Mutex
+	seq    uint64
+	token  uint64 // token of the active play(), 0 when idle
+	active *audio.LoadResult
+}
+
+// claim takes ownership for data. It fails if another play() already owns
+// playback.
+func (g *playbackGuard) claim(data *audio.LoadResult) (uint64, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != 0 {
+		return 0, false
+	}
+	g.seq++
+	g.token = g.seq
+	g.active = data
+	return g.token, true
+}
+
+// release gives up ownership, but only if token still holds it — a play()
+// that was handed off must not clear its successor's claim.
+func (g *playbackGuard) release(token uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token == token {
+		g.token = 0
+		g.active = nil
+	}
+}
+
+// handOff ends the active claim when it belongs to videoID, letting the next
+// song claim playback. An empty videoID ends any claim (used by skip).
+func (g *playbackGuard) handOff(videoID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.active == nil || (videoID != "" && g.active.VideoID != videoID) {
+		return
+	}
+	g.token = 0
+	g.active = nil
+}
+
+// owns reports whether data is the LoadResult currently being played.
+func (g *playbackGuard) owns(data *audio.LoadResult) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.token != 0 && g.active == data
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"sync"
+	"testing"
+
+	"beatbot/audio"
+)
+
+func TestPlaybackGuardSingleOwner(t *testing.T) {
+	var g playbackGuard
+	first := &audio.LoadResult{VideoID: "a"}
+	second := &audio.LoadResult{VideoID: "a"}
+
+	token, ok := g.claim(first)
+	if !ok {
+		t.Fatal("first claim should succeed")
+	}
+	if _, ok := g.claim(second); ok {
+		t.Fatal("second claim should fail while the first is active")
+	}
+	if !g.owns(first) || g.owns(second) {
+		t.Error("owns should report only the active result")
+	}
+
+	g.release(token)
+	if _, ok := g.claim(second); !ok {
+		t.Error("claim should succeed after release")
+	}
+}
+
+func TestPlaybackGuardConcurrentClaims(t *testing.T) {
+	var g playbackGuard
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := g.claim(&audio.LoadResult{VideoID: "a"}); ok {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if winners != 1 {
+		t.Errorf("winners = %d, want exactly 1", winners)
+	}
+}
+
+func TestPlaybackGuardHandOff(t *testing.T) {
+	var g playbackGuard
+	oldToken, _ := g.claim(&audio.LoadResult{VideoID: "old"})
+
+	// A completion for a different song must not end the active claim.
+	g.handOff("other")
+	if _, ok := g.claim(&audio.LoadResult{VideoID: "next"}); ok {
+		t.Fatal("handOff for another video should not release the claim")
+	}
+
+	g.handOff("old")
+	next := &audio.LoadResult{VideoID: "next"}
+	if _, ok := g.claim(next); !ok {
+		t.Fatal("claim should succeed after handOff")
+	}
+
+	// The old play() returning late must not clear its successor.
+	g.release(oldToken)
+	if !g.owns(next) {
+		t.Error("stale release cleared the successor's claim")
+	}
+
+	g.handOff("")
+	if g.owns(next) {
+		t.Error("handOff(\"\") should end any claim")
+	}
+}
@@ -0,0 +1,19 @@
+package controller
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// volumeOffsetFor is the volume adjustment whoever queued item set for their
+// songs in /preferences.
+func (p *GuildPlayer) volumeOffsetFor(item *GuildQueueItem) int {
+	if p.DB == nil || item.Interaction == nil || item.Interaction.UserID == "" {
+		return 0
+	}
+	prefs, err := p.DB.GetUserPreferences(item.Interaction.UserID)
+	if err != nil {
+		log.Warnf("Failed to read preferences for %s: %v", item.Interaction.UserID, err)
+		return 0
+	}
+	return prefs.VolumeOffset
+}
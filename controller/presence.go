@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+)
+
+// SetLeaveHook sets what runs just before a player leaves voice on its own,
+// because its channel emptied or it sat idle, while the queue is still
+// intact. It returns how many songs it saved for /summon restore. The queue
+// format lives in handlers, so the hook is set from there.
+func (c *Controller) SetLeaveHook(hook func(p *GuildPlayer) int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.leaveHook = hook
+	for _, player := range c.sessions {
+		player.leaveHook = hook
+	}
+}
+
+// onVoiceStateUpdate re-checks who's listening whenever someone joins,
+// leaves or moves in a guild the bot has a player for. discordgo updates
+// State before handlers run, so the voice states are current.
+func (c *Controller) onVoiceStateUpdate(s *discordgo.Session, update *discordgo.VoiceStateUpdate) {
+	c.mu.RLock()
+	player, ok := c.sessions[update.GuildID]
+	c.mu.RUnlock()
+	if ok && s.State != nil && s.State.User != nil && update.UserID == s.State.User.ID {
+		player.checkMuted(update.VoiceState)
+	}
+	if ok {
+		// Follow first, so the channel the requester left doesn't count
+		// as emptied.
+		player.followRequester(update.VoiceState)
+		player.checkListeners()
+	}
+}
+
+// activeRequester is who queued the song playing, or the next one when
+// nothing is.
+func (p *GuildPlayer) activeRequester() string {
+	item := p.GetCurrentItem()
+	if item == nil {
+		p.Queue.Mutex.Lock()
+		if len(p.Queue.Items) > 0 {
+			item = p.Queue.Items[0]
+		}
+		p.Queue.Mutex.Unlock()
+	}
+	if item == nil || item.Interaction == nil {
+		return ""
+	}
+	return item.Interaction.UserID
+}
+
+// followRequester moves the bot after the active requester when /follow is
+// on and they switch voice channels. It doesn't leave anyone behind: the
+// bot only moves once its channel has no other listeners.
+func (p *GuildPlayer) followRequester(vs *discordgo.VoiceState) {
+	if vs == nil || vs.ChannelID == "" || !p.GetFollowRequester() {
+		return
+	}
+	vcID := p.GetVoiceChannelID()
+	if vcID == nil || *vcID == vs.ChannelID || vs.UserID != p.activeRequester() || p.listenerCount() > 0 {
+		return
+	}
+
+	log.Infof("Following %s to voice channel %s in guild %s", vs.UserID, vs.ChannelID, p.GuildID)
+	if err := p.JoinChannel(vs.ChannelID); err != nil {
+		log.Errorf("Failed to follow %s to voice channel %s: %v", vs.UserID, vs.ChannelID, err)
+		return
+	}
+
+	name := "the requester"
+	if vs.Member != nil && vs.Member.DisplayName() != "" {
+		name = vs.Member.DisplayName()
+	}
+	if textCh := p.textChannelID(); textCh != "" {
+		if _, err := p.Discord.ChannelMessageSend(textCh, fmt.Sprintf("🎧 Followed **%s** to <#%s>.", name, vs.ChannelID)); err != nil {
+			log.Errorf("Failed to send follow message: %v", err)
+		}
+	}
+}
+
+// listenerCount counts the people in the player's voice channel, leaving out
+// the bot itself and other bots.
+func (p *GuildPlayer) listenerCount() int {
+	vcID := p.GetVoiceChannelID()
+	if vcID == nil || p.Discord == nil || p.Discord.State == nil {
+		return 0
+	}
+
+	guild, err := p.Discord.State.Guild(p.GuildID)
+	if err != nil {
+		return 0
+	}
+
+	botID := ""
+	if p.Discord.State.User != nil {
+		botID = p.Discord.State.User.ID
+	}
+
+	p.Discord.State.RLock()
+	defer p.Discord.State.RUnlock()
+	count := 0
+	for _, vs := range guild.VoiceStates {
+		if vs.ChannelID != *vcID || vs.UserID == botID {
+			continue
+		}
+		if vs.Member != nil && vs.Member.User != nil && vs.Member.User.Bot {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// checkListeners pauses playback when the last listener leaves and resumes it
+// when someone comes back. If the channel stays empty for the idle timeout,
+// the player saves the queue and leaves. A pause someone asked for with
+// /pause is left alone.
+func (p *GuildPlayer) checkListeners() {
+	if p.GetVoiceChannelID() == nil {
+		p.stopEmptyTimer()
+		return
+	}
+	alone := p.listenerCount() == 0
+
+	p.presenceMu.Lock()
+	defer p.presenceMu.Unlock()
+
+	if alone {
+		if p.emptyTimer == nil {
+			timeout := time.Duration(config.Config.Options.IdleTimeoutMinutes) * time.Minute
+			log.Infof("Voice channel empty in guild %s, leaving in %v unless someone returns", p.GuildID, timeout)
+			p.emptyTimer = time.AfterFunc(timeout, p.leaveEmptyChannel)
+		}
+		if p.Player != nil && p.Player.IsPlaying() && !p.Player.IsPaused() {
+			log.Infof("Pausing guild %s: nobody is listening", p.GuildID)
+			p.autoPaused = true
+			p.Player.Pause(context.Background())
+		}
+		return
+	}
+
+	if p.emptyTimer != nil {
+		p.emptyTimer.Stop()
+		p.emptyTimer = nil
+	}
+	if p.autoPaused && !p.muted {
+		p.autoPaused = false
+		if p.Player != nil && p.Player.IsPaused() {
+			log.Infof("Resuming guild %s: a listener is back", p.GuildID)
+			p.Player.Resume(context.Background())
+		}
+	}
+}
+
+// stopEmptyTimer cancels a pending empty-channel leave.
+func (p *GuildPlayer) stopEmptyTimer() {
+	p.presenceMu.Lock()
+	defer p.presenceMu.Unlock()
+	if p.emptyTimer != nil {
+		p.emptyTimer.Stop()
+		p.emptyTimer = nil
+	}
+	p.autoPaused = false
+	p.mutePaused = false
+}
+
+// leaveEmptyChannel runs once the channel has been empty for the idle
+// timeout.
+func (p *GuildPlayer) leaveEmptyChannel() {
+	p.presenceMu.Lock()
+	p.emptyTimer = nil
+	p.presenceMu.Unlock()
+
+	// Someone may have joined while the timer fired.
+	if p.GetVoiceChannelID() == nil || p.listenerCount() > 0 {
+		return
+	}
+	log.Infof("Voice channel in guild %s stayed empty, disconnecting", p.GuildID)
+
+	message := "Everyone left the voice channel, so I did too."
+	if saved := p.saveBeforeLeave(); saved > 0 {
+		message += savedQueueNote(saved)
+	}
+	if textCh := p.textChannelID(); textCh != "" {
+		if _, err := p.Discord.ChannelMessageSend(textCh, message); err != nil {
+			log.Errorf("Failed to send empty channel message: %v", err)
+		}
+	}
+	p.Disconnect()
+}
+
+// saveBeforeLeave runs the leave hook, if any, and returns how many songs it
+// saved.
+func (p *GuildPlayer) saveBeforeLeave() int {
+	if p.leaveHook == nil {
+		return 0
+	}
+	return p.leaveHook(p)
+}
+
+func savedQueueNote(saved int) string {
+	songs := "songs"
+	if saved == 1 {
+		songs = "song"
+	}
+	return fmt.Sprintf(" Saved %d %s — `/summon restore:True` picks up where we left off.", saved, songs)
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"beatbot/audio"
+	"beatbot/config"
+)
+
+func presencePlayer(t *testing.T, voiceStates ...*discordgo.VoiceState) *GuildPlayer {
+	t.Helper()
+	orig := config.Config
+	t.Cleanup(func() { config.Config = orig })
+	config.Config = &config.ConfigStruct{Options: config.Options{IdleTimeoutMinutes: 20}}
+
+	state := discordgo.NewState()
+	state.User = &discordgo.User{ID: "bot"}
+	if err := state.GuildAdd(&discordgo.Guild{ID: "g1", VoiceStates: voiceStates}); err != nil {
+		t.Fatal(err)
+	}
+	channelID := "vc1"
+	p := &GuildPlayer{
+		Discord:        &discordgo.Session{State: state, StateEnabled: true},
+		GuildID:        "g1",
+		VoiceChannelID: &channelID,
+	}
+	t.Cleanup(p.stopEmptyTimer)
+	return p
+}
+
+func TestListenerCount(t *testing.T) {
+	p := presencePlayer(t,
+		&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"},
+		&discordgo.VoiceState{UserID: "ana", ChannelID: "vc1"},
+		&discordgo.VoiceState{UserID: "otherbot", ChannelID: "vc1", Member: &discordgo.Member{User: &discordgo.User{ID: "otherbot", Bot: true}}},
+		&discordgo.VoiceState{UserID: "bo", ChannelID: "vc2"},
+	)
+	if got := p.listenerCount(); got != 1 {
+		t.Errorf("listenerCount = %d, want 1", got)
+	}
+}
+
+func TestCheckListenersEmptyTimer(t *testing.T) {
+	p := presencePlayer(t, &discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"})
+
+	p.checkListeners()
+	if p.emptyTimer == nil {
+		t.Fatal("no leave timer once the channel emptied")
+	}
+
+	// Someone joining cancels the pending leave.
+	if err := p.Discord.State.OnInterface(p.Discord, &discordgo.VoiceStateUpdate{
+		VoiceState: &discordgo.VoiceState{GuildID: "g1", UserID: "ana", ChannelID: "vc1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	p.checkListeners()
+	if p.emptyTimer != nil {
+		t.Error("leave timer still set with a listener back")
+	}
+}
+
+func TestFollowRequesterStaysWithListeners(t *testing.T) {
+	p := presencePlayer(t,
+		&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"},
+		&discordgo.VoiceState{UserID: "bo", ChannelID: "vc1"},
+		&discordgo.VoiceState{UserID: "ana", ChannelID: "vc2"},
+	)
+	p.Queue = &GuildQueue{}
+	p.SetFollowRequester(true)
+	p.CurrentItem = &GuildQueueItem{Interaction: &GuildQueueItemInteraction{UserID: "ana"}}
+
+	if got := p.activeRequester(); got != "ana" {
+		t.Fatalf("activeRequester = %q, want ana", got)
+	}
+
+	// Neither moves the bot: bo isn't the requester, and bo is still
+	// listening when ana moves. (A move would need a real voice connection.)
+	p.followRequester(&discordgo.VoiceState{UserID: "bo", ChannelID: "vc3"})
+	p.followRequester(&discordgo.VoiceState{UserID: "ana", ChannelID: "vc2"})
+	if got := *p.GetVoiceChannelID(); got != "vc1" {
+		t.Errorf("bot moved to %s", got)
+	}
+}
+
+func TestActiveRequesterFallsBackToQueue(t *testing.T) {
+	p := &GuildPlayer{Queue: &GuildQueue{Items: []*GuildQueueItem{
+		{Interaction: &GuildQueueItemInteraction{UserID: "cy"}},
+	}}}
+	if got := p.activeRequester(); got != "cy" {
+		t.Errorf("activeRequester = %q, want cy", got)
+	}
+	p.Queue.Items = nil
+	if got := p.activeRequester(); got != "" {
+		t.Errorf("activeRequester with nothing queued = %q", got)
+	}
+}
+
+func TestShouldJoinVoiceBusyElsewhere(t *testing.T) {
+	p := presencePlayer(t,
+		&discordgo.VoiceState{UserID: "bot", ChannelID: "vc1"},
+		&discordgo.VoiceState{UserID: "bo", ChannelID: "vc1"},
+	)
+	player, err := audio.NewPlayer()
+	if err != nil {
+		t.Fatalf("NewPlayer: %v", err)
+	}
+	player.SetPlaying(true)
+	p.Player = player
+	p.Queue = &GuildQueue{}
+	p.VoiceConnection = &discordgo.VoiceConnection{}
+
+	if p.ShouldJoinVoice("vc2") {
+		t.Error("moved away from a listener mid-song")
+	}
+
+	// Once bo leaves, whoever asks next gets the bot.
+	if err := p.Discord.State.OnInterface(p.Discord, &discordgo.VoiceStateUpdate{
+		VoiceState: &discordgo.VoiceState{GuildID: "g1", UserID: "bo"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !p.ShouldJoinVoice("vc2") {
+		t.Error("stayed in an empty channel while someone elsewhere asked")
+	}
+}
@@ -0,0 +1,69 @@
+package controller
+
+import "time"
+
+// Duration returns the best known length of the item, preferring the ffprobe
+// result > loader result > YouTube metadata. Returns 0 when nothing is known
+// yet (e.g. a playlist entry YouTube didn't report a duration for).
+func (item *GuildQueueItem) Duration() time.Duration {
+	if item.ProbedDuration > 0 {
+		return item.ProbedDuration
+	}
+	if item.LoadResult != nil && item.LoadResult.Duration > 0 {
+		return item.LoadResult.Duration
+	}
+	return item.Video.Duration
+}
+
+// QueueETA is the estimated time until a queued item starts playing.
+// Approximate is set when an item ahead of it has an unknown duration, so the
+// real wait is at least Start.
+type QueueETA struct {
+	Start       time.Duration
+	Approximate bool
+}
+
+// EstimateQueueStarts returns a start estimate for each item, assuming
+// `remaining` is left on the current song and the queue plays in order.
+// Unknown durations count as zero and mark every later estimate as approximate.
+func EstimateQueueStarts(remaining time.Duration, items []*GuildQueueItem) []QueueETA {
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	etas := make([]QueueETA, len(items))
+	elapsed := remaining
+	approximate := false
+	for i, item := range items {
+		etas[i] = QueueETA{Start: elapsed, Approximate: approximate}
+		d := item.Duration()
+		if d <= 0 {
+			approximate = true
+		}
+		elapsed += d
+	}
+	return etas
+}
+
+// QueueStartEstimates returns start estimates for a window of items from the
+// front of the queue (e.g. Queue.PeekRange(0, n)) along with the total
+// remaining playtime (current song + everything queued, not just the window).
+func (p *GuildPlayer) QueueStartEstimates(items []*GuildQueueItem) ([]QueueETA, time.Duration) {
+	var remaining time.Duration
+	currentUnknown := false
+	if current := p.GetCurrentItem(); current != nil && p.Player != nil {
+		if d := current.Duration(); d > 0 {
+			remaining = max(d-p.Player.GetPosition(), 0)
+		} else {
+			currentUnknown = true
+		}
+	}
+
+	etas := EstimateQueueStarts(remaining, items)
+	if currentUnknown {
+		for i := range etas {
+			etas[i].Approximate = true
+		}
+	}
+	return etas, remaining + p.Queue.Stats().TotalDuration
+}
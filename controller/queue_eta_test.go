@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"beatbot/audio"
+	"beatbot/youtube"
+)
+
+func TestGuildQueueItemDuration(t *testing.T) {
+	item := &GuildQueueItem{Video: youtube.VideoResponse{Duration: 3 * time.Minute}}
+	if got := item.Duration(); got != 3*time.Minute {
+		t.Errorf("Duration() = %v, want YouTube metadata 3m", got)
+	}
+
+	item.LoadResult = &audio.LoadResult{Duration: 3*time.Minute + 5*time.Second}
+	if got := item.Duration(); got != 3*time.Minute+5*time.Second {
+		t.Errorf("Duration() = %v, want load result 3m5s", got)
+	}
+
+	item.ProbedDuration = 3*time.Minute + 4*time.Second
+	if got := item.Duration(); got != 3*time.Minute+4*time.Second {
+		t.Errorf("Duration() = %v, want probed 3m4s", got)
+	}
+}
+
+func TestEstimateQueueStarts(t *testing.T) {
+	items := []*GuildQueueItem{
+		{Video: youtube.VideoResponse{Duration: 3 * time.Minute}},
+		{Video: youtube.VideoResponse{Duration: 4 * time.Minute}},
+		{Video: youtube.VideoResponse{}}, // unknown duration
+		{Video: youtube.VideoResponse{Duration: 2 * time.Minute}},
+	}
+
+	etas := EstimateQueueStarts(90*time.Second, items)
+
+	want := []QueueETA{
+		{Start: 90 * time.Second},
+		{Start: 4*time.Minute + 30*time.Second},
+		{Start: 8*time.Minute + 30*time.Second},
+		{Start: 8*time.Minute + 30*time.Second, Approximate: true},
+	}
+	if len(etas) != len(want) {
+		t.Fatalf("len(etas) = %d, want %d", len(etas), len(want))
+	}
+	for i := range want {
+		if etas[i] != want[i] {
+			t.Errorf("etas[%d] = %+v, want %+v", i, etas[i], want[i])
+		}
+	}
+}
+
+func TestEstimateQueueStartsNegativeRemaining(t *testing.T) {
+	items := []*GuildQueueItem{{Video: youtube.VideoResponse{Duration: time.Minute}}}
+
+	etas := EstimateQueueStarts(-5*time.Second, items)
+	if etas[0].Start != 0 {
+		t.Errorf("Start = %v, want 0 when position overshoots duration", etas[0].Start)
+	}
+}
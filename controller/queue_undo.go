@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/gemini"
+	"beatbot/sentryhelper"
+)
+
+// UndoWindow is how long after a destructive queue operation /undo can
+// still restore the previous queue.
+const UndoWindow = 60 * time.Second
+
+// Undo actions, reported back to the caller so it can say what was undone.
+const (
+	UndoClear   = "clear"
+	UndoRemove  = "remove"
+	UndoShuffle = "shuffle"
+)
+
+// queueSnapshot is a one-level copy of the queue taken right before a
+// destructive operation.
+type queueSnapshot struct {
+	action  string
+	items   []*GuildQueueItem
+	dropped map[*GuildQueueItem]bool // items the operation itself removed
+	takenAt time.Time
+}
+
+// snapshotQueue records the queue before action runs, replacing any older
+// snapshot. dropped lists the items the action is about to remove. Caller
+// holds Queue.Mutex.
+func (p *GuildPlayer) snapshotQueue(action string, dropped []*GuildQueueItem) {
+	snap := &queueSnapshot{
+		action:  action,
+		items:   make([]*GuildQueueItem, len(p.Queue.Items)),
+		dropped: make(map[*GuildQueueItem]bool, len(dropped)),
+		takenAt: time.Now(),
+	}
+	copy(snap.items, p.Queue.Items)
+	for _, item := range dropped {
+		snap.dropped[item] = true
+	}
+
+	p.undoMu.Lock()
+	p.undoSnapshot = snap
+	p.undoMu.Unlock()
+}
+
+// discardUndo forgets the pending snapshot, e.g. when the player resets.
+func (p *GuildPlayer) discardUndo() {
+	p.undoMu.Lock()
+	p.undoSnapshot = nil
+	p.undoMu.Unlock()
+}
+
+// Undo restores the queue as it was before the last /clear, /remove range or
+// /shuffle, if that happened within UndoWindow. Songs that finished playing
+// since are not brought back, and songs queued since stay at the end.
+// Returns the undone action ("" when there is nothing to undo) and how many
+// songs were put back in their old place.
+func (p *GuildPlayer) Undo() (string, int) {
+	p.undoMu.Lock()
+	snap := p.undoSnapshot
+	p.undoSnapshot = nil
+	p.undoMu.Unlock()
+
+	if snap == nil || time.Since(snap.takenAt) > UndoWindow {
+		return "", 0
+	}
+
+	p.Queue.Mutex.Lock()
+	present := make(map[*GuildQueueItem]bool, len(p.Queue.Items))
+	for _, item := range p.Queue.Items {
+		present[item] = true
+	}
+	inSnapshot := make(map[*GuildQueueItem]bool, len(snap.items))
+	for _, item := range snap.items {
+		inSnapshot[item] = true
+	}
+
+	// A song the player is already starting keeps its place at the head.
+	pinned := p.Queue.Items[:p.Queue.pinned()]
+	placed := make(map[*GuildQueueItem]bool, len(pinned))
+	items := make([]*GuildQueueItem, 0, len(snap.items)+len(p.Queue.Items))
+	restoredCount := 0
+	for _, item := range pinned {
+		items = append(items, item)
+		placed[item] = true
+		if inSnapshot[item] {
+			restoredCount++
+		}
+	}
+
+	var requeued []*GuildQueueItem
+	for _, item := range snap.items {
+		switch {
+		case placed[item]:
+		case present[item]:
+			items = append(items, item)
+			restoredCount++
+		case snap.dropped[item]:
+			// Its stream lookup and preload were canceled on removal, so
+			// it goes back in as a fresh item.
+			restored := item.requeueCopy()
+			items = append(items, restored)
+			requeued = append(requeued, restored)
+			restoredCount++
+		}
+		// Otherwise it played or was removed after the snapshot.
+	}
+	for _, item := range p.Queue.Items {
+		if !inSnapshot[item] && !placed[item] {
+			items = append(items, item)
+		}
+	}
+	p.Queue.Items = items
+
+	for _, item := range requeued {
+		if p.GetTranslateTitles() && gemini.NeedsTranslation(item.Video.Title) {
+			go p.resolveTitleTranslation(item)
+		}
+		select {
+		case p.Queue.notifications <- QueueEvent{Type: EventAdd, Item: item}:
+		default:
+			msg := "Queue notifications channel is full for guild " + p.GuildID
+			sentryhelper.CaptureMessage(item.Context, msg)
+			log.Warn(msg)
+		}
+	}
+	p.Queue.Mutex.Unlock()
+
+	p.LastActivityAt = time.Now()
+	if p.playbackState != nil {
+		p.syncNextFromQueue()
+	}
+
+	return snap.action, restoredCount
+}
+
+// requeueCopy returns a fresh, unloaded copy of an item that was removed
+// from the queue, keeping who queued it and any enrichment. TranslatedTitle
+// is left for resolveTitleTranslation, which has it cached by now.
+func (item *GuildQueueItem) requeueCopy() *GuildQueueItem {
+	itemCtx, cancelLoad := context.WithCancel(sentryhelper.DetachFromTransaction(context.Background()))
+	return &GuildQueueItem{
+		Video:          item.Video,
+		ProbedDuration: item.ProbedDuration,
+		AddedAt:        item.AddedAt,
+		streamReady:    make(chan struct{}),
+		Interaction:    item.Interaction,
+		MaxAttempts:    3,
+		Context:        itemCtx,
+		cancelLoad:     cancelLoad,
+		Commentary:     item.Commentary,
+		IsRadioPick:    item.IsRadioPick,
+		IsDJPick:       item.IsDJPick,
+		FallbackVideos: item.FallbackVideos,
+		DeezerMeta:     item.DeezerMeta,
+	}
+}
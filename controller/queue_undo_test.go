@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"beatbot/youtube"
+)
+
+func newUndoTestPlayer(ids ...string) *GuildPlayer {
+	player := &GuildPlayer{
+		Queue:         &GuildQueue{notifications: make(chan QueueEvent, 100)},
+		playbackState: newPlaybackState(),
+	}
+	for _, id := range ids {
+		player.Add(context.Background(), youtube.VideoResponse{VideoID: id, Title: id}, "user", "", "", nil)
+	}
+	return player
+}
+
+func queueIDs(p *GuildPlayer) string {
+	var out []string
+	for _, item := range p.GetQueueSnapshot() {
+		out = append(out, item.Video.VideoID)
+	}
+	return fmt.Sprint(out)
+}
+
+func TestUndoClear(t *testing.T) {
+	player := newUndoTestPlayer("a", "b", "c")
+	player.Clear()
+	player.Add(context.Background(), youtube.VideoResponse{VideoID: "d", Title: "d"}, "user", "", "", nil)
+
+	action, n := player.Undo()
+	if action != UndoClear || n != 3 {
+		t.Errorf("Undo() = (%q, %d), want (clear, 3)", action, n)
+	}
+	if got := queueIDs(player); got != "[a b c d]" {
+		t.Errorf("queue = %s, want [a b c d]", got)
+	}
+	for _, item := range player.GetQueueSnapshot() {
+		if item.Context.Err() != nil {
+			t.Errorf("restored %s should have a live context", item.Video.VideoID)
+		}
+	}
+
+	if action, _ := player.Undo(); action != "" {
+		t.Errorf("second Undo() = %q, want nothing to undo", action)
+	}
+}
+
+func TestUndoRemoveRange(t *testing.T) {
+	player := newUndoTestPlayer("a", "b", "c", "d", "e")
+
+	if got := player.RemoveRange(2, 4); got != 3 {
+		t.Fatalf("RemoveRange(2, 4) = %d, want 3", got)
+	}
+	if got := queueIDs(player); got != "[a e]" {
+		t.Fatalf("queue after remove = %s, want [a e]", got)
+	}
+
+	if action, n := player.Undo(); action != UndoRemove || n != 5 {
+		t.Errorf("Undo() = (%q, %d), want (remove, 5)", action, n)
+	}
+	if got := queueIDs(player); got != "[a b c d e]" {
+		t.Errorf("queue = %s, want [a b c d e]", got)
+	}
+
+	if got := player.RemoveRange(3, 9); got != 0 {
+		t.Errorf("RemoveRange out of bounds = %d, want 0", got)
+	}
+}
+
+func TestUndoShuffleSkipsPlayedSongs(t *testing.T) {
+	player := newUndoTestPlayer("a", "b", "c", "d")
+	player.Shuffle()
+
+	// Simulate the head finishing before /undo.
+	player.Queue.Mutex.Lock()
+	played := player.Queue.Items[0].Video.VideoID
+	player.Queue.Items = player.Queue.Items[1:]
+	player.Queue.Mutex.Unlock()
+
+	action, n := player.Undo()
+	if action != UndoShuffle || n != 3 {
+		t.Errorf("Undo() = (%q, %d), want (shuffle, 3)", action, n)
+	}
+	want := []string{}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if id != played {
+			want = append(want, id)
+		}
+	}
+	if got := queueIDs(player); got != fmt.Sprint(want) {
+		t.Errorf("queue = %s, want %v", got, want)
+	}
+}
+
+func TestUndoExpires(t *testing.T) {
+	player := newUndoTestPlayer("a", "b")
+	player.Clear()
+
+	player.undoMu.Lock()
+	player.undoSnapshot.takenAt = time.Now().Add(-UndoWindow - time.Second)
+	player.undoMu.Unlock()
+
+	if action, _ := player.Undo(); action != "" {
+		t.Errorf("Undo() after window = %q, want nothing to undo", action)
+	}
+	if !player.IsEmpty() {
+		t.Error("expired undo should leave the queue alone")
+	}
+}
@@ -0,0 +1,64 @@
+package controller
+
+import "time"
+
+// QueueStats summarizes the queued (not yet playing) items.
+type QueueStats struct {
+	Count            int
+	TotalDuration    time.Duration  // sum of known durations
+	UnknownDurations int            // items with no duration yet; TotalDuration undercounts by these
+	RadioPicks       int            // items auto-queued by radio mode
+	PerUser          map[string]int // requester user ID -> queued items (radio picks excluded)
+}
+
+// Len returns the number of queued items.
+func (q *GuildQueue) Len() int {
+	q.Mutex.Lock()
+	defer q.Mutex.Unlock()
+	return len(q.Items)
+}
+
+// PeekRange returns up to limit items starting at offset without copying the
+// whole queue. A negative offset counts from the tail, so PeekRange(-3, 3)
+// is the last three items. Out-of-range windows are clamped; an empty window
+// returns nil. The returned slice is a copy and safe to use without the lock.
+func (q *GuildQueue) PeekRange(offset, limit int) []*GuildQueueItem {
+	q.Mutex.Lock()
+	defer q.Mutex.Unlock()
+
+	n := len(q.Items)
+	if offset < 0 {
+		offset = max(n+offset, 0)
+	}
+	if limit <= 0 || offset >= n {
+		return nil
+	}
+	end := min(offset+limit, n)
+
+	window := make([]*GuildQueueItem, end-offset)
+	copy(window, q.Items[offset:end])
+	return window
+}
+
+// Stats computes queue totals under the queue mutex in a single pass.
+func (q *GuildQueue) Stats() QueueStats {
+	q.Mutex.Lock()
+	defer q.Mutex.Unlock()
+
+	stats := QueueStats{Count: len(q.Items), PerUser: make(map[string]int)}
+	for _, item := range q.Items {
+		if d := item.Duration(); d > 0 {
+			stats.TotalDuration += d
+		} else {
+			stats.UnknownDurations++
+		}
+		if item.IsRadioPick {
+			stats.RadioPicks++
+			continue
+		}
+		if userID := item.requesterID(); userID != "" {
+			stats.PerUser[userID]++
+		}
+	}
+	return stats
+}
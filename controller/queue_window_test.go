@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"beatbot/youtube"
+)
+
+func newWindowTestQueue(n int) *GuildQueue {
+	q := &GuildQueue{}
+	for i := range n {
+		q.Items = append(q.Items, &GuildQueueItem{Video: youtube.VideoResponse{VideoID: string(rune('a' + i))}})
+	}
+	return q
+}
+
+func TestPeekRange(t *testing.T) {
+	q := newWindowTestQueue(5)
+
+	tests := []struct {
+		name          string
+		offset, limit int
+		wantFirst     string
+		wantLen       int
+	}{
+		{"front window", 0, 2, "a", 2},
+		{"middle window", 2, 2, "c", 2},
+		{"clamped to end", 3, 10, "d", 2},
+		{"offset past end", 5, 2, "", 0},
+		{"tail via negative offset", -2, 2, "d", 2},
+		{"negative offset beyond head", -10, 1, "a", 1},
+		{"zero limit", 0, 0, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window := q.PeekRange(tt.offset, tt.limit)
+			if len(window) != tt.wantLen {
+				t.Fatalf("PeekRange(%d, %d) len = %d, want %d", tt.offset, tt.limit, len(window), tt.wantLen)
+			}
+			if tt.wantLen > 0 && window[0].Video.VideoID != tt.wantFirst {
+				t.Errorf("PeekRange(%d, %d)[0] = %q, want %q", tt.offset, tt.limit, window[0].Video.VideoID, tt.wantFirst)
+			}
+		})
+	}
+
+	// The window is a copy; writing to it must not touch the queue.
+	window := q.PeekRange(0, 1)
+	window[0] = nil
+	if q.Items[0] == nil {
+		t.Error("PeekRange should return a copy, not a view into Items")
+	}
+}
+
+func TestQueueStats(t *testing.T) {
+	q := &GuildQueue{Items: []*GuildQueueItem{
+		{Video: youtube.VideoResponse{Duration: 3 * time.Minute}, Interaction: &GuildQueueItemInteraction{UserID: "alice"}},
+		{Video: youtube.VideoResponse{Duration: 2 * time.Minute}, Interaction: &GuildQueueItemInteraction{UserID: "alice"}},
+		{Video: youtube.VideoResponse{}, Interaction: &GuildQueueItemInteraction{UserID: "bob"}},
+		{Video: youtube.VideoResponse{Duration: time.Minute}, IsRadioPick: true},
+	}}
+
+	stats := q.Stats()
+	if stats.Count != 4 {
+		t.Errorf("Count = %d, want 4", stats.Count)
+	}
+	if stats.TotalDuration != 6*time.Minute {
+		t.Errorf("TotalDuration = %v, want 6m", stats.TotalDuration)
+	}
+	if stats.UnknownDurations != 1 {
+		t.Errorf("UnknownDurations = %d, want 1", stats.UnknownDurations)
+	}
+	if stats.RadioPicks != 1 {
+		t.Errorf("RadioPicks = %d, want 1", stats.RadioPicks)
+	}
+	if stats.PerUser["alice"] != 2 || stats.PerUser["bob"] != 1 || len(stats.PerUser) != 2 {
+		t.Errorf("PerUser = %v, want alice:2 bob:1", stats.PerUser)
+	}
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// reactionControlsSetting is the guild setting ("true"/"false") for adding
+// playback reactions to now-playing cards.
+const reactionControlsSetting = "reaction_controls"
+
+// controlReactions are the reactions put on now-playing cards, in order,
+// and the card button each one stands in for.
+var controlReactions = []struct{ emoji, action string }{
+	{"⏯️", "playpause"},
+	{"⏭️", "skip"},
+	{"⏹️", "stop"},
+}
+
+// variationSelector asks for an emoji's color presentation.
+const variationSelector = "\uFE0F"
+
+// ReactionAction returns the button action a reaction on a now-playing card
+// stands in for. Discord may send the emoji without its variation selector,
+// so that's ignored.
+func ReactionAction(emoji string) (action string, ok bool) {
+	emoji = strings.TrimSuffix(emoji, variationSelector)
+	for _, r := range controlReactions {
+		if strings.TrimSuffix(r.emoji, variationSelector) == emoji {
+			return r.action, true
+		}
+	}
+	return "", false
+}
+
+// addControlReactions puts the playback reactions on a now-playing card,
+// so playback can be controlled without typing a command. Needs the Add Reactions
+// permission; without it the card just goes without.
+func (p *GuildPlayer) addControlReactions(channelID, messageID string) {
+	if p.Discord == nil {
+		return
+	}
+	for _, r := range controlReactions {
+		if err := p.Discord.MessageReactionAdd(channelID, messageID, r.emoji); err != nil {
+			log.Warnf("Failed to add control reactions to %s: %v", messageID, err)
+			return
+		}
+	}
+}
+
+// IsNowPlayingMessage reports whether messageID is the current now-playing
+// card.
+func (p *GuildPlayer) IsNowPlayingMessage(messageID string) bool {
+	p.nowPlayingMutex.Lock()
+	defer p.nowPlayingMutex.Unlock()
+	return p.NowPlayingMessageID != nil && *p.NowPlayingMessageID == messageID
+}
+
+// RemoveReaction takes userID's reaction off a message so the same control
+// can be used again. Needs Manage Messages; without it people un-react and
+// react again.
+func (p *GuildPlayer) RemoveReaction(channelID, messageID, emoji, userID string) {
+	if p.Discord == nil {
+		return
+	}
+	if err := p.Discord.MessageReactionRemove(channelID, messageID, emoji, userID); err != nil {
+		log.Debugf("Couldn't remove reaction from %s: %v", messageID, err)
+	}
+}
@@ -0,0 +1,37 @@
+package controller
+
+import "testing"
+
+func TestReactionAction(t *testing.T) {
+	tests := []struct {
+		emoji  string
+		action string
+		ok     bool
+	}{
+		{"⏯️", "playpause", true},
+		{"⏯", "playpause", true},
+		{"⏭️", "skip", true},
+		{"⏹️", "stop", true},
+		{"⏹", "stop", true},
+		{"👍", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		action, ok := ReactionAction(tt.emoji)
+		if action != tt.action || ok != tt.ok {
+			t.Errorf("ReactionAction(%q) = (%q, %v), want (%q, %v)", tt.emoji, action, ok, tt.action, tt.ok)
+		}
+	}
+}
+
+func TestIsNowPlayingMessage(t *testing.T) {
+	p := &GuildPlayer{}
+	if p.IsNowPlayingMessage("1") {
+		t.Error("matched with no card posted")
+	}
+	id := "1"
+	p.NowPlayingMessageID = &id
+	if !p.IsNowPlayingMessage("1") || p.IsNowPlayingMessage("2") {
+		t.Error("IsNowPlayingMessage didn't match only the posted card")
+	}
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// sessionThreadSetting is the guild setting ("true"/"false") for giving each
+// listening session its own thread; see openSessionThread.
+const sessionThreadSetting = "session_threads"
+
+// sessionThreadArchive is how long, in minutes, a session thread may sit
+// quiet before Discord archives it on its own, should the bot never get to.
+const sessionThreadArchive = 1440
+
+// threadNameLimit is the longest thread name Discord accepts.
+const threadNameLimit = 100
+
+// openSessionThread starts a thread for this listening session under the
+// text channel the bot would otherwise post in, named after the song that
+// started it. Now-playing cards, queue updates and commentary go there until
+// the session ends, keeping the channel itself clean. If the thread can't be
+// made (no Create Public Threads permission, a voice channel's chat), the
+// session posts in the channel as before.
+func (p *GuildPlayer) openSessionThread(first *GuildQueueItem) {
+	if !p.GetSessionThreads() || p.Discord == nil {
+		return
+	}
+	p.sessionThreadMu.Lock()
+	defer p.sessionThreadMu.Unlock()
+	if p.sessionThreadID != "" || p.sessionThreadTried {
+		return
+	}
+	p.sessionThreadTried = true
+
+	parentID := p.threadParentID()
+	if parentID == "" {
+		return
+	}
+	thread, err := p.Discord.ThreadStart(parentID, sessionThreadName(first), discordgo.ChannelTypeGuildPublicThread, sessionThreadArchive)
+	if err != nil {
+		log.Warnf("Couldn't start a session thread in %s, posting in the channel: %v", parentID, err)
+		return
+	}
+	p.sessionThreadID = thread.ID
+}
+
+// threadParentID is the channel a session thread goes under: where the bot
+// posts, or that channel's parent when it's a thread itself, such as the
+// last session's.
+func (p *GuildPlayer) threadParentID() string {
+	channelID := p.baseTextChannelID()
+	if channelID == "" {
+		return ""
+	}
+	channel, err := p.Discord.State.Channel(channelID)
+	if err != nil {
+		if channel, err = p.Discord.Channel(channelID); err != nil {
+			log.Warnf("Couldn't look up channel %s for a session thread: %v", channelID, err)
+			return ""
+		}
+	}
+	if channel.IsThread() {
+		return channel.ParentID
+	}
+	return channelID
+}
+
+// closeSessionThread says goodbye in the session's thread and archives it.
+// The next session starts a new one.
+func (p *GuildPlayer) closeSessionThread() {
+	p.sessionThreadMu.Lock()
+	threadID := p.sessionThreadID
+	p.sessionThreadID = ""
+	p.sessionThreadTried = false
+	p.sessionThreadMu.Unlock()
+
+	if threadID == "" || p.Discord == nil {
+		return
+	}
+	if _, err := p.Discord.ChannelMessageSend(threadID, "👋 Session over — see you next time!"); err != nil {
+		log.Warnf("Failed to post in session thread %s: %v", threadID, err)
+	}
+	archived := true
+	if _, err := p.Discord.ChannelEdit(threadID, &discordgo.ChannelEdit{Archived: &archived}); err != nil {
+		log.Warnf("Failed to archive session thread %s: %v", threadID, err)
+	}
+}
+
+// sessionThread returns the open session thread's ID, or "".
+func (p *GuildPlayer) sessionThread() string {
+	p.sessionThreadMu.Lock()
+	defer p.sessionThreadMu.Unlock()
+	return p.sessionThreadID
+}
+
+func sessionThreadName(first *GuildQueueItem) string {
+	name := "🎶 Listening session"
+	if first != nil && first.Video.Title != "" {
+		name += " · " + first.Video.Title
+	}
+	if runes := []rune(name); len(runes) > threadNameLimit {
+		name = string(runes[:threadNameLimit-1]) + "…"
+	}
+	return name
+}
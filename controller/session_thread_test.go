@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"beatbot/youtube"
+)
+
+func TestSessionThreadRouting(t *testing.T) {
+	p := &GuildPlayer{GuildID: "g1"}
+	p.SetLastTextChannelID("last")
+	if got := p.announceChannelID(); got != "" {
+		t.Errorf("announceChannelID without a music channel = %q, want none", got)
+	}
+
+	p.SetMusicChannelID("music")
+	p.sessionThreadID = "thread"
+	if got := p.textChannelID(); got != "thread" {
+		t.Errorf("textChannelID = %q, want the session thread", got)
+	}
+	if got := p.announceChannelID(); got != "thread" {
+		t.Errorf("announceChannelID = %q, want the session thread", got)
+	}
+	if got := p.baseTextChannelID(); got != "music" {
+		t.Errorf("baseTextChannelID = %q, want the music channel", got)
+	}
+
+	// Closing without a session (no Discord) just forgets the thread.
+	p.sessionThreadTried = true
+	p.closeSessionThread()
+	if got := p.textChannelID(); got != "music" {
+		t.Errorf("textChannelID after the session = %q, want the music channel", got)
+	}
+	if p.sessionThreadTried {
+		t.Error("the next session should try to open a thread again")
+	}
+}
+
+func TestSessionThreadName(t *testing.T) {
+	if got := sessionThreadName(nil); got != "🎶 Listening session" {
+		t.Errorf("name without a song = %q", got)
+	}
+	item := &GuildQueueItem{Video: youtube.VideoResponse{Title: strings.Repeat("x", 200)}}
+	got := sessionThreadName(item)
+	if n := utf8.RuneCountInString(got); n != threadNameLimit || !strings.HasSuffix(got, "…") {
+		t.Errorf("long name has %d runes (%q), want %d ending in …", n, got, threadNameLimit)
+	}
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"errors"
+
+	"beatbot/database"
+)
+
+// ErrNotInVoice means the bot has no voice connection in the guild.
+var ErrNotInVoice = errors.New("not in a voice channel")
+
+// PlaySound plays a soundboard sound in the bot's voice channel, over the
+// current song with the song ducked under it, or on its own when nothing is
+// loaded. Returns once the sound is mixed in or, on its own, has finished.
+func (p *GuildPlayer) PlaySound(sound *database.Sound) error {
+	p.VoiceChannelMutex.RLock()
+	vc := p.VoiceConnection
+	p.VoiceChannelMutex.RUnlock()
+	if vc == nil {
+		return ErrNotInVoice
+	}
+	if p.DB == nil {
+		return errors.New("no database")
+	}
+	samples, err := p.DB.LoadSound(sound)
+	if err != nil {
+		return err
+	}
+	return p.Player.PlaySound(samples, vc)
+}
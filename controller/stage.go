@@ -0,0 +1,36 @@
+package controller
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+)
+
+// takeStage asks to speak after joining a Stage channel. Bots join stages
+// as suppressed audience members, and suppressed audio is dropped, so
+// without this playback goes nowhere.
+func (p *GuildPlayer) takeStage(channelID string) {
+	if !discord.IsStageChannel(p.Discord, channelID) {
+		return
+	}
+
+	speaking, err := discord.BecomeStageSpeaker(p.Discord, p.GuildID, channelID)
+	if err != nil {
+		log.Errorf("Failed to become a speaker on stage %s in guild %s: %v", channelID, p.GuildID, err)
+		p.sendStageNote("⚠️ I couldn't get on stage in <#" + channelID + ">, so nobody will hear me. Invite me to speak, or give me the Mute Members permission.")
+		return
+	}
+	if !speaking {
+		p.sendStageNote("✋ I've asked to speak in <#" + channelID + ">. A stage moderator needs to accept before anyone can hear me — or give me the Mute Members permission to skip this.")
+	}
+}
+
+func (p *GuildPlayer) sendStageNote(message string) {
+	textCh := p.textChannelID()
+	if textCh == "" {
+		return
+	}
+	if _, err := p.Discord.ChannelMessageSend(textCh, message); err != nil {
+		log.Errorf("Failed to send stage message: %v", err)
+	}
+}
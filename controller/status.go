@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+)
+
+// statusDelay batches track changes (a skip is a stop and a start) into one
+// presence update. Discord only allows a few per minute.
+const statusDelay = 3 * time.Second
+
+// statusNameLimit is the longest activity name Discord shows.
+const statusNameLimit = 128
+
+// botStatus keeps the bot's "Listening to ..." presence in step with what
+// its guilds are playing. One is shared by every player.
+type botStatus struct {
+	session *discordgo.Session
+	mode    string
+
+	mu      sync.Mutex
+	playing map[string]string // guild ID -> title of the track playing there
+	latest  string            // guild whose track started most recently
+	shown   string            // activity name last sent; "" is no activity
+	sent    bool              // shown reached Discord
+	timer   *time.Timer
+}
+
+func newBotStatus(session *discordgo.Session, mode string) *botStatus {
+	return &botStatus{
+		session: session,
+		mode:    mode,
+		playing: make(map[string]string),
+	}
+}
+
+// trackStarted records that title began playing in guildID.
+func (s *botStatus) trackStarted(guildID, title string) {
+	if s == nil || s.mode == config.PresenceOff {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.playing[guildID] = title
+	s.latest = guildID
+	s.schedule()
+}
+
+// trackEnded records that guildID stopped playing.
+func (s *botStatus) trackEnded(guildID string) {
+	if s == nil || s.mode == config.PresenceOff {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.playing[guildID]; !ok {
+		return
+	}
+	delete(s.playing, guildID)
+	s.schedule()
+}
+
+// schedule queues an update. Callers hold s.mu.
+func (s *botStatus) schedule() {
+	if s.timer == nil {
+		s.timer = time.AfterFunc(statusDelay, s.update)
+	}
+}
+
+func (s *botStatus) update() {
+	s.mu.Lock()
+	s.timer = nil
+	name := statusName(s.mode, s.playing, s.latest)
+	if (s.sent && name == s.shown) || s.session == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.shown, s.sent = name, true
+	s.mu.Unlock()
+
+	if err := s.session.UpdateListeningStatus(name); err != nil {
+		log.Warnf("Failed to update presence: %v", err)
+		// Let the next change try again.
+		s.mu.Lock()
+		s.sent = false
+		s.mu.Unlock()
+	}
+}
+
+// statusName is the activity to show for what's playing: the most recently
+// started track, or how many servers have music on. With the latest guild
+// stopped, another playing guild's track stands in.
+func statusName(mode string, playing map[string]string, latest string) string {
+	if len(playing) == 0 {
+		return ""
+	}
+	if mode == config.PresenceGuilds {
+		if len(playing) == 1 {
+			return "music in 1 server"
+		}
+		return fmt.Sprintf("music in %d servers", len(playing))
+	}
+	title, ok := playing[latest]
+	if !ok {
+		guilds := make([]string, 0, len(playing))
+		for guildID := range playing {
+			guilds = append(guilds, guildID)
+		}
+		sort.Strings(guilds)
+		title = playing[guilds[0]]
+	}
+	if runes := []rune(title); len(runes) > statusNameLimit {
+		title = string(runes[:statusNameLimit-1]) + "…"
+	}
+	return title
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"beatbot/config"
+)
+
+func TestStatusName(t *testing.T) {
+	playing := map[string]string{"g1": "First Song", "g2": "Second Song"}
+	long := strings.Repeat("a", statusNameLimit+10)
+
+	tests := []struct {
+		name    string
+		mode    string
+		playing map[string]string
+		latest  string
+		want    string
+	}{
+		{"nothing playing", config.PresenceTrack, map[string]string{}, "g1", ""},
+		{"latest track", config.PresenceTrack, playing, "g2", "Second Song"},
+		{"latest stopped", config.PresenceTrack, playing, "g3", "First Song"},
+		{"long title", config.PresenceTrack, map[string]string{"g1": long}, "g1", long[:statusNameLimit-1] + "…"},
+		{"one server", config.PresenceGuilds, map[string]string{"g1": "x"}, "g1", "music in 1 server"},
+		{"servers", config.PresenceGuilds, playing, "g1", "music in 2 servers"},
+		{"no servers", config.PresenceGuilds, map[string]string{}, "", ""},
+	}
+	for _, tt := range tests {
+		if got := statusName(tt.mode, tt.playing, tt.latest); got != tt.want {
+			t.Errorf("%s: statusName = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBotStatusTracksGuilds(t *testing.T) {
+	s := newBotStatus(nil, config.PresenceTrack)
+	s.trackStarted("g1", "First Song")
+	s.trackStarted("g2", "Second Song")
+	s.trackEnded("g2")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.playing) != 1 || s.playing["g1"] != "First Song" {
+		t.Errorf("playing = %v, want only g1", s.playing)
+	}
+	if s.timer == nil {
+		t.Error("expected an update to be scheduled")
+	} else {
+		s.timer.Stop()
+	}
+
+	off := newBotStatus(nil, config.PresenceOff)
+	off.trackStarted("g1", "First Song")
+	if len(off.playing) != 0 || off.timer != nil {
+		t.Error("presence off should ignore tracks")
+	}
+
+	var none *botStatus
+	none.trackStarted("g1", "First Song")
+	none.trackEnded("g1")
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/database"
+	"beatbot/discord"
+)
+
+// NotifyUpNextSetting is the user setting, "true" or "false", for a DM when
+// a song they queued is next; see /notifyme.
+const NotifyUpNextSetting = database.PrefNotifyUpNext
+
+// WantsUpNextDM reports whether userID asked to be told when their song is
+// next.
+func (p *GuildPlayer) WantsUpNextDM(userID string) bool {
+	if p.DB == nil || userID == "" {
+		return false
+	}
+	value, err := p.DB.GetUserSetting(userID, NotifyUpNextSetting)
+	if err != nil {
+		log.Warnf("Failed to read %s for %s: %v", NotifyUpNextSetting, userID, err)
+		return false
+	}
+	enabled, _ := strconv.ParseBool(value)
+	return enabled
+}
+
+// notifyUpNext DMs whoever queued the song after current, if they opted in,
+// so they can get back to the channel before it plays. Each song is
+// announced once, and not to someone whose own song is playing now.
+func (p *GuildPlayer) notifyUpNext(current *GuildQueueItem) {
+	next := p.GetNext()
+	if next == nil || next.Interaction == nil || next.Interaction.UserID == "" {
+		return
+	}
+	userID := next.Interaction.UserID
+	if current != nil && current.Interaction != nil && current.Interaction.UserID == userID {
+		return
+	}
+	if !next.upNextNotified.CompareAndSwap(false, true) || !p.WantsUpNextDM(userID) {
+		return
+	}
+
+	msg := "🎵 **" + next.Video.Title + "** is up next in **" + p.getGuildName() + "**"
+	if channelID := p.GetVoiceChannelID(); channelID != nil {
+		msg += " — head to <#" + *channelID + "> to hear it"
+	}
+	if _, err := discord.SendDirectMessage(userID, msg, nil); err != nil {
+		if errors.Is(err, discord.ErrDMsClosed) {
+			log.Debugf("User %s has DMs closed, skipping up-next notice", userID)
+			return
+		}
+		log.Warnf("Failed to send up-next notice to %s: %v", userID, err)
+	}
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"path/filepath"
+	"testing"
+
+	"beatbot/database"
+)
+
+func TestNotifyUpNextSkips(t *testing.T) {
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := database.New()
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	defer db.Close()
+
+	p := &GuildPlayer{GuildID: "g1", DB: db, Queue: &GuildQueue{}}
+	if p.WantsUpNextDM("u1") {
+		t.Error("users start opted out")
+	}
+	if err := db.SetUserSetting("u1", NotifyUpNextSetting, "true"); err != nil {
+		t.Fatalf("SetUserSetting: %v", err)
+	}
+	if !p.WantsUpNextDM("u1") {
+		t.Error("opted-in user should want the DM")
+	}
+
+	current := &GuildQueueItem{Interaction: &GuildQueueItemInteraction{UserID: "u1"}}
+	mine := &GuildQueueItem{Interaction: &GuildQueueItemInteraction{UserID: "u1"}}
+	radio := &GuildQueueItem{}
+	p.Queue.Items = []*GuildQueueItem{mine}
+
+	// Their own song is playing, so they're already listening.
+	p.notifyUpNext(current)
+	if mine.upNextNotified.Load() {
+		t.Error("requester of the current song was notified about their next one")
+	}
+
+	// A radio pick has nobody to notify.
+	p.Queue.Items = []*GuildQueueItem{radio}
+	p.notifyUpNext(nil)
+	if radio.upNextNotified.Load() {
+		t.Error("radio pick was marked notified")
+	}
+
+	// Opted-out requesters are only marked, so the check isn't repeated.
+	other := &GuildQueueItem{Interaction: &GuildQueueItemInteraction{UserID: "u2"}}
+	p.Queue.Items = []*GuildQueueItem{other}
+	p.notifyUpNext(current)
+	if !other.upNextNotified.Load() {
+		t.Error("next song wasn't marked as handled")
+	}
+}
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/youtube"
+)
+
+// voiceSessionSetting is the guild setting SaveVoiceSessions stores a
+// playing session under until the next start picks it back up.
+const voiceSessionSetting = "voice_session"
+
+// voiceSessionMaxAge is how long a saved session is worth restoring. After
+// a longer outage the listeners have likely moved on, and the bot turning
+// up with music would be a surprise rather than a seamless deploy.
+const voiceSessionMaxAge = 10 * time.Minute
+
+// voiceSessionGuildWait bounds how long a restore waits for the gateway to
+// deliver the guild.
+const voiceSessionGuildWait = 10 * time.Second
+
+// savedVoiceSession is where a player was when the bot shut down.
+type savedVoiceSession struct {
+	VoiceChannelID string            `json:"voice_channel_id"`
+	TextChannelID  string            `json:"text_channel_id,omitempty"`
+	Played         time.Duration     `json:"played"` // how far the current song (Tracks[0]) got
+	SavedAt        time.Time         `json:"saved_at"`
+	Tracks         []savedVoiceTrack `json:"tracks"` // the current song, then the queue
+}
+
+type savedVoiceTrack struct {
+	Video     youtube.VideoResponse `json:"video"`
+	UserID    string                `json:"user_id,omitempty"`
+	RadioPick bool                  `json:"radio_pick,omitempty"`
+}
+
+// voiceSession captures the player's session if it's playing in voice.
+// Paused and idle players aren't saved; they'd come back playing.
+func (p *GuildPlayer) voiceSession(now time.Time) (savedVoiceSession, bool) {
+	channelID := p.GetVoiceChannelID()
+	if channelID == nil || *channelID == "" || p.Player == nil || !p.Player.IsPlaying() || p.Player.IsPaused() {
+		return savedVoiceSession{}, false
+	}
+	current := p.GetCurrentItem()
+	if current == nil {
+		return savedVoiceSession{}, false
+	}
+
+	session := savedVoiceSession{
+		VoiceChannelID: *channelID,
+		TextChannelID:  p.GetLastTextChannelID(),
+		Played:         p.Player.GetPosition(),
+		SavedAt:        now,
+	}
+	for _, item := range append([]*GuildQueueItem{current}, p.GetQueueSnapshot()...) {
+		track := savedVoiceTrack{Video: item.Video, RadioPick: item.IsRadioPick}
+		if item.Interaction != nil {
+			track.UserID = item.Interaction.UserID
+		}
+		session.Tracks = append(session.Tracks, track)
+	}
+	return session, true
+}
+
+// SaveVoiceSessions stores every playing session, so RestoreVoiceSessions
+// can rejoin and pick the music back up after a restart. It's meant for
+// shutdown, and returns how many sessions it saved.
+func (c *Controller) SaveVoiceSessions() int {
+	if c.db == nil {
+		return 0
+	}
+	c.mu.RLock()
+	players := make([]*GuildPlayer, 0, len(c.sessions))
+	for _, player := range c.sessions {
+		players = append(players, player)
+	}
+	c.mu.RUnlock()
+
+	saved := 0
+	now := time.Now()
+	for _, player := range players {
+		session, ok := player.voiceSession(now)
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(session)
+		if err == nil {
+			err = c.db.SetGuildSetting(player.GuildID, voiceSessionSetting, string(data))
+		}
+		if err != nil {
+			log.Errorf("Failed to save voice session for guild %s: %v", player.GuildID, err)
+			sentry.CaptureException(err)
+			continue
+		}
+		saved++
+	}
+	log.Infof("Saved %d voice sessions for restart", saved)
+	return saved
+}
+
+// RestoreVoiceSessions rejoins the voice channels SaveVoiceSessions saved
+// and resumes each current song where it stopped, followed by its queue.
+// Each session is forgotten as it's read, so a start that crashes doesn't
+// keep rejoining.
+func (c *Controller) RestoreVoiceSessions(appID string) {
+	if c.db == nil {
+		return
+	}
+	values, err := c.db.GuildsWithSetting(voiceSessionSetting)
+	if err != nil {
+		log.Errorf("Failed to load saved voice sessions: %v", err)
+		sentry.CaptureException(err)
+		return
+	}
+
+	now := time.Now()
+	for guildID, value := range values {
+		if err := c.db.SetGuildSetting(guildID, voiceSessionSetting, ""); err != nil {
+			log.Errorf("Failed to clear saved voice session for guild %s: %v", guildID, err)
+		}
+		var session savedVoiceSession
+		if err := json.Unmarshal([]byte(value), &session); err != nil {
+			log.Warnf("Ignoring unreadable voice session for guild %s: %v", guildID, err)
+			continue
+		}
+		if now.Sub(session.SavedAt) > voiceSessionMaxAge || session.VoiceChannelID == "" || len(session.Tracks) == 0 {
+			log.Infof("Not restoring voice session for guild %s saved at %v", guildID, session.SavedAt)
+			continue
+		}
+		go c.restoreVoiceSession(guildID, session, appID)
+	}
+}
+
+func (c *Controller) restoreVoiceSession(guildID string, session savedVoiceSession, appID string) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("Panic restoring voice session for guild %s: %v", guildID, err)
+		}
+	}()
+
+	// Voice states arrive with the guild, a moment after connecting; without
+	// them the channel looks empty and playback would pause straight away.
+	for deadline := time.Now().Add(voiceSessionGuildWait); time.Now().Before(deadline); time.Sleep(250 * time.Millisecond) {
+		if _, err := c.discord.State.Guild(guildID); err == nil {
+			break
+		}
+	}
+
+	player := c.GetPlayer(guildID)
+	if session.TextChannelID != "" && player.GetLastTextChannelID() == "" {
+		player.SetLastTextChannelID(session.TextChannelID)
+	}
+	if err := player.JoinChannel(session.VoiceChannelID); err != nil {
+		log.Warnf("Couldn't rejoin voice for guild %s after restart: %v", guildID, err)
+		return
+	}
+
+	ctx := context.Background()
+	for i, track := range session.Tracks {
+		video := track.Video
+		if i == 0 {
+			video = resumeVideo(video, session.Played)
+		}
+		player.Add(ctx, video, track.UserID, "", appID, nil, track.RadioPick)
+	}
+	log.Infof("Restored voice session for guild %s: %d tracks, resuming at %v", guildID, len(session.Tracks), session.Played)
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"beatbot/database"
+	"beatbot/youtube"
+)
+
+func TestRestoreVoiceSessionsSkipsStale(t *testing.T) {
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := database.New()
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	defer db.Close()
+
+	stale, _ := json.Marshal(savedVoiceSession{
+		VoiceChannelID: "vc1",
+		SavedAt:        time.Now().Add(-time.Hour),
+		Tracks:         []savedVoiceTrack{{Video: youtube.VideoResponse{VideoID: "abc", Title: "Song"}}},
+	})
+	db.SetGuildSetting("g1", voiceSessionSetting, string(stale))
+	db.SetGuildSetting("g2", voiceSessionSetting, "{not json")
+
+	c := &Controller{db: db, sessions: map[string]*GuildPlayer{}}
+	c.RestoreVoiceSessions("app")
+
+	if left, _ := db.GuildsWithSetting(voiceSessionSetting); len(left) != 0 {
+		t.Errorf("sessions left after restore: %v", left)
+	}
+	if len(c.sessions) != 0 {
+		t.Errorf("restored a stale or unreadable session: %v", c.sessions)
+	}
+}
+
+func TestSavedVoiceSessionRoundTrip(t *testing.T) {
+	session := savedVoiceSession{
+		VoiceChannelID: "vc1",
+		TextChannelID:  "t1",
+		Played:         95 * time.Second,
+		SavedAt:        time.Now().UTC().Truncate(time.Second),
+		Tracks: []savedVoiceTrack{
+			{Video: youtube.VideoResponse{VideoID: "abc", Title: "Song", Duration: 4 * time.Minute}, UserID: "u1"},
+			{Video: youtube.VideoResponse{VideoID: "def", Title: "Radio pick"}, RadioPick: true},
+		},
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got savedVoiceSession
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Played != session.Played || !got.SavedAt.Equal(session.SavedAt) || len(got.Tracks) != 2 ||
+		got.Tracks[0].UserID != "u1" || !got.Tracks[1].RadioPick {
+		t.Fatalf("round trip = %+v", got)
+	}
+	if resumed := resumeVideo(got.Tracks[0].Video, got.Played); resumed.StartAt != 95*time.Second {
+		t.Errorf("resumed at %v, want 1m35s", resumed.StartAt)
+	}
+}
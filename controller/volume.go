@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// lastVolumeSetting is the guild setting holding the volume from the last
+// /volume or Vol +/- press, restored when the bot restarts.
+const lastVolumeSetting = "last_volume"
+
+// volumeSaveDelay is how long volume changes must settle before they are
+// written, so mashing Vol +/- costs one database write instead of one each.
+const volumeSaveDelay = 3 * time.Second
+
+// SaveVolumeSoon persists the current volume once it has stopped changing for
+// volumeSaveDelay. Each call pushes the write back.
+func (p *GuildPlayer) SaveVolumeSoon() {
+	if p.DB == nil {
+		return
+	}
+	p.volumeSaveMu.Lock()
+	defer p.volumeSaveMu.Unlock()
+	if p.volumeSaveTimer != nil {
+		p.volumeSaveTimer.Stop()
+	}
+	p.volumeSaveTimer = time.AfterFunc(volumeSaveDelay, p.saveVolume)
+}
+
+func (p *GuildPlayer) saveVolume() {
+	volume := strconv.Itoa(p.Player.GetVolume())
+	if err := p.DB.SetGuildSetting(p.GuildID, lastVolumeSetting, volume); err != nil {
+		log.Warnf("Failed to save volume for guild %s: %v", p.GuildID, err)
+	}
+}
+
+// ForgetVolume drops the remembered volume (and any pending write) so the
+// /settings default applies after a restart.
+func (p *GuildPlayer) ForgetVolume() error {
+	p.volumeSaveMu.Lock()
+	if p.volumeSaveTimer != nil {
+		p.volumeSaveTimer.Stop()
+		p.volumeSaveTimer = nil
+	}
+	p.volumeSaveMu.Unlock()
+	if p.DB == nil {
+		return nil
+	}
+	return p.DB.SetGuildSetting(p.GuildID, lastVolumeSetting, "")
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// How a command recorded in the audit log turned out.
+const (
+	AuditOK      = "ok"
+	AuditError   = "error"   // the command reported an error
+	AuditRefused = "refused" // a permission check or cooldown turned it away
+)
+
+// auditRetention is how long commands stay in the audit log.
+const auditRetention = 90 * 24 * time.Hour
+
+// AuditEntry is a command someone ran in a guild. Options is how it was
+// invoked, like "add name:Road trip", and Detail says why it failed or was
+// refused.
+type AuditEntry struct {
+	ID        int64
+	GuildID   string
+	UserID    string
+	Username  string
+	Command   string
+	Options   string
+	Outcome   string
+	Detail    string
+	CreatedAt time.Time
+}
+
+// AuditFilter narrows GetAuditLog to one member or command; empty fields
+// match everything.
+type AuditFilter struct {
+	UserID  string
+	Command string
+}
+
+// RecordCommand adds entry to its guild's audit log and returns its ID, so
+// the outcome can be filled in later with SetAuditOutcome. An empty Outcome
+// is recorded as AuditOK.
+func (d *Database) RecordCommand(entry AuditEntry) (int64, error) {
+	if entry.Outcome == "" {
+		entry.Outcome = AuditOK
+	}
+	id, err := d.insert(
+		`INSERT INTO audit_log (guild_id, user_id, username, command, options, outcome, detail, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.GuildID, entry.UserID, entry.Username, entry.Command, entry.Options,
+		entry.Outcome, entry.Detail, d.db.dialect.timeArg(time.Now()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record command: %w", err)
+	}
+	return id, nil
+}
+
+// SetAuditOutcome updates how a recorded command turned out.
+func (d *Database) SetAuditOutcome(id int64, outcome, detail string) error {
+	_, err := d.db.Exec(`UPDATE audit_log SET outcome = ?, detail = ? WHERE id = ?`, outcome, detail, id)
+	if err != nil {
+		return fmt.Errorf("failed to update audit outcome: %w", err)
+	}
+	return nil
+}
+
+// GetAuditLog returns a guild's most recent commands matching filter,
+// newest first.
+func (d *Database) GetAuditLog(guildID string, filter AuditFilter, limit int) ([]AuditEntry, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	query := `SELECT id, guild_id, user_id, username, command, options, outcome, detail, created_at
+		FROM audit_log WHERE guild_id = ?`
+	args := []any{guildID}
+	if filter.UserID != "" {
+		query += ` AND user_id = ?`
+		args = append(args, filter.UserID)
+	}
+	if filter.Command != "" {
+		query += ` AND command = ?`
+		args = append(args, filter.Command)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.GuildID, &e.UserID, &e.Username, &e.Command,
+			&e.Options, &e.Outcome, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit row: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// PruneAuditLog deletes commands from before cutoff in every guild and
+// returns how many were removed.
+func (d *Database) PruneAuditLog(cutoff time.Time) (int64, error) {
+	res, err := d.db.Exec(`DELETE FROM audit_log WHERE created_at < ?`, d.db.dialect.timeArg(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit log: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// StartAuditPruning deletes commands older than 90 days now and then daily
+// until ctx is done.
+func (d *Database) StartAuditPruning(ctx context.Context) {
+	prune := func() {
+		removed, err := d.PruneAuditLog(time.Now().Add(-auditRetention))
+		if err != nil {
+			log.Errorf("Error pruning audit log: %v", err)
+			sentry.CaptureException(err)
+			return
+		}
+		if removed > 0 {
+			log.Infof("Pruned %d commands older than %d days from the audit log", removed, int(auditRetention.Hours()/24))
+		}
+	}
+
+	runDaily(ctx, prune)
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	d := newTestDatabase(t)
+
+	clearID, err := d.RecordCommand(AuditEntry{GuildID: "g1", UserID: "u1", Username: "alice", Command: "clear"})
+	if err != nil {
+		t.Fatalf("RecordCommand: %v", err)
+	}
+	if _, err := d.RecordCommand(AuditEntry{GuildID: "g1", UserID: "u2", Username: "bob", Command: "play", Options: "query:song"}); err != nil {
+		t.Fatalf("RecordCommand: %v", err)
+	}
+	if _, err := d.RecordCommand(AuditEntry{GuildID: "g2", UserID: "u1", Username: "alice", Command: "clear"}); err != nil {
+		t.Fatalf("RecordCommand: %v", err)
+	}
+	if err := d.SetAuditOutcome(clearID, AuditRefused, "not a DJ"); err != nil {
+		t.Fatalf("SetAuditOutcome: %v", err)
+	}
+
+	entries, err := d.GetAuditLog("g1", AuditFilter{}, 10)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("GetAuditLog = %+v, %v", entries, err)
+	}
+	if entries[0].Command != "play" || entries[0].Outcome != AuditOK || entries[0].Options != "query:song" {
+		t.Errorf("newest entry = %+v", entries[0])
+	}
+	if entries[1].Outcome != AuditRefused || entries[1].Detail != "not a DJ" {
+		t.Errorf("clear entry = %+v", entries[1])
+	}
+
+	if entries, _ := d.GetAuditLog("g1", AuditFilter{UserID: "u1"}, 10); len(entries) != 1 || entries[0].Command != "clear" {
+		t.Errorf("filtered by user = %+v", entries)
+	}
+	if entries, _ := d.GetAuditLog("g1", AuditFilter{Command: "play"}, 10); len(entries) != 1 || entries[0].UserID != "u2" {
+		t.Errorf("filtered by command = %+v", entries)
+	}
+
+	if _, err := d.db.Exec(`UPDATE audit_log SET created_at = ? WHERE id = ?`,
+		time.Now().Add(-100*24*time.Hour).UTC().Format(time.RFC3339Nano), clearID); err != nil {
+		t.Fatalf("backdating entry: %v", err)
+	}
+	removed, err := d.PruneAuditLog(time.Now().Add(-auditRetention))
+	if err != nil || removed != 1 {
+		t.Errorf("PruneAuditLog = %d, %v; want 1", removed, err)
+	}
+}
@@ -13,8 +13,12 @@ import (
 )
 
 type Database struct {
-	db      *sql.DB
+	db      *conn
 	session *discordgo.Session
+	dir     string // holds the database file and anything kept beside it
+
+	guildSettings *settingsCache
+	userSettings  *settingsCache
 }
 
 type SongHistoryRecord struct {
@@ -37,8 +41,16 @@ type MostPlayedRecord struct {
 	LastPlayed time.Time
 }
 
-// New creates a new Database instance. dbPath defaults to DB_PATH env var or /app/data/beatbot.db.
+// New creates a new Database instance. DATABASE_DRIVER picks sqlite (the
+// default), postgres or mysql. SQLite's file is DB_PATH, defaulting to
+// /app/data/beatbot.db; the others connect to DATABASE_URL. Files kept beside
+// the database, like soundboard sounds, go in DB_PATH's directory either way.
 func New() (*Database, error) {
+	dl, err := dialectFor(os.Getenv("DATABASE_DRIVER"))
+	if err != nil {
+		return nil, err
+	}
+
 	dbPath := os.Getenv("DB_PATH")
 	if dbPath == "" {
 		dbPath = "/app/data/beatbot.db"
@@ -50,11 +62,52 @@ func New() (*Database, error) {
 		return nil, fmt.Errorf("failed to create database directory %s: %w", dir, err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	var db *sql.DB
+	location := dbPath
+	if _, ok := dl.(sqliteDialect); ok {
+		db, err = openSQLite(dbPath)
+	} else {
+		dsn := os.Getenv("DATABASE_URL")
+		if dsn == "" {
+			return nil, fmt.Errorf("DATABASE_URL is required for DATABASE_DRIVER=%s", os.Getenv("DATABASE_DRIVER"))
+		}
+		if _, ok := dl.(mysqlDialect); ok {
+			dsn = mysqlDSN(dsn)
+		}
+		db, err = sql.Open(dl.driverName(), dsn)
+		if err == nil {
+			err = db.Ping()
+		}
+		location = "DATABASE_URL (" + os.Getenv("DATABASE_DRIVER") + ")"
+	}
 	if err != nil {
+		if db != nil {
+			db.Close()
+		}
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	d := &Database{
+		db:            &conn{db: db, dialect: dl},
+		dir:           dir,
+		guildSettings: newSettingsCache(settingsCacheTTL),
+		userSettings:  newSettingsCache(settingsCacheTTL),
+	}
+	if err := d.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	log.Infof("Database initialized at %s", location)
+	return d, nil
+}
+
+func openSQLite(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// SQLite is file-level locked: a single connection serializes all writes
 	// and avoids SQLITE_BUSY errors from concurrent goroutines. WAL mode still
 	// allows concurrent readers even with one writer connection.
@@ -72,93 +125,77 @@ func New() (*Database, error) {
 		db.Close()
 		return nil, fmt.Errorf("failed to set busy timeout: %w", err)
 	}
-
-	d := &Database{db: db}
-	if err := d.migrate(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
-	}
-
-	log.Infof("Database initialized at %s", dbPath)
-	return d, nil
+	return db, nil
 }
 
 func (d *Database) Close() error {
 	return d.db.Close()
 }
 
-func (d *Database) migrate() error {
-	migrations := []string{
-		`CREATE TABLE IF NOT EXISTS song_history (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			guild_id TEXT NOT NULL,
-			video_id TEXT NOT NULL,
-			title TEXT NOT NULL,
-			url TEXT NOT NULL DEFAULT '',
-			requested_by_user_id TEXT NOT NULL DEFAULT '',
-			requested_by_username TEXT NOT NULL DEFAULT '',
-			played_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			duration_seconds INTEGER NOT NULL DEFAULT 0
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_song_history_guild_id ON song_history(guild_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_song_history_played_at ON song_history(guild_id, played_at DESC)`,
-		`CREATE INDEX IF NOT EXISTS idx_song_history_video_id ON song_history(guild_id, video_id)`,
-		`CREATE TABLE IF NOT EXISTS user_cache (
-			user_id TEXT NOT NULL,
-			guild_id TEXT NOT NULL,
-			username TEXT NOT NULL,
-			cached_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			PRIMARY KEY (guild_id, user_id)
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_user_cache_lookup ON user_cache(guild_id, user_id)`,
-		`CREATE TABLE IF NOT EXISTS user_favorites (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			user_id TEXT NOT NULL,
-			guild_id TEXT NOT NULL,
-			video_id TEXT NOT NULL,
-			title TEXT NOT NULL,
-			url TEXT NOT NULL DEFAULT '',
-			added_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_user_favorites_user_guild ON user_favorites(guild_id, user_id)`,
-		// Enforce uniqueness at the DB level so duplicate favorites can never be
-		// inserted even under concurrent load. INSERT OR IGNORE in AddFavorite
-		// relies on this constraint for idempotency.
-		`CREATE UNIQUE INDEX IF NOT EXISTS idx_user_favorites_unique ON user_favorites(user_id, guild_id, video_id)`,
-		`CREATE TABLE IF NOT EXISTS guild_settings (
-			guild_id TEXT NOT NULL,
-			key TEXT NOT NULL,
-			value TEXT NOT NULL,
-			PRIMARY KEY (guild_id, key)
-		)`,
-		`CREATE TABLE IF NOT EXISTS guild_blocked_videos (
-			guild_id   TEXT NOT NULL,
-			video_id   TEXT NOT NULL,
-			title      TEXT NOT NULL DEFAULT '',
-			url        TEXT NOT NULL DEFAULT '',
-			blocked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			PRIMARY KEY (guild_id, video_id)
-		)`,
-	}
-
-	for _, m := range migrations {
-		if _, err := d.db.Exec(m); err != nil {
-			return fmt.Errorf("migration failed: %w\nSQL: %s", err, m)
+// addColumn adds a column to an existing table unless it's already there;
+// SQLite has no ADD COLUMN IF NOT EXISTS.
+func (d *Database) addColumn(table, column, definition string) error {
+	rows, err := d.db.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name, kind string
+			notNull    int
+			dflt       sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &dflt, &pk); err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", table, err)
+		}
+		if name == column {
+			return nil
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	rows.Close()
 
+	m := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := d.db.Exec(m); err != nil {
+		return fmt.Errorf("migration failed: %w\nSQL: %s", err, m)
+	}
 	return nil
 }
 
-// RecordPlay inserts a song play record.
-func (d *Database) RecordPlay(guildID, videoID, title, url, userID, username string, durationSeconds int) error {
-	_, err := d.db.Exec(
-		`INSERT INTO song_history (guild_id, video_id, title, url, requested_by_user_id, requested_by_username, played_at, duration_seconds)
+// RecordPlay inserts a song play record and returns its ID for FinishPlay.
+// trackSeconds is the song's length, 0 if unknown, so skips can be placed
+// within it.
+func (d *Database) RecordPlay(guildID, videoID, title, url, userID, username string, trackSeconds int) (int64, error) {
+	id, err := d.insert(
+		`INSERT INTO song_history (guild_id, video_id, title, url, requested_by_user_id, requested_by_username, played_at, track_seconds)
 		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		guildID, videoID, title, url, userID, username, time.Now().UTC().Format(time.RFC3339Nano), durationSeconds,
+		guildID, videoID, title, url, userID, username, d.db.dialect.timeArg(time.Now()), trackSeconds,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record play: %w", err)
+	}
+	return id, nil
+}
+
+// FinishPlay records how a play ended: how many seconds were listened to and
+// whether it was skipped.
+func (d *Database) FinishPlay(playID int64, listenedSeconds int, skipped bool) error {
+	skip := 0
+	if skipped {
+		skip = 1
+	}
+	_, err := d.db.Exec(
+		`UPDATE song_history SET duration_seconds = ?, skipped = ? WHERE id = ?`,
+		listenedSeconds, skip, playID,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to record play: %w", err)
+		return fmt.Errorf("failed to finish play: %w", err)
 	}
 	return nil
 }
@@ -194,14 +231,50 @@ func (d *Database) GetHistory(guildID string, limit int) ([]SongHistoryRecord, e
 	return records, rows.Err()
 }
 
+// GetHistorySince returns a guild's plays at or after since, oldest first.
+func (d *Database) GetHistorySince(guildID string, since time.Time, limit int) ([]SongHistoryRecord, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := d.db.Query(
+		`SELECT id, guild_id, video_id, title, url, requested_by_user_id, requested_by_username, played_at, duration_seconds
+		 FROM (
+			SELECT * FROM song_history
+			WHERE guild_id = ? AND played_at >= ?
+			ORDER BY played_at DESC
+			LIMIT ?
+		 ) AS recent
+		 ORDER BY played_at ASC`,
+		guildID, d.db.dialect.timeArg(since), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []SongHistoryRecord
+	for rows.Next() {
+		var r SongHistoryRecord
+		if err := rows.Scan(&r.ID, &r.GuildID, &r.VideoID, &r.Title, &r.URL,
+			&r.RequestedByUserID, &r.RequestedByUsername, &r.PlayedAt, &r.DurationSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan history row: %w", err)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
 // GetMostPlayed returns the most played songs for a guild.
 func (d *Database) GetMostPlayed(guildID string, limit int) ([]MostPlayedRecord, error) {
 	if limit <= 0 {
 		limit = 10
 	}
 
+	// Titles and URLs are aggregated too: Postgres and MySQL don't allow
+	// bare columns beside GROUP BY.
 	rows, err := d.db.Query(
-		`SELECT video_id, title, url, COUNT(*) as play_count, MAX(played_at) as last_played
+		`SELECT video_id, MAX(title), MAX(url), COUNT(*) as play_count, MAX(played_at) as last_played
 		 FROM song_history
 		 WHERE guild_id = ?
 		 GROUP BY video_id
@@ -259,7 +332,7 @@ func (d *Database) GetGlobalMostPlayed(limit int) ([]MostPlayedRecord, error) {
 	}
 
 	rows, err := d.db.Query(
-		`SELECT video_id, title, url, COUNT(*) as play_count, MAX(played_at) as last_played
+		`SELECT video_id, MAX(title), MAX(url), COUNT(*) as play_count, MAX(played_at) as last_played
 		 FROM song_history
 		 GROUP BY video_id
 		 ORDER BY play_count DESC, last_played DESC
@@ -344,7 +417,8 @@ func (d *Database) GetOrFetchUsername(guildID, userID string) string {
 
 			// Update cache
 			_, err = d.db.Exec(
-				`INSERT OR REPLACE INTO user_cache (guild_id, user_id, username, cached_at) VALUES (?, ?, ?, ?)`,
+				`INSERT INTO user_cache (guild_id, user_id, username, cached_at) VALUES (?, ?, ?, ?)
+				 ON CONFLICT(guild_id, user_id) DO UPDATE SET username = excluded.username, cached_at = excluded.cached_at`,
 				guildID, userID, username, time.Now().UTC(),
 			)
 			if err != nil {
@@ -361,7 +435,8 @@ func (d *Database) GetOrFetchUsername(guildID, userID string) string {
 
 			// Update cache
 			_, err = d.db.Exec(
-				`INSERT OR REPLACE INTO user_cache (guild_id, user_id, username, cached_at) VALUES (?, ?, ?, ?)`,
+				`INSERT INTO user_cache (guild_id, user_id, username, cached_at) VALUES (?, ?, ?, ?)
+				 ON CONFLICT(guild_id, user_id) DO UPDATE SET username = excluded.username, cached_at = excluded.cached_at`,
 				guildID, userID, username, time.Now().UTC(),
 			)
 			if err != nil {
@@ -390,14 +465,15 @@ type UserFavoriteRecord struct {
 	VideoID string
 	Title   string
 	URL     string
+	Source  string // resolver that produced the track; "" means YouTube
 	AddedAt time.Time
 }
 
 // AddFavorite saves a song to a user's favorites. Silently ignores duplicates.
-func (d *Database) AddFavorite(userID, guildID, videoID, title, url string) error {
+func (d *Database) AddFavorite(userID, guildID, videoID, title, url, source string) error {
 	_, err := d.db.Exec(
-		`INSERT OR IGNORE INTO user_favorites (user_id, guild_id, video_id, title, url) VALUES (?, ?, ?, ?, ?)`,
-		userID, guildID, videoID, title, url,
+		`INSERT OR IGNORE INTO user_favorites (user_id, guild_id, video_id, title, url, source) VALUES (?, ?, ?, ?, ?, ?)`,
+		userID, guildID, videoID, title, url, source,
 	)
 	return err
 }
@@ -415,7 +491,7 @@ func (d *Database) IsFavorite(userID, guildID, videoID string) bool {
 // GetFavorites returns a user's saved favorites for a guild, newest first.
 func (d *Database) GetFavorites(userID, guildID string, limit int) ([]UserFavoriteRecord, error) {
 	rows, err := d.db.Query(
-		`SELECT id, user_id, guild_id, video_id, title, url, added_at
+		`SELECT id, user_id, guild_id, video_id, title, url, source, added_at
 		 FROM user_favorites
 		 WHERE user_id = ? AND guild_id = ?
 		 ORDER BY added_at DESC
@@ -431,7 +507,7 @@ func (d *Database) GetFavorites(userID, guildID string, limit int) ([]UserFavori
 	for rows.Next() {
 		var r UserFavoriteRecord
 		var addedAt string
-		if err := rows.Scan(&r.ID, &r.UserID, &r.GuildID, &r.VideoID, &r.Title, &r.URL, &addedAt); err != nil {
+		if err := rows.Scan(&r.ID, &r.UserID, &r.GuildID, &r.VideoID, &r.Title, &r.URL, &r.Source, &addedAt); err != nil {
 			return nil, err
 		}
 		// user_favorites.added_at uses DEFAULT CURRENT_TIMESTAMP (SQLite format)
@@ -486,20 +562,61 @@ func (d *Database) RemoveFavoriteByIndex(userID, guildID string, index int) (str
 	return title, tx.Commit()
 }
 
-// GetGuildSetting returns the value for a per-guild setting, or "" if not found.
+// GetGuildSetting returns the value for a per-guild setting, or "" if not
+// found. Settings are cached; see settingsCache.
 func (d *Database) GetGuildSetting(guildID, key string) (string, error) {
-	var value string
-	err := d.db.QueryRow(
-		`SELECT value FROM guild_settings WHERE guild_id = ? AND key = ?`,
-		guildID, key,
-	).Scan(&value)
-	if err == sql.ErrNoRows {
-		return "", nil
+	if value, ok := d.guildSettings.lookup(guildID, key); ok {
+		return value, nil
 	}
+	token := d.guildSettings.loading(guildID)
+	values, err := d.loadSettings(`SELECT key, value FROM guild_settings WHERE guild_id = ?`, guildID)
 	if err != nil {
 		return "", fmt.Errorf("failed to get guild setting %s/%s: %w", guildID, key, err)
 	}
-	return value, nil
+	d.guildSettings.store(guildID, values, token)
+	return values[key], nil
+}
+
+// loadSettings reads key/value rows into a map.
+func (d *Database) loadSettings(query string, args ...any) (map[string]string, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, rows.Err()
+}
+
+// GuildsWithSetting returns every guild that has key set to a non-empty
+// value, mapped to that value.
+func (d *Database) GuildsWithSetting(key string) (map[string]string, error) {
+	rows, err := d.db.Query(
+		`SELECT guild_id, value FROM guild_settings WHERE key = ? AND value != ''`,
+		key,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guild setting %s: %w", key, err)
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var guildID, value string
+		if err := rows.Scan(&guildID, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan guild setting row: %w", err)
+		}
+		values[guildID] = value
+	}
+	return values, rows.Err()
 }
 
 // BlockVideo adds a guild-wide never-play block. Silently ignores duplicates.
@@ -556,5 +673,65 @@ func (d *Database) SetGuildSetting(guildID, key, value string) error {
 	if err != nil {
 		return fmt.Errorf("failed to set guild setting %s/%s: %w", guildID, key, err)
 	}
+	d.guildSettings.update(guildID, key, value)
+	return nil
+}
+
+// GetUserSetting returns the value for a per-user setting, or "" if not
+// found. Cached like guild settings.
+func (d *Database) GetUserSetting(userID, key string) (string, error) {
+	if value, ok := d.userSettings.lookup(userID, key); ok {
+		return value, nil
+	}
+	token := d.userSettings.loading(userID)
+	values, err := d.loadSettings(`SELECT key, value FROM user_settings WHERE user_id = ?`, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user setting %s/%s: %w", userID, key, err)
+	}
+	d.userSettings.store(userID, values, token)
+	return values[key], nil
+}
+
+// SetUserSetting upserts a per-user setting.
+func (d *Database) SetUserSetting(userID, key, value string) error {
+	_, err := d.db.Exec(
+		`INSERT INTO user_settings (user_id, key, value) VALUES (?, ?, ?)
+		 ON CONFLICT(user_id, key) DO UPDATE SET value = excluded.value`,
+		userID, key, value,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set user setting %s/%s: %w", userID, key, err)
+	}
+	d.userSettings.update(userID, key, value)
+	return nil
+}
+
+// GetTitleTranslation returns the cached translation for a video. found is false
+// when the video has never been translated; an empty translation with found=true
+// means Gemini decided the title didn't need one.
+func (d *Database) GetTitleTranslation(videoID string) (translation string, found bool, err error) {
+	err = d.db.QueryRow(
+		`SELECT translation FROM title_translations WHERE video_id = ?`,
+		videoID,
+	).Scan(&translation)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get title translation %s: %w", videoID, err)
+	}
+	return translation, true, nil
+}
+
+// SetTitleTranslation upserts the cached translation for a video.
+func (d *Database) SetTitleTranslation(videoID, translation string) error {
+	_, err := d.db.Exec(
+		`INSERT INTO title_translations (video_id, translation) VALUES (?, ?)
+		 ON CONFLICT(video_id) DO UPDATE SET translation = excluded.translation, created_at = CURRENT_TIMESTAMP`,
+		videoID, translation,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set title translation %s: %w", videoID, err)
+	}
 	return nil
 }
@@ -0,0 +1,91 @@
+package database
+
+import "testing"
+
+func TestFavoritesKeepSource(t *testing.T) {
+	d := newTestDatabase(t)
+
+	if err := d.AddFavorite("u1", "g1", "abc", "YouTube Song", "https://www.youtube.com/watch?v=abc", ""); err != nil {
+		t.Fatalf("AddFavorite: %v", err)
+	}
+	if err := d.AddFavorite("u1", "g1", "123", "SoundCloud Song", "https://soundcloud.com/a/b", "soundcloud"); err != nil {
+		t.Fatalf("AddFavorite: %v", err)
+	}
+
+	records, err := d.GetFavorites("u1", "g1", 25)
+	if err != nil {
+		t.Fatalf("GetFavorites: %v", err)
+	}
+	sources := make(map[string]string)
+	for _, r := range records {
+		sources[r.VideoID] = r.Source
+	}
+	if len(records) != 2 || sources["abc"] != "" || sources["123"] != "soundcloud" {
+		t.Errorf("favorites = %+v, want sources kept", records)
+	}
+}
+
+func TestUserSettings(t *testing.T) {
+	d := newTestDatabase(t)
+
+	if v, err := d.GetUserSetting("u1", "notify_up_next"); err != nil || v != "" {
+		t.Fatalf("unset setting = %q, %v; want empty", v, err)
+	}
+	if err := d.SetUserSetting("u1", "notify_up_next", "true"); err != nil {
+		t.Fatalf("SetUserSetting: %v", err)
+	}
+	if err := d.SetUserSetting("u1", "notify_up_next", "false"); err != nil {
+		t.Fatalf("SetUserSetting: %v", err)
+	}
+	if v, _ := d.GetUserSetting("u1", "notify_up_next"); v != "false" {
+		t.Errorf("setting = %q, want the latest value", v)
+	}
+	if v, _ := d.GetUserSetting("u2", "notify_up_next"); v != "" {
+		t.Errorf("another user's setting = %q, want empty", v)
+	}
+}
+
+func TestGuildsWithSetting(t *testing.T) {
+	d := newTestDatabase(t)
+
+	for _, s := range []struct{ guild, key, value string }{
+		{"g1", "music_channel_id", "c1"},
+		{"g2", "music_channel_id", "c2"},
+		{"g3", "music_channel_id", ""}, // unbound
+		{"g4", "volume", "50"},
+	} {
+		if err := d.SetGuildSetting(s.guild, s.key, s.value); err != nil {
+			t.Fatalf("SetGuildSetting: %v", err)
+		}
+	}
+
+	got, err := d.GuildsWithSetting("music_channel_id")
+	if err != nil {
+		t.Fatalf("GuildsWithSetting: %v", err)
+	}
+	if len(got) != 2 || got["g1"] != "c1" || got["g2"] != "c2" {
+		t.Errorf("GuildsWithSetting = %v, want g1 and g2", got)
+	}
+}
+
+func TestUserPreferences(t *testing.T) {
+	d := newTestDatabase(t)
+
+	if prefs, err := d.GetUserPreferences("u1"); err != nil || prefs != (UserPreferences{}) {
+		t.Fatalf("defaults = %+v, %v; want zero", prefs, err)
+	}
+	for key, value := range map[string]string{
+		PrefLanguage:       "es",
+		PrefPrivateReplies: "true",
+		PrefVolumeOffset:   "-80", // out of range, clamped
+		PrefNotifyUpNext:   "true",
+	} {
+		if err := d.SetUserSetting("u1", key, value); err != nil {
+			t.Fatalf("SetUserSetting: %v", err)
+		}
+	}
+	want := UserPreferences{NotifyUpNext: true, Language: "es", PrivateReplies: true, VolumeOffset: -MaxVolumeOffset}
+	if prefs, _ := d.GetUserPreferences("u1"); prefs != want {
+		t.Errorf("preferences = %+v, want %+v", prefs, want)
+	}
+}
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Database drivers, chosen with DATABASE_DRIVER. SQLite keeps everything in
+// one local file; Postgres and MySQL let several bot instances share state.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+)
+
+// dialect covers where the drivers' SQL differs. Queries in this package are
+// written for SQLite and rewritten by rebind for the others, so each one is
+// only written once. That's why there's a single Database type rather than
+// a store implementation per driver.
+type dialect interface {
+	// driverName is the database/sql driver to open.
+	driverName() string
+	// rebind rewrites a SQLite query for this database.
+	rebind(query string) string
+	// timeArg is how a time is passed as a query argument.
+	timeArg(t time.Time) any
+	// returningID reports whether inserted IDs come back from RETURNING
+	// rather than LastInsertId.
+	returningID() bool
+	// tableExistsQuery takes a table name and counts tables by that name.
+	tableExistsQuery() string
+	// migrationsDir is where this database's migrations live under migrations/.
+	migrationsDir() string
+}
+
+func dialectFor(driver string) (dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(driver)) {
+	case "", DriverSQLite, "sqlite3":
+		return sqliteDialect{}, nil
+	case DriverPostgres, "postgresql", "pgx":
+		return postgresDialect{}, nil
+	case DriverMySQL, "mariadb":
+		return mysqlDialect{}, nil
+	}
+	return nil, fmt.Errorf("unknown DATABASE_DRIVER %q; use sqlite, postgres or mysql", driver)
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) driverName() string         { return "sqlite" }
+func (sqliteDialect) rebind(query string) string { return query }
+
+// Plays have always been stored as RFC 3339 text in SQLite, and comparisons
+// against played_at are string comparisons, so times stay in that format.
+func (sqliteDialect) timeArg(t time.Time) any { return t.UTC().Format(time.RFC3339Nano) }
+func (sqliteDialect) returningID() bool       { return false }
+func (sqliteDialect) tableExistsQuery() string {
+	return `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`
+}
+func (sqliteDialect) migrationsDir() string { return "migrations/sqlite" }
+
+type postgresDialect struct{}
+
+func (postgresDialect) driverName() string { return "pgx" }
+
+// rebind numbers placeholders ($1, $2...) and turns INSERT OR IGNORE into
+// ON CONFLICT DO NOTHING. ON CONFLICT upserts are the same in both.
+func (postgresDialect) rebind(query string) string {
+	if rest, ok := strings.CutPrefix(query, "INSERT OR IGNORE INTO"); ok {
+		query = "INSERT INTO" + rest + " ON CONFLICT DO NOTHING"
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+func (postgresDialect) timeArg(t time.Time) any { return t.UTC() }
+func (postgresDialect) returningID() bool       { return true }
+func (postgresDialect) tableExistsQuery() string {
+	return `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?`
+}
+func (postgresDialect) migrationsDir() string { return "migrations/postgres" }
+
+type mysqlDialect struct{}
+
+var (
+	mysqlKeyColumn  = regexp.MustCompile(`\bkey\b`)
+	mysqlOnConflict = regexp.MustCompile(`ON CONFLICT\s*\([^)]*\)\s*DO UPDATE SET`)
+	mysqlExcluded   = regexp.MustCompile(`excluded\.(\w+)`)
+)
+
+func (mysqlDialect) driverName() string { return "mysql" }
+
+// rebind quotes the settings tables' key column, a reserved word in MySQL,
+// and swaps SQLite's INSERT OR IGNORE and ON CONFLICT upserts for MySQL's
+// INSERT IGNORE and ON DUPLICATE KEY UPDATE.
+func (mysqlDialect) rebind(query string) string {
+	query = mysqlKeyColumn.ReplaceAllString(query, "`key`")
+	if rest, ok := strings.CutPrefix(query, "INSERT OR IGNORE INTO"); ok {
+		query = "INSERT IGNORE INTO" + rest
+	}
+	query = mysqlOnConflict.ReplaceAllString(query, "ON DUPLICATE KEY UPDATE")
+	return mysqlExcluded.ReplaceAllString(query, "VALUES($1)")
+}
+func (mysqlDialect) timeArg(t time.Time) any { return t.UTC() }
+func (mysqlDialect) returningID() bool       { return false }
+func (mysqlDialect) tableExistsQuery() string {
+	return `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?`
+}
+func (mysqlDialect) migrationsDir() string { return "migrations/mysql" }
+
+// mysqlDSN adds the parameters the package relies on: parseTime so DATETIME
+// columns scan into time.Time, and multiStatements so a migration file can
+// run in one Exec.
+func mysqlDSN(dsn string) string {
+	for _, param := range []string{"parseTime=true", "multiStatements=true"} {
+		name, _, _ := strings.Cut(param, "=")
+		if strings.Contains(dsn, name+"=") {
+			continue
+		}
+		if strings.Contains(dsn, "?") {
+			dsn += "&" + param
+		} else {
+			dsn += "?" + param
+		}
+	}
+	return dsn
+}
+
+// conn runs queries through the dialect, so the rest of the package can
+// write SQLite and use it like a *sql.DB.
+type conn struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+func (c *conn) Exec(query string, args ...any) (sql.Result, error) {
+	return c.db.Exec(c.dialect.rebind(query), args...)
+}
+
+func (c *conn) Query(query string, args ...any) (*sql.Rows, error) {
+	return c.db.Query(c.dialect.rebind(query), args...)
+}
+
+func (c *conn) QueryRow(query string, args ...any) *sql.Row {
+	return c.db.QueryRow(c.dialect.rebind(query), args...)
+}
+
+func (c *conn) Begin() (*tx, error) {
+	t, err := c.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &tx{Tx: t, dialect: c.dialect}, nil
+}
+
+func (c *conn) Close() error {
+	return c.db.Close()
+}
+
+// tx is a transaction that rebinds queries like conn.
+type tx struct {
+	*sql.Tx
+	dialect dialect
+}
+
+func (t *tx) Exec(query string, args ...any) (sql.Result, error) {
+	return t.Tx.Exec(t.dialect.rebind(query), args...)
+}
+
+func (t *tx) Query(query string, args ...any) (*sql.Rows, error) {
+	return t.Tx.Query(t.dialect.rebind(query), args...)
+}
+
+func (t *tx) QueryRow(query string, args ...any) *sql.Row {
+	return t.Tx.QueryRow(t.dialect.rebind(query), args...)
+}
+
+// insert runs an INSERT and returns the new row's ID, or 0 when an
+// INSERT OR IGNORE skipped the row.
+func (d *Database) insert(query string, args ...any) (int64, error) {
+	if d.db.dialect.returningID() {
+		var id int64
+		err := d.db.db.QueryRow(d.db.dialect.rebind(query)+" RETURNING id", args...).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return id, err
+	}
+
+	result, err := d.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
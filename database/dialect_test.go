@@ -0,0 +1,97 @@
+package database
+
+import "testing"
+
+func TestDialectFor(t *testing.T) {
+	for driver, want := range map[string]dialect{
+		"":         sqliteDialect{},
+		"sqlite":   sqliteDialect{},
+		"Postgres": postgresDialect{},
+		"pgx":      postgresDialect{},
+		"mysql":    mysqlDialect{},
+		"mariadb":  mysqlDialect{},
+	} {
+		if got, err := dialectFor(driver); err != nil || got != want {
+			t.Errorf("dialectFor(%q) = %T, %v; want %T", driver, got, err, want)
+		}
+	}
+	if _, err := dialectFor("oracle"); err == nil {
+		t.Error("dialectFor(oracle) succeeded, want an error")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name, query, postgres, mysql string
+	}{
+		{
+			"placeholders",
+			`SELECT title FROM song_history WHERE guild_id = ? LIMIT ?`,
+			`SELECT title FROM song_history WHERE guild_id = $1 LIMIT $2`,
+			`SELECT title FROM song_history WHERE guild_id = ? LIMIT ?`,
+		},
+		{
+			"insert or ignore",
+			`INSERT OR IGNORE INTO guild_blocked_videos (guild_id, video_id) VALUES (?, ?)`,
+			`INSERT INTO guild_blocked_videos (guild_id, video_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			`INSERT IGNORE INTO guild_blocked_videos (guild_id, video_id) VALUES (?, ?)`,
+		},
+		{
+			"upsert",
+			`INSERT INTO guild_settings (guild_id, key, value) VALUES (?, ?, ?)
+		 ON CONFLICT(guild_id, key) DO UPDATE SET value = excluded.value`,
+			`INSERT INTO guild_settings (guild_id, key, value) VALUES ($1, $2, $3)
+		 ON CONFLICT(guild_id, key) DO UPDATE SET value = excluded.value`,
+			"INSERT INTO guild_settings (guild_id, `key`, value) VALUES (?, ?, ?)\n\t\t ON DUPLICATE KEY UPDATE value = VALUES(value)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (sqliteDialect{}).rebind(tt.query); got != tt.query {
+				t.Errorf("sqlite rebind = %q, want it unchanged", got)
+			}
+			if got := (postgresDialect{}).rebind(tt.query); got != tt.postgres {
+				t.Errorf("postgres rebind = %q, want %q", got, tt.postgres)
+			}
+			if got := (mysqlDialect{}).rebind(tt.query); got != tt.mysql {
+				t.Errorf("mysql rebind = %q, want %q", got, tt.mysql)
+			}
+		})
+	}
+}
+
+func TestMySQLDSN(t *testing.T) {
+	tests := []struct{ dsn, want string }{
+		{"bot:pw@tcp(db:3306)/beatbot", "bot:pw@tcp(db:3306)/beatbot?parseTime=true&multiStatements=true"},
+		{"bot:pw@tcp(db:3306)/beatbot?tls=true", "bot:pw@tcp(db:3306)/beatbot?tls=true&parseTime=true&multiStatements=true"},
+		{"bot:pw@tcp(db:3306)/beatbot?parseTime=true", "bot:pw@tcp(db:3306)/beatbot?parseTime=true&multiStatements=true"},
+	}
+	for _, tt := range tests {
+		if got := mysqlDSN(tt.dsn); got != tt.want {
+			t.Errorf("mysqlDSN(%q) = %q, want %q", tt.dsn, got, tt.want)
+		}
+	}
+}
+
+// Every driver needs the same migrations, so schema versions mean the same
+// thing whichever one a deployment uses.
+func TestMigrationsMatchAcrossDrivers(t *testing.T) {
+	want, err := loadMigrations(migrationFiles, sqliteDialect{}.migrationsDir())
+	if err != nil {
+		t.Fatalf("loadMigrations(sqlite): %v", err)
+	}
+	for _, dl := range []dialect{postgresDialect{}, mysqlDialect{}} {
+		got, err := loadMigrations(migrationFiles, dl.migrationsDir())
+		if err != nil {
+			t.Fatalf("loadMigrations(%s): %v", dl.driverName(), err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s has %d migrations, sqlite has %d", dl.driverName(), len(got), len(want))
+		}
+		for i := range want {
+			if got[i].name != want[i].name {
+				t.Errorf("%s migration %d is %q, sqlite's is %q", dl.driverName(), i+1, got[i].name, want[i].name)
+			}
+		}
+	}
+}
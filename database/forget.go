@@ -0,0 +1,42 @@
+package database
+
+import "fmt"
+
+// userDataStatements delete or anonymize everything stored about a user,
+// children before their parents. Shared things they added to, like guild
+// playlists, soundboard sounds and play history, stay but lose their name.
+var userDataStatements = []string{
+	`DELETE FROM playlist_tracks WHERE playlist_id IN (SELECT id FROM playlists WHERE owner_id = ?)`,
+	`DELETE FROM playlists WHERE owner_id = ?`,
+	`UPDATE playlists SET created_by = '' WHERE created_by = ?`,
+	`UPDATE playlist_tracks SET added_by = '' WHERE added_by = ?`,
+	`DELETE FROM user_favorites WHERE user_id = ?`,
+	`DELETE FROM user_settings WHERE user_id = ?`,
+	`DELETE FROM user_cache WHERE user_id = ?`,
+	`DELETE FROM scheduled_plays WHERE user_id = ?`,
+	`UPDATE soundboard_sounds SET uploaded_by = '' WHERE uploaded_by = ?`,
+	`UPDATE song_history SET requested_by_user_id = '', requested_by_username = '' WHERE requested_by_user_id = ?`,
+}
+
+// ForgetUser deletes a user's favorites, personal playlists, preferences and
+// scheduled plays in every guild, and takes their name off history and
+// anything shared they added. The audit log is kept for moderation; it
+// expires on its own after 90 days.
+func (d *Database) ForgetUser(userID string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck — superseded by explicit Commit below
+
+	for _, query := range userDataStatements {
+		if _, err := tx.Exec(query, userID); err != nil {
+			return fmt.Errorf("failed to delete user data: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete user data: %w", err)
+	}
+	d.userSettings.forget(userID)
+	return nil
+}
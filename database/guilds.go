@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// guildDataGrace is how long a server's data is kept after the bot is
+// removed, so kicking it by mistake and adding it back loses nothing.
+const guildDataGrace = 30 * 24 * time.Hour
+
+// RecordGuildJoin notes that the bot is in a guild, as it's told for every
+// guild when connecting and again when added to one. Returns true when the
+// guild is new or the bot had been removed from it, which cancels the
+// pending deletion of its data.
+func (d *Database) RecordGuildJoin(guildID, name string) (bool, error) {
+	var left bool
+	err := d.db.QueryRow(`SELECT left_at IS NOT NULL FROM guilds WHERE guild_id = ?`, guildID).Scan(&left)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = d.db.Exec(
+			`INSERT INTO guilds (guild_id, name, joined_at) VALUES (?, ?, ?)`,
+			guildID, name, d.db.dialect.timeArg(time.Now()),
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to record guild join: %w", err)
+		}
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to look up guild: %w", err)
+	case left:
+		_, err = d.db.Exec(
+			`UPDATE guilds SET name = ?, joined_at = ?, left_at = NULL WHERE guild_id = ?`,
+			name, d.db.dialect.timeArg(time.Now()), guildID,
+		)
+	default:
+		_, err = d.db.Exec(`UPDATE guilds SET name = ? WHERE guild_id = ?`, name, guildID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record guild join: %w", err)
+	}
+	return left, nil
+}
+
+// RecordGuildLeave notes that the bot was removed from a guild, starting
+// the countdown to PurgeLeftGuilds deleting its data. Its scheduled plays
+// are cancelled straight away, since there's nowhere left to play them.
+func (d *Database) RecordGuildLeave(guildID string) error {
+	now := d.db.dialect.timeArg(time.Now())
+	res, err := d.db.Exec(`UPDATE guilds SET left_at = ? WHERE guild_id = ? AND left_at IS NULL`, now, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to record guild leave: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		// Joined before guilds were tracked, or already recorded.
+		if _, err := d.db.Exec(
+			`INSERT OR IGNORE INTO guilds (guild_id, joined_at, left_at) VALUES (?, ?, ?)`,
+			guildID, now, now,
+		); err != nil {
+			return fmt.Errorf("failed to record guild leave: %w", err)
+		}
+	}
+	if _, err := d.db.Exec(`DELETE FROM scheduled_plays WHERE guild_id = ?`, guildID); err != nil {
+		return fmt.Errorf("failed to cancel scheduled plays: %w", err)
+	}
+	return nil
+}
+
+// guildTables lists the statements that delete a guild's rows, children
+// before their parents.
+var guildTables = []string{
+	`DELETE FROM playlist_tracks WHERE playlist_id IN (SELECT id FROM playlists WHERE guild_id = ?)`,
+	`DELETE FROM playlists WHERE guild_id = ?`,
+	`DELETE FROM song_history WHERE guild_id = ?`,
+	`DELETE FROM user_cache WHERE guild_id = ?`,
+	`DELETE FROM user_favorites WHERE guild_id = ?`,
+	`DELETE FROM guild_settings WHERE guild_id = ?`,
+	`DELETE FROM guild_blocked_videos WHERE guild_id = ?`,
+	`DELETE FROM scheduled_plays WHERE guild_id = ?`,
+	`DELETE FROM soundboard_sounds WHERE guild_id = ?`,
+	`DELETE FROM audit_log WHERE guild_id = ?`,
+	`DELETE FROM guilds WHERE guild_id = ?`,
+}
+
+// PurgeGuild deletes everything stored for a guild: settings, history,
+// favorites, playlists, scheduled plays, soundboard sounds and the audit log.
+func (d *Database) PurgeGuild(guildID string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck — superseded by explicit Commit below
+
+	for _, query := range guildTables {
+		if _, err := tx.Exec(query, guildID); err != nil {
+			return fmt.Errorf("failed to purge guild %s: %w", guildID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to purge guild %s: %w", guildID, err)
+	}
+	d.guildSettings.forget(guildID)
+
+	if err := os.RemoveAll(filepath.Join(d.dir, "soundboard", guildID)); err != nil {
+		log.Warnf("Failed to delete soundboard files for guild %s: %v", guildID, err)
+	}
+	return nil
+}
+
+// PurgeLeftGuilds deletes the data of guilds the bot left before cutoff and
+// returns how many were purged.
+func (d *Database) PurgeLeftGuilds(cutoff time.Time) (int, error) {
+	rows, err := d.db.Query(`SELECT guild_id FROM guilds WHERE left_at < ?`, d.db.dialect.timeArg(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query left guilds: %w", err)
+	}
+	var guildIDs []string
+	for rows.Next() {
+		var guildID string
+		if err := rows.Scan(&guildID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan left guild: %w", err)
+		}
+		guildIDs = append(guildIDs, guildID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to query left guilds: %w", err)
+	}
+
+	for i, guildID := range guildIDs {
+		if err := d.PurgeGuild(guildID); err != nil {
+			return i, err
+		}
+	}
+	return len(guildIDs), nil
+}
+
+// StartGuildCleanup deletes the data of guilds the bot left more than 30
+// days ago, now and then daily until ctx is done.
+func (d *Database) StartGuildCleanup(ctx context.Context) {
+	runDaily(ctx, func() {
+		purged, err := d.PurgeLeftGuilds(time.Now().Add(-guildDataGrace))
+		if err != nil {
+			log.Errorf("Error deleting data of departed guilds: %v", err)
+			sentry.CaptureException(err)
+			return
+		}
+		if purged > 0 {
+			log.Infof("Deleted the data of %d guilds the bot left over %d days ago", purged, int(guildDataGrace.Hours()/24))
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuildJoinAndLeave(t *testing.T) {
+	d := newTestDatabase(t)
+
+	if fresh, err := d.RecordGuildJoin("g1", "Lounge"); err != nil || !fresh {
+		t.Fatalf("first RecordGuildJoin = %v, %v; want true", fresh, err)
+	}
+	if fresh, err := d.RecordGuildJoin("g1", "Lounge"); err != nil || fresh {
+		t.Fatalf("reconnect RecordGuildJoin = %v, %v; want false", fresh, err)
+	}
+
+	if err := d.SetGuildSetting("g1", "volume", "40"); err != nil {
+		t.Fatalf("SetGuildSetting: %v", err)
+	}
+	if err := d.AddFavorite("u1", "g1", "vid", "Song", "https://youtu.be/vid", ""); err != nil {
+		t.Fatalf("AddFavorite: %v", err)
+	}
+	if err := d.RecordGuildLeave("g1"); err != nil {
+		t.Fatalf("RecordGuildLeave: %v", err)
+	}
+
+	// Left recently: nothing is purged yet, and rejoining cancels it.
+	if purged, err := d.PurgeLeftGuilds(time.Now().Add(-guildDataGrace)); err != nil || purged != 0 {
+		t.Fatalf("PurgeLeftGuilds within grace = %d, %v", purged, err)
+	}
+	if fresh, err := d.RecordGuildJoin("g1", "Lounge"); err != nil || !fresh {
+		t.Fatalf("rejoin RecordGuildJoin = %v, %v; want true", fresh, err)
+	}
+	if purged, _ := d.PurgeLeftGuilds(time.Now().Add(time.Hour)); purged != 0 {
+		t.Fatalf("purged %d guilds after rejoining", purged)
+	}
+
+	// A guild the bot joined before guilds were tracked is still recorded.
+	if err := d.RecordGuildLeave("g2"); err != nil {
+		t.Fatalf("RecordGuildLeave unknown guild: %v", err)
+	}
+	if err := d.SetGuildSetting("g2", "volume", "70"); err != nil {
+		t.Fatalf("SetGuildSetting: %v", err)
+	}
+	if err := d.RecordGuildLeave("g1"); err != nil {
+		t.Fatalf("RecordGuildLeave: %v", err)
+	}
+
+	purged, err := d.PurgeLeftGuilds(time.Now().Add(time.Hour))
+	if err != nil || purged != 2 {
+		t.Fatalf("PurgeLeftGuilds = %d, %v; want 2", purged, err)
+	}
+	if v, _ := d.GetGuildSetting("g1", "volume"); v != "" {
+		t.Errorf("g1 volume after purge = %q", v)
+	}
+	if v, _ := d.GetGuildSetting("g2", "volume"); v != "" {
+		t.Errorf("g2 volume after purge = %q", v)
+	}
+	if d.IsFavorite("u1", "g1", "vid") {
+		t.Error("favorite survived the purge")
+	}
+	if fresh, _ := d.RecordGuildJoin("g1", "Lounge"); !fresh {
+		t.Error("purged guild wasn't new when added back")
+	}
+}
+
+func TestForgetUser(t *testing.T) {
+	d := newTestDatabase(t)
+
+	if err := d.AddFavorite("u1", "g1", "vid", "Song", "https://youtu.be/vid", ""); err != nil {
+		t.Fatalf("AddFavorite: %v", err)
+	}
+	if err := d.AddFavorite("u2", "g1", "vid", "Song", "https://youtu.be/vid", ""); err != nil {
+		t.Fatalf("AddFavorite: %v", err)
+	}
+	if err := d.SetUserSetting("u1", PrefLanguage, "de"); err != nil {
+		t.Fatalf("SetUserSetting: %v", err)
+	}
+	if _, err := d.CreatePlaylist("g1", "u1", "Mine", "u1"); err != nil {
+		t.Fatalf("CreatePlaylist personal: %v", err)
+	}
+	shared, err := d.CreatePlaylist("g1", "", "Shared", "u1")
+	if err != nil {
+		t.Fatalf("CreatePlaylist guild: %v", err)
+	}
+	if _, err := d.AddPlaylistTrack(shared.ID, PlaylistTrack{VideoID: "vid", Title: "Song", AddedBy: "u1"}); err != nil {
+		t.Fatalf("AddPlaylistTrack: %v", err)
+	}
+	// Load the preference into the cache so forgetting has to drop it.
+	if prefs, _ := d.GetUserPreferences("u1"); prefs.Language != "de" {
+		t.Fatalf("language before forgetting = %q", prefs.Language)
+	}
+
+	if err := d.ForgetUser("u1"); err != nil {
+		t.Fatalf("ForgetUser: %v", err)
+	}
+
+	if d.IsFavorite("u1", "g1", "vid") {
+		t.Error("u1's favorite survived")
+	}
+	if !d.IsFavorite("u2", "g1", "vid") {
+		t.Error("u2's favorite was deleted")
+	}
+	if prefs, _ := d.GetUserPreferences("u1"); prefs.Language != "" {
+		t.Errorf("language after forgetting = %q", prefs.Language)
+	}
+	if p, err := d.FindPlaylist("g1", "u1", "Mine"); err == nil && p != nil {
+		t.Errorf("personal playlist survived: %+v", p)
+	}
+	p, err := d.FindPlaylist("g1", "u2", "Shared")
+	if err != nil || p == nil {
+		t.Fatalf("guild playlist = %+v, %v; want it kept", p, err)
+	}
+	if p.CreatedBy != "" {
+		t.Errorf("guild playlist still credits %q", p.CreatedBy)
+	}
+	tracks, err := d.GetPlaylistTracks(p.ID)
+	if err != nil || len(tracks) != 1 || tracks[0].AddedBy != "" {
+		t.Errorf("guild playlist tracks = %+v, %v", tracks, err)
+	}
+}
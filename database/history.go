@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// historyPruneInterval is how often plays past the retention period are
+// deleted.
+const historyPruneInterval = 24 * time.Hour
+
+// historyPruneBatch caps how many rows one DELETE removes, so a first prune
+// of a large history doesn't hold the write lock long enough to stall plays
+// being recorded.
+const historyPruneBatch = 5000
+
+// PruneHistory deletes plays from before cutoff in every guild and returns
+// how many were removed.
+func (d *Database) PruneHistory(cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		res, err := d.db.Exec(
+			// The extra derived table is for MySQL, which can't LIMIT an IN
+			// subquery or select from the table it's deleting from.
+			`DELETE FROM song_history WHERE id IN (
+				SELECT id FROM (SELECT id FROM song_history WHERE played_at < ? LIMIT ?) AS old
+			)`,
+			d.db.dialect.timeArg(cutoff), historyPruneBatch,
+		)
+		if err != nil {
+			return total, fmt.Errorf("failed to prune history: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("failed to prune history: %w", err)
+		}
+		total += n
+		if n < historyPruneBatch {
+			return total, nil
+		}
+	}
+}
+
+// StartHistoryPruning deletes plays older than retention now and then daily
+// until ctx is done. It does nothing when retention is 0.
+func (d *Database) StartHistoryPruning(ctx context.Context, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	prune := func() {
+		removed, err := d.PruneHistory(time.Now().Add(-retention))
+		if err != nil {
+			log.Errorf("Error pruning play history: %v", err)
+			sentry.CaptureException(err)
+			return
+		}
+		if removed > 0 {
+			log.Infof("Pruned %d plays older than %d days from history", removed, int(retention.Hours()/24))
+		}
+	}
+
+	runDaily(ctx, prune)
+}
+
+// runDaily runs job now and then once a day until ctx is done.
+func runDaily(ctx context.Context, job func()) {
+	go func() {
+		job()
+		ticker := time.NewTicker(historyPruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job()
+			}
+		}
+	}()
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPruneHistory(t *testing.T) {
+	d := newTestDatabase(t)
+
+	now := time.Now()
+	plays := []struct {
+		guild, video string
+		at           time.Time
+	}{
+		{"g1", "old", now.Add(-400 * 24 * time.Hour)},
+		{"g2", "old", now.Add(-366 * 24 * time.Hour)},
+		{"g1", "recent", now.Add(-time.Hour)},
+	}
+	for _, p := range plays {
+		id, err := d.RecordPlay(p.guild, p.video, p.video, "", "u1", "user", 180)
+		if err != nil {
+			t.Fatalf("RecordPlay: %v", err)
+		}
+		if _, err := d.db.Exec(`UPDATE song_history SET played_at = ? WHERE id = ?`, p.at.UTC().Format(time.RFC3339Nano), id); err != nil {
+			t.Fatalf("backdating play: %v", err)
+		}
+	}
+	// Plays recorded before played_at was written explicitly use SQLite's
+	// CURRENT_TIMESTAMP format.
+	if _, err := d.db.Exec(`INSERT INTO song_history (guild_id, video_id, title, played_at) VALUES ('g1', 'legacy', 'legacy', '2020-01-01 12:00:00')`); err != nil {
+		t.Fatalf("inserting legacy play: %v", err)
+	}
+
+	removed, err := d.PruneHistory(now.Add(-365 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("PruneHistory: %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("PruneHistory removed %d plays, want 3", removed)
+	}
+	records, err := d.GetHistory("g1", 10)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(records) != 1 || records[0].VideoID != "recent" {
+		t.Errorf("history after pruning = %+v, want just the recent play", records)
+	}
+}
@@ -0,0 +1,223 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Schema changes are numbered SQL files in migrations/<driver>/:
+// NNNN_name.up.sql applies one and NNNN_name.down.sql undoes it. Add a new
+// pair for every change rather than editing a migration that has shipped,
+// and add it for every driver under the same number.
+//
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// loadMigrations reads the migration pairs in dir, in version order. Versions
+// must run 1, 2, 3... with no gaps, and each needs both an up and a down.
+func loadMigrations(fsys fs.FS, dir string) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), ".")
+		number, name, named := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || !named || err != nil || version < 1 || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("bad migration file name %q, want NNNN_name.up.sql or NNNN_name.down.sql", file)
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &migration{version: version, name: name}
+			byVersion[version] = m
+		} else if m.name != name {
+			return nil, fmt.Errorf("migration %d is named both %q and %q", version, m.name, name)
+		}
+		if direction == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	for i, m := range migrations {
+		if m.version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing", i+1)
+		}
+		if strings.TrimSpace(m.up) == "" || strings.TrimSpace(m.down) == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", m.version, m.name)
+		}
+	}
+	return migrations, nil
+}
+
+// migrate brings the schema up to the latest version. A database from before
+// migrations were versioned already has the baseline tables, so it's adopted
+// at version 1 after adding any columns it was still missing.
+func (d *Database) migrate() error {
+	migrations, err := d.migrations()
+	if err != nil {
+		return err
+	}
+	if _, err := d.db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_version: %w", err)
+	}
+
+	current, err := d.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database schema is at version %d but this build only knows %d; migrate it down with the newer build first", current, len(migrations))
+	}
+	if _, ok := d.db.dialect.(sqliteDialect); ok && current == 0 {
+		legacy, err := d.tableExists("song_history")
+		if err != nil {
+			return err
+		}
+		if legacy {
+			log.Info("Adopting an unversioned database at schema version 1")
+			if err := d.applyMigration(migrations[0], true); err != nil {
+				return err
+			}
+			// Columns added to the baseline tables before versioning.
+			if err := d.addColumn("song_history", "skipped", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+				return err
+			}
+			if err := d.addColumn("user_favorites", "source", "TEXT NOT NULL DEFAULT ''"); err != nil {
+				return err
+			}
+		}
+	}
+	return d.migrateTo(migrations, len(migrations))
+}
+
+// SchemaVersion is the version of the last migration applied, or 0 for an
+// empty database.
+func (d *Database) SchemaVersion() (int, error) {
+	var version int
+	if err := d.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// LatestSchemaVersion is the version New migrates the database up to.
+func (d *Database) LatestSchemaVersion() (int, error) {
+	migrations, err := d.migrations()
+	if err != nil {
+		return 0, err
+	}
+	return len(migrations), nil
+}
+
+func (d *Database) migrations() ([]migration, error) {
+	return loadMigrations(migrationFiles, d.db.dialect.migrationsDir())
+}
+
+// MigrateTo applies up or down migrations until the schema is at version, for
+// rolling a deploy back. Down migrations can drop data, so they're only run
+// when asked for; New never migrates down.
+func (d *Database) MigrateTo(version int) error {
+	migrations, err := d.migrations()
+	if err != nil {
+		return err
+	}
+	if version < 0 || version > len(migrations) {
+		return fmt.Errorf("no schema version %d; the latest is %d", version, len(migrations))
+	}
+	return d.migrateTo(migrations, version)
+}
+
+func (d *Database) migrateTo(migrations []migration, version int) error {
+	current, err := d.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	for ; current < version; current++ {
+		if err := d.applyMigration(migrations[current], true); err != nil {
+			return err
+		}
+	}
+	for ; current > version; current-- {
+		if err := d.applyMigration(migrations[current-1], false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one migration in either direction and records it, in a
+// single transaction so a failure leaves the schema as it was. MySQL commits
+// schema changes as they run, so there a failed migration needs cleaning up
+// by hand.
+func (d *Database) applyMigration(m migration, up bool) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start migration %04d_%s: %w", m.version, m.name, err)
+	}
+	defer tx.Rollback()
+
+	script, record, args := m.down, `DELETE FROM schema_version WHERE version = ?`, []any{m.version}
+	if up {
+		script, record, args = m.up, `INSERT INTO schema_version (version, name) VALUES (?, ?)`, []any{m.version, m.name}
+	}
+	// Migration files are already in the database's own dialect.
+	if _, err := tx.Tx.Exec(script); err != nil {
+		return fmt.Errorf("migration %04d_%s failed: %w", m.version, m.name, err)
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return fmt.Errorf("failed to record migration %04d_%s: %w", m.version, m.name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %04d_%s: %w", m.version, m.name, err)
+	}
+
+	direction := "Applied"
+	if !up {
+		direction = "Reverted"
+	}
+	log.Infof("%s migration %04d_%s", direction, m.version, m.name)
+	return nil
+}
+
+func (d *Database) tableExists(name string) (bool, error) {
+	var count int
+	err := d.db.QueryRow(d.db.dialect.tableExistsQuery(), name).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS soundboard_sounds;
+DROP TABLE IF EXISTS user_settings;
+DROP TABLE IF EXISTS scheduled_plays;
+DROP TABLE IF EXISTS playlist_tracks;
+DROP TABLE IF EXISTS playlists;
+DROP TABLE IF EXISTS title_translations;
+DROP TABLE IF EXISTS guild_blocked_videos;
+DROP TABLE IF EXISTS guild_settings;
+DROP TABLE IF EXISTS user_favorites;
+DROP TABLE IF EXISTS user_cache;
+DROP TABLE IF EXISTS song_history;
@@ -0,0 +1,117 @@
+-- The MySQL schema matching SQLite's baseline. Keyed text columns are
+-- VARCHAR, since MySQL can't index TEXT without a prefix length; Discord
+-- snowflakes and video IDs fit easily. The default utf8mb4 collation already
+-- compares names ignoring case, like SQLite's COLLATE NOCASE.
+
+CREATE TABLE IF NOT EXISTS song_history (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    guild_id VARCHAR(32) NOT NULL,
+    video_id VARCHAR(255) NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    requested_by_user_id VARCHAR(32) NOT NULL DEFAULT '',
+    requested_by_username VARCHAR(255) NOT NULL DEFAULT '',
+    played_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    duration_seconds INT NOT NULL DEFAULT 0,
+    skipped INT NOT NULL DEFAULT 0,
+    INDEX idx_song_history_guild_id (guild_id),
+    INDEX idx_song_history_played_at (guild_id, played_at DESC),
+    INDEX idx_song_history_video_id (guild_id, video_id)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS user_cache (
+    user_id VARCHAR(32) NOT NULL,
+    guild_id VARCHAR(32) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    cached_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (guild_id, user_id)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS user_favorites (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_id VARCHAR(32) NOT NULL,
+    guild_id VARCHAR(32) NOT NULL,
+    video_id VARCHAR(255) NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    source VARCHAR(32) NOT NULL DEFAULT '',
+    added_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_user_favorites_user_guild (guild_id, user_id),
+    UNIQUE INDEX idx_user_favorites_unique (user_id, guild_id, video_id)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS guild_settings (
+    guild_id VARCHAR(32) NOT NULL,
+    `key` VARCHAR(64) NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (guild_id, `key`)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS guild_blocked_videos (
+    guild_id   VARCHAR(32) NOT NULL,
+    video_id   VARCHAR(255) NOT NULL,
+    title      TEXT NOT NULL,
+    url        TEXT NOT NULL,
+    blocked_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (guild_id, video_id)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS title_translations (
+    video_id    VARCHAR(255) PRIMARY KEY,
+    translation TEXT NOT NULL,
+    created_at  DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS playlists (
+    id         BIGINT PRIMARY KEY AUTO_INCREMENT,
+    guild_id   VARCHAR(32) NOT NULL,
+    owner_id   VARCHAR(32) NOT NULL DEFAULT '',
+    name       VARCHAR(100) NOT NULL,
+    created_by VARCHAR(32) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE (guild_id, owner_id, name)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS playlist_tracks (
+    id          BIGINT PRIMARY KEY AUTO_INCREMENT,
+    playlist_id BIGINT NOT NULL,
+    video_id    VARCHAR(255) NOT NULL,
+    title       TEXT NOT NULL,
+    url         TEXT NOT NULL,
+    source      VARCHAR(32) NOT NULL DEFAULT '',
+    added_by    VARCHAR(32) NOT NULL,
+    added_at    DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE (playlist_id, video_id)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS scheduled_plays (
+    id               BIGINT PRIMARY KEY AUTO_INCREMENT,
+    guild_id         VARCHAR(32) NOT NULL,
+    voice_channel_id VARCHAR(32) NOT NULL,
+    text_channel_id  VARCHAR(32) NOT NULL DEFAULT '',
+    user_id          VARCHAR(32) NOT NULL,
+    playlist_id      BIGINT NOT NULL DEFAULT 0,
+    video_id         VARCHAR(255) NOT NULL DEFAULT '',
+    title            TEXT NOT NULL,
+    url              TEXT NOT NULL,
+    source           VARCHAR(32) NOT NULL DEFAULT '',
+    run_at           BIGINT NOT NULL,
+    INDEX idx_scheduled_plays_run_at (run_at)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id VARCHAR(32) NOT NULL,
+    `key`   VARCHAR(64) NOT NULL,
+    value   TEXT NOT NULL,
+    PRIMARY KEY (user_id, `key`)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS soundboard_sounds (
+    id          BIGINT PRIMARY KEY AUTO_INCREMENT,
+    guild_id    VARCHAR(32) NOT NULL,
+    name        VARCHAR(100) NOT NULL,
+    duration_ms INT NOT NULL,
+    uploaded_by VARCHAR(32) NOT NULL,
+    uploaded_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE (guild_id, name)
+) DEFAULT CHARSET = utf8mb4;
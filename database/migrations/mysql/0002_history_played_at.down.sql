@@ -0,0 +1 @@
+DROP INDEX idx_song_history_played_at_all ON song_history;
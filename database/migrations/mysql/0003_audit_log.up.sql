@@ -0,0 +1,14 @@
+-- Who ran which command in each guild, for /auditlog.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    guild_id VARCHAR(32) NOT NULL,
+    user_id VARCHAR(32) NOT NULL,
+    username VARCHAR(255) NOT NULL DEFAULT '',
+    command VARCHAR(64) NOT NULL,
+    options TEXT NOT NULL,
+    outcome VARCHAR(16) NOT NULL DEFAULT 'ok',
+    detail TEXT NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_audit_log_guild (guild_id, created_at DESC),
+    INDEX idx_audit_log_created_at (created_at)
+) DEFAULT CHARSET = utf8mb4;
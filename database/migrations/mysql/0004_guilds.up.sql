@@ -0,0 +1,9 @@
+-- Servers the bot has been added to. left_at is set when it's removed, and
+-- the server's data is deleted once it has been gone long enough.
+CREATE TABLE IF NOT EXISTS guilds (
+    guild_id  VARCHAR(32) PRIMARY KEY,
+    name      VARCHAR(255) NOT NULL DEFAULT '',
+    joined_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    left_at   DATETIME(6) NULL,
+    INDEX idx_guilds_left_at (left_at)
+) DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,2 @@
+-- How long each played song is, so skips can be placed within it.
+ALTER TABLE song_history ADD COLUMN track_seconds INT NOT NULL DEFAULT 0;
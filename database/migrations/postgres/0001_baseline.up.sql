@@ -0,0 +1,115 @@
+-- The Postgres schema matching SQLite's baseline. Names that SQLite compares
+-- with COLLATE NOCASE get unique indexes on LOWER(name) instead.
+
+CREATE TABLE IF NOT EXISTS song_history (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    video_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    requested_by_user_id TEXT NOT NULL DEFAULT '',
+    requested_by_username TEXT NOT NULL DEFAULT '',
+    played_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_song_history_guild_id ON song_history(guild_id);
+CREATE INDEX IF NOT EXISTS idx_song_history_played_at ON song_history(guild_id, played_at DESC);
+CREATE INDEX IF NOT EXISTS idx_song_history_video_id ON song_history(guild_id, video_id);
+
+CREATE TABLE IF NOT EXISTS user_cache (
+    user_id TEXT NOT NULL,
+    guild_id TEXT NOT NULL,
+    username TEXT NOT NULL,
+    cached_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (guild_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_favorites (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    guild_id TEXT NOT NULL,
+    video_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT '',
+    added_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_user_favorites_user_guild ON user_favorites(guild_id, user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_favorites_unique ON user_favorites(user_id, guild_id, video_id);
+
+CREATE TABLE IF NOT EXISTS guild_settings (
+    guild_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (guild_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS guild_blocked_videos (
+    guild_id   TEXT NOT NULL,
+    video_id   TEXT NOT NULL,
+    title      TEXT NOT NULL DEFAULT '',
+    url        TEXT NOT NULL DEFAULT '',
+    blocked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (guild_id, video_id)
+);
+
+CREATE TABLE IF NOT EXISTS title_translations (
+    video_id    TEXT PRIMARY KEY,
+    translation TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS playlists (
+    id         BIGSERIAL PRIMARY KEY,
+    guild_id   TEXT NOT NULL,
+    owner_id   TEXT NOT NULL DEFAULT '',
+    name       TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playlists_name ON playlists(guild_id, owner_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS playlist_tracks (
+    id          BIGSERIAL PRIMARY KEY,
+    playlist_id BIGINT NOT NULL,
+    video_id    TEXT NOT NULL,
+    title       TEXT NOT NULL,
+    url         TEXT NOT NULL DEFAULT '',
+    source      TEXT NOT NULL DEFAULT '',
+    added_by    TEXT NOT NULL,
+    added_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (playlist_id, video_id)
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_plays (
+    id               BIGSERIAL PRIMARY KEY,
+    guild_id         TEXT NOT NULL,
+    voice_channel_id TEXT NOT NULL,
+    text_channel_id  TEXT NOT NULL DEFAULT '',
+    user_id          TEXT NOT NULL,
+    playlist_id      BIGINT NOT NULL DEFAULT 0,
+    video_id         TEXT NOT NULL DEFAULT '',
+    title            TEXT NOT NULL,
+    url              TEXT NOT NULL DEFAULT '',
+    source           TEXT NOT NULL DEFAULT '',
+    run_at           BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_scheduled_plays_run_at ON scheduled_plays(run_at);
+
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id TEXT NOT NULL,
+    key     TEXT NOT NULL,
+    value   TEXT NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS soundboard_sounds (
+    id          BIGSERIAL PRIMARY KEY,
+    guild_id    TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    uploaded_by TEXT NOT NULL,
+    uploaded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_soundboard_sounds_name ON soundboard_sounds(guild_id, LOWER(name));
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_song_history_played_at_all;
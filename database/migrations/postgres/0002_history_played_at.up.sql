@@ -0,0 +1,2 @@
+-- Lets history pruning find old plays across every guild without a full scan.
+CREATE INDEX IF NOT EXISTS idx_song_history_played_at_all ON song_history(played_at);
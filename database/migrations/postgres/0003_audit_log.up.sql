@@ -0,0 +1,14 @@
+-- Who ran which command in each guild, for /auditlog.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    username TEXT NOT NULL DEFAULT '',
+    command TEXT NOT NULL,
+    options TEXT NOT NULL DEFAULT '',
+    outcome TEXT NOT NULL DEFAULT 'ok',
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_guild ON audit_log(guild_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
@@ -0,0 +1 @@
+ALTER TABLE song_history DROP COLUMN track_seconds;
@@ -0,0 +1,130 @@
+-- The schema as it stood before migrations were versioned. Statements are
+-- IF NOT EXISTS so databases created before then can adopt it (see migrate).
+
+CREATE TABLE IF NOT EXISTS song_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    guild_id TEXT NOT NULL,
+    video_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    requested_by_user_id TEXT NOT NULL DEFAULT '',
+    requested_by_username TEXT NOT NULL DEFAULT '',
+    played_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_song_history_guild_id ON song_history(guild_id);
+CREATE INDEX IF NOT EXISTS idx_song_history_played_at ON song_history(guild_id, played_at DESC);
+CREATE INDEX IF NOT EXISTS idx_song_history_video_id ON song_history(guild_id, video_id);
+
+CREATE TABLE IF NOT EXISTS user_cache (
+    user_id TEXT NOT NULL,
+    guild_id TEXT NOT NULL,
+    username TEXT NOT NULL,
+    cached_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (guild_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_cache_lookup ON user_cache(guild_id, user_id);
+
+CREATE TABLE IF NOT EXISTS user_favorites (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    guild_id TEXT NOT NULL,
+    video_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT '',
+    added_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_user_favorites_user_guild ON user_favorites(guild_id, user_id);
+
+-- Enforce uniqueness at the DB level so duplicate favorites can never be
+-- inserted even under concurrent load. INSERT OR IGNORE in AddFavorite
+-- relies on this constraint for idempotency.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_favorites_unique ON user_favorites(user_id, guild_id, video_id);
+
+CREATE TABLE IF NOT EXISTS guild_settings (
+    guild_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (guild_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS guild_blocked_videos (
+    guild_id   TEXT NOT NULL,
+    video_id   TEXT NOT NULL,
+    title      TEXT NOT NULL DEFAULT '',
+    url        TEXT NOT NULL DEFAULT '',
+    blocked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (guild_id, video_id)
+);
+
+-- Gemini title translations are keyed by video ID only — a translation
+-- doesn't depend on which guild queued the song. An empty translation
+-- means "checked, nothing to show" so we don't ask Gemini again.
+CREATE TABLE IF NOT EXISTS title_translations (
+    video_id    TEXT PRIMARY KEY,
+    translation TEXT NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- owner_id is the user for personal playlists and '' for playlists
+-- shared with the whole guild. Names are unique per owner, ignoring case.
+CREATE TABLE IF NOT EXISTS playlists (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    guild_id   TEXT NOT NULL,
+    owner_id   TEXT NOT NULL DEFAULT '',
+    name       TEXT NOT NULL COLLATE NOCASE,
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (guild_id, owner_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS playlist_tracks (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    playlist_id INTEGER NOT NULL,
+    video_id    TEXT NOT NULL,
+    title       TEXT NOT NULL,
+    url         TEXT NOT NULL DEFAULT '',
+    source      TEXT NOT NULL DEFAULT '',
+    added_by    TEXT NOT NULL,
+    added_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (playlist_id, video_id)
+);
+
+-- A scheduled /playat: either one song (video_id etc.) or a playlist
+-- (playlist_id, with title holding its name). run_at is a unix time.
+CREATE TABLE IF NOT EXISTS scheduled_plays (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    guild_id         TEXT NOT NULL,
+    voice_channel_id TEXT NOT NULL,
+    text_channel_id  TEXT NOT NULL DEFAULT '',
+    user_id          TEXT NOT NULL,
+    playlist_id      INTEGER NOT NULL DEFAULT 0,
+    video_id         TEXT NOT NULL DEFAULT '',
+    title            TEXT NOT NULL,
+    url              TEXT NOT NULL DEFAULT '',
+    source           TEXT NOT NULL DEFAULT '',
+    run_at           INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_scheduled_plays_run_at ON scheduled_plays(run_at);
+
+-- Per-user preferences that follow them across servers, like /notifyme.
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id TEXT NOT NULL,
+    key     TEXT NOT NULL,
+    value   TEXT NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+-- Soundboard sounds; the audio itself is a file named by id under
+-- the soundboard directory (see soundPath).
+CREATE TABLE IF NOT EXISTS soundboard_sounds (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    guild_id    TEXT NOT NULL,
+    name        TEXT NOT NULL COLLATE NOCASE,
+    duration_ms INTEGER NOT NULL,
+    uploaded_by TEXT NOT NULL,
+    uploaded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (guild_id, name)
+);
@@ -0,0 +1,9 @@
+-- Servers the bot has been added to. left_at is set when it's removed, and
+-- the server's data is deleted once it has been gone long enough.
+CREATE TABLE IF NOT EXISTS guilds (
+    guild_id  TEXT PRIMARY KEY,
+    name      TEXT NOT NULL DEFAULT '',
+    joined_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    left_at   DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_guilds_left_at ON guilds(left_at);
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestMigrateFreshDatabase(t *testing.T) {
+	d := newTestDatabase(t)
+
+	latest, err := d.LatestSchemaVersion()
+	if err != nil {
+		t.Fatalf("LatestSchemaVersion: %v", err)
+	}
+	if v, err := d.SchemaVersion(); err != nil || v != latest {
+		t.Fatalf("SchemaVersion = %d, %v; want %d", v, err, latest)
+	}
+	if err := d.AddFavorite("u1", "g1", "abc", "Song", "", "soundcloud"); err != nil {
+		t.Errorf("AddFavorite on a fresh schema: %v", err)
+	}
+}
+
+func TestMigrateAdoptsUnversionedDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	raw, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	// song_history and user_favorites as they first shipped, with a row that
+	// has to survive adoption.
+	for _, stmt := range []string{
+		`CREATE TABLE song_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			guild_id TEXT NOT NULL,
+			video_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			url TEXT NOT NULL DEFAULT '',
+			requested_by_user_id TEXT NOT NULL DEFAULT '',
+			requested_by_username TEXT NOT NULL DEFAULT '',
+			played_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			duration_seconds INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE user_favorites (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			guild_id TEXT NOT NULL,
+			video_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			url TEXT NOT NULL DEFAULT '',
+			added_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`INSERT INTO song_history (guild_id, video_id, title) VALUES ('g1', 'abc', 'Old Song')`,
+	} {
+		if _, err := raw.Exec(stmt); err != nil {
+			t.Fatalf("setting up legacy schema: %v", err)
+		}
+	}
+	raw.Close()
+
+	t.Setenv("DB_PATH", dbPath)
+	d, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer d.Close()
+
+	latest, _ := d.LatestSchemaVersion()
+	if v, _ := d.SchemaVersion(); v != latest {
+		t.Errorf("SchemaVersion = %d, want %d", v, latest)
+	}
+	var skipped int
+	if err := d.db.QueryRow(`SELECT skipped FROM song_history WHERE video_id = 'abc'`).Scan(&skipped); err != nil {
+		t.Errorf("old play after adoption: %v", err)
+	}
+	if err := d.AddFavorite("u1", "g1", "abc", "Song", "", "soundcloud"); err != nil {
+		t.Errorf("AddFavorite after adoption: %v", err)
+	}
+}
+
+func TestMigrateDownAndUp(t *testing.T) {
+	d := newTestDatabase(t)
+	latest, _ := d.LatestSchemaVersion()
+
+	if err := d.MigrateTo(0); err != nil {
+		t.Fatalf("MigrateTo(0): %v", err)
+	}
+	if v, _ := d.SchemaVersion(); v != 0 {
+		t.Errorf("SchemaVersion after down = %d, want 0", v)
+	}
+	if exists, _ := d.tableExists("song_history"); exists {
+		t.Error("song_history still exists after migrating down")
+	}
+
+	if err := d.MigrateTo(latest); err != nil {
+		t.Fatalf("MigrateTo(%d): %v", latest, err)
+	}
+	if v, _ := d.SchemaVersion(); v != latest {
+		t.Errorf("SchemaVersion after up = %d, want %d", v, latest)
+	}
+	if err := d.MigrateTo(latest + 1); err == nil {
+		t.Error("MigrateTo past the latest version succeeded")
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(body string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(body)} }
+
+	migrations, err := loadMigrations(fstest.MapFS{
+		"m/0002_second.up.sql":   file("CREATE TABLE b (x)"),
+		"m/0002_second.down.sql": file("DROP TABLE b"),
+		"m/0001_first.up.sql":    file("CREATE TABLE a (x)"),
+		"m/0001_first.down.sql":  file("DROP TABLE a"),
+	}, "m")
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].name != "first" || migrations[1].down != "DROP TABLE b" {
+		t.Errorf("migrations = %+v, want first then second", migrations)
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"gap": {
+			"m/0001_first.up.sql": file("x"), "m/0001_first.down.sql": file("x"),
+			"m/0003_third.up.sql": file("x"), "m/0003_third.down.sql": file("x"),
+		},
+		"no down":  {"m/0001_first.up.sql": file("x")},
+		"bad name": {"m/first.up.sql": file("x"), "m/first.down.sql": file("x")},
+	} {
+		if _, err := loadMigrations(fsys, "m"); err == nil {
+			t.Errorf("%s: loadMigrations succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrPlaylistExists   = errors.New("playlist already exists")
+	ErrPlaylistNotFound = errors.New("playlist not found")
+)
+
+// Playlist is a named list of tracks kept by the bot. OwnerID is "" for
+// playlists shared with the whole guild.
+type Playlist struct {
+	ID         int64
+	GuildID    string
+	OwnerID    string
+	Name       string
+	CreatedBy  string
+	TrackCount int
+}
+
+// Shared reports whether the playlist belongs to the guild rather than one user.
+func (p Playlist) Shared() bool {
+	return p.OwnerID == ""
+}
+
+type PlaylistTrack struct {
+	ID      int64
+	VideoID string
+	Title   string
+	URL     string
+	Source  string // resolver that produced the track; "" means YouTube
+	AddedBy string
+}
+
+// CreatePlaylist makes an empty playlist. ownerID is "" for a guild playlist.
+// Returns ErrPlaylistExists if the owner already has one by that name.
+func (d *Database) CreatePlaylist(guildID, ownerID, name, createdBy string) (*Playlist, error) {
+	id, err := d.insert(
+		`INSERT OR IGNORE INTO playlists (guild_id, owner_id, name, created_by) VALUES (?, ?, ?, ?)`,
+		guildID, ownerID, name, createdBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create playlist: %w", err)
+	}
+	if id == 0 {
+		return nil, ErrPlaylistExists
+	}
+	return &Playlist{ID: id, GuildID: guildID, OwnerID: ownerID, Name: name, CreatedBy: createdBy}, nil
+}
+
+// FindPlaylist looks a playlist up by name for userID, preferring the user's
+// own playlist over a guild one with the same name.
+func (d *Database) FindPlaylist(guildID, userID, name string) (*Playlist, error) {
+	var p Playlist
+	err := d.db.QueryRow(
+		`SELECT p.id, p.guild_id, p.owner_id, p.name, p.created_by,
+		        (SELECT COUNT(*) FROM playlist_tracks t WHERE t.playlist_id = p.id)
+		 FROM playlists p
+		 WHERE p.guild_id = ? AND LOWER(p.name) = LOWER(?) AND p.owner_id IN (?, '')
+		 ORDER BY p.owner_id = '' ASC
+		 LIMIT 1`,
+		guildID, strings.TrimSpace(name), userID,
+	).Scan(&p.ID, &p.GuildID, &p.OwnerID, &p.Name, &p.CreatedBy, &p.TrackCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find playlist: %w", err)
+	}
+	return &p, nil
+}
+
+// ListPlaylists returns the playlists userID can see in a guild: their own,
+// then the guild's, each alphabetically.
+func (d *Database) ListPlaylists(guildID, userID string) ([]Playlist, error) {
+	rows, err := d.db.Query(
+		`SELECT p.id, p.guild_id, p.owner_id, p.name, p.created_by, COUNT(t.id)
+		 FROM playlists p
+		 LEFT JOIN playlist_tracks t ON t.playlist_id = p.id
+		 WHERE p.guild_id = ? AND p.owner_id IN (?, '')
+		 GROUP BY p.id
+		 ORDER BY p.owner_id = '' ASC, LOWER(p.name) ASC`,
+		guildID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+	defer rows.Close()
+
+	var playlists []Playlist
+	for rows.Next() {
+		var p Playlist
+		if err := rows.Scan(&p.ID, &p.GuildID, &p.OwnerID, &p.Name, &p.CreatedBy, &p.TrackCount); err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, p)
+	}
+	return playlists, rows.Err()
+}
+
+// AddPlaylistTrack appends a track to a playlist. Returns false if the track
+// was already on it.
+func (d *Database) AddPlaylistTrack(playlistID int64, track PlaylistTrack) (bool, error) {
+	result, err := d.db.Exec(
+		`INSERT OR IGNORE INTO playlist_tracks (playlist_id, video_id, title, url, source, added_by)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		playlistID, track.VideoID, track.Title, track.URL, track.Source, track.AddedBy,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to add playlist track: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// GetPlaylistTracks returns a playlist's tracks in the order they were added.
+func (d *Database) GetPlaylistTracks(playlistID int64) ([]PlaylistTrack, error) {
+	rows, err := d.db.Query(
+		`SELECT id, video_id, title, url, source, added_by
+		 FROM playlist_tracks
+		 WHERE playlist_id = ?
+		 ORDER BY id ASC`,
+		playlistID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query playlist tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []PlaylistTrack
+	for rows.Next() {
+		var t PlaylistTrack
+		if err := rows.Scan(&t.ID, &t.VideoID, &t.Title, &t.URL, &t.Source, &t.AddedBy); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, t)
+	}
+	return tracks, rows.Err()
+}
+
+// RemovePlaylistTrack removes the Nth track (1-based) from a playlist and
+// returns its title. The SELECT and DELETE share a transaction, as in
+// RemoveFavoriteByIndex.
+func (d *Database) RemovePlaylistTrack(playlistID int64, index int) (string, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck — superseded by explicit Commit below
+
+	var id int64
+	var title string
+	err = tx.QueryRow(
+		`SELECT id, title FROM playlist_tracks WHERE playlist_id = ? ORDER BY id ASC LIMIT 1 OFFSET ?`,
+		playlistID, index-1,
+	).Scan(&id, &title)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM playlist_tracks WHERE id = ?`, id); err != nil {
+		return "", fmt.Errorf("failed to delete playlist track: %w", err)
+	}
+
+	return title, tx.Commit()
+}
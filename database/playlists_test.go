@@ -0,0 +1,60 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlaylists(t *testing.T) {
+	d := newTestDatabase(t)
+
+	mine, err := d.CreatePlaylist("g1", "u1", "Road Trip", "u1")
+	if err != nil {
+		t.Fatalf("CreatePlaylist: %v", err)
+	}
+	if _, err := d.CreatePlaylist("g1", "u1", "road trip", "u1"); !errors.Is(err, ErrPlaylistExists) {
+		t.Errorf("duplicate name (different case) err = %v, want ErrPlaylistExists", err)
+	}
+	shared, err := d.CreatePlaylist("g1", "", "Road Trip", "u2")
+	if err != nil {
+		t.Fatalf("CreatePlaylist shared: %v", err)
+	}
+
+	// The user's own playlist wins over the guild's; others get the guild's.
+	if p, err := d.FindPlaylist("g1", "u1", " ROAD TRIP "); err != nil || p.ID != mine.ID {
+		t.Errorf("FindPlaylist for owner = %+v, %v; want personal playlist", p, err)
+	}
+	if p, err := d.FindPlaylist("g1", "u3", "Road Trip"); err != nil || p.ID != shared.ID || !p.Shared() {
+		t.Errorf("FindPlaylist for other user = %+v, %v; want shared playlist", p, err)
+	}
+	if _, err := d.FindPlaylist("g2", "u1", "Road Trip"); !errors.Is(err, ErrPlaylistNotFound) {
+		t.Errorf("other guild err = %v, want ErrPlaylistNotFound", err)
+	}
+
+	for _, id := range []string{"a", "b", "c"} {
+		added, err := d.AddPlaylistTrack(mine.ID, PlaylistTrack{VideoID: id, Title: "Song " + id, AddedBy: "u1"})
+		if err != nil || !added {
+			t.Fatalf("AddPlaylistTrack(%s) = %v, %v", id, added, err)
+		}
+	}
+	if added, _ := d.AddPlaylistTrack(mine.ID, PlaylistTrack{VideoID: "a", Title: "Song a", AddedBy: "u1"}); added {
+		t.Error("adding a track twice should be a no-op")
+	}
+
+	title, err := d.RemovePlaylistTrack(mine.ID, 2)
+	if err != nil || title != "Song b" {
+		t.Errorf("RemovePlaylistTrack = %q, %v; want Song b", title, err)
+	}
+	tracks, err := d.GetPlaylistTracks(mine.ID)
+	if err != nil || len(tracks) != 2 || tracks[0].VideoID != "a" || tracks[1].VideoID != "c" {
+		t.Errorf("tracks = %+v, %v; want a, c in order", tracks, err)
+	}
+
+	playlists, err := d.ListPlaylists("g1", "u1")
+	if err != nil {
+		t.Fatalf("ListPlaylists: %v", err)
+	}
+	if len(playlists) != 2 || playlists[0].ID != mine.ID || playlists[0].TrackCount != 2 || !playlists[1].Shared() {
+		t.Errorf("ListPlaylists = %+v; want personal (2 tracks) then shared", playlists)
+	}
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Per-user preference keys in user_settings. They follow the user into every
+// server.
+const (
+	PrefNotifyUpNext   = "notify_up_next"
+	PrefLanguage       = "language"
+	PrefPrivateReplies = "private_replies"
+	PrefVolumeOffset   = "volume_offset"
+)
+
+// MaxVolumeOffset bounds PrefVolumeOffset either way, in volume points.
+const MaxVolumeOffset = 50
+
+// UserPreferences are a user's own settings, as set with /preferences.
+type UserPreferences struct {
+	NotifyUpNext   bool   // DM when a song they queued is next
+	Language       string // base language for replies to them; "" follows the server
+	PrivateReplies bool   // show AI confirmations and tips only to them
+	VolumeOffset   int    // added to the volume while songs they queued play
+}
+
+// GetUserPreferences reads userID's preferences, with defaults for anything
+// they haven't set.
+func (d *Database) GetUserPreferences(userID string) (UserPreferences, error) {
+	var prefs UserPreferences
+	values := make(map[string]string, 4)
+	for _, key := range []string{PrefNotifyUpNext, PrefLanguage, PrefPrivateReplies, PrefVolumeOffset} {
+		value, err := d.GetUserSetting(userID, key)
+		if err != nil {
+			return prefs, fmt.Errorf("failed to get preferences for %s: %w", userID, err)
+		}
+		values[key] = value
+	}
+
+	prefs.NotifyUpNext, _ = strconv.ParseBool(values[PrefNotifyUpNext])
+	prefs.Language = values[PrefLanguage]
+	prefs.PrivateReplies, _ = strconv.ParseBool(values[PrefPrivateReplies])
+	if offset, err := strconv.Atoi(values[PrefVolumeOffset]); err == nil {
+		prefs.VolumeOffset = max(-MaxVolumeOffset, min(MaxVolumeOffset, offset))
+	}
+	return prefs, nil
+}
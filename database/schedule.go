@@ -0,0 +1,126 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScheduledPlay is a song or playlist /playat starts at RunAt. PlaylistID is
+// non-zero for a playlist, in which case Title is the playlist's name and the
+// video fields are empty.
+type ScheduledPlay struct {
+	ID             int64
+	GuildID        string
+	VoiceChannelID string
+	TextChannelID  string
+	UserID         string
+	PlaylistID     int64
+	VideoID        string
+	Title          string
+	URL            string
+	Source         string
+	RunAt          time.Time
+}
+
+const scheduledPlayColumns = `id, guild_id, voice_channel_id, text_channel_id, user_id,
+	playlist_id, video_id, title, url, source, run_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanScheduledPlay(row rowScanner) (ScheduledPlay, error) {
+	var p ScheduledPlay
+	var runAt int64
+	err := row.Scan(&p.ID, &p.GuildID, &p.VoiceChannelID, &p.TextChannelID, &p.UserID,
+		&p.PlaylistID, &p.VideoID, &p.Title, &p.URL, &p.Source, &runAt)
+	p.RunAt = time.Unix(runAt, 0)
+	return p, err
+}
+
+// AddScheduledPlay stores play and returns its ID.
+func (d *Database) AddScheduledPlay(play ScheduledPlay) (int64, error) {
+	id, err := d.insert(
+		`INSERT INTO scheduled_plays (guild_id, voice_channel_id, text_channel_id, user_id,
+		                              playlist_id, video_id, title, url, source, run_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		play.GuildID, play.VoiceChannelID, play.TextChannelID, play.UserID,
+		play.PlaylistID, play.VideoID, play.Title, play.URL, play.Source, play.RunAt.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add scheduled play: %w", err)
+	}
+	return id, nil
+}
+
+// ListScheduledPlays returns a guild's scheduled plays, soonest first.
+func (d *Database) ListScheduledPlays(guildID string) ([]ScheduledPlay, error) {
+	rows, err := d.db.Query(
+		`SELECT `+scheduledPlayColumns+` FROM scheduled_plays WHERE guild_id = ? ORDER BY run_at ASC, id ASC`,
+		guildID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled plays: %w", err)
+	}
+	defer rows.Close()
+
+	var plays []ScheduledPlay
+	for rows.Next() {
+		p, err := scanScheduledPlay(rows)
+		if err != nil {
+			return nil, err
+		}
+		plays = append(plays, p)
+	}
+	return plays, rows.Err()
+}
+
+// CancelScheduledPlay deletes a guild's scheduled play. Returns false if
+// there was no such play in that guild.
+func (d *Database) CancelScheduledPlay(guildID string, id int64) (bool, error) {
+	result, err := d.db.Exec(`DELETE FROM scheduled_plays WHERE guild_id = ? AND id = ?`, guildID, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel scheduled play: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// TakeDueScheduledPlays removes and returns every play due at or before now,
+// across all guilds. The SELECT and DELETE share a transaction so a play is
+// handed out once.
+func (d *Database) TakeDueScheduledPlays(now time.Time) ([]ScheduledPlay, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck — superseded by explicit Commit below
+
+	rows, err := tx.Query(
+		`SELECT `+scheduledPlayColumns+` FROM scheduled_plays WHERE run_at <= ? ORDER BY run_at ASC, id ASC`,
+		now.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due scheduled plays: %w", err)
+	}
+	var plays []ScheduledPlay
+	for rows.Next() {
+		p, err := scanScheduledPlay(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		plays = append(plays, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, p := range plays {
+		if _, err := tx.Exec(`DELETE FROM scheduled_plays WHERE id = ?`, p.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete scheduled play: %w", err)
+		}
+	}
+	return plays, tx.Commit()
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduledPlays(t *testing.T) {
+	d := newTestDatabase(t)
+	now := time.Now().Truncate(time.Second)
+
+	later, err := d.AddScheduledPlay(ScheduledPlay{GuildID: "g1", VoiceChannelID: "v1", UserID: "u1",
+		VideoID: "b", Title: "Later", RunAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("AddScheduledPlay: %v", err)
+	}
+	soon, err := d.AddScheduledPlay(ScheduledPlay{GuildID: "g1", VoiceChannelID: "v1", UserID: "u1",
+		PlaylistID: 7, Title: "Movie Night", RunAt: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("AddScheduledPlay: %v", err)
+	}
+	if _, err := d.AddScheduledPlay(ScheduledPlay{GuildID: "g2", VoiceChannelID: "v2", UserID: "u2",
+		VideoID: "c", Title: "Elsewhere", RunAt: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("AddScheduledPlay: %v", err)
+	}
+
+	plays, err := d.ListScheduledPlays("g1")
+	if err != nil || len(plays) != 2 {
+		t.Fatalf("ListScheduledPlays = %+v, %v; want 2", plays, err)
+	}
+	if plays[0].ID != soon || plays[0].PlaylistID != 7 || !plays[0].RunAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("first play = %+v, want the playlist due in a minute", plays[0])
+	}
+
+	if ok, err := d.CancelScheduledPlay("g2", later); err != nil || ok {
+		t.Errorf("cancel from another guild = %v, %v; want false", ok, err)
+	}
+	if ok, err := d.CancelScheduledPlay("g1", later); err != nil || !ok {
+		t.Errorf("CancelScheduledPlay = %v, %v; want true", ok, err)
+	}
+
+	due, err := d.TakeDueScheduledPlays(now.Add(2 * time.Minute))
+	if err != nil || len(due) != 2 {
+		t.Fatalf("TakeDueScheduledPlays = %+v, %v; want both guilds' plays", due, err)
+	}
+	if due, _ := d.TakeDueScheduledPlays(now.Add(2 * time.Minute)); len(due) != 0 {
+		t.Errorf("second take = %+v, want nothing", due)
+	}
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"sync"
+	"time"
+)
+
+// settingsCacheTTL is how long cached settings are trusted. Writes through
+// this process update the cache straight away; the TTL only bounds how long
+// a change made by another instance sharing the database goes unseen.
+const settingsCacheTTL = 5 * time.Minute
+
+// settingsCache holds every setting of recently used guilds (or users), so
+// interactions that check settings don't each hit the database. A miss
+// loads all of the owner's settings at once.
+type settingsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]settingsEntry
+	// writes counts updates per owner, so a load that raced a write doesn't
+	// cache what it read from before the write.
+	writes map[string]uint64
+}
+
+type settingsEntry struct {
+	values   map[string]string
+	loadedAt time.Time
+}
+
+func newSettingsCache(ttl time.Duration) *settingsCache {
+	return &settingsCache{
+		ttl:     ttl,
+		entries: make(map[string]settingsEntry),
+		writes:  make(map[string]uint64),
+	}
+}
+
+// lookup returns owner's cached key, or ok=false when owner's settings need
+// loading. An unset key is cached as "".
+func (c *settingsCache) lookup(owner, key string) (value string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, found := c.entries[owner]
+	if !found || time.Since(entry.loadedAt) > c.ttl {
+		return "", false
+	}
+	return entry.values[key], true
+}
+
+// loading returns a token for store, taken before owner's settings are read.
+func (c *settingsCache) loading(owner string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writes[owner]
+}
+
+// store caches owner's settings as loaded, unless a write landed since the
+// load began.
+func (c *settingsCache) store(owner string, values map[string]string, token uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writes[owner] != token {
+		return
+	}
+	c.entries[owner] = settingsEntry{values: values, loadedAt: time.Now()}
+}
+
+// update records a write that reached the database.
+func (c *settingsCache) update(owner, key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes[owner]++
+	if entry, found := c.entries[owner]; found {
+		entry.values[key] = value
+	}
+}
+
+// forget drops owner's cached settings after they were all deleted.
+func (c *settingsCache) forget(owner string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes[owner]++
+	delete(c.entries, owner)
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuildSettingsAreCached(t *testing.T) {
+	d := newTestDatabase(t)
+
+	if err := d.SetGuildSetting("g1", "queue_mode", "fair"); err != nil {
+		t.Fatalf("SetGuildSetting: %v", err)
+	}
+	if v, _ := d.GetGuildSetting("g1", "queue_mode"); v != "fair" {
+		t.Fatalf("setting = %q, want fair", v)
+	}
+
+	// A change behind the cache's back, as from another instance, isn't seen
+	// until the cache expires.
+	if _, err := d.db.Exec(`UPDATE guild_settings SET value = 'normal' WHERE guild_id = 'g1'`); err != nil {
+		t.Fatalf("updating setting: %v", err)
+	}
+	if v, _ := d.GetGuildSetting("g1", "queue_mode"); v != "fair" {
+		t.Errorf("cached setting = %q, want fair", v)
+	}
+	if v, _ := d.GetGuildSetting("g1", "unset"); v != "" {
+		t.Errorf("unset setting = %q, want empty", v)
+	}
+
+	d.guildSettings.ttl = 0
+	if v, _ := d.GetGuildSetting("g1", "queue_mode"); v != "normal" {
+		t.Errorf("setting after expiry = %q, want normal", v)
+	}
+}
+
+func TestSettingsWriteThrough(t *testing.T) {
+	d := newTestDatabase(t)
+
+	if v, _ := d.GetUserSetting("u1", "notify_up_next"); v != "" {
+		t.Fatalf("unset setting = %q, want empty", v)
+	}
+	if err := d.SetUserSetting("u1", "notify_up_next", "true"); err != nil {
+		t.Fatalf("SetUserSetting: %v", err)
+	}
+	if v, _ := d.GetUserSetting("u1", "notify_up_next"); v != "true" {
+		t.Errorf("setting after write = %q, want true", v)
+	}
+}
+
+func TestSettingsCacheSkipsStaleLoads(t *testing.T) {
+	c := newSettingsCache(time.Minute)
+
+	token := c.loading("g1")
+	c.update("g1", "volume", "50")
+	c.store("g1", map[string]string{"volume": "100"}, token)
+	if _, ok := c.lookup("g1", "volume"); ok {
+		t.Error("a load that raced a write was cached")
+	}
+
+	c.store("g1", map[string]string{"volume": "50"}, c.loading("g1"))
+	if v, ok := c.lookup("g1", "volume"); !ok || v != "50" {
+		t.Errorf("lookup = %q, %v; want 50 cached", v, ok)
+	}
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrSoundNotFound = errors.New("sound not found")
+
+// Sound is a short clip uploaded to a guild's soundboard. Its audio is
+// 48kHz stereo 16-bit PCM kept in a file beside the database. The file stays
+// on local disk even with a shared Postgres or MySQL database, so other
+// instances list the sound but can't play it.
+type Sound struct {
+	ID         int64
+	GuildID    string
+	Name       string
+	Duration   time.Duration
+	UploadedBy string
+}
+
+// soundPath is where a sound's audio lives.
+func (d *Database) soundPath(guildID string, id int64) string {
+	return filepath.Join(d.dir, "soundboard", guildID, strconv.FormatInt(id, 10)+".pcm")
+}
+
+// SaveSound stores a sound under name, replacing the guild's sound of that
+// name (ignoring case) if there is one. Returns the saved sound and whether
+// it replaced another.
+func (d *Database) SaveSound(guildID, name, uploadedBy string, duration time.Duration, samples []int16) (*Sound, bool, error) {
+	existing, err := d.FindSound(guildID, name)
+	replaced := err == nil
+	if err != nil && !errors.Is(err, ErrSoundNotFound) {
+		return nil, false, err
+	}
+
+	var id int64
+	if replaced {
+		id = existing.ID
+		_, err = d.db.Exec(
+			`UPDATE soundboard_sounds SET name = ?, duration_ms = ?, uploaded_by = ?, uploaded_at = CURRENT_TIMESTAMP WHERE id = ?`,
+			name, duration.Milliseconds(), uploadedBy, id,
+		)
+	} else {
+		id, err = d.insert(
+			`INSERT INTO soundboard_sounds (guild_id, name, duration_ms, uploaded_by) VALUES (?, ?, ?, ?)`,
+			guildID, name, duration.Milliseconds(), uploadedBy,
+		)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to save sound: %w", err)
+	}
+
+	if err := d.writeSoundFile(d.soundPath(guildID, id), samples); err != nil {
+		if !replaced {
+			d.db.Exec(`DELETE FROM soundboard_sounds WHERE id = ?`, id)
+		}
+		return nil, false, err
+	}
+	return &Sound{ID: id, GuildID: guildID, Name: name, Duration: duration, UploadedBy: uploadedBy}, replaced, nil
+}
+
+// writeSoundFile writes samples through a temporary file, so a sound being
+// replaced never plays half-written.
+func (d *Database) writeSoundFile(path string, samples []int16) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create soundboard directory: %w", err)
+	}
+	data, err := binary.Append(nil, binary.LittleEndian, samples)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sound: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write sound: %w", err)
+	}
+	return nil
+}
+
+// FindSound looks a guild's sound up by name, ignoring case and surrounding
+// spaces.
+func (d *Database) FindSound(guildID, name string) (*Sound, error) {
+	s := &Sound{GuildID: guildID}
+	var durationMs int64
+	err := d.db.QueryRow(
+		`SELECT id, name, duration_ms, uploaded_by FROM soundboard_sounds WHERE guild_id = ? AND LOWER(name) = LOWER(?)`,
+		guildID, strings.TrimSpace(name),
+	).Scan(&s.ID, &s.Name, &durationMs, &s.UploadedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSoundNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sound: %w", err)
+	}
+	s.Duration = time.Duration(durationMs) * time.Millisecond
+	return s, nil
+}
+
+// ListSounds returns a guild's sounds by name.
+func (d *Database) ListSounds(guildID string) ([]Sound, error) {
+	rows, err := d.db.Query(
+		`SELECT id, name, duration_ms, uploaded_by FROM soundboard_sounds WHERE guild_id = ? ORDER BY LOWER(name)`,
+		guildID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sounds: %w", err)
+	}
+	defer rows.Close()
+
+	var sounds []Sound
+	for rows.Next() {
+		s := Sound{GuildID: guildID}
+		var durationMs int64
+		if err := rows.Scan(&s.ID, &s.Name, &durationMs, &s.UploadedBy); err != nil {
+			return nil, err
+		}
+		s.Duration = time.Duration(durationMs) * time.Millisecond
+		sounds = append(sounds, s)
+	}
+	return sounds, rows.Err()
+}
+
+// LoadSound reads a sound's audio back.
+func (d *Database) LoadSound(s *Sound) ([]int16, error) {
+	data, err := os.ReadFile(d.soundPath(s.GuildID, s.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sound: %w", err)
+	}
+	samples := make([]int16, len(data)/2)
+	if _, err := binary.Decode(data, binary.LittleEndian, samples); err != nil {
+		return nil, fmt.Errorf("failed to read sound: %w", err)
+	}
+	return samples, nil
+}
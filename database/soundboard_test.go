@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSoundboard(t *testing.T) {
+	d := newTestDatabase(t)
+
+	airhorn := []int16{1, -2, 3, -4}
+	saved, replaced, err := d.SaveSound("g1", "Airhorn", "u1", 2*time.Second, airhorn)
+	if err != nil || replaced {
+		t.Fatalf("SaveSound = %+v, %v, %v", saved, replaced, err)
+	}
+	if _, _, err := d.SaveSound("g1", "rimshot", "u2", time.Second, []int16{5, 6}); err != nil {
+		t.Fatalf("SaveSound: %v", err)
+	}
+	if _, _, err := d.SaveSound("g2", "airhorn", "u3", time.Second, []int16{7, 8}); err != nil {
+		t.Fatalf("SaveSound: %v", err)
+	}
+
+	found, err := d.FindSound("g1", " AIRHORN ")
+	if err != nil || found.ID != saved.ID || found.Duration != 2*time.Second || found.UploadedBy != "u1" {
+		t.Fatalf("FindSound = %+v, %v; want %+v", found, err, saved)
+	}
+	if samples, err := d.LoadSound(found); err != nil || !slices.Equal(samples, airhorn) {
+		t.Errorf("LoadSound = %v, %v; want %v", samples, err, airhorn)
+	}
+	if _, err := d.FindSound("g1", "sad trombone"); !errors.Is(err, ErrSoundNotFound) {
+		t.Errorf("missing sound err = %v, want ErrSoundNotFound", err)
+	}
+
+	// Uploading under the same name replaces the sound.
+	louder := []int16{9, 9, 9, 9, 9, 9}
+	again, replaced, err := d.SaveSound("g1", "airhorn", "u2", 3*time.Second, louder)
+	if err != nil || !replaced || again.ID != saved.ID {
+		t.Fatalf("replacing SaveSound = %+v, %v, %v", again, replaced, err)
+	}
+	if samples, err := d.LoadSound(again); err != nil || !slices.Equal(samples, louder) {
+		t.Errorf("LoadSound after replace = %v, %v; want %v", samples, err, louder)
+	}
+
+	sounds, err := d.ListSounds("g1")
+	if err != nil || len(sounds) != 2 {
+		t.Fatalf("ListSounds = %+v, %v; want 2", sounds, err)
+	}
+	if sounds[0].Name != "airhorn" || sounds[0].UploadedBy != "u2" || sounds[1].Name != "rimshot" {
+		t.Errorf("ListSounds = %+v; want airhorn (by u2) then rimshot", sounds)
+	}
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// GuildStats summarizes a guild's playback history for /stats.
+type GuildStats struct {
+	SongsPlayed   int
+	UniqueSongs   int
+	Playtime      time.Duration // time actually listened, for plays that have finished
+	Skips         int
+	TopRequesters []RequesterStats
+	MostSkipped   []MostSkippedRecord
+}
+
+// UserStats sums up the songs one user has requested across every server.
+type UserStats struct {
+	SongsPlayed int
+	UniqueSongs int
+	Playtime    time.Duration
+	Skips       int
+	Servers     int
+}
+
+type RequesterStats struct {
+	UserID   string
+	Username string
+	Plays    int
+	Playtime time.Duration
+}
+
+// MostSkippedRecord is a song a guild skips, and how soon.
+type MostSkippedRecord struct {
+	VideoID string
+	Title   string
+	Skips   int
+	Plays   int
+	// SkippedAt is how far into the song it's skipped on average, in
+	// percent; -1 when its length wasn't recorded.
+	SkippedAt int
+}
+
+// A guild is taken to always skip a song once it has skipped it at least
+// usuallySkippedMin times, on at least usuallySkippedPercent of its plays.
+const (
+	usuallySkippedMin     = 2
+	usuallySkippedPercent = 75
+)
+
+// GetGuildStats aggregates song_history for a guild. limit caps the
+// requester and most-skipped lists.
+func (d *Database) GetGuildStats(guildID string, limit int) (*GuildStats, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+
+	var stats GuildStats
+	var playtimeSeconds int64
+	err := d.db.QueryRow(
+		`SELECT COUNT(*), COUNT(DISTINCT video_id), COALESCE(SUM(duration_seconds), 0), COALESCE(SUM(skipped), 0)
+		 FROM song_history
+		 WHERE guild_id = ?`,
+		guildID,
+	).Scan(&stats.SongsPlayed, &stats.UniqueSongs, &playtimeSeconds, &stats.Skips)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guild stats: %w", err)
+	}
+	stats.Playtime = time.Duration(playtimeSeconds) * time.Second
+
+	rows, err := d.db.Query(
+		`SELECT requested_by_user_id, MAX(requested_by_username), COUNT(*) AS plays, COALESCE(SUM(duration_seconds), 0)
+		 FROM song_history
+		 WHERE guild_id = ? AND requested_by_user_id != ''
+		 GROUP BY requested_by_user_id
+		 ORDER BY plays DESC
+		 LIMIT ?`,
+		guildID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top requesters: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r RequesterStats
+		var seconds int64
+		if err := rows.Scan(&r.UserID, &r.Username, &r.Plays, &seconds); err != nil {
+			return nil, fmt.Errorf("failed to scan requester row: %w", err)
+		}
+		r.Playtime = time.Duration(seconds) * time.Second
+		stats.TopRequesters = append(stats.TopRequesters, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if stats.MostSkipped, err = d.GetMostSkipped(guildID, limit); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetMostSkipped returns the songs a guild has skipped most, with how many
+// times each was played and how far in it's usually skipped.
+func (d *Database) GetMostSkipped(guildID string, limit int) ([]MostSkippedRecord, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	rows, err := d.db.Query(
+		`SELECT video_id, MAX(title), SUM(skipped) AS skips, COUNT(*),
+		        AVG(CASE WHEN skipped = 1 AND track_seconds > 0 THEN 100.0 * duration_seconds / track_seconds END)
+		 FROM song_history
+		 WHERE guild_id = ?
+		 GROUP BY video_id
+		 HAVING SUM(skipped) > 0
+		 ORDER BY skips DESC, MAX(played_at) DESC
+		 LIMIT ?`,
+		guildID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query most skipped: %w", err)
+	}
+	defer rows.Close()
+
+	var records []MostSkippedRecord
+	for rows.Next() {
+		var r MostSkippedRecord
+		var skippedAt sql.NullFloat64
+		if err := rows.Scan(&r.VideoID, &r.Title, &r.Skips, &r.Plays, &skippedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan most skipped row: %w", err)
+		}
+		r.SkippedAt = -1
+		if skippedAt.Valid {
+			r.SkippedAt = min(100, int(math.Round(skippedAt.Float64)))
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// GetUsuallySkippedVideoIDs returns the songs a guild nearly always skips,
+// for radio mode to leave out.
+func (d *Database) GetUsuallySkippedVideoIDs(guildID string) (map[string]bool, error) {
+	rows, err := d.db.Query(
+		`SELECT video_id
+		 FROM song_history
+		 WHERE guild_id = ?
+		 GROUP BY video_id
+		 HAVING SUM(skipped) >= ? AND SUM(skipped) * 100 >= COUNT(*) * ?`,
+		guildID, usuallySkippedMin, usuallySkippedPercent,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query skipped videos: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var videoID string
+		if err := rows.Scan(&videoID); err != nil {
+			return nil, fmt.Errorf("failed to scan skipped video row: %w", err)
+		}
+		ids[videoID] = true
+	}
+	return ids, rows.Err()
+}
+
+// GetUserStats totals the plays userID requested, in any server.
+func (d *Database) GetUserStats(userID string) (*UserStats, error) {
+	var stats UserStats
+	var playtimeSeconds int64
+	err := d.db.QueryRow(
+		`SELECT COUNT(*), COUNT(DISTINCT video_id), COALESCE(SUM(duration_seconds), 0), COALESCE(SUM(skipped), 0), COUNT(DISTINCT guild_id)
+		 FROM song_history
+		 WHERE requested_by_user_id = ?`,
+		userID,
+	).Scan(&stats.SongsPlayed, &stats.UniqueSongs, &playtimeSeconds, &stats.Skips, &stats.Servers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user stats: %w", err)
+	}
+	stats.Playtime = time.Duration(playtimeSeconds) * time.Second
+	return &stats, nil
+}
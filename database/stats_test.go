@@ -0,0 +1,170 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	d, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestGetGuildStats(t *testing.T) {
+	d := newTestDatabase(t)
+
+	plays := []struct {
+		videoID, userID string
+		listened        int
+		skipped         bool
+	}{
+		{"a", "u1", 200, false},
+		{"b", "u1", 30, true},
+		{"b", "u2", 10, true},
+		{"c", "u2", 180, false},
+		{"a", "u1", 200, false},
+	}
+	for _, p := range plays {
+		id, err := d.RecordPlay("g1", p.videoID, "Song "+p.videoID, "", p.userID, "name-"+p.userID, 0)
+		if err != nil {
+			t.Fatalf("RecordPlay: %v", err)
+		}
+		if err := d.FinishPlay(id, p.listened, p.skipped); err != nil {
+			t.Fatalf("FinishPlay: %v", err)
+		}
+	}
+	// Another guild's plays must not leak in.
+	if _, err := d.RecordPlay("g2", "z", "Other", "", "u9", "other", 0); err != nil {
+		t.Fatalf("RecordPlay: %v", err)
+	}
+
+	stats, err := d.GetGuildStats("g1", 5)
+	if err != nil {
+		t.Fatalf("GetGuildStats: %v", err)
+	}
+	if stats.SongsPlayed != 5 || stats.UniqueSongs != 3 || stats.Skips != 2 {
+		t.Errorf("got played=%d unique=%d skips=%d, want 5/3/2", stats.SongsPlayed, stats.UniqueSongs, stats.Skips)
+	}
+	if stats.Playtime != 620*time.Second {
+		t.Errorf("playtime = %v, want 620s", stats.Playtime)
+	}
+	if len(stats.TopRequesters) != 2 || stats.TopRequesters[0].UserID != "u1" || stats.TopRequesters[0].Plays != 3 {
+		t.Errorf("top requesters = %+v", stats.TopRequesters)
+	}
+	if len(stats.MostSkipped) != 1 || stats.MostSkipped[0].Title != "Song b" || stats.MostSkipped[0].Skips != 2 {
+		t.Errorf("most skipped = %+v", stats.MostSkipped)
+	}
+}
+
+func TestGetUserStats(t *testing.T) {
+	d := newTestDatabase(t)
+
+	plays := []struct {
+		guildID, videoID, userID string
+		listened                 int
+		skipped                  bool
+	}{
+		{"g1", "a", "u1", 200, false},
+		{"g1", "b", "u1", 30, true},
+		{"g2", "a", "u1", 100, false},
+		{"g1", "c", "u2", 180, false},
+	}
+	for _, p := range plays {
+		id, err := d.RecordPlay(p.guildID, p.videoID, "Song "+p.videoID, "", p.userID, "name-"+p.userID, 0)
+		if err != nil {
+			t.Fatalf("RecordPlay: %v", err)
+		}
+		if err := d.FinishPlay(id, p.listened, p.skipped); err != nil {
+			t.Fatalf("FinishPlay: %v", err)
+		}
+	}
+
+	stats, err := d.GetUserStats("u1")
+	if err != nil {
+		t.Fatalf("GetUserStats: %v", err)
+	}
+	if stats.SongsPlayed != 3 || stats.UniqueSongs != 2 || stats.Skips != 1 || stats.Servers != 2 {
+		t.Errorf("got played=%d unique=%d skips=%d servers=%d, want 3/2/1/2", stats.SongsPlayed, stats.UniqueSongs, stats.Skips, stats.Servers)
+	}
+	if stats.Playtime != 330*time.Second {
+		t.Errorf("playtime = %v, want 330s", stats.Playtime)
+	}
+}
+
+func TestAddColumnIsIdempotent(t *testing.T) {
+	d := newTestDatabase(t)
+	// New already ran migrate once; a second run must not fail on the
+	// existing column.
+	if err := d.migrate(); err != nil {
+		t.Fatalf("second migrate: %v", err)
+	}
+}
+
+func TestSkipAnalytics(t *testing.T) {
+	d := newTestDatabase(t)
+
+	plays := []struct {
+		videoID  string
+		length   int
+		listened int
+		skipped  bool
+	}{
+		// Skipped every time, early on.
+		{"a", 200, 20, true},
+		{"a", 200, 60, true},
+		{"a", 200, 40, true},
+		// Skipped once, but usually played through.
+		{"b", 180, 90, true},
+		{"b", 180, 180, false},
+		{"b", 180, 180, false},
+		// Length unknown, skipped both times.
+		{"c", 0, 30, true},
+		{"c", 0, 10, true},
+		{"d", 150, 150, false},
+	}
+	for _, p := range plays {
+		id, err := d.RecordPlay("g1", p.videoID, "Song "+p.videoID, "", "u1", "name-u1", p.length)
+		if err != nil {
+			t.Fatalf("RecordPlay: %v", err)
+		}
+		if err := d.FinishPlay(id, p.listened, p.skipped); err != nil {
+			t.Fatalf("FinishPlay: %v", err)
+		}
+	}
+
+	records, err := d.GetMostSkipped("g1", 10)
+	if err != nil {
+		t.Fatalf("GetMostSkipped: %v", err)
+	}
+	want := []MostSkippedRecord{
+		{VideoID: "a", Title: "Song a", Skips: 3, Plays: 3, SkippedAt: 20},
+		{VideoID: "c", Title: "Song c", Skips: 2, Plays: 2, SkippedAt: -1},
+		{VideoID: "b", Title: "Song b", Skips: 1, Plays: 3, SkippedAt: 50},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("GetMostSkipped = %+v, want %+v", records, want)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("record %d = %+v, want %+v", i, records[i], want[i])
+		}
+	}
+
+	ids, err := d.GetUsuallySkippedVideoIDs("g1")
+	if err != nil {
+		t.Fatalf("GetUsuallySkippedVideoIDs: %v", err)
+	}
+	if len(ids) != 2 || !ids["a"] || !ids["c"] {
+		t.Errorf("usually skipped = %v, want a and c", ids)
+	}
+	if ids, _ := d.GetUsuallySkippedVideoIDs("g2"); len(ids) != 0 {
+		t.Errorf("another guild's usually skipped = %v", ids)
+	}
+}
@@ -0,0 +1,116 @@
+package deezer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	sentry "github.com/getsentry/sentry-go"
+)
+
+// AlbumResult is an album's name, artist and tracks
+type AlbumResult struct {
+	Name        string
+	Artist      string
+	Tracks      []Track
+	TotalTracks int
+}
+
+// PlaylistResult is a playlist's name and first tracks
+type PlaylistResult struct {
+	Name        string
+	Tracks      []Track
+	TotalTracks int
+}
+
+type albumResponse struct {
+	Title    string `json:"title"`
+	NbTracks int    `json:"nb_tracks"`
+	Artist   Artist `json:"artist"`
+	Tracks   struct {
+		Data []Track `json:"data"`
+	} `json:"tracks"`
+}
+
+// GetAlbum fetches an album and its tracks.
+func GetAlbum(ctx context.Context, albumID int) (*AlbumResult, error) {
+	span := sentry.StartSpan(ctx, "deezer.get_album")
+	span.Description = "Get album from Deezer API"
+	span.SetTag("album_id", strconv.Itoa(albumID))
+	span.SetTag("area", "deezer")
+	defer span.Finish()
+
+	body, err := get(ctx, fmt.Sprintf("/album/%d", albumID), nil)
+	if err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		return nil, fmt.Errorf("deezer: get album failed: %w", err)
+	}
+
+	var album albumResponse
+	if err := json.Unmarshal(body, &album); err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		sentry.CaptureException(fmt.Errorf("deezer: decode album %d: %w", albumID, err))
+		return nil, fmt.Errorf("deezer: failed to decode album response: %w", err)
+	}
+	if len(album.Tracks.Data) == 0 {
+		span.Status = sentry.SpanStatusNotFound
+		return nil, errors.New("album has no playable tracks")
+	}
+
+	span.Status = sentry.SpanStatusOK
+	span.SetData("tracks_count", len(album.Tracks.Data))
+	return &AlbumResult{
+		Name:        album.Title,
+		Artist:      album.Artist.Name,
+		Tracks:      album.Tracks.Data,
+		TotalTracks: max(album.NbTracks, len(album.Tracks.Data)),
+	}, nil
+}
+
+type playlistResponse struct {
+	Title    string `json:"title"`
+	NbTracks int    `json:"nb_tracks"`
+	Tracks   struct {
+		Data []Track `json:"data"`
+	} `json:"tracks"`
+}
+
+// GetPlaylist fetches a public playlist and its first limit tracks.
+func GetPlaylist(ctx context.Context, playlistID, limit int) (*PlaylistResult, error) {
+	span := sentry.StartSpan(ctx, "deezer.get_playlist")
+	span.Description = "Get playlist from Deezer API"
+	span.SetTag("playlist_id", strconv.Itoa(playlistID))
+	span.SetTag("area", "deezer")
+	defer span.Finish()
+
+	body, err := get(ctx, fmt.Sprintf("/playlist/%d", playlistID), nil)
+	if err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		return nil, fmt.Errorf("deezer: get playlist failed: %w", err)
+	}
+
+	var playlist playlistResponse
+	if err := json.Unmarshal(body, &playlist); err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		sentry.CaptureException(fmt.Errorf("deezer: decode playlist %d: %w", playlistID, err))
+		return nil, fmt.Errorf("deezer: failed to decode playlist response: %w", err)
+	}
+	if len(playlist.Tracks.Data) == 0 {
+		span.Status = sentry.SpanStatusNotFound
+		return nil, errors.New("playlist has no playable tracks")
+	}
+
+	tracks := playlist.Tracks.Data
+	if limit > 0 && len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	span.Status = sentry.SpanStatusOK
+	span.SetData("tracks_count", len(tracks))
+	return &PlaylistResult{
+		Name:        playlist.Title,
+		Tracks:      tracks,
+		TotalTracks: max(playlist.NbTracks, len(playlist.Tracks.Data)),
+	}, nil
+}
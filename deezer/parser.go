@@ -0,0 +1,94 @@
+package deezer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DeezerRequest represents a parsed Deezer link. Exactly one ID is set.
+type DeezerRequest struct {
+	TrackID    int
+	AlbumID    int
+	PlaylistID int
+}
+
+// IsDeezerURL reports whether rawURL is a Deezer link, including the
+// shortened deezer.page.link and link.deezer.com share links.
+func IsDeezerURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	return host == "deezer.com" || host == "deezer.page.link" || host == "link.deezer.com"
+}
+
+// isShortLink reports whether u is a share link that redirects to the real
+// deezer.com URL.
+func isShortLink(u *url.URL) bool {
+	host := strings.ToLower(u.Host)
+	return host == "deezer.page.link" || host == "link.deezer.com"
+}
+
+// ParseDeezerURL extracts the track, album or playlist ID from a deezer.com
+// link. Links may carry a language prefix (/en/track/123) and tracking query
+// parameters. Share links must go through ResolveShortLink first.
+func ParseDeezerURL(rawURL string) (DeezerRequest, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return DeezerRequest{}, err
+	}
+	if !IsDeezerURL(rawURL) || isShortLink(u) {
+		return DeezerRequest{}, errors.New("not a deezer.com link")
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		id, err := strconv.Atoi(parts[i+1])
+		if err != nil || id <= 0 {
+			continue
+		}
+		switch parts[i] {
+		case "track":
+			return DeezerRequest{TrackID: id}, nil
+		case "album":
+			return DeezerRequest{AlbumID: id}, nil
+		case "playlist":
+			return DeezerRequest{PlaylistID: id}, nil
+		}
+	}
+	log.Warnf("Could not parse Deezer URL (no track, album or playlist ID): %s", rawURL)
+	return DeezerRequest{}, errors.New("use a Deezer track, album or playlist link")
+}
+
+// ResolveShortLink follows a Deezer share link to the deezer.com URL it
+// points at. Other links are returned unchanged.
+func ResolveShortLink(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || !isShortLink(u) {
+		return rawURL, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("deezer: following share link: %w", err)
+	}
+	resp.Body.Close()
+
+	final := resp.Request.URL.String()
+	if !IsDeezerURL(final) || isShortLink(resp.Request.URL) {
+		return "", fmt.Errorf("deezer: share link led to %s", resp.Request.URL.Host)
+	}
+	return final, nil
+}
@@ -0,0 +1,111 @@
+package deezer
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseDeezerURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    DeezerRequest
+		wantErr bool
+	}{
+		{
+			name: "track with language prefix",
+			url:  "https://www.deezer.com/en/track/3135556",
+			want: DeezerRequest{TrackID: 3135556},
+		},
+		{
+			name: "track without prefix or www",
+			url:  "https://deezer.com/track/3135556?utm_source=share",
+			want: DeezerRequest{TrackID: 3135556},
+		},
+		{
+			name: "album",
+			url:  "https://www.deezer.com/fr/album/302127",
+			want: DeezerRequest{AlbumID: 302127},
+		},
+		{
+			name: "playlist with trailing slash",
+			url:  "https://www.deezer.com/us/playlist/908622995/",
+			want: DeezerRequest{PlaylistID: 908622995},
+		},
+		{
+			name:    "artist is unsupported",
+			url:     "https://www.deezer.com/en/artist/27",
+			wantErr: true,
+		},
+		{
+			name:    "share link needs resolving first",
+			url:     "https://deezer.page.link/abc123",
+			wantErr: true,
+		},
+		{
+			name:    "not deezer",
+			url:     "https://open.spotify.com/track/42",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDeezerURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDeezerURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDeezerURL(%q) = %+v, want %+v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDeezerURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://www.deezer.com/en/track/1": true,
+		"https://deezer.page.link/abc":      true,
+		"https://link.deezer.com/s/abc":     true,
+		"https://notdeezer.com/track/1":     false,
+		"deezer.com/track/1":                false,
+		"daft punk":                         false,
+	}
+	for url, want := range tests {
+		if got := IsDeezerURL(url); got != want {
+			t.Errorf("IsDeezerURL(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
+
+func TestGetPlaylistLimit(t *testing.T) {
+	playlistJSON := `{
+		"title": "Road Trip",
+		"nb_tracks": 40,
+		"tracks": {"data": [
+			{"id": 1, "title": "One", "duration": 200, "artist": {"name": "A"}},
+			{"id": 2, "title": "Two", "duration": 210, "artist": {"name": "B"}},
+			{"id": 3, "title": "Three", "duration": 220, "artist": {"name": "C"}}
+		]}
+	}`
+
+	origClient := httpClient
+	t.Cleanup(func() { httpClient = origClient })
+	transport := &captureTransport{body: []byte(playlistJSON)}
+	httpClient = &http.Client{Transport: transport, Timeout: 10 * time.Second}
+
+	result, err := GetPlaylist(context.Background(), 908622995, 2)
+	if err != nil {
+		t.Fatalf("GetPlaylist() unexpected error: %v", err)
+	}
+	if got := transport.URL().Path; got != "/playlist/908622995" {
+		t.Errorf("request path = %q", got)
+	}
+	if result.Name != "Road Trip" || result.TotalTracks != 40 || len(result.Tracks) != 2 {
+		t.Errorf("GetPlaylist() = %+v", result)
+	}
+	if result.Tracks[1].Artist.Name != "B" {
+		t.Errorf("second track = %+v", result.Tracks[1])
+	}
+}
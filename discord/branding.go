@@ -0,0 +1,129 @@
+package discord
+
+import (
+	"encoding/json"
+	"regexp"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Branding is a guild's look for now-playing cards and their buttons, so the
+// bot can match a server's colors and emoji. Empty fields keep the defaults.
+type Branding struct {
+	Color     int               `json:"color,omitempty"`      // embed color while playing; paused cards stay gray
+	BarFilled string            `json:"bar_filled,omitempty"` // progress bar character for time played
+	BarEmpty  string            `json:"bar_empty,omitempty"`  // progress bar character for time left
+	Emojis    map[string]string `json:"emojis,omitempty"`     // BrandingButtons key -> emoji
+}
+
+// Default branding.
+const (
+	DefaultColor     = 0x1DB954 // Spotify green
+	PausedColor      = 0x808080
+	DefaultBarFilled = "▓"
+	DefaultBarEmpty  = "░"
+)
+
+// BrandingButtons maps each button emoji a guild can override to its
+// default. The play/pause button has one for each state.
+var BrandingButtons = map[string]string{
+	"pause":   "⏸️",
+	"play":    "▶️",
+	"skip":    "⏭️",
+	"shuffle": "🔀",
+	"queue":   "📜",
+	"voldown": "🔉",
+	"volup":   "🔊",
+	"grab":    "💾",
+}
+
+var (
+	brandingMu sync.RWMutex
+	brandings  = make(map[string]Branding)
+)
+
+// SetBranding sets guildID's branding; the zero Branding goes back to the
+// defaults.
+func SetBranding(guildID string, b Branding) {
+	brandingMu.Lock()
+	defer brandingMu.Unlock()
+	if b.IsZero() {
+		delete(brandings, guildID)
+		return
+	}
+	brandings[guildID] = b
+}
+
+// BrandingFor returns guildID's branding.
+func BrandingFor(guildID string) Branding {
+	brandingMu.RLock()
+	defer brandingMu.RUnlock()
+	return brandings[guildID]
+}
+
+// DecodeBranding reads branding saved by Encode.
+func DecodeBranding(s string) (Branding, error) {
+	var b Branding
+	err := json.Unmarshal([]byte(s), &b)
+	return b, err
+}
+
+// Encode serializes b for storing as a guild setting.
+func (b Branding) Encode() string {
+	data, _ := json.Marshal(b)
+	return string(data)
+}
+
+// IsZero reports whether b changes nothing.
+func (b Branding) IsZero() bool {
+	return b.Color == 0 && b.BarFilled == "" && b.BarEmpty == "" && len(b.Emojis) == 0
+}
+
+// embedColor is the card color for the playback state.
+func (b Branding) embedColor(playing bool) int {
+	if !playing {
+		return PausedColor
+	}
+	if b.Color != 0 {
+		return b.Color
+	}
+	return DefaultColor
+}
+
+// bar returns the progress bar's filled and empty characters.
+func (b Branding) bar() (filled, empty string) {
+	filled, empty = DefaultBarFilled, DefaultBarEmpty
+	if b.BarFilled != "" {
+		filled = b.BarFilled
+	}
+	if b.BarEmpty != "" {
+		empty = b.BarEmpty
+	}
+	return filled, empty
+}
+
+// buttonEmoji returns the emoji for a BrandingButtons key.
+func (b Branding) buttonEmoji(key string) *discordgo.ComponentEmoji {
+	if emoji, ok := b.Emojis[key]; ok {
+		return ParseComponentEmoji(emoji)
+	}
+	return &discordgo.ComponentEmoji{Name: BrandingButtons[key]}
+}
+
+var customEmojiPattern = regexp.MustCompile(`^<(a?):(\w{2,32}):(\d+)>$`)
+
+// ParseComponentEmoji turns a unicode emoji or a server emoji as Discord
+// writes it in messages (<:name:id>, <a:name:id> when animated) into a
+// button emoji.
+func ParseComponentEmoji(s string) *discordgo.ComponentEmoji {
+	if m := customEmojiPattern.FindStringSubmatch(s); m != nil {
+		return &discordgo.ComponentEmoji{Name: m[2], ID: m[3], Animated: m[1] == "a"}
+	}
+	return &discordgo.ComponentEmoji{Name: s}
+}
+
+// IsCustomEmoji reports whether s is a server emoji in message form.
+func IsCustomEmoji(s string) bool {
+	return customEmojiPattern.MatchString(s)
+}
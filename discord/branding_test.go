@@ -0,0 +1,105 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseComponentEmoji(t *testing.T) {
+	tests := []struct {
+		in   string
+		want discordgo.ComponentEmoji
+	}{
+		{"🎵", discordgo.ComponentEmoji{Name: "🎵"}},
+		{"<:beat:123456789>", discordgo.ComponentEmoji{Name: "beat", ID: "123456789"}},
+		{"<a:spin:42>", discordgo.ComponentEmoji{Name: "spin", ID: "42", Animated: true}},
+	}
+	for _, tt := range tests {
+		if got := ParseComponentEmoji(tt.in); *got != tt.want {
+			t.Errorf("ParseComponentEmoji(%q) = %+v, want %+v", tt.in, *got, tt.want)
+		}
+	}
+}
+
+func TestBrandingProgressBar(t *testing.T) {
+	b := Branding{BarFilled: "🟩", BarEmpty: "⬛"}
+	if got, want := b.progressBar(30*time.Second, 60*time.Second, 4), "🟩🟩⬛⬛ 0:30 / 1:00"; got != want {
+		t.Errorf("progressBar() = %q, want %q", got, want)
+	}
+	if got, want := b.progressBar(0, 0, 3), "⬛⬛⬛ 0:00 / 0:00"; got != want {
+		t.Errorf("progressBar() with no duration = %q, want %q", got, want)
+	}
+}
+
+func TestBrandingEmbedColor(t *testing.T) {
+	if got := (Branding{}).embedColor(true); got != DefaultColor {
+		t.Errorf("default playing color = %#x", got)
+	}
+	custom := Branding{Color: 0xFF5500}
+	if got := custom.embedColor(true); got != 0xFF5500 {
+		t.Errorf("custom playing color = %#x", got)
+	}
+	if got := custom.embedColor(false); got != PausedColor {
+		t.Errorf("custom paused color = %#x, want gray", got)
+	}
+}
+
+func TestBrandedNowPlaying(t *testing.T) {
+	const guildID = "branded-guild"
+	SetBranding(guildID, Branding{
+		Color:     0x123456,
+		BarFilled: "=",
+		BarEmpty:  "-",
+		Emojis:    map[string]string{"skip": "<:next:99>", "pause": "🛑"},
+	})
+	defer SetBranding(guildID, Branding{})
+
+	embed := BuildNowPlayingEmbed(&NowPlayingMetadata{
+		VideoID:   "abc",
+		Title:     "Song",
+		Duration:  60 * time.Second,
+		IsPlaying: true,
+		GuildID:   guildID,
+	})
+	if embed.Color != 0x123456 {
+		t.Errorf("embed color = %#x", embed.Color)
+	}
+	UpdateNowPlayingProgress(embed, guildID, 30*time.Second, 60*time.Second)
+	if want := "=======-------- 0:30 / 1:00"; embed.Footer.Text != want {
+		t.Errorf("footer = %q, want %q", embed.Footer.Text, want)
+	}
+
+	emojis := make(map[string]*discordgo.ComponentEmoji)
+	for _, row := range BuildPlaybackButtons(guildID, true) {
+		for _, component := range row.(discordgo.ActionsRow).Components {
+			button := component.(discordgo.Button)
+			action, _, _ := ParseButtonCustomID(button.CustomID)
+			emojis[action] = button.Emoji
+		}
+	}
+	if e := emojis["skip"]; e.Name != "next" || e.ID != "99" {
+		t.Errorf("skip emoji = %+v", *e)
+	}
+	if e := emojis["playpause"]; e.Name != "🛑" {
+		t.Errorf("pause emoji = %+v", *e)
+	}
+	if e := emojis["grab"]; e.Name != "💾" {
+		t.Errorf("grab emoji = %+v, want the default", *e)
+	}
+}
+
+func TestBrandingEncodeRoundTrip(t *testing.T) {
+	b := Branding{Color: 0xABCDEF, BarFilled: "█", Emojis: map[string]string{"grab": "📌"}}
+	got, err := DecodeBranding(b.Encode())
+	if err != nil {
+		t.Fatalf("DecodeBranding: %v", err)
+	}
+	if got.Color != b.Color || got.BarFilled != b.BarFilled || got.BarEmpty != "" || got.Emojis["grab"] != "📌" {
+		t.Errorf("round trip = %+v, want %+v", got, b)
+	}
+	if _, err := DecodeBranding("not json"); err == nil {
+		t.Error("DecodeBranding accepted garbage")
+	}
+}
@@ -2,6 +2,8 @@ package discord
 
 import (
 	"strings"
+
+	"github.com/bwmarrin/discordgo"
 )
 
 // ParseButtonCustomID extracts action and guildID from button custom ID
@@ -13,3 +15,106 @@ func ParseButtonCustomID(customID string) (action, guildID string, ok bool) {
 	}
 	return parts[1], parts[2], true
 }
+
+// ButtonCustomID builds a custom ID in the format ParseButtonCustomID reads.
+func ButtonCustomID(action, guildID string) string {
+	return "np:" + action + ":" + guildID
+}
+
+// ConfirmButtons returns a single row with a danger-styled confirm button and
+// a cancel button, routed to the given actions.
+func ConfirmButtons(guildID, confirmAction, confirmLabel, cancelAction string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    confirmLabel,
+				Style:    discordgo.DangerButton,
+				CustomID: ButtonCustomID(confirmAction, guildID),
+			},
+			discordgo.Button{
+				Label:    "Cancel",
+				Style:    discordgo.SecondaryButton,
+				CustomID: ButtonCustomID(cancelAction, guildID),
+			},
+		}},
+	}
+}
+
+// DisabledButton returns a row with one inert button, used to replace
+// confirm/cancel buttons once they've been answered.
+func DisabledButton(label string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    label,
+				Style:    discordgo.SecondaryButton,
+				CustomID: "np:done:-",
+				Disabled: true,
+			},
+		}},
+	}
+}
+
+// MoveHereButton returns a row with one button routed to the "steal"
+// action, which moves the bot to the clicker's voice channel.
+func MoveHereButton(guildID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Move here",
+				Style:    discordgo.SecondaryButton,
+				CustomID: ButtonCustomID("steal", guildID),
+				Emoji:    &discordgo.ComponentEmoji{Name: "🎧"},
+			},
+		}},
+	}
+}
+
+// SelectMenu returns a row with a single-choice string select menu routed to
+// action. Discord allows at most 25 options.
+func SelectMenu(guildID, action, placeholder string, options []discordgo.SelectMenuOption) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				MenuType:    discordgo.StringSelectMenu,
+				CustomID:    ButtonCustomID(action, guildID),
+				Placeholder: placeholder,
+				MaxValues:   1,
+				Options:     options,
+			},
+		}},
+	}
+}
+
+// BuildPlaybackButtons returns the control rows for a now-playing card,
+// with guildID's branding emoji. The play/pause button shows the action a
+// click performs.
+func BuildPlaybackButtons(guildID string, isPlaying bool) []discordgo.MessageComponent {
+	branding := BrandingFor(guildID)
+	playPause := "pause"
+	if !isPlaying {
+		playPause = "play"
+	}
+	// Buttons other than play/pause share their action's emoji key.
+	button := func(action, emojiKey, label string, style discordgo.ButtonStyle) discordgo.Button {
+		return discordgo.Button{
+			Label:    label,
+			Style:    style,
+			CustomID: ButtonCustomID(action, guildID),
+			Emoji:    branding.buttonEmoji(emojiKey),
+		}
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			button("playpause", playPause, "", discordgo.PrimaryButton),
+			button("skip", "skip", "", discordgo.SecondaryButton),
+			button("shuffle", "shuffle", "", discordgo.SecondaryButton),
+			button("queue", "queue", "Queue", discordgo.SecondaryButton),
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			button("voldown", "voldown", "Vol -", discordgo.SecondaryButton),
+			button("volup", "volup", "Vol +", discordgo.SecondaryButton),
+			button("grab", "grab", "Grab", discordgo.SecondaryButton),
+		}},
+	}
+}
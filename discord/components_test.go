@@ -2,6 +2,8 @@ package discord
 
 import (
 	"testing"
+
+	"github.com/bwmarrin/discordgo"
 )
 
 func TestParseButtonCustomID(t *testing.T) {
@@ -106,3 +108,42 @@ func TestParseButtonCustomID(t *testing.T) {
 		})
 	}
 }
+
+func TestButtonCustomIDRoundTrip(t *testing.T) {
+	for _, action := range []string{"playpause", "skip", "stop", "volup", "voldown", "queue", "shuffle", "grab"} {
+		gotAction, gotGuildID, ok := ParseButtonCustomID(ButtonCustomID(action, "123"))
+		if !ok || gotAction != action || gotGuildID != "123" {
+			t.Errorf("round trip of %q = (%q, %q, %v)", action, gotAction, gotGuildID, ok)
+		}
+	}
+}
+
+func TestBuildPlaybackButtons(t *testing.T) {
+	for _, isPlaying := range []bool{true, false} {
+		var actions []string
+		playPause := ""
+		for _, row := range BuildPlaybackButtons("123", isPlaying) {
+			for _, component := range row.(discordgo.ActionsRow).Components {
+				button := component.(discordgo.Button)
+				action, guildID, ok := ParseButtonCustomID(button.CustomID)
+				if !ok || guildID != "123" {
+					t.Errorf("button %q has custom ID %q", button.Label, button.CustomID)
+				}
+				actions = append(actions, action)
+				if action == "playpause" {
+					playPause = button.Emoji.Name
+				}
+			}
+		}
+		if len(actions) != 7 {
+			t.Errorf("isPlaying=%v: got actions %v, want 7", isPlaying, actions)
+		}
+		want := "⏸️"
+		if !isPlaying {
+			want = "▶️"
+		}
+		if playPause != want {
+			t.Errorf("isPlaying=%v: play/pause emoji = %q, want %q", isPlaying, playPause, want)
+		}
+	}
+}
@@ -111,6 +111,30 @@ func (a *daveSessionAdapter) RemoveUser(userID string) {
 	a.session.RemoveUser(godave.UserID(userID))
 }
 
+// decrypt removes the end-to-end encryption from a frame userID sent.
+func (a *daveSessionAdapter) decrypt(userID string, frame []byte) ([]byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	out := make([]byte, a.session.MaxDecryptedFrameSize(godave.UserID(userID), len(frame)))
+	n, err := a.session.Decrypt(godave.UserID(userID), frame, out)
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
+
+// DecryptVoiceFrame returns the Opus in a frame userID sent on vc. discordgo
+// only strips the transport encryption from received audio; with DAVE on,
+// each frame is still end-to-end encrypted. Frames on connections without
+// DAVE come back as they are.
+func DecryptVoiceFrame(vc *discordgo.VoiceConnection, userID string, frame []byte) ([]byte, error) {
+	adapter, ok := vc.DaveSession.(*daveSessionAdapter)
+	if !ok {
+		return frame, nil
+	}
+	return adapter.decrypt(userID, frame)
+}
+
 // daveCallbacksAdapter wraps discordgo.DaveCallbacks to implement godave.Callbacks.
 type daveCallbacksAdapter struct {
 	callbacks discordgo.DaveCallbacks
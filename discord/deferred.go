@@ -0,0 +1,148 @@
+package discord
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// interactionTokenTTL is how long Discord accepts an interaction token.
+const interactionTokenTTL = 15 * time.Minute
+
+// responseDeadline is how long Discord gives an interaction's initial
+// response. A followup sent while the response is still being decided waits
+// at most this long for it.
+const responseDeadline = 3 * time.Second
+
+// ephemeralFlag is the message flag that shows a message only to the user
+// who ran the command.
+const ephemeralFlag = 64
+
+// deferral is an interaction whose initial response is being decided or was
+// a deferred "thinking…" message that hasn't been replaced yet. deferred and
+// ephemeral are only read once ready is closed.
+type deferral struct {
+	at        time.Time
+	ready     chan struct{}
+	deferred  bool
+	ephemeral bool
+}
+
+// pendingOriginals maps interaction tokens to their *deferral.
+var pendingOriginals sync.Map
+
+// ExpectResponse records that an interaction is being handled, before its
+// handler runs. Followups the handler's goroutines send before
+// MarkDeferred or MarkAnswered wait for the response to be decided, so they
+// don't miss the "thinking…" message they should replace.
+func ExpectResponse(token string) {
+	now := time.Now()
+	pendingOriginals.Range(func(key, value any) bool {
+		if now.Sub(value.(*deferral).at) > interactionTokenTTL {
+			pendingOriginals.Delete(key)
+		}
+		return true
+	})
+	pendingOriginals.Store(token, &deferral{at: now, ready: make(chan struct{})})
+}
+
+// MarkDeferred records that an interaction was answered with a deferred
+// response (type 5), ephemeral or not, so its first followup replaces the
+// "thinking…" message instead of posting a second message under it.
+func MarkDeferred(token string, ephemeral bool) {
+	d := settle(token)
+	d.deferred = true
+	d.ephemeral = ephemeral
+	close(d.ready)
+}
+
+// MarkAnswered records that an interaction was answered with anything but a
+// deferred response, so its followups are posted as new messages.
+func MarkAnswered(token string) {
+	close(settle(token).ready)
+	pendingOriginals.Delete(token)
+}
+
+// settle returns the interaction's undecided deferral, registering one if
+// ExpectResponse wasn't called.
+func settle(token string) *deferral {
+	if value, ok := pendingOriginals.Load(token); ok {
+		if d := value.(*deferral); !isClosed(d.ready) {
+			return d
+		}
+	}
+	d := &deferral{at: time.Now(), ready: make(chan struct{})}
+	pendingOriginals.Store(token, d)
+	return d
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// takeOriginal waits for the interaction's initial response to be decided
+// and reports whether it was a deferral still waiting to be replaced, and
+// whether that deferral was ephemeral. Only the first caller gets it.
+func takeOriginal(token string) (pending, ephemeral bool) {
+	value, ok := pendingOriginals.Load(token)
+	if !ok {
+		return false, false
+	}
+	d := value.(*deferral)
+	select {
+	case <-d.ready:
+	case <-time.After(responseDeadline):
+		log.Warnf("Interaction response wasn't decided within %v, sending a followup", responseDeadline)
+		return false, false
+	}
+	if !d.deferred || !pendingOriginals.CompareAndDelete(token, value) {
+		return false, false
+	}
+	return time.Since(d.at) < interactionTokenTTL, d.ephemeral
+}
+
+// dropOriginal forgets the interaction's deferral without waiting, for
+// callers that replace the original message themselves.
+func dropOriginal(token string) {
+	pendingOriginals.Delete(token)
+}
+
+// sendFollowup posts a followup for the interaction, or, while its deferred
+// original is still thinking, edits that instead. An edit keeps the
+// deferral's visibility, so when it differs from the followup's the
+// "thinking…" message is deleted and the followup posted on its own. If the
+// edit fails the followup is posted as a new message.
+func sendFollowup(appID, token string, ephemeral bool, req *restRequest) error {
+	req.path = webhookPath(appID, token)
+	if pending, deferredEphemeral := takeOriginal(token); pending {
+		original := req.path + "/messages/@original"
+		if deferredEphemeral == ephemeral {
+			edit := *req
+			edit.method = "PATCH"
+			edit.path = original
+			_, err := rest.do(context.Background(), &edit)
+			if err == nil {
+				return nil
+			}
+			log.Warnf("Couldn't edit deferred response, sending a followup instead: %v", err)
+		} else if _, err := rest.do(context.Background(), &restRequest{method: "DELETE", path: original}); err != nil {
+			log.Warnf("Couldn't delete deferred response: %v", err)
+		}
+	}
+	req.method = "POST"
+	_, err := rest.do(context.Background(), req)
+	return err
+}
+
+// payloadEphemeral reports whether a followup payload is flagged ephemeral.
+func payloadEphemeral(payload map[string]interface{}) bool {
+	flags, _ := payload["flags"].(int)
+	return flags&ephemeralFlag != 0
+}
@@ -0,0 +1,116 @@
+package discord
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFollowupEditsDeferredOriginal(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	})
+	orig := rest
+	rest = client
+	t.Cleanup(func() { rest = orig })
+
+	MarkDeferred("tok", false)
+	for range 2 {
+		if err := PostFollowup("app", "tok", map[string]interface{}{"content": "hi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"PATCH /webhooks/app/tok/messages/@original",
+		"POST /webhooks/app/tok",
+	}
+	if len(requests) != len(want) || requests[0] != want[0] || requests[1] != want[1] {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
+
+func TestFollowupFallsBackWhenEditFails(t *testing.T) {
+	var posted bool
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Unknown Message", "code": 10008}`))
+			return
+		}
+		posted = true
+		w.Write([]byte(`{}`))
+	})
+	orig := rest
+	rest = client
+	t.Cleanup(func() { rest = orig })
+
+	MarkDeferred("tok2", false)
+	if err := PostFollowup("app", "tok2", map[string]interface{}{"content": "hi"}); err != nil || !posted {
+		t.Errorf("err = %v, posted = %v; want the followup posted", err, posted)
+	}
+}
+
+func TestEphemeralFollowupReplacesPublicDeferral(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	})
+	orig := rest
+	rest = client
+	t.Cleanup(func() { rest = orig })
+
+	MarkDeferred("tok3", false)
+	if err := PostFollowup("app", "tok3", map[string]interface{}{"content": "psst", "flags": 64}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"DELETE /webhooks/app/tok3/messages/@original",
+		"POST /webhooks/app/tok3",
+	}
+	if len(requests) != len(want) || requests[0] != want[0] || requests[1] != want[1] {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
+
+func TestFollowupWaitsForResponse(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	})
+	orig := rest
+	rest = client
+	t.Cleanup(func() { rest = orig })
+
+	// A handler's goroutine answers before the handler has returned its
+	// deferral.
+	ExpectResponse("tok4")
+	sent := make(chan error)
+	go func() {
+		sent <- PostFollowup("app", "tok4", map[string]interface{}{"content": "hi"})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	MarkDeferred("tok4", false)
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 || requests[0] != "PATCH /webhooks/app/tok4/messages/@original" {
+		t.Errorf("requests = %q, want the deferral edited", requests)
+	}
+}
@@ -14,6 +14,7 @@ const ProgressBarWidth = 15
 // NowPlayingMetadata contains all info for a now-playing card
 type NowPlayingMetadata struct {
 	VideoID         string
+	URL             string // track page link; "" means the YouTube watch URL for VideoID
 	Title           string
 	Artist          string
 	Album           string
@@ -27,6 +28,9 @@ type NowPlayingMetadata struct {
 	BPM             float64
 	AlbumYear       string
 	Popularity      int
+	TranslatedTitle string // Gemini translation shown under non-Latin titles
+	Live            bool   // endless stream: show time listened instead of progress
+	ListenAlongURL  string // song.link page for /listen-along; "" leaves it off the card
 }
 
 // BuildNowPlayingEmbed creates a rich embed for now-playing
@@ -37,23 +41,39 @@ func BuildNowPlayingEmbed(metadata *NowPlayingMetadata) *discordgo.MessageEmbed
 		artist = ExtractArtistFromTitle(metadata.Title)
 	}
 
-	// Build thumbnail URL from video ID if not provided
+	// Build link and thumbnail from the YouTube video ID unless the track
+	// came from another source (URL set), which may not have a thumbnail.
+	pageURL := metadata.URL
 	thumbnailURL := metadata.ThumbnailURL
-	if thumbnailURL == "" {
-		thumbnailURL = fmt.Sprintf("https://i.ytimg.com/vi/%s/hqdefault.jpg", metadata.VideoID)
+	if pageURL == "" {
+		pageURL = fmt.Sprintf("https://www.youtube.com/watch?v=%s", metadata.VideoID)
+		if thumbnailURL == "" {
+			thumbnailURL = fmt.Sprintf("https://i.ytimg.com/vi/%s/hqdefault.jpg", metadata.VideoID)
+		}
+	}
+	var thumbnail *discordgo.MessageEmbedThumbnail
+	if thumbnailURL != "" {
+		thumbnail = &discordgo.MessageEmbedThumbnail{URL: thumbnailURL}
 	}
 
+	branding := BrandingFor(metadata.GuildID)
+
 	// Create progress bar
-	progressBar := RenderProgressBar(metadata.CurrentPosition, metadata.Duration, ProgressBarWidth)
+	progressBar := branding.progressBar(metadata.CurrentPosition, metadata.Duration, ProgressBarWidth)
+	durationText := FormatDuration(metadata.Duration)
+	if metadata.Live {
+		progressBar = RenderLiveProgress(metadata.CurrentPosition)
+		durationText = "🔴 Live"
+	}
 
 	// Determine embed color based on playback state
-	color := 0x1DB954 // Spotify green for playing
-	if !metadata.IsPlaying {
-		color = 0x808080 // Gray for paused
-	}
+	color := branding.embedColor(metadata.IsPlaying)
 
 	// Build description
 	var desc strings.Builder
+	if metadata.TranslatedTitle != "" {
+		desc.WriteString(fmt.Sprintf("*(%s)*\n", metadata.TranslatedTitle))
+	}
 	if artist != metadata.Title {
 		desc.WriteString(fmt.Sprintf("**Artist:** %s\n", artist))
 	}
@@ -67,19 +87,17 @@ func BuildNowPlayingEmbed(metadata *NowPlayingMetadata) *discordgo.MessageEmbed
 
 	embed := &discordgo.MessageEmbed{
 		Title:       metadata.Title,
-		URL:         fmt.Sprintf("https://www.youtube.com/watch?v=%s", metadata.VideoID),
+		URL:         pageURL,
 		Description: desc.String(),
 		Color:       color,
-		Thumbnail: &discordgo.MessageEmbedThumbnail{
-			URL: thumbnailURL,
-		},
+		Thumbnail:   thumbnail,
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: progressBar,
 		},
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   "Duration",
-				Value:  FormatDuration(metadata.Duration),
+				Value:  durationText,
 				Inline: true,
 			},
 			{
@@ -114,6 +132,17 @@ func BuildNowPlayingEmbed(metadata *NowPlayingMetadata) *discordgo.MessageEmbed
 		})
 	}
 
+	if metadata.ListenAlongURL != "" {
+		value := fmt.Sprintf("[Open the song](%s)", metadata.ListenAlongURL)
+		if !metadata.Live {
+			value = ListenAlongText(metadata.ListenAlongURL, metadata.CurrentPosition, metadata.IsPlaying, time.Now())
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "🎧 Listen along",
+			Value: value,
+		})
+	}
+
 	if metadata.Popularity > 800000 {
 		embed.Footer.Text += " • Popular"
 	}
@@ -121,22 +150,107 @@ func BuildNowPlayingEmbed(metadata *NowPlayingMetadata) *discordgo.MessageEmbed
 	return embed
 }
 
-// UpdateNowPlayingProgress updates just the progress bar (efficient)
-func UpdateNowPlayingProgress(embed *discordgo.MessageEmbed, currentPosition, duration time.Duration) *discordgo.MessageEmbed {
+// ListenAlongText links a song for members following along on their own
+// player, with where it's up to. While it plays, the time it started is
+// given too (as a Discord timestamp) so the offset can be worked out later.
+func ListenAlongText(link string, position time.Duration, playing bool, now time.Time) string {
+	if !playing {
+		return fmt.Sprintf("[Open the song](%s) · paused at **%s**", link, FormatDuration(position))
+	}
+	started := now.Add(-position).Unix()
+	return fmt.Sprintf("[Open the song](%s) and skip to **%s** · started <t:%d:T>", link, FormatDuration(position), started)
+}
+
+// DashboardUpNext is how many queued songs the music channel dashboard lists.
+const DashboardUpNext = 5
+
+// BuildDashboardEmbed turns a now-playing embed into the music channel
+// dashboard, or an idle card when nowPlaying is nil. The dashboard is only
+// edited on changes, so the progress bar, which would sit stale between
+// edits, gives way to the next songs in the queue: upNext holds the first
+// few titles and queued the full queue length.
+func BuildDashboardEmbed(nowPlaying *discordgo.MessageEmbed, upNext []string, queued int) *discordgo.MessageEmbed {
+	embed := nowPlaying
+	if embed == nil {
+		embed = &discordgo.MessageEmbed{
+			Title:       "Nothing playing",
+			Description: "Queue something with `/play` and it'll show up here.",
+			Color:       0x808080,
+			Timestamp:   time.Now().Format(time.RFC3339),
+		}
+	}
+
+	if len(upNext) > 0 {
+		var list strings.Builder
+		for i, title := range upNext {
+			list.WriteString(fmt.Sprintf("%d. %s\n", i+1, title))
+		}
+		if more := queued - len(upNext); more > 0 {
+			list.WriteString(fmt.Sprintf("…and %d more", more))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Up Next",
+			Value: strings.TrimSuffix(list.String(), "\n"),
+		})
+	}
+
+	footer := "Queue is empty"
+	if queued == 1 {
+		footer = "1 song queued"
+	} else if queued > 1 {
+		footer = fmt.Sprintf("%d songs queued", queued)
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: footer}
+	return embed
+}
+
+// UpdateNowPlayingProgress updates just the progress bar (efficient), drawn
+// in guildID's branding
+func UpdateNowPlayingProgress(embed *discordgo.MessageEmbed, guildID string, currentPosition, duration time.Duration) *discordgo.MessageEmbed {
 	if embed == nil || embed.Footer == nil {
 		return embed
 	}
 
 	// Update progress bar in footer
-	embed.Footer.Text = RenderProgressBar(currentPosition, duration, ProgressBarWidth)
+	embed.Footer.Text = BrandingFor(guildID).progressBar(currentPosition, duration, ProgressBarWidth)
+
+	return embed
+}
 
+// UpdateNowPlayingLive updates the footer of a live stream's card, which
+// has no progress bar to move.
+func UpdateNowPlayingLive(embed *discordgo.MessageEmbed, listened time.Duration) *discordgo.MessageEmbed {
+	if embed == nil || embed.Footer == nil {
+		return embed
+	}
+	embed.Footer.Text = RenderLiveProgress(listened)
+	return embed
+}
+
+// UpdateNowPlayingVolume rewrites the Volume field of a now-playing embed in
+// place, leaving the rest of the card alone.
+func UpdateNowPlayingVolume(embed *discordgo.MessageEmbed, volume int) *discordgo.MessageEmbed {
+	if embed == nil {
+		return embed
+	}
+	for _, field := range embed.Fields {
+		if field.Name == "Volume" {
+			field.Value = fmt.Sprintf("%d%%", volume)
+		}
+	}
 	return embed
 }
 
 // RenderProgressBar creates a Unicode progress bar
 func RenderProgressBar(current, total time.Duration, width int) string {
+	return Branding{}.progressBar(current, total, width)
+}
+
+// progressBar draws a progress bar with b's characters.
+func (b Branding) progressBar(current, total time.Duration, width int) string {
+	filledChar, emptyChar := b.bar()
 	if total == 0 {
-		return "░░░░░░░░░░░░░░░ 0:00 / 0:00"
+		return strings.Repeat(emptyChar, width) + " 0:00 / 0:00"
 	}
 
 	percentage := float64(current) / float64(total)
@@ -149,13 +263,22 @@ func RenderProgressBar(current, total time.Duration, width int) string {
 		filled = width
 	}
 
-	bar := strings.Repeat("▓", filled) + strings.Repeat("░", width-filled)
+	bar := strings.Repeat(filledChar, filled) + strings.Repeat(emptyChar, width-filled)
 	currentStr := FormatDuration(current)
 	totalStr := FormatDuration(total)
 
 	return fmt.Sprintf("%s %s / %s", bar, currentStr, totalStr)
 }
 
+// RenderLiveProgress is the progress line for a live stream: how long it's
+// been playing, since there's no end to measure against.
+func RenderLiveProgress(listened time.Duration) string {
+	if listened <= 0 {
+		return "🔴 LIVE"
+	}
+	return "🔴 LIVE • " + FormatDuration(listened)
+}
+
 // FormatDuration formats duration as MM:SS or HH:MM:SS
 func FormatDuration(d time.Duration) string {
 	d = d.Round(time.Second)
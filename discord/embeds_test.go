@@ -270,6 +270,84 @@ func TestBuildNowPlayingEmbedPaused(t *testing.T) {
 	}
 }
 
+func TestBuildNowPlayingEmbedTranslatedTitle(t *testing.T) {
+	metadata := &NowPlayingMetadata{
+		VideoID:         "test123",
+		Title:           "真夜中のドア",
+		TranslatedTitle: "Mayonaka no Door / Stay With Me",
+		Duration:        180 * time.Second,
+		IsPlaying:       true,
+		Volume:          100,
+	}
+
+	embed := BuildNowPlayingEmbed(metadata)
+
+	// Original title stays as the embed title; translation goes in the description
+	if embed.Title != metadata.Title {
+		t.Errorf("Expected title %q, got %q", metadata.Title, embed.Title)
+	}
+	if !strings.HasPrefix(embed.Description, "*(Mayonaka no Door / Stay With Me)*") {
+		t.Errorf("Expected description to start with translation, got %q", embed.Description)
+	}
+
+	metadata.TranslatedTitle = ""
+	embed = BuildNowPlayingEmbed(metadata)
+	if strings.Contains(embed.Description, "*(") {
+		t.Errorf("Expected no translation line, got %q", embed.Description)
+	}
+}
+
+func TestBuildNowPlayingEmbedLive(t *testing.T) {
+	embed := BuildNowPlayingEmbed(&NowPlayingMetadata{
+		VideoID:         "live123",
+		Title:           "lofi hip hop radio",
+		CurrentPosition: 75 * time.Second,
+		IsPlaying:       true,
+		Volume:          100,
+		Live:            true,
+	})
+
+	if embed.Footer.Text != "🔴 LIVE • 1:15" {
+		t.Errorf("Expected live footer, got %q", embed.Footer.Text)
+	}
+	if embed.Fields[0].Value != "🔴 Live" {
+		t.Errorf("Expected live duration field, got %q", embed.Fields[0].Value)
+	}
+
+	embed = UpdateNowPlayingLive(embed, 2*time.Hour)
+	if embed.Footer.Text != "🔴 LIVE • 2:00:00" {
+		t.Errorf("Expected updated live footer, got %q", embed.Footer.Text)
+	}
+	if got := RenderLiveProgress(0); got != "🔴 LIVE" {
+		t.Errorf("RenderLiveProgress(0) = %q", got)
+	}
+}
+
+func TestListenAlongText(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	got := ListenAlongText("https://song.link/y/abc", 83*time.Second, true, now)
+	want := "[Open the song](https://song.link/y/abc) and skip to **1:23** · started <t:1699999917:T>"
+	if got != want {
+		t.Errorf("playing = %q, want %q", got, want)
+	}
+	got = ListenAlongText("https://song.link/y/abc", 83*time.Second, false, now)
+	if want := "[Open the song](https://song.link/y/abc) · paused at **1:23**"; got != want {
+		t.Errorf("paused = %q, want %q", got, want)
+	}
+
+	embed := BuildNowPlayingEmbed(&NowPlayingMetadata{
+		VideoID:        "abc",
+		Title:          "Song",
+		Duration:       3 * time.Minute,
+		IsPlaying:      true,
+		ListenAlongURL: "https://song.link/y/abc",
+	})
+	last := embed.Fields[len(embed.Fields)-1]
+	if last.Name != "🎧 Listen along" || !strings.Contains(last.Value, "https://song.link/y/abc") {
+		t.Errorf("last field = %+v, want the listen-along link", last)
+	}
+}
+
 func TestUpdateNowPlayingProgress(t *testing.T) {
 	// Create initial embed
 	metadata := &NowPlayingMetadata{
@@ -286,7 +364,7 @@ func TestUpdateNowPlayingProgress(t *testing.T) {
 	initialFooter := embed.Footer.Text
 
 	// Update progress
-	updatedEmbed := UpdateNowPlayingProgress(embed, 60*time.Second, 120*time.Second)
+	updatedEmbed := UpdateNowPlayingProgress(embed, "123", 60*time.Second, 120*time.Second)
 
 	// Check footer changed
 	if updatedEmbed.Footer.Text == initialFooter {
@@ -298,3 +376,45 @@ func TestUpdateNowPlayingProgress(t *testing.T) {
 		t.Error("Expected footer to contain updated time 1:00")
 	}
 }
+
+func TestUpdateNowPlayingVolume(t *testing.T) {
+	embed := BuildNowPlayingEmbed(&NowPlayingMetadata{Title: "Song", Volume: 100, IsPlaying: true})
+	UpdateNowPlayingVolume(embed, 70)
+	for _, field := range embed.Fields {
+		if field.Name == "Volume" && field.Value != "70%" {
+			t.Errorf("Volume field = %q, want 70%%", field.Value)
+		}
+		if field.Name == "Duration" && field.Value == "70%" {
+			t.Error("other fields should be left alone")
+		}
+	}
+	if UpdateNowPlayingVolume(nil, 70) != nil {
+		t.Error("nil embed should stay nil")
+	}
+}
+
+func TestBuildDashboardEmbed(t *testing.T) {
+	nowPlaying := BuildNowPlayingEmbed(&NowPlayingMetadata{VideoID: "abc", Title: "Song", Duration: time.Minute, IsPlaying: true})
+	embed := BuildDashboardEmbed(nowPlaying, []string{"One", "Two"}, 7)
+
+	if strings.Contains(embed.Footer.Text, "░") {
+		t.Errorf("footer still has a progress bar: %q", embed.Footer.Text)
+	}
+	if embed.Footer.Text != "7 songs queued" {
+		t.Errorf("footer = %q", embed.Footer.Text)
+	}
+	last := embed.Fields[len(embed.Fields)-1]
+	if last.Name != "Up Next" || last.Value != "1. One\n2. Two\n…and 5 more" {
+		t.Errorf("up next field = %q: %q", last.Name, last.Value)
+	}
+}
+
+func TestBuildDashboardEmbedIdle(t *testing.T) {
+	embed := BuildDashboardEmbed(nil, nil, 0)
+	if embed.Title != "Nothing playing" || embed.Footer.Text != "Queue is empty" {
+		t.Errorf("idle dashboard = %q / %q", embed.Title, embed.Footer.Text)
+	}
+	if len(embed.Fields) != 0 {
+		t.Errorf("idle dashboard has %d fields", len(embed.Fields))
+	}
+}
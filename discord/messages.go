@@ -6,8 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"mime/multipart"
 
 	"beatbot/config"
 	"beatbot/gemini"
@@ -39,14 +38,9 @@ func IsMissingPermissions(err error) bool {
 	return errors.As(err, &permErr)
 }
 
-// classifyError wraps the error as ErrMissingPermissions if the response body contains code 50013
-func classifyError(baseErr error, responseBody []byte) error {
-	var discordErr DiscordErrorResponse
-	if json.Unmarshal(responseBody, &discordErr) == nil && discordErr.Code == 50013 {
-		return &ErrMissingPermissions{OriginalError: baseErr}
-	}
-	return baseErr
-}
+// ErrDMsClosed is returned when Discord refuses a direct message (code
+// 50007), usually because the user doesn't accept DMs from server members.
+var ErrDMsClosed = errors.New("user does not accept direct messages")
 
 type FollowUpRequest struct {
 	Token           string
@@ -55,6 +49,7 @@ type FollowUpRequest struct {
 	Content         string
 	GenerateContent bool
 	Flags           int
+	Components      []discordgo.MessageComponent
 }
 
 func buildRequest(request *FollowUpRequest) map[string]interface{} {
@@ -76,68 +71,90 @@ func buildRequest(request *FollowUpRequest) map[string]interface{} {
 		payload["flags"] = request.Flags
 	}
 
+	if len(request.Components) > 0 {
+		payload["components"] = request.Components
+	}
+
 	return payload
 }
 
-func SendFollowup(request *FollowUpRequest) {
-	payload := buildRequest(request)
+// webhookPath is the followup endpoint for an interaction token.
+func webhookPath(appID, token string) string {
+	return "/webhooks/" + appID + "/" + token
+}
 
-	jsonPayload, err := json.Marshal(payload)
+// PostFollowup sends a followup message for an interaction with an
+// arbitrary payload, for callers that need more than FollowUpRequest
+// carries (embeds, say). The first followup to a deferred interaction
+// replaces its "thinking…" message.
+func PostFollowup(appID, token string, payload map[string]interface{}) error {
+	req, err := jsonRequest("POST", "", payload, false)
 	if err != nil {
-		sentry.CaptureException(err)
-		log.Errorf("Error marshalling payload: %v", err)
-		return
+		return err
 	}
+	return sendFollowup(appID, token, payloadEphemeral(payload), req)
+}
 
-	resp, err := http.Post(
-		"https://discord.com/api/v10/webhooks/"+request.AppID+"/"+request.Token,
-		"application/json",
-		bytes.NewBuffer(jsonPayload),
-	)
-	if err != nil {
+func SendFollowup(request *FollowUpRequest) {
+	if err := PostFollowup(request.AppID, request.Token, buildRequest(request)); err != nil {
 		sentry.CaptureException(err)
 		log.Errorf("Error sending followup: %v", err)
-		return
 	}
-	defer resp.Body.Close()
 }
 
-func UpdateMessage(request *FollowUpRequest) {
+// SendFollowupWithFile sends a followup message with a single file attached.
+func SendFollowupWithFile(request *FollowUpRequest, filename string, data []byte) error {
 	payload := buildRequest(request)
+	payload["attachments"] = []map[string]interface{}{{"id": 0, "filename": filename}}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
 		sentry.CaptureException(err)
-		log.Errorf("Error marshalling payload: %v", err)
-		return
+		return err
 	}
 
-	req, err := http.NewRequest(
-		"PATCH",
-		"https://discord.com/api/v10/webhooks/"+request.AppID+"/"+request.Token+"/messages/@original",
-		bytes.NewBuffer(jsonPayload),
-	)
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("payload_json", string(jsonPayload)); err != nil {
+		return err
+	}
+	part, err := writer.CreateFormFile("files[0]", filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
 
+	err = sendFollowup(request.AppID, request.Token, payloadEphemeral(payload), &restRequest{
+		body:        body.Bytes(),
+		contentType: writer.FormDataContentType(),
+	})
 	if err != nil {
 		sentry.CaptureException(err)
-		log.Errorf("Error updating message: %v", err)
-		return
+		log.Errorf("Error sending followup with file: %v", err)
 	}
+	return err
+}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
+// UpdateMessage edits the interaction's original response.
+func UpdateMessage(request *FollowUpRequest) {
+	dropOriginal(request.Token)
+	req, err := jsonRequest("PATCH", webhookPath(request.AppID, request.Token)+"/messages/@original", buildRequest(request), false)
+	if err == nil {
+		_, err = rest.do(context.Background(), req)
+	}
 	if err != nil {
 		sentry.CaptureException(err)
 		log.Errorf("Error updating message: %v", err)
-		return
 	}
-	defer resp.Body.Close()
 }
 
-// SendChannelMessage sends a new message to a channel using bot token
-// Returns the created message for storing the message ID
-func SendChannelMessage(channelID, content string, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) (*discordgo.Message, error) {
+// messagePayload builds a channel message body, leaving out empty parts.
+func messagePayload(content string, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) map[string]interface{} {
 	payload := map[string]interface{}{}
 
 	if content != "" {
@@ -152,94 +169,71 @@ func SendChannelMessage(channelID, content string, embed *discordgo.MessageEmbed
 		payload["components"] = components
 	}
 
-	jsonPayload, err := json.Marshal(payload)
+	return payload
+}
+
+// SendChannelMessage sends a new message to a channel using bot token
+// Returns the created message for storing the message ID
+func SendChannelMessage(channelID, content string, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) (*discordgo.Message, error) {
+	req, err := jsonRequest("POST", "/channels/"+channelID+"/messages", messagePayload(content, embed, components), true)
 	if err != nil {
 		sentry.CaptureException(err)
 		return nil, err
 	}
 
-	url := fmt.Sprintf("https://discord.com/api/v10/channels/%s/messages", channelID)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	body, err := rest.do(context.Background(), req)
 	if err != nil {
+		log.Errorf("Failed to send message: %v", err)
+		return nil, err
+	}
+
+	var message discordgo.Message
+	if err := json.Unmarshal(body, &message); err != nil {
 		sentry.CaptureException(err)
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bot %s", config.Config.Discord.BotToken))
+	return &message, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// SendDirectMessage opens (or reuses) the bot's DM channel with userID and
+// sends the message there. Returns an error wrapping ErrDMsClosed when the
+// user doesn't accept DMs.
+func SendDirectMessage(userID, content string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	req, err := jsonRequest("POST", "/users/@me/channels", map[string]string{"recipient_id": userID}, true)
 	if err != nil {
-		sentry.CaptureException(err)
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		baseErr := fmt.Errorf("failed to send message: %s - %s", resp.Status, string(body))
-		log.Error(baseErr)
-		return nil, classifyError(baseErr, body)
+	body, err := rest.do(context.Background(), req)
+	if err != nil {
+		log.Errorf("Failed to open DM channel: %v", err)
+		return nil, err
 	}
 
-	var message discordgo.Message
-	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+	var channel discordgo.Channel
+	if err := json.Unmarshal(body, &channel); err != nil {
 		sentry.CaptureException(err)
 		return nil, err
 	}
 
-	return &message, nil
+	return SendChannelMessage(channel.ID, content, embed, nil)
 }
 
 // EditChannelMessage updates an existing message in a channel using bot token
 // Used for updating now-playing cards without the 15-minute webhook token limit
 func EditChannelMessage(channelID, messageID string, content string, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error {
-	payload := map[string]interface{}{}
-
-	if content != "" {
-		payload["content"] = content
-	}
-
-	if embed != nil {
-		payload["embeds"] = []*discordgo.MessageEmbed{embed}
-	}
-
-	if components != nil {
-		payload["components"] = components
-	}
-
-	jsonPayload, err := json.Marshal(payload)
+	req, err := jsonRequest("PATCH", "/channels/"+channelID+"/messages/"+messageID, messagePayload(content, embed, components), true)
 	if err != nil {
 		sentry.CaptureException(err)
 		log.Errorf("Error marshalling payload: %v", err)
 		return err
 	}
 
-	url := fmt.Sprintf("https://discord.com/api/v10/channels/%s/messages/%s", channelID, messageID)
-
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		sentry.CaptureException(err)
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bot %s", config.Config.Discord.BotToken))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		sentry.CaptureException(err)
+	if _, err := rest.do(context.Background(), req); err != nil {
+		log.Errorf("Failed to edit message: %v", err)
 		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		baseErr := fmt.Errorf("failed to edit message: %s - %s", resp.Status, string(body))
-		log.Error(baseErr)
-		return classifyError(baseErr, body)
-	}
 
 	return nil
 }
@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"errors"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// Voice access problems found before joining. The messages are shown to
+// users as-is.
+var (
+	ErrNoConnectPermission = errors.New("I'm not allowed to connect to that voice channel — a server admin needs to give me the Connect permission there")
+	ErrNoSpeakPermission   = errors.New("I can join that voice channel but I'm not allowed to speak in it — a server admin needs to give me the Speak permission there")
+	ErrVoiceChannelFull    = errors.New("that voice channel is full — make room, raise its user limit, or give me the Move Members permission")
+)
+
+// IsVoiceAccessError reports whether err is one of the pre-flight problems
+// rather than a failure to connect.
+func IsVoiceAccessError(err error) bool {
+	return errors.Is(err, ErrNoConnectPermission) || errors.Is(err, ErrNoSpeakPermission) || errors.Is(err, ErrVoiceChannelFull)
+}
+
+// CheckVoiceAccess makes sure the bot may join and be heard in channelID,
+// so a missing permission or a full channel gets a specific error instead of
+// a connection that never becomes ready. A failed lookup lets the join go
+// ahead.
+func CheckVoiceAccess(session *discordgo.Session, guildID, channelID string) error {
+	if session.State == nil || session.State.User == nil {
+		return nil
+	}
+	botID := session.State.User.ID
+
+	perms, err := session.UserChannelPermissions(botID, channelID)
+	if err != nil {
+		log.Warnf("Couldn't check voice permissions for %s, joining anyway: %v", channelID, err)
+		return nil
+	}
+	channel, err := lookupChannel(session, channelID)
+	if err != nil {
+		log.Warnf("Couldn't look up voice channel %s, joining anyway: %v", channelID, err)
+		return nil
+	}
+
+	occupants := 0
+	if guild, err := session.State.Guild(guildID); err == nil {
+		session.State.RLock()
+		for _, vs := range guild.VoiceStates {
+			if vs.ChannelID == channelID && vs.UserID != botID {
+				occupants++
+			}
+		}
+		session.State.RUnlock()
+	}
+
+	return voiceAccessError(perms, channel, occupants)
+}
+
+// voiceAccessError checks the bot's permissions in channel and whether
+// there's room for it among occupants.
+func voiceAccessError(perms int64, channel *discordgo.Channel, occupants int) error {
+	if perms&discordgo.PermissionViewChannel == 0 || perms&discordgo.PermissionVoiceConnect == 0 {
+		return ErrNoConnectPermission
+	}
+	// On a stage, getting to speak is handled after joining.
+	if channel.Type != discordgo.ChannelTypeGuildStageVoice && perms&discordgo.PermissionVoiceSpeak == 0 {
+		return ErrNoSpeakPermission
+	}
+	// Move Members lets the bot in past the user limit.
+	if channel.UserLimit > 0 && occupants >= channel.UserLimit && perms&discordgo.PermissionVoiceMoveMembers == 0 {
+		return ErrVoiceChannelFull
+	}
+	return nil
+}
+
+// lookupChannel reads a channel from the state cache, asking the API when
+// it isn't cached.
+func lookupChannel(session *discordgo.Session, channelID string) (*discordgo.Channel, error) {
+	if channel, err := session.State.Channel(channelID); err == nil {
+		return channel, nil
+	}
+	return session.Channel(channelID)
+}
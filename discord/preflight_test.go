@@ -0,0 +1,36 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestVoiceAccessError(t *testing.T) {
+	const base = discordgo.PermissionViewChannel | discordgo.PermissionVoiceConnect | discordgo.PermissionVoiceSpeak
+	voice := &discordgo.Channel{Type: discordgo.ChannelTypeGuildVoice}
+	limited := &discordgo.Channel{Type: discordgo.ChannelTypeGuildVoice, UserLimit: 2}
+	stage := &discordgo.Channel{Type: discordgo.ChannelTypeGuildStageVoice}
+
+	tests := []struct {
+		name      string
+		perms     int64
+		channel   *discordgo.Channel
+		occupants int
+		want      error
+	}{
+		{"allowed", base, voice, 5, nil},
+		{"no connect", base &^ discordgo.PermissionVoiceConnect, voice, 0, ErrNoConnectPermission},
+		{"hidden channel", base &^ discordgo.PermissionViewChannel, voice, 0, ErrNoConnectPermission},
+		{"no speak", base &^ discordgo.PermissionVoiceSpeak, voice, 0, ErrNoSpeakPermission},
+		{"stage without speak", base &^ discordgo.PermissionVoiceSpeak, stage, 0, nil},
+		{"room left", base, limited, 1, nil},
+		{"full", base, limited, 2, ErrVoiceChannelFull},
+		{"full but can move members", base | discordgo.PermissionVoiceMoveMembers, limited, 2, nil},
+	}
+	for _, tt := range tests {
+		if got := voiceAccessError(tt.perms, tt.channel, tt.occupants); got != tt.want {
+			t.Errorf("%s: voiceAccessError = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+)
+
+const (
+	apiBase = "https://discord.com/api/v10"
+
+	restTimeout = 15 * time.Second
+	restRetries = 3
+	// maxRateLimitWait is the longest a request waits out a rate limit,
+	// whether asked for by a 429 or left over on its route; past that it
+	// fails with ErrRateLimited instead of stalling the caller.
+	maxRateLimitWait = 30 * time.Second
+	// maxIdleBuckets bounds the route table; every interaction token is its
+	// own route, so idle ones are dropped past this size.
+	maxIdleBuckets = 500
+)
+
+// ErrRateLimited is returned when Discord keeps answering 429 after the
+// retries, or a request would wait longer than maxRateLimitWait.
+var ErrRateLimited = errors.New("rate limited by discord")
+
+// ErrInteractionExpired is returned when an interaction token is no longer
+// valid (unknown interaction or webhook), usually because it's older than
+// 15 minutes.
+var ErrInteractionExpired = errors.New("interaction token expired")
+
+// APIError is a non-2xx response from the Discord REST API.
+type APIError struct {
+	Method  string
+	Route   string
+	Status  int
+	Code    int
+	Message string
+	Body    []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("discord %s %s: %d %s (code %d)", e.Method, e.Route, e.Status, e.Message, e.Code)
+	}
+	return fmt.Sprintf("discord %s %s: %d %s", e.Method, e.Route, e.Status, string(e.Body))
+}
+
+// classify wraps an API error in the error the handlers branch on.
+func classify(apiErr *APIError) error {
+	switch {
+	case apiErr.Status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %v", ErrRateLimited, apiErr)
+	case apiErr.Code == 50013:
+		return &ErrMissingPermissions{OriginalError: apiErr}
+	case apiErr.Code == 50007:
+		return fmt.Errorf("%w: %v", ErrDMsClosed, apiErr)
+	case apiErr.Code == 10062 || apiErr.Code == 10015:
+		return fmt.Errorf("%w: %v", ErrInteractionExpired, apiErr)
+	}
+	return apiErr
+}
+
+// restClient sends Discord REST calls. Requests on the same route queue
+// behind each other, and the client waits out Discord's rate limits —
+// per-route from the X-RateLimit headers, globally from a global 429 —
+// instead of failing.
+type restClient struct {
+	base    string
+	http    *http.Client
+	retries int
+
+	mu          sync.Mutex
+	buckets     map[string]*routeBucket
+	globalUntil time.Time
+}
+
+type routeBucket struct {
+	mu      sync.Mutex // held for the whole request, so the route queues
+	resetAt time.Time  // set when the route's remaining count hits zero
+	users   int        // guarded by restClient.mu
+}
+
+var rest = newRESTClient(apiBase)
+
+func newRESTClient(base string) *restClient {
+	return &restClient{
+		base:    base,
+		http:    &http.Client{Timeout: restTimeout},
+		retries: restRetries,
+		buckets: make(map[string]*routeBucket),
+	}
+}
+
+// restRequest is one call. Body is kept as bytes so it can be resent.
+type restRequest struct {
+	method      string
+	path        string
+	body        []byte
+	contentType string
+	// authorize sends the bot token. Webhook calls carry their own token in
+	// the path and don't need it.
+	authorize bool
+}
+
+// jsonRequest builds a request with a JSON body; a nil payload sends none.
+func jsonRequest(method, path string, payload any, authorize bool) (*restRequest, error) {
+	req := &restRequest{method: method, path: path, authorize: authorize}
+	if payload != nil {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		req.body = body
+		req.contentType = "application/json"
+	}
+	return req, nil
+}
+
+// retryable reports whether the request can be sent again after Discord may
+// already have acted on it. A repeated POST would post the message twice.
+func (r *restRequest) retryable() bool {
+	return r.method != http.MethodPost
+}
+
+// minorIDs matches the IDs Discord doesn't bucket on: everything after
+// the channel, guild or webhook that leads the path.
+var minorIDs = regexp.MustCompile(`/(messages|voice-states)/[^/]+`)
+
+// routeKey names the rate limit bucket a request falls in.
+func routeKey(method, path string) string {
+	return method + " " + minorIDs.ReplaceAllString(path, "/$1/:id")
+}
+
+func (c *restClient) acquire(key string) *routeBucket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.buckets[key]
+	if !ok {
+		if len(c.buckets) >= maxIdleBuckets {
+			now := time.Now()
+			for k, idle := range c.buckets {
+				if idle.users == 0 && now.After(idle.resetAt) {
+					delete(c.buckets, k)
+				}
+			}
+		}
+		b = &routeBucket{}
+		c.buckets[key] = b
+	}
+	b.users++
+	return b
+}
+
+func (c *restClient) release(b *routeBucket) {
+	c.mu.Lock()
+	b.users--
+	c.mu.Unlock()
+}
+
+// do sends the request and returns the response body. 429s are retried
+// after the wait Discord asks for. Network errors and 5xx responses are
+// retried with a short backoff, except for a POST that may have reached
+// Discord: that's only retried when the connection failed before the
+// request went out.
+func (c *restClient) do(ctx context.Context, req *restRequest) ([]byte, error) {
+	key := routeKey(req.method, req.path)
+	b := c.acquire(key)
+	defer c.release(b)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		c.mu.Lock()
+		until := c.globalUntil
+		c.mu.Unlock()
+		if b.resetAt.After(until) {
+			until = b.resetAt
+		}
+		if wait := time.Until(until); wait > maxRateLimitWait {
+			return nil, fmt.Errorf("%w: %s is limited for another %v", ErrRateLimited, key, wait.Round(time.Second))
+		}
+		if err := waitUntil(ctx, until); err != nil {
+			return nil, err
+		}
+
+		resp, sent, err := c.send(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			if sent && !req.retryable() {
+				return nil, fmt.Errorf("discord %s failed after sending: %w", key, err)
+			}
+			lastErr = err
+			log.Warnf("Discord %s failed (attempt %d): %v", key, attempt+1, err)
+			if err := sleepCtx(ctx, backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			if !req.retryable() {
+				return nil, fmt.Errorf("discord %s: reading response: %w", key, err)
+			}
+			lastErr = err
+			continue
+		}
+		c.noteLimits(b, resp.Header)
+
+		if resp.StatusCode < 300 {
+			return body, nil
+		}
+
+		apiErr := &APIError{Method: req.method, Route: key, Status: resp.StatusCode, Body: body}
+		var discordErr DiscordErrorResponse
+		if json.Unmarshal(body, &discordErr) == nil {
+			apiErr.Code = discordErr.Code
+			apiErr.Message = discordErr.Message
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			wait, global := retryAfter(resp.Header, body)
+			if wait > maxRateLimitWait {
+				return nil, classify(apiErr)
+			}
+			log.Warnf("Discord rate limited %s, retrying in %v (global: %v)", key, wait, global)
+			if global {
+				c.mu.Lock()
+				c.globalUntil = time.Now().Add(wait)
+				c.mu.Unlock()
+			} else {
+				b.resetAt = time.Now().Add(wait)
+			}
+			lastErr = apiErr
+		case resp.StatusCode >= 500:
+			if !req.retryable() {
+				return nil, classify(apiErr)
+			}
+			lastErr = apiErr
+			if err := sleepCtx(ctx, backoff(attempt)); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, classify(apiErr)
+		}
+	}
+
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) {
+		return nil, classify(apiErr)
+	}
+	return nil, fmt.Errorf("discord %s failed after %d attempts: %w", key, c.retries+1, lastErr)
+}
+
+// send makes one attempt at the request. sent reports whether the request
+// was written to the connection, so a failure before that is safe to retry
+// for any method.
+func (c *restClient) send(ctx context.Context, req *restRequest) (resp *http.Response, sent bool, err error) {
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	// The transport reports the write from its own goroutine.
+	var wrote atomic.Bool
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				wrote.Store(true)
+			}
+		},
+	}
+	httpReq, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), req.method, c.base+req.path, body)
+	if err != nil {
+		return nil, false, err
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	if req.authorize {
+		httpReq.Header.Set("Authorization", "Bot "+config.Config.Discord.BotToken)
+	}
+	resp, err = c.http.Do(httpReq)
+	return resp, wrote.Load(), err
+}
+
+// noteLimits holds the route once its remaining count runs out.
+func (c *restClient) noteLimits(b *routeBucket, header http.Header) {
+	if header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	if secs, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil {
+		b.resetAt = time.Now().Add(time.Duration(secs * float64(time.Second)))
+	}
+}
+
+// retryAfter reads how long a 429 asks to wait, preferring the body's
+// fractional seconds over the header's whole ones.
+func retryAfter(header http.Header, body []byte) (time.Duration, bool) {
+	var limited struct {
+		RetryAfter float64 `json:"retry_after"`
+		Global     bool    `json:"global"`
+	}
+	_ = json.Unmarshal(body, &limited)
+	global := limited.Global || header.Get("X-RateLimit-Global") == "true"
+	if limited.RetryAfter > 0 {
+		return time.Duration(limited.RetryAfter * float64(time.Second)), global
+	}
+	if secs, err := strconv.ParseFloat(header.Get("Retry-After"), 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), global
+	}
+	return time.Second, global
+}
+
+func backoff(attempt int) time.Duration {
+	return time.Duration(250*(attempt+1)) * time.Millisecond
+}
+
+func waitUntil(ctx context.Context, t time.Time) error {
+	if d := time.Until(t); d > 0 {
+		return sleepCtx(ctx, d)
+	}
+	return nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
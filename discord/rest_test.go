@@ -0,0 +1,208 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testClient(t *testing.T, handler http.HandlerFunc) *restClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return newRESTClient(server.URL)
+}
+
+func TestRouteKey(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"POST", "/channels/1/messages", "POST /channels/1/messages"},
+		{"PATCH", "/channels/1/messages/99", "PATCH /channels/1/messages/:id"},
+		{"PATCH", "/webhooks/app/tok/messages/@original", "PATCH /webhooks/app/tok/messages/:id"},
+		{"GET", "/guilds/1/voice-states/42", "GET /guilds/1/voice-states/:id"},
+	}
+	for _, tt := range tests {
+		if got := routeKey(tt.method, tt.path); got != tt.want {
+			t.Errorf("routeKey(%s, %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRESTRetriesAfterRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.05, "global": false}`))
+			return
+		}
+		w.Write([]byte(`{"id": "1"}`))
+	})
+
+	start := time.Now()
+	body, err := client.do(context.Background(), &restRequest{method: "POST", path: "/channels/1/messages"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"id": "1"}` || calls.Load() != 2 {
+		t.Errorf("body = %s after %d calls", body, calls.Load())
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("retried after %v, before retry_after", waited)
+	}
+}
+
+func TestRESTGivesUpOnLongRateLimit(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"retry_after": 600}`))
+	})
+	_, err := client.do(context.Background(), &restRequest{method: "POST", path: "/channels/1/messages"})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestRESTWaitsForBucketReset(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset-After", "0.05")
+		w.Write([]byte(`{}`))
+	})
+
+	for range 2 {
+		if _, err := client.do(context.Background(), &restRequest{method: "PATCH", path: "/channels/1/messages/2"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gap := times[1].Sub(times[0]); gap < 50*time.Millisecond {
+		t.Errorf("second request %v after the first, before the bucket reset", gap)
+	}
+}
+
+func TestRESTErrorTypes(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		check  func(error) bool
+	}{
+		{http.StatusForbidden, `{"message": "Missing Permissions", "code": 50013}`, IsMissingPermissions},
+		{http.StatusForbidden, `{"message": "Cannot send messages to this user", "code": 50007}`, func(err error) bool { return errors.Is(err, ErrDMsClosed) }},
+		{http.StatusNotFound, `{"message": "Unknown Webhook", "code": 10015}`, func(err error) bool { return errors.Is(err, ErrInteractionExpired) }},
+		{http.StatusBadRequest, `{"message": "Invalid Form Body", "code": 50035}`, func(err error) bool {
+			var apiErr *APIError
+			return errors.As(err, &apiErr) && apiErr.Code == 50035 && apiErr.Status == http.StatusBadRequest
+		}},
+	}
+	for _, tt := range tests {
+		client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		})
+		_, err := client.do(context.Background(), &restRequest{method: "POST", path: "/channels/1/messages"})
+		if err == nil || !tt.check(err) {
+			t.Errorf("%d %s: got err %v", tt.status, tt.body, err)
+		}
+	}
+}
+
+func TestRESTRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	if _, err := client.do(context.Background(), &restRequest{method: "DELETE", path: "/channels/1/messages/2"}); err != nil {
+		t.Errorf("err = %v after %d calls", err, calls.Load())
+	}
+}
+
+func TestRESTDoesNotRepeatPOSTs(t *testing.T) {
+	var calls atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	_, err := client.do(context.Background(), &restRequest{method: "POST", path: "/channels/1/messages"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadGateway || calls.Load() != 1 {
+		t.Errorf("502 on a POST: err = %v after %d calls, want one call", err, calls.Load())
+	}
+
+	calls.Store(0)
+	client = testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// Drop the connection after Discord has the message, before it answers.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	})
+	if _, err := client.do(context.Background(), &restRequest{method: "POST", path: "/channels/1/messages"}); err == nil || calls.Load() != 1 {
+		t.Errorf("dropped POST: err = %v after %d calls, want an error after one call", err, calls.Load())
+	}
+}
+
+// failFirst fails its first round trip without sending anything, like a
+// refused connection.
+type failFirst struct {
+	failed atomic.Bool
+	next   http.RoundTripper
+}
+
+func (f *failFirst) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !f.failed.Swap(true) {
+		return nil, errors.New("connection refused")
+	}
+	return f.next.RoundTrip(r)
+}
+
+func TestRESTRetriesPOSTsNeverSent(t *testing.T) {
+	var calls atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"id": "1"}`))
+	})
+	client.http.Transport = &failFirst{next: http.DefaultTransport}
+
+	if _, err := client.do(context.Background(), &restRequest{method: "POST", path: "/channels/1/messages"}); err != nil || calls.Load() != 1 {
+		t.Errorf("err = %v after %d calls, want the POST retried once", err, calls.Load())
+	}
+}
+
+func TestRESTGivesUpOnLongBucketReset(t *testing.T) {
+	var calls atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset-After", "120")
+		w.Write([]byte(`{}`))
+	})
+
+	req := &restRequest{method: "PATCH", path: "/channels/1/messages/2"}
+	if _, err := client.do(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err := client.do(context.Background(), req)
+	if !errors.Is(err, ErrRateLimited) || calls.Load() != 1 {
+		t.Errorf("err = %v after %d calls, want ErrRateLimited without sending", err, calls.Load())
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("waited %v before giving up", waited)
+	}
+}
@@ -16,7 +16,9 @@ func NewSession() (*discordgo.Session, error) {
 		log.Fatalf("Error creating Discord session: %v", err)
 		return nil, err
 	}
-	session.Identify.Intents = discordgo.IntentsGuildVoiceStates
+	// Reactions are only for the playback controls on now-playing cards.
+	session.Identify.Intents = discordgo.IntentsGuildVoiceStates | discordgo.IntentsGuildMessageReactions
+	trackVoiceStates(session)
 
 	// Enable DAVE E2EE for voice connections
 	session.DaveSessionCreate = NewDaveSessionCreate()
@@ -0,0 +1,55 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// restRequester sends raw REST calls; *discordgo.Session satisfies it.
+type restRequester interface {
+	RequestWithBucketID(method, urlStr string, data interface{}, bucketID string, options ...discordgo.RequestOption) ([]byte, error)
+}
+
+// IsStageChannel reports whether channelID is a Stage channel, checking the
+// state cache before asking the API.
+func IsStageChannel(session *discordgo.Session, channelID string) bool {
+	channel, err := lookupChannel(session, channelID)
+	if err != nil {
+		log.Warnf("Couldn't look up voice channel %s: %v", channelID, err)
+		return false
+	}
+	return channel.Type == discordgo.ChannelTypeGuildStageVoice
+}
+
+// BecomeStageSpeaker makes the bot a speaker in a Stage channel it has
+// joined. Bots join stages as suppressed audience members, so nothing they
+// play is heard. With Mute Members the bot moves itself to the stage;
+// without it, it raises its hand and returns speaking=false until a stage
+// moderator accepts.
+func BecomeStageSpeaker(api restRequester, guildID, channelID string) (speaking bool, err error) {
+	endpoint := discordgo.EndpointGuildMemberVoiceState(guildID, "@me")
+	bucket := discordgo.EndpointGuildMemberVoiceState(guildID, "")
+
+	_, err = api.RequestWithBucketID("PATCH", endpoint, map[string]any{
+		"channel_id": channelID,
+		"suppress":   false,
+	}, bucket)
+	if err == nil {
+		return true, nil
+	}
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Response == nil || restErr.Response.StatusCode != http.StatusForbidden {
+		return false, err
+	}
+
+	log.Infof("No permission to speak on stage %s, requesting to speak", channelID)
+	_, err = api.RequestWithBucketID("PATCH", endpoint, map[string]any{
+		"channel_id":                 channelID,
+		"request_to_speak_timestamp": time.Now().UTC().Format(time.RFC3339),
+	}, bucket)
+	return false, err
+}
@@ -0,0 +1,58 @@
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// fakeREST records each request and answers it with the next error.
+type fakeREST struct {
+	errs     []error
+	requests []string
+}
+
+func (f *fakeREST) RequestWithBucketID(method, urlStr string, data interface{}, bucketID string, options ...discordgo.RequestOption) ([]byte, error) {
+	body, _ := json.Marshal(data)
+	f.requests = append(f.requests, method+" "+urlStr+" "+string(body))
+	var err error
+	if len(f.errs) > 0 {
+		err, f.errs = f.errs[0], f.errs[1:]
+	}
+	return nil, err
+}
+
+func TestBecomeStageSpeaker(t *testing.T) {
+	api := &fakeREST{}
+	speaking, err := BecomeStageSpeaker(api, "g1", "stage1")
+	if err != nil || !speaking {
+		t.Fatalf("BecomeStageSpeaker = %v, %v; want speaking", speaking, err)
+	}
+	want := "PATCH " + discordgo.EndpointGuildMemberVoiceState("g1", "@me") + ` {"channel_id":"stage1","suppress":false}`
+	if len(api.requests) != 1 || api.requests[0] != want {
+		t.Errorf("requests = %q, want %q", api.requests, want)
+	}
+}
+
+func TestBecomeStageSpeakerRaisesHand(t *testing.T) {
+	forbidden := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+	api := &fakeREST{errs: []error{forbidden}}
+
+	speaking, err := BecomeStageSpeaker(api, "g1", "stage1")
+	if err != nil || speaking {
+		t.Fatalf("BecomeStageSpeaker = %v, %v; want a raised hand", speaking, err)
+	}
+	if len(api.requests) != 2 || !strings.Contains(api.requests[1], `"request_to_speak_timestamp"`) {
+		t.Errorf("requests = %q, want a request to speak", api.requests)
+	}
+}
+
+func TestBecomeStageSpeakerOtherError(t *testing.T) {
+	api := &fakeREST{errs: []error{&discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusNotFound}}}}
+	if _, err := BecomeStageSpeaker(api, "g1", "stage1"); err == nil || len(api.requests) != 1 {
+		t.Errorf("err = %v after %d requests; want the error and no retry", err, len(api.requests))
+	}
+}
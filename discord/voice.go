@@ -1,22 +1,28 @@
 package discord
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"time"
 
 	sentry "github.com/getsentry/sentry-go"
 	log "github.com/sirupsen/logrus"
 
-	"beatbot/config"
-
 	"github.com/bwmarrin/discordgo"
 )
 
-func JoinVoiceChannel(session *discordgo.Session, guildId string, channelId string) (vc *discordgo.VoiceConnection, err error) {
-	vc, err = session.ChannelVoiceJoin(guildId, channelId, false, true)
+// JoinVoiceChannel connects to a voice channel. The bot deafens itself
+// unless it needs to hear the channel, which only /clip does; a connection
+// made deafened never receives audio, even if undeafened later.
+func JoinVoiceChannel(session *discordgo.Session, guildId string, channelId string, deaf bool) (vc *discordgo.VoiceConnection, err error) {
+	if err := CheckVoiceAccess(session, guildId, channelId); err != nil {
+		log.Warnf("Not joining voice channel %s: %v", channelId, err)
+		return nil, err
+	}
+
+	vc, err = session.ChannelVoiceJoin(guildId, channelId, false, deaf)
 	if err != nil {
 		sentry.CaptureException(err)
 		log.Errorf("Error joining voice channel: %v", err)
@@ -26,7 +32,7 @@ func JoinVoiceChannel(session *discordgo.Session, guildId string, channelId stri
 	// Add connection check with timeout
 	maxRetries := 5
 	for range maxRetries {
-		if vc.Ready && vc.OpusSend != nil {
+		if vc.Ready && vc.OpusSend != nil && (deaf || vc.OpusRecv != nil) {
 			return vc, nil
 		}
 		time.Sleep(time.Second)
@@ -76,67 +82,32 @@ type VoiceState struct {
 	Suppress   bool             `json:"suppress"`
 }
 
-func MakeRequestWithRetries(client *http.Client, req *http.Request) (*http.Response, error) {
-	logger := log.WithFields(log.Fields{
-		"module": "discord.voice",
-		"method": req.Method,
-		"url":    req.URL.String(),
-	})
-
-	retries := 3
-
-	for i := 0; i < retries; i++ {
-		resp, err := client.Do(req)
-		if err != nil {
-			logger.Errorf("error making request: %v", err)
-			time.Sleep(time.Millisecond * time.Duration(100*(i+1)))
-			continue
-		}
-
-		return resp, nil
-	}
-
-	return nil, fmt.Errorf("failed to make request after %d retries", retries)
-}
-
+// GetMemberVoiceState returns the user's voice state in the guild, or nil
+// when they aren't in a voice channel. It answers from the gateway's voice
+// states when it can and asks the API otherwise.
 func GetMemberVoiceState(userId *string, guildId *string) (*VoiceState, error) {
 	if userId == nil || guildId == nil {
 		return nil, fmt.Errorf("user or guild ID is empty")
 	}
 
-	log.Tracef("getting voice state for user %s in guild %s", *userId, *guildId)
-
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://discord.com/api/v10/guilds/%s/voice-states/%s", *guildId, *userId), nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
+	if state, known := voiceStates.lookup(*guildId, *userId); known {
+		return state, nil
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bot %s", config.Config.Discord.BotToken))
-
-	resp, err := MakeRequestWithRetries(client, req)
-	if err != nil {
-		return nil, fmt.Errorf("error getting voice state: %v", err)
-	}
-	defer resp.Body.Close()
+	log.Tracef("getting voice state for user %s in guild %s", *userId, *guildId)
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := rest.do(context.Background(), &restRequest{
+		method:    "GET",
+		path:      fmt.Sprintf("/guilds/%s/voice-states/%s", *guildId, *userId),
+		authorize: true,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp DiscordErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return nil, fmt.Errorf("error parsing error response: %v", err)
-		}
-
 		// If user is not in voice channel, return nil without error
-		if errorResp.Code == 10065 {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == 10065 {
 			return nil, nil
 		}
-
-		return nil, fmt.Errorf("discord API error: %s (code: %d)", errorResp.Message, errorResp.Code)
+		return nil, fmt.Errorf("error getting voice state: %w", err)
 	}
 
 	var voiceState VoiceState
@@ -0,0 +1,145 @@
+package discord
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// voiceStateCache mirrors who is in which voice channel from the gateway's
+// voice state events, so looking up a requester's channel doesn't cost a
+// REST call on every /play. A guild's states are complete once its
+// GUILD_CREATE has arrived; until then, and after the gateway drops (events
+// may have been missed), lookups go to the API.
+type voiceStateCache struct {
+	mu     sync.RWMutex
+	states map[string]map[string]VoiceState // guild ID -> user ID -> state
+	synced map[string]bool                  // guilds whose states are complete
+}
+
+var voiceStates = newVoiceStateCache()
+
+func newVoiceStateCache() *voiceStateCache {
+	return &voiceStateCache{
+		states: make(map[string]map[string]VoiceState),
+		synced: make(map[string]bool),
+	}
+}
+
+// trackVoiceStates keeps voiceStates current from session's events.
+func trackVoiceStates(session *discordgo.Session) {
+	session.AddHandler(func(_ *discordgo.Session, event *discordgo.GuildCreate) {
+		voiceStates.loadGuild(event.ID, event.VoiceStates)
+	})
+	session.AddHandler(func(_ *discordgo.Session, event *discordgo.GuildDelete) {
+		voiceStates.dropGuild(event.ID)
+	})
+	session.AddHandler(func(_ *discordgo.Session, event *discordgo.VoiceStateUpdate) {
+		voiceStates.update(event.VoiceState)
+	})
+	session.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) {
+		voiceStates.unsync()
+	})
+}
+
+// loadGuild replaces a guild's states with the full list from GUILD_CREATE.
+func (c *voiceStateCache) loadGuild(guildID string, states []*discordgo.VoiceState) {
+	guild := make(map[string]VoiceState, len(states))
+	for _, vs := range states {
+		if vs != nil && vs.ChannelID != "" {
+			state := fromGateway(vs)
+			state.GuildID = guildID
+			guild[vs.UserID] = state
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.states[guildID] = guild
+	c.synced[guildID] = true
+}
+
+func (c *voiceStateCache) dropGuild(guildID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.states, guildID)
+	delete(c.synced, guildID)
+}
+
+// update applies a join, move or leave.
+func (c *voiceStateCache) update(vs *discordgo.VoiceState) {
+	if vs == nil || vs.GuildID == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	guild := c.states[vs.GuildID]
+	if vs.ChannelID == "" {
+		delete(guild, vs.UserID)
+		return
+	}
+	if guild == nil {
+		guild = make(map[string]VoiceState)
+		c.states[vs.GuildID] = guild
+	}
+	guild[vs.UserID] = fromGateway(vs)
+}
+
+// unsync marks every guild incomplete until its next GUILD_CREATE.
+func (c *voiceStateCache) unsync() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.synced)
+}
+
+// lookup returns userID's voice state in guildID. known is false when the
+// cache can't say, and the API has to be asked; a known nil state means the
+// user isn't in a voice channel.
+func (c *voiceStateCache) lookup(guildID, userID string) (state *VoiceState, known bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.synced[guildID] {
+		return nil, false
+	}
+	if vs, ok := c.states[guildID][userID]; ok {
+		return &vs, true
+	}
+	return nil, true
+}
+
+// fromGateway converts discordgo's voice state to the REST shape handlers
+// already use.
+func fromGateway(vs *discordgo.VoiceState) VoiceState {
+	state := VoiceState{
+		ChannelID:  vs.ChannelID,
+		GuildID:    vs.GuildID,
+		UserID:     vs.UserID,
+		SessionID:  vs.SessionID,
+		Deaf:       vs.Deaf,
+		Mute:       vs.Mute,
+		SelfDeaf:   vs.SelfDeaf,
+		SelfMute:   vs.SelfMute,
+		SelfVideo:  vs.SelfVideo,
+		SelfStream: vs.SelfStream,
+		Suppress:   vs.Suppress,
+	}
+	if m := vs.Member; m != nil {
+		if m.User != nil {
+			state.Member.User = VoiceStateUser{
+				ID:            m.User.ID,
+				Username:      m.User.Username,
+				Avatar:        m.User.Avatar,
+				Discriminator: m.User.Discriminator,
+			}
+		}
+		if m.Nick != "" {
+			nick := m.Nick
+			state.Member.Nick = &nick
+		}
+		state.Member.Roles = m.Roles
+		if !m.JoinedAt.IsZero() {
+			state.Member.JoinedAt = m.JoinedAt.Format(time.RFC3339)
+		}
+	}
+	return state
+}
@@ -0,0 +1,54 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestVoiceStateCache(t *testing.T) {
+	c := newVoiceStateCache()
+
+	if _, known := c.lookup("g1", "u1"); known {
+		t.Fatal("an unseen guild must fall back to the API")
+	}
+
+	c.loadGuild("g1", []*discordgo.VoiceState{
+		{UserID: "u1", ChannelID: "vc1", Member: &discordgo.Member{User: &discordgo.User{ID: "u1", Username: "alice"}, Nick: "Al"}},
+	})
+	state, known := c.lookup("g1", "u1")
+	if !known || state == nil || state.ChannelID != "vc1" || state.GuildID != "g1" {
+		t.Fatalf("lookup = %+v, %v; want u1 in vc1", state, known)
+	}
+	if state.Member.User.Username != "alice" || state.Member.Nick == nil || *state.Member.Nick != "Al" {
+		t.Errorf("member = %+v, want alice/Al", state.Member)
+	}
+	if state, known := c.lookup("g1", "u2"); !known || state != nil {
+		t.Errorf("absent user = %+v, %v; want known not in voice", state, known)
+	}
+
+	c.update(&discordgo.VoiceState{GuildID: "g1", UserID: "u1", ChannelID: "vc2"})
+	if state, _ := c.lookup("g1", "u1"); state == nil || state.ChannelID != "vc2" {
+		t.Errorf("after move = %+v, want vc2", state)
+	}
+	c.update(&discordgo.VoiceState{GuildID: "g1", UserID: "u1"})
+	if state, known := c.lookup("g1", "u1"); !known || state != nil {
+		t.Errorf("after leave = %+v, %v; want known not in voice", state, known)
+	}
+
+	// Events can be missed while disconnected.
+	c.update(&discordgo.VoiceState{GuildID: "g1", UserID: "u3", ChannelID: "vc1"})
+	c.unsync()
+	if _, known := c.lookup("g1", "u3"); known {
+		t.Error("lookups after a disconnect must fall back to the API")
+	}
+	c.loadGuild("g1", nil)
+	if state, known := c.lookup("g1", "u3"); !known || state != nil {
+		t.Errorf("after resync = %+v, %v; want the fresh list", state, known)
+	}
+
+	c.dropGuild("g1")
+	if _, known := c.lookup("g1", "u3"); known {
+		t.Error("a removed guild must fall back to the API")
+	}
+}
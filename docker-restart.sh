@@ -38,9 +38,15 @@ docker run -d --name discord-audio-streamer \
   -e DISCORD_APP_ID=$DISCORD_APP_ID \
   -e DISCORD_PUBLIC_KEY=$DISCORD_PUBLIC_KEY \
   -e DISCORD_BOT_TOKEN=$DISCORD_BOT_TOKEN \
+  -e DISCORD_MODE=${DISCORD_MODE:-webhook} \
   -e ENFORCE_VOICE_CHANNEL=$ENFORCE_VOICE_CHANNEL \
   -e YOUTUBE_API_KEY=$YOUTUBE_API_KEY \
   -e YOUTUBE_PLAYLIST_LIMIT=${YOUTUBE_PLAYLIST_LIMIT:-15} \
+  -e YTDLP_COOKIES_FILE=$YTDLP_COOKIES_FILE \
+  -e YTDLP_PO_TOKEN=$YTDLP_PO_TOKEN \
+  -e YTDLP_VERSION=$YTDLP_VERSION \
+  -e YTDLP_UPDATE_HOURS=${YTDLP_UPDATE_HOURS:-24} \
+  -e ADMIN_TOKEN=$ADMIN_TOKEN \
   -e SPOTIFY_CLIENT_ID=$SPOTIFY_CLIENT_ID \
   -e SPOTIFY_CLIENT_SECRET=$SPOTIFY_CLIENT_SECRET \
   -e SPOTIFY_ENABLED=${SPOTIFY_ENABLED:-true} \
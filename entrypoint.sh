@@ -1,21 +1,14 @@
 #!/bin/bash
 set -e
 
-# Update yt-dlp to latest version on every container start
-echo "Updating yt-dlp..."
-timeout 30 yt-dlp -U || echo "Warning: yt-dlp self-update failed, using bundled version"
-
-# Register slash commands with Discord on startup
-if [ -n "$DISCORD_BOT_TOKEN" ] && [ -n "$DISCORD_APP_ID" ]; then
-  echo "Registering Discord commands..."
-  curl -sf -X PUT \
-    -H "Authorization: Bot $DISCORD_BOT_TOKEN" \
-    -H "Content-Type: application/json" \
-    -d @commands.json \
-    "https://discord.com/api/v10/applications/$DISCORD_APP_ID/commands" > /dev/null
-  echo "Commands registered."
+# Update yt-dlp on every container start: to YTDLP_VERSION when pinned,
+# otherwise to the latest release
+if [ -n "$YTDLP_VERSION" ]; then
+  echo "Updating yt-dlp to pinned $YTDLP_VERSION..."
+  timeout 30 yt-dlp --update-to "$YTDLP_VERSION" || echo "Warning: yt-dlp update to $YTDLP_VERSION failed, using bundled version"
 else
-  echo "Warning: DISCORD_BOT_TOKEN or DISCORD_APP_ID not set, skipping command registration."
+  echo "Updating yt-dlp..."
+  timeout 30 yt-dlp -U || echo "Warning: yt-dlp self-update failed, using bundled version"
 fi
 
 exec ./discord-bot
@@ -0,0 +1,138 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+)
+
+// maxDJPicks caps how many songs one /dj request queues.
+const maxDJPicks = 5
+
+// DJIntent is what a free-form /dj request asks for, as the model read it,
+// and the songs it picked to match.
+type DJIntent struct {
+	Genre   string   `json:"genre"`
+	Mood    string   `json:"mood"`
+	Era     string   `json:"era"`
+	Artist  string   `json:"artist"`
+	Picks   []DJPick `json:"songs"`
+	Summary string   `json:"summary"` // the DJ explaining the picks, for the listener
+}
+
+// DJPick is one song for a /dj request.
+type DJPick struct {
+	Query string `json:"query"` // YouTube search query, "Artist - Song Title"
+	Why   string `json:"why"`
+}
+
+// Tags returns the intent's non-empty genre, mood, era and artist.
+func (i DJIntent) Tags() []string {
+	var tags []string
+	for _, tag := range []string{i.Genre, i.Mood, i.Era, i.Artist} {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// InterpretDJRequest reads a free-form request like "something chill for
+// studying" into genre, mood, era and artist, and picks songs to match.
+// recentSongs give context. Returns nil if AI is disabled, on error, or
+// when the reply can't be read.
+func InterpretDJRequest(ctx context.Context, request string, recentSongs []string) *DJIntent {
+	if !config.Config.Gemini.Enabled || textProvider == nil {
+		return nil
+	}
+
+	span := sentry.StartSpan(ctx, "gemini.dj_request")
+	span.Description = "Interpret DJ request"
+	defer span.Finish()
+
+	songList := "No recent songs played yet."
+	if len(recentSongs) > 0 {
+		songList = strings.Join(recentSongs, "\n")
+	}
+
+	instructions := buildPrompt(ctx, fmt.Sprintf(`A listener asked you, the DJ: %q
+
+Recently played, for context:
+%s
+
+Work out what they're after, then pick songs for it. If they named a specific song, pick that one. Otherwise pick 3-5 specific, real songs that fit, with some variety, and avoid repeating the recent songs.
+
+Reply with only a JSON object, no code fences, in this shape:
+{"genre": "", "mood": "", "era": "", "artist": "", "songs": [{"query": "Artist - Song Title", "why": ""}], "summary": ""}
+
+- genre, mood, era and artist: what the request asks for, briefly; leave any it doesn't imply empty
+- songs: each "query" is a YouTube search for the song; "why" is a few words on why it fits
+- summary: one or two sentences to the listener explaining your picks, in your DJ voice, no markdown`, request, songList))
+
+	response := generateResponse(ctx, inLanguage(ctx, instructions))
+	if response == "" {
+		span.Status = sentry.SpanStatusInternalError
+		return nil
+	}
+
+	intent, err := parseDJIntent(response)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"module":   "gemini",
+			"request":  request,
+			"response": response,
+		}).Warnf("Couldn't read DJ request interpretation: %v", err)
+		span.Status = sentry.SpanStatusInternalError
+		return nil
+	}
+
+	span.Status = sentry.SpanStatusOK
+	span.SetData("num_picks", fmt.Sprintf("%d", len(intent.Picks)))
+	log.WithFields(log.Fields{
+		"module":  "gemini",
+		"request": request,
+		"tags":    intent.Tags(),
+		"picks":   len(intent.Picks),
+	}).Debug("Interpreted DJ request")
+
+	return intent
+}
+
+// parseDJIntent reads the model's JSON reply, tolerating code fences or a
+// sentence around it, and drops picks without a query.
+func parseDJIntent(response string) (*DJIntent, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in reply")
+	}
+
+	var intent DJIntent
+	if err := json.Unmarshal([]byte(response[start:end+1]), &intent); err != nil {
+		return nil, err
+	}
+
+	picks := intent.Picks[:0]
+	for _, pick := range intent.Picks {
+		pick.Query = strings.Trim(strings.TrimSpace(pick.Query), "\"'`")
+		pick.Why = strings.TrimSpace(pick.Why)
+		if pick.Query != "" {
+			picks = append(picks, pick)
+		}
+	}
+	if len(picks) == 0 {
+		return nil, fmt.Errorf("no songs picked")
+	}
+	if len(picks) > maxDJPicks {
+		picks = picks[:maxDJPicks]
+	}
+	intent.Picks = picks
+	intent.Summary = strings.TrimSpace(intent.Summary)
+	return &intent, nil
+}
@@ -0,0 +1,57 @@
+package gemini
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseDJIntent(t *testing.T) {
+	response := "Here you go:\n```json\n" + `{"genre": "lo-fi", "mood": "chill", "era": "", "artist": " ",
+"songs": [{"query": "\"Nujabes - Feather\"", "why": " smooth beats "}, {"query": "  ", "why": "dropped"}, {"query": "Jinsang - Affection", "why": ""}],
+"summary": " Easy listening for focus. "}` + "\n```"
+
+	intent, err := parseDJIntent(response)
+	if err != nil {
+		t.Fatalf("parseDJIntent: %v", err)
+	}
+	if got := strings.Join(intent.Tags(), ","); got != "lo-fi,chill" {
+		t.Errorf("Tags = %q, want lo-fi,chill", got)
+	}
+	if len(intent.Picks) != 2 {
+		t.Fatalf("got %d picks, want 2: %+v", len(intent.Picks), intent.Picks)
+	}
+	if intent.Picks[0] != (DJPick{Query: "Nujabes - Feather", Why: "smooth beats"}) {
+		t.Errorf("first pick = %+v", intent.Picks[0])
+	}
+	if intent.Summary != "Easy listening for focus." {
+		t.Errorf("Summary = %q", intent.Summary)
+	}
+}
+
+func TestParseDJIntentCapsPicks(t *testing.T) {
+	var songs []string
+	for i := range maxDJPicks + 3 {
+		songs = append(songs, fmt.Sprintf(`{"query": "Artist - Song %d"}`, i))
+	}
+	intent, err := parseDJIntent(`{"songs": [` + strings.Join(songs, ",") + `]}`)
+	if err != nil {
+		t.Fatalf("parseDJIntent: %v", err)
+	}
+	if len(intent.Picks) != maxDJPicks {
+		t.Errorf("got %d picks, want %d", len(intent.Picks), maxDJPicks)
+	}
+}
+
+func TestParseDJIntentRejects(t *testing.T) {
+	for _, response := range []string{
+		"Sorry, I can't help with that.",
+		`{"genre": "jazz", "songs": []}`,
+		`{"songs": [{"query": ""}]}`,
+		`{"songs": "not a list"}`,
+	} {
+		if intent, err := parseDJIntent(response); err == nil {
+			t.Errorf("parseDJIntent(%q) = %+v, want error", response, intent)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
 
 	"beatbot/config"
 
@@ -33,6 +34,12 @@ var leadingNumberRe = regexp.MustCompile(`^\d+\.\s*`)
 // Creating a new HTTP client + TLS session on every call was wasteful.
 var defaultClient *genai.Client
 
+// TTSReady reports whether there's a Gemini client to synthesize speech
+// with. Without a GEMINI_API_KEY, another LLM_PROVIDER can still write text.
+func TTSReady() bool {
+	return defaultClient != nil
+}
+
 // AvailableVoices lists all Gemini TTS voice presets available for DJ announcements.
 var AvailableVoices = []string{
 	"Zephyr", "Puck", "Charon", "Kore", "Fenrir", "Leda", "Orus", "Aoede",
@@ -70,75 +77,127 @@ type DJScriptContext struct {
 	VoiceChannelMembers []string // display names of listeners in the voice channel (excludes the bot)
 }
 
-// Init initializes the shared Gemini client. Must be called once at startup
-// (after config is loaded) before any Gemini functions are used. Safe to call
-// when Gemini is disabled — it becomes a no-op.
+// Init initializes the shared Gemini client and the text provider picked by
+// LLM_PROVIDER. Must be called once at startup (after config is loaded)
+// before any Gemini functions are used. Safe to call when AI features are
+// disabled — it becomes a no-op.
 func Init() error {
 	if !config.Config.Gemini.Enabled {
 		return nil
 	}
-	// Use a background context for client creation — the client itself is
-	// long-lived and should not be tied to any single request context.
-	c, err := genai.NewClient(context.Background(), &genai.ClientConfig{
-		APIKey:  config.Config.Gemini.APIKey,
-		Backend: genai.BackendGeminiAPI,
-		HTTPClient: &http.Client{
-			Timeout: 35 * time.Second,
-		},
-	})
+	// Another text provider only needs Gemini for TTS, and only if there's
+	// a key for it.
+	if config.Config.LLM.Provider == config.LLMGemini || config.Config.Gemini.APIKey != "" {
+		// Use a background context for client creation — the client itself is
+		// long-lived and should not be tied to any single request context.
+		c, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+			APIKey:  config.Config.Gemini.APIKey,
+			Backend: genai.BackendGeminiAPI,
+			HTTPClient: &http.Client{
+				Timeout: 35 * time.Second,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create Gemini client: %w", err)
+		}
+		defaultClient = c
+		log.Info("Gemini client initialized")
+	}
+
+	p, err := newTextProvider(config.Config.LLM)
 	if err != nil {
-		return fmt.Errorf("failed to create Gemini client: %w", err)
+		return err
 	}
-	defaultClient = c
-	log.Info("Gemini client initialized")
+	textProvider = p
+	log.Infof("AI replies written by %s (%s)", p.Name(), p.Model())
 	return nil
 }
 
+// errNoReply is returned by generate when the model wasn't asked, or gave
+// an empty reply.
+var errNoReply = errors.New("no AI reply")
+
+// ErrRateLimited is returned instead of errNoReply when the guild has used
+// up its AI requests for now. The question is still open, so callers that
+// remember answers must not remember this one.
+var ErrRateLimited = errors.New("AI rate limit reached")
+
+// generateResponse returns the model's reply to prompt, or "" when there
+// isn't one. Callers fall back to their canned replies on "", so a
+// rate-limited guild still gets an answer, just not a generated one.
 func generateResponse(ctx context.Context, prompt string) string {
+	response, _ := generate(ctx, prompt)
+	return response
+}
+
+// generate is generateResponse for callers that keep the answer, which must
+// tell a failed or skipped request from a real reply.
+func generate(ctx context.Context, prompt string) (string, error) {
 	if !config.Config.Gemini.Enabled {
-		return ""
+		return "", errNoReply
 	}
-	if defaultClient == nil {
-		log.Warn("generateResponse called before Gemini client was initialized")
-		return ""
+	if textProvider == nil {
+		log.Warn("generateResponse called before the text provider was initialized")
+		return "", errNoReply
 	}
 
-	// Start span for Gemini AI generation
+	cached, ok := cachedOrAllowed(ctx, prompt)
+	if cached != "" {
+		return cached, nil
+	}
+	if !ok {
+		log.WithField("guild", guildFromContext(ctx)).Debug("AI rate limit reached, using fallback reply")
+		return "", ErrRateLimited
+	}
+
+	// Start span for AI generation
 	span := sentry.StartSpan(ctx, "gemini.generate")
 	span.Description = "Generate AI response"
-	span.SetTag("model", config.Config.Gemini.Model)
+	span.SetTag("provider", textProvider.Name())
+	span.SetTag("model", textProvider.Model())
 	defer span.Finish()
 
-	parts := []*genai.Part{
-		{Text: prompt},
+	if timeout := config.Config.Gemini.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
-	content := []*genai.Content{{Parts: parts}}
 
-	resp, err := defaultClient.Models.GenerateContent(ctx, config.Config.Gemini.Model, content, nil)
+	response, err := textProvider.Generate(ctx, prompt)
 	if err != nil {
 		log.Errorf("failed to generate content: %v", err)
 		sentry.CaptureException(err)
 		span.Status = sentry.SpanStatusInternalError
-		return ""
+		return "", err
 	}
-
-	var sb strings.Builder
-	for _, cand := range resp.Candidates {
-		if cand.Content != nil {
-			for _, part := range cand.Content.Parts {
-				if part.Text != "" {
-					sb.WriteString(part.Text)
-				}
-			}
-		}
+	if strings.TrimSpace(response) == "" {
+		span.Status = sentry.SpanStatusInternalError
+		return "", errNoReply
 	}
-	response := sb.String()
 	span.Status = sentry.SpanStatusOK
-	return response
+	replies.put(prompt, response, config.Config.Gemini.CacheTTL, time.Now())
+	return response, nil
+}
+
+// textConfig applies GEMINI_TEMPERATURE and GEMINI_MAX_OUTPUT_TOKENS to text
+// requests. Unset, the model's own defaults are used.
+func textConfig() *genai.GenerateContentConfig {
+	cfg := config.Config.Gemini
+	if cfg.Temperature == nil && cfg.MaxOutputTokens == 0 {
+		return nil
+	}
+	return &genai.GenerateContentConfig{
+		Temperature:     cfg.Temperature,
+		MaxOutputTokens: cfg.MaxOutputTokens,
+	}
 }
 
-// buildPrompt prepends the shared beatbot personality to task-specific instructions.
-func buildPrompt(instructions string) string {
+// buildPrompt prepends the shared beatbot personality, plus any style notes
+// the guild set in /settings or /tone, to task-specific instructions.
+func buildPrompt(ctx context.Context, instructions string) string {
+	if style := guildStyle(ctx); style != "" {
+		return PersonalityPrompt + "\n\nThis server's admins asked for this style — lean into it, but the rules above still win:\n" + style + "\n\n" + instructions
+	}
 	return PersonalityPrompt + "\n\n" + instructions
 }
 
@@ -148,7 +207,7 @@ func GenerateRaw(ctx context.Context, prompt string) string {
 	if !config.Config.Gemini.Enabled {
 		return ""
 	}
-	return generateResponse(ctx, buildPrompt(prompt))
+	return generateResponse(ctx, buildPrompt(ctx, prompt))
 }
 
 func GenerateResponse(ctx context.Context, prompt string) string {
@@ -156,45 +215,27 @@ func GenerateResponse(ctx context.Context, prompt string) string {
 		return ""
 	}
 
-	instructions := buildPrompt(prompt)
+	instructions := buildPrompt(ctx, prompt)
 
-	return generateResponse(ctx, instructions)
+	return generateResponse(ctx, inLanguage(ctx, instructions))
 }
 
-func GenerateHelpfulResponse(ctx context.Context, prompt string) string {
+func GenerateHelpfulResponse(ctx context.Context, commands, prompt string) string {
 	if !config.Config.Gemini.Enabled {
 		return ""
 	}
 
-	instructions := buildPrompt(fmt.Sprintf(`You are responding to a user's help request. Be helpful and informative but keep your signature personality.
+	instructions := buildPrompt(ctx, fmt.Sprintf(`You are responding to a user's help request. Be helpful and informative but keep your signature personality.
 All responses are rendered in Discord markdown, so use proper formatting.
 Keep it concise—a few sentences max.
 
 Here are the available commands:
 
-**Music Control:**
-/play (or /queue) - Queue a song. Takes a search query, YouTube URL/playlist, or Spotify URL. Note: YouTube links with ?list= will queue the whole playlist
-/skip - Skip the current song and play the next in queue
-/pause (or /stop) - Pause the current song
-/resume - Resume playback
-/volume - Set playback volume (0-100)
-
-**Queue Management:**
-/view - View the current queue
-/remove - Remove a song from the queue by index number
-/clear - Clear the entire queue
-
-**Radio / AI Mode:**
-/radio - Toggle AI radio mode (auto-queues songs based on listening history)
-
-**Info:**
-/now-playing - Show the current song
-/history - Show recently played songs
-/leaderboard - Show most played songs
+%s
 
-User's request: %s`, prompt))
+User's request: %s`, commands, prompt))
 
-	return generateResponse(ctx, instructions)
+	return generateResponse(ctx, inLanguage(ctx, instructions))
 }
 
 // GenerateAgeRestrictedResponse returns a snarky DJ response for when a video
@@ -206,7 +247,7 @@ func GenerateAgeRestrictedResponse(ctx context.Context, directRequest bool) stri
 	if directRequest {
 		fallback = "That video is age-restricted and can't be played — YouTube won't let me near it. Try a different link?"
 	} else {
-		fallback = `YouTube blocked this from loading because it's "restricted" — sorry! Try something else.`
+		fallback = "That video is age-restricted, so YouTube blocked it from loading — sorry! Try something else."
 	}
 
 	if !config.Config.Gemini.Enabled {
@@ -217,10 +258,10 @@ func GenerateAgeRestrictedResponse(ctx context.Context, directRequest bool) stri
 	if directRequest {
 		instructions = `A user requested a specific YouTube video by URL and YouTube won't play it because it's age-restricted. Tell them in one sentence.`
 	} else {
-		instructions = `A user requested a song and YouTube blocked it because it's "restricted". Tell them in one sentence and suggest they try something else.`
+		instructions = `A user requested a song and YouTube blocked it because it's age-restricted. Tell them in one sentence, saying it's age-restricted, and suggest they try something else.`
 	}
 
-	response := generateResponse(ctx, buildPrompt(instructions))
+	response := generateResponse(ctx, inLanguage(ctx, buildPrompt(ctx, instructions)))
 	if response == "" {
 		return fallback
 	}
@@ -249,7 +290,7 @@ func GenerateSongRecommendation(ctx context.Context, recentSongs []string) strin
 	// Build the song list
 	songList := strings.Join(recentSongs, "\n")
 
-	instructions := buildPrompt(fmt.Sprintf(`You are a music recommendation AI. Based on the following recently played songs, suggest ONE similar song that would fit well in this listening session.
+	instructions := buildPrompt(ctx, fmt.Sprintf(`You are a music recommendation AI. Based on the following recently played songs, suggest ONE similar song that would fit well in this listening session.
 
 Recent songs played:
 %s
@@ -300,7 +341,7 @@ Now generate your recommendation:`, songList))
 // with recent song history as secondary context. Returns an empty string if Gemini is
 // disabled or on error.
 func GenerateThemedRecommendation(ctx context.Context, theme string, recentSongs []string) string {
-	if !config.Config.Gemini.Enabled || defaultClient == nil {
+	if !config.Config.Gemini.Enabled || textProvider == nil {
 		return ""
 	}
 
@@ -315,7 +356,7 @@ func GenerateThemedRecommendation(ctx context.Context, theme string, recentSongs
 		songList = strings.Join(recentSongs, "\n")
 	}
 
-	instructions := buildPrompt(fmt.Sprintf(`The listener wants: %s
+	instructions := buildPrompt(ctx, fmt.Sprintf(`The listener wants: %s
 
 Their recent songs for context:
 %s
@@ -349,7 +390,7 @@ Suggest ONE song that matches the requested vibe. Return ONLY a YouTube search q
 // free-text song/artist/vibe request, used by the /request command. Returns nil
 // if Gemini is disabled or on error.
 func GenerateRequestQueries(ctx context.Context, suggestion string, recentSongs []string) []string {
-	if !config.Config.Gemini.Enabled || defaultClient == nil {
+	if !config.Config.Gemini.Enabled || textProvider == nil {
 		return nil
 	}
 
@@ -364,7 +405,7 @@ func GenerateRequestQueries(ctx context.Context, suggestion string, recentSongs
 		songList = strings.Join(recentSongs, "\n")
 	}
 
-	instructions := buildPrompt(fmt.Sprintf(`The listener is requesting: %s
+	instructions := buildPrompt(ctx, fmt.Sprintf(`The listener is requesting: %s
 
 Their recent songs for context:
 %s
@@ -377,10 +418,82 @@ Suggest 3-5 songs that match the request. Return each as a YouTube search query
 		return nil
 	}
 
+	queries := queryLines(response)
+
+	span.Status = sentry.SpanStatusOK
+	span.SetData("num_queries", fmt.Sprintf("%d", len(queries)))
+
+	log.WithFields(log.Fields{
+		"module":     "gemini",
+		"suggestion": suggestion,
+		"queries":    queries,
+	}).Debug("Generated request search queries")
+
+	return queries
+}
+
+// GenerateSongRecommendations suggests 3-5 songs that would follow the one
+// playing and the recent history, as YouTube search queries, for /recommend.
+// currentSong may be empty. Returns nil if AI is disabled or on error.
+func GenerateSongRecommendations(ctx context.Context, currentSong string, recentSongs []string) []string {
+	if !config.Config.Gemini.Enabled || textProvider == nil {
+		return nil
+	}
+	if currentSong == "" && len(recentSongs) == 0 {
+		return nil
+	}
+
+	span := sentry.StartSpan(ctx, "gemini.song_recommendations")
+	span.Description = "Generate song recommendation queries"
+	span.SetTag("num_songs", fmt.Sprintf("%d", len(recentSongs)))
+	defer span.Finish()
+
+	playing := "Nothing is playing right now."
+	if currentSong != "" {
+		playing = "Playing now: " + currentSong
+	}
+	songList := "No other songs played yet."
+	if len(recentSongs) > 0 {
+		songList = strings.Join(recentSongs, "\n")
+	}
+
+	instructions := buildPrompt(ctx, fmt.Sprintf(`You're recommending what this server should listen to next.
+
+%s
+
+Recently played:
+%s
+
+Suggest 3-5 specific, real songs that would fit this session: similar genre, mood, era or energy, with some variety between them. Favor songs and artists that aren't in the list above, and don't suggest anything already in it.
+Return each as a YouTube search query (e.g. "Artist - Song Title"), one per line, with nothing else. Don't number the lines.`, playing, songList))
+
+	response := generateResponse(ctx, instructions)
+	if response == "" {
+		span.Status = sentry.SpanStatusInternalError
+		return nil
+	}
+
+	queries := queryLines(response)
+	span.Status = sentry.SpanStatusOK
+	span.SetData("num_queries", fmt.Sprintf("%d", len(queries)))
+
+	log.WithFields(log.Fields{
+		"module":  "gemini",
+		"current": currentSong,
+		"queries": queries,
+	}).Debug("Generated song recommendation queries")
+
+	return queries
+}
+
+// queryLines reads one search query per line of a model's reply, dropping
+// quotes, numbering and blank lines.
+func queryLines(response string) []string {
 	lines := strings.Split(response, "\n")
 	queries := make([]string, 0, len(lines))
 	for _, line := range lines {
 		query := strings.TrimSpace(line)
+		query = strings.TrimPrefix(query, "- ")
 		query = strings.Trim(query, "\"'`")
 		query = leadingNumberRe.ReplaceAllString(query, "")
 		if query == "" {
@@ -388,17 +501,120 @@ Suggest 3-5 songs that match the request. Return each as a YouTube search query
 		}
 		queries = append(queries, query)
 	}
+	return queries
+}
+
+// NeedsTranslation reports whether a title contains letters outside the Latin
+// script (Japanese, Korean, Cyrillic, etc.) and is worth sending to
+// TranslateTitle. Latin-script titles are left alone even if they aren't English.
+func NeedsTranslation(title string) bool {
+	for _, r := range title {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslateTitle returns a short English transliteration/translation of a
+// non-English song title, e.g. "真夜中のドア" -> "Mayonaka no Door / Stay With Me".
+// Returns "" with a nil error when the model decides the title doesn't need
+// translating, so only that answer is worth remembering; an error means the
+// model couldn't be asked (AI disabled, ErrRateLimited, failed or timed out).
+func TranslateTitle(ctx context.Context, title string) (string, error) {
+	if !config.Config.Gemini.Enabled || textProvider == nil {
+		return "", errNoReply
+	}
+
+	// Start span for Gemini title translation
+	span := sentry.StartSpan(ctx, "gemini.translate_title")
+	span.Description = "Translate song title"
+	span.SetTag("model", config.Config.Gemini.Model)
+	defer span.Finish()
+
+	// No personality here — this is displayed verbatim next to the title.
+	prompt := fmt.Sprintf(`Translate this song title into English for display next to the original.
+If the title uses a non-Latin script, give the romanized reading followed by " / " and the English meaning (e.g. "Mayonaka no Door / Stay With Me").
+Keep artist names romanized, not translated. Drop video suffixes like "Official Video" or "MV".
+If the title is already English, reply with exactly NONE.
+Reply with the translation only, no quotes, no explanation.
 
+Title: %s`, title)
+
+	response, err := generate(ctx, prompt)
+	translation := strings.Trim(strings.TrimSpace(response), "\"'`")
+	if err != nil || translation == "" {
+		span.Status = sentry.SpanStatusInternalError
+		if err == nil {
+			err = errNoReply
+		}
+		return "", err
+	}
 	span.Status = sentry.SpanStatusOK
-	span.SetData("num_queries", fmt.Sprintf("%d", len(queries)))
+
+	if strings.EqualFold(translation, "NONE") {
+		return "", nil
+	}
+	// Guard against the model rambling — this has to fit in an embed line.
+	if len([]rune(translation)) > 120 {
+		translation = string([]rune(translation)[:117]) + "..."
+	}
 
 	log.WithFields(log.Fields{
-		"module":     "gemini",
-		"suggestion": suggestion,
-		"queries":    queries,
-	}).Debug("Generated request search queries")
+		"module":      "gemini",
+		"title":       title,
+		"translation": translation,
+	}).Debug("Translated song title")
 
-	return queries
+	return translation, nil
+}
+
+// CleanSongQuery turns a raw video title into a plain "artist song" search
+// query for lyrics lookups, e.g. "【MV】YOASOBI「アイドル」" -> "YOASOBI Idol".
+// Returns "" with a nil error when the model can't tell what song it is; an
+// error means it couldn't be asked (AI disabled, ErrRateLimited, failed or
+// timed out), so the title is worth trying again later.
+func CleanSongQuery(ctx context.Context, title, channelName string) (string, error) {
+	if !config.Config.Gemini.Enabled || textProvider == nil {
+		return "", errNoReply
+	}
+
+	span := sentry.StartSpan(ctx, "gemini.clean_song_query")
+	span.Description = "Clean song title for lyrics search"
+	span.SetTag("model", config.Config.Gemini.Model)
+	defer span.Finish()
+
+	prompt := fmt.Sprintf(`Identify the song in this video title and reply with a lyrics search query: the main artist, a space, then the song title.
+Drop video suffixes ("Official Video", "MV", "Lyrics"), featured artists, and anything in brackets that isn't part of the song name.
+Use the song's official title; keep it in its original language unless it is usually written in English.
+If you can't tell what song it is, reply with exactly NONE.
+Reply with the query only, no quotes, no explanation.
+
+Title: %s
+Channel: %s`, title, channelLabel(channelName))
+
+	response, err := generate(ctx, prompt)
+	query := strings.Trim(strings.TrimSpace(response), "\"'`")
+	if err != nil || query == "" {
+		span.Status = sentry.SpanStatusInternalError
+		if err == nil {
+			err = errNoReply
+		}
+		return "", err
+	}
+	span.Status = sentry.SpanStatusOK
+
+	if strings.EqualFold(query, "NONE") || len([]rune(query)) > 150 {
+		return "", nil
+	}
+
+	log.WithFields(log.Fields{
+		"module": "gemini",
+		"title":  title,
+		"query":  query,
+	}).Debug("Cleaned song title for lyrics search")
+
+	return query, nil
 }
 
 // SongContext carries optional Deezer-derived metadata (and radio-mode state)
@@ -456,7 +672,7 @@ func GenerateNowPlayingCommentary(ctx context.Context, currentSong, channelName
 		artistLine = fmt.Sprintf("Artist: %s (verified)", songCtx.ArtistName)
 	}
 
-	instructions := buildPrompt(fmt.Sprintf(`Current song playing: **%s**
+	instructions := buildPrompt(ctx, fmt.Sprintf(`Current song playing: **%s**
 %s
 
 %s
@@ -520,7 +736,7 @@ Now write your commentary:`, currentSong, artistLine, historyStr, radioStr))
 		instructions += "\n\nUse this context naturally — mention genre, tempo, or album era IF it adds to the commentary. Don't force it."
 	}
 
-	response := generateResponse(ctx, instructions)
+	response := generateResponse(ctx, inLanguage(ctx, instructions))
 	if response == "" {
 		span.Status = sentry.SpanStatusInternalError
 		return ""
@@ -620,7 +836,7 @@ func cleanTitle(title string) string {
 // tags for TTS delivery — for the moment described by sc. The result must
 // avoid markdown; it's fed directly into GenerateTTSAudio via BuildTTSPrompt.
 func GenerateDJScript(ctx context.Context, sc DJScriptContext) string {
-	if !config.Config.Gemini.Enabled || defaultClient == nil {
+	if !config.Config.Gemini.Enabled || textProvider == nil {
 		return ""
 	}
 
@@ -705,7 +921,7 @@ Your task: Introduce the first song of the session. Immediately establish domina
 Now write your intro:`, nextSong, nextLabel, roastStr)
 	case AnnouncementQueueEmpty:
 		taskPrompt = fmt.Sprintf(`%s
-Your task: The queue just ran out. Blame someone. Hype up /radio mode as the way to keep the music going — it auto-queues songs based on what's been playing. Also mention /play or /queue for adding specific songs, but lead with radio.
+Your task: The queue just ran out. Blame someone. Hype up /radio mode as the way to keep the music going — it auto-queues songs based on what's been playing. Also mention /play for adding specific songs, but lead with radio.
 - If there are listeners, pick one and destroy them for being useless and not queuing anything. Make it personal.
 
 Now write your announcement:`, roastStr)
@@ -0,0 +1,137 @@
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"beatbot/config"
+)
+
+// maxCachedReplies caps the reply cache; expired entries are swept first,
+// then the oldest go.
+const maxCachedReplies = 500
+
+type guildKey struct{}
+
+// WithGuild returns a context whose text requests count against guildID's
+// rate limit. Requests without a guild, like those from DMs, share one.
+func WithGuild(ctx context.Context, guildID string) context.Context {
+	return context.WithValue(ctx, guildKey{}, guildID)
+}
+
+func guildFromContext(ctx context.Context) string {
+	guildID, _ := ctx.Value(guildKey{}).(string)
+	return guildID
+}
+
+// guildBucket is one guild's allowance of text requests: a token bucket
+// that refills perMinute/60 tokens a second, up to perMinute.
+type guildBucket struct {
+	tokens   float64
+	lastFill time.Time
+}
+
+// guildLimiter hands out text requests per guild, so one busy server can't
+// run up the AI bill or starve the others.
+type guildLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*guildBucket
+}
+
+var limiter = &guildLimiter{buckets: make(map[string]*guildBucket)}
+
+// allow takes a request from guildID's bucket of perMinute, reporting
+// whether there was one left. perMinute 0 means no limit.
+func (l *guildLimiter) allow(guildID string, perMinute int, now time.Time) bool {
+	if perMinute <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	capacity := float64(perMinute)
+	b, ok := l.buckets[guildID]
+	if !ok {
+		b = &guildBucket{tokens: capacity, lastFill: now}
+		l.buckets[guildID] = b
+	} else if elapsed := now.Sub(b.lastFill); elapsed > 0 {
+		b.tokens = min(capacity, b.tokens+elapsed.Seconds()*capacity/60)
+		b.lastFill = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type cachedReply struct {
+	text    string
+	expires time.Time
+}
+
+// replyCache reuses replies to identical prompts for a short while. Prompts
+// carry the guild's style and language, so guilds only share a reply when
+// they'd have been asked exactly the same thing.
+type replyCache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]cachedReply
+}
+
+var replies = &replyCache{entries: make(map[[sha256.Size]byte]cachedReply)}
+
+func (c *replyCache) get(prompt string, now time.Time) (string, bool) {
+	key := sha256.Sum256([]byte(prompt))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if now.After(entry.expires) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.text, true
+}
+
+func (c *replyCache) put(prompt, text string, ttl time.Duration, now time.Time) {
+	if ttl <= 0 || text == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCachedReplies {
+		var oldest [sha256.Size]byte
+		var oldestExpiry time.Time
+		for key, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, key)
+				continue
+			}
+			if oldestExpiry.IsZero() || entry.expires.Before(oldestExpiry) {
+				oldest, oldestExpiry = key, entry.expires
+			}
+		}
+		if len(c.entries) >= maxCachedReplies {
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[sha256.Sum256([]byte(prompt))] = cachedReply{text: text, expires: now.Add(ttl)}
+}
+
+// cachedOrAllowed returns a cached reply to prompt if there is one. If not,
+// ok reports whether the guild in ctx may make a new request.
+func cachedOrAllowed(ctx context.Context, prompt string) (cached string, ok bool) {
+	now := time.Now()
+	if config.Config.Gemini.CacheTTL > 0 {
+		if text, hit := replies.get(prompt, now); hit {
+			return text, true
+		}
+	}
+	return "", limiter.allow(guildFromContext(ctx), config.Config.Gemini.RateLimit, now)
+}
@@ -0,0 +1,156 @@
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"beatbot/config"
+)
+
+// countingText answers every prompt with the number of calls so far.
+type countingText struct{ calls int }
+
+func (c *countingText) Generate(context.Context, string) (string, error) {
+	c.calls++
+	return fmt.Sprintf("reply %d", c.calls), nil
+}
+func (c *countingText) Name() string  { return "test" }
+func (c *countingText) Model() string { return "test" }
+
+func withTextProvider(t *testing.T, p TextProvider) {
+	t.Helper()
+	saved, savedLimiter, savedReplies := textProvider, limiter, replies
+	textProvider = p
+	limiter = &guildLimiter{buckets: make(map[string]*guildBucket)}
+	replies = &replyCache{entries: make(map[[sha256.Size]byte]cachedReply)}
+	t.Cleanup(func() { textProvider, limiter, replies = saved, savedLimiter, savedReplies })
+}
+
+func TestGuildLimiter(t *testing.T) {
+	l := &guildLimiter{buckets: make(map[string]*guildBucket)}
+	now := time.Now()
+	for i := range 3 {
+		if !l.allow("g1", 3, now) {
+			t.Fatalf("request %d refused within the limit", i+1)
+		}
+	}
+	if l.allow("g1", 3, now) {
+		t.Error("fourth request in a minute allowed")
+	}
+	if !l.allow("g2", 3, now) {
+		t.Error("another guild was limited by g1's requests")
+	}
+	if !l.allow("g1", 0, now) {
+		t.Error("a limit of 0 should mean no limit")
+	}
+}
+
+func TestGuildLimiterRefill(t *testing.T) {
+	l := &guildLimiter{buckets: make(map[string]*guildBucket)}
+	now := time.Now()
+	for range 60 {
+		l.allow("g1", 60, now)
+	}
+	if l.allow("g1", 60, now) {
+		t.Fatal("request allowed from an empty bucket")
+	}
+
+	// 60 a minute refills one token a second.
+	if l.allow("g1", 60, now.Add(500*time.Millisecond)) {
+		t.Error("request allowed half a second after emptying the bucket")
+	}
+	if !l.allow("g1", 60, now.Add(time.Second)) {
+		t.Error("no token a second after emptying the bucket")
+	}
+	if l.allow("g1", 60, now.Add(time.Second)) {
+		t.Error("a second's refill allowed two requests")
+	}
+	now = now.Add(time.Second)
+	if !l.allow("g1", 60, now.Add(5*time.Second)) || !l.allow("g1", 60, now.Add(5*time.Second)) {
+		t.Error("five seconds' refill didn't allow two requests")
+	}
+
+	// A long idle refills to perMinute and no further.
+	now = now.Add(time.Hour)
+	allowed := 0
+	for l.allow("g1", 60, now) {
+		allowed++
+	}
+	if allowed != 60 {
+		t.Errorf("allowed %d requests after an hour idle; want the cap of 60", allowed)
+	}
+}
+
+func TestReplyCache(t *testing.T) {
+	c := &replyCache{entries: make(map[[sha256.Size]byte]cachedReply)}
+	now := time.Now()
+	c.put("help", "here's help", time.Minute, now)
+	if got, ok := c.get("help", now.Add(30*time.Second)); !ok || got != "here's help" {
+		t.Errorf("get = %q, %v; want the cached reply", got, ok)
+	}
+	if _, ok := c.get("help", now.Add(2*time.Minute)); ok {
+		t.Error("expired reply returned")
+	}
+	if _, ok := c.get("other", now); ok {
+		t.Error("reply returned for a prompt never cached")
+	}
+
+	for i := range maxCachedReplies + 10 {
+		c.put(fmt.Sprint(i), "x", time.Minute+time.Duration(i)*time.Second, now)
+	}
+	if len(c.entries) > maxCachedReplies {
+		t.Errorf("cache holds %d replies; want at most %d", len(c.entries), maxCachedReplies)
+	}
+	if _, ok := c.get("0", now); ok {
+		t.Error("oldest reply kept over the cap")
+	}
+}
+
+func TestGenerateResponseCachesAndLimits(t *testing.T) {
+	withConfig(t, &config.ConfigStruct{Gemini: config.GeminiConfig{Enabled: true, RateLimit: 2, CacheTTL: time.Minute}})
+	provider := &countingText{}
+	withTextProvider(t, provider)
+	ctx := WithGuild(context.Background(), "g1")
+
+	if got := generateResponse(ctx, "help"); got != "reply 1" {
+		t.Fatalf("first reply = %q", got)
+	}
+	if got := generateResponse(ctx, "help"); got != "reply 1" || provider.calls != 1 {
+		t.Errorf("repeat prompt = %q after %d calls; want the cached reply", got, provider.calls)
+	}
+	if got := generateResponse(ctx, "skip"); got != "reply 2" {
+		t.Errorf("second prompt = %q", got)
+	}
+	if got := generateResponse(ctx, "pause"); got != "" {
+		t.Errorf("over the limit = %q; want \"\" so callers fall back", got)
+	}
+	if got := generateResponse(ctx, "help"); got != "reply 1" {
+		t.Errorf("cached prompt over the limit = %q; want the cached reply", got)
+	}
+	if got := generateResponse(WithGuild(context.Background(), "g2"), "pause"); got != "reply 3" {
+		t.Errorf("other guild = %q; want a fresh reply", got)
+	}
+}
+
+func TestLimitedRequestsAreNotAnswers(t *testing.T) {
+	withConfig(t, &config.ConfigStruct{Gemini: config.GeminiConfig{Enabled: true, RateLimit: 1}})
+	withTextProvider(t, fixedText{reply: "NONE"})
+	ctx := WithGuild(context.Background(), "g1")
+
+	if got, err := TranslateTitle(ctx, "Hello"); err != nil || got != "" {
+		t.Fatalf("NONE = %q, %v; want \"\", nil", got, err)
+	}
+	if _, err := generate(ctx, "help"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("generate over the limit = %v; want ErrRateLimited", err)
+	}
+	if got, err := TranslateTitle(ctx, "真夜中のドア"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("TranslateTitle over the limit = %q, %v; want ErrRateLimited so it isn't cached", got, err)
+	}
+	if got, err := CleanSongQuery(ctx, "【MV】YOASOBI「アイドル」", "Ayase / YOASOBI"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("CleanSongQuery over the limit = %q, %v; want ErrRateLimited", got, err)
+	}
+}
@@ -0,0 +1,274 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"beatbot/config"
+)
+
+// TextProvider writes the bot's text replies. Everything in this package
+// builds its prompts (personality, guild style, language) the same way
+// whichever provider LLM_PROVIDER picks; only the last step differs.
+type TextProvider interface {
+	// Generate returns the model's reply to prompt.
+	Generate(ctx context.Context, prompt string) (string, error)
+
+	// Name returns the provider identifier, as set in LLM_PROVIDER.
+	Name() string
+
+	// Model returns the model the provider asks for.
+	Model() string
+}
+
+// textProvider is the active provider, set by Init.
+var textProvider TextProvider
+
+// Default models for providers that don't have their own setting.
+const (
+	defaultOpenAIModel    = "gpt-4o-mini"
+	defaultAnthropicModel = "claude-3-5-haiku-latest"
+	defaultOllamaModel    = "llama3.2"
+
+	// anthropicMaxTokens is used when GEMINI_MAX_OUTPUT_TOKENS isn't set,
+	// since Anthropic requires a cap on every request.
+	anthropicMaxTokens = 1024
+)
+
+// newTextProvider builds the provider cfg.Provider names.
+func newTextProvider(cfg config.LLMConfig) (TextProvider, error) {
+	model := func(fallback string) string {
+		if cfg.Model != "" {
+			return cfg.Model
+		}
+		return fallback
+	}
+	baseURL := func(fallback string) string {
+		if cfg.BaseURL != "" {
+			return cfg.BaseURL
+		}
+		return fallback
+	}
+
+	switch cfg.Provider {
+	case config.LLMGemini, "":
+		if defaultClient == nil {
+			return nil, fmt.Errorf("GEMINI_API_KEY is required when LLM_PROVIDER=gemini")
+		}
+		return &geminiText{model: model(config.Config.Gemini.Model)}, nil
+	case config.LLMOpenAI:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("LLM_API_KEY is required when LLM_PROVIDER=openai")
+		}
+		return newOpenAIText(config.LLMOpenAI, baseURL("https://api.openai.com/v1"), cfg.APIKey, model(defaultOpenAIModel)), nil
+	case config.LLMOllama:
+		// Ollama serves the OpenAI chat API and needs no key.
+		return newOpenAIText(config.LLMOllama, baseURL("http://localhost:11434/v1"), cfg.APIKey, model(defaultOllamaModel)), nil
+	case config.LLMAnthropic:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("LLM_API_KEY is required when LLM_PROVIDER=anthropic")
+		}
+		return &anthropicText{
+			baseURL: baseURL("https://api.anthropic.com"),
+			apiKey:  cfg.APIKey,
+			model:   model(defaultAnthropicModel),
+			client:  &http.Client{Timeout: 35 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM_PROVIDER: %q (valid: gemini, openai, anthropic, ollama)", cfg.Provider)
+	}
+}
+
+// geminiText generates with the shared Gemini client.
+type geminiText struct {
+	model string
+}
+
+func (g *geminiText) Generate(ctx context.Context, prompt string) (string, error) {
+	content := []*genai.Content{{Parts: []*genai.Part{{Text: prompt}}}}
+	resp, err := defaultClient.Models.GenerateContent(ctx, g.model, content, textConfig())
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, cand := range resp.Candidates {
+		if cand.Content != nil {
+			for _, part := range cand.Content.Parts {
+				if part.Text != "" {
+					sb.WriteString(part.Text)
+				}
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+func (g *geminiText) Name() string  { return config.LLMGemini }
+func (g *geminiText) Model() string { return g.model }
+
+// openAIText speaks the OpenAI chat completions API, which Ollama serves
+// too.
+type openAIText struct {
+	name    string
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func newOpenAIText(name, baseURL, apiKey, model string) *openAIText {
+	return &openAIText{
+		name:    name,
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		model:   model,
+		// Local models can be slow to load on the first request.
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature *float32      `json:"temperature,omitempty"`
+	MaxTokens   int32         `json:"max_tokens,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (o *openAIText) Generate(ctx context.Context, prompt string) (string, error) {
+	body := openAIRequest{
+		Model:       o.model,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		Temperature: config.Config.Gemini.Temperature,
+		MaxTokens:   config.Config.Gemini.MaxOutputTokens,
+	}
+	headers := map[string]string{}
+	if o.apiKey != "" {
+		headers["Authorization"] = "Bearer " + o.apiKey
+	}
+
+	var resp openAIResponse
+	if err := postJSON(ctx, o.client, o.baseURL+"/chat/completions", headers, body, &resp); err != nil {
+		return "", fmt.Errorf("%s: %w", o.name, err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", nil
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+func (o *openAIText) Name() string  { return o.name }
+func (o *openAIText) Model() string { return o.model }
+
+// anthropicText speaks the Anthropic Messages API.
+type anthropicText struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+type anthropicRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int32         `json:"max_tokens"`
+	Temperature *float32      `json:"temperature,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func (a *anthropicText) Generate(ctx context.Context, prompt string) (string, error) {
+	maxTokens := config.Config.Gemini.MaxOutputTokens
+	if maxTokens == 0 {
+		maxTokens = anthropicMaxTokens
+	}
+	temperature := config.Config.Gemini.Temperature
+	if temperature != nil && *temperature > 1 {
+		// Anthropic's range is 0–1 rather than Gemini's 0–2.
+		one := float32(1)
+		temperature = &one
+	}
+	body := anthropicRequest{
+		Model:       a.model,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	}
+	headers := map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": "2023-06-01",
+	}
+
+	var resp anthropicResponse
+	if err := postJSON(ctx, a.client, a.baseURL+"/v1/messages", headers, body, &resp); err != nil {
+		return "", fmt.Errorf("anthropic: %w", err)
+	}
+	var sb strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+	return sb.String(), nil
+}
+
+func (a *anthropicText) Name() string  { return config.LLMAnthropic }
+func (a *anthropicText) Model() string { return a.model }
+
+// postJSON sends body to url and decodes a successful reply into out.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
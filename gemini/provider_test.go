@@ -0,0 +1,150 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"beatbot/config"
+)
+
+func withConfig(t *testing.T, cfg *config.ConfigStruct) {
+	t.Helper()
+	saved := config.Config
+	config.Config = cfg
+	t.Cleanup(func() { config.Config = saved })
+}
+
+func TestOpenAIText(t *testing.T) {
+	temperature := float32(0.4)
+	withConfig(t, &config.ConfigStruct{Gemini: config.GeminiConfig{Temperature: &temperature, MaxOutputTokens: 200}})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("request to %s with auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if req.Model != "gpt-test" || req.Messages[0].Content != "hi" || req.MaxTokens != 200 || *req.Temperature != 0.4 {
+			t.Errorf("request = %+v", req)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hello"}}]}`))
+	}))
+	defer srv.Close()
+
+	p, err := newTextProvider(config.LLMConfig{Provider: config.LLMOpenAI, APIKey: "key", Model: "gpt-test", BaseURL: srv.URL + "/v1"})
+	if err != nil {
+		t.Fatalf("newTextProvider: %v", err)
+	}
+	if got, err := p.Generate(context.Background(), "hi"); err != nil || got != "hello" {
+		t.Errorf("Generate = %q, %v", got, err)
+	}
+}
+
+func TestAnthropicText(t *testing.T) {
+	temperature := float32(1.5)
+	withConfig(t, &config.ConfigStruct{Gemini: config.GeminiConfig{Temperature: &temperature}})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "key" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("request to %s with headers %v", r.URL.Path, r.Header)
+		}
+		var req anthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if req.MaxTokens != anthropicMaxTokens || *req.Temperature != 1 || req.Model != defaultAnthropicModel {
+			t.Errorf("request = %+v", req)
+		}
+		w.Write([]byte(`{"content":[{"type":"text","text":"hel"},{"type":"text","text":"lo"}]}`))
+	}))
+	defer srv.Close()
+
+	p, err := newTextProvider(config.LLMConfig{Provider: config.LLMAnthropic, APIKey: "key", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("newTextProvider: %v", err)
+	}
+	if got, err := p.Generate(context.Background(), "hi"); err != nil || got != "hello" {
+		t.Errorf("Generate = %q, %v", got, err)
+	}
+}
+
+func TestTextProviderErrors(t *testing.T) {
+	withConfig(t, &config.ConfigStruct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	p, err := newTextProvider(config.LLMConfig{Provider: config.LLMOllama, BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("newTextProvider ollama without a key: %v", err)
+	}
+	if p.Model() != defaultOllamaModel {
+		t.Errorf("ollama model = %q", p.Model())
+	}
+	if _, err := p.Generate(context.Background(), "hi"); err == nil {
+		t.Error("Generate succeeded on a 404")
+	}
+
+	for _, cfg := range []config.LLMConfig{
+		{Provider: config.LLMOpenAI},
+		{Provider: config.LLMAnthropic},
+		{Provider: "llamafile"},
+	} {
+		if _, err := newTextProvider(cfg); err == nil {
+			t.Errorf("newTextProvider(%+v) succeeded", cfg)
+		}
+	}
+}
+
+func TestQueryLines(t *testing.T) {
+	got := queryLines("1. Daft Punk - Digital Love\n\n- \"Justice - D.A.N.C.E.\"\n  Air - Sexy Boy  \n")
+	want := []string{"Daft Punk - Digital Love", "Justice - D.A.N.C.E.", "Air - Sexy Boy"}
+	if len(got) != len(want) {
+		t.Fatalf("queryLines = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("queryLines[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// fixedText answers every prompt with reply, or fails with err.
+type fixedText struct {
+	reply string
+	err   error
+}
+
+func (f fixedText) Generate(context.Context, string) (string, error) { return f.reply, f.err }
+func (f fixedText) Name() string                                     { return "test" }
+func (f fixedText) Model() string                                    { return "test" }
+
+func TestTranslateTitleErrors(t *testing.T) {
+	withConfig(t, &config.ConfigStruct{Gemini: config.GeminiConfig{Enabled: true}})
+	ctx := context.Background()
+
+	withTextProvider(t, fixedText{reply: "Mayonaka no Door / Stay With Me"})
+	if got, err := TranslateTitle(ctx, "真夜中のドア"); err != nil || got != "Mayonaka no Door / Stay With Me" {
+		t.Errorf("TranslateTitle = %q, %v", got, err)
+	}
+
+	withTextProvider(t, fixedText{reply: "NONE"})
+	if got, err := TranslateTitle(ctx, "Hello"); err != nil || got != "" {
+		t.Errorf("NONE = %q, %v; want \"\", nil", got, err)
+	}
+
+	for _, p := range []TextProvider{fixedText{err: errors.New("timeout")}, fixedText{reply: "  "}} {
+		withTextProvider(t, p)
+		if _, err := TranslateTitle(ctx, "真夜中のドア"); err == nil {
+			t.Errorf("TranslateTitle with %+v returned no error, so a failure would be cached", p)
+		}
+	}
+}
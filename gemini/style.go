@@ -0,0 +1,97 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"beatbot/config"
+)
+
+// MaxGuildStyleLength caps a guild's custom style notes so they can't crowd
+// out the personality and task instructions.
+const MaxGuildStyleLength = 300
+
+type guildStyleKey struct{}
+
+// WithGuildStyle returns a context carrying a guild's custom style notes,
+// which every personality-bearing prompt generated with it will include.
+// An empty style leaves ctx unchanged.
+func WithGuildStyle(ctx context.Context, style string) context.Context {
+	style = strings.TrimSpace(style)
+	if style == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, guildStyleKey{}, style)
+}
+
+func guildStyle(ctx context.Context) string {
+	style, _ := ctx.Value(guildStyleKey{}).(string)
+	return style
+}
+
+// styleOverrides are phrases that try to replace the personality's rules
+// rather than flavour them. Matched case-insensitively.
+var styleOverrides = []string{
+	"ignore previous", "ignore the above", "ignore all", "ignore your",
+	"ignore the rules", "disregard", "forget your", "system prompt",
+	"new instructions", "you are no longer", "jailbreak",
+}
+
+// CheckGuildStyle returns why style can't be used as a guild's style notes,
+// or "" when it's fine. Notes go into every prompt, so they're kept short,
+// can't ping anyone or carry links, and can't tell the model to drop its
+// rules.
+func CheckGuildStyle(style string) string {
+	style = strings.TrimSpace(style)
+	if len([]rune(style)) > MaxGuildStyleLength {
+		return fmt.Sprintf("Keep the AI style under %d characters.", MaxGuildStyleLength)
+	}
+	lower := strings.ToLower(style)
+	if strings.Contains(lower, "@everyone") || strings.Contains(lower, "@here") || strings.Contains(lower, "<@") {
+		return "The AI style can't mention people or roles."
+	}
+	if strings.Contains(lower, "http://") || strings.Contains(lower, "https://") || strings.Contains(lower, "discord.gg/") {
+		return "The AI style can't contain links."
+	}
+	for _, phrase := range styleOverrides {
+		if strings.Contains(lower, phrase) {
+			return "The AI style can flavour the DJ's voice, but not override its rules."
+		}
+	}
+	return ""
+}
+
+// PreviewGuildStyle writes a sample now-playing line in style, so admins
+// can hear a new style before it shows up in chat. Returns "" if AI is
+// disabled or on error.
+func PreviewGuildStyle(ctx context.Context, style string) string {
+	if !config.Config.Gemini.Enabled || textProvider == nil {
+		return ""
+	}
+	ctx = WithGuildStyle(ctx, style)
+	instructions := buildPrompt(ctx, `Write a one or two sentence sample of how you'd announce the next song in this server's chat, so the admins can hear your style. Make up a well-known song to announce. Reply with only the announcement.`)
+	return strings.TrimSpace(generateResponse(ctx, inLanguage(ctx, instructions)))
+}
+
+type languageKey struct{}
+
+// WithLanguage returns a context asking replies written for users to be in
+// language, an English name such as "Spanish". An empty language (English)
+// leaves ctx unchanged.
+func WithLanguage(ctx context.Context, language string) context.Context {
+	if language == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// inLanguage adds the reply-language instruction to a prompt whose output
+// goes straight to users. Prompts that produce search queries skip it.
+func inLanguage(ctx context.Context, instructions string) string {
+	language, _ := ctx.Value(languageKey{}).(string)
+	if language == "" {
+		return instructions
+	}
+	return instructions + "\n\nWrite your reply in " + language + ". Keep song titles, artist names and /commands as they are."
+}
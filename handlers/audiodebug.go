@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"beatbot/controller"
+	"beatbot/discord"
+)
+
+// pipelineReport renders /audiodebug. It's meant to be pasted into a bug
+// report, so it's plain key: value lines in a code block.
+func pipelineReport(status controller.PipelineStatus) string {
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🩺 **Audio pipeline**\n```\n")
+
+	voice := "not connected"
+	if status.VoiceChannelID != "" {
+		voice = status.VoiceChannelID + ", ready: " + yesNo(status.VoiceReady)
+	}
+	sb.WriteString(fmt.Sprintf("voice:          %s\n", voice))
+	if status.OpusCapacity > 0 {
+		sb.WriteString(fmt.Sprintf("send buffer:    %d/%d frames\n", status.OpusBuffered, status.OpusCapacity))
+	}
+
+	state := "stopped"
+	switch {
+	case status.Playing && status.Paused:
+		state = "paused"
+	case status.Playing:
+		state = "playing"
+	}
+	sb.WriteString(fmt.Sprintf("player:         %s, volume %d%%\n", state, status.Volume))
+	if status.CurrentTitle != "" {
+		sb.WriteString(fmt.Sprintf("current:        %s (%s / %s)\n", status.CurrentTitle,
+			discord.FormatDuration(status.Position), discord.FormatDuration(status.Duration)))
+	}
+	sb.WriteString(fmt.Sprintf("encoder:        %s\n", status.Encoder))
+	sb.WriteString(fmt.Sprintf("player events:  %d unhandled\n", status.PlayerEvents))
+
+	ffmpeg := "idle"
+	if status.Loader.FFmpegPID != 0 {
+		ffmpeg = fmt.Sprintf("pid %d", status.Loader.FFmpegPID)
+	}
+	sb.WriteString(fmt.Sprintf("ffmpeg:         %s\n", ffmpeg))
+	sb.WriteString(fmt.Sprintf("loads:          %d running or waiting, %d events unhandled\n",
+		status.Loader.PendingLoads, status.Loader.Notifications))
+
+	sb.WriteString(fmt.Sprintf("queue:          %d", status.QueueLength))
+	if status.QueueLength > 0 {
+		var parts []string
+		for _, s := range []controller.ItemState{controller.ItemPending, controller.ItemResolved, controller.ItemLoaded, controller.ItemPlaying} {
+			if n := status.ItemStates[s]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", n, s))
+			}
+		}
+		sb.WriteString(" (" + strings.Join(parts, ", ") + ")")
+	}
+	sb.WriteString("\n```")
+	return sb.String()
+}
+
+// handleAudioDebug shows the guild's pipeline state to server managers.
+// Restricted to Manage Server via DefaultMemberPermissions in Commands.
+func (manager *Manager) handleAudioDebug(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: pipelineReport(player.PipelineStatus()),
+			Flags:   64,
+		},
+	}
+}
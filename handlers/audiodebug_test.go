@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/audio"
+	"beatbot/controller"
+)
+
+func TestPipelineReport(t *testing.T) {
+	report := pipelineReport(controller.PipelineStatus{
+		VoiceChannelID: "vc1",
+		VoiceReady:     true,
+		OpusBuffered:   3,
+		OpusCapacity:   10,
+		Playing:        true,
+		Volume:         80,
+		CurrentTitle:   "Song",
+		Position:       30 * time.Second,
+		Duration:       3 * time.Minute,
+		Encoder:        audio.EncoderSettings,
+		Loader:         audio.LoaderStats{FFmpegPID: 4242, PendingLoads: 1},
+		QueueLength:    3,
+		ItemStates:     map[controller.ItemState]int{controller.ItemPending: 2, controller.ItemLoaded: 1},
+	})
+	for _, want := range []string{
+		"voice:          vc1, ready: yes",
+		"send buffer:    3/10 frames",
+		"player:         playing, volume 80%",
+		"current:        Song (0:30 / 3:00)",
+		"ffmpeg:         pid 4242",
+		"loads:          1 running or waiting",
+		"queue:          3 (2 pending, 1 loaded)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+
+	idle := pipelineReport(controller.PipelineStatus{})
+	if !strings.Contains(idle, "voice:          not connected") || !strings.Contains(idle, "ffmpeg:         idle") ||
+		strings.Contains(idle, "send buffer") {
+		t.Errorf("idle report:\n%s", idle)
+	}
+}
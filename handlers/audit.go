@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/database"
+)
+
+// auditFieldLimit caps how much of a command's options or error is kept in
+// the audit log.
+const auditFieldLimit = 300
+
+// auditOptions renders how a command was invoked, like
+// "add name:Road trip", from its options.
+func auditOptions(options []InteractionOption) string {
+	parts := make([]string, 0, len(options))
+	for _, opt := range options {
+		if len(opt.Options) > 0 || opt.Type == optionTypeSubcommand || opt.Type == optionTypeSubcommandGroup {
+			parts = append(parts, strings.TrimSpace(opt.Name+" "+auditOptions(opt.Options)))
+			continue
+		}
+		parts = append(parts, opt.Name+":"+opt.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+func truncateAudit(s string) string {
+	if len(s) <= auditFieldLimit {
+		return s
+	}
+	return strings.ToValidUTF8(s[:auditFieldLimit], "") + "…"
+}
+
+// recordCommand adds a guild command to the audit log, as ok until
+// auditOutcome says otherwise. It's a no-op without a database.
+func (manager *Manager) recordCommand(interaction *Interaction, command, options string) {
+	db := manager.Controller.GetDB()
+	if db == nil || interaction.GuildID == "" {
+		return
+	}
+	id, err := db.RecordCommand(database.AuditEntry{
+		GuildID:  interaction.GuildID,
+		UserID:   interaction.Member.User.ID,
+		Username: interaction.Member.User.Username,
+		Command:  command,
+		Options:  truncateAudit(options),
+	})
+	if err != nil {
+		log.Warnf("Failed to record /%s in the audit log: %v", command, err)
+		return
+	}
+	interaction.auditID = id
+}
+
+// auditOutcome records that the interaction's command failed or was
+// refused, and why.
+func (manager *Manager) auditOutcome(interaction *Interaction, outcome, detail string) {
+	db := manager.Controller.GetDB()
+	if db == nil || interaction.auditID == 0 {
+		return
+	}
+	if err := db.SetAuditOutcome(interaction.auditID, outcome, truncateAudit(detail)); err != nil {
+		log.Warnf("Failed to update audit outcome: %v", err)
+	}
+}
+
+// handleAuditLog shows server managers who ran which commands recently,
+// optionally just one member's or one command's.
+func (manager *Manager) handleAuditLog(interaction *Interaction) Response {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	var filter database.AuditFilter
+	limit := 15
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "user":
+			filter.UserID = opt.Value
+		case "command":
+			filter.Command = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(opt.Value)), "/")
+		case "limit":
+			if v, err := strconv.Atoi(opt.Value); err == nil && v >= 1 && v <= 25 {
+				limit = v
+			}
+		}
+	}
+
+	entries, err := db.GetAuditLog(interaction.GuildID, filter, limit)
+	if err != nil {
+		log.Errorf("Error fetching audit log: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: "Failed to fetch the audit log.", Flags: 64}}
+	}
+	if len(entries) == 0 {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "audit.empty"), Flags: 64}}
+	}
+	return Response{Type: 4, Data: ResponseData{Content: auditLogMessage(interaction, entries), Flags: 64, AllowedMentions: noPings}}
+}
+
+// auditLogMessage renders /auditlog's entries, newest first.
+func auditLogMessage(interaction *Interaction, entries []database.AuditEntry) string {
+	var sb strings.Builder
+	sb.WriteString(tr(interaction, "audit.title") + "\n\n")
+	for i, e := range entries {
+		invocation := "/" + e.Command
+		if e.Options != "" {
+			invocation += " " + e.Options
+		}
+		line := tr(interaction, "audit.line", i+1, strings.ReplaceAll(invocation, "`", "'"), e.Username, formatRelativeTime(interaction, e.CreatedAt)) + "\n"
+		switch e.Outcome {
+		case database.AuditRefused:
+			line += tr(interaction, "audit.refused", e.Detail) + "\n"
+		case database.AuditError:
+			line += tr(interaction, "audit.failed", e.Detail) + "\n"
+		}
+		if sb.Len()+len(line) > 1900 {
+			sb.WriteString("...")
+			break
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/database"
+)
+
+func TestAuditOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []InteractionOption
+		want    string
+	}{
+		{"none", nil, ""},
+		{"plain", []InteractionOption{{Name: "query", Type: optionTypeString, Value: "daft punk"}, {Name: "position", Type: 4, Value: "1"}}, "query:daft punk position:1"},
+		{"subcommand", []InteractionOption{{Name: "add", Type: optionTypeSubcommand, Options: []InteractionOption{{Name: "name", Type: optionTypeString, Value: "Road trip"}}}}, "add name:Road trip"},
+		{"bare subcommand", []InteractionOption{{Name: "list", Type: optionTypeSubcommand}}, "list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := auditOptions(tt.options); got != tt.want {
+				t.Errorf("auditOptions() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditLogMessage(t *testing.T) {
+	now := time.Now()
+	msg := auditLogMessage(&Interaction{}, []database.AuditEntry{
+		{Command: "clear", Options: "confirmed", Username: "alice", Outcome: database.AuditOK, CreatedAt: now},
+		{Command: "skip", Username: "bob", Outcome: database.AuditRefused, Detail: "Only server managers can use /skip here.", CreatedAt: now},
+		{Command: "play", Options: "query:`x`", Username: "carol", Outcome: database.AuditError, Detail: "No results", CreatedAt: now},
+	})
+	for _, want := range []string{"`/clear confirmed` by **alice**", "⛔ refused: Only server managers", "`/play query:'x'` by **carol**", "⚠️ failed: No results"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+
+	many := make([]database.AuditEntry, 25)
+	for i := range many {
+		many[i] = database.AuditEntry{Command: "play", Options: strings.Repeat("x", auditFieldLimit), Username: "alice", CreatedAt: now}
+	}
+	if msg := auditLogMessage(&Interaction{}, many); len(msg) > 2000 || !strings.HasSuffix(msg, "...") {
+		t.Errorf("long log not cut short: %d bytes", len(msg))
+	}
+}
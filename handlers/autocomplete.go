@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"beatbot/controller"
+)
+
+// autocompleteLimit is the most choices Discord shows for one option.
+const autocompleteLimit = 25
+
+// handleAutocomplete answers the suggestions Discord asks for while a member
+// is typing an autocomplete option (interaction type 4).
+func (manager *Manager) handleAutocomplete(interaction *Interaction) Response {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	switch interaction.Data.Name {
+	case "remove":
+		player := manager.Controller.GetPlayer(interaction.GuildID)
+		choices = queueChoices(player, player.GetQueueSnapshot(), focusedValue(interaction))
+	case "soundboard":
+		choices = soundChoices(manager.Controller.GetDB(), interaction.GuildID, focusedValue(interaction))
+	}
+	if choices == nil {
+		choices = []*discordgo.ApplicationCommandOptionChoice{}
+	}
+	return Response{Type: 8, Data: ResponseData{Choices: &choices}}
+}
+
+// focusedValue returns what the member has typed so far into the option
+// being autocompleted.
+func focusedValue(interaction *Interaction) string {
+	for _, opt := range commandOptions(interaction) {
+		if opt.Focused {
+			return strings.TrimSpace(opt.Value)
+		}
+	}
+	return ""
+}
+
+// queueChoices lists queue entries as "3. Artist – Title" with the position
+// as the value. A typed number matches positions starting with it; anything
+// else matches the label.
+func queueChoices(player *controller.GuildPlayer, items []*controller.GuildQueueItem, typed string) []*discordgo.ApplicationCommandOptionChoice {
+	_, numeric := strconv.Atoi(typed)
+	typedLower := strings.ToLower(typed)
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for i, item := range items {
+		position := strconv.Itoa(i + 1)
+		label := fmt.Sprintf("%s. %s", position, queueEntryName(player, item))
+		switch {
+		case typed == "":
+		case numeric == nil && strings.HasPrefix(position, typed):
+		case numeric != nil && strings.Contains(strings.ToLower(label), typedLower):
+		default:
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  truncateRunes(label, selectTextLimit),
+			Value: position,
+		})
+		if len(choices) == autocompleteLimit {
+			break
+		}
+	}
+	return choices
+}
+
+// queueEntryName is "Artist – Title" when the artist is known and not
+// already part of the title.
+func queueEntryName(player *controller.GuildPlayer, item *controller.GuildQueueItem) string {
+	title := player.DisplayTitle(item)
+	artist := player.GetDeezerArtist(item)
+	if artist == "" {
+		artist = strings.TrimSuffix(item.Video.ChannelName, " - Topic")
+	}
+	if artist == "" || strings.Contains(strings.ToLower(title), strings.ToLower(artist)) {
+		return title
+	}
+	return artist + " – " + title
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"beatbot/controller"
+	"beatbot/youtube"
+)
+
+func TestQueueChoices(t *testing.T) {
+	player := &controller.GuildPlayer{}
+	items := []*controller.GuildQueueItem{
+		{Video: youtube.VideoResponse{Title: "Never Gonna Give You Up", ChannelName: "Rick Astley"}},
+		{Video: youtube.VideoResponse{Title: "Daft Punk - One More Time", ChannelName: "Daft Punk"}},
+		{Video: youtube.VideoResponse{Title: "Blue Monday", ChannelName: "New Order - Topic"}},
+	}
+	for i := 0; i < 9; i++ {
+		items = append(items, &controller.GuildQueueItem{Video: youtube.VideoResponse{Title: "Filler " + strconv.Itoa(i)}})
+	}
+
+	all := queueChoices(player, items, "")
+	if len(all) != len(items) {
+		t.Fatalf("got %d choices, want %d", len(all), len(items))
+	}
+	want := []string{"1. Rick Astley – Never Gonna Give You Up", "2. Daft Punk - One More Time", "3. New Order – Blue Monday"}
+	for i, name := range want {
+		if all[i].Name != name || all[i].Value != strconv.Itoa(i+1) {
+			t.Errorf("choice %d = %q/%v, want %q/%d", i, all[i].Name, all[i].Value, name, i+1)
+		}
+	}
+
+	if got := queueChoices(player, items, "1"); len(got) != 4 { // 1, 10, 11, 12
+		t.Errorf("typing 1 matched %d entries, want 4", len(got))
+	}
+	if got := queueChoices(player, items, "monday"); len(got) != 1 || got[0].Value != "3" {
+		t.Errorf("typing monday = %+v, want entry 3", got)
+	}
+}
+
+func TestFocusedValue(t *testing.T) {
+	var payload InteractionData
+	if err := json.Unmarshal([]byte(`{"name":"remove","options":[{"name":"song_number","type":3,"value":" 4","focused":true}]}`), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if got := focusedValue(&Interaction{Data: payload}); got != "4" {
+		t.Errorf("focusedValue = %q, want 4", got)
+	}
+}
+
+func TestAutocompleteResponseSendsEmptyChoices(t *testing.T) {
+	data, err := json.Marshal(Response{Type: 8, Data: ResponseData{Choices: &[]*discordgo.ApplicationCommandOptionChoice{}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"choices":[]`) {
+		t.Errorf("autocomplete response = %s, want an empty choices list", data)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+)
+
+// barCharLimit caps each progress bar character, which has room for an
+// emoji with a skin tone or variation selector but not a word.
+const barCharLimit = 4
+
+// handleBranding lets admins restyle now-playing cards: the color while
+// playing, the progress bar characters and the button emoji.
+func (manager *Manager) handleBranding(interaction *Interaction) Response {
+	guildID := interaction.GuildID
+	branding := discord.BrandingFor(guildID)
+
+	opts := make(map[string]string)
+	for _, opt := range commandOptions(interaction) {
+		opts[opt.Name] = strings.TrimSpace(opt.Value)
+	}
+
+	var msg string
+	switch subcommandName(interaction) {
+	case "color":
+		color, ok := parseHexColor(opts["hex"])
+		if !ok {
+			return brandingError(tr(interaction, "branding.bad_color"))
+		}
+		if color == 0 {
+			// Discord draws 0 as no color, and to Branding it's the default.
+			color = 0x000001
+		}
+		branding.Color = color
+		msg = tr(interaction, "branding.color_set", color)
+	case "bar":
+		filled, empty := opts["filled"], opts["empty"]
+		if !validBarChar(filled) || !validBarChar(empty) {
+			return brandingError(tr(interaction, "branding.bad_bar", barCharLimit))
+		}
+		branding.BarFilled, branding.BarEmpty = filled, empty
+		msg = tr(interaction, "branding.bar_set", strings.Repeat(filled, 5)+strings.Repeat(empty, 10))
+	case "emoji":
+		button, emoji := opts["button"], opts["emoji"]
+		if _, ok := discord.BrandingButtons[button]; !ok {
+			return brandingError(tr(interaction, "branding.bad_button"))
+		}
+		if !discord.IsCustomEmoji(emoji) && !looksLikeEmoji(emoji) {
+			return brandingError(tr(interaction, "branding.bad_emoji"))
+		}
+		emojis := make(map[string]string, len(branding.Emojis)+1)
+		for k, v := range branding.Emojis {
+			emojis[k] = v
+		}
+		emojis[button] = emoji
+		branding.Emojis = emojis
+		msg = tr(interaction, "branding.emoji_set", button, emoji)
+	case "reset":
+		branding = discord.Branding{}
+		msg = tr(interaction, "branding.reset")
+	default:
+		return Response{Type: 4, Data: ResponseData{
+			Embeds: []*discordgo.MessageEmbed{brandingPreview(interaction, branding)},
+			Flags:  64,
+		}}
+	}
+
+	discord.SetBranding(guildID, branding)
+	if db := manager.Controller.GetDB(); db != nil {
+		value := ""
+		if !branding.IsZero() {
+			value = branding.Encode()
+		}
+		if err := db.SetGuildSetting(guildID, "branding", value); err != nil {
+			log.Errorf("Failed to save branding: %v", err)
+		}
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg + " " + tr(interaction, "branding.next_card"),
+		},
+	}
+}
+
+func brandingError(msg string) Response {
+	return Response{Type: 4, Data: ResponseData{Content: msg, Flags: 64}}
+}
+
+// brandingPreview is a sample card in guildID's branding, listing what has
+// been changed from the defaults.
+func brandingPreview(interaction *Interaction, branding discord.Branding) *discordgo.MessageEmbed {
+	embed := discord.BuildNowPlayingEmbed(&discord.NowPlayingMetadata{
+		URL:             "https://www.youtube.com",
+		Title:           tr(interaction, "branding.sample_title"),
+		Duration:        213 * time.Second,
+		CurrentPosition: 71 * time.Second,
+		IsPlaying:       true,
+		Volume:          100,
+		GuildID:         interaction.GuildID,
+	})
+	embed.Author = &discordgo.MessageEmbedAuthor{Name: tr(interaction, "branding.preview")}
+
+	if branding.IsZero() {
+		embed.Description = tr(interaction, "branding.default_look")
+		return embed
+	}
+	var sb strings.Builder
+	if branding.Color != 0 {
+		sb.WriteString(tr(interaction, "branding.color", branding.Color) + "\n")
+	}
+	if branding.BarFilled != "" || branding.BarEmpty != "" {
+		sb.WriteString(tr(interaction, "branding.bar", branding.BarFilled, branding.BarEmpty) + "\n")
+	}
+	buttons := make([]string, 0, len(branding.Emojis))
+	for button := range branding.Emojis {
+		buttons = append(buttons, button)
+	}
+	sort.Strings(buttons)
+	for _, button := range buttons {
+		sb.WriteString(fmt.Sprintf("**%s:** %s\n", button, branding.Emojis[button]))
+	}
+	embed.Description = sb.String()
+	return embed
+}
+
+// parseHexColor reads an RGB color written as RRGGBB, with or without a
+// leading #.
+func parseHexColor(s string) (int, bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return 0, false
+	}
+	color, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int(color), true
+}
+
+func validBarChar(s string) bool {
+	if s == "" || utf8.RuneCountInString(s) > barCharLimit || discord.IsCustomEmoji(s) {
+		return false
+	}
+	return !strings.ContainsFunc(s, unicode.IsSpace)
+}
+
+// looksLikeEmoji is a loose check for a unicode emoji: a few code points,
+// none of them ordinary letters, digits or punctuation. Discord refuses the
+// whole message when a button's emoji isn't one, so text gets turned away
+// here.
+func looksLikeEmoji(s string) bool {
+	if s == "" || utf8.RuneCountInString(s) > 8 {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x2000 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import "testing"
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"#FF5500", 0xFF5500, true},
+		{"1db954", 0x1DB954, true},
+		{"#FFF", 0, false},
+		{"#GG0000", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseHexColor(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseHexColor(%q) = (%#x, %v), want (%#x, %v)", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestValidBarChar(t *testing.T) {
+	for _, s := range []string{"=", "█", "🟩", "👍🏽"} {
+		if !validBarChar(s) {
+			t.Errorf("validBarChar(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"", " ", "a b", "toolong", "<:bar:123>"} {
+		if validBarChar(s) {
+			t.Errorf("validBarChar(%q) = true", s)
+		}
+	}
+}
+
+func TestLooksLikeEmoji(t *testing.T) {
+	for _, s := range []string{"🎵", "⏭️", "👩‍🎤"} {
+		if !looksLikeEmoji(s) {
+			t.Errorf("looksLikeEmoji(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"", "skip", ":skip:", "é"} {
+		if looksLikeEmoji(s) {
+			t.Errorf("looksLikeEmoji(%q) = true", s)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+	"beatbot/discord"
+)
+
+// refuseBusy answers a request from channelID while the bot is playing for
+// people in another voice channel of the server. Rather than quietly
+// queueing into a channel the requester can't hear, it says where the bot
+// is, with a button server managers can use to move it. Call it once
+// ShouldJoinVoice has said no; it returns true when the caller should stop.
+func (manager *Manager) refuseBusy(interaction *Interaction, player *controller.GuildPlayer, channelID string) bool {
+	current := player.GetVoiceChannelID()
+	if current == nil || *current == channelID {
+		return false
+	}
+	discord.SendFollowup(&discord.FollowUpRequest{
+		Token:      interaction.Token,
+		AppID:      manager.AppID,
+		Content:    tr(interaction, "busy.elsewhere", *current),
+		Components: discord.MoveHereButton(interaction.GuildID),
+	})
+	return true
+}
+
+// handleSteal moves the bot to the clicker's voice channel from a busy
+// notice. Server managers can always move it; anyone else only once nobody
+// is listening where it is.
+func (manager *Manager) handleSteal(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
+	if err != nil || voiceState == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.join_voice_first"), Flags: 64}}
+	}
+	target := voiceState.ChannelID
+
+	if current := player.GetVoiceChannelID(); current != nil && *current == target {
+		return Response{Type: 7, Data: ResponseData{
+			Content:    tr(interaction, "busy.already_there", target),
+			Components: discord.DisabledButton(tr(interaction, "busy.moved_button")),
+		}}
+	} else if current != nil && !player.ShouldJoinVoice(target) && !canManageGuild(interaction.Member) {
+		return Response{Type: 4, Data: ResponseData{
+			Content: tr(interaction, "busy.still_listening", *current),
+			Flags:   64,
+		}}
+	}
+
+	// Joining takes longer than Discord waits for an answer, so acknowledge
+	// the click now and edit the notice once the move is done.
+	token := interaction.Token
+	go func() {
+		update := &discord.FollowUpRequest{
+			Token:      token,
+			AppID:      manager.AppID,
+			Content:    tr(interaction, "busy.moved", target),
+			Components: discord.DisabledButton(tr(interaction, "busy.moved_button")),
+		}
+		if err := player.JoinChannel(target); err != nil {
+			log.Errorf("Failed to move to voice channel %s: %v", target, err)
+			update.Content = tr(interaction, "common.join_voice_error", err.Error())
+			update.Components = discord.MoveHereButton(interaction.GuildID)
+		}
+		discord.UpdateMessage(update)
+	}()
+	return Response{Type: 6}
+}
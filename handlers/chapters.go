@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+	"beatbot/resolver"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+// chapterChoices is how many chapters the /chapters menu lists. Discord
+// allows 25 options and one is "Queue all".
+const chapterChoices = 24
+
+// chapterAll is the menu value that queues every chapter.
+const chapterAll = "all"
+
+// chapterLabelPrefix starts a chapter's source label, "chapter of **Album**".
+// "Queue all" names the collection by the title that follows it.
+const chapterLabelPrefix = "chapter of "
+
+// chapterMenu builds the /chapters menu in the invoker's language: "Queue
+// all" first, then one option per chapter with its time range.
+func chapterMenu(interaction *Interaction, tracks []youtube.VideoResponse, chapters []youtube.Chapter) []discordgo.SelectMenuOption {
+	options := []discordgo.SelectMenuOption{{
+		Label:       tr(interaction, "chapters.queue_all", len(chapters)),
+		Value:       chapterAll,
+		Description: tr(interaction, "chapters.queue_all_description"),
+	}}
+	for i, chapter := range chapters {
+		if i == chapterChoices {
+			break
+		}
+		details := fmt.Sprintf("%s–%s", discord.FormatDuration(chapter.Start), discord.FormatDuration(chapter.End))
+		if tracks[i].Duration > 0 {
+			details += " · " + discord.FormatDuration(tracks[i].Duration)
+		}
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       truncateRunes(fmt.Sprintf("%d. %s", i+1, chapter.Title), selectTextLimit),
+			Value:       strconv.Itoa(i),
+			Description: details,
+		})
+	}
+	return options
+}
+
+func (manager *Manager) handleChapters(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
+	go manager.onChapters(ctx, transaction, interaction)
+
+	// Deferred ephemeral: only the listener picking sees the menu.
+	return Response{
+		Type: 5,
+		Data: ResponseData{
+			Flags: 64,
+		},
+	}
+}
+
+// onChapters looks up the chapters of the linked video, or of the song
+// playing, and offers them as a select menu.
+func (manager *Manager) onChapters(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onChapters: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	var link string
+	for _, opt := range commandOptions(interaction) {
+		if opt.Name == "video" {
+			link = strings.TrimSpace(opt.Value)
+		}
+	}
+
+	var videoID string
+	if link != "" {
+		videoID = youtube.ParseYoutubeUrl(link)
+		if videoID == "" {
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "chapters.not_youtube_link"), true)
+			return
+		}
+	} else {
+		current := manager.Controller.GetPlayer(interaction.GuildID).GetCurrentItem()
+		switch {
+		case current == nil:
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "chapters.nothing_playing"), true)
+			return
+		case !current.Video.IsYouTube():
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "chapters.youtube_only"), true)
+			return
+		}
+		videoID = current.Video.VideoID
+	}
+
+	// Fetch fresh details: the playing song may itself be a clip.
+	video, err := youtube.GetVideoByID(ctx, videoID)
+	if err != nil {
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "chapters.lookup_failed", err.Error()), true)
+		return
+	}
+	if video.Live {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "chapters.live", video.Title), true)
+		return
+	}
+
+	chapters, err := youtube.GetChapters(ctx, videoID)
+	if err != nil {
+		log.Warnf("Error getting chapters for %s: %v", videoID, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "chapters.read_failed", err.Error()), true)
+		return
+	}
+	if len(chapters) == 0 {
+		manager.SendFollowup(ctx, interaction, "",
+			tr(interaction, "chapters.none", video.Title), true)
+		return
+	}
+
+	tracks := youtube.ChapterTracks(video, chapters)
+	manager.searches.put(interaction.GuildID, interaction.Member.User.ID, pendingSearch{
+		videos:      tracks,
+		sourceLabel: chapterLabelPrefix + "**" + video.Title + "**",
+		expires:     time.Now().Add(searchTTL),
+	})
+
+	content := tr(interaction, "chapters.menu", video.Title, len(chapters))
+	if len(chapters) > chapterChoices {
+		content += "\n" + tr(interaction, "chapters.truncated", chapterChoices)
+	}
+	discord.SendFollowup(&discord.FollowUpRequest{
+		Token:      interaction.Token,
+		AppID:      manager.AppID,
+		UserID:     interaction.Member.User.ID,
+		Content:    content,
+		Components: discord.SelectMenu(interaction.GuildID, "chapter_pick", tr(interaction, "chapters.placeholder"), chapterMenu(interaction, tracks, chapters)),
+	})
+}
+
+// handleChapterPick queues the chapter picked from a /chapters menu, or
+// every chapter as its own song.
+func (manager *Manager) handleChapterPick(interaction *Interaction) Response {
+	search, ok := manager.searches.take(interaction.GuildID, interaction.Member.User.ID)
+	if !ok {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "chapters.expired"),
+				Flags:   64,
+			},
+		}
+	}
+
+	var value string
+	if len(interaction.Data.Values) == 1 {
+		value = interaction.Data.Values[0]
+	}
+	all := value == chapterAll
+	index, err := strconv.Atoi(value)
+	if !all && (err != nil || index < 0 || index >= len(search.videos)) {
+		return Response{Type: 7, Data: ResponseData{
+			Content:    tr(interaction, "chapters.bad_pick"),
+			Components: discord.DisabledButton(tr(interaction, "search.bad_pick_button")),
+		}}
+	}
+
+	go func() {
+		ctx, transaction := sentryhelper.StartCommandTransaction(
+			context.Background(),
+			"chapter_pick",
+			interaction.GuildID,
+			interaction.Member.User.ID,
+		)
+		defer func() {
+			if err := recover(); err != nil {
+				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleChapterPick: %v", err))
+				transaction.Status = sentry.SpanStatusInternalError
+			}
+			transaction.Finish()
+		}()
+
+		player := manager.Controller.GetPlayer(interaction.GuildID)
+		if !manager.joinRequesterVoice(ctx, interaction, player) {
+			return
+		}
+
+		videos := manager.filterBlocked(interaction.GuildID, search.videos)
+		if len(videos) == 0 {
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "chapters.blocked"), true)
+			return
+		}
+		if all {
+			manager.queueCollection(ctx, interaction, player, &resolver.Resolution{
+				Tracks:     videos,
+				Collection: &resolver.Collection{Kind: "chapters", Name: strings.Trim(strings.TrimPrefix(search.sourceLabel, chapterLabelPrefix), "*")},
+			})
+			return
+		}
+		log.WithFields(log.Fields{
+			"module":   "handlers",
+			"guild_id": interaction.GuildID,
+			"video_id": search.videos[index].VideoID,
+			"chapter":  index + 1,
+			"user_id":  interaction.Member.User.ID,
+		}).Info("Queued chapter")
+		manager.queueVideo(ctx, interaction, player, search.videos[index], nil, search.sourceLabel)
+	}()
+
+	picked := tr(interaction, "chapters.all")
+	if !all {
+		picked = "**" + search.videos[index].Title + "**"
+	}
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "chapters.picked", picked),
+		Components: discord.DisabledButton(tr(interaction, "search.picked_button")),
+	}}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"beatbot/youtube"
+)
+
+func TestChapterMenu(t *testing.T) {
+	video := youtube.VideoResponse{Title: "Full Album", VideoID: "abc123", Duration: time.Hour}
+	var chapters []youtube.Chapter
+	for i := range 30 {
+		chapters = append(chapters, youtube.Chapter{
+			Title: fmt.Sprintf("Song %d", i+1),
+			Start: time.Duration(i) * 2 * time.Minute,
+			End:   time.Duration(i+1) * 2 * time.Minute,
+		})
+	}
+	options := chapterMenu(&Interaction{}, youtube.ChapterTracks(video, chapters), chapters)
+
+	if len(options) != chapterChoices+1 {
+		t.Fatalf("got %d options, want %d", len(options), chapterChoices+1)
+	}
+	if options[0].Value != chapterAll || options[0].Label != "Queue all 30 chapters" {
+		t.Errorf("first option = %+v", options[0])
+	}
+	if got := options[2]; got.Value != "1" || got.Label != "2. Song 2" || got.Description != "2:00–4:00 · 2:00" {
+		t.Errorf("second chapter option = %+v", got)
+	}
+}
+
+func TestQueueKeySeparatesClips(t *testing.T) {
+	full := youtube.VideoResponse{VideoID: "abc123", Duration: 10 * time.Minute}
+	first, second := full, full
+	first.Clip(0, 4*time.Minute)
+	second.Clip(4*time.Minute, 0)
+
+	keys := map[string]bool{queueKey(full): true, queueKey(first): true, queueKey(second): true}
+	if len(keys) != 3 {
+		t.Errorf("queue keys collide: %v", keys)
+	}
+	if queueKey(full) != "abc123" {
+		t.Errorf("queueKey(full) = %q", queueKey(full))
+	}
+}
@@ -27,7 +27,7 @@ func (manager *Manager) handleCharts(ctx context.Context, transaction *sentry.Sp
 	}()
 
 	if !config.Config.Deezer.Enabled {
-		manager.SendError(interaction, "Charts aren't enabled on this bot right now.", true)
+		manager.SendError(interaction, tr(interaction, "charts.disabled"), true)
 		return
 	}
 
@@ -50,13 +50,13 @@ func (manager *Manager) handleCharts(ctx context.Context, transaction *sentry.Sp
 	if err != nil {
 		log.Errorf("Error fetching Deezer charts: %v", err)
 		sentryhelper.CaptureException(ctx, err)
-		manager.SendError(interaction, "Couldn't fetch the charts right now: "+err.Error(), true)
+		manager.SendError(interaction, tr(interaction, "charts.fetch_failed", err.Error()), true)
 		return
 	}
 
 	tracks := charts.Tracks.Data
 	if len(tracks) == 0 {
-		manager.SendRequest(interaction, "No chart data available right now. Try again later.", true)
+		manager.SendRequest(interaction, tr(interaction, "charts.empty"), true)
 		return
 	}
 	if len(tracks) > 10 {
@@ -68,11 +68,11 @@ func (manager *Manager) handleCharts(ctx context.Context, transaction *sentry.Sp
 		if err != nil {
 			log.Errorf("Error getting voice state: %v", err)
 			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Error getting voice state: "+err.Error(), true)
+			manager.SendError(interaction, tr(interaction, "common.voice_state_error", err.Error()), true)
 			return
 		}
 		if voiceState == nil {
-			manager.SendRequest(interaction, "Join a voice channel first! 🎤", true)
+			manager.SendRequest(interaction, tr(interaction, "common.join_voice_first"), true)
 			return
 		}
 
@@ -80,9 +80,11 @@ func (manager *Manager) handleCharts(ctx context.Context, transaction *sentry.Sp
 		if player.ShouldJoinVoice(voiceState.ChannelID) {
 			if err := player.JoinVoiceChannel(interaction.Member.User.ID); err != nil {
 				sentryhelper.CaptureException(ctx, err)
-				manager.SendError(interaction, "Error joining voice channel: "+err.Error(), true)
+				manager.SendError(interaction, tr(interaction, "common.join_voice_error", err.Error()), true)
 				return
 			}
+		} else if manager.refuseBusy(interaction, player, voiceState.ChannelID) {
+			return
 		}
 
 		var queued []string
@@ -101,12 +103,12 @@ func (manager *Manager) handleCharts(ctx context.Context, transaction *sentry.Sp
 		}
 
 		if len(queued) == 0 {
-			manager.SendRequest(interaction, "Couldn't find any of the trending tracks on YouTube. Try again later.", true)
+			manager.SendRequest(interaction, tr(interaction, "charts.none_found"), true)
 			return
 		}
 
 		var sb strings.Builder
-		sb.WriteString(fmt.Sprintf("📊 **Queued %d trending tracks:**\n", len(queued)))
+		sb.WriteString(tr(interaction, "charts.queued", len(queued)) + "\n")
 		for i, title := range queued {
 			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, title))
 		}
@@ -116,11 +118,11 @@ func (manager *Manager) handleCharts(ctx context.Context, transaction *sentry.Sp
 	}
 
 	var sb strings.Builder
-	sb.WriteString("📊 **What's Trending**\n\n")
+	sb.WriteString(tr(interaction, "charts.title") + "\n\n")
 	for i, t := range tracks {
 		sb.WriteString(fmt.Sprintf("`%2d.` **%s** — %s\n", i+1, t.TitleShort, t.Artist.Name))
 	}
-	sb.WriteString("\n*Use `/charts play:true` to queue these tracks.*")
+	sb.WriteString("\n" + tr(interaction, "charts.play_hint"))
 
 	manager.SendFollowup(ctx, interaction, "", sb.String(), false)
 }
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+	"beatbot/discord"
+	"beatbot/sentryhelper"
+)
+
+// onClip posts the last 30 seconds of the bot's voice channel for /clip
+// save. Only members of that channel can take one, and only members who
+// opted in are heard in it.
+func (manager *Manager) onClip(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onClip: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if !player.GetClipsEnabled() {
+		manager.SendRequest(interaction, tr(interaction, "clip.off"), true)
+		return
+	}
+	botChannel := player.GetVoiceChannelID()
+	if botChannel == nil || *botChannel == "" {
+		manager.SendRequest(interaction, tr(interaction, "clip.not_in_voice"), true)
+		return
+	}
+	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
+	if err != nil || voiceState == nil || voiceState.ChannelID != *botChannel {
+		manager.SendRequest(interaction, tr(interaction, "clip.join_channel", *botChannel), true)
+		return
+	}
+
+	data, err := player.Clip()
+	if errors.Is(err, controller.ErrNoClipAudio) {
+		manager.SendRequest(interaction, tr(interaction, "clip.nothing_heard"), true)
+		return
+	}
+	if err != nil {
+		log.Errorf("Error making clip: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendError(interaction, tr(interaction, "clip.failed"), true)
+		return
+	}
+
+	err = discord.SendFollowupWithFile(&discord.FollowUpRequest{
+		Token:   interaction.Token,
+		AppID:   manager.AppID,
+		Content: tr(interaction, "clip.posted", *botChannel, interaction.Member.User.ID),
+	}, fmt.Sprintf("clip-%s.wav", time.Now().UTC().Format("20060102-150405")), data)
+	if err != nil {
+		log.Errorf("Error sending clip: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+	}
+}
+
+// handleClipConsent records whether the invoker agrees to be heard in this
+// server's clips, for /clip optin and /clip optout.
+func (manager *Manager) handleClipConsent(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if player.DB == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	consented := subcommandName(interaction) == "optin"
+	if err := player.SetClipConsent(interaction.Member.User.ID, consented); err != nil {
+		log.Errorf("Failed to save clip consent: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "clip.consent_failed"), Flags: 64}}
+	}
+
+	msg := tr(interaction, "clip.opted_out")
+	if consented {
+		msg = tr(interaction, "clip.opted_in")
+		if !player.GetClipsEnabled() {
+			msg += " " + tr(interaction, "clip.opted_in_while_off")
+		}
+	}
+	return Response{Type: 4, Data: ResponseData{Content: msg, Flags: 64}}
+}
+
+// handleClips toggles /clip for the server. With clips on, the bot joins
+// voice undeafened and keeps the last 30 seconds of what opted-in members
+// say; turning them off drops that and deafens it again.
+// Restricted to Manage Server via DefaultMemberPermissions in Commands.
+func (manager *Manager) handleClips(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if player.DB == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+	enabled := !player.GetClipsEnabled()
+	player.SetClipsEnabled(enabled)
+	if err := player.DB.SetGuildSetting(interaction.GuildID, "clips_enabled", strconv.FormatBool(enabled)); err != nil {
+		log.Errorf("Failed to save clips setting: %v", err)
+	}
+
+	msg := tr(interaction, "clip.disabled")
+	if enabled {
+		msg = tr(interaction, "clip.enabled")
+	} else {
+		player.StopClips()
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
@@ -0,0 +1,1124 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/database"
+	"beatbot/gemini"
+)
+
+// manageServer restricts admin commands (/djrole, /musicchannel, /dashboard, /threads, /reactions, /clips, /branding) to members
+// with the Manage Server permission. Server admins can override it per
+// command in Discord's integration settings.
+var manageServer int64 = discordgo.PermissionManageServer
+
+func minValue(v float64) *float64 {
+	return &v
+}
+
+// playlistNameOption is the required playlist name shared by the /playlist
+// subcommands.
+var playlistNameOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "name",
+	Description: "Playlist name",
+	Required:    true,
+	MaxLength:   50,
+}
+
+// contextMenuHelp describes context-menu commands for /help, since Discord
+// doesn't allow them a description of their own.
+var contextMenuHelp = map[string]string{
+	queueMessageCommand: "Queue the YouTube, Spotify, Apple Music, Deezer, SoundCloud, Bandcamp or Twitch link in it",
+}
+
+// Commands is every slash and context-menu command the bot handles. It is the source of truth
+// for what gets registered with Discord; a command added to the switch in
+// HandleInteraction must also be added here.
+var Commands = []*discordgo.ApplicationCommand{
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "ping",
+		Description: "Checks if the bot is responsive",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "help",
+		Description: "Shows the help menu",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "queue",
+		Description: "Add songs to the queue, or export and import it",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Adds a song to the queue",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "query",
+						Description: "Search query, or a YouTube, Spotify, SoundCloud, Bandcamp, Twitch, or audio file URL",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "source",
+						Description: "Where to search for the song (default: the server's setting)",
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Auto", Value: "auto"},
+							{Name: "YouTube", Value: "youtube"},
+							{Name: "Spotify (matched on YouTube)", Value: "spotify"},
+							{Name: "SoundCloud", Value: "soundcloud"},
+							{Name: "Bandcamp (links only)", Value: "bandcamp"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "pick",
+						Description: "Choose from the top 5 search results instead of taking the first",
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "export",
+				Description: "Save the current queue as a file and shareable code",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "import",
+				Description: "Queue songs from an exported queue file or code",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionAttachment,
+						Name:        "file",
+						Description: "A queue file from /queue export",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "code",
+						Description: "A queue code from /queue export",
+					},
+				},
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "play",
+		Description: "Queues a song from a search, link, or playlist (YouTube, Spotify, and more)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "query",
+				Description: "Search query, or a YouTube, Spotify, SoundCloud, Bandcamp, Twitch, or audio file URL",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "source",
+				Description: "Where to search for the song (default: the server's setting)",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Auto", Value: "auto"},
+					{Name: "YouTube", Value: "youtube"},
+					{Name: "Spotify (matched on YouTube)", Value: "spotify"},
+					{Name: "SoundCloud", Value: "soundcloud"},
+					{Name: "Bandcamp (links only)", Value: "bandcamp"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "pick",
+				Description: "Choose from the top 5 search results instead of taking the first",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "Start partway in, e.g. 1:30 (single songs only)",
+				MaxLength:   10,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "end",
+				Description: "Stop early, e.g. 3:45 (single songs only)",
+				MaxLength:   10,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "file",
+				Description: "An audio file to play instead (mp3, ogg, flac, wav, m4a)",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "search",
+		Description: "Search for a song and pick which result to queue",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "query",
+				Description: "What to search for",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "source",
+				Description: "Where to search for the song (default: the server's setting)",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Auto", Value: "auto"},
+					{Name: "YouTube", Value: "youtube"},
+					{Name: "SoundCloud", Value: "soundcloud"},
+					{Name: "Bandcamp (links only)", Value: "bandcamp"},
+				},
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "podcast",
+		Description: "Pick a recent episode from a podcast's RSS feed to queue",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "feed",
+				Description: "The podcast's RSS or Atom feed URL",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "chapters",
+		Description: "Queue one chapter of a long YouTube video, like an album upload, or all of them as songs",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "video",
+				Description: "A YouTube link (default: the song playing)",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "view",
+		Description: "View the current queue, or search it by title or requester",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "query",
+				Description: "Only show songs whose title or artist contains this",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "requester",
+				Description: "Only show songs this member queued",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "skip",
+		Description: "Skips the current song",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "reset",
+		Description: "Resets the player state, use this if the bot is stuck",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "disconnect",
+		Description: "Leaves the voice channel and saves the queue for /summon",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "summon",
+		Description: "Brings the bot to your voice channel without queueing anything",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "restore",
+				Description: "Queue the songs saved by the last /disconnect",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "follow",
+		Description: "Toggle following whoever queued the song when they switch voice channels",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "nowplaying",
+		Description: "Shows the current song with playback controls",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "listen-along",
+		Description: "Post a link and timestamp to follow along on your own player",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "notifyme",
+		Description: "Toggle a DM when a song you queued is up next",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "forgetme",
+		Description: "Delete everything the bot stores about you, in every server",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "preferences",
+		Description: "View or change your own settings, which follow you into every server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "language",
+				Description: "The language I answer you in",
+				Choices:     languageChoices(),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "private_replies",
+				Description: "Show AI confirmations of your commands only to you",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "volume_offset",
+				Description: "Turn songs you queue up or down by this much, e.g. -20 if yours tend to be loud",
+				MinValue:    minValue(-database.MaxVolumeOffset),
+				MaxValue:    database.MaxVolumeOffset,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "notify_up_next",
+				Description: "DM you when a song you queued is up next (same as /notifyme)",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "grab",
+		Description: "DMs you the current song so you can find it later",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "remove",
+		Description: "Removes a song from the queue",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "song_number",
+				Description:  "The number of the song to remove, or a range like 2-5",
+				Autocomplete: true,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "undo",
+		Description: "Undo the last /clear, /remove range, or /shuffle (within 60 seconds)",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "jump",
+		Description: "Jump straight to a song in the queue",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "position",
+				Description: "The queue number of the song to play next (see /view)",
+				Required:    true,
+				MinValue:    minValue(1),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "skipped",
+				Description: "What to do with the songs ahead of it (default: drop)",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "drop", Value: "drop"},
+					{Name: "move to end", Value: "move"},
+				},
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "topsongs",
+		Description: "Queues the top 5 songs for an artist",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "artist",
+				Description: "The artist to get the top songs for",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "volume",
+		Description: "Sets the volume of the player",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "volume",
+				Description: "The volume to set the player to, range is 0-150",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "pause",
+		Description: "Pauses the current song",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "stop",
+		Description: "Pauses the current song",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "resume",
+		Description: "Resumes the current song",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "purge",
+		Description: "Purges the queue",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "clear",
+		Description: "Clears the queue but keeps the current song playing",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "shuffle",
+		Description: "Shuffles the current queue",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "radio",
+		Description: "Toggle radio mode - automatically queues similar songs. Add a vibe to guide the picks.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "vibe",
+				Description: "A mood, artist, or genre to guide radio picks (e.g. 'chill indie', '90s hip hop')",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "genre",
+				Description: "Lock radio to a genre station (e.g. 'rock', 'electronic', 'jazz')",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "artist",
+				Description: "Seed radio from a specific artist (e.g. 'Daft Punk', 'Radiohead')",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "request",
+		Description: "Ask the DJ to rework the queue with a suggestion",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "suggestion",
+				Description: "What you want to hear (e.g. 'something chill', 'more punk', 'Radiohead deep cuts')",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "dj",
+		Description: "Tell the DJ what you're in the mood for and it queues songs to match",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "request",
+				Description: "What you want to hear (e.g. 'something chill for studying', '80s synthpop')",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "loop",
+		Description: "Toggle loop mode - repeats the current song",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "history",
+		Description: "Shows recently played songs in this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "limit",
+				Description: "Number of songs to show (default 10, max 25)",
+				MinValue:    minValue(1),
+				MaxValue:    25,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "leaderboard",
+		Description: "Shows the most played songs in this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "limit",
+				Description: "Number of songs to show (default 10, max 25)",
+				MinValue:    minValue(1),
+				MaxValue:    25,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "skipped",
+				Description: "Rank the most skipped songs instead",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "stats",
+		Description: "Shows playback statistics for this server",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "lyrics",
+		Description: "Shows lyrics for the currently playing song, or one you name",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "song",
+				Description: "A song to look up instead of the one playing",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "synced",
+				Description: "Follow along line by line as the song plays",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "recommend",
+		Description: "Get a few AI DJ picks to queue, based on what's been playing",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "favorite",
+		Description: "Save the currently playing song to your favorites",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "favorites",
+		Description: "View your saved favorite songs for this server",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "unfavorite",
+		Description: "Remove a song from your favorites",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "song_number",
+				Description: "The number of the song to remove (from /favorites list)",
+				Required:    true,
+				MinValue:    minValue(1),
+				MaxValue:    25,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "playfavorites",
+		Description: "Queue all of your saved favorites",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "shuffle",
+				Description: "Queue them in random order",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "playlist",
+		Description: "Keep named playlists in the bot and queue them",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "create",
+				Description: "Create an empty playlist",
+				Options: []*discordgo.ApplicationCommandOption{
+					playlistNameOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "shared",
+						Description: "Make it a server playlist anyone can add to and play",
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Add a song to a playlist",
+				Options: []*discordgo.ApplicationCommandOption{
+					playlistNameOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "query",
+						Description: "Search query or song link (default: the current song)",
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Remove a song from a playlist",
+				Options: []*discordgo.ApplicationCommandOption{
+					playlistNameOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "position",
+						Description: "The number of the song (from /playlist list)",
+						Required:    true,
+						MinValue:    minValue(1),
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Show your and the server's playlists, or the songs on one",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Playlist to show the songs of",
+						MaxLength:   50,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "play",
+				Description: "Queue every song on a playlist",
+				Options: []*discordgo.ApplicationCommandOption{
+					playlistNameOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "shuffle",
+						Description: "Queue them in random order",
+					},
+				},
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "playat",
+		Description: "Schedule a song or playlist to start later, e.g. at 21:00",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "time",
+				Description: "When to start: 21:00, 9pm, or a delay like 30m",
+				Required:    true,
+				MaxLength:   20,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "query",
+				Description: "Search query or song link",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "playlist",
+				Description: "A playlist from /playlist list",
+				MaxLength:   50,
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "schedule",
+		Description: "See or cancel scheduled plays",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Show what's scheduled in this server",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "cancel",
+				Description: "Cancel a scheduled play",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "id",
+						Description: "The number from /schedule list",
+						Required:    true,
+						MinValue:    minValue(1),
+					},
+				},
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "announce",
+		Description: "Toggle DJ voice announcements between songs",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "voice",
+				Description: "Set the DJ voice (e.g. Kore, Puck, Charon)",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "voice-demo",
+		Description: "Preview the DJ voice with a short quip",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "voice",
+				Description: "Voice to preview (uses current voice if omitted)",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "voices",
+		Description: "List available TTS voices for DJ announcements",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "charts",
+		Description: "Show what's trending right now",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "play",
+				Description: "Queue the top tracks instead of just displaying them (true/false)",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "transcript",
+		Description: "Post everything played this session, in order, with requesters",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "file",
+				Description: "Attach a markdown file instead of a message",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "clip",
+		Description: "Save the last 30 seconds of the voice channel, or choose whether you're in clips",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "save",
+				Description: "Post the last 30 seconds of the voice channel as a file",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "optin",
+				Description: "Let your voice be in this server's clips",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "optout",
+				Description: "Keep your voice out of this server's clips",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "soundboard",
+		Description: "Play short sounds over the music, or add your own",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "play",
+				Description: "Play a sound in the voice channel, over whatever's playing",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "name",
+						Description:  "The sound to play",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "upload",
+				Description: "Add a sound of up to 10 seconds, or replace one of yours",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "What to call the sound",
+						Required:    true,
+						MaxLength:   soundNameLimit,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionAttachment,
+						Name:        "file",
+						Description: "An audio file (mp3, ogg, wav...) up to 10 seconds long",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Show this server's sounds",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "neverplay",
+		Description: "Block the current song from ever playing again and skip it",
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "translate",
+		Description: "Toggle English translations for foreign-language song titles",
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "djrole",
+		Description:              "Configure the DJ role and which commands only DJs can use",
+		DefaultMemberPermissions: &manageServer,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Set the DJ role; its members' requests jump ahead in the queue",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "Role to treat as DJ",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "clear",
+				Description: "Remove the DJ role",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "restrict",
+				Description: "Limit a command to DJs and server managers",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "command",
+						Description: "Command to restrict",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "All of them", Value: "all"},
+							{Name: "/skip", Value: "skip"},
+							{Name: "/clear", Value: "clear"},
+							{Name: "/reset", Value: "reset"},
+							{Name: "/volume", Value: "volume"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "enabled",
+						Description: "Restrict it (default) or open it back up to everyone",
+					},
+				},
+			},
+		},
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "settings",
+		Description:              "View and change this server's bot settings",
+		DefaultMemberPermissions: &manageServer,
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "tone",
+		Description: "Give the AI DJ's personality a custom style for this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Set the style and hear a preview (server managers only)",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "style",
+						Description: "How the DJ should sound (e.g. 'talk like a 90s radio host', 'extra dry and deadpan')",
+						Required:    true,
+						MaxLength:   gemini.MaxGuildStyleLength,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Show this server's current style",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Go back to the default personality (server managers only)",
+			},
+		},
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "audiodebug",
+		Description:              "Show the audio pipeline's state, for when playback gets stuck",
+		DefaultMemberPermissions: &manageServer,
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "auditlog",
+		Description:              "See who ran which commands recently",
+		DefaultMemberPermissions: &manageServer,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "Only show this member's commands",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "command",
+				Description: "Only show this command, e.g. clear",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "limit",
+				Description: "Number of commands to show (default 15, max 25)",
+				MinValue:    minValue(1),
+				MaxValue:    25,
+			},
+		},
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "musicchannel",
+		Description:              "Post now-playing cards and queue updates in one channel (omit to unbind)",
+		DefaultMemberPermissions: &manageServer,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "Text channel for music updates",
+				ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+			},
+		},
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "dashboard",
+		Description:              "Keep a pinned, self-updating now-playing message in the music channel (toggle)",
+		DefaultMemberPermissions: &manageServer,
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "threads",
+		Description:              "Give each listening session its own thread for cards and queue updates (toggle)",
+		DefaultMemberPermissions: &manageServer,
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "clips",
+		Description:              "Let /clip record the voice channel, for members who opt in (toggle)",
+		DefaultMemberPermissions: &manageServer,
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "reactions",
+		Description:              "Add ⏯️ ⏭️ ⏹️ reactions to now-playing cards as playback controls (toggle)",
+		DefaultMemberPermissions: &manageServer,
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "branding",
+		Description:              "Match now-playing cards to your server's colors and emoji",
+		DefaultMemberPermissions: &manageServer,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Show this server's branding",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "color",
+				Description: "Set the card color while playing",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "hex",
+						Description: "A hex color, e.g. #FF5500",
+						Required:    true,
+						MaxLength:   7,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "bar",
+				Description: "Set the progress bar characters",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "filled",
+						Description: "Character for time played (default ▓)",
+						Required:    true,
+						MaxLength:   8,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "empty",
+						Description: "Character for time left (default ░)",
+						Required:    true,
+						MaxLength:   8,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "emoji",
+				Description: "Set a playback button's emoji",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "button",
+						Description: "Which button",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Pause", Value: "pause"},
+							{Name: "Play", Value: "play"},
+							{Name: "Skip", Value: "skip"},
+							{Name: "Shuffle", Value: "shuffle"},
+							{Name: "Queue", Value: "queue"},
+							{Name: "Volume down", Value: "voldown"},
+							{Name: "Volume up", Value: "volup"},
+							{Name: "Grab", Value: "grab"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "emoji",
+						Description: "A unicode emoji or one of this server's emoji",
+						Required:    true,
+						MaxLength:   64,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Go back to the default look",
+			},
+		},
+	},
+	{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        "guestdj",
+		Description: "Give someone temporary DJ powers (skip, reorder, volume)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "Who gets the decks",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "duration",
+				Description: "How long, e.g. 30m or 1h (default 30m) — use off to end early",
+			},
+		},
+	},
+	{
+		Type:                     discordgo.ChatApplicationCommand,
+		Name:                     "queuesettings",
+		Description:              "Change how the queue orders requests and displays titles",
+		DefaultMemberPermissions: &manageServer,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "fifo: first come, first served. fair: take turns by requester",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "fifo", Value: "fifo"},
+					{Name: "fair", Value: "fair"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "strip_emoji",
+				Description: "Remove emoji from song titles as they're queued",
+			},
+		},
+	},
+	{
+		// Message context-menu commands have no description or options.
+		Type: discordgo.MessageApplicationCommand,
+		Name: queueMessageCommand,
+	},
+}
+
+// RegisterCommands overwrites the application's global commands with
+// Commands. Discord only touches commands whose definition changed, so it is
+// safe to run on every boot.
+func RegisterCommands(botToken, appID string) error {
+	if botToken == "" || appID == "" {
+		return fmt.Errorf("DISCORD_BOT_TOKEN and DISCORD_APP_ID must be set to register commands")
+	}
+
+	// Only the REST API is needed, so the session is never opened.
+	session, err := discordgo.New("Bot " + botToken)
+	if err != nil {
+		return fmt.Errorf("creating discord session: %w", err)
+	}
+
+	registered, err := session.ApplicationCommandBulkOverwrite(appID, "", Commands)
+	if err != nil {
+		return fmt.Errorf("registering commands: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"module": "handlers",
+		"count":  len(registered),
+	}).Info("Registered slash commands")
+	return nil
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var commandNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// TestCommands_Valid checks the limits Discord enforces on registration, so a
+// bad definition fails here instead of rejecting the whole overwrite on boot.
+func TestCommands_Valid(t *testing.T) {
+	if len(Commands) > 100 {
+		t.Fatalf("Discord allows at most 100 global commands, got %d", len(Commands))
+	}
+
+	seen := make(map[string]bool)
+	for _, cmd := range Commands {
+		if seen[cmd.Name] {
+			t.Errorf("duplicate command %q", cmd.Name)
+		}
+		seen[cmd.Name] = true
+		if cmd.Type == discordgo.MessageApplicationCommand || cmd.Type == discordgo.UserApplicationCommand {
+			// Context-menu names are free text; they take no description or options.
+			if n := len([]rune(cmd.Name)); n == 0 || n > 32 || cmd.Description != "" || len(cmd.Options) > 0 {
+				t.Errorf("context-menu command %q: name must be 1-32 characters with no description or options", cmd.Name)
+			}
+			continue
+		}
+		checkNameAndDescription(t, cmd.Name, cmd.Name, cmd.Description)
+		checkOptions(t, cmd.Name, cmd.Options)
+	}
+}
+
+func checkNameAndDescription(t *testing.T, path, name, description string) {
+	t.Helper()
+	if !commandNamePattern.MatchString(name) {
+		t.Errorf("%s: invalid name %q", path, name)
+	}
+	if n := len([]rune(description)); n == 0 || n > 100 {
+		t.Errorf("%s: description must be 1-100 characters, got %d", path, n)
+	}
+}
+
+func checkOptions(t *testing.T, path string, options []*discordgo.ApplicationCommandOption) {
+	t.Helper()
+	if len(options) > 25 {
+		t.Errorf("%s: at most 25 options allowed, got %d", path, len(options))
+	}
+	seen := make(map[string]bool)
+	optional := false
+	for _, opt := range options {
+		optPath := path + " " + opt.Name
+		if seen[opt.Name] {
+			t.Errorf("%s: duplicate option", optPath)
+		}
+		seen[opt.Name] = true
+		checkNameAndDescription(t, optPath, opt.Name, opt.Description)
+
+		if opt.Required && optional {
+			t.Errorf("%s: required options must come before optional ones", optPath)
+		}
+		optional = optional || !opt.Required
+
+		if len(opt.Choices) > 25 {
+			t.Errorf("%s: at most 25 choices allowed, got %d", optPath, len(opt.Choices))
+		}
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand {
+			checkOptions(t, optPath, opt.Options)
+		} else if len(opt.Options) > 0 {
+			t.Errorf("%s: only subcommands can have nested options", optPath)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"beatbot/discord"
+)
+
+// confirmTimeout is how long a confirmation prompt's buttons stay live.
+const confirmTimeout = 30 * time.Second
+
+// confirmations tracks open confirm/cancel prompts so they expire: each is
+// keyed by guild, user and action, and a prompt that's still open when its
+// timer fires is marked expired.
+type confirmations struct {
+	mu   sync.Mutex
+	open map[string]*time.Timer
+}
+
+func newConfirmations() *confirmations {
+	return &confirmations{open: make(map[string]*time.Timer)}
+}
+
+func confirmKey(guildID, userID, action string) string {
+	return guildID + "/" + userID + "/" + action
+}
+
+// start opens a prompt under key, replacing any earlier one, and calls
+// expire if it's still open after timeout.
+func (c *confirmations) start(key string, timeout time.Duration, expire func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.open[key]; ok {
+		old.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(timeout, func() {
+		c.mu.Lock()
+		current := c.open[key] == timer
+		if current {
+			delete(c.open, key)
+		}
+		c.mu.Unlock()
+		if current {
+			expire()
+		}
+	})
+	c.open[key] = timer
+}
+
+// resolve closes the prompt under key, reporting whether it was still open.
+func (c *confirmations) resolve(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer, ok := c.open[key]
+	if !ok {
+		return false
+	}
+	timer.Stop()
+	delete(c.open, key)
+	return true
+}
+
+// confirmPrompt answers a destructive command with an ephemeral prompt whose
+// Danger button routes to action+"_confirm" and Cancel to action+"_cancel".
+// The buttons are disabled if nobody answers within confirmTimeout.
+func (manager *Manager) confirmPrompt(interaction *Interaction, action, content, confirmLabel string) Response {
+	token := interaction.Token
+	manager.confirms.start(confirmKey(interaction.GuildID, interaction.Member.User.ID, action), confirmTimeout, func() {
+		discord.UpdateMessage(&discord.FollowUpRequest{
+			Token:      token,
+			AppID:      manager.AppID,
+			Content:    tr(interaction, "confirm.timed_out"),
+			Components: discord.DisabledButton(tr(interaction, "confirm.expired_button")),
+		})
+	})
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content:    content,
+			Flags:      64,
+			Components: discord.ConfirmButtons(interaction.GuildID, action+"_confirm", confirmLabel, action+"_cancel"),
+		},
+	}
+}
+
+// answerPrompt closes the prompt a confirm or cancel button belongs to. ok
+// is false once it has timed out, along with the message to show instead.
+func (manager *Manager) answerPrompt(interaction *Interaction, action, command string) (expired Response, ok bool) {
+	if manager.confirms.resolve(confirmKey(interaction.GuildID, interaction.Member.User.ID, action)) {
+		return Response{}, true
+	}
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "confirm.expired", command),
+		Components: discord.DisabledButton(tr(interaction, "confirm.expired_button")),
+	}}, false
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfirmations(t *testing.T) {
+	c := newConfirmations()
+	key := confirmKey("g1", "u1", "clear")
+
+	expired := make(chan struct{}, 1)
+	c.start(key, time.Hour, func() { expired <- struct{}{} })
+	if !c.resolve(key) {
+		t.Fatal("open prompt wasn't resolvable")
+	}
+	if c.resolve(key) {
+		t.Error("prompt resolved twice")
+	}
+
+	c.start(key, 10*time.Millisecond, func() { expired <- struct{}{} })
+	select {
+	case <-expired:
+	case <-time.After(time.Second):
+		t.Fatal("prompt never expired")
+	}
+	if c.resolve(key) {
+		t.Error("expired prompt was still resolvable")
+	}
+}
+
+func TestConfirmationsReplace(t *testing.T) {
+	c := newConfirmations()
+	key := confirmKey("g1", "u1", "reset")
+
+	fired := make(chan string, 2)
+	c.start(key, 10*time.Millisecond, func() { fired <- "first" })
+	c.start(key, time.Hour, func() { fired <- "second" })
+
+	select {
+	case which := <-fired:
+		t.Errorf("%s prompt expired; the first should have been replaced", which)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if !c.resolve(key) {
+		t.Error("replacement prompt wasn't open")
+	}
+}
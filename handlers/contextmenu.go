@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	sentry "github.com/getsentry/sentry-go"
+
+	"beatbot/resolver"
+	"beatbot/unfurl"
+)
+
+// queueMessageCommand is the message context-menu command that queues the
+// first playable link in a message.
+const queueMessageCommand = "Queue in BeatBot"
+
+// commandTypeMessage is the interaction data type of a message context-menu command.
+const commandTypeMessage = 3
+
+var messageLinkPattern = regexp.MustCompile(`https?://[^\s<>]+`)
+
+// playableLink returns the first link in content that /play can queue: one
+// a resolver claims (YouTube, Spotify, SoundCloud and the rest) or a
+// shortened share link.
+func playableLink(content string) string {
+	for _, link := range messageLinkPattern.FindAllString(content, -1) {
+		link = strings.TrimRight(link, ".,;:!?)]*_|~`'\"")
+		if resolver.ForURL(link) != nil || unfurl.IsShortLink(link) {
+			return link
+		}
+	}
+	return ""
+}
+
+// handleQueueMessage queues the link in the message a member right-clicked.
+// The link is handed to QueryAndQueue as if it were /play's query option.
+func (manager *Manager) handleQueueMessage(ctx context.Context, transaction *sentry.Span, interaction *Interaction) (response Response, async bool) {
+	var content string
+	if resolved := interaction.Data.Resolved; resolved != nil {
+		content = resolved.Messages[interaction.Data.TargetID].Content
+	}
+
+	link := playableLink(content)
+	if link == "" {
+		return Response{Type: 4, Data: ResponseData{
+			Content: tr(interaction, "contextmenu.no_link"),
+			Flags:   64,
+		}}, false
+	}
+
+	interaction.Data.Options = []InteractionOption{{Name: "query", Type: optionTypeString, Value: link}}
+	go manager.QueryAndQueue(ctx, transaction, interaction)
+	return Response{Type: 5}, true
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import "testing"
+
+func TestPlayableLink(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"check this out https://www.youtube.com/watch?v=abc123!", "https://www.youtube.com/watch?v=abc123"},
+		{"<https://youtu.be/abc123?si=x>", "https://youtu.be/abc123?si=x"},
+		{"https://youtube.com/shorts/abc123", "https://youtube.com/shorts/abc123"},
+		{"https://youtube.com/playlist?list=PL1", "https://youtube.com/playlist?list=PL1"},
+		{"(https://open.spotify.com/track/42)", "https://open.spotify.com/track/42"},
+		{"https://music.apple.com/us/album/x/1?i=2", "https://music.apple.com/us/album/x/1?i=2"},
+		{"https://www.deezer.com/en/album/302127", "https://www.deezer.com/en/album/302127"},
+		{"listen https://spotify.link/AbCdEf123!", "https://spotify.link/AbCdEf123"},
+		{"see https://example.com then https://soundcloud.com/a/b", "https://soundcloud.com/a/b"},
+		{"https://www.youtube.com/@channel", ""},
+		{"no links here", ""},
+	}
+	for _, tt := range tests {
+		if got := playableLink(tt.content); got != tt.want {
+			t.Errorf("playableLink(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
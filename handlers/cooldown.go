@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// cooldownSweepSize is how many entries build up before expired ones are
+// dropped, so the map doesn't grow with every user the bot has ever seen.
+const cooldownSweepSize = 1000
+
+// Cooldowns enforces a per-user wait between uses of the same command.
+type Cooldowns struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration // command -> cooldown
+	until     map[string]time.Time     // userID + "/" + command -> when it's usable again
+}
+
+// NewCooldowns creates a limiter; commands missing from durations are never limited.
+func NewCooldowns(durations map[string]time.Duration) *Cooldowns {
+	return &Cooldowns{
+		durations: durations,
+		until:     make(map[string]time.Time),
+	}
+}
+
+// Allow records a use of command by userID at now. When the user is still
+// cooling down it returns false and how long is left, without resetting
+// the wait.
+func (c *Cooldowns) Allow(userID, command string, now time.Time) (bool, time.Duration) {
+	d := c.durations[command]
+	if d <= 0 || userID == "" {
+		return true, 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := userID + "/" + command
+	if until, ok := c.until[key]; ok && now.Before(until) {
+		return false, until.Sub(now)
+	}
+
+	if len(c.until) >= cooldownSweepSize {
+		for k, until := range c.until {
+			if !now.Before(until) {
+				delete(c.until, k)
+			}
+		}
+	}
+	c.until[key] = now.Add(d)
+	return true, 0
+}
+
+// requireCooldown records a use of command by the interaction's member. ok
+// is false when they're still cooling down and the caller should return the
+// refusal instead of running the command.
+func (manager *Manager) requireCooldown(interaction *Interaction, command string) (refusal Response, ok bool) {
+	if manager.Cooldowns == nil {
+		return Response{}, true
+	}
+	allowed, wait := manager.Cooldowns.Allow(interaction.Member.User.ID, command, time.Now())
+	if allowed {
+		return Response{}, true
+	}
+	return Response{Type: 4, Data: ResponseData{
+		Content: tr(interaction, "cooldown.slow_down", command, int(math.Ceil(wait.Seconds()))),
+		Flags:   64,
+	}}, false
+}
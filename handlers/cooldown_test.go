@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCooldowns(t *testing.T) {
+	c := NewCooldowns(map[string]time.Duration{"play": 3 * time.Second})
+	start := time.Now()
+
+	if ok, _ := c.Allow("u1", "play", start); !ok {
+		t.Fatal("first use was limited")
+	}
+	ok, wait := c.Allow("u1", "play", start.Add(time.Second))
+	if ok || wait != 2*time.Second {
+		t.Errorf("second use = %v, %v; want limited with 2s left", ok, wait)
+	}
+	if ok, _ := c.Allow("u2", "play", start.Add(time.Second)); !ok {
+		t.Error("another user was limited")
+	}
+	if ok, _ := c.Allow("u1", "view", start.Add(time.Second)); !ok {
+		t.Error("a command without a cooldown was limited")
+	}
+	if ok, _ := c.Allow("u1", "play", start.Add(3*time.Second)); !ok {
+		t.Error("use after the cooldown was limited")
+	}
+}
+
+func TestCooldownsSweep(t *testing.T) {
+	c := NewCooldowns(map[string]time.Duration{"play": time.Second})
+	start := time.Now()
+	for i := range cooldownSweepSize {
+		c.Allow(strconv.Itoa(i), "play", start)
+	}
+	c.Allow("late", "play", start.Add(time.Minute))
+	if len(c.until) != 1 {
+		t.Errorf("%d entries after sweep, want 1", len(c.until))
+	}
+}
+
+func TestRequireCooldown(t *testing.T) {
+	manager := &Manager{Cooldowns: NewCooldowns(map[string]time.Duration{"skip": time.Minute})}
+	interaction := &Interaction{Member: MemberData{User: UserData{ID: "u1"}}}
+
+	if _, ok := manager.requireCooldown(interaction, "skip"); !ok {
+		t.Fatal("first skip was refused")
+	}
+	refusal, ok := manager.requireCooldown(interaction, "skip")
+	if ok || refusal.Data.Flags != 64 || !strings.Contains(refusal.Data.Content, "/skip") {
+		t.Errorf("second skip = %+v, %v; want a private refusal naming /skip", refusal, ok)
+	}
+	if _, ok := (&Manager{}).requireCooldown(interaction, "skip"); !ok {
+		t.Error("a manager without cooldowns refused")
+	}
+}
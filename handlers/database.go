@@ -1,40 +1,37 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
-	sentry "github.com/getsentry/sentry-go"
+	"github.com/bwmarrin/discordgo"
 	log "github.com/sirupsen/logrus"
 
-	"beatbot/config"
-	"beatbot/discord"
-	"beatbot/gemini"
-	"beatbot/sentryhelper"
+	"beatbot/database"
 	"beatbot/youtube"
 )
 
 func (manager *Manager) handleHistory(interaction *Interaction) Response {
 	db := manager.Controller.GetDB()
 	if db == nil {
-		return Response{Type: 4, Data: ResponseData{Content: "Database is not available.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
 	}
 
 	limit := parseLimitOption(interaction)
 	records, err := db.GetHistory(interaction.GuildID, limit)
 	if err != nil {
 		log.Errorf("Error fetching history: %v", err)
-		return Response{Type: 4, Data: ResponseData{Content: "Failed to fetch history.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "history.fetch_failed"), Flags: 64}}
 	}
 
 	if len(records) == 0 {
-		return Response{Type: 4, Data: ResponseData{Content: "🎵 No songs played yet!"}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "history.empty")}}
 	}
 
 	var sb strings.Builder
-	sb.WriteString("🎵 **Recently Played**\n\n")
+	sb.WriteString(tr(interaction, "history.title") + "\n\n")
 	for i, r := range records {
 		requester := r.RequestedByUsername
 		if requester == "" {
@@ -42,10 +39,10 @@ func (manager *Manager) handleHistory(interaction *Interaction) Response {
 			if r.RequestedByUserID != "" {
 				requester = db.GetOrFetchUsername(interaction.GuildID, r.RequestedByUserID)
 			} else {
-				requester = "Unknown"
+				requester = tr(interaction, "common.unknown")
 			}
 		}
-		sb.WriteString(fmt.Sprintf("**%d.** %s\n　　↳ requested by **%s** · %s\n", i+1, r.Title, requester, formatRelativeTime(r.PlayedAt)))
+		sb.WriteString(tr(interaction, "history.line", i+1, r.Title, requester, formatRelativeTime(interaction, r.PlayedAt)) + "\n")
 	}
 
 	content := sb.String()
@@ -63,33 +60,34 @@ func (manager *Manager) handleHistory(interaction *Interaction) Response {
 func (manager *Manager) handleLeaderboard(interaction *Interaction) Response {
 	db := manager.Controller.GetDB()
 	if db == nil {
-		return Response{Type: 4, Data: ResponseData{Content: "Database is not available.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
 	}
 
 	limit := parseLimitOption(interaction)
+	for _, opt := range interaction.Data.Options {
+		if opt.Name == "skipped" && opt.Value == "true" {
+			return manager.mostSkipped(interaction, db, limit)
+		}
+	}
 	records, err := db.GetMostPlayed(interaction.GuildID, limit)
 	if err != nil {
 		log.Errorf("Error fetching leaderboard: %v", err)
-		return Response{Type: 4, Data: ResponseData{Content: "Failed to fetch leaderboard.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "leaderboard.fetch_failed"), Flags: 64}}
 	}
 
 	if len(records) == 0 {
-		return Response{Type: 4, Data: ResponseData{Content: "🏆 No songs played yet!"}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "leaderboard.empty")}}
 	}
 
 	medals := []string{"🥇", "🥈", "🥉"}
 	var sb strings.Builder
-	sb.WriteString("🏆 **Most Played Songs**\n\n")
+	sb.WriteString(tr(interaction, "leaderboard.title") + "\n\n")
 	for i, r := range records {
 		prefix := fmt.Sprintf("**%d.**", i+1)
 		if i < 3 {
 			prefix = medals[i]
 		}
-		plays := "play"
-		if r.PlayCount != 1 {
-			plays = "plays"
-		}
-		sb.WriteString(fmt.Sprintf("%s %s\n　　↳ **%d** %s · last played %s\n", prefix, r.Title, r.PlayCount, plays, formatRelativeTime(r.LastPlayed)))
+		sb.WriteString(tr(interaction, "leaderboard.line", prefix, r.Title, r.PlayCount, pluralPlays(interaction, r.PlayCount), formatRelativeTime(interaction, r.LastPlayed)) + "\n")
 	}
 
 	content := sb.String()
@@ -104,31 +102,142 @@ func (manager *Manager) handleLeaderboard(interaction *Interaction) Response {
 	return Response{Type: 4, Data: ResponseData{Content: content}}
 }
 
+// mostSkipped answers /leaderboard skipped:True.
+func (manager *Manager) mostSkipped(interaction *Interaction, db *database.Database, limit int) Response {
+	records, err := db.GetMostSkipped(interaction.GuildID, limit)
+	if err != nil {
+		log.Errorf("Error fetching most skipped: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "leaderboard.fetch_failed"), Flags: 64}}
+	}
+	if len(records) == 0 {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "leaderboard.none_skipped")}}
+	}
+	return Response{Type: 4, Data: ResponseData{Content: mostSkippedMessage(interaction, records)}}
+}
+
+// mostSkippedMessage renders the most skipped songs, with how often and how
+// soon each gets skipped.
+func mostSkippedMessage(interaction *Interaction, records []database.MostSkippedRecord) string {
+	var sb strings.Builder
+	sb.WriteString(tr(interaction, "leaderboard.skipped_title") + "\n\n")
+	for i, r := range records {
+		line := tr(interaction, "leaderboard.skipped_line", i+1, r.Title, r.Skips, r.Plays, pluralPlays(interaction, r.Plays))
+		if r.SkippedAt >= 0 {
+			line += tr(interaction, "leaderboard.skipped_at", r.SkippedAt)
+		}
+		line += "\n"
+		if sb.Len()+len(line) > 1900 {
+			sb.WriteString("...")
+			break
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+func (manager *Manager) handleStats(interaction *Interaction) Response {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	stats, err := db.GetGuildStats(interaction.GuildID, 5)
+	if err != nil {
+		log.Errorf("Error fetching stats: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "stats.fetch_failed"), Flags: 64}}
+	}
+
+	if stats.SongsPlayed == 0 {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "stats.empty")}}
+	}
+
+	return Response{Type: 4, Data: ResponseData{Embeds: []*discordgo.MessageEmbed{buildStatsEmbed(interaction, stats)}}}
+}
+
+func buildStatsEmbed(interaction *Interaction, stats *database.GuildStats) *discordgo.MessageEmbed {
+	skipRate := 100 * stats.Skips / stats.SongsPlayed
+	embed := &discordgo.MessageEmbed{
+		Title: tr(interaction, "stats.title"),
+		Color: 0x1DB954,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: tr(interaction, "stats.songs_played"), Value: strconv.Itoa(stats.SongsPlayed), Inline: true},
+			{Name: tr(interaction, "stats.unique_songs"), Value: strconv.Itoa(stats.UniqueSongs), Inline: true},
+			{Name: tr(interaction, "stats.listening_time"), Value: formatPlaytime(stats.Playtime), Inline: true},
+			{Name: tr(interaction, "stats.skips"), Value: fmt.Sprintf("%d (%d%%)", stats.Skips, skipRate), Inline: true},
+		},
+	}
+
+	if len(stats.TopRequesters) > 0 {
+		var sb strings.Builder
+		for i, r := range stats.TopRequesters {
+			// Mentions in embeds render as names without pinging.
+			sb.WriteString(fmt.Sprintf("**%d.** <@%s> · %d %s · %s\n", i+1, r.UserID, r.Plays, pluralSongs(interaction, r.Plays), formatPlaytime(r.Playtime)))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: tr(interaction, "stats.top_requesters"), Value: sb.String()})
+	}
+
+	if len(stats.MostSkipped) > 0 {
+		var sb strings.Builder
+		for i, r := range stats.MostSkipped {
+			sb.WriteString(tr(interaction, "stats.skipped_line", i+1, r.Title, r.Skips) + "\n")
+		}
+		value := sb.String()
+		if len(value) > 1024 {
+			value = value[:strings.LastIndex(value[:1024], "\n")+1]
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: tr(interaction, "stats.most_skipped"), Value: value})
+	}
+
+	return embed
+}
+
+// pluralPlays is "play" or "plays" in the interaction's language.
+func pluralPlays(interaction *Interaction, n int) string {
+	if n == 1 {
+		return tr(interaction, "common.play")
+	}
+	return tr(interaction, "common.plays")
+}
+
+// formatPlaytime renders a listening total like "3h 12m", or "45m" under an hour.
+func formatPlaytime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	switch {
+	case h >= 24:
+		return fmt.Sprintf("%dd %dh", h/24, h%24)
+	case h > 0:
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
 func (manager *Manager) handleNeverPlay(interaction *Interaction) Response {
 	db := manager.Controller.GetDB()
 	if db == nil {
-		return Response{Type: 4, Data: ResponseData{Content: "Database is not available.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
 	}
 
 	player := manager.Controller.GetPlayer(interaction.GuildID)
 	currentItem := player.GetCurrentItem()
 	if currentItem == nil {
-		return Response{Type: 4, Data: ResponseData{Content: "Nothing is currently playing.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.nothing_playing"), Flags: 64}}
 	}
 
 	video := currentItem.Video
 
 	if db.IsVideoBlocked(interaction.GuildID, video.VideoID) {
 		return Response{Type: 4, Data: ResponseData{
-			Content: fmt.Sprintf("**%s** is already on the never-play list.", video.Title),
+			Content: tr(interaction, "neverplay.already_blocked", video.Title),
 			Flags:   64,
 		}}
 	}
 
-	videoURL := "https://www.youtube.com/watch?v=" + video.VideoID
+	videoURL := video.PageURL()
 	if err := db.BlockVideo(interaction.GuildID, video.VideoID, video.Title, videoURL); err != nil {
 		log.Errorf("Error blocking video: %v", err)
-		return Response{Type: 4, Data: ResponseData{Content: "Failed to block song. Try again.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "neverplay.failed"), Flags: 64}}
 	}
 
 	// Only skip if the blocked song is still the active one; a natural end between
@@ -141,7 +250,7 @@ func (manager *Manager) handleNeverPlay(interaction *Interaction) Response {
 	}()
 
 	return Response{Type: 4, Data: ResponseData{
-		Content: fmt.Sprintf("🚫 **%s** has been blocked and will never play again.", video.Title),
+		Content: tr(interaction, "neverplay.blocked", video.Title),
 		Flags:   64,
 	}}
 }
@@ -173,7 +282,7 @@ func (manager *Manager) filterBlocked(guildID string, videos []youtube.VideoResp
 func (manager *Manager) handleFavorite(interaction *Interaction) Response {
 	db := manager.Controller.GetDB()
 	if db == nil {
-		return Response{Type: 4, Data: ResponseData{Content: "Database is not available.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
 	}
 
 	player := manager.Controller.GetPlayer(interaction.GuildID)
@@ -181,7 +290,7 @@ func (manager *Manager) handleFavorite(interaction *Interaction) Response {
 	currentSong := player.GetCurrentSong()
 	currentItem := player.GetCurrentItem()
 	if currentSong == nil || currentItem == nil {
-		return Response{Type: 4, Data: ResponseData{Content: "Nothing is currently playing.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.nothing_playing"), Flags: 64}}
 	}
 
 	userID := interaction.Member.User.ID
@@ -189,19 +298,19 @@ func (manager *Manager) handleFavorite(interaction *Interaction) Response {
 
 	if db.IsFavorite(userID, interaction.GuildID, song.Video.VideoID) {
 		return Response{Type: 4, Data: ResponseData{
-			Content: fmt.Sprintf("**%s** is already in your favorites.", song.Video.Title),
+			Content: tr(interaction, "favorites.already_saved", song.Video.Title),
 			Flags:   64,
 		}}
 	}
 
-	videoURL := "https://www.youtube.com/watch?v=" + song.Video.VideoID
-	if err := db.AddFavorite(userID, interaction.GuildID, song.Video.VideoID, song.Video.Title, videoURL); err != nil {
+	videoURL := song.Video.PageURL()
+	if err := db.AddFavorite(userID, interaction.GuildID, song.Video.VideoID, song.Video.Title, videoURL, song.Video.Source); err != nil {
 		log.Errorf("Error adding favorite: %v", err)
-		return Response{Type: 4, Data: ResponseData{Content: "Failed to save favorite. Try again.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "favorites.save_failed"), Flags: 64}}
 	}
 
 	return Response{Type: 4, Data: ResponseData{
-		Content: fmt.Sprintf("❤️ Added **%s** to your favorites.", song.Video.Title),
+		Content: tr(interaction, "favorites.saved", song.Video.Title),
 		Flags:   64,
 	}}
 }
@@ -209,36 +318,33 @@ func (manager *Manager) handleFavorite(interaction *Interaction) Response {
 func (manager *Manager) handleFavorites(interaction *Interaction) Response {
 	db := manager.Controller.GetDB()
 	if db == nil {
-		return Response{Type: 4, Data: ResponseData{Content: "Database is not available.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
 	}
 
 	userID := interaction.Member.User.ID
-	records, err := db.GetFavorites(userID, interaction.GuildID, 25)
+	records, err := db.GetFavorites(userID, interaction.GuildID, favoritesListLimit)
 	if err != nil {
 		log.Errorf("Error fetching favorites: %v", err)
-		return Response{Type: 4, Data: ResponseData{Content: "Failed to fetch favorites.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "favorites.fetch_failed"), Flags: 64}}
 	}
 
 	if len(records) == 0 {
 		return Response{Type: 4, Data: ResponseData{
-			Content: "You haven't saved any favorites yet. Use `/favorite` while a song is playing!",
+			Content: tr(interaction, "favorites.none"),
 			Flags:   64,
 		}}
 	}
 
-	var sb strings.Builder
-	sb.WriteString("❤️ **Your Favorites**\n\n")
-	for i, r := range records {
-		sb.WriteString(fmt.Sprintf("`%d.` [%s](%s)\n", i+1, r.Title, r.URL))
-	}
-
-	return Response{Type: 4, Data: ResponseData{Content: sb.String()}}
+	// Ephemeral: the menu queues from whoever clicks it, so only the owner
+	// should see it.
+	content, components := favoritesMessage(interaction, records)
+	return Response{Type: 4, Data: ResponseData{Content: content, Components: components, Flags: 64}}
 }
 
 func (manager *Manager) handleUnfavorite(interaction *Interaction) Response {
 	db := manager.Controller.GetDB()
 	if db == nil {
-		return Response{Type: 4, Data: ResponseData{Content: "Database is not available.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
 	}
 
 	userID := interaction.Member.User.ID
@@ -248,162 +354,23 @@ func (manager *Manager) handleUnfavorite(interaction *Interaction) Response {
 		if opt.Name == "song_number" {
 			n, err := strconv.Atoi(opt.Value)
 			if err != nil || n < 1 {
-				return Response{Type: 4, Data: ResponseData{Content: "Invalid song number.", Flags: 64}}
+				return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "playlists.invalid_position"), Flags: 64}}
 			}
 			songNumber = n
 			break
 		}
 	}
 	if songNumber == 0 {
-		return Response{Type: 4, Data: ResponseData{Content: "Please provide a song number from `/favorites`.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "favorites.number_missing"), Flags: 64}}
 	}
 
 	title, err := db.RemoveFavoriteByIndex(userID, interaction.GuildID, songNumber)
 	if err != nil {
-		return Response{Type: 4, Data: ResponseData{Content: "That number isn't in your favorites list.", Flags: 64}}
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "favorites.number_unknown"), Flags: 64}}
 	}
 
 	return Response{Type: 4, Data: ResponseData{
-		Content: fmt.Sprintf("Removed **%s** from your favorites.", title),
+		Content: tr(interaction, "favorites.removed", title),
 		Flags:   64,
 	}}
 }
-
-func (manager *Manager) handleRecommend(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
-	defer func() {
-		if err := recover(); err != nil {
-			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleRecommend: %v", err))
-			transaction.Status = sentry.SpanStatusInternalError
-		}
-		transaction.Finish()
-	}()
-
-	if !config.Config.Gemini.Enabled {
-		manager.SendFollowup(ctx, interaction, "", "AI recommendations require Gemini to be enabled.", true)
-		return
-	}
-
-	db := manager.Controller.GetDB()
-	if db == nil {
-		manager.SendFollowup(ctx, interaction, "", "No database available. Play some songs first to build listening history!", true)
-		return
-	}
-
-	history, err := db.GetHistory(interaction.GuildID, 10)
-	if err != nil {
-		log.Errorf("Error fetching history for recommend: %v", err)
-		sentryhelper.CaptureException(ctx, err)
-		manager.SendFollowup(ctx, interaction, "", "Failed to fetch listening history.", true)
-		return
-	}
-
-	log.Infof("Recommend: fetched %d history records for guild %s", len(history), interaction.GuildID)
-
-	if len(history) < 3 {
-		manager.SendFollowup(ctx, interaction, "", "Need at least 3 songs in history to make a smart recommendation. Play some more first! 🎵", true)
-		return
-	}
-
-	var songTitles []string
-	historyVideoIDs := make(map[string]bool)
-	for _, r := range history {
-		songTitles = append(songTitles, r.Title)
-		if r.VideoID != "" {
-			historyVideoIDs[r.VideoID] = true
-		}
-	}
-
-	// Merge never-play blocked videos so they are excluded from recommendation picks.
-	if blocked, err := db.GetBlockedVideoIDs(interaction.GuildID); err == nil {
-		for id := range blocked {
-			historyVideoIDs[id] = true
-		}
-	}
-
-	query := gemini.GenerateSongRecommendation(ctx, songTitles)
-	if query == "" {
-		log.Warnf("Recommend: Gemini returned empty query despite %d history records for guild %s", len(history), interaction.GuildID)
-		manager.SendFollowup(ctx, interaction, "", "Couldn't generate a recommendation right now. Try again in a moment! 🤖", true)
-		return
-	}
-
-	log.Infof("Recommend: Gemini query='%s' for guild %s", query, interaction.GuildID)
-
-	// Voice check like in other handlers
-	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
-	if err != nil {
-		log.Errorf("Error getting voice state: %v", err)
-		sentryhelper.CaptureException(ctx, err)
-		manager.SendError(interaction, "Error getting voice state: "+err.Error(), true)
-		return
-	}
-
-	if voiceState == nil {
-		manager.SendFollowup(ctx, interaction, "user not in voice channel", "Join a voice channel first! 🎤", true)
-		return
-	}
-
-	player := manager.Controller.GetPlayer(interaction.GuildID)
-
-	if player.ShouldJoinVoice(voiceState.ChannelID) {
-		err := player.JoinVoiceChannel(interaction.Member.User.ID)
-		if err != nil {
-			errStr := err.Error()
-			if errStr == "voice state not found" {
-				manager.SendFollowup(ctx, interaction, "", "You gotta join a voice channel first!", true)
-				return
-			}
-			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Error joining voice channel: "+errStr, true)
-			return
-		}
-	}
-
-	// Search YouTube
-	videos := youtube.Query(ctx, query)
-	log.Infof("Recommend: YouTube returned %d results for query='%s' guild=%s", len(videos), query, interaction.GuildID)
-	if len(videos) == 0 {
-		manager.SendFollowup(ctx, interaction, "", "No suitable tracks found for this recommendation. Try again soon! 🔍", true)
-		return
-	}
-
-	// Pick first result not already in history
-	var video youtube.VideoResponse
-	picked := false
-	for _, v := range videos {
-		if !historyVideoIDs[v.VideoID] {
-			video = v
-			picked = true
-			break
-		}
-	}
-	if !picked {
-		// All results are in history — just take the top result rather than failing
-		log.Warnf("Recommend: all YouTube results already in history, using top result anyway for guild %s", interaction.GuildID)
-		video = videos[0]
-	}
-	log.Debugf("Recommend selected video: %s (ID: %s)", video.Title, video.VideoID)
-
-	// Determine if first song
-	firstSongQueued := player.IsEmpty() && !player.Player.IsPlaying() && player.GetCurrentSong() == nil
-
-	var backupMsg string
-	if firstSongQueued {
-		backupMsg = fmt.Sprintf("🎵 **Now spinning** your AI DJ pick: **%s**\\n\\n(First song - give it a sec to load!)", video.Title)
-	} else {
-		backupMsg = fmt.Sprintf("🎵 **Queued up** the AI DJ recommendation: **%s**\\n\\nGood vibes incoming! ✨", video.Title)
-	}
-
-	// Queue it
-	player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, nil)
-
-	// DJ personality response
-	aiPrompt := fmt.Sprintf(`User %s used /recommend.
-
-AI DJ queued **%s** based on recent listening history.
-
-Announce it like a cool DJ - excited, conversational, with music nerd vibe.`,
-		interaction.Member.User.Username, video.Title)
-
-	manager.SendFollowup(ctx, interaction, aiPrompt, backupMsg, false)
-}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/database"
+)
+
+func TestFormatPlaytime(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{45 * time.Minute, "45m"},
+		{3*time.Hour + 12*time.Minute + 20*time.Second, "3h 12m"},
+		{50 * time.Hour, "2d 2h"},
+	}
+	for _, tt := range tests {
+		if got := formatPlaytime(tt.d); got != tt.want {
+			t.Errorf("formatPlaytime(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestBuildStatsEmbed(t *testing.T) {
+	embed := buildStatsEmbed(&Interaction{}, &database.GuildStats{
+		SongsPlayed: 8,
+		UniqueSongs: 5,
+		Playtime:    90 * time.Minute,
+		Skips:       2,
+		TopRequesters: []database.RequesterStats{
+			{UserID: "42", Plays: 1, Playtime: 3 * time.Minute},
+		},
+	})
+
+	if got := embed.Fields[3].Value; got != "2 (25%)" {
+		t.Errorf("skips field = %q, want %q", got, "2 (25%)")
+	}
+	if got := embed.Fields[4].Value; got != "**1.** <@42> · 1 song · 3m\n" {
+		t.Errorf("top requesters = %q", got)
+	}
+	if len(embed.Fields) != 5 {
+		t.Errorf("most skipped field should be omitted when empty, got %d fields", len(embed.Fields))
+	}
+}
+
+func TestMostSkippedMessage(t *testing.T) {
+	got := mostSkippedMessage(&Interaction{}, []database.MostSkippedRecord{
+		{Title: "Too Long", Skips: 3, Plays: 4, SkippedAt: 15},
+		{Title: "Live Set", Skips: 1, Plays: 1, SkippedAt: -1},
+	})
+	for _, want := range []string{
+		"**1.** Too Long\n　　↳ skipped **3** of 4 plays · usually 15% in\n",
+		"**2.** Live Set\n　　↳ skipped **1** of 1 play\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message missing %q:\n%s", want, got)
+		}
+	}
+}
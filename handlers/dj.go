@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+	"beatbot/discord"
+	"beatbot/i18n"
+)
+
+// handleDJRole routes /djrole: set or clear the guild's DJ role, or restrict
+// commands to DJs. Songs queued by members with the role jump ahead of
+// regular requests. Restricted to Manage Server via DefaultMemberPermissions
+// in Commands.
+func (manager *Manager) handleDJRole(interaction *Interaction) Response {
+	switch subcommandName(interaction) {
+	case "set":
+		var roleID string
+		for _, opt := range commandOptions(interaction) {
+			if opt.Name == "role" {
+				roleID = opt.Value
+			}
+		}
+		return manager.setDJRole(interaction, roleID)
+	case "clear":
+		return manager.setDJRole(interaction, "")
+	case "restrict":
+		return manager.handleDJRestrict(interaction)
+	default:
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "dj.unknown_command"), Flags: 64}}
+	}
+}
+
+// setDJRole saves the guild's DJ role; "" clears it.
+func (manager *Manager) setDJRole(interaction *Interaction, roleID string) Response {
+	guildID := interaction.GuildID
+	player := manager.Controller.GetPlayer(guildID)
+
+	if player.DB != nil {
+		if err := player.DB.SetGuildSetting(guildID, "dj_role_id", roleID); err != nil {
+			log.Errorf("Failed to save DJ role: %v", err)
+			return Response{
+				Type: 4,
+				Data: ResponseData{
+					Content: tr(interaction, "dj.role_save_failed"),
+					Flags:   64,
+				},
+			}
+		}
+	}
+	player.SetDJRoleID(roleID)
+
+	msg := tr(interaction, "dj.role_cleared")
+	if roleID != "" {
+		msg = tr(interaction, "dj.role_set", roleID)
+	}
+	if restricted := player.GetDJOnlyCommands(); len(restricted) > 0 {
+		if roleID != "" {
+			msg += tr(interaction, "dj.role_set_restricted", formatCommandList(interaction, restricted))
+		} else {
+			msg += tr(interaction, "dj.role_cleared_restricted", formatCommandList(interaction, restricted))
+		}
+	}
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content:         msg,
+			AllowedMentions: noPings,
+		},
+	}
+}
+
+// handleDJRestrict limits one of DJRestrictableCommands (or all of them) to
+// DJs and server managers, or lifts the limit.
+func (manager *Manager) handleDJRestrict(interaction *Interaction) Response {
+	guildID := interaction.GuildID
+	player := manager.Controller.GetPlayer(guildID)
+
+	var command string
+	enabled := true
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "command":
+			command = opt.Value
+		case "enabled":
+			enabled = opt.Value == "true"
+		}
+	}
+
+	targets := []string{command}
+	if command == "all" {
+		targets = controller.DJRestrictableCommands
+	} else if !slices.Contains(controller.DJRestrictableCommands, command) {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "dj.not_restrictable"), Flags: 64}}
+	}
+
+	restricted := player.GetDJOnlyCommands()
+	for _, target := range targets {
+		if enabled && !slices.Contains(restricted, target) {
+			restricted = append(restricted, target)
+		}
+		if !enabled {
+			restricted = slices.DeleteFunc(restricted, func(c string) bool { return c == target })
+		}
+	}
+
+	if player.DB != nil {
+		if err := player.DB.SetGuildSetting(guildID, "dj_only_commands", strings.Join(restricted, ",")); err != nil {
+			log.Errorf("Failed to save DJ-only commands: %v", err)
+			return Response{
+				Type: 4,
+				Data: ResponseData{
+					Content: tr(interaction, "dj.restrict_save_failed"),
+					Flags:   64,
+				},
+			}
+		}
+	}
+	player.SetDJOnlyCommands(restricted)
+
+	restricted = player.GetDJOnlyCommands()
+	var msg string
+	switch {
+	case len(restricted) == 0:
+		msg = tr(interaction, "dj.unrestricted")
+	case player.GetDJRoleID() == "":
+		msg = tr(interaction, "dj.restricted_managers", formatCommandList(interaction, restricted))
+	default:
+		msg = tr(interaction, "dj.restricted", formatCommandList(interaction, restricted), player.GetDJRoleID())
+	}
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content:         msg,
+			AllowedMentions: noPings,
+		},
+	}
+}
+
+// formatCommandList renders command names as "/skip, /clear and /volume".
+func formatCommandList(interaction *Interaction, commands []string) string {
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = "/" + command
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " " + tr(interaction, "common.and") + " " + names[len(names)-1]
+}
+
+// requireDJ refuses command when this guild restricts it to DJs and the
+// member is neither a DJ nor a server manager. ok is false when the caller
+// should return the refusal instead of running the command.
+func (manager *Manager) requireDJ(interaction *Interaction, command string) (refusal Response, ok bool) {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if !player.IsDJOnly(command) {
+		return Response{}, true
+	}
+	userID := interaction.Member.User.ID
+	player.SetMemberRoles(userID, interaction.Member.Roles)
+	if player.IsDJ(userID) || canManageGuild(interaction.Member) {
+		return Response{}, true
+	}
+
+	msg := tr(interaction, "dj.managers_only", command)
+	if roleID := player.GetDJRoleID(); roleID != "" {
+		msg = tr(interaction, "dj.djs_only", roleID, command)
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content:         msg,
+			Flags:           64,
+			AllowedMentions: noPings,
+		},
+	}, false
+}
+
+const (
+	permissionAdministrator = 1 << 3
+	permissionManageGuild   = 1 << 5
+
+	defaultGuestDJDuration = 30 * time.Minute
+	maxGuestDJDuration     = 12 * time.Hour
+)
+
+// canManageGuild reports whether the member has Manage Server (or Administrator)
+// in the channel the interaction came from.
+func canManageGuild(member MemberData) bool {
+	perms, err := strconv.ParseInt(member.Permissions, 10, 64)
+	if err != nil {
+		return false
+	}
+	return perms&(permissionAdministrator|permissionManageGuild) != 0
+}
+
+// canGrantGuestDJ reports whether member may hand out guest DJ sessions:
+// DJs by role and server managers. Guests can't, or they could renew their
+// own grant forever and pass it around.
+func canGrantGuestDJ(player *controller.GuildPlayer, member MemberData) bool {
+	return player.HasDJRole(member.User.ID) || canManageGuild(member)
+}
+
+// handleGuestDJ grants (or with duration "off", revokes) temporary DJ
+// permissions for a listening party: `/guestdj user:@someone duration:30m`.
+// Grants live in memory only and lapse on their own; a restart clears them.
+func (manager *Manager) handleGuestDJ(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	if !canGrantGuestDJ(player, interaction.Member) {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "dj.guest_not_allowed"),
+				Flags:   64,
+			},
+		}
+	}
+
+	var userID, durationOpt string
+	for _, opt := range interaction.Data.Options {
+		switch opt.Name {
+		case "user":
+			userID = opt.Value
+		case "duration":
+			durationOpt = strings.ToLower(strings.TrimSpace(opt.Value))
+		}
+	}
+
+	if userID == "" {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "dj.guest_no_user"),
+				Flags:   64,
+			},
+		}
+	}
+
+	if durationOpt == "off" || durationOpt == "0" {
+		if !player.RevokeGuestDJ(userID) {
+			return Response{
+				Type: 4,
+				Data: ResponseData{
+					Content:         tr(interaction, "dj.guest_not_guest", userID),
+					Flags:           64,
+					AllowedMentions: noPings,
+				},
+			}
+		}
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content:         tr(interaction, "dj.guest_revoked", userID),
+				AllowedMentions: noPings,
+			},
+		}
+	}
+
+	duration := defaultGuestDJDuration
+	if durationOpt != "" {
+		d, err := time.ParseDuration(durationOpt)
+		if err != nil || d < time.Minute {
+			return Response{
+				Type: 4,
+				Data: ResponseData{
+					Content: tr(interaction, "dj.guest_bad_duration"),
+					Flags:   64,
+				},
+			}
+		}
+		duration = min(d, maxGuestDJDuration)
+	}
+
+	expiry := player.GrantGuestDJ(userID, duration)
+
+	// Announce the end of the session in the same channel, unless it was
+	// extended or revoked in the meantime.
+	channelID := interaction.ChannelID
+	time.AfterFunc(duration, func() {
+		if !player.ExpireGuestDJ(userID, expiry) {
+			return // revoked or re-granted with a new expiry
+		}
+		if player.IsDJ(userID) {
+			return // holds the DJ role anyway, nothing changed for them
+		}
+		msg := i18n.T(interaction.GuildLocale, "dj.guest_expired", userID)
+		if _, err := discord.SendChannelMessage(channelID, msg, nil, nil); err != nil {
+			log.Warnf("Failed to announce guest DJ expiry: %v", err)
+		}
+	})
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content:         tr(interaction, "dj.guest_granted", userID, formatGuestDJDuration(duration), expiry.Unix()),
+			AllowedMentions: &AllowedMentions{Parse: []string{"users"}},
+		},
+	}
+}
+
+// formatGuestDJDuration renders durations like "30m", "1h" or "1h30m"
+// without the trailing "0s" that time.Duration.String adds.
+func formatGuestDJDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
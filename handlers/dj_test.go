@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"beatbot/controller"
+)
+
+func TestFormatCommandList(t *testing.T) {
+	tests := []struct {
+		commands []string
+		want     string
+	}{
+		{[]string{"skip"}, "/skip"},
+		{[]string{"skip", "volume"}, "/skip and /volume"},
+		{[]string{"skip", "clear", "reset"}, "/skip, /clear and /reset"},
+	}
+	for _, tt := range tests {
+		if got := formatCommandList(&Interaction{}, tt.commands); got != tt.want {
+			t.Errorf("formatCommandList(%v) = %q, want %q", tt.commands, got, tt.want)
+		}
+	}
+}
+
+func TestCanManageGuild(t *testing.T) {
+	tests := []struct {
+		permissions string
+		want        bool
+	}{
+		{"32", true},       // Manage Server
+		{"8", true},        // Administrator
+		{"2048", false},    // Send Messages
+		{"", false},        // missing
+		{"garbage", false}, // unparseable
+	}
+	for _, tt := range tests {
+		if got := canManageGuild(MemberData{Permissions: tt.permissions}); got != tt.want {
+			t.Errorf("canManageGuild(%q) = %v, want %v", tt.permissions, got, tt.want)
+		}
+	}
+}
+
+func TestCanGrantGuestDJ(t *testing.T) {
+	player := &controller.GuildPlayer{Queue: &controller.GuildQueue{}}
+	player.SetDJRoleID("djrole")
+	player.SetMemberRoles("dj", []string{"djrole"})
+	player.GrantGuestDJ("guest", time.Hour)
+
+	member := func(id, permissions string) MemberData {
+		return MemberData{User: UserData{ID: id}, Permissions: permissions}
+	}
+	if !canGrantGuestDJ(player, member("dj", "")) {
+		t.Error("a DJ by role should be able to grant")
+	}
+	if !canGrantGuestDJ(player, member("admin", "32")) {
+		t.Error("a server manager should be able to grant")
+	}
+	if !player.IsDJ("guest") {
+		t.Fatal("guest should be a DJ during the grant")
+	}
+	if canGrantGuestDJ(player, member("guest", "")) {
+		t.Error("a guest DJ must not be able to grant or renew guest DJ")
+	}
+	if canGrantGuestDJ(player, member("listener", "")) {
+		t.Error("a regular listener must not be able to grant")
+	}
+}
+
+func TestButtonCommandsAreRestrictable(t *testing.T) {
+	for action, command := range buttonCommands {
+		if !slices.Contains(controller.DJRestrictableCommands, command) && !voiceControlCommands[command] {
+			t.Errorf("button %q follows %q, which no restriction covers", action, command)
+		}
+	}
+	for _, action := range []string{"skip", "volup", "voldown", "clear_confirm", "reset_confirm"} {
+		if command := buttonCommands[action]; !slices.Contains(controller.DJRestrictableCommands, command) {
+			t.Errorf("button %q should follow a DJ-restrictable command, got %q", action, command)
+		}
+	}
+}
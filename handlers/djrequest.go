@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+	"beatbot/discord"
+	"beatbot/gemini"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+// djPick is a /dj song found on YouTube, with the AI's reason for it.
+type djPick struct {
+	video youtube.VideoResponse
+	why   string
+}
+
+// handleDJ reads a free-form request like "something chill for studying",
+// queues songs that fit after whatever's already queued, and has the AI
+// explain its picks. Unlike /request it leaves the queue and radio alone.
+func (manager *Manager) handleDJ(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleDJ: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	if !config.Config.Gemini.Enabled {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "djrequest.needs_gemini"), true)
+		return
+	}
+
+	var request string
+	for _, opt := range interaction.Data.Options {
+		if opt.Name == "request" {
+			request = strings.TrimSpace(opt.Value)
+			break
+		}
+	}
+	if request == "" {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playback.request_empty"), true)
+		return
+	}
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if !manager.joinRequesterVoice(ctx, interaction, player) {
+		return
+	}
+
+	recentEntries := player.SongHistory.GetRecent(5)
+	recentSongs := make([]string, len(recentEntries))
+	for i, e := range recentEntries {
+		recentSongs[i] = e.Title
+	}
+
+	intent := gemini.InterpretDJRequest(ctx, request, recentSongs)
+	if intent == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "djrequest.not_understood"), true)
+		return
+	}
+
+	// Play history isn't excluded: the listener may have asked for a
+	// specific song they heard before.
+	exclude := make(map[string]bool)
+	if item := player.GetCurrentItem(); item != nil {
+		exclude[item.Video.VideoID] = true
+	}
+	for _, item := range player.GetQueueSnapshot() {
+		exclude[item.Video.VideoID] = true
+	}
+	if db := manager.Controller.GetDB(); db != nil {
+		if blocked, err := db.GetBlockedVideoIDs(interaction.GuildID); err == nil {
+			for id := range blocked {
+				exclude[id] = true
+			}
+		}
+	}
+
+	queries := make([]string, len(intent.Picks))
+	for i, pick := range intent.Picks {
+		queries[i] = pick.Query
+	}
+	var picks []djPick
+	for i, video := range lookupQueries(ctx, queries, exclude) {
+		if video == nil || player.TooLong(*video) {
+			continue
+		}
+		picks = append(picks, djPick{video: *video, why: intent.Picks[i].Why})
+	}
+	log.Infof("DJ: resolved %d of %d picks %q for guild %s", len(picks), len(queries), queries, interaction.GuildID)
+	if len(picks) == 0 {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playback.request_no_results"), true)
+		return
+	}
+
+	for _, p := range picks {
+		player.Add(ctx, p.video, interaction.Member.User.ID, interaction.Token, manager.AppID, nil)
+	}
+
+	discord.SendFollowup(&discord.FollowUpRequest{
+		Token:   interaction.Token,
+		AppID:   manager.AppID,
+		UserID:  interaction.Member.User.ID,
+		Content: djMessage(interaction, request, intent, picks),
+	})
+}
+
+// djMessage announces what a /dj request queued: the request, how the AI
+// read it, its explanation, then each song with why it fits.
+func djMessage(interaction *Interaction, request string, intent *gemini.DJIntent, picks []djPick) string {
+	var sb strings.Builder
+	sb.WriteString(tr(interaction, "djrequest.header", truncateRunes(request, 200)) + "\n")
+	if tags := intent.Tags(); len(tags) > 0 {
+		fmt.Fprintf(&sb, "-# %s\n", strings.Join(tags, " · "))
+	}
+	if intent.Summary != "" {
+		sb.WriteString("\n" + intent.Summary + "\n")
+	}
+	sb.WriteString("\n")
+	for i, p := range picks {
+		line := fmt.Sprintf("**%d.** [%s](<%s>)", i+1, p.video.Title, p.video.PageURL())
+		if p.why != "" {
+			line += " — " + p.why
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"beatbot/gemini"
+	"beatbot/youtube"
+)
+
+func TestDJMessage(t *testing.T) {
+	intent := &gemini.DJIntent{Genre: "lo-fi", Mood: "chill", Summary: "Mellow beats to keep you focused."}
+	picks := []djPick{
+		{video: youtube.VideoResponse{VideoID: "aaaaaaaaaaa", Title: "Feather"}, why: "soft and steady"},
+		{video: youtube.VideoResponse{VideoID: "bbbbbbbbbbb", Title: "Affection"}},
+	}
+	got := djMessage(&Interaction{}, "something chill for studying", intent, picks)
+
+	for _, want := range []string{
+		"*something chill for studying*",
+		"-# lo-fi · chill\n",
+		"Mellow beats to keep you focused.",
+		"**1.** [Feather](<https://www.youtube.com/watch?v=aaaaaaaaaaa>) — soft and steady\n",
+		"**2.** [Affection](<https://www.youtube.com/watch?v=bbbbbbbbbbb>)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("djMessage missing %q:\n%s", want, got)
+		}
+	}
+}
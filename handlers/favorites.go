@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/database"
+	"beatbot/discord"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+const (
+	// favoritesListLimit is how many favorites /favorites shows, which is
+	// also Discord's cap on select menu options.
+	favoritesListLimit = 25
+	// maxPlayFavorites caps how many favorites /playfavorites queues at once.
+	maxPlayFavorites = 100
+)
+
+// savedVideo rebuilds a queueable track from what favorites and playlists
+// store. Only non-YouTube tracks need their page URL to stream.
+func savedVideo(videoID, title, pageURL, source string) youtube.VideoResponse {
+	video := youtube.VideoResponse{VideoID: videoID, Title: title, Source: source}
+	if !video.IsYouTube() {
+		video.URL = pageURL
+	}
+	return video
+}
+
+// favoriteVideo turns a saved favorite back into something the player can
+// queue. Favorites saved before sources were recorded are YouTube videos.
+func favoriteVideo(r database.UserFavoriteRecord) youtube.VideoResponse {
+	return savedVideo(r.VideoID, r.Title, r.URL, r.Source)
+}
+
+// favoritesMessage renders a user's favorites as a numbered list (the
+// numbers /unfavorite takes) with a menu to queue one of them.
+func favoritesMessage(interaction *Interaction, records []database.UserFavoriteRecord) (string, []discordgo.MessageComponent) {
+	var sb strings.Builder
+	sb.WriteString(tr(interaction, "favorites.title") + "\n\n")
+	options := make([]discordgo.SelectMenuOption, 0, len(records))
+	for i, r := range records {
+		sb.WriteString(fmt.Sprintf("`%d.` [%s](%s)\n", i+1, r.Title, r.URL))
+		options = append(options, discordgo.SelectMenuOption{
+			Label: truncateRunes(fmt.Sprintf("%d. %s", i+1, r.Title), selectTextLimit),
+			Value: r.VideoID,
+		})
+	}
+	sb.WriteString("\n" + tr(interaction, "favorites.pick_hint"))
+	return sb.String(), discord.SelectMenu(interaction.GuildID, "favorite_pick", tr(interaction, "favorites.pick_placeholder"), options)
+}
+
+// handleFavoritePick queues the favorite picked from a /favorites menu. The
+// list stays up so more can be picked; joining voice and queueing happen
+// afterwards.
+func (manager *Manager) handleFavoritePick(interaction *Interaction) Response {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	userID := interaction.Member.User.ID
+	records, err := db.GetFavorites(userID, interaction.GuildID, favoritesListLimit)
+	if err != nil {
+		log.Errorf("Error fetching favorites: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "favorites.fetch_failed"), Flags: 64}}
+	}
+
+	var picked *database.UserFavoriteRecord
+	if len(interaction.Data.Values) == 1 {
+		for i := range records {
+			if records[i].VideoID == interaction.Data.Values[0] {
+				picked = &records[i]
+				break
+			}
+		}
+	}
+	if picked == nil {
+		return Response{Type: 4, Data: ResponseData{
+			Content: tr(interaction, "favorites.gone"),
+			Flags:   64,
+		}}
+	}
+	video := favoriteVideo(*picked)
+
+	go func() {
+		ctx, transaction := sentryhelper.StartCommandTransaction(
+			context.Background(),
+			"favorite_pick",
+			interaction.GuildID,
+			userID,
+		)
+		defer func() {
+			if err := recover(); err != nil {
+				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleFavoritePick: %v", err))
+				transaction.Status = sentry.SpanStatusInternalError
+			}
+			transaction.Finish()
+		}()
+
+		if blocked := manager.filterBlocked(interaction.GuildID, []youtube.VideoResponse{video}); len(blocked) == 0 {
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "favorites.blocked", video.Title), true)
+			return
+		}
+
+		player := manager.Controller.GetPlayer(interaction.GuildID)
+		if !manager.joinRequesterVoice(ctx, interaction, player) {
+			return
+		}
+		manager.queueVideo(ctx, interaction, player, video, nil, "favorite")
+	}()
+
+	content, components := favoritesMessage(interaction, records)
+	return Response{Type: 7, Data: ResponseData{
+		Content:    content,
+		Components: components,
+	}}
+}
+
+// onPlayFavorites queues all of the user's favorites, oldest first unless
+// shuffle is set. Songs already queued or blocked are left out.
+func (manager *Manager) onPlayFavorites(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onPlayFavorites: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	db := manager.Controller.GetDB()
+	if db == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "common.db_unavailable"), true)
+		return
+	}
+
+	shuffle := false
+	for _, opt := range commandOptions(interaction) {
+		if opt.Name == "shuffle" {
+			shuffle = opt.Value == "true"
+		}
+	}
+
+	records, err := db.GetFavorites(interaction.Member.User.ID, interaction.GuildID, maxPlayFavorites)
+	if err != nil {
+		log.Errorf("Error fetching favorites: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "favorites.fetch_failed"), true)
+		return
+	}
+	if len(records) == 0 {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "favorites.none"), true)
+		return
+	}
+
+	// GetFavorites is newest first; play in the order they were saved.
+	videos := make([]youtube.VideoResponse, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		videos = append(videos, favoriteVideo(records[i]))
+	}
+	if shuffle {
+		rand.Shuffle(len(videos), func(i, j int) {
+			videos[i], videos[j] = videos[j], videos[i]
+		})
+	}
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	videosToQueue := dropQueued(player, manager.filterBlocked(interaction.GuildID, videos))
+	if len(videosToQueue) == 0 {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "favorites.all_skipped"), true)
+		return
+	}
+
+	if !manager.joinRequesterVoice(ctx, interaction, player) {
+		return
+	}
+
+	for _, video := range videosToQueue {
+		player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, nil)
+	}
+
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "queue",
+		Message:  fmt.Sprintf("Queued %d favorites", len(videosToQueue)),
+		Level:    sentry.LevelInfo,
+	})
+
+	key := "favorites.queued"
+	if shuffle {
+		key = "favorites.queued_shuffled"
+	}
+	response := tr(interaction, key, len(videosToQueue))
+	if skipped := len(records) - len(videosToQueue); skipped > 0 {
+		response += " " + tr(interaction, "common.skipped_count", skipped)
+	}
+	manager.SendFollowup(ctx, interaction, fmt.Sprintf("Queued %d of the user's saved favorite songs", len(videosToQueue)), response, false)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"beatbot/database"
+)
+
+func TestFavoriteVideo(t *testing.T) {
+	yt := favoriteVideo(database.UserFavoriteRecord{VideoID: "abc", Title: "Song", URL: "https://www.youtube.com/watch?v=abc"})
+	if !yt.IsYouTube() || yt.URL != "" {
+		t.Errorf("YouTube favorite = %+v, want no URL so the ID is streamed", yt)
+	}
+
+	sc := favoriteVideo(database.UserFavoriteRecord{VideoID: "123", Title: "Song", URL: "https://soundcloud.com/a/b", Source: "soundcloud"})
+	if sc.IsYouTube() || sc.URL != "https://soundcloud.com/a/b" {
+		t.Errorf("SoundCloud favorite = %+v, want its page URL kept", sc)
+	}
+}
+
+func TestFavoritesMessage(t *testing.T) {
+	records := []database.UserFavoriteRecord{
+		{VideoID: "a", Title: "First", URL: "https://www.youtube.com/watch?v=a"},
+		{VideoID: "b", Title: strings.Repeat("x", 150), URL: "https://www.youtube.com/watch?v=b"},
+	}
+	content, components := favoritesMessage(&Interaction{GuildID: "g1"}, records)
+	if !strings.Contains(content, "`1.` [First](https://www.youtube.com/watch?v=a)") {
+		t.Errorf("content missing numbered entry: %q", content)
+	}
+
+	row := components[0].(discordgo.ActionsRow)
+	menu := row.Components[0].(discordgo.SelectMenu)
+	if menu.CustomID != "np:favorite_pick:g1" {
+		t.Errorf("CustomID = %q", menu.CustomID)
+	}
+	if len(menu.Options) != 2 || menu.Options[0].Value != "a" || menu.Options[0].Label != "1. First" {
+		t.Errorf("options = %+v", menu.Options)
+	}
+	if n := len([]rune(menu.Options[1].Label)); n > selectTextLimit {
+		t.Errorf("label of %d runes exceeds Discord's limit", n)
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+)
+
+// handleForgetMe asks before deleting everything the bot keeps about the
+// invoker, in every server.
+func (manager *Manager) handleForgetMe(interaction *Interaction) Response {
+	if manager.Controller.GetDB() == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+	return manager.confirmPrompt(interaction, "forgetme",
+		tr(interaction, "forgetme.confirm"), tr(interaction, "forgetme.confirm_button"))
+}
+
+// handleForgetMeConfirm deletes the invoker's data once they've confirmed.
+func (manager *Manager) handleForgetMeConfirm(interaction *Interaction) Response {
+	if expired, ok := manager.answerPrompt(interaction, "forgetme", "forgetme"); !ok {
+		return expired
+	}
+	userID := interaction.Member.User.ID
+	if err := manager.Controller.GetDB().ForgetUser(userID); err != nil {
+		log.Errorf("Error deleting data for user %s: %v", userID, err)
+		return Response{Type: 7, Data: ResponseData{
+			Content:    tr(interaction, "forgetme.failed"),
+			Components: discord.DisabledButton(tr(interaction, "forgetme.failed_button")),
+		}}
+	}
+	// Players cache clip consent; stop recording them now rather than on
+	// the next voice join.
+	manager.Controller.ForgetClipConsent(userID)
+	log.Infof("Deleted stored data for user %s at their request", userID)
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "forgetme.deleted"),
+		Components: discord.DisabledButton(tr(interaction, "forgetme.deleted_button")),
+	}}
+}
+
+func (manager *Manager) handleForgetMeCancel(interaction *Interaction) Response {
+	if expired, ok := manager.answerPrompt(interaction, "forgetme", "forgetme"); !ok {
+		return expired
+	}
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "forgetme.kept"),
+		Components: discord.DisabledButton(tr(interaction, "common.canceled_button")),
+	}}
+}
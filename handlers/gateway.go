@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// interactionResponder posts to Discord's REST API; *discordgo.Session
+// satisfies it.
+type interactionResponder interface {
+	RequestWithBucketID(method, urlStr string, data interface{}, bucketID string, options ...discordgo.RequestOption) ([]byte, error)
+}
+
+// ListenGateway handles interactions that arrive over the bot's gateway
+// websocket, for deployments Discord can't reach over HTTP. They go through
+// HandleInteraction exactly like the /discord/interactions endpoint; only
+// the reply travels differently. The returned function stops listening.
+func (manager *Manager) ListenGateway(session *discordgo.Session) func() {
+	return session.AddHandler(func(s *discordgo.Session, event *discordgo.Event) {
+		if event.Type != "INTERACTION_CREATE" {
+			return
+		}
+		if err := respondOverGateway(s, event.RawData, manager.HandleInteraction); err != nil {
+			log.Errorf("Error answering gateway interaction: %v", err)
+		}
+	})
+}
+
+// respondOverGateway parses a raw INTERACTION_CREATE payload, handles it,
+// and posts the response to the interaction's callback. The payload is the
+// same JSON Discord posts to the webhook endpoint, so it's parsed the same
+// way rather than through discordgo's types.
+func respondOverGateway(responder interactionResponder, raw []byte, handle func(*Interaction) Response) error {
+	var interaction Interaction
+	if err := json.Unmarshal(raw, &interaction); err != nil {
+		return fmt.Errorf("parsing interaction: %w", err)
+	}
+
+	response := handle(&interaction)
+	endpoint := discordgo.EndpointInteractionResponse(interaction.ID, interaction.Token)
+	if _, err := responder.RequestWithBucketID("POST", endpoint, response, endpoint); err != nil {
+		return fmt.Errorf("responding to %s: %w", interaction.Data.Name, err)
+	}
+	return nil
+}
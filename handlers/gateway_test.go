@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type fakeResponder struct {
+	endpoint string
+	body     []byte
+}
+
+func (f *fakeResponder) RequestWithBucketID(method, urlStr string, data interface{}, bucketID string, options ...discordgo.RequestOption) ([]byte, error) {
+	f.endpoint = urlStr
+	body, err := json.Marshal(data)
+	f.body = body
+	return nil, err
+}
+
+func TestRespondOverGateway(t *testing.T) {
+	raw := `{"id":"1234","application_id":"99","type":2,"token":"tok","guild_id":"g1",
+		"data":{"name":"queue","options":[{"name":"query","type":3,"value":"blue monday"}]},
+		"member":{"user":{"id":"u1","username":"dj"}}}`
+
+	var got *Interaction
+	responder := &fakeResponder{}
+	err := respondOverGateway(responder, []byte(raw), func(interaction *Interaction) Response {
+		got = interaction
+		return Response{Type: 5, Data: ResponseData{Flags: 64}}
+	})
+	if err != nil {
+		t.Fatalf("respondOverGateway: %v", err)
+	}
+
+	if got.ID != "1234" || got.Data.Name != "queue" || got.Member.User.ID != "u1" || got.Data.Options[0].Value != "blue monday" {
+		t.Errorf("parsed interaction = %+v", got)
+	}
+	if responder.endpoint != discordgo.EndpointInteractionResponse("1234", "tok") {
+		t.Errorf("endpoint = %q", responder.endpoint)
+	}
+	// The reply is the same JSON the webhook endpoint would have returned.
+	if !strings.Contains(string(responder.body), `"type":5`) || !strings.Contains(string(responder.body), `"flags":64`) {
+		t.Errorf("body = %s", responder.body)
+	}
+}
+
+func TestRespondOverGatewayBadPayload(t *testing.T) {
+	handled := false
+	err := respondOverGateway(&fakeResponder{}, []byte(`{"id":`), func(*Interaction) Response {
+		handled = true
+		return Response{}
+	})
+	if err == nil || handled {
+		t.Errorf("err = %v, handled = %v; want a parse error and no handling", err, handled)
+	}
+}
+
+func TestVerifyDiscordRequestWithoutPublicKey(t *testing.T) {
+	// Gateway mode may run without DISCORD_PUBLIC_KEY; a stray request must
+	// be refused rather than panic in ed25519.Verify.
+	for _, key := range []string{"", "abcd"} {
+		manager := &Manager{PublicKey: key}
+		if manager.VerifyDiscordRequest(strings.Repeat("00", 64), "1700000000", []byte(`{"type":1}`)) {
+			t.Errorf("request verified with public key %q", key)
+		}
+	}
+}
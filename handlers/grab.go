@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	sentry "github.com/getsentry/sentry-go"
+
+	"beatbot/controller"
+	"beatbot/discord"
+	"beatbot/sentryhelper"
+)
+
+// buildGrabEmbed describes item for a /grab DM, noting where in the song it
+// was grabbed. YouTube links jump straight to that moment.
+func buildGrabEmbed(interaction *Interaction, item *controller.GuildQueueItem, position time.Duration) *discordgo.MessageEmbed {
+	link := item.Video.PageURL()
+	if item.Video.IsYouTube() {
+		link = fmt.Sprintf("https://youtu.be/%s?t=%d", item.Video.VideoID, int(position.Seconds()))
+	}
+
+	grabbedAt := discord.FormatDuration(position)
+	if duration := item.Duration(); duration > 0 {
+		grabbedAt += " / " + discord.FormatDuration(duration)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       item.Video.Title,
+		URL:         item.Video.PageURL(),
+		Description: tr(interaction, "grab.listen", link),
+		Color:       0x1DB954,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: tr(interaction, "grab.grabbed_at"), Value: grabbedAt, Inline: true},
+		},
+		Footer:    &discordgo.MessageEmbedFooter{Text: tr(interaction, "grab.footer")},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if item.Video.ChannelName != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: tr(interaction, "grab.channel"), Value: item.Video.ChannelName, Inline: true,
+		})
+	}
+	if thumbnail := item.Video.ThumbnailURL(); thumbnail != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: thumbnail}
+	}
+	return embed
+}
+
+// handleGrab DMs the current song to whoever asked, from /grab or the 💾
+// button on a now-playing card.
+func (manager *Manager) handleGrab(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	item := player.GetCurrentItem()
+	if item == nil {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "playback.nothing_playing"),
+				Flags:   64,
+			},
+		}
+	}
+	// Capture the moment of the click, not of the DM going out.
+	embed := buildGrabEmbed(interaction, item, player.Player.GetPosition())
+
+	go func() {
+		ctx, transaction := sentryhelper.StartCommandTransaction(
+			context.Background(),
+			"grab",
+			interaction.GuildID,
+			interaction.Member.User.ID,
+		)
+		defer func() {
+			if err := recover(); err != nil {
+				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleGrab: %v", err))
+				transaction.Status = sentry.SpanStatusInternalError
+			}
+			transaction.Finish()
+		}()
+
+		content := tr(interaction, "grab.sent", item.Video.Title)
+		if _, err := discord.SendDirectMessage(interaction.Member.User.ID, "", embed); err != nil {
+			if errors.Is(err, discord.ErrDMsClosed) {
+				content = tr(interaction, "grab.dms_closed")
+			} else {
+				sentryhelper.CaptureException(ctx, err)
+				content = tr(interaction, "grab.failed")
+			}
+		}
+		manager.SendFollowup(ctx, interaction, "", content, true)
+	}()
+
+	return Response{
+		Type: 5,
+		Data: ResponseData{
+			Flags: 64,
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/controller"
+	"beatbot/youtube"
+)
+
+func TestBuildGrabEmbed(t *testing.T) {
+	item := &controller.GuildQueueItem{
+		Video: youtube.VideoResponse{
+			VideoID:     "dQw4w9WgXcQ",
+			Title:       "Never Gonna Give You Up",
+			ChannelName: "Rick Astley",
+			Duration:    3*time.Minute + 33*time.Second,
+		},
+	}
+
+	embed := buildGrabEmbed(&Interaction{}, item, 83*time.Second)
+	if !strings.Contains(embed.Description, "https://youtu.be/dQw4w9WgXcQ?t=83") {
+		t.Errorf("description should link to the grabbed moment, got %q", embed.Description)
+	}
+	if embed.Fields[0].Value != "1:23 / 3:33" {
+		t.Errorf("grabbed at = %q, want %q", embed.Fields[0].Value, "1:23 / 3:33")
+	}
+	if embed.Thumbnail == nil {
+		t.Error("YouTube grabs should include the thumbnail")
+	}
+
+	item.Video.Source = "soundcloud"
+	item.Video.URL = "https://soundcloud.com/artist/track"
+	embed = buildGrabEmbed(&Interaction{}, item, 83*time.Second)
+	if !strings.Contains(embed.Description, item.Video.URL) || embed.Thumbnail != nil {
+		t.Errorf("non-YouTube grab should link the page without a thumbnail, got %q", embed.Description)
+	}
+}
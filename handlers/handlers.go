@@ -1,23 +1,25 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
 	"crypto/ed25519"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"strconv"
 	"time"
 
+	"github.com/bwmarrin/discordgo"
 	sentry "github.com/getsentry/sentry-go"
 	log "github.com/sirupsen/logrus"
 
 	"beatbot/config"
 	"beatbot/controller"
+	"beatbot/database"
+	"beatbot/discord"
 	"beatbot/gemini"
+	"beatbot/i18n"
 	"beatbot/sentryhelper"
 )
 
@@ -27,13 +29,93 @@ type Response struct {
 }
 
 type ResponseData struct {
-	Content string `json:"content"`
-	Flags   int    `json:"flags"`
+	Content         string                       `json:"content"`
+	Flags           int                          `json:"flags"`
+	AllowedMentions *AllowedMentions             `json:"allowed_mentions,omitempty"`
+	Components      []discordgo.MessageComponent `json:"components,omitempty"`
+	Embeds          []*discordgo.MessageEmbed    `json:"embeds,omitempty"`
+	// CustomID and Title are only used for modal responses (Type 9).
+	CustomID string `json:"custom_id,omitempty"`
+	Title    string `json:"title,omitempty"`
+	// Choices is only used for autocomplete responses (Type 8). It's a
+	// pointer so an empty list is still sent as [] rather than omitted.
+	Choices *[]*discordgo.ApplicationCommandOptionChoice `json:"choices,omitempty"`
+}
+
+// AllowedMentions controls which mentions in Content actually ping. An empty
+// Parse list renders <@user>/<@&role> mentions without notifying anyone.
+type AllowedMentions struct {
+	Parse []string `json:"parse"`
 }
 
+// noPings renders mentions as names without notifying anyone.
+var noPings = &AllowedMentions{Parse: []string{}}
+
+// Application command option types used by handlers.
+const (
+	optionTypeSubcommand      = 1
+	optionTypeSubcommandGroup = 2
+	optionTypeString          = 3
+	optionTypeAttachment      = 11
+)
+
 type InteractionOption struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name    string              `json:"name"`
+	Type    int                 `json:"type"`
+	Value   string              `json:"value"`
+	Options []InteractionOption `json:"options"` // set for subcommands
+	Focused bool                `json:"focused"` // the option being autocompleted
+}
+
+// UnmarshalJSON accepts string, integer, number, and boolean option values,
+// normalizing them to their string form so handlers can keep using Value
+// with strconv (e.g. an integer option arrives as 5, not "5").
+func (o *InteractionOption) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name    string              `json:"name"`
+		Type    int                 `json:"type"`
+		Value   json.RawMessage     `json:"value"`
+		Options []InteractionOption `json:"options"`
+		Focused bool                `json:"focused"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	o.Name = raw.Name
+	o.Type = raw.Type
+	o.Options = raw.Options
+	o.Focused = raw.Focused
+	o.Value = ""
+	if len(raw.Value) == 0 || string(raw.Value) == "null" {
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(raw.Value, &str); err == nil {
+		o.Value = str
+		return nil
+	}
+	o.Value = string(raw.Value)
+	return nil
+}
+
+// Attachment is a file uploaded through an attachment command option.
+type Attachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Size     int    `json:"size"`
+	URL      string `json:"url"`
+}
+
+// ResolvedMessage is the message a message context-menu command targets.
+type ResolvedMessage struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+}
+
+// ResolvedData holds the objects referenced by ID in command options.
+type ResolvedData struct {
+	Attachments map[string]Attachment      `json:"attachments"`
+	Messages    map[string]ResolvedMessage `json:"messages"`
 }
 
 // StringOrInt is a custom type that can unmarshal from either a string or number in JSON
@@ -64,6 +146,21 @@ type InteractionData struct {
 	Options       []InteractionOption `json:"options"`
 	CustomID      string              `json:"custom_id"`
 	ComponentType int                 `json:"component_type"`
+	Values        []string            `json:"values"` // select menu choices
+	Resolved      *ResolvedData       `json:"resolved"`
+	TargetID      string              `json:"target_id"`  // message or user a context-menu command was used on
+	Components    []ModalRow          `json:"components"` // submitted modal fields
+}
+
+// ModalRow is one action row of a submitted modal.
+type ModalRow struct {
+	Components []ModalField `json:"components"`
+}
+
+// ModalField is the value a user entered in a modal's text input.
+type ModalField struct {
+	CustomID string `json:"custom_id"`
+	Value    string `json:"value"`
 }
 
 type UserData struct {
@@ -74,13 +171,15 @@ type UserData struct {
 }
 
 type MemberData struct {
-	User     UserData `json:"user"`
-	Roles    []string `json:"roles"`
-	JoinedAt string   `json:"joined_at"`
-	Nick     *string  `json:"nick"`
+	User        UserData `json:"user"`
+	Roles       []string `json:"roles"`
+	JoinedAt    string   `json:"joined_at"`
+	Nick        *string  `json:"nick"`
+	Permissions string   `json:"permissions"` // computed channel permissions bitfield, as a decimal string
 }
 
 type Interaction struct {
+	ID            string          `json:"id"`
 	ApplicationID string          `json:"application_id"`
 	Type          int             `json:"type"`
 	Data          InteractionData `json:"data"`
@@ -89,6 +188,37 @@ type Interaction struct {
 	Version       int             `json:"version"`
 	GuildID       string          `json:"guild_id"`
 	ChannelID     string          `json:"channel_id"`
+	GuildLocale   string          `json:"guild_locale"`
+	// User is the invoker outside servers, where there's no member; see
+	// adoptUser.
+	User *UserData `json:"user"`
+	// Context is where the command was used: 0 a server, 1 the bot's DMs,
+	// 2 another DM or group DM.
+	Context int `json:"context"`
+	// AuthorizingIntegrationOwners maps each installation that allowed this
+	// interaction ("0" server, "1" user) to the server or user ID.
+	AuthorizingIntegrationOwners map[string]string `json:"authorizing_integration_owners"`
+	// Message is the message a clicked button or select menu is attached to.
+	Message *discordgo.Message `json:"message"`
+
+	// prefs are the invoker's /preferences; see loadPreferences.
+	prefs database.UserPreferences
+	// auditID is the command's audit log entry; see recordCommand.
+	auditID int64
+}
+
+// tr returns the i18n message for key in the invoker's language.
+func tr(interaction *Interaction, key string, args ...any) string {
+	return i18n.T(interaction.locale(), key, args...)
+}
+
+// locale is the language to answer the invoker in: their /preferences
+// choice, or else the guild's.
+func (i *Interaction) locale() string {
+	if i.prefs.Language != "" {
+		return i.prefs.Language
+	}
+	return i.GuildLocale
 }
 
 type Options struct {
@@ -101,24 +231,38 @@ type Manager struct {
 	BotToken   string
 	Controller *controller.Controller
 	Hints      *Hints
+	Cooldowns  *Cooldowns
+	searches   *searchCache
+	confirms   *confirmations
 }
 
 func NewManager(appID string, controller *controller.Controller) *Manager {
 	publicKey := config.Config.Discord.PublicKey
 	botToken := config.Config.Discord.BotToken
 
-	if publicKey == "" || botToken == "" {
-		log.Fatal("DISCORD_PUBLIC_KEY and DISCORD_BOT_TOKEN must be set")
+	if botToken == "" {
+		log.Fatal("DISCORD_BOT_TOKEN must be set")
+		os.Exit(1)
+	}
+	// Only the webhook endpoint verifies signatures with the public key.
+	if publicKey == "" && !config.Config.Discord.UseGateway() {
+		log.Fatal("DISCORD_PUBLIC_KEY must be set unless DISCORD_MODE=gateway")
 		os.Exit(1)
 	}
 
-	return &Manager{
+	manager := &Manager{
 		AppID:      appID,
 		PublicKey:  publicKey,
 		BotToken:   botToken,
 		Controller: controller,
 		Hints:      NewHints(),
+		Cooldowns:  NewCooldowns(config.Config.Options.CommandCooldowns),
+		searches:   newSearchCache(),
+		confirms:   newConfirmations(),
 	}
+	// Leaving an empty or idle channel keeps the queue like /disconnect does.
+	controller.SetLeaveHook(manager.saveQueueOnLeave)
+	return manager
 }
 
 func (manager *Manager) VerifyDiscordRequest(signature, timestamp string, body []byte) bool {
@@ -127,6 +271,11 @@ func (manager *Manager) VerifyDiscordRequest(signature, timestamp string, body [
 		log.Errorf("Error decoding public key: %v", err)
 		return false
 	}
+	// ed25519.Verify panics on a key of the wrong length, e.g. when
+	// DISCORD_PUBLIC_KEY is unset in gateway mode.
+	if len(pubKeyBytes) != ed25519.PublicKeySize {
+		return false
+	}
 
 	signatureBytes, err := hex.DecodeString(signature)
 	if err != nil {
@@ -148,10 +297,34 @@ func (manager *Manager) ParseInteraction(body []byte) (*Interaction, error) {
 }
 
 func (manager *Manager) HandleInteraction(interaction *Interaction) (response Response) {
+	// Deferred responses get their "thinking…" message replaced by the first
+	// followup rather than left hanging above it. The token is registered
+	// before the handler runs so followups from goroutines it starts wait to
+	// learn how it answered.
+	discord.ExpectResponse(interaction.Token)
+	defer func() {
+		if response.Type == 5 {
+			discord.MarkDeferred(interaction.Token, response.Data.Flags&64 != 0)
+		} else {
+			discord.MarkAnswered(interaction.Token)
+		}
+	}()
+
+	interaction.adoptUser()
+	manager.loadPreferences(interaction)
+
 	// Handle Message Component interactions (button clicks) - Type 3
 	if interaction.Type == 3 {
 		return manager.handleMessageComponent(interaction)
 	}
+	// Handle Autocomplete interactions - Type 4
+	if interaction.Type == 4 {
+		return manager.handleAutocomplete(interaction)
+	}
+	// Handle Modal Submit interactions - Type 5
+	if interaction.Type == 5 {
+		return manager.handleModalSubmit(interaction)
+	}
 
 	// Create transaction with cloned hub for scope isolation (breadcrumbs per-command)
 	ctx, transaction := sentryhelper.StartCommandTransaction(
@@ -175,10 +348,11 @@ func (manager *Manager) HandleInteraction(interaction *Interaction) (response Re
 			log.Errorf("Panic in command handling: %v", err)
 			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in command %s: %v", interaction.Data.Name, err))
 			transaction.Status = sentry.SpanStatusInternalError
+			manager.auditOutcome(interaction, database.AuditError, fmt.Sprint(err))
 			response = Response{
 				Type: 4,
 				Data: ResponseData{
-					Content: "An error occurred while processing your command",
+					Content: tr(interaction, "misc.command_error"),
 					Flags:   64,
 				},
 			}
@@ -202,28 +376,98 @@ func (manager *Manager) HandleInteraction(interaction *Interaction) (response Re
 		})
 	})
 
+	// Installed just for the user (or in DMs), only commands that don't
+	// need the bot in the server work.
+	if !interaction.inBotGuild() && !userAppCommands[interaction.Data.Name] {
+		return userAppRefusal(interaction)
+	}
+
 	// Always track the last text channel so we can send messages (e.g. radio announcements)
-	if interaction.GuildID != "" && interaction.ChannelID != "" {
+	if interaction.inBotGuild() && interaction.ChannelID != "" {
 		player := manager.Controller.GetPlayer(interaction.GuildID)
+		ctx = gemini.WithGuildStyle(ctx, player.GetAIStyle())
+		ctx = gemini.WithGuild(ctx, interaction.GuildID)
+		ctx = gemini.WithLanguage(ctx, i18n.LanguageName(interaction.locale()))
 		player.SetLastTextChannelID(interaction.ChannelID)
+		player.SetGuildLocale(interaction.GuildLocale)
+		// Remember member roles so queue priority can check the DJ role
+		player.SetMemberRoles(interaction.Member.User.ID, interaction.Member.Roles)
+		manager.recordCommand(interaction, interaction.Data.Name, auditOptions(interaction.Data.Options))
+
+		if refusal, ok := manager.requireDJ(interaction, interaction.Data.Name); !ok {
+			manager.auditOutcome(interaction, database.AuditRefused, refusal.Data.Content)
+			return refusal
+		}
+		if refusal, ok := manager.requireListener(interaction, interaction.Data.Name); !ok {
+			manager.auditOutcome(interaction, database.AuditRefused, refusal.Data.Content)
+			return refusal
+		}
+	}
+
+	command := interaction.Data.Name
+	if command == queueMessageCommand {
+		command = "play"
+	}
+	if refusal, ok := manager.requireCooldown(interaction, command); !ok {
+		manager.auditOutcome(interaction, database.AuditRefused, "cooldown")
+		return refusal
 	}
 
 	switch interaction.Data.Name {
 	case "ping":
-		return manager.handlePing()
+		return manager.handlePing(interaction)
 	case "help":
 		finishTransaction = false // goroutine will finish
 		return manager.handleHelp(ctx, transaction, interaction)
-	case "queue", "play":
+	case "play":
 		finishTransaction = false // goroutine will finish
 		return manager.handleQueue(ctx, transaction, interaction)
+	case queueMessageCommand:
+		response, async := manager.handleQueueMessage(ctx, transaction, interaction)
+		finishTransaction = !async
+		return response
+	case "queue":
+		finishTransaction = false // goroutine will finish
+		return manager.handleQueueCommand(ctx, transaction, interaction)
+	case "search":
+		finishTransaction = false // goroutine will finish
+		if !interaction.inBotGuild() {
+			go manager.onUserAppSearch(ctx, transaction, interaction)
+			return Response{Type: 5, Data: ResponseData{Flags: 64}}
+		}
+		return manager.handleSearch(ctx, transaction, interaction)
+	case "podcast":
+		finishTransaction = false // goroutine will finish
+		return manager.handlePodcast(ctx, transaction, interaction)
+	case "chapters":
+		finishTransaction = false // goroutine will finish
+		return manager.handleChapters(ctx, transaction, interaction)
 	case "view":
 		finishTransaction = false // goroutine will finish
 		return manager.handleView(ctx, transaction, interaction)
+	case "nowplaying":
+		finishTransaction = false // goroutine will finish
+		return manager.handleNowPlaying(ctx, transaction, interaction)
+	case "listen-along":
+		finishTransaction = false
+		go manager.onListenAlong(ctx, transaction, interaction)
+		return Response{Type: 5}
+	case "grab":
+		return manager.handleGrab(interaction)
+	case "notifyme":
+		return manager.handleNotifyMe(interaction)
+	case "preferences":
+		return manager.handlePreferences(interaction)
+	case "forgetme":
+		return manager.handleForgetMe(interaction)
 	case "remove":
 		return manager.handleRemove(ctx, interaction)
 	case "clear":
 		return manager.handleClear(ctx, interaction)
+	case "jump":
+		return manager.handleJump(interaction)
+	case "undo":
+		return manager.handleUndo(interaction)
 	case "skip":
 		finishTransaction = false // goroutine will finish
 		return manager.handleSkip(ctx, transaction, interaction)
@@ -234,8 +478,17 @@ func (manager *Manager) HandleInteraction(interaction *Interaction) (response Re
 	case "resume":
 		return manager.handleResume(ctx, interaction)
 	case "reset":
+		return manager.handleReset(interaction)
+	case "disconnect":
 		finishTransaction = false // goroutine will finish
-		return manager.handleReset(ctx, transaction, interaction)
+		go manager.onDisconnect(ctx, transaction, interaction)
+		return Response{Type: 5}
+	case "summon":
+		finishTransaction = false // goroutine will finish
+		go manager.onSummon(ctx, transaction, interaction)
+		return Response{Type: 5}
+	case "follow":
+		return manager.handleFollow(interaction)
 	case "shuffle":
 		return manager.handleShuffle(ctx, interaction)
 	case "radio":
@@ -246,6 +499,28 @@ func (manager *Manager) HandleInteraction(interaction *Interaction) (response Re
 		return manager.handleHistory(interaction)
 	case "leaderboard":
 		return manager.handleLeaderboard(interaction)
+	case "stats":
+		if !interaction.inBotGuild() {
+			return manager.handleUserStats(interaction)
+		}
+		return manager.handleStats(interaction)
+	case "transcript":
+		finishTransaction = false
+		go manager.handleTranscript(ctx, transaction, interaction)
+		return Response{Type: 5}
+	case "clip":
+		if subcommandName(interaction) != "save" {
+			return manager.handleClipConsent(interaction)
+		}
+		finishTransaction = false
+		go manager.onClip(ctx, transaction, interaction)
+		return Response{Type: 5}
+	case "soundboard":
+		if subcommandName(interaction) == "list" {
+			return manager.handleSoundboardList(interaction)
+		}
+		finishTransaction = false
+		return manager.handleSoundboard(ctx, transaction, interaction)
 	case "topsongs":
 		finishTransaction = false
 		go manager.handleTopSongs(ctx, transaction, interaction)
@@ -263,6 +538,10 @@ func (manager *Manager) HandleInteraction(interaction *Interaction) (response Re
 		finishTransaction = false
 		go manager.handleRequest(ctx, transaction, interaction)
 		return Response{Type: 5}
+	case "dj":
+		finishTransaction = false
+		go manager.handleDJ(ctx, transaction, interaction)
+		return Response{Type: 5}
 	case "neverplay":
 		return manager.handleNeverPlay(interaction)
 	case "favorite":
@@ -271,6 +550,19 @@ func (manager *Manager) HandleInteraction(interaction *Interaction) (response Re
 		return manager.handleFavorites(interaction)
 	case "unfavorite":
 		return manager.handleUnfavorite(interaction)
+	case "playlist":
+		finishTransaction = false // handlePlaylistCommand finishes it
+		return manager.handlePlaylistCommand(ctx, transaction, interaction)
+	case "playfavorites":
+		finishTransaction = false // goroutine will finish
+		go manager.onPlayFavorites(ctx, transaction, interaction)
+		return Response{Type: 5}
+	case "playat":
+		finishTransaction = false // goroutine will finish
+		go manager.onPlayAt(ctx, transaction, interaction)
+		return Response{Type: 5}
+	case "schedule":
+		return manager.handleSchedule(interaction)
 	case "announce":
 		return manager.handleAnnounce(ctx, interaction)
 	case "voice-demo":
@@ -279,6 +571,36 @@ func (manager *Manager) HandleInteraction(interaction *Interaction) (response Re
 		return Response{Type: 5}
 	case "voices":
 		return manager.handleVoices(interaction)
+	case "translate":
+		return manager.handleTranslate(interaction)
+	case "djrole":
+		return manager.handleDJRole(interaction)
+	case "settings":
+		return manager.handleSettings(interaction)
+	case "tone":
+		response, async := manager.handleTone(ctx, transaction, interaction)
+		finishTransaction = !async
+		return response
+	case "audiodebug":
+		return manager.handleAudioDebug(interaction)
+	case "auditlog":
+		return manager.handleAuditLog(interaction)
+	case "musicchannel":
+		return manager.handleMusicChannel(interaction)
+	case "dashboard":
+		return manager.handleDashboard(interaction)
+	case "threads":
+		return manager.handleThreads(interaction)
+	case "reactions":
+		return manager.handleReactions(interaction)
+	case "clips":
+		return manager.handleClips(interaction)
+	case "branding":
+		return manager.handleBranding(interaction)
+	case "guestdj":
+		return manager.handleGuestDJ(interaction)
+	case "queuesettings":
+		return manager.handleQueueSettings(interaction)
 	case "charts":
 		finishTransaction = false
 		go manager.handleCharts(ctx, transaction, interaction)
@@ -289,7 +611,7 @@ func (manager *Manager) HandleInteraction(interaction *Interaction) (response Re
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "Sorry, I don't know how to handle this type of interaction",
+				Content: tr(interaction, "misc.unknown_command"),
 				Flags:   64,
 			},
 		}
@@ -305,24 +627,13 @@ func (manager *Manager) SendRequest(interaction *Interaction, content string, ep
 		payload["flags"] = 64
 	}
 
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		log.Errorf("Error marshalling payload: %v", err)
-		return
-	}
-
-	resp, err := http.Post(
-		"https://discord.com/api/v10/webhooks/"+manager.AppID+"/"+interaction.Token,
-		"application/json",
-		bytes.NewBuffer(jsonPayload),
-	)
-	if err != nil {
+	if err := discord.PostFollowup(manager.AppID, interaction.Token, payload); err != nil {
 		log.Errorf("Error sending followup: %v", err)
 	}
-	defer resp.Body.Close()
 }
 
 func (manager *Manager) SendError(interaction *Interaction, content string, ephemeral bool) {
+	manager.auditOutcome(interaction, database.AuditError, content)
 	manager.SendRequest(interaction, content, ephemeral)
 }
 
@@ -332,6 +643,11 @@ func (manager *Manager) SendFollowup(ctx context.Context, interaction *Interacti
 
 	// pass in an empty string to skip the AI generation
 	if content != "" {
+		// AI confirmations are chatter; quiet guilds, and members who'd rather,
+		// only show them to the caller
+		if interaction.prefs.PrivateReplies || manager.Controller.GetPlayer(interaction.GuildID).GetQuietResponses() {
+			ephemeral = true
+		}
 		genText := gemini.GenerateResponse(ctx, "User: "+userName+"\nEvent: "+content)
 		if genText != "" {
 			toSend = genText
@@ -340,30 +656,48 @@ func (manager *Manager) SendFollowup(ctx context.Context, interaction *Interacti
 	manager.SendRequest(interaction, toSend, ephemeral)
 }
 
-func formatRelativeTime(t time.Time) string {
+func formatRelativeTime(interaction *Interaction, t time.Time) string {
 	d := time.Since(t)
 	switch {
 	case d < time.Minute:
-		return "just now"
+		return tr(interaction, "time.just_now")
 	case d < time.Hour:
 		m := int(d.Minutes())
 		if m == 1 {
-			return "1 min ago"
+			return tr(interaction, "time.min_ago")
 		}
-		return fmt.Sprintf("%d mins ago", m)
+		return tr(interaction, "time.mins_ago", m)
 	case d < 24*time.Hour:
 		h := int(d.Hours())
 		if h == 1 {
-			return "1 hour ago"
+			return tr(interaction, "time.hour_ago")
 		}
-		return fmt.Sprintf("%d hours ago", h)
+		return tr(interaction, "time.hours_ago", h)
 	default:
 		days := int(d.Hours() / 24)
 		if days == 1 {
-			return "1 day ago"
+			return tr(interaction, "time.day_ago")
 		}
-		return fmt.Sprintf("%d days ago", days)
+		return tr(interaction, "time.days_ago", days)
+	}
+}
+
+// subcommandName returns the invoked subcommand, or "" for commands without
+// subcommands.
+func subcommandName(interaction *Interaction) string {
+	if len(interaction.Data.Options) > 0 && interaction.Data.Options[0].Type == optionTypeSubcommand {
+		return interaction.Data.Options[0].Name
+	}
+	return ""
+}
+
+// commandOptions returns the options the user filled in, looking inside the
+// subcommand when one was invoked.
+func commandOptions(interaction *Interaction) []InteractionOption {
+	if len(interaction.Data.Options) > 0 && interaction.Data.Options[0].Type == optionTypeSubcommand {
+		return interaction.Data.Options[0].Options
 	}
+	return interaction.Data.Options
 }
 
 func parseLimitOption(interaction *Interaction) int {
@@ -16,20 +16,20 @@ func TestHintsCooldownBehavior(t *testing.T) {
 	guildID := "test-guild"
 
 	// First call should show hint
-	hint1 := hints.ShowIfApplicable(guildID)
+	hint1 := hints.ShowIfApplicable("", guildID)
 	if hint1 == "" {
 		t.Error("Expected hint on first call")
 	}
 
 	// Immediate second call should not show hint (cooldown)
-	hint2 := hints.ShowIfApplicable(guildID)
+	hint2 := hints.ShowIfApplicable("", guildID)
 	if hint2 != "" {
 		t.Error("Expected no hint due to cooldown")
 	}
 
 	// Clear cooldown and test again
 	hints.ClearCooldown(guildID)
-	hint3 := hints.ShowIfApplicable(guildID)
+	hint3 := hints.ShowIfApplicable("", guildID)
 	if hint3 == "" {
 		t.Error("Expected hint after cooldown clear")
 	}
@@ -52,7 +52,7 @@ func TestHintsIntegrationWithManager(t *testing.T) {
 
 	// With 100% chance, should show hint
 	hints.hintChance = 1.0
-	hint := hints.ShowIfApplicable(guildID)
+	hint := hints.ShowIfApplicable("", guildID)
 	if hint == "" {
 		t.Error("Expected hint with 100% chance")
 	}
@@ -75,7 +75,7 @@ func TestHintsProbability(t *testing.T) {
 
 	// With 0% chance, should never show hint
 	for i := 0; i < 10; i++ {
-		hint := hints.ShowIfApplicable(guildID)
+		hint := hints.ShowIfApplicable("", guildID)
 		if hint != "" {
 			t.Errorf("Expected no hint with 0%% chance, got: %s", hint)
 		}
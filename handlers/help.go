@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"beatbot/config"
+	"beatbot/controller"
+	"beatbot/i18n"
+)
+
+// helpSectionOrder is the order sections appear in /help. Each is an i18n key
+// for the section title.
+var helpSectionOrder = []string{
+	"help.music",
+	"help.queue",
+	"help.favorites",
+	"help.playlists",
+	"help.discovery",
+	"help.stats",
+	"help.voice",
+	"help.server",
+	"help.other",
+}
+
+// helpSection files commands under a /help section. Anything not listed here
+// still shows up, under Other.
+var helpSection = map[string]string{
+	"play":              "help.music",
+	queueMessageCommand: "help.music",
+	"search":            "help.music",
+	"podcast":           "help.music",
+	"chapters":          "help.music",
+	"topsongs":          "help.music",
+	"skip":              "help.music",
+	"pause":             "help.music",
+	"stop":              "help.music",
+	"resume":            "help.music",
+	"volume":            "help.music",
+	"loop":              "help.music",
+	"summon":            "help.music",
+	"disconnect":        "help.music",
+	"follow":            "help.music",
+
+	"queue":        "help.queue",
+	"view":         "help.queue",
+	"nowplaying":   "help.queue",
+	"listen-along": "help.queue",
+	"grab":         "help.queue",
+	"notifyme":     "help.queue",
+	"remove":       "help.queue",
+	"jump":         "help.queue",
+	"shuffle":      "help.queue",
+	"undo":         "help.queue",
+	"clear":        "help.queue",
+	"purge":        "help.queue",
+	"reset":        "help.queue",
+
+	"favorite":      "help.favorites",
+	"favorites":     "help.favorites",
+	"unfavorite":    "help.favorites",
+	"playfavorites": "help.favorites",
+
+	"playlist": "help.playlists",
+	"playat":   "help.playlists",
+	"schedule": "help.playlists",
+
+	"radio":     "help.discovery",
+	"request":   "help.discovery",
+	"dj":        "help.discovery",
+	"recommend": "help.discovery",
+	"charts":    "help.discovery",
+	"lyrics":    "help.discovery",
+	"translate": "help.discovery",
+
+	"history":     "help.stats",
+	"leaderboard": "help.stats",
+	"stats":       "help.stats",
+	"transcript":  "help.stats",
+
+	"announce":   "help.voice",
+	"voice-demo": "help.voice",
+	"voices":     "help.voice",
+	"clip":       "help.voice",
+	"soundboard": "help.voice",
+
+	"settings":      "help.server",
+	"tone":          "help.server",
+	"audiodebug":    "help.server",
+	"auditlog":      "help.server",
+	"musicchannel":  "help.server",
+	"dashboard":     "help.server",
+	"threads":       "help.server",
+	"reactions":     "help.server",
+	"clips":         "help.server",
+	"branding":      "help.server",
+	"queuesettings": "help.server",
+	"djrole":        "help.server",
+	"guestdj":       "help.server",
+	"neverplay":     "help.server",
+
+	"help":        "help.other",
+	"ping":        "help.other",
+	"preferences": "help.other",
+	"forgetme":    "help.other",
+}
+
+func hasDatabase(player *controller.GuildPlayer) bool {
+	return player == nil || player.DB != nil
+}
+
+func geminiEnabled() bool {
+	return config.Config != nil && config.Config.Gemini.Enabled
+}
+
+// commandAvailable reports whether a command can do anything for this guild,
+// so /help can leave out commands whose integration isn't set up. player may
+// be nil when there's no guild to check.
+var commandAvailable = map[string]func(player *controller.GuildPlayer) bool{
+	"topsongs": func(*controller.GuildPlayer) bool {
+		return config.Config != nil && config.Config.Spotify.Enabled
+	},
+	"charts": func(*controller.GuildPlayer) bool {
+		return config.Config != nil && config.Config.Deezer.Enabled
+	},
+	"translate": func(*controller.GuildPlayer) bool { return geminiEnabled() },
+	"dj":        func(*controller.GuildPlayer) bool { return geminiEnabled() },
+	"tone":      func(*controller.GuildPlayer) bool { return geminiEnabled() },
+	"recommend": func(player *controller.GuildPlayer) bool {
+		return geminiEnabled() && hasDatabase(player)
+	},
+	"favorite":      hasDatabase,
+	"favorites":     hasDatabase,
+	"unfavorite":    hasDatabase,
+	"playfavorites": hasDatabase,
+	"playlist":      hasDatabase,
+	"playat":        hasDatabase,
+	"schedule":      hasDatabase,
+	"history":       hasDatabase,
+	"leaderboard":   hasDatabase,
+	"stats":         hasDatabase,
+	"notifyme":      hasDatabase,
+	"preferences":   hasDatabase,
+	"forgetme":      hasDatabase,
+	"transcript":    hasDatabase,
+	"clip":          hasDatabase,
+	"clips":         hasDatabase,
+	"soundboard":    hasDatabase,
+	"neverplay":     hasDatabase,
+	"auditlog":      hasDatabase,
+}
+
+// commandDescription returns description translated into locale when the
+// registry has a translation for it.
+func commandDescription(description string, localizations map[discordgo.Locale]string, locale string) string {
+	if localized := localizations[discordgo.Locale(locale)]; localized != "" {
+		return localized
+	}
+	return description
+}
+
+// commandHelpLines renders one command as help lines: one per subcommand, or
+// a single line, noting who can use it in this guild.
+func commandHelpLines(cmd *discordgo.ApplicationCommand, locale string, player *controller.GuildPlayer) []string {
+	if cmd.Type == discordgo.MessageApplicationCommand {
+		return []string{i18n.T(locale, "help.message_command", cmd.Name) + " - " + contextMenuHelp[cmd.Name]}
+	}
+
+	note := ""
+	switch {
+	case cmd.DefaultMemberPermissions != nil:
+		note = " " + i18n.T(locale, "help.managers_only")
+	case player != nil && player.IsDJOnly(cmd.Name):
+		note = " " + i18n.T(locale, "help.dj_only")
+	}
+
+	var localizations map[discordgo.Locale]string
+	if cmd.DescriptionLocalizations != nil {
+		localizations = *cmd.DescriptionLocalizations
+	}
+
+	var lines []string
+	for _, opt := range cmd.Options {
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand {
+			lines = append(lines, "/"+cmd.Name+" "+opt.Name+" - "+
+				commandDescription(opt.Description, opt.DescriptionLocalizations, locale)+note)
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "/"+cmd.Name+" - "+
+			commandDescription(cmd.Description, localizations, locale)+note)
+	}
+	return lines
+}
+
+// HelpMenu renders the help menu from Commands, so it lists exactly what is
+// registered with Discord. Section titles are in locale; descriptions are
+// too when the registry has translations. player is the guild asking, or nil
+// to describe the bot in general.
+func HelpMenu(locale string, player *controller.GuildPlayer) string {
+	lines := make(map[string][]string, len(helpSectionOrder))
+	for _, cmd := range Commands {
+		if available, ok := commandAvailable[cmd.Name]; ok && !available(player) {
+			continue
+		}
+		section, ok := helpSection[cmd.Name]
+		if !ok {
+			section = "help.other"
+		}
+		lines[section] = append(lines[section], commandHelpLines(cmd, locale, player)...)
+	}
+
+	sections := make([]string, 0, len(helpSectionOrder))
+	for _, section := range helpSectionOrder {
+		if len(lines[section]) == 0 {
+			continue
+		}
+		sections = append(sections, "**"+i18n.T(locale, section)+":**\n"+strings.Join(lines[section], "\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"beatbot/config"
+	"beatbot/controller"
+)
+
+func TestHelpMenuListsEveryCommand(t *testing.T) {
+	saved := config.Config
+	config.Config = &config.ConfigStruct{
+		Gemini:  config.GeminiConfig{Enabled: true},
+		Spotify: config.SpotifyConfig{Enabled: true},
+		Deezer:  config.DeezerConfig{Enabled: true},
+	}
+	defer func() { config.Config = saved }()
+
+	menu := HelpMenu("en-US", nil)
+	if !strings.HasPrefix(menu, "**Music Control:**\n/play - ") {
+		t.Errorf("menu starts %q", menu[:40])
+	}
+	for _, cmd := range Commands {
+		name := "/" + cmd.Name
+		if cmd.Name == queueMessageCommand {
+			name = "Apps → " + cmd.Name
+		}
+		if !strings.Contains(menu, name) {
+			t.Errorf("%s is missing from the help menu", name)
+		}
+		if _, ok := helpSection[cmd.Name]; !ok {
+			t.Errorf("%s has no help section", name)
+		}
+	}
+	for _, line := range []string{
+		"/playlist create - Create an empty playlist",
+		"/settings - View and change this server's bot settings (server managers)",
+	} {
+		if !strings.Contains(menu, line) {
+			t.Errorf("menu is missing %q", line)
+		}
+	}
+}
+
+func TestHelpMenuHidesUnavailableCommands(t *testing.T) {
+	saved := config.Config
+	config.Config = &config.ConfigStruct{}
+	defer func() { config.Config = saved }()
+
+	player := &controller.GuildPlayer{}
+	player.SetDJOnlyCommands([]string{"skip"})
+	menu := HelpMenu("es-ES", player)
+
+	for _, hidden := range []string{"/topsongs", "/charts", "/recommend", "/favorites", "/playlist"} {
+		if strings.Contains(menu, hidden+" ") {
+			t.Errorf("%s should be hidden without its integration", hidden)
+		}
+	}
+	if !strings.Contains(menu, "/skip - Skips the current song (solo DJs)") {
+		t.Errorf("DJ-only note missing from %q", menu)
+	}
+	if !strings.HasPrefix(menu, "**Control de música:**") {
+		t.Errorf("Spanish menu starts %q", menu[:40])
+	}
+}
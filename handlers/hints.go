@@ -7,6 +7,8 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"beatbot/i18n"
 )
 
 // Note: rand.Seed is intentionally not called here. Since Go 1.20, the global
@@ -19,7 +21,7 @@ type Hints struct {
 	cooldownMu  sync.RWMutex
 	cooldownDur time.Duration
 	hintChance  float32
-	hints       []string
+	hints       []string // i18n keys
 }
 
 // NewHints creates a new Hints manager with guild-specific cooldowns
@@ -29,18 +31,18 @@ func NewHints() *Hints {
 		cooldownDur: 5 * time.Minute,
 		hintChance:  0.15, // 15% chance
 		hints: []string{
-			"Pro tip: /radio auto-queues similar songs when the queue is empty",
-			"Pro tip: /leaderboard shows the most played songs in this server",
-			"Pro tip: /favorites lets you save songs for later",
-			"Pro tip: /shuffle randomizes the current queue",
-			"Pro tip: /loop repeats the current song",
-			"Pro tip: /history shows recently played songs",
-			"Pro tip: /recommend lets the AI pick a song based on your taste",
-			"Pro tip: /volume adjusts the playback volume (0-150)",
-			"Pro tip: /lyrics shows lyrics for the currently playing song",
-			"Pro tip: /favorite saves the current song to your favorites",
-			"Pro tip: /announce toggles the DJ voice announcements between songs",
-			"Pro tip: Use /request to tell the DJ what vibe you're going for",
+			"hints.radio",
+			"hints.leaderboard",
+			"hints.favorites",
+			"hints.shuffle",
+			"hints.loop",
+			"hints.history",
+			"hints.recommend",
+			"hints.volume",
+			"hints.lyrics",
+			"hints.favorite",
+			"hints.announce",
+			"hints.request",
 		},
 	}
 }
@@ -98,11 +100,21 @@ func (h *Hints) GetCooldownRemaining(guildID string) time.Duration {
 	return remaining
 }
 
-// ShowIfApplicable checks if a hint should be shown and returns it with formatting
-func (h *Hints) ShowIfApplicable(guildID string) string {
+// ShowIfApplicable checks if a hint should be shown and returns it with
+// formatting, in locale's language
+func (h *Hints) ShowIfApplicable(locale, guildID string) string {
 	hint, show := h.ShouldShowHint(guildID)
 	if show {
-		return fmt.Sprintf("\n\n💡 %s", hint)
+		return fmt.Sprintf("\n\n💡 %s", i18n.T(locale, hint))
 	}
 	return ""
 }
+
+// hint returns a formatted hint for the interaction's guild, or "" when the
+// guild has quiet responses turned on.
+func (manager *Manager) hint(interaction *Interaction) string {
+	if manager.Controller.GetPlayer(interaction.GuildID).GetQuietResponses() {
+		return ""
+	}
+	return manager.Hints.ShowIfApplicable(interaction.locale(), interaction.GuildID)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+	"beatbot/discord"
+	"beatbot/lyrics"
+)
+
+const (
+	// karaokeInterval is how often the synced lyrics view follows playback.
+	karaokeInterval = 3 * time.Second
+	// karaokeMaxDuration stops the view before the interaction token, which
+	// it edits through, expires after 15 minutes.
+	karaokeMaxDuration = 14 * time.Minute
+	// Lines shown around the current one.
+	karaokeLinesBefore = 2
+	karaokeLinesAfter  = 3
+)
+
+// followSyncedLyrics turns the deferred /lyrics reply into a karaoke view,
+// re-rendering it around the line at the player's position until the song
+// changes.
+func (manager *Manager) followSyncedLyrics(interaction *Interaction, player *controller.GuildPlayer, item *controller.GuildQueueItem, lines []lyrics.Line, trackInfo string) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("Panic in followSyncedLyrics: %v", err)
+		}
+	}()
+
+	edit := func(content string) {
+		discord.UpdateMessage(&discord.FollowUpRequest{
+			Token:   interaction.Token,
+			AppID:   manager.AppID,
+			Content: content,
+		})
+	}
+
+	ticker := time.NewTicker(karaokeInterval)
+	defer ticker.Stop()
+	started := time.Now()
+	last := ""
+	for {
+		if player.GetCurrentItem() != item {
+			edit(tr(interaction, "lyrics.karaoke_finished", trackInfo))
+			return
+		}
+		if time.Since(started) > karaokeMaxDuration {
+			edit(tr(interaction, "lyrics.karaoke_stopped", trackInfo))
+			return
+		}
+
+		if content := renderKaraoke(trackInfo, lines, player.Player.GetPosition()); content != last {
+			edit(content)
+			last = content
+		}
+		<-ticker.C
+	}
+}
+
+// renderKaraoke shows a window of lines around the one sung at pos, with the
+// current line as a heading and past lines dimmed.
+func renderKaraoke(trackInfo string, lines []lyrics.Line, pos time.Duration) string {
+	current := lyrics.LineAt(lines, pos)
+	start := max(current-karaokeLinesBefore, 0)
+	end := min(current+karaokeLinesAfter+1, len(lines))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🎤 **%s**\n\n", trackInfo)
+	if current < 0 {
+		b.WriteString("## ♪\n")
+	}
+	for i := start; i < end; i++ {
+		text := lines[i].Text
+		if text == "" {
+			text = "♪"
+		}
+		switch {
+		case i == current:
+			b.WriteString("## " + text + "\n")
+		case i < current:
+			b.WriteString("-# " + text + "\n")
+		default:
+			b.WriteString(text + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/lyrics"
+)
+
+func TestRenderKaraoke(t *testing.T) {
+	var lines []lyrics.Line
+	for i, text := range []string{"one", "two", "", "four", "five", "six", "seven"} {
+		lines = append(lines, lyrics.Line{At: time.Duration(10*(i+1)) * time.Second, Text: text})
+	}
+
+	got := renderKaraoke("Song — Artist", lines, 5*time.Second)
+	want := "🎤 **Song — Artist**\n\n## ♪\none\ntwo\n♪"
+	if got != want {
+		t.Errorf("before the first line:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = renderKaraoke("Song — Artist", lines, 45*time.Second)
+	wantLines := []string{"-# two", "-# ♪", "## four", "five", "six", "seven"}
+	if !strings.HasSuffix(got, strings.Join(wantLines, "\n")) {
+		t.Errorf("mid-song view:\n%s\nwant it to end with:\n%s", got, strings.Join(wantLines, "\n"))
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+
+	"beatbot/controller"
+	"beatbot/discord"
+	"beatbot/sentryhelper"
+)
+
+// onListenAlong posts a link to the current song and where it's up to, for
+// members who can't join voice, and keeps a link on the now-playing card
+// for the rest of the session.
+func (manager *Manager) onListenAlong(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onListenAlong: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	info, err := player.StartListenAlong()
+	if errors.Is(err, controller.ErrNothingPlaying) {
+		manager.SendRequest(interaction, tr(interaction, "common.nothing_playing"), true)
+		return
+	}
+	if err != nil {
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendError(interaction, tr(interaction, "listenalong.failed"), true)
+		return
+	}
+	manager.SendRequest(interaction, listenAlongMessage(interaction, info, time.Now()), false)
+}
+
+// listenAlongMessage is the /listen-along post for info as of now.
+func listenAlongMessage(interaction *Interaction, info *controller.ListenAlongInfo, now time.Time) string {
+	if info.Live {
+		return tr(interaction, "listenalong.live", info.Title, info.Link)
+	}
+	msg := fmt.Sprintf("🎧 **%s**\n%s", info.Title, discord.ListenAlongText(info.Link, info.Position, info.Playing, now))
+	if info.SeekURL != "" {
+		msg += "\n" + tr(interaction, "listenalong.seek", discord.FormatDuration(info.Position), info.SeekURL)
+	}
+	return msg + "\n" + tr(interaction, "listenalong.footer")
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/controller"
+)
+
+func TestListenAlongMessage(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	msg := listenAlongMessage(&Interaction{}, &controller.ListenAlongInfo{
+		Title:    "Song",
+		Link:     "https://song.link/y/abc",
+		SeekURL:  "https://www.youtube.com/watch?v=abc&t=83s",
+		Position: 83 * time.Second,
+		Playing:  true,
+	}, now)
+	for _, want := range []string{"**Song**", "https://song.link/y/abc", "**1:23**", "<t:1699999917:T>", "(https://www.youtube.com/watch?v=abc&t=83s)"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+
+	msg = listenAlongMessage(&Interaction{}, &controller.ListenAlongInfo{Title: "Radio", Link: "https://radio.example/stream", Live: true}, now)
+	if strings.Contains(msg, "skip to") || !strings.Contains(msg, "https://radio.example/stream") {
+		t.Errorf("live message = %q", msg)
+	}
+}
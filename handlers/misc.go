@@ -1,29 +1,30 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	sentry "github.com/getsentry/sentry-go"
 	log "github.com/sirupsen/logrus"
 
 	"beatbot/config"
+	"beatbot/controller"
 	"beatbot/discord"
 	"beatbot/gemini"
+	"beatbot/i18n"
 	"beatbot/lyrics"
+	"beatbot/resolver"
 	"beatbot/sentryhelper"
 	"beatbot/spotify"
 )
 
-func (manager *Manager) handlePing() Response {
+func (manager *Manager) handlePing(interaction *Interaction) Response {
 	return Response{
 		Type: 4,
 		Data: ResponseData{
-			Content: "Pong! 🏓",
+			Content: tr(interaction, "misc.pong"),
 		},
 	}
 }
@@ -37,23 +38,10 @@ func (manager *Manager) onHelp(ctx context.Context, transaction *sentry.Span, in
 		transaction.Finish()
 	}()
 
-	response := gemini.GenerateHelpfulResponse(ctx, "(user issued the help command, return a nicely formatted help menu)")
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	response := gemini.GenerateHelpfulResponse(ctx, HelpMenu("", player), "(user issued the help command, return a nicely formatted help menu)")
 	if response == "" {
-		response = `**Music Control:**
-/play (or /queue) - Queue a song. Takes a search query, YouTube URL/playlist, or Spotify URL. Note: YouTube links with ?list= will queue the whole playlist
-/skip - Skip the current song and play the next in queue
-/pause (or /stop) - Pause the current song
-/resume - Resume playback
-/volume - Set playback volume (0-100)
-
-**Queue Management:**
-/view - View the current queue
-/remove - Remove a song from the queue by index number
-/reset - Clear everything and reset the player
-
-**Other:**
-/help - Show this help menu
-/ping - Check if the bot is alive`
+		response = HelpMenu(interaction.locale(), player)
 	}
 	manager.SendRequest(interaction, response, false)
 }
@@ -77,7 +65,7 @@ func (manager *Manager) handleTopSongs(ctx context.Context, transaction *sentry.
 	artistQuery := interaction.Data.Options[0].Value
 
 	if !config.Config.Spotify.Enabled {
-		manager.SendFollowup(ctx, interaction, "", "Spotify integration is not enabled. Ask the bot admin to set SPOTIFY_ENABLED=true.", true)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "topsongs.spotify_disabled"), true)
 		return
 	}
 
@@ -85,11 +73,11 @@ func (manager *Manager) handleTopSongs(ctx context.Context, transaction *sentry.
 	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
 	if err != nil {
 		sentryhelper.CaptureException(ctx, err)
-		manager.SendError(interaction, "Error getting voice state: "+err.Error(), true)
+		manager.SendError(interaction, tr(interaction, "common.voice_state_error", err.Error()), true)
 		return
 	}
 	if voiceState == nil {
-		manager.SendFollowup(ctx, interaction, "The user is not in a voice channel", "Join a voice channel first!", true)
+		manager.SendFollowup(ctx, interaction, "The user is not in a voice channel", tr(interaction, "common.join_voice_first"), true)
 		return
 	}
 
@@ -98,9 +86,11 @@ func (manager *Manager) handleTopSongs(ctx context.Context, transaction *sentry.
 	if player.ShouldJoinVoice(voiceState.ChannelID) {
 		if err := player.JoinVoiceChannel(interaction.Member.User.ID); err != nil {
 			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Error joining voice channel: "+err.Error(), true)
+			manager.SendError(interaction, tr(interaction, "common.join_voice_error", err.Error()), true)
 			return
 		}
+	} else if manager.refuseBusy(interaction, player, voiceState.ChannelID) {
+		return
 	}
 
 	// Search for artist
@@ -113,49 +103,25 @@ func (manager *Manager) handleTopSongs(ctx context.Context, transaction *sentry.
 	artistID, artistName, err := spotify.SearchArtist(ctx, artistQuery)
 	if err != nil {
 		sentryhelper.CaptureException(ctx, err)
-		manager.SendFollowup(ctx, interaction, "", fmt.Sprintf("Couldn't find artist **%s** on Spotify.", artistQuery), true)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "topsongs.artist_not_found", artistQuery), true)
 		return
 	}
 
-	manager.SendFollowup(ctx, interaction, "", fmt.Sprintf("Found **%s** on Spotify, fetching top songs...", artistName), false)
+	report := manager.reportProgress(ctx, interaction)
+	report(tr(interaction, "topsongs.fetching", artistName))
 
-	// Get top songs
-	tracks, err := spotify.GetArtistTopSongs(ctx, artistID)
+	resolution, err := resolver.SpotifyTopSongs(resolver.WithProgress(ctx, report), artistID, artistName)
 	if err != nil {
-		sentryhelper.CaptureException(ctx, err)
-		manager.SendFollowup(ctx, interaction, "", "Error fetching top songs from Spotify.", true)
+		manager.sendResolveError(ctx, interaction, artistQuery, err)
 		return
 	}
-
-	if len(tracks) == 0 {
-		manager.SendFollowup(ctx, interaction, "", fmt.Sprintf("No top songs found for **%s**.", artistName), true)
-		return
-	}
-
-	// Limit to 5
-	if len(tracks) > 5 {
-		tracks = tracks[:5]
-	}
-
-	// Convert to PlaylistTrackInfo for the collection handler
-	playlistTracks := make([]spotify.PlaylistTrackInfo, len(tracks))
-	for i, t := range tracks {
-		playlistTracks[i] = spotify.PlaylistTrackInfo{
-			TrackInfo: t,
-			Position:  i,
-		}
-	}
-
-	manager.handleSpotifyCollection(ctx, interaction, player, SpotifyCollection{
-		Type:        "artist top songs",
-		ID:          artistID,
-		Name:        artistName,
-		Artist:      artistName,
-		Tracks:      playlistTracks,
-		TotalTracks: len(playlistTracks),
-	})
+	manager.queueCollection(ctx, interaction, player, resolution)
 }
 
+// lyricsPageLimit is Discord's cap on an embed description. Each page goes
+// out as its own message, since one message's embeds share a 6000-char cap.
+const lyricsPageLimit = 4096
+
 func (manager *Manager) onLyrics(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -165,43 +131,102 @@ func (manager *Manager) onLyrics(ctx context.Context, transaction *sentry.Span,
 		transaction.Finish()
 	}()
 
-	player := manager.Controller.GetPlayer(interaction.GuildID)
+	synced := false
+	for _, opt := range commandOptions(interaction) {
+		if opt.Name == "synced" {
+			synced = opt.Value == "true"
+		}
+	}
 
-	currentSong := player.GetCurrentSong()
-	if currentSong == nil {
-		manager.SendFollowup(ctx, interaction, "", "Nothing is currently playing.", true)
+	if song := lyricsSongOption(interaction); song != "" {
+		manager.sendLyricsFor(ctx, interaction, song)
+		return
+	}
+	if !interaction.inBotGuild() {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "lyrics.song_missing"), true)
 		return
 	}
 
-	title := *currentSong
+	player := manager.Controller.GetPlayer(interaction.GuildID)
 
-	artist := discord.ExtractArtistFromTitle(title)
+	item := player.GetCurrentItem()
+	if item == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "common.nothing_playing"), true)
+		return
+	}
+	title := item.Video.Title
 
-	query := artist + " " + title
+	result := manager.findLyrics(ctx, player, item)
+	if result == nil || result.Plain() == "" {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "lyrics.not_found", title), false)
+		return
+	}
 
-	lc := lyrics.New()
+	if synced {
+		if lines := lyrics.ParseSynced(result.SyncedLyrics); len(lines) > 0 {
+			// The deferred reply becomes the karaoke view; it outlives this
+			// command's transaction.
+			go manager.followSyncedLyrics(interaction, player, item, lines, result.TrackInfo())
+			return
+		}
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "lyrics.no_synced", title), false)
+	}
 
-	lyricsText, trackInfo, err := lc.Search(query)
+	manager.sendLyricsPages(interaction, result)
+}
 
-	if err != nil || lyricsText == "" {
-		manager.SendFollowup(ctx, interaction, "", fmt.Sprintf("Couldn't find lyrics for **%s**.", title), false)
-		return
+// sendLyricsPages sends plain lyrics as embeds, split to fit.
+func (manager *Manager) sendLyricsPages(interaction *Interaction, result *lyrics.SearchResult) {
+	pages := lyrics.Pages(result.Plain(), lyricsPageLimit)
+	for i, page := range pages {
+		embed := &discordgo.MessageEmbed{
+			Title:       tr(interaction, "lyrics.title", result.TrackInfo()),
+			Description: page,
+			Color:       0x7289DA,
+		}
+		if len(pages) > 1 {
+			embed.Title += fmt.Sprintf(" (%d/%d)", i+1, len(pages))
+		}
+		if i == len(pages)-1 {
+			embed.Footer = &discordgo.MessageEmbedFooter{
+				Text: tr(interaction, "lyrics.footer"),
+			}
+		}
+		manager.sendEmbedFollowup(interaction, embed, false)
 	}
+}
 
-	if len(lyricsText) > 3800 {
-		lyricsText = lyricsText[:3800]
+// findLyrics looks up lrclib by the cleaned title (and Deezer's artist when
+// known). Raw video titles often defeat lrclib's search (non-Latin titles,
+// creative formatting), so on a miss Gemini names the song and it tries once
+// more. Returns nil when neither finds anything.
+func (manager *Manager) findLyrics(ctx context.Context, player *controller.GuildPlayer, item *controller.GuildQueueItem) *lyrics.SearchResult {
+	lc := lyrics.New()
+	query := lyrics.Query(item.Video.Title, player.GetDeezerArtist(item))
+	result, err := lc.Find(query)
+	if err != nil {
+		log.Warnf("Lyrics search for %q failed: %v", query, err)
 	}
-
-	embed := &discordgo.MessageEmbed{
-		Title:       "Lyrics: " + trackInfo,
-		Description: lyricsText,
-		Color:       0x7289DA,
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Lyrics provided by lrclib.net",
-		},
+	if result != nil && result.Plain() != "" {
+		return result
 	}
 
-	manager.sendEmbedFollowup(interaction, embed, false)
+	cleaned, err := gemini.CleanSongQuery(ctx, item.Video.OriginalTitle(), item.Video.ChannelName)
+	if err != nil {
+		log.Debugf("Couldn't name the song in %q for a lyrics retry: %v", item.Video.OriginalTitle(), err)
+		return result
+	}
+	if cleaned == "" || strings.EqualFold(cleaned, query) {
+		return result
+	}
+	retry, err := lc.Find(cleaned)
+	if err != nil {
+		log.Warnf("Lyrics search for %q failed: %v", cleaned, err)
+	}
+	if retry != nil {
+		return retry
+	}
+	return result
 }
 
 func (manager *Manager) handleLyrics(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
@@ -209,6 +234,19 @@ func (manager *Manager) handleLyrics(ctx context.Context, transaction *sentry.Sp
 	return Response{Type: 5}
 }
 
+// buttonCommands maps button actions to the command whose DJ and voice
+// channel restrictions they follow.
+var buttonCommands = map[string]string{
+	"playpause":     "pause",
+	"stop":          "pause",
+	"skip":          "skip",
+	"volup":         "volume",
+	"voldown":       "volume",
+	"shuffle":       "shuffle",
+	"clear_confirm": "clear",
+	"reset_confirm": "reset",
+}
+
 // handleMessageComponent handles button click interactions (Type 3)
 func (manager *Manager) handleMessageComponent(interaction *Interaction) Response {
 	ctx := context.Background()
@@ -220,7 +258,7 @@ func (manager *Manager) handleMessageComponent(interaction *Interaction) Respons
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "Invalid button interaction",
+				Content: tr(interaction, "buttons.invalid"),
 				Flags:   64,
 			},
 		}
@@ -232,7 +270,7 @@ func (manager *Manager) handleMessageComponent(interaction *Interaction) Respons
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "Guild mismatch",
+				Content: tr(interaction, "buttons.guild_mismatch"),
 				Flags:   64,
 			},
 		}
@@ -240,6 +278,39 @@ func (manager *Manager) handleMessageComponent(interaction *Interaction) Respons
 
 	log.Debugf("Button clicked: %s in guild %s", action, guildID)
 
+	// /forgetme works outside servers the bot is in, so it has no player.
+	switch action {
+	case "forgetme_confirm":
+		return manager.handleForgetMeConfirm(interaction)
+	case "forgetme_cancel":
+		return manager.handleForgetMeCancel(interaction)
+	}
+
+	ctx = gemini.WithGuildStyle(ctx, manager.Controller.GetPlayer(guildID).GetAIStyle())
+	ctx = gemini.WithGuild(ctx, guildID)
+	ctx = gemini.WithLanguage(ctx, i18n.LanguageName(interaction.locale()))
+
+	// Buttons that do what a restricted command does are restricted too,
+	// and share its cooldown.
+	if command, ok := buttonCommands[action]; ok {
+		if refusal, ok := manager.requireDJ(interaction, command); !ok {
+			return refusal
+		}
+		if refusal, ok := manager.requireListener(interaction, command); !ok {
+			return refusal
+		}
+		if refusal, ok := manager.requireCooldown(interaction, command); !ok {
+			return refusal
+		}
+	}
+
+	if videoID, ok := strings.CutPrefix(action, recommendAction); ok {
+		if refusal, ok := manager.requireCooldown(interaction, "play"); !ok {
+			return refusal
+		}
+		return manager.handleRecommendPick(interaction, videoID)
+	}
+
 	// Route to appropriate handler based on action
 	switch action {
 	case "playpause":
@@ -261,12 +332,57 @@ func (manager *Manager) handleMessageComponent(interaction *Interaction) Respons
 		return manager.handleSkip(ctx, transaction, interaction)
 	case "stop":
 		return manager.handlePause(ctx, interaction)
+	case "volup":
+		return manager.handleVolumeStep(interaction, volumeButtonStep)
+	case "voldown":
+		return manager.handleVolumeStep(interaction, -volumeButtonStep)
+	case "queue":
+		ctx, transaction := sentryhelper.StartCommandTransaction(
+			ctx,
+			"button_queue",
+			interaction.GuildID,
+			interaction.Member.User.ID,
+		)
+		// handleView's goroutine finishes the transaction
+		return manager.handleView(ctx, transaction, interaction)
+	case "shuffle":
+		return manager.handleShuffle(ctx, interaction)
+	case "grab":
+		return manager.handleGrab(interaction)
+	case "clear_confirm":
+		return manager.handleClearConfirm(ctx, interaction)
+	case "clear_cancel":
+		return manager.handleClearCancel(interaction)
+	case "reset_confirm":
+		return manager.handleResetConfirm(interaction)
+	case "reset_cancel":
+		return manager.handleResetCancel(interaction)
+	case "search_pick":
+		return manager.handleSearchPick(interaction)
+	case "podcast_pick":
+		return manager.handlePodcastPick(interaction)
+	case "chapter_pick":
+		return manager.handleChapterPick(interaction)
+	case "favorite_pick":
+		return manager.handleFavoritePick(interaction)
+	case "settings_channel":
+		return manager.handleSettingsChannel(interaction)
+	case "settings_voice":
+		return manager.handleSettingsVoice(interaction)
+	case "settings_responses":
+		return manager.handleSettingsResponses(interaction)
+	case "settings_source":
+		return manager.handleSettingsSource(interaction)
+	case "settings_edit":
+		return manager.handleSettingsEdit(interaction)
+	case "steal":
+		return manager.handleSteal(interaction)
 	default:
 		log.Errorf("Unknown button action: %s", action)
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "Unknown button action",
+				Content: tr(interaction, "buttons.unknown"),
 				Flags:   64,
 			},
 		}
@@ -282,21 +398,7 @@ func (manager *Manager) sendEmbedFollowup(interaction *Interaction, embed *disco
 		payload["flags"] = 64
 	}
 
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		log.Errorf("Error marshalling embed payload: %v", err)
-		return
-	}
-
-	resp, err := http.Post(
-		"https://discord.com/api/v10/webhooks/"+manager.AppID+"/"+interaction.Token,
-		"application/json",
-		bytes.NewBuffer(jsonPayload),
-	)
-	if err != nil {
+	if err := discord.PostFollowup(manager.AppID, interaction.Token, payload); err != nil {
 		log.Errorf("Error sending embed followup: %v", err)
 	}
-	if resp != nil {
-		defer resp.Body.Close()
-	}
 }
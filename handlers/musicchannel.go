@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// handleMusicChannel binds (or unbinds, when no channel is given) the text
+// channel where the bot posts now-playing cards and queue updates.
+// Restricted to Manage Server via DefaultMemberPermissions in Commands.
+func (manager *Manager) handleMusicChannel(interaction *Interaction) Response {
+	guildID := interaction.GuildID
+	player := manager.Controller.GetPlayer(guildID)
+
+	var channelID string
+	for _, opt := range interaction.Data.Options {
+		if opt.Name == "channel" {
+			channelID = opt.Value
+		}
+	}
+
+	if player.DB != nil {
+		if err := player.DB.SetGuildSetting(guildID, "music_channel_id", channelID); err != nil {
+			log.Errorf("Failed to save music channel: %v", err)
+			return Response{
+				Type: 4,
+				Data: ResponseData{
+					Content: tr(interaction, "musicchannel.save_failed"),
+					Flags:   64,
+				},
+			}
+		}
+	}
+	player.SetMusicChannelID(channelID)
+	// Moves the dashboard, if there is one, to the new channel.
+	player.RefreshDashboard()
+
+	msg := tr(interaction, "musicchannel.unbound")
+	if channelID != "" {
+		msg = tr(interaction, "musicchannel.bound", channelID)
+	}
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
+
+// handleDashboard toggles the dashboard: one pinned message in the music
+// channel that's edited in place as songs change, instead of scrolling away.
+// Restricted to Manage Server via DefaultMemberPermissions in Commands.
+func (manager *Manager) handleDashboard(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	enabled := !player.GetDashboardEnabled()
+
+	if enabled && player.GetMusicChannelID() == "" {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "musicchannel.dashboard_needs_channel"),
+				Flags:   64,
+			},
+		}
+	}
+
+	player.SetDashboardEnabled(enabled)
+	if player.DB != nil {
+		if err := player.DB.SetGuildSetting(interaction.GuildID, "dashboard_enabled", strconv.FormatBool(enabled)); err != nil {
+			log.Errorf("Failed to save dashboard setting: %v", err)
+		}
+	}
+
+	msg := tr(interaction, "musicchannel.dashboard_disabled")
+	if enabled {
+		player.RefreshDashboard()
+		msg = tr(interaction, "musicchannel.dashboard_enabled", player.GetMusicChannelID())
+	} else {
+		go player.RemoveDashboard()
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
+
+// handleThreads toggles session threads: each time the bot starts playing
+// after being idle it opens a thread, posts its cards and queue updates
+// there, and archives it when it leaves voice.
+// Restricted to Manage Server via DefaultMemberPermissions in Commands.
+func (manager *Manager) handleThreads(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	enabled := !player.GetSessionThreads()
+	player.SetSessionThreads(enabled)
+	if player.DB != nil {
+		if err := player.DB.SetGuildSetting(interaction.GuildID, "session_threads", strconv.FormatBool(enabled)); err != nil {
+			log.Errorf("Failed to save session thread setting: %v", err)
+		}
+	}
+
+	msg := tr(interaction, "musicchannel.threads_disabled")
+	if enabled {
+		msg = tr(interaction, "musicchannel.threads_enabled")
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
+
+// handleReactions toggles playback reactions on now-playing cards, starting
+// with the next card. Restricted to Manage Server via
+// DefaultMemberPermissions in Commands.
+func (manager *Manager) handleReactions(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	enabled := !player.GetReactionControls()
+	player.SetReactionControls(enabled)
+	if player.DB != nil {
+		if err := player.DB.SetGuildSetting(interaction.GuildID, "reaction_controls", strconv.FormatBool(enabled)); err != nil {
+			log.Errorf("Failed to save reaction controls setting: %v", err)
+		}
+	}
+
+	msg := tr(interaction, "musicchannel.reactions_disabled")
+	if enabled {
+		msg = tr(interaction, "musicchannel.reactions_enabled")
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+)
+
+// handleNotifyMe toggles a DM for the invoker whenever a song they queued is
+// next. The choice is theirs, so it applies in every server.
+func (manager *Manager) handleNotifyMe(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if player.DB == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	userID := interaction.Member.User.ID
+	enabled := !player.WantsUpNextDM(userID)
+	if err := player.DB.SetUserSetting(userID, controller.NotifyUpNextSetting, strconv.FormatBool(enabled)); err != nil {
+		log.Errorf("Failed to save notify setting: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "notifyme.save_failed"), Flags: 64}}
+	}
+
+	msg := tr(interaction, "notifyme.disabled")
+	if enabled {
+		msg = tr(interaction, "notifyme.enabled")
+	}
+	return Response{Type: 4, Data: ResponseData{Content: msg, Flags: 64}}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"beatbot/audio"
 	"beatbot/config"
+	"beatbot/controller"
 	"beatbot/deezer"
 	"beatbot/discord"
 	"beatbot/gemini"
@@ -32,18 +34,20 @@ func (manager *Manager) handleVolume(ctx context.Context, interaction *Interacti
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "Invalid volume",
+				Content: tr(interaction, "playback.invalid_volume"),
 			},
 		}
 	}
 
 	player.Player.SetVolume(volume)
+	player.SaveVolumeSoon()
+	player.RefreshDashboard()
 
 	// Generate DJ response with a tight deadline so we never blow Discord's 3s interaction limit
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
 	djResponse := helpers.GenerateDJResponse(djCtx, "volume", volume)
-	hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+	hint := manager.hint(interaction)
 
 	return Response{
 		Type: 4,
@@ -53,17 +57,96 @@ func (manager *Manager) handleVolume(ctx context.Context, interaction *Interacti
 	}
 }
 
+// volumeButtonStep is how far one press of a volume button moves the volume.
+const volumeButtonStep = 10
+
+// handleVolumeStep nudges the volume from a button press. On a now-playing
+// card the card's Volume field is edited in place; anywhere else the reply is
+// ephemeral so repeated presses don't flood the channel.
+func (manager *Manager) handleVolumeStep(interaction *Interaction, delta int) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	player.Player.SetVolume(player.Player.GetVolume() + delta)
+	player.SaveVolumeSoon()
+	player.RefreshDashboard()
+	volume := player.Player.GetVolume()
+
+	if interaction.Message != nil && len(interaction.Message.Embeds) > 0 {
+		embeds := interaction.Message.Embeds
+		discord.UpdateNowPlayingVolume(embeds[0], volume)
+		return Response{Type: 7, Data: ResponseData{Embeds: embeds}}
+	}
+
+	icon := "🔉"
+	if delta > 0 {
+		icon = "🔊"
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: icon + " " + tr(interaction, "playback.volume", volume),
+			Flags:   64,
+		},
+	}
+}
+
+func (manager *Manager) handleNowPlaying(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if player.GetCurrentItem() == nil {
+		transaction.Finish()
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "playback.nothing_playing"),
+				Flags:   64,
+			},
+		}
+	}
+
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleNowPlaying: %v", err))
+				transaction.Status = sentry.SpanStatusInternalError
+			}
+			transaction.Finish()
+		}()
+
+		// The card goes out as a regular channel message so it can keep being
+		// edited after the interaction token expires.
+		content := tr(interaction, "playback.card_posted")
+		err := player.PostNowPlayingCard(interaction.ChannelID)
+		switch {
+		case errors.Is(err, controller.ErrNothingPlaying):
+			content = tr(interaction, "playback.card_song_ended")
+		case discord.IsMissingPermissions(err):
+			content = tr(interaction, "playback.card_no_permission")
+		case err != nil:
+			sentryhelper.CaptureException(ctx, err)
+			content = tr(interaction, "playback.card_failed")
+		}
+		manager.SendFollowup(ctx, interaction, "", content, true)
+	}()
+
+	return Response{
+		Type: 5,
+		Data: ResponseData{
+			Flags: 64,
+		},
+	}
+}
+
 // todo: need to assure the user is in the voice channel
 func (manager *Manager) handlePause(ctx context.Context, interaction *Interaction) Response {
 	userName := interaction.Member.User.Username
 	player := manager.Controller.GetPlayer(interaction.GuildID)
 
 	if !player.Player.IsPlaying() {
-		hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+		hint := manager.hint(interaction)
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "nothing is playing" + hint,
+				Content: tr(interaction, "playback.nothing_playing_lower") + hint,
 			},
 		}
 	}
@@ -74,12 +157,12 @@ func (manager *Manager) handlePause(ctx context.Context, interaction *Interactio
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
 	djResponse := helpers.GenerateDJResponse(djCtx, "pause")
-	hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+	hint := manager.hint(interaction)
 
 	return Response{
 		Type: 4,
 		Data: ResponseData{
-			Content: "@" + userName + " paused - " + djResponse + hint,
+			Content: tr(interaction, "playback.paused", userName) + " - " + djResponse + hint,
 		},
 	}
 }
@@ -90,11 +173,11 @@ func (manager *Manager) handleResume(ctx context.Context, interaction *Interacti
 	player.LastActivityAt = time.Now()
 
 	if !player.Player.IsPlaying() {
-		hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+		hint := manager.hint(interaction)
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "nothing is playing" + hint,
+				Content: tr(interaction, "playback.nothing_playing_lower") + hint,
 			},
 		}
 	}
@@ -105,7 +188,7 @@ func (manager *Manager) handleResume(ctx context.Context, interaction *Interacti
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
 	djResponse := helpers.GenerateDJResponse(djCtx, "resume")
-	hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+	hint := manager.hint(interaction)
 
 	return Response{
 		Type: 4,
@@ -124,20 +207,14 @@ func (manager *Manager) handleLoop(ctx context.Context, interaction *Interaction
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
 	djResponse := helpers.GenerateDJResponse(djCtx, "loop", newState)
-	hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+	hint := manager.hint(interaction)
 
-	var emoji, status string
+	msg := "➡️ " + tr(interaction, "playback.loop_disabled", djResponse)
 	if newState {
-		emoji = "🔂"
-		status = "**enabled**"
-	} else {
-		emoji = "➡️"
-		status = "**disabled**"
+		msg = "🔂 " + tr(interaction, "playback.loop_enabled", djResponse)
 	}
-
-	msg := fmt.Sprintf("%s Loop mode %s — %s", emoji, status, djResponse)
 	if song := player.GetCurrentSong(); song != nil && newState {
-		msg += fmt.Sprintf(" (current: **%s**)", *song)
+		msg += " " + tr(interaction, "playback.loop_current", *song)
 	}
 
 	return Response{
@@ -189,7 +266,7 @@ func (manager *Manager) handleRadio(ctx context.Context, interaction *Interactio
 			return Response{
 				Type: 4,
 				Data: ResponseData{
-					Content: "📻 Join a voice channel first, then try again.",
+					Content: tr(interaction, "playback.radio_join_first"),
 				},
 			}
 		}
@@ -201,7 +278,7 @@ func (manager *Manager) handleRadio(ctx context.Context, interaction *Interactio
 				return Response{
 					Type: 4,
 					Data: ResponseData{
-						Content: "📻 Couldn't join your voice channel: " + err.Error(),
+						Content: tr(interaction, "playback.radio_join_failed", err),
 					},
 				}
 			}
@@ -233,26 +310,26 @@ func (manager *Manager) handleRadio(ctx context.Context, interaction *Interactio
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
 	djResponse := helpers.GenerateDJResponse(djCtx, "radio", enabled)
-	hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+	hint := manager.hint(interaction)
 
 	var msg string
 	if enabled {
 		theme := player.GetRadioTheme()
 		switch {
 		case genre != "":
-			msg = fmt.Sprintf("📻 Radio mode **enabled** — genre: *%s* — %s", genre, djResponse)
+			msg = tr(interaction, "playback.radio_genre", genre, djResponse)
 		case artistOpt != "":
-			msg = fmt.Sprintf("📻 Radio mode **enabled** — artist: *%s* — %s", artistOpt, djResponse)
+			msg = tr(interaction, "playback.radio_artist", artistOpt, djResponse)
 		case theme != "":
-			msg = fmt.Sprintf("📻 Radio mode **enabled** — vibing to *%s* — %s", theme, djResponse)
+			msg = tr(interaction, "playback.radio_theme", theme, djResponse)
 		default:
-			msg = "📻 Radio mode **enabled** — " + djResponse
+			msg = tr(interaction, "playback.radio_enabled", djResponse)
 		}
 		if player.SongHistory.Len() == 0 && !modeRequested {
-			msg += "\n*Queue a few songs first so I have something to go off of.*"
+			msg += "\n" + tr(interaction, "playback.radio_needs_history")
 		}
 	} else {
-		msg = "📻 Radio mode **disabled** — " + djResponse
+		msg = tr(interaction, "playback.radio_disabled", djResponse)
 	}
 
 	return Response{
@@ -280,7 +357,7 @@ func (manager *Manager) handleRequest(ctx context.Context, transaction *sentry.S
 		}
 	}
 	if suggestion == "" {
-		manager.SendFollowup(ctx, interaction, "", "Tell me what you want to hear! 🎵", true)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playback.request_empty"), true)
 		return
 	}
 
@@ -288,11 +365,11 @@ func (manager *Manager) handleRequest(ctx context.Context, transaction *sentry.S
 	if err != nil {
 		log.Errorf("Error getting voice state: %v", err)
 		sentryhelper.CaptureException(ctx, err)
-		manager.SendError(interaction, "Error getting voice state: "+err.Error(), true)
+		manager.SendError(interaction, tr(interaction, "common.voice_state_error", err.Error()), true)
 		return
 	}
 	if voiceState == nil {
-		manager.SendFollowup(ctx, interaction, "", "Join a voice channel first! 🎤", true)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "common.join_voice_first"), true)
 		return
 	}
 
@@ -301,13 +378,15 @@ func (manager *Manager) handleRequest(ctx context.Context, transaction *sentry.S
 		if err := player.JoinVoiceChannel(interaction.Member.User.ID); err != nil {
 			errStr := err.Error()
 			if errStr == "voice state not found" {
-				manager.SendFollowup(ctx, interaction, "", "You gotta join a voice channel first!", true)
+				manager.SendFollowup(ctx, interaction, "", tr(interaction, "common.join_voice_first"), true)
 				return
 			}
 			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Error joining voice channel: "+errStr, true)
+			manager.SendError(interaction, tr(interaction, "common.join_voice_error", errStr), true)
 			return
 		}
+	} else if manager.refuseBusy(interaction, player, voiceState.ChannelID) {
+		return
 	}
 
 	// Get recent history for context
@@ -320,7 +399,7 @@ func (manager *Manager) handleRequest(ctx context.Context, transaction *sentry.S
 	// Generate search queries via Gemini
 	queries := gemini.GenerateRequestQueries(ctx, suggestion, recentSongs)
 	if len(queries) == 0 {
-		manager.SendFollowup(ctx, interaction, "", "Couldn't come up with anything for that. Try a different suggestion? 🤔", true)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playback.request_no_ideas"), true)
 		return
 	}
 
@@ -355,7 +434,7 @@ func (manager *Manager) handleRequest(ctx context.Context, transaction *sentry.S
 	}
 
 	if len(picks) == 0 {
-		manager.SendFollowup(ctx, interaction, "", "Found nothing good for that. Try something else? 🎵", true)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playback.request_no_results"), true)
 		return
 	}
 
@@ -374,11 +453,11 @@ func (manager *Manager) handleRequest(ctx context.Context, transaction *sentry.S
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("🎧 **DJ Request:** *%s*\n\n", suggestion))
+	sb.WriteString(tr(interaction, "playback.request_header", suggestion) + "\n\n")
 	for i, title := range queued {
 		sb.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, title))
 	}
-	sb.WriteString("\n📻 Radio mode set to this vibe — I'll keep it going.")
+	sb.WriteString("\n" + tr(interaction, "playback.request_radio"))
 
 	manager.SendFollowup(ctx, interaction, "", sb.String(), false)
 }
@@ -407,7 +486,7 @@ func (manager *Manager) handleAnnounce(ctx context.Context, interaction *Interac
 			return Response{
 				Type: 4,
 				Data: ResponseData{
-					Content: fmt.Sprintf("Unknown voice **%s**. Available voices: %s", voiceOption, voiceList),
+					Content: tr(interaction, "playback.unknown_voice", voiceOption, voiceList),
 					Flags:   64,
 				},
 			}
@@ -433,10 +512,10 @@ func (manager *Manager) handleAnnounce(ctx context.Context, interaction *Interac
 			player.TriggerTTSRegen()
 		}
 
-		hint := manager.Hints.ShowIfApplicable(guildID)
-		msg := fmt.Sprintf("🎙️ DJ voice set to **%s**", matchedVoice)
+		hint := manager.hint(interaction)
+		msg := tr(interaction, "playback.voice_set", matchedVoice)
 		if wasDisabled {
-			msg += " — announcements enabled"
+			msg += " — " + tr(interaction, "playback.voice_set_enabled")
 		}
 
 		return Response{
@@ -465,13 +544,13 @@ func (manager *Manager) handleAnnounce(ctx context.Context, interaction *Interac
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
 	djResponse := helpers.GenerateDJResponse(djCtx, "announce", player.GetAnnounceEnabled())
-	hint := manager.Hints.ShowIfApplicable(guildID)
+	hint := manager.hint(interaction)
 
 	var msg string
 	if player.AnnounceEnabled {
-		msg = "🎙️ Voice announcements **enabled** — " + djResponse
+		msg = tr(interaction, "playback.announce_enabled", djResponse)
 	} else {
-		msg = "🔇 Voice announcements **disabled** — " + djResponse
+		msg = tr(interaction, "playback.announce_disabled", djResponse)
 	}
 
 	return Response{
@@ -482,6 +561,48 @@ func (manager *Manager) handleAnnounce(ctx context.Context, interaction *Interac
 	}
 }
 
+// handleTranslate toggles inline Gemini translation of non-Latin song titles
+// in /view and the now-playing card.
+func (manager *Manager) handleTranslate(interaction *Interaction) Response {
+	guildID := interaction.GuildID
+	player := manager.Controller.GetPlayer(guildID)
+
+	if !config.Config.Gemini.Enabled {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "playback.translate_unavailable"),
+				Flags:   64,
+			},
+		}
+	}
+
+	enabled := !player.GetTranslateTitles()
+	player.SetTranslateTitles(enabled)
+
+	enabledStr := "false"
+	if enabled {
+		enabledStr = "true"
+	}
+	if player.DB != nil {
+		if err := player.DB.SetGuildSetting(guildID, "translate_titles", enabledStr); err != nil {
+			log.Errorf("Failed to save translate setting: %v", err)
+		}
+	}
+
+	msg := tr(interaction, "playback.translate_disabled")
+	if enabled {
+		msg = tr(interaction, "playback.translate_enabled")
+	}
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
+
 func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -498,11 +619,11 @@ func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry
 	if err != nil {
 		log.Errorf("Error getting voice state: %v", err)
 		sentryhelper.CaptureException(ctx, err)
-		manager.SendError(interaction, "Error getting voice state: "+err.Error(), true)
+		manager.SendError(interaction, tr(interaction, "common.voice_state_error", err.Error()), true)
 		return
 	}
 	if voiceState == nil {
-		manager.SendRequest(interaction, "Join a voice channel first!", true)
+		manager.SendRequest(interaction, tr(interaction, "common.join_voice_first"), true)
 		return
 	}
 
@@ -512,9 +633,11 @@ func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry
 	if player.ShouldJoinVoice(voiceState.ChannelID) {
 		if err := player.JoinVoiceChannel(interaction.Member.User.ID); err != nil {
 			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Error joining voice channel: "+err.Error(), true)
+			manager.SendError(interaction, tr(interaction, "common.join_voice_error", err.Error()), true)
 			return
 		}
+	} else if manager.refuseBusy(interaction, player, voiceState.ChannelID) {
+		return
 	}
 
 	// Get voice from option, or from guild settings, or default to "Kore"
@@ -544,20 +667,20 @@ func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry
 	// Generate a short demo script via Gemini
 	script := gemini.GenerateRaw(ctx, "Say something cool and brief as a radio DJ in one sentence. No markdown.")
 	if script == "" {
-		script = "Testing, testing. Your DJ is live and ready to drop some beats."
+		script = tr(interaction, "playback.demo_script")
 	}
 
 	// Generate TTS audio
 	provider := tts.Get()
 	if provider == nil {
-		manager.SendError(interaction, "TTS provider not configured", true)
+		manager.SendError(interaction, tr(interaction, "playback.tts_unavailable"), true)
 		return
 	}
 	audioBytes, err := provider.Synthesize(ctx, script, tts.ResolveVoice(voice))
 	if err != nil {
 		log.Errorf("TTS generation failed: %v", err)
 		sentryhelper.CaptureException(ctx, err)
-		manager.SendError(interaction, "TTS generation failed: "+err.Error(), true)
+		manager.SendError(interaction, tr(interaction, "playback.tts_failed", err), true)
 		return
 	}
 
@@ -566,7 +689,7 @@ func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry
 	if convErr != nil {
 		log.Errorf("TTS audio conversion failed: %v", convErr)
 		sentryhelper.CaptureException(ctx, convErr)
-		manager.SendError(interaction, "Audio conversion failed: "+convErr.Error(), true)
+		manager.SendError(interaction, tr(interaction, "playback.tts_convert_failed", convErr), true)
 		return
 	}
 	ttsPlayback := &audio.TTSPlayback{Samples: samples}
@@ -577,7 +700,7 @@ func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry
 	player.VoiceChannelMutex.RUnlock()
 
 	if vc == nil {
-		manager.SendError(interaction, "Not connected to a voice channel", true)
+		manager.SendError(interaction, tr(interaction, "voice.not_connected"), true)
 		return
 	}
 
@@ -586,7 +709,7 @@ func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry
 	if err != nil {
 		log.Errorf("Error creating opus encoder: %v", err)
 		sentryhelper.CaptureException(ctx, err)
-		manager.SendError(interaction, "Error creating audio encoder", true)
+		manager.SendError(interaction, tr(interaction, "playback.encoder_failed"), true)
 		return
 	}
 	// Important: We must use the same bit depth and complexity as the main player
@@ -595,7 +718,7 @@ func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry
 	encoder.SetComplexity(10)
 
 	if player.Player.IsPlaying() {
-		manager.SendError(interaction, "Stop the music before previewing a voice", true)
+		manager.SendError(interaction, tr(interaction, "playback.demo_busy"), true)
 		return
 	}
 
@@ -628,7 +751,7 @@ func (manager *Manager) handleVoiceDemo(ctx context.Context, transaction *sentry
 	vc.Speaking(false)
 
 	// Send followup message with the voice name and the script text
-	manager.SendRequest(interaction, fmt.Sprintf("🎙️ Voice preview (**%s**): *%s*", voice, script), false)
+	manager.SendRequest(interaction, tr(interaction, "playback.demo", voice, script), false)
 }
 
 func (manager *Manager) handleVoices(interaction *Interaction) Response {
@@ -640,7 +763,7 @@ func (manager *Manager) handleVoices(interaction *Interaction) Response {
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "TTS provider not configured.",
+				Content: tr(interaction, "playback.tts_unavailable"),
 				Flags:   64,
 			},
 		}
@@ -657,15 +780,15 @@ func (manager *Manager) handleVoices(interaction *Interaction) Response {
 	}
 
 	var msg strings.Builder
-	msg.WriteString(fmt.Sprintf("🎙️ **Available TTS Voices** — %s (current: **%s**)\n", provider.Name(), currentVoice))
+	msg.WriteString(tr(interaction, "playback.voices_header", provider.Name(), currentVoice) + "\n")
 	for i, v := range provider.Voices() {
 		if strings.EqualFold(v, currentVoice) {
-			msg.WriteString(fmt.Sprintf("`%2d.` **%s** ← active\n", i+1, v))
+			msg.WriteString(fmt.Sprintf("`%2d.` **%s** ← %s\n", i+1, v, tr(interaction, "playback.voices_active")))
 		} else {
 			msg.WriteString(fmt.Sprintf("`%2d.` %s\n", i+1, v))
 		}
 	}
-	msg.WriteString("\nUse `/announce voice:<name>` to switch")
+	msg.WriteString("\n" + tr(interaction, "playback.voices_footer"))
 
 	return Response{
 		Type: 4,
@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/database"
+	"beatbot/resolver"
+	"beatbot/sentryhelper"
+	"beatbot/unfurl"
+	"beatbot/youtube"
+)
+
+const (
+	// maxPlaylistTracks caps how many songs one playlist can hold.
+	maxPlaylistTracks = 200
+	// maxPlaylistMessage leaves room under Discord's 2000 character limit
+	// for the "…and N more" line.
+	maxPlaylistMessage = 1900
+)
+
+// playlistTrackVideo turns a stored playlist track back into something the
+// player can queue.
+func playlistTrackVideo(t database.PlaylistTrack) youtube.VideoResponse {
+	return savedVideo(t.VideoID, t.Title, t.URL, t.Source)
+}
+
+func playlistOwnerLabel(interaction *Interaction, p database.Playlist) string {
+	if p.Shared() {
+		return tr(interaction, "playlists.owner_server")
+	}
+	return tr(interaction, "playlists.owner_yours")
+}
+
+// formatPlaylists lists the user's playlists, then the guild's.
+func formatPlaylists(interaction *Interaction, playlists []database.Playlist) string {
+	var mine, shared strings.Builder
+	for _, p := range playlists {
+		line := fmt.Sprintf("`%s` · %d %s\n", p.Name, p.TrackCount, pluralSongs(interaction, p.TrackCount))
+		if p.Shared() {
+			shared.WriteString(line)
+		} else {
+			mine.WriteString(line)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr(interaction, "playlists.title") + "\n")
+	if mine.Len() > 0 {
+		sb.WriteString("\n" + tr(interaction, "playlists.yours") + "\n" + mine.String())
+	}
+	if shared.Len() > 0 {
+		sb.WriteString("\n" + tr(interaction, "playlists.server") + "\n" + shared.String())
+	}
+	return sb.String()
+}
+
+// formatPlaylist lists a playlist's songs, numbered for /playlist remove,
+// stopping short of Discord's message limit.
+func formatPlaylist(interaction *Interaction, p database.Playlist, tracks []database.PlaylistTrack) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📁 **%s** (%s) · %d %s\n\n", p.Name, playlistOwnerLabel(interaction, p), len(tracks), pluralSongs(interaction, len(tracks))))
+	if len(tracks) == 0 {
+		sb.WriteString(tr(interaction, "playlists.empty_list"))
+		return sb.String()
+	}
+	for i, t := range tracks {
+		line := fmt.Sprintf("`%d.` %s\n", i+1, t.Title)
+		if sb.Len()+len(line) > maxPlaylistMessage {
+			sb.WriteString(tr(interaction, "common.and_more", len(tracks)-i))
+			break
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// playlistOptions reads the options shared by the /playlist subcommands.
+func playlistOptions(interaction *Interaction) (name, query string, position int, flag bool) {
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "name":
+			name = strings.TrimSpace(opt.Value)
+		case "query":
+			query = strings.TrimSpace(opt.Value)
+		case "position":
+			position, _ = strconv.Atoi(opt.Value)
+		case "shared", "shuffle":
+			flag = opt.Value == "true"
+		}
+	}
+	return name, query, position, flag
+}
+
+// handlePlaylistCommand routes /playlist subcommands. add and play resolve
+// songs or join voice, so they reply in a goroutine; the rest only touch the
+// database and answer right away.
+func (manager *Manager) handlePlaylistCommand(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
+	switch subcommandName(interaction) {
+	case "add":
+		go manager.onPlaylistAdd(ctx, transaction, interaction)
+		return Response{Type: 5, Data: ResponseData{Flags: 64}}
+	case "play":
+		go manager.onPlaylistPlay(ctx, transaction, interaction)
+		return Response{Type: 5}
+	}
+	defer transaction.Finish()
+
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	switch subcommandName(interaction) {
+	case "create":
+		return manager.handlePlaylistCreate(db, interaction)
+	case "remove":
+		return manager.handlePlaylistRemove(db, interaction)
+	case "list":
+		return manager.handlePlaylistList(db, interaction)
+	default:
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "playlists.unknown_command"), Flags: 64}}
+	}
+}
+
+func (manager *Manager) handlePlaylistCreate(db *database.Database, interaction *Interaction) Response {
+	name, _, _, shared := playlistOptions(interaction)
+	if name == "" {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "playlists.name_missing"), Flags: 64}}
+	}
+
+	userID := interaction.Member.User.ID
+	ownerID := userID
+	if shared {
+		ownerID = ""
+	}
+	playlist, err := db.CreatePlaylist(interaction.GuildID, ownerID, name, userID)
+	if errors.Is(err, database.ErrPlaylistExists) {
+		return Response{Type: 4, Data: ResponseData{
+			Content: tr(interaction, "playlists.exists", name),
+			Flags:   64,
+		}}
+	}
+	if err != nil {
+		log.Errorf("Error creating playlist: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "playlists.create_failed"), Flags: 64}}
+	}
+
+	return Response{Type: 4, Data: ResponseData{
+		Content: tr(interaction, "playlists.created", playlistOwnerLabel(interaction, *playlist), name),
+		Flags:   64,
+	}}
+}
+
+func (manager *Manager) handlePlaylistRemove(db *database.Database, interaction *Interaction) Response {
+	name, _, position, _ := playlistOptions(interaction)
+	playlist, err := db.FindPlaylist(interaction.GuildID, interaction.Member.User.ID, name)
+	if err != nil {
+		return playlistLookupFailed(interaction, name, err)
+	}
+	if position < 1 {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "playlists.invalid_position"), Flags: 64}}
+	}
+
+	title, err := db.RemovePlaylistTrack(playlist.ID, position)
+	if err != nil {
+		return Response{Type: 4, Data: ResponseData{
+			Content: tr(interaction, "playlists.no_position", playlist.Name, position),
+			Flags:   64,
+		}}
+	}
+
+	return Response{Type: 4, Data: ResponseData{
+		Content: tr(interaction, "playlists.removed", title, playlist.Name),
+		Flags:   64,
+	}}
+}
+
+func (manager *Manager) handlePlaylistList(db *database.Database, interaction *Interaction) Response {
+	name, _, _, _ := playlistOptions(interaction)
+	userID := interaction.Member.User.ID
+
+	if name == "" {
+		playlists, err := db.ListPlaylists(interaction.GuildID, userID)
+		if err != nil {
+			log.Errorf("Error listing playlists: %v", err)
+			return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "playlists.list_failed"), Flags: 64}}
+		}
+		if len(playlists) == 0 {
+			return Response{Type: 4, Data: ResponseData{
+				Content: tr(interaction, "playlists.none"),
+				Flags:   64,
+			}}
+		}
+		return Response{Type: 4, Data: ResponseData{Content: formatPlaylists(interaction, playlists)}}
+	}
+
+	playlist, err := db.FindPlaylist(interaction.GuildID, userID, name)
+	if err != nil {
+		return playlistLookupFailed(interaction, name, err)
+	}
+	tracks, err := db.GetPlaylistTracks(playlist.ID)
+	if err != nil {
+		log.Errorf("Error fetching playlist tracks: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "playlists.fetch_failed"), Flags: 64}}
+	}
+	return Response{Type: 4, Data: ResponseData{Content: formatPlaylist(interaction, *playlist, tracks)}}
+}
+
+func playlistLookupFailed(interaction *Interaction, name string, err error) Response {
+	content := tr(interaction, "playlists.not_found", name)
+	if !errors.Is(err, database.ErrPlaylistNotFound) {
+		log.Errorf("Error finding playlist: %v", err)
+		content = tr(interaction, "playlists.fetch_failed")
+	}
+	return Response{Type: 4, Data: ResponseData{Content: content, Flags: 64}}
+}
+
+// resolveSingleTrack finds one song to save for later (/playlist add,
+// /playat): the current one when query is empty, otherwise the first result
+// for a link or search.
+func (manager *Manager) resolveSingleTrack(ctx context.Context, interaction *Interaction, query string) (youtube.VideoResponse, string) {
+	if query == "" {
+		item := manager.Controller.GetPlayer(interaction.GuildID).GetCurrentItem()
+		if item == nil {
+			return youtube.VideoResponse{}, tr(interaction, "playlists.nothing_playing")
+		}
+		return item.Video, ""
+	}
+
+	query, err := unfurl.Expand(ctx, query)
+	if err != nil {
+		return youtube.VideoResponse{}, tr(interaction, "playlists.link_failed", err)
+	}
+	if resolver.IsCollection(query) {
+		return youtube.VideoResponse{}, tr(interaction, "playlists.single_song_only")
+	}
+
+	resolution, err := resolver.Resolve(ctx, query, "")
+	var refusal *resolver.Refusal
+	if errors.As(err, &refusal) {
+		return youtube.VideoResponse{}, refusal.Reason
+	}
+	if err != nil {
+		log.Errorf("Error resolving song query %q: %v", query, err)
+		sentryhelper.CaptureException(ctx, err)
+		return youtube.VideoResponse{}, tr(interaction, "playlists.search_failed", err)
+	}
+	if len(resolution.Tracks) == 0 {
+		return youtube.VideoResponse{}, tr(interaction, "playlists.nothing_found", query)
+	}
+	return resolution.Tracks[0], ""
+}
+
+func (manager *Manager) onPlaylistAdd(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onPlaylistAdd: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	db := manager.Controller.GetDB()
+	if db == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "common.db_unavailable"), true)
+		return
+	}
+
+	name, query, _, _ := playlistOptions(interaction)
+	playlist, err := db.FindPlaylist(interaction.GuildID, interaction.Member.User.ID, name)
+	if err != nil {
+		manager.SendFollowup(ctx, interaction, "", playlistLookupFailed(interaction, name, err).Data.Content, true)
+		return
+	}
+	if playlist.TrackCount >= maxPlaylistTracks {
+		manager.SendFollowup(ctx, interaction, "",
+			tr(interaction, "playlists.full", playlist.Name, maxPlaylistTracks), true)
+		return
+	}
+
+	video, problem := manager.resolveSingleTrack(ctx, interaction, query)
+	if problem != "" {
+		manager.SendFollowup(ctx, interaction, "", problem, true)
+		return
+	}
+
+	track := database.PlaylistTrack{
+		VideoID: video.VideoID,
+		Title:   video.Title,
+		URL:     video.PageURL(),
+		Source:  video.Source,
+		AddedBy: interaction.Member.User.ID,
+	}
+	added, err := db.AddPlaylistTrack(playlist.ID, track)
+	if err != nil {
+		log.Errorf("Error adding playlist track: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.add_failed"), true)
+		return
+	}
+	if !added {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.already_added", video.Title, playlist.Name), true)
+		return
+	}
+	manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.added", video.Title, playlist.Name), true)
+}
+
+// onPlaylistPlay queues a whole playlist in order, or shuffled. Songs already
+// queued or blocked are left out.
+func (manager *Manager) onPlaylistPlay(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onPlaylistPlay: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	db := manager.Controller.GetDB()
+	if db == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "common.db_unavailable"), true)
+		return
+	}
+
+	name, _, _, shuffle := playlistOptions(interaction)
+	playlist, err := db.FindPlaylist(interaction.GuildID, interaction.Member.User.ID, name)
+	if err != nil {
+		manager.SendFollowup(ctx, interaction, "", playlistLookupFailed(interaction, name, err).Data.Content, true)
+		return
+	}
+	tracks, err := db.GetPlaylistTracks(playlist.ID)
+	if err != nil {
+		log.Errorf("Error fetching playlist tracks: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.fetch_failed"), true)
+		return
+	}
+	if len(tracks) == 0 {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.empty", playlist.Name), true)
+		return
+	}
+
+	videos := make([]youtube.VideoResponse, 0, len(tracks))
+	for _, t := range tracks {
+		videos = append(videos, playlistTrackVideo(t))
+	}
+	if shuffle {
+		rand.Shuffle(len(videos), func(i, j int) {
+			videos[i], videos[j] = videos[j], videos[i]
+		})
+	}
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	videosToQueue := dropQueued(player, manager.filterBlocked(interaction.GuildID, videos))
+	if len(videosToQueue) == 0 {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.all_skipped", playlist.Name), true)
+		return
+	}
+
+	if !manager.joinRequesterVoice(ctx, interaction, player) {
+		return
+	}
+
+	for _, video := range videosToQueue {
+		player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, nil)
+	}
+
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "queue",
+		Message:  fmt.Sprintf("Queued %d tracks from playlist %d", len(videosToQueue), playlist.ID),
+		Level:    sentry.LevelInfo,
+	})
+
+	key := "playlists.queued"
+	if shuffle {
+		key = "playlists.queued_shuffled"
+	}
+	response := tr(interaction, key, len(videosToQueue), pluralSongs(interaction, len(videosToQueue)), playlist.Name)
+	if skipped := len(tracks) - len(videosToQueue); skipped > 0 {
+		response += " " + tr(interaction, "common.skipped_count", skipped)
+	}
+	manager.SendFollowup(ctx, interaction, fmt.Sprintf("Queued %d songs from the playlist %q", len(videosToQueue), playlist.Name), response, false)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"beatbot/database"
+)
+
+func TestFormatPlaylists(t *testing.T) {
+	got := formatPlaylists(&Interaction{}, []database.Playlist{
+		{Name: "Road Trip", OwnerID: "u1", TrackCount: 1},
+		{Name: "Party", TrackCount: 12},
+	})
+	want := "📁 **Playlists**\n\n**Yours**\n`Road Trip` · 1 song\n\n**Server**\n`Party` · 12 songs\n"
+	if got != want {
+		t.Errorf("formatPlaylists = %q, want %q", got, want)
+	}
+}
+
+func TestFormatPlaylist(t *testing.T) {
+	p := database.Playlist{Name: "Road Trip", OwnerID: "u1"}
+	if got := formatPlaylist(&Interaction{}, p, nil); !strings.Contains(got, "Nothing here yet") {
+		t.Errorf("empty playlist = %q", got)
+	}
+
+	got := formatPlaylist(&Interaction{}, p, []database.PlaylistTrack{{Title: "First"}, {Title: "Second"}})
+	if !strings.HasPrefix(got, "📁 **Road Trip** (your playlist) · 2 songs") || !strings.Contains(got, "`2.` Second") {
+		t.Errorf("formatPlaylist = %q", got)
+	}
+
+	tracks := make([]database.PlaylistTrack, maxPlaylistTracks)
+	for i := range tracks {
+		tracks[i].Title = strings.Repeat("x", 60)
+	}
+	got = formatPlaylist(&Interaction{}, database.Playlist{Name: "Big"}, tracks)
+	if len(got) > 2000 || !strings.Contains(got, "more") {
+		t.Errorf("long playlist is %d bytes, want truncated under 2000 with a count", len(got))
+	}
+}
+
+func TestPlaylistTrackVideo(t *testing.T) {
+	video := playlistTrackVideo(database.PlaylistTrack{VideoID: "123", Title: "Song", URL: "https://soundcloud.com/a/b", Source: "soundcloud"})
+	if video.IsYouTube() || video.URL != "https://soundcloud.com/a/b" {
+		t.Errorf("video = %+v, want the SoundCloud page URL kept", video)
+	}
+}
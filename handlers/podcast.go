@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+	"beatbot/podcast"
+	"beatbot/resolver"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+// podcastChoices is how many recent episodes the /podcast menu offers.
+const podcastChoices = 10
+
+// podcastMenu picks the newest episodes short enough to play and builds the
+// menu for them: one option per episode, described by date and length.
+// Episodes are loaded into memory like any audio file, so ones the feed says
+// run over resolver.MaxAudioFileDuration are left out and counted.
+func podcastMenu(feed *podcast.Feed) (videos []youtube.VideoResponse, options []discordgo.SelectMenuOption, tooLong int) {
+	for _, episode := range feed.Episodes {
+		if episode.Duration > resolver.MaxAudioFileDuration {
+			tooLong++
+			continue
+		}
+		if len(videos) == podcastChoices {
+			continue
+		}
+
+		var details []string
+		if !episode.Published.IsZero() {
+			details = append(details, episode.Published.Format("Jan 2, 2006"))
+		}
+		if episode.Duration > 0 {
+			details = append(details, discord.FormatDuration(episode.Duration))
+		}
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       truncateRunes(episode.Title, selectTextLimit),
+			Value:       strconv.Itoa(len(videos)),
+			Description: truncateRunes(strings.Join(details, " · "), selectTextLimit),
+		})
+		videos = append(videos, youtube.VideoResponse{
+			Title:       episode.Title,
+			ChannelName: feed.Title,
+			Duration:    episode.Duration,
+			Source:      youtube.SourceFile,
+			URL:         episode.AudioURL,
+		})
+	}
+	return videos, options, tooLong
+}
+
+func (manager *Manager) handlePodcast(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
+	go manager.onPodcast(ctx, transaction, interaction)
+
+	// Deferred ephemeral: only the listener picking sees the menu.
+	return Response{
+		Type: 5,
+		Data: ResponseData{
+			Flags: 64,
+		},
+	}
+}
+
+// onPodcast fetches the feed and offers its recent episodes as a select menu.
+func (manager *Manager) onPodcast(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onPodcast: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	var feedURL string
+	for _, opt := range commandOptions(interaction) {
+		if opt.Name == "feed" {
+			feedURL = strings.TrimSpace(opt.Value)
+		}
+	}
+	if u, err := url.Parse(feedURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "podcast.not_a_link"), true)
+		return
+	}
+
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "podcast",
+		Message:  "Fetching podcast feed: " + feedURL,
+		Level:    sentry.LevelInfo,
+	})
+
+	feed, err := podcast.Fetch(ctx, feedURL)
+	if errors.Is(err, podcast.ErrNotFeed) {
+		manager.SendFollowup(ctx, interaction, "",
+			"That link isn't a podcast feed. Look for the RSS link on the show's website or podcast app.", true)
+		return
+	}
+	if err != nil {
+		log.Warnf("Error fetching podcast feed %s: %v", feedURL, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "podcast.feed_failed", err.Error()), true)
+		return
+	}
+
+	title := feed.Title
+	if title == "" {
+		title = tr(interaction, "podcast.untitled")
+	}
+	videos, options, tooLong := podcastMenu(feed)
+	switch {
+	case len(feed.Episodes) == 0:
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "podcast.no_episodes", title), true)
+		return
+	case len(videos) == 0:
+		manager.SendFollowup(ctx, interaction, "",
+			tr(interaction, "podcast.all_too_long", title, int(resolver.MaxAudioFileDuration.Minutes())), true)
+		return
+	}
+
+	manager.searches.put(interaction.GuildID, interaction.Member.User.ID, pendingSearch{
+		videos:      videos,
+		sourceLabel: "podcast episode",
+		expires:     time.Now().Add(searchTTL),
+	})
+
+	content := tr(interaction, "podcast.menu", title)
+	if tooLong > 0 {
+		content += "\n" + tr(interaction, "podcast.some_too_long",
+			tooLong, pluralEpisodes(interaction, tooLong), int(resolver.MaxAudioFileDuration.Minutes()))
+	}
+	discord.SendFollowup(&discord.FollowUpRequest{
+		Token:      interaction.Token,
+		AppID:      manager.AppID,
+		UserID:     interaction.Member.User.ID,
+		Content:    content,
+		Components: discord.SelectMenu(interaction.GuildID, "podcast_pick", tr(interaction, "podcast.placeholder"), options),
+	})
+}
+
+func pluralEpisodes(interaction *Interaction, n int) string {
+	if n == 1 {
+		return tr(interaction, "podcast.episode")
+	}
+	return tr(interaction, "podcast.episodes")
+}
+
+// handlePodcastPick queues the episode picked from a /podcast menu. The file
+// is probed first, since feeds don't always give a length and some lie.
+func (manager *Manager) handlePodcastPick(interaction *Interaction) Response {
+	search, ok := manager.searches.take(interaction.GuildID, interaction.Member.User.ID)
+	if !ok {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "podcast.expired"),
+				Flags:   64,
+			},
+		}
+	}
+
+	index := -1
+	if len(interaction.Data.Values) == 1 {
+		index, _ = strconv.Atoi(interaction.Data.Values[0])
+	}
+	if index < 0 || index >= len(search.videos) {
+		return Response{Type: 7, Data: ResponseData{
+			Content:    tr(interaction, "podcast.bad_pick"),
+			Components: discord.DisabledButton(tr(interaction, "search.bad_pick_button")),
+		}}
+	}
+	episode := search.videos[index]
+
+	go func() {
+		ctx, transaction := sentryhelper.StartCommandTransaction(
+			context.Background(),
+			"podcast_pick",
+			interaction.GuildID,
+			interaction.Member.User.ID,
+		)
+		defer func() {
+			if err := recover(); err != nil {
+				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handlePodcastPick: %v", err))
+				transaction.Status = sentry.SpanStatusInternalError
+			}
+			transaction.Finish()
+		}()
+
+		video, err := resolver.ProbeAudio(ctx, episode.URL)
+		if err != nil {
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "podcast.unplayable", err.Error()), true)
+			return
+		}
+		if video.Live {
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "podcast.unknown_length"), true)
+			return
+		}
+		video.Title = episode.Title
+		video.ChannelName = episode.ChannelName
+		video.Normalize(false)
+
+		player := manager.Controller.GetPlayer(interaction.GuildID)
+		if !manager.joinRequesterVoice(ctx, interaction, player) {
+			return
+		}
+		log.WithFields(log.Fields{
+			"module":   "handlers",
+			"guild_id": interaction.GuildID,
+			"video_id": video.VideoID,
+			"podcast":  episode.ChannelName,
+			"user_id":  interaction.Member.User.ID,
+		}).Info("Queued podcast episode")
+		manager.queueVideo(ctx, interaction, player, video, nil, search.sourceLabel)
+	}()
+
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "search.picked", episode.Title),
+		Components: discord.DisabledButton(tr(interaction, "search.picked_button")),
+	}}
+}
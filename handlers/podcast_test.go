@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/podcast"
+	"beatbot/youtube"
+)
+
+func TestPodcastMenu(t *testing.T) {
+	feed := &podcast.Feed{Title: "Switched On Pop"}
+	published := time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)
+	feed.Episodes = append(feed.Episodes,
+		podcast.Episode{Title: "Deep dive", AudioURL: "https://cdn.example.com/long.mp3", Duration: 90 * time.Minute},
+		podcast.Episode{Title: "Quick take", AudioURL: "https://cdn.example.com/quick.mp3", Published: published, Duration: 24*time.Minute + 13*time.Second},
+		podcast.Episode{Title: "No length", AudioURL: "https://cdn.example.com/unknown.mp3"},
+	)
+	for i := 0; i < podcastChoices; i++ {
+		feed.Episodes = append(feed.Episodes, podcast.Episode{Title: "Filler", AudioURL: "https://cdn.example.com/f.mp3", Duration: time.Minute})
+	}
+
+	videos, options, tooLong := podcastMenu(feed)
+	if tooLong != 1 {
+		t.Errorf("tooLong = %d, want 1", tooLong)
+	}
+	if len(videos) != podcastChoices || len(options) != podcastChoices {
+		t.Fatalf("got %d videos and %d options, want %d", len(videos), len(options), podcastChoices)
+	}
+	if v := videos[0]; v.Title != "Quick take" || v.Source != youtube.SourceFile ||
+		v.URL != "https://cdn.example.com/quick.mp3" || v.ChannelName != "Switched On Pop" {
+		t.Errorf("first episode = %+v", v)
+	}
+	if got := options[0].Description; !strings.HasPrefix(got, "Mar 9, 2026 · ") {
+		t.Errorf("description = %q, want the date then the length", got)
+	}
+	if options[0].Value != "0" || options[1].Value != "1" {
+		t.Errorf("values = %q, %q", options[0].Value, options[1].Value)
+	}
+	if got := options[1].Description; got != "" {
+		t.Errorf("episode without date or length described as %q", got)
+	}
+}
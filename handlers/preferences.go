@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/database"
+	"beatbot/i18n"
+)
+
+// followServerLanguage is the /preferences language choice that clears the
+// preference.
+const followServerLanguage = "server"
+
+// preferenceLanguages are the languages with their own i18n catalog, by
+// their own names.
+var preferenceLanguages = []struct{ code, name string }{
+	{"en", "English"},
+	{"es", "Español"},
+	{"fr", "Français"},
+	{"de", "Deutsch"},
+	{"pt", "Português"},
+}
+
+func languageChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := []*discordgo.ApplicationCommandOptionChoice{{Name: "The server's language", Value: followServerLanguage}}
+	for _, lang := range preferenceLanguages {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: lang.name, Value: lang.code})
+	}
+	return choices
+}
+
+// loadPreferences reads the invoker's /preferences onto the interaction, so
+// replies are in their language.
+func (manager *Manager) loadPreferences(interaction *Interaction) {
+	if manager.Controller == nil || interaction.Member.User.ID == "" {
+		return
+	}
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return
+	}
+	prefs, err := db.GetUserPreferences(interaction.Member.User.ID)
+	if err != nil {
+		log.Warnf("Failed to load preferences: %v", err)
+		return
+	}
+	interaction.prefs = prefs
+}
+
+// handlePreferences saves whichever preferences were given and shows them
+// all. They're the invoker's own, so they apply in every server.
+func (manager *Manager) handlePreferences(interaction *Interaction) Response {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	userID := interaction.Member.User.ID
+	for _, opt := range commandOptions(interaction) {
+		var key, value string
+		switch opt.Name {
+		case "language":
+			key, value = database.PrefLanguage, opt.Value
+			if value == followServerLanguage {
+				value = ""
+			}
+		case "private_replies":
+			key, value = database.PrefPrivateReplies, strconv.FormatBool(opt.Value == "true")
+		case "volume_offset":
+			offset, err := strconv.Atoi(opt.Value)
+			if err != nil || offset < -database.MaxVolumeOffset || offset > database.MaxVolumeOffset {
+				return Response{Type: 4, Data: ResponseData{
+					Content: tr(interaction, "preferences.offset_range", database.MaxVolumeOffset, database.MaxVolumeOffset),
+					Flags:   64,
+				}}
+			}
+			key, value = database.PrefVolumeOffset, strconv.Itoa(offset)
+		case "notify_up_next":
+			key, value = database.PrefNotifyUpNext, strconv.FormatBool(opt.Value == "true")
+		default:
+			continue
+		}
+		if err := db.SetUserSetting(userID, key, value); err != nil {
+			log.Errorf("Failed to save preference %s: %v", key, err)
+			return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "preferences.save_failed"), Flags: 64}}
+		}
+	}
+
+	prefs, err := db.GetUserPreferences(userID)
+	if err != nil {
+		log.Errorf("Failed to load preferences: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "preferences.load_failed"), Flags: 64}}
+	}
+	interaction.prefs = prefs
+	return Response{Type: 4, Data: ResponseData{Content: preferencesMessage(interaction.locale(), prefs), Flags: 64}}
+}
+
+// preferencesMessage lists prefs for /preferences in locale's language.
+func preferencesMessage(locale string, prefs database.UserPreferences) string {
+	language := i18n.T(locale, "preferences.server_language")
+	for _, lang := range preferenceLanguages {
+		if lang.code == prefs.Language {
+			language = lang.name
+		}
+	}
+	volume := i18n.T(locale, "preferences.volume_default")
+	if prefs.VolumeOffset != 0 {
+		volume = fmt.Sprintf("%+d", prefs.VolumeOffset)
+	}
+
+	lines := []string{
+		i18n.T(locale, "preferences.header"),
+		i18n.T(locale, "preferences.language", language),
+		i18n.T(locale, "preferences.private_replies", onOff(locale, prefs.PrivateReplies)),
+		i18n.T(locale, "preferences.volume", volume),
+		i18n.T(locale, "preferences.notify_up_next", onOff(locale, prefs.NotifyUpNext)),
+		i18n.T(locale, "preferences.footer"),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func onOff(locale string, on bool) string {
+	if on {
+		return i18n.T(locale, "preferences.on")
+	}
+	return i18n.T(locale, "preferences.off")
+}
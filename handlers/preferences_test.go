@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"beatbot/database"
+	"beatbot/i18n"
+)
+
+func TestTrUsesPreferredLanguage(t *testing.T) {
+	interaction := &Interaction{GuildLocale: "en-US"}
+	english := tr(interaction, "common.nothing_playing")
+
+	interaction.prefs.Language = "de"
+	if got := tr(interaction, "common.nothing_playing"); got == english {
+		t.Errorf("tr with a German preference = %q, want the German message", got)
+	}
+	if got := interaction.locale(); got != "de" {
+		t.Errorf("locale() = %q, want de", got)
+	}
+}
+
+// replyKey matches the catalog keys replies are looked up by, whether passed
+// to tr directly or picked into a variable first.
+var replyKey = regexp.MustCompile(`(?:tr\(interaction, |key :?= )"([a-z]+\.[a-z_]+)"`)
+
+// A key missing from the English catalog would be shown to users as is.
+func TestReplyKeysHaveEnglish(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range replyKey.FindAllStringSubmatch(string(src), -1) {
+			if key := m[1]; i18n.T("en", key) == key {
+				t.Errorf("%s: %q is not in the English catalog", file, key)
+			}
+		}
+	}
+}
+
+func TestPreferencesMessage(t *testing.T) {
+	msg := preferencesMessage("", database.UserPreferences{Language: "fr", VolumeOffset: -20, NotifyUpNext: true})
+	for _, want := range []string{"**Français**", "Private replies: **off**", "**-20**", "Up-next DMs: **on**"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+	if msg := preferencesMessage("", database.UserPreferences{}); !strings.Contains(msg, "the server's") || !strings.Contains(msg, "as everyone else's") {
+		t.Errorf("default preferences message:\n%s", msg)
+	}
+}
@@ -2,21 +2,22 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	sentry "github.com/getsentry/sentry-go"
 	log "github.com/sirupsen/logrus"
 
-	"beatbot/applemusic"
-	"beatbot/config"
 	"beatbot/controller"
 	"beatbot/discord"
 	"beatbot/helpers"
+	"beatbot/resolver"
 	"beatbot/sentryhelper"
-	"beatbot/spotify"
+	"beatbot/unfurl"
 	"beatbot/youtube"
 )
 
@@ -33,232 +34,412 @@ func (manager *Manager) QueryAndQueue(ctx context.Context, transaction *sentry.S
 	}()
 
 	log.Debugf("Querying and queuing: %+v", interaction.Member.User.ID)
-	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
-	if err != nil {
-		log.Errorf("Error getting voice state: %v", err)
-		sentryhelper.CaptureException(ctx, err)
-		manager.SendError(interaction, "Error getting voice state: "+err.Error(), true)
-		return
-	}
-
 	player := manager.Controller.GetPlayer(interaction.GuildID)
-
-	if voiceState == nil {
-		manager.SendFollowup(ctx, interaction, "The user is not in a voice channel and trying to play a song", "Hey dummy, join a voice channel first", true)
+	if !manager.joinRequesterVoice(ctx, interaction, player) {
 		return
 	}
 
-	// join vc if not in one, or move to requester's vc if stopped
-	if player.ShouldJoinVoice(voiceState.ChannelID) {
-		err := player.JoinVoiceChannel(interaction.Member.User.ID)
-		if err != nil {
-			errStr := err.Error()
-			if errStr != "" && errStr == "voice state not found" {
-				manager.SendFollowup(ctx, interaction, "You gotta join a voice channel first!", "Error joining voice channel: "+errStr, true)
-				return
-			}
-			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Error joining voice channel: "+errStr, true)
-			return
+	var query, source, startOpt, endOpt, attachmentID string
+	// /search always offers a choice; /play and /queue add only when asked.
+	pick := interaction.Data.Name == "search"
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "query":
+			query = opt.Value
+		case "source":
+			source = opt.Value
+		case "pick":
+			pick = opt.Value == "true"
+		case "start":
+			startOpt = opt.Value
+		case "end":
+			endOpt = opt.Value
+		case "file":
+			attachmentID = opt.Value
 		}
 	}
 
-	query := interaction.Data.Options[0].Value
-
-	if strings.HasPrefix(query, "https://open.spotify.com/") {
-		log.Debugf("Detected Spotify URL: %s", query)
-
-		// Check if Spotify is enabled
-		if !config.Config.Spotify.Enabled {
-			manager.SendFollowup(ctx, interaction, "", "Spotify integration is not enabled. Ask the bot admin to set SPOTIFY_ENABLED=true.", true)
+	if attachmentID != "" {
+		if query != "" {
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "queue.query_and_file"), true)
 			return
 		}
-
-		spotifyReq, err := spotify.ParseSpotifyURL(query)
-		if err != nil {
-			log.Errorf("Error parsing Spotify URL: %v", err)
-			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Invalid Spotify URL: "+err.Error(), true)
+		fileURL, problem := attachedAudioURL(interaction, attachmentID)
+		if problem != "" {
+			manager.SendFollowup(ctx, interaction, "", problem, true)
 			return
 		}
+		query = fileURL
+	}
+	if query == "" {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "queue.nothing_given"), true)
+		return
+	}
 
-		// Handle playlist URLs
-		if spotifyReq.PlaylistID != "" {
-			manager.handleSpotifyPlaylist(ctx, interaction, player, spotifyReq.PlaylistID)
-			return
-		}
+	clipStart, clipEnd, problem := parseClipOptions(interaction, startOpt, endOpt)
+	if problem != "" {
+		manager.SendFollowup(ctx, interaction, "", problem, true)
+		return
+	}
 
-		// Handle album URLs
-		if spotifyReq.AlbumID != "" {
-			manager.handleSpotifyAlbum(ctx, interaction, player, spotifyReq.AlbumID)
-			return
-		}
+	// Share links from phones (spotify.link, song.link) are expanded to
+	// the link they stand for before anything else looks at them.
+	query, err := unfurl.Expand(ctx, query)
+	if err != nil {
+		log.Warnf("Error expanding short link %s: %v", query, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.link_failed", err), true)
+		return
+	}
 
-		// Handle artist URLs (still coming soon)
-		if spotifyReq.ArtistID != "" {
-			manager.SendFollowup(ctx, interaction, "", "Spotify artists are coming soon! For now, please use track URLs, playlist URLs, or search queries.", true)
-			return
+	// A source picked as the server default may have outlived its
+	// integration; search as usual then.
+	if source == "" {
+		source = player.GetSearchSource()
+		if !resolver.Enabled(source) {
+			source = resolver.SourceAuto
 		}
+	}
 
-		if spotifyReq.TrackID == "" {
-			manager.SendFollowup(ctx, interaction, "", "Invalid Spotify URL. Please use a track, playlist, or album URL.", true)
-			return
-		}
+	resolution, err := resolver.Resolve(resolver.WithProgress(ctx, manager.reportProgress(ctx, interaction)), query, source)
+	if err != nil {
+		manager.sendResolveError(ctx, interaction, query, err)
+		return
+	}
 
-		log.Tracef("Fetching Spotify track: %s", spotifyReq.TrackID)
-		trackInfo, err := spotify.GetTrack(ctx, spotifyReq.TrackID)
-		if err != nil {
-			log.Errorf("Error fetching Spotify track: %v", err)
-			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Error fetching track from Spotify: "+err.Error(), true)
-			return
-		}
+	if resolution.Collection != nil {
+		manager.queueCollection(ctx, interaction, player, resolution)
+		return
+	}
 
-		artistsStr := strings.Join(trackInfo.Artists, ", ")
-		youtubeQuery := artistsStr + " - " + trackInfo.Title
-		log.Debugf("Converted Spotify track '%s' by '%s' to YouTube query: %s", trackInfo.Title, artistsStr, youtubeQuery)
+	videos := resolution.Tracks
+	if len(videos) == 0 {
+		manager.SendFollowup(ctx, interaction, "There wasn't anything found for "+query, tr(interaction, "queue.nothing_found"), true)
+		return
+	}
+	videos = manager.filterBlocked(interaction.GuildID, videos)
+	if len(videos) == 0 {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "queue.blocked", resolution.Tracks[0].Title), true)
+		return
+	}
 
-		manager.SendFollowup(ctx, interaction,
-			"",
-			fmt.Sprintf("Found **%s** by **%s** on Spotify, searching YouTube...", trackInfo.Title, artistsStr),
-			false)
+	if startOpt == "" {
+		clipStart = resolution.Start
+	}
+	// The picker doesn't carry start/end through, so a clip skips it.
+	if pick && resolution.Search && len(videos) > 1 && clipStart == 0 && clipEnd == 0 {
+		manager.offerSearchResults(interaction, query, videos, resolution.Label)
+		return
+	}
 
-		videos := youtube.Query(ctx, youtubeQuery)
-		if len(videos) == 0 {
-			log.Warnf("No YouTube results found for Spotify track: %s", youtubeQuery)
-			manager.SendFollowup(ctx, interaction,
-				"",
-				fmt.Sprintf("Couldn't find **%s** by **%s** on YouTube", trackInfo.Title, artistsStr),
-				true)
-			return
-		}
+	// A link resolves to the track it points at; the runners-up of a
+	// search or a YouTube match stand in if it won't play.
+	video := videos[0]
+	fallbacks := fallbackSlice(videos, 2)
 
-		videos = manager.filterBlocked(interaction.GuildID, videos)
-		if len(videos) == 0 {
-			manager.SendFollowup(ctx, interaction, "",
-				fmt.Sprintf("**%s** by **%s** is blocked from playing.", trackInfo.Title, artistsStr),
-				true)
+	if clipStart > 0 || clipEnd > 0 {
+		if problem := clipVideo(interaction, &video, clipStart, clipEnd); problem != "" {
+			manager.SendFollowup(ctx, interaction, "", problem, true)
 			return
 		}
+		// Fallbacks are other videos, where the same timestamps mean nothing.
+		fallbacks = nil
+	}
 
-		video := videos[0]
-		fallbacks := fallbackSlice(videos, 2)
-		log.Debugf("Found YouTube match: %s (ID: %s)", video.Title, video.VideoID)
-
-		firstSongQueued := player.IsEmpty() && !player.Player.IsPlaying() && player.GetCurrentSong() == nil
-
-		var followUpMessage string
-		if firstSongQueued {
-			followUpMessage = fmt.Sprintf("Now playing the YouTube video titled: **%s** (also mention politely that playback could take a few seconds to start, since it's the first song)", video.Title)
-		} else {
-			followUpMessage = fmt.Sprintf("Now playing the YouTube video titled: **%s**", video.Title)
-		}
-
-		manager.SendFollowup(ctx, interaction, followUpMessage, followUpMessage, false)
+	manager.queueVideo(ctx, interaction, player, video, fallbacks, resolution.Label)
+}
 
-		player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, fallbacks)
+// sendResolveError replies to a failed resolve of query: a
+// resolver.Refusal with its reason, anything else as an error.
+func (manager *Manager) sendResolveError(ctx context.Context, interaction *Interaction, query string, err error) {
+	var refusal *resolver.Refusal
+	if errors.As(err, &refusal) {
+		manager.SendFollowup(ctx, interaction, "", refusal.Reason, true)
 		return
 	}
+	log.Errorf("Error resolving %q: %v", query, err)
+	sentryhelper.CaptureException(ctx, err)
+	manager.SendError(interaction, tr(interaction, "queue.resolve_failed", err), true)
+}
 
-	// Check for Apple Music URL
-	if strings.HasPrefix(query, "https://music.apple.com/") ||
-		strings.HasPrefix(query, "https://itunes.apple.com/") {
-		log.Debugf("Detected Apple Music URL: %s", query)
-
-		appleMusicReq, err := applemusic.ParseAppleMusicURL(query)
-		if err != nil {
-			log.Errorf("Error parsing Apple Music URL: %v", err)
-			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Invalid Apple Music URL: "+err.Error(), true)
+// reportProgress returns a resolver.WithProgress callback that posts the
+// first status of a slow link and edits that message with the rest.
+func (manager *Manager) reportProgress(ctx context.Context, interaction *Interaction) func(string) {
+	posted := false
+	return func(status string) {
+		if !posted {
+			posted = true
+			manager.SendFollowup(ctx, interaction, "", status, false)
 			return
 		}
+		discord.UpdateMessage(&discord.FollowUpRequest{
+			Token:   interaction.Token,
+			AppID:   manager.AppID,
+			Content: status,
+		})
+	}
+}
 
-		// Handle playlists
-		if appleMusicReq.PlaylistID != "" {
-			manager.handleAppleMusicPlaylist(ctx, interaction, player, appleMusicReq)
-			return
-		}
+// attachedAudioURL returns the link to the audio file uploaded through
+// /play's file option, or a message for the user when it can't be played.
+func attachedAudioURL(interaction *Interaction, attachmentID string) (string, string) {
+	if interaction.Data.Resolved == nil {
+		return "", tr(interaction, "queue.file_missing")
+	}
+	attachment, ok := interaction.Data.Resolved.Attachments[attachmentID]
+	if !ok || attachment.URL == "" {
+		return "", tr(interaction, "queue.file_missing")
+	}
+	if resolver.ForURL(attachment.URL) == nil {
+		return "", tr(interaction, "queue.file_not_audio", attachment.Filename)
+	}
+	if attachment.Size > resolver.MaxAudioFileBytes {
+		return "", tr(interaction, "queue.file_too_big", attachment.Filename, resolver.MaxAudioFileBytes>>20)
+	}
+	return attachment.URL, ""
+}
 
-		// Handle albums
-		if appleMusicReq.AlbumID != "" && appleMusicReq.TrackID == "" {
-			manager.handleAppleMusicAlbum(ctx, interaction, player, appleMusicReq)
-			return
+// parseClipOptions reads /play's start and end options, returning a message
+// for the user when they don't make sense.
+func parseClipOptions(interaction *Interaction, startOpt, endOpt string) (start, end time.Duration, problem string) {
+	var err error
+	if startOpt != "" {
+		if start, err = youtube.ParseTimestamp(startOpt); err != nil {
+			return 0, 0, tr(interaction, "queue.bad_start", startOpt)
 		}
-
-		// Handle artists (Phase 3 - not implemented yet)
-		if appleMusicReq.ArtistID != "" {
-			manager.SendFollowup(ctx, interaction, "", "Apple Music artists are coming soon!", true)
-			return
+	}
+	if endOpt != "" {
+		if end, err = youtube.ParseTimestamp(endOpt); err != nil {
+			return 0, 0, tr(interaction, "queue.bad_end", endOpt)
 		}
-
-		// Handle tracks
-		if appleMusicReq.TrackID != "" {
-			manager.handleAppleMusicTrack(ctx, interaction, player, appleMusicReq)
-			return
+		if end <= start {
+			return 0, 0, tr(interaction, "queue.end_before_start")
 		}
+	}
+	return start, end, ""
+}
 
-		manager.SendFollowup(ctx, interaction, "", "Invalid Apple Music URL type.", true)
-		return
+// clipVideo trims video to start–end, checked against its length when known.
+// An end past the song's end just plays it out.
+func clipVideo(interaction *Interaction, video *youtube.VideoResponse, start, end time.Duration) string {
+	if video.Live {
+		return tr(interaction, "queue.clip_live", video.Title)
+	}
+	if video.Duration > 0 {
+		if start >= video.Duration {
+			return tr(interaction, "queue.clip_too_short", video.Title, discord.FormatDuration(video.Duration))
+		}
+		if end >= video.Duration {
+			end = 0
+		}
 	}
+	video.Clip(start, end)
+	return ""
+}
 
-	// Check for YouTube playlist URL first
-	youtubeURL := youtube.ParseYouTubeURL(query)
-	if youtubeURL.PlaylistID != "" {
-		log.Debugf("Detected YouTube playlist URL: %s", youtubeURL.PlaylistID)
-		manager.handleYouTubePlaylist(ctx, interaction, player, youtubeURL.PlaylistID)
-		return
+// clipLabel describes the part of a clipped track that plays, e.g.
+// " (1:30–3:45)", or "" for a whole track.
+func clipLabel(interaction *Interaction, video youtube.VideoResponse) string {
+	switch {
+	case video.EndAt > 0:
+		return fmt.Sprintf(" (%s–%s)", discord.FormatDuration(video.StartAt), discord.FormatDuration(video.EndAt))
+	case video.StartAt > 0:
+		return " " + tr(interaction, "queue.clip_from", discord.FormatDuration(video.StartAt))
 	}
+	return ""
+}
 
-	videoID := youtube.ParseYoutubeUrl(query)
+// joinRequesterVoice makes sure the requester is in a voice channel and the
+// bot is with them, reporting the problem to the user when not.
+func (manager *Manager) joinRequesterVoice(ctx context.Context, interaction *Interaction, player *controller.GuildPlayer) bool {
+	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
+	if err != nil {
+		log.Errorf("Error getting voice state: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendError(interaction, tr(interaction, "common.voice_state_error", err.Error()), true)
+		return false
+	}
 
-	var video youtube.VideoResponse
-	var fallbacks []youtube.VideoResponse
+	if voiceState == nil {
+		manager.SendFollowup(ctx, interaction, "The user is not in a voice channel and trying to play a song", tr(interaction, "queue.join_voice_first"), true)
+		return false
+	}
 
-	// user passed in a youtube url
-	if videoID != "" {
-		videoResponse, err := youtube.GetVideoByID(ctx, videoID)
+	// join vc if not in one, or move to requester's vc if stopped
+	if player.ShouldJoinVoice(voiceState.ChannelID) {
+		err := player.JoinVoiceChannel(interaction.Member.User.ID)
 		if err != nil {
+			errStr := err.Error()
+			if errStr != "" && errStr == "voice state not found" {
+				manager.SendFollowup(ctx, interaction, "You gotta join a voice channel first!", tr(interaction, "common.join_voice_error", errStr), true)
+				return false
+			}
 			sentryhelper.CaptureException(ctx, err)
-			manager.SendError(interaction, "Error getting video stream: "+err.Error(), true)
-			return
+			manager.SendError(interaction, tr(interaction, "common.join_voice_error", errStr), true)
+			return false
 		}
+	} else if manager.refuseBusy(interaction, player, voiceState.ChannelID) {
+		return false
+	}
+	return true
+}
 
-		if db := manager.Controller.GetDB(); db != nil && db.IsVideoBlocked(interaction.GuildID, videoResponse.VideoID) {
-			manager.SendFollowup(ctx, interaction, "", fmt.Sprintf("**%s** is blocked from playing.", videoResponse.Title), true)
-			return
-		}
+// queueVideo announces and queues a single resolved track.
+func (manager *Manager) queueVideo(ctx context.Context, interaction *Interaction, player *controller.GuildPlayer, video youtube.VideoResponse, fallbacks []youtube.VideoResponse, sourceLabel string) {
+	if player.TooLong(video) {
+		manager.SendFollowup(ctx, interaction, "",
+			tr(interaction, "queue.too_long",
+				video.Title, discord.FormatDuration(video.Duration), discord.FormatDuration(player.GetMaxTrackDuration())),
+			true)
+		return
+	}
 
-		video = videoResponse
-		// No fallbacks for direct URL requests — the user asked for a specific video
-	} else {
-		videos := youtube.Query(ctx, query)
+	prompt := "Now playing the " + sourceLabel + " titled: **" + video.Title + "**" + clipLabel(interaction, video)
+	response := tr(interaction, "queue.now_playing", sourceLabel, video.Title) + clipLabel(interaction, video)
+	if player.IsEmpty() && !player.Player.IsPlaying() && player.GetCurrentSong() == nil {
+		prompt += " (also mention politely that playback could take a few seconds to start, since it's the first song and needs to load)"
+		response += "\n\n" + tr(interaction, "queue.first_song_loading")
+	}
 
-		if len(videos) == 0 {
-			manager.SendFollowup(ctx, interaction, "There wasn't anything found for "+query, "No videos found for the given query", true)
-			return
-		}
+	manager.SendFollowup(ctx, interaction, prompt, response, false)
+	player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, fallbacks)
+}
 
-		videos = manager.filterBlocked(interaction.GuildID, videos)
-		if len(videos) == 0 {
-			manager.SendFollowup(ctx, interaction, "No unblocked results found for "+query, "All results are blocked", true)
-			return
+// queueCollection queues every track of a playlist, album or set, leaving
+// out ones that are blocked, already queued, or over the server's length cap.
+func (manager *Manager) queueCollection(ctx context.Context, interaction *Interaction, player *controller.GuildPlayer, resolution *resolver.Resolution) {
+	collection := resolution.Collection
+	title := collectionTitle(interaction, resolution)
+	fetched := resolution.Tracks
+
+	var videosToQueue []youtube.VideoResponse
+	tooLong := 0
+	for _, video := range dropQueued(player, manager.filterBlocked(interaction.GuildID, fetched)) {
+		if player.TooLong(video) {
+			tooLong++
+			continue
 		}
+		videosToQueue = append(videosToQueue, video)
+	}
+	skipped := len(fetched) - len(videosToQueue)
 
-		video = videos[0]
-		fallbacks = fallbackSlice(videos, 2)
+	if len(videosToQueue) == 0 {
+		manager.SendFollowup(ctx, interaction, "",
+			tr(interaction, "queue.collection_all_skipped", title), true)
+		return
 	}
 
-	var followUpMessage string
 	firstSongQueued := player.IsEmpty() && !player.Player.IsPlaying() && player.GetCurrentSong() == nil
+	for _, video := range videosToQueue {
+		player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, nil)
+	}
+
+	source := ""
+	if resolution.Resolver != nil {
+		source = resolution.Resolver.Name()
+	}
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "queue",
+		Message:  fmt.Sprintf("Queued %d tracks from %s", len(videosToQueue), title),
+		Level:    sentry.LevelInfo,
+		Data: map[string]interface{}{
+			"source":   source,
+			"kind":     collection.Kind,
+			"name":     collection.Name,
+			"fetched":  len(fetched),
+			"missing":  collection.Missing,
+			"too_long": tooLong,
+			"queued":   len(videosToQueue),
+		},
+	})
+
+	var sb strings.Builder
+	sb.WriteString(tr(interaction, "queue.collection_queued", len(videosToQueue), pluralSongs(interaction, len(videosToQueue)), title) + "\n")
+	for i, video := range videosToQueue {
+		sb.WriteString(fmt.Sprintf("%d. %s", i+1, video.Title))
+		if video.Duration > 0 {
+			sb.WriteString(" (" + discord.FormatDuration(video.Duration) + ")")
+		}
+		sb.WriteString("\n")
+	}
 
+	var notes []string
+	if collection.Missing > 0 {
+		notes = append(notes, tr(interaction, "queue.collection_missing", collection.Missing))
+	}
+	if skipped > 0 {
+		notes = append(notes, tr(interaction, "queue.collection_skipped", skipped))
+	}
+	if looked := len(fetched) + collection.Missing; collection.Total > looked {
+		notes = append(notes, tr(interaction, "queue.collection_truncated", looked, collection.Total))
+	}
+	if len(notes) > 0 {
+		sb.WriteString("\n(" + strings.Join(notes, ", ") + ")")
+	}
 	if firstSongQueued {
-		followUpMessage = "Now playing the YouTube video titled: **" + video.Title + "** (also mention politely that playback could take a few seconds to start, since it's the first song and needs to load)"
-	} else {
-		followUpMessage = "Now playing the YouTube video titled: **" + video.Title + "**"
+		sb.WriteString("\n\n" + tr(interaction, "queue.first_song_loading"))
 	}
 
-	manager.SendFollowup(ctx, interaction, followUpMessage, followUpMessage, false)
-	player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, fallbacks)
+	// Only a preview goes to the AI, to keep long playlists cheap.
+	preview := videosToQueue
+	if len(preview) > 5 {
+		preview = preview[:5]
+	}
+	titles := make([]string, len(preview))
+	for i, video := range preview {
+		titles[i] = video.Title
+	}
+	prompt := fmt.Sprintf("User %s queued %d songs from %s, starting with: %s",
+		interaction.Member.User.Username, len(videosToQueue), title, strings.Join(titles, "; "))
+	manager.SendFollowup(ctx, interaction, prompt, sb.String(), false)
+}
+
+// collectionTitle names a collection in replies, like "**Discovery by Daft
+// Punk** (Spotify album)", or by its source when the link didn't say.
+func collectionTitle(interaction *Interaction, resolution *resolver.Resolution) string {
+	var source string
+	if resolution.Resolver != nil {
+		source = resolution.Resolver.DisplayName()
+	}
+	collection := resolution.Collection
+	if collection.Name == "" {
+		return tr(interaction, "queue.collection_link", source)
+	}
+	about := strings.TrimSpace(source + " " + collection.Kind)
+	if about == "" {
+		return "**" + collection.Name + "**"
+	}
+	return fmt.Sprintf("**%s** (%s)", collection.Name, about)
+}
+
+// dropQueued leaves out videos that are playing, already queued, or repeated
+// earlier in videos. Different clips of one video, like the chapters of an
+// album upload, count as different songs.
+func dropQueued(player *controller.GuildPlayer, videos []youtube.VideoResponse) []youtube.VideoResponse {
+	queued := make(map[string]bool)
+	for _, item := range player.GetQueueSnapshot() {
+		queued[queueKey(item.Video)] = true
+	}
+	if current := player.GetCurrentItem(); current != nil {
+		queued[queueKey(current.Video)] = true
+	}
+	var out []youtube.VideoResponse
+	for _, video := range videos {
+		if queued[queueKey(video)] {
+			continue
+		}
+		queued[queueKey(video)] = true
+		out = append(out, video)
+	}
+	return out
+}
+
+// queueKey identifies a track for duplicate checks: its video, plus the part
+// of it that plays when clipped.
+func queueKey(video youtube.VideoResponse) string {
+	if !video.Clipped() {
+		return video.VideoID
+	}
+	return fmt.Sprintf("%s@%d-%d", video.VideoID, video.StartAt, video.EndAt)
 }
 
 func (manager *Manager) handleQueue(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
@@ -269,6 +450,43 @@ func (manager *Manager) handleQueue(ctx context.Context, transaction *sentry.Spa
 	}
 }
 
+const (
+	// viewMaxEntries caps how many queue entries /view formats before
+	// fitting them into viewCharBudget.
+	viewMaxEntries = 50
+	// viewCharBudget is how long a /view message may get, header and footer
+	// included, leaving headroom under Discord's 2000 character limit.
+	viewCharBudget = 1900
+)
+
+// fitViewPage joins header, as many lines as fit in viewCharBudget and
+// footer. Lines that don't fit, plus hiddenBeyond entries that were never
+// formatted, are summed up in an "…and N more" note.
+func fitViewPage(interaction *Interaction, header string, lines []string, hiddenBeyond int, footer string) string {
+	used := utf8.RuneCountInString(header) + utf8.RuneCountInString(footer)
+	if total := len(lines) + hiddenBeyond; total > 0 {
+		used += utf8.RuneCountInString(tr(interaction, "common.and_more", total)) + 1
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header)
+	shown := 0
+	for _, line := range lines {
+		n := utf8.RuneCountInString(line)
+		if used+n > viewCharBudget {
+			break
+		}
+		used += n
+		sb.WriteString(line)
+		shown++
+	}
+	if hidden := len(lines) - shown + hiddenBeyond; hidden > 0 {
+		sb.WriteString(tr(interaction, "common.and_more", hidden) + "\n")
+	}
+	sb.WriteString(footer)
+	return sb.String()
+}
+
 func (manager *Manager) onView(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -281,22 +499,135 @@ func (manager *Manager) onView(ctx context.Context, transaction *sentry.Span, in
 	player := manager.Controller.GetPlayer(interaction.GuildID)
 
 	if player.IsEmpty() && !player.Player.IsPlaying() && player.GetCurrentSong() == nil {
-		manager.SendFollowup(ctx, interaction, "The queue is empty and nothing is playing", "The queue is empty and nothing is playing", false)
+		manager.SendFollowup(ctx, interaction, "The queue is empty and nothing is playing", tr(interaction, "queue.view_empty"), false)
+		return
+	}
+
+	if filter := viewFilter(interaction); filter.active() {
+		manager.SendFollowup(ctx, interaction, "", filteredQueueView(interaction, player, player.GetQueueSnapshot(), filter), false)
+		return
 	}
 
-	// Take a snapshot under the queue lock to avoid races during iteration.
-	queueSnapshot := player.GetQueueSnapshot()
-	formatted_queue := ""
-	for i, video := range queueSnapshot {
-		formatted_queue += fmt.Sprintf("%d. %s\n", i+1, video.Video.Title)
+	// Only copy the visible window; totals come from Stats so they still
+	// cover the whole queue.
+	window := player.Queue.PeekRange(0, viewMaxEntries)
+	stats := player.Queue.Stats()
+	etas, total := player.QueueStartEstimates(window)
+	lines := make([]string, len(window))
+	for i, video := range window {
+		badge := video.State().Badge()
+		if badge != "" {
+			badge += " "
+		}
+		lines[i] = fmt.Sprintf("%d. %s%s%s\n", i+1, badge, player.DisplayTitle(video), formatQueueETA(interaction, video.Duration(), etas[i]))
+	}
+	footer := ""
+	if stats.Count > 0 && total > 0 {
+		footer += "\n" + tr(interaction, "queue.view_total", stats.Count, discord.FormatDuration(total)) + "\n"
 	}
 
 	// Capture the pointer once; nil-check and dereference are in the same expression.
 	if song := player.GetCurrentSong(); song != nil {
-		formatted_queue += fmt.Sprintf("\nNow playing: **%s**", *song)
+		title := *song
+		if item := player.GetCurrentItem(); item != nil && item.Video.Title == title {
+			title = player.DisplayTitle(item)
+		}
+		footer += "\n" + controller.ItemPlaying.Badge() + " " + tr(interaction, "queue.view_now_playing", title)
+	}
+
+	manager.SendFollowup(ctx, interaction, "", fitViewPage(interaction, "", lines, stats.Count-len(window), footer), false)
+}
+
+// queueFilter narrows /view down to matching entries.
+type queueFilter struct {
+	query       string // matched case-insensitively against title and artist
+	requesterID string
+}
+
+func viewFilter(interaction *Interaction) queueFilter {
+	var filter queueFilter
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "query":
+			filter.query = strings.TrimSpace(opt.Value)
+		case "requester":
+			filter.requesterID = opt.Value
+		}
+	}
+	return filter
+}
+
+func (f queueFilter) active() bool {
+	return f.query != "" || f.requesterID != ""
+}
+
+// label describes the filter for the /view header, e.g. “drake” from @user.
+func (f queueFilter) label(interaction *Interaction) string {
+	var parts []string
+	if f.query != "" {
+		parts = append(parts, "“"+f.query+"”")
+	}
+	if f.requesterID != "" {
+		parts = append(parts, tr(interaction, "queue.filter_from", f.requesterID))
+	}
+	return strings.Join(parts, " ")
+}
+
+// matches returns the indices of the items that pass the filter.
+func (f queueFilter) matches(player *controller.GuildPlayer, items []*controller.GuildQueueItem) []int {
+	query := strings.ToLower(f.query)
+	var matched []int
+	for i, item := range items {
+		if f.requesterID != "" && (item.Interaction == nil || item.Interaction.UserID != f.requesterID) {
+			continue
+		}
+		if query != "" &&
+			!strings.Contains(strings.ToLower(queueEntryName(player, item)), query) &&
+			!strings.Contains(strings.ToLower(item.Video.Title), query) {
+			continue
+		}
+		matched = append(matched, i)
+	}
+	return matched
+}
+
+// filteredQueueView lists the entries matching filter under their real queue
+// positions, so they can be passed straight to /remove or /jump.
+func filteredQueueView(interaction *Interaction, player *controller.GuildPlayer, items []*controller.GuildQueueItem, filter queueFilter) string {
+	matched := filter.matches(player, items)
+	if len(matched) == 0 {
+		return tr(interaction, "queue.filter_no_match", filter.label(interaction))
 	}
 
-	manager.SendFollowup(ctx, interaction, "", formatted_queue, false)
+	etas, _ := player.QueueStartEstimates(items)
+	header := tr(interaction, "queue.filter_header", len(matched), len(items), pluralSongs(interaction, len(items)), filter.label(interaction)) + "\n\n"
+	shown := matched[:min(len(matched), viewMaxEntries)]
+	lines := make([]string, len(shown))
+	for n, i := range shown {
+		badge := items[i].State().Badge()
+		if badge != "" {
+			badge += " "
+		}
+		lines[n] = fmt.Sprintf("%d. %s%s%s\n", i+1, badge, player.DisplayTitle(items[i]), formatQueueETA(interaction, items[i].Duration(), etas[i]))
+	}
+	return fitViewPage(interaction, header, lines, len(matched)-len(shown), "")
+}
+
+// formatQueueETA renders the " — 3:45 · plays in ~14:32" suffix for a /view
+// line. The "+" marks estimates that sit behind a track of unknown length.
+func formatQueueETA(interaction *Interaction, duration time.Duration, eta controller.QueueETA) string {
+	suffix := ""
+	if duration > 0 {
+		suffix = " — " + discord.FormatDuration(duration)
+	}
+	if eta.Start <= 0 && !eta.Approximate {
+		return suffix + " · " + tr(interaction, "queue.eta_next")
+	}
+	approx := ""
+	if eta.Approximate {
+		approx = "+"
+	}
+	return suffix + " · " + tr(interaction, "queue.eta", discord.FormatDuration(eta.Start)+approx)
 }
 
 func (manager *Manager) handleView(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
@@ -319,7 +650,7 @@ func (manager *Manager) onSkip(ctx context.Context, transaction *sentry.Span, in
 
 	currentSong := player.GetCurrentSong()
 	if !player.Player.IsPlaying() && currentSong == nil {
-		manager.SendFollowup(ctx, interaction, "user tried to skip but nothing is playing", "Nothing to skip", true)
+		manager.SendFollowup(ctx, interaction, "user tried to skip but nothing is playing", tr(interaction, "queue.nothing_to_skip"), true)
 		return
 	}
 
@@ -333,14 +664,17 @@ func (manager *Manager) onSkip(ctx context.Context, transaction *sentry.Span, in
 	if currentSong != nil {
 		songTitle = *currentSong
 	}
-	response := "@" + userName + " skipped **" + songTitle + "**"
+	prompt := "@" + userName + " skipped **" + songTitle + "**"
+	response := tr(interaction, "queue.skipped", userName, songTitle)
 	if next != nil {
-		response += "\n\nNow playing **" + next.Video.Title + "**"
+		prompt += "\n\nNow playing **" + next.Video.Title + "**"
+		response += "\n\n" + tr(interaction, "queue.skipped_next", next.Video.Title)
 	} else {
-		response += "\n\nNo more songs in queue"
+		prompt += "\n\nNo more songs in queue"
+		response += "\n\n" + tr(interaction, "queue.skipped_last")
 	}
 
-	manager.SendFollowup(ctx, interaction, response, response, false)
+	manager.SendFollowup(ctx, interaction, prompt, response, false)
 }
 
 func (manager *Manager) handlePurge(ctx context.Context, interaction *Interaction) {
@@ -348,54 +682,102 @@ func (manager *Manager) handlePurge(ctx context.Context, interaction *Interactio
 
 	go player.Clear()
 
-	manager.SendFollowup(ctx, interaction, "Queue purged", "Queue purged", false)
+	manager.SendFollowup(ctx, interaction, "Queue purged", tr(interaction, "queue.purged"), false)
 }
 
+// clearConfirmThreshold is the queue size above which /clear asks for a
+// button confirmation before dropping anything.
+const clearConfirmThreshold = 5
+
 // handleClear clears the queue but keeps the current song playing
 func (manager *Manager) handleClear(ctx context.Context, interaction *Interaction) Response {
 	player := manager.Controller.GetPlayer(interaction.GuildID)
 
-	// Get queue length before clearing
-	queueLen := 0
-	player.Queue.Mutex.Lock()
-	queueLen = len(player.Queue.Items)
-	player.Queue.Mutex.Unlock()
-
+	queueLen := player.Queue.Len()
 	if queueLen == 0 {
 		// Empty queue - show hint and return
-		hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+		hint := manager.hint(interaction)
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "Nothing to clear!" + hint,
+				Content: tr(interaction, "queue.nothing_to_clear") + hint,
 			},
 		}
 	}
 
-	// Clear the queue (async, doesn't stop current track)
-	go player.Clear()
+	if queueLen > clearConfirmThreshold {
+		return manager.confirmPrompt(interaction, "clear",
+			tr(interaction, "queue.clear_confirm", queueLen, pluralSongs(interaction, queueLen)),
+			tr(interaction, "queue.clear_confirm_button"))
+	}
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: manager.clearQueue(ctx, interaction, player),
+		},
+	}
+}
+
+// clearQueue drops the queue and returns the DJ-style announcement for it.
+func (manager *Manager) clearQueue(ctx context.Context, interaction *Interaction, player *controller.GuildPlayer) string {
+	cleared := player.Clear()
 
 	// Generate DJ response with a tight deadline so we never blow Discord's 3s interaction limit
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
-	djResponse := helpers.GenerateClearDJResponse(djCtx, queueLen)
+	djResponse := helpers.GenerateClearDJResponse(djCtx, cleared)
 
 	// Add hint with 15% chance
-	hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+	hint := manager.hint(interaction)
 
 	log.WithFields(log.Fields{
 		"module":   "handlers",
 		"guild_id": interaction.GuildID,
-		"cleared":  queueLen,
+		"cleared":  cleared,
 		"user_id":  interaction.Member.User.ID,
 	}).Info("Queue cleared")
 
-	return Response{
-		Type: 4,
-		Data: ResponseData{
-			Content: djResponse + hint,
-		},
+	return djResponse + hint + "\n" + tr(interaction, "queue.undo_hint")
+}
+
+// handleClearConfirm runs a /clear the user confirmed via button. The
+// ephemeral prompt is updated in place and the result is posted publicly.
+func (manager *Manager) handleClearConfirm(ctx context.Context, interaction *Interaction) Response {
+	if expired, ok := manager.answerPrompt(interaction, "clear", "clear"); !ok {
+		return expired
+	}
+	manager.recordCommand(interaction, "clear", "confirmed")
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if player.Queue.Len() == 0 {
+		return Response{Type: 7, Data: ResponseData{
+			Content:    tr(interaction, "queue.already_empty"),
+			Components: discord.DisabledButton(tr(interaction, "queue.already_empty_button")),
+		}}
 	}
+
+	announcement := manager.clearQueue(ctx, interaction, player)
+	go func() {
+		if _, err := discord.SendChannelMessage(interaction.ChannelID, announcement, nil, nil); err != nil {
+			log.Warnf("Failed to announce queue clear: %v", err)
+		}
+	}()
+
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "queue.cleared"),
+		Components: discord.DisabledButton(tr(interaction, "queue.cleared_button")),
+	}}
+}
+
+func (manager *Manager) handleClearCancel(interaction *Interaction) Response {
+	if expired, ok := manager.answerPrompt(interaction, "clear", "clear"); !ok {
+		return expired
+	}
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "queue.clear_canceled"),
+		Components: discord.DisabledButton(tr(interaction, "common.canceled_button")),
+	}}
 }
 
 func (manager *Manager) handleSkip(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
@@ -405,13 +787,36 @@ func (manager *Manager) handleSkip(ctx context.Context, transaction *sentry.Span
 	}
 }
 
-func (manager *Manager) handleReset(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
+// handleReset asks before resetting, since it stops playback, leaves voice
+// and throws away the queue.
+func (manager *Manager) handleReset(interaction *Interaction) Response {
 	player := manager.Controller.GetPlayer(interaction.GuildID)
+	content := tr(interaction, "queue.reset_confirm")
+	if n := player.Queue.Len(); n > 0 {
+		content = tr(interaction, "queue.reset_confirm_queue", n, pluralSongs(interaction, n))
+	}
+	return manager.confirmPrompt(interaction, "reset", content, tr(interaction, "queue.reset_confirm_button"))
+}
+
+// handleResetConfirm runs a /reset the user confirmed via button. The reset
+// itself posts a followup once it's done.
+func (manager *Manager) handleResetConfirm(interaction *Interaction) Response {
+	if expired, ok := manager.answerPrompt(interaction, "reset", "reset"); !ok {
+		return expired
+	}
+	manager.recordCommand(interaction, "reset", "confirmed")
 
+	player := manager.Controller.GetPlayer(interaction.GuildID)
 	go func() {
+		ctx, transaction := sentryhelper.StartCommandTransaction(
+			context.Background(),
+			"reset",
+			interaction.GuildID,
+			interaction.Member.User.ID,
+		)
 		defer func() {
 			if err := recover(); err != nil {
-				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleReset: %v", err))
+				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleResetConfirm: %v", err))
 				transaction.Status = sentry.SpanStatusInternalError
 			}
 			transaction.Finish()
@@ -423,48 +828,68 @@ func (manager *Manager) handleReset(ctx context.Context, transaction *sentry.Spa
 		})
 	}()
 
-	return Response{
-		Type: 5,
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "queue.resetting"),
+		Components: discord.DisabledButton(tr(interaction, "queue.reset_button")),
+	}}
+}
+
+func (manager *Manager) handleResetCancel(interaction *Interaction) Response {
+	if expired, ok := manager.answerPrompt(interaction, "reset", "reset"); !ok {
+		return expired
 	}
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "queue.reset_canceled"),
+		Components: discord.DisabledButton(tr(interaction, "common.canceled_button")),
+	}}
 }
 
 func (manager *Manager) handleRemove(ctx context.Context, interaction *Interaction) Response {
 	player := manager.Controller.GetPlayer(interaction.GuildID)
 
 	if player.IsEmpty() {
-		hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+		hint := manager.hint(interaction)
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "the queue is empty" + hint,
+				Content: tr(interaction, "common.queue_empty") + hint,
 			},
 		}
 	}
 
 	var index int = 1 // Default to first song if no index provided, .Remove substracts 1
 	if len(interaction.Data.Options) > 0 {
+		value := strings.TrimSpace(interaction.Data.Options[0].Value)
+		if from, to, ok := parseRemoveRange(value); ok {
+			return manager.removeRange(interaction, player, from, to)
+		}
+
 		var err error
-		index, err = strconv.Atoi(interaction.Data.Options[0].Value)
+		index, err = strconv.Atoi(value)
 		if err != nil {
 			return Response{
 				Type: 4,
 				Data: ResponseData{
-					Content: "Invalid index",
+					Content: tr(interaction, "queue.invalid_index"),
 				},
 			}
 		}
 	}
 
+	if item := player.Queue.PeekRange(index-1, 1); index >= 1 && len(item) == 1 && item[0].State() == controller.ItemPlaying {
+		return startingSongResponse(interaction, item[0].Video.Title)
+	}
+
 	removed_title := player.Remove(index)
 
 	// Generate DJ response with a tight deadline so we never blow Discord's 3s interaction limit
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
 	djResponse := helpers.GenerateDJResponse(djCtx, "remove", removed_title)
-	hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+	hint := manager.hint(interaction)
 
 	if removed_title != "" {
-		djResponse = "@" + interaction.Member.User.Username + " removed **" + removed_title + "** - " + djResponse
+		djResponse = tr(interaction, "queue.removed", interaction.Member.User.Username, removed_title) + " - " + djResponse
 	}
 
 	return Response{
@@ -475,27 +900,224 @@ func (manager *Manager) handleRemove(ctx context.Context, interaction *Interacti
 	}
 }
 
+// parseRemoveRange parses a /remove range like "2-5" (1-based, inclusive).
+func parseRemoveRange(value string) (int, int, bool) {
+	fromStr, toStr, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, false
+	}
+	from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+	if err != nil {
+		return 0, 0, false
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(toStr))
+	if err != nil {
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
+func (manager *Manager) removeRange(interaction *Interaction, player *controller.GuildPlayer, from, to int) Response {
+	if from > to {
+		from, to = to, from
+	}
+
+	queueLen := player.Queue.Len()
+	if from < 1 || to > queueLen {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "queue.bad_range", queueLen),
+				Flags:   64,
+			},
+		}
+	}
+	if head := player.Queue.PeekRange(0, 1); from == 1 && len(head) == 1 && head[0].State() == controller.ItemPlaying {
+		return startingSongResponse(interaction, head[0].Video.Title)
+	}
+
+	removed := player.RemoveRange(from, to)
+	if removed == 0 {
+		// Queue changed between the length check and the removal
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "queue.changed"),
+				Flags:   64,
+			},
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"module":   "handlers",
+		"guild_id": interaction.GuildID,
+		"from":     from,
+		"to":       to,
+		"removed":  removed,
+		"user_id":  interaction.Member.User.ID,
+	}).Info("Removed range from queue")
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: tr(interaction, "queue.removed_range",
+				interaction.Member.User.Username, removed, pluralSongs(interaction, removed), from, to) + "\n" + tr(interaction, "queue.undo_hint"),
+		},
+	}
+}
+
+// startingSongResponse explains why a song the player is already starting
+// can't be removed.
+func startingSongResponse(interaction *Interaction, title string) Response {
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: tr(interaction, "queue.starting", title),
+			Flags:   64,
+		},
+	}
+}
+
+// handleUndo restores the queue from before the last /clear, /remove range
+// or /shuffle, if it happened within the last minute.
+func (manager *Manager) handleUndo(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	action, restored := player.Undo()
+	if action == "" {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "queue.nothing_to_undo"),
+				Flags:   64,
+			},
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"module":   "handlers",
+		"guild_id": interaction.GuildID,
+		"action":   action,
+		"restored": restored,
+		"user_id":  interaction.Member.User.ID,
+	}).Info("Undid queue change")
+
+	var msg string
+	switch action {
+	case controller.UndoShuffle:
+		msg = tr(interaction, "queue.undid_shuffle", interaction.Member.User.Username, action)
+	default:
+		msg = tr(interaction, "queue.undid", interaction.Member.User.Username, action, restored, pluralSongs(interaction, restored))
+	}
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
+
+func (manager *Manager) handleJump(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	position := 0
+	moveToEnd := false
+	for _, opt := range interaction.Data.Options {
+		switch opt.Name {
+		case "position":
+			position, _ = strconv.Atoi(opt.Value)
+		case "skipped":
+			moveToEnd = opt.Value == "move"
+		}
+	}
+
+	queueLen := player.Queue.Len()
+	if queueLen == 0 {
+		hint := manager.hint(interaction)
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "queue.nowhere_to_jump") + hint,
+			},
+		}
+	}
+	if position < 1 || position > queueLen {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "queue.bad_position", queueLen),
+				Flags:   64,
+			},
+		}
+	}
+
+	title, passed := player.Jump(position, moveToEnd)
+	if title == "" {
+		// Queue changed between the length check and the jump
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "queue.changed"),
+				Flags:   64,
+			},
+		}
+	}
+
+	msg := tr(interaction, "queue.jumped", interaction.Member.User.Username, title)
+	switch {
+	case passed == 0:
+	case moveToEnd:
+		msg += " " + tr(interaction, "queue.jumped_moved", passed, pluralSongs(interaction, passed))
+	default:
+		msg += " " + tr(interaction, "queue.jumped_dropped", passed, pluralSongs(interaction, passed))
+	}
+
+	log.WithFields(log.Fields{
+		"module":   "handlers",
+		"guild_id": interaction.GuildID,
+		"position": position,
+		"passed":   passed,
+		"moved":    moveToEnd,
+		"user_id":  interaction.Member.User.ID,
+	}).Info("Jumped in queue")
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
+
+func pluralSongs(interaction *Interaction, n int) string {
+	if n == 1 {
+		return tr(interaction, "common.song")
+	}
+	return tr(interaction, "common.songs")
+}
+
 func (manager *Manager) handleShuffle(ctx context.Context, interaction *Interaction) Response {
 	player := manager.Controller.GetPlayer(interaction.GuildID)
 
 	count := player.Shuffle()
 
 	if count == 0 {
-		hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+		hint := manager.hint(interaction)
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "the queue is empty, nothing to shuffle" + hint,
+				Content: tr(interaction, "queue.shuffle_empty") + hint,
 			},
 		}
 	}
 
 	if count == 1 {
-		hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+		hint := manager.hint(interaction)
 		return Response{
 			Type: 4,
 			Data: ResponseData{
-				Content: "only one song in the queue, nothing to shuffle" + hint,
+				Content: tr(interaction, "queue.shuffle_one") + hint,
 			},
 		}
 	}
@@ -504,12 +1126,94 @@ func (manager *Manager) handleShuffle(ctx context.Context, interaction *Interact
 	djCtx, djCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer djCancel()
 	djResponse := helpers.GenerateDJResponse(djCtx, "shuffle", count)
-	hint := manager.Hints.ShowIfApplicable(interaction.GuildID)
+	hint := manager.hint(interaction)
 
 	return Response{
 		Type: 4,
 		Data: ResponseData{
-			Content: djResponse + hint,
+			Content: djResponse + hint + "\n" + tr(interaction, "queue.undo_hint"),
+		},
+	}
+}
+
+// handleQueueSettings updates per-guild queue behavior. Currently just the
+// ordering mode: `fifo` (default) or `fair` (round-robin by requester).
+func (manager *Manager) handleQueueSettings(interaction *Interaction) Response {
+	guildID := interaction.GuildID
+	player := manager.Controller.GetPlayer(guildID)
+
+	var modeOpt, stripEmojiOpt string
+	for _, opt := range interaction.Data.Options {
+		switch opt.Name {
+		case "mode":
+			modeOpt = strings.ToLower(opt.Value)
+		case "strip_emoji":
+			stripEmojiOpt = opt.Value
+		}
+	}
+
+	if modeOpt == "" && stripEmojiOpt == "" {
+		emojiState := tr(interaction, "queue.emoji_kept")
+		if player.GetStripTitleEmoji() {
+			emojiState = tr(interaction, "queue.emoji_stripped")
+		}
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "queue.settings", player.GetQueueMode(), emojiState),
+				Flags:   64,
+			},
+		}
+	}
+
+	var lines []string
+
+	if modeOpt != "" {
+		mode := controller.QueueMode(modeOpt)
+		if mode != controller.QueueModeFIFO && mode != controller.QueueModeFair {
+			return Response{
+				Type: 4,
+				Data: ResponseData{
+					Content: tr(interaction, "queue.unknown_mode"),
+					Flags:   64,
+				},
+			}
+		}
+
+		player.SetQueueMode(mode)
+		if player.DB != nil {
+			if err := player.DB.SetGuildSetting(guildID, "queue_mode", string(mode)); err != nil {
+				log.Errorf("Failed to save queue mode: %v", err)
+			}
+		}
+
+		if mode == controller.QueueModeFair {
+			lines = append(lines, tr(interaction, "queue.mode_fair"))
+		} else {
+			lines = append(lines, tr(interaction, "queue.mode_fifo"))
+		}
+	}
+
+	if stripEmojiOpt != "" {
+		strip := stripEmojiOpt == "true"
+		player.SetStripTitleEmoji(strip)
+		if player.DB != nil {
+			if err := player.DB.SetGuildSetting(guildID, "strip_title_emoji", strconv.FormatBool(strip)); err != nil {
+				log.Errorf("Failed to save strip_title_emoji setting: %v", err)
+			}
+		}
+
+		if strip {
+			lines = append(lines, tr(interaction, "queue.strip_emoji_on"))
+		} else {
+			lines = append(lines, tr(interaction, "queue.strip_emoji_off"))
+		}
+	}
+
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: strings.Join(lines, "\n"),
 		},
 	}
 }
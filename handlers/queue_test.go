@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"beatbot/controller"
+	"beatbot/youtube"
+)
+
+func TestQueueFilterMatches(t *testing.T) {
+	player := &controller.GuildPlayer{}
+	items := []*controller.GuildQueueItem{
+		{Video: youtube.VideoResponse{Title: "God's Plan", ChannelName: "Drake - Topic"}, Interaction: &controller.GuildQueueItemInteraction{UserID: "u1"}},
+		{Video: youtube.VideoResponse{Title: "Blue Monday", ChannelName: "New Order"}, Interaction: &controller.GuildQueueItemInteraction{UserID: "u2"}},
+		{Video: youtube.VideoResponse{Title: "Hotline Bling", ChannelName: "DrakeVEVO"}, Interaction: &controller.GuildQueueItemInteraction{UserID: "u2"}},
+		{Video: youtube.VideoResponse{Title: "Radio pick"}},
+	}
+
+	tests := []struct {
+		filter queueFilter
+		want   []int
+	}{
+		{queueFilter{query: "DRAKE"}, []int{0, 2}},
+		{queueFilter{requesterID: "u2"}, []int{1, 2}},
+		{queueFilter{query: "drake", requesterID: "u2"}, []int{2}},
+		{queueFilter{query: "monday", requesterID: "u1"}, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.matches(player, items); !slices.Equal(got, tt.want) {
+			t.Errorf("%s matched %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+
+	if got := (queueFilter{query: "drake", requesterID: "u2"}).label(&Interaction{}); got != "“drake” from <@u2>" {
+		t.Errorf("label = %q", got)
+	}
+}
+
+func TestFitViewPage(t *testing.T) {
+	lines := make([]string, viewMaxEntries)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%d. %s — 9:59 · plays in ~1:23:45\n", i+1, strings.Repeat("é", 90))
+	}
+	footer := "\nTotal: 99 songs\n\n▶️ Now playing: something"
+	page := fitViewPage(&Interaction{}, "header\n\n", lines, 100, footer)
+
+	if n := utf8.RuneCountInString(page); n > viewCharBudget {
+		t.Errorf("page is %d characters, budget is %d", n, viewCharBudget)
+	}
+	shown := strings.Count(page, "plays in")
+	if shown == 0 || shown == len(lines) {
+		t.Fatalf("showed %d of %d lines, want a budget-limited subset", shown, len(lines))
+	}
+	if want := fmt.Sprintf("…and %d more", len(lines)-shown+100); !strings.Contains(page, want) {
+		t.Errorf("page missing %q:\n%s", want, page)
+	}
+	if !strings.HasPrefix(page, "header") || !strings.HasSuffix(page, footer) {
+		t.Errorf("page lost its header or footer:\n%s", page)
+	}
+
+	if page := fitViewPage(&Interaction{}, "", lines[:3], 0, ""); strings.Contains(page, "more") || strings.Count(page, "\n") != 3 {
+		t.Errorf("short queue = %q, want every line and no note", page)
+	}
+}
+
+func TestClipOptions(t *testing.T) {
+	start, end, problem := parseClipOptions(&Interaction{}, "1:30", "3:45")
+	if problem != "" || start != 90*time.Second || end != 225*time.Second {
+		t.Errorf("parseClipOptions(1:30, 3:45) = %v, %v, %q", start, end, problem)
+	}
+	if _, _, problem := parseClipOptions(&Interaction{}, "2:00", "1:00"); problem == "" {
+		t.Error("end before start should be rejected")
+	}
+	if _, _, problem := parseClipOptions(&Interaction{}, "soon", ""); problem == "" {
+		t.Error("unreadable start should be rejected")
+	}
+
+	video := youtube.VideoResponse{Title: "Song", Duration: 4 * time.Minute}
+	if problem := clipVideo(&Interaction{}, &video, 5*time.Minute, 0); problem == "" {
+		t.Error("start past the end should be rejected")
+	}
+	if problem := clipVideo(&Interaction{}, &video, 90*time.Second, 10*time.Minute); problem != "" {
+		t.Fatalf("clipVideo: %s", problem)
+	}
+	if video.EndAt != 0 || video.Duration != 150*time.Second || clipLabel(&Interaction{}, video) != " (from 1:30)" {
+		t.Errorf("end past the song: EndAt = %v, Duration = %v, label %q", video.EndAt, video.Duration, clipLabel(&Interaction{}, video))
+	}
+
+	live := youtube.VideoResponse{Title: "Radio", Live: true}
+	if problem := clipVideo(&Interaction{}, &live, time.Minute, 0); problem == "" || live.Clipped() {
+		t.Error("live streams should not be clipped")
+	}
+}
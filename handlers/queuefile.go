@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+	"beatbot/discord"
+	"beatbot/resolver"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+const (
+	queueExportVersion = 1
+	// queueCodePrefix marks a shareable queue code so typos and random text
+	// fail fast instead of producing a confusing decode error.
+	queueCodePrefix = "bbq1:"
+	// Longest code we'll paste into chat; longer exports are file-only.
+	maxQueueCodeLength = 1500
+	maxImportTracks    = 50
+	maxImportFileBytes = 1 << 20
+)
+
+// QueueExport is the JSON document produced by /queue export and accepted
+// by /queue import.
+type QueueExport struct {
+	Version    int                `json:"version"`
+	ExportedAt time.Time          `json:"exported_at,omitempty"`
+	Tracks     []QueueExportTrack `json:"tracks"`
+}
+
+// QueueExportTrack is one song in an export. Source and URL are only set for
+// non-YouTube tracks.
+type QueueExportTrack struct {
+	VideoID string `json:"video_id"`
+	Title   string `json:"title"`
+	Source  string `json:"source,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+func (t QueueExportTrack) video() youtube.VideoResponse {
+	return youtube.VideoResponse{VideoID: t.VideoID, Title: t.Title, Source: t.Source, URL: t.URL}
+}
+
+// queueExportTracks lists the current song followed by the queue, so a
+// restored session picks up where it left off.
+func queueExportTracks(player *controller.GuildPlayer) []QueueExportTrack {
+	var tracks []QueueExportTrack
+	var items []*controller.GuildQueueItem
+	if current := player.GetCurrentItem(); current != nil {
+		items = append(items, current)
+	}
+	items = append(items, player.GetQueueSnapshot()...)
+	for _, item := range items {
+		track := QueueExportTrack{VideoID: item.Video.VideoID, Title: item.Video.Title}
+		// Radio picks carry a label like "deezer" as their source but are
+		// YouTube videos; only tracks with their own link are exported as such.
+		if !item.Video.IsYouTube() && item.Video.URL != "" {
+			track.Source = item.Video.Source
+			track.URL = item.Video.URL
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks
+}
+
+// encodeQueueCode packs tracks into a compact, chat-pasteable code.
+func encodeQueueCode(tracks []QueueExportTrack) (string, error) {
+	payload, err := json.Marshal(QueueExport{Version: queueExportVersion, Tracks: tracks})
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return queueCodePrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeQueueCode reverses encodeQueueCode.
+func decodeQueueCode(code string) (*QueueExport, error) {
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, queueCodePrefix) {
+		return nil, errors.New("not a queue code")
+	}
+	compressed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, queueCodePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid queue code: %w", err)
+	}
+	payload, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), maxImportFileBytes))
+	if err != nil {
+		return nil, fmt.Errorf("invalid queue code: %w", err)
+	}
+	return parseQueueExport(payload)
+}
+
+// parseQueueExport validates an export document and drops unusable tracks.
+func parseQueueExport(data []byte) (*QueueExport, error) {
+	var export QueueExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid queue file: %w", err)
+	}
+	if export.Version > queueExportVersion {
+		return nil, fmt.Errorf("queue file version %d is newer than this bot supports", export.Version)
+	}
+	tracks := export.Tracks[:0]
+	for _, t := range export.Tracks {
+		t, ok := importableTrack(t)
+		if !ok {
+			continue
+		}
+		if t.Title == "" {
+			t.Title = t.VideoID
+		}
+		tracks = append(tracks, t)
+	}
+	export.Tracks = tracks
+	return &export, nil
+}
+
+// importableTrack checks one imported track. Files are untrusted and their
+// links end up in yt-dlp and ffmpeg, so a non-YouTube track needs a source
+// whose links play as they are and an http(s) link that source claims.
+// Sources matched on YouTube never export links of their own. YouTube
+// tracks are played by ID, so any link on them is dropped.
+func importableTrack(t QueueExportTrack) (QueueExportTrack, bool) {
+	if t.VideoID == "" {
+		return t, false
+	}
+	if t.Source == "" || t.Source == resolver.SourceYouTube {
+		t.Source, t.URL = "", ""
+		return t, true
+	}
+	res := resolver.Get(t.Source)
+	if _, matched := res.(resolver.LinkResolver); res == nil || matched {
+		return t, false
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || !res.MatchesURL(t.URL) {
+		return t, false
+	}
+	return t, true
+}
+
+// handleQueueCommand routes /queue subcommands. "add" (and the legacy
+// option-only form) behaves like /play.
+func (manager *Manager) handleQueueCommand(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
+	switch subcommandName(interaction) {
+	case "export":
+		go manager.onQueueExport(ctx, transaction, interaction)
+		return Response{Type: 5, Data: ResponseData{Flags: 64}}
+	case "import":
+		go manager.onQueueImport(ctx, transaction, interaction)
+		return Response{Type: 5}
+	default:
+		return manager.handleQueue(ctx, transaction, interaction)
+	}
+}
+
+func (manager *Manager) onQueueExport(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onQueueExport: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	tracks := queueExportTracks(player)
+	if len(tracks) == 0 {
+		manager.SendRequest(interaction, tr(interaction, "queuefile.empty"), true)
+		return
+	}
+
+	data, err := json.MarshalIndent(QueueExport{
+		Version:    queueExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Tracks:     tracks,
+	}, "", "  ")
+	if err != nil {
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendError(interaction, tr(interaction, "queuefile.export_failed"), true)
+		return
+	}
+
+	content := tr(interaction, "queuefile.exported", len(tracks))
+	if code, err := encodeQueueCode(tracks); err == nil && len(code) <= maxQueueCodeLength {
+		content += "\n\n" + tr(interaction, "queuefile.share_code") + "\n```\n" + code + "\n```"
+	}
+
+	err = discord.SendFollowupWithFile(&discord.FollowUpRequest{
+		Token:   interaction.Token,
+		AppID:   manager.AppID,
+		Content: content,
+		Flags:   64,
+	}, fmt.Sprintf("queue-%s.json", time.Now().UTC().Format("20060102-1504")), data)
+	if err != nil {
+		log.Errorf("Error sending queue export: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+	}
+}
+
+func (manager *Manager) onQueueImport(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onQueueImport: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	var code, attachmentID string
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "code":
+			code = opt.Value
+		case "file":
+			attachmentID = opt.Value
+		}
+	}
+
+	var export *QueueExport
+	var err error
+	switch {
+	case attachmentID != "":
+		export, err = manager.fetchQueueExport(ctx, interaction, attachmentID)
+	case code != "":
+		export, err = decodeQueueCode(code)
+	default:
+		manager.SendRequest(interaction, tr(interaction, "queuefile.nothing_attached"), true)
+		return
+	}
+	if err != nil {
+		log.Warnf("Queue import failed: %v", err)
+		manager.SendRequest(interaction, tr(interaction, "queuefile.unreadable", err.Error()), true)
+		return
+	}
+
+	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
+	if err != nil || voiceState == nil {
+		manager.SendRequest(interaction, tr(interaction, "queuefile.join_first"), true)
+		return
+	}
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if player.ShouldJoinVoice(voiceState.ChannelID) {
+		if err := player.JoinVoiceChannel(interaction.Member.User.ID); err != nil {
+			sentryhelper.CaptureException(ctx, err)
+			manager.SendError(interaction, tr(interaction, "common.join_voice_error", err.Error()), true)
+			return
+		}
+	} else if manager.refuseBusy(interaction, player, voiceState.ChannelID) {
+		return
+	}
+
+	truncated := 0
+	if len(export.Tracks) > maxImportTracks {
+		truncated = len(export.Tracks) - maxImportTracks
+		export.Tracks = export.Tracks[:maxImportTracks]
+	}
+
+	videos := make([]youtube.VideoResponse, 0, len(export.Tracks))
+	for _, t := range export.Tracks {
+		videos = append(videos, t.video())
+	}
+	videos = manager.filterBlocked(interaction.GuildID, videos)
+
+	queued := make(map[string]bool)
+	for _, item := range player.GetQueueSnapshot() {
+		queued[item.Video.VideoID] = true
+	}
+	var videosToQueue []youtube.VideoResponse
+	for _, video := range videos {
+		if queued[video.VideoID] {
+			continue
+		}
+		queued[video.VideoID] = true
+		videosToQueue = append(videosToQueue, video)
+	}
+
+	if len(videosToQueue) == 0 {
+		manager.SendRequest(interaction, tr(interaction, "queuefile.all_skipped"), true)
+		return
+	}
+
+	for _, video := range videosToQueue {
+		player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, nil)
+	}
+
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "queue",
+		Message:  fmt.Sprintf("Imported %d tracks", len(videosToQueue)),
+		Level:    sentry.LevelInfo,
+	})
+
+	response := tr(interaction, "queuefile.imported", len(videosToQueue))
+	if skipped := len(export.Tracks) - len(videosToQueue); skipped > 0 {
+		response += " " + tr(interaction, "common.skipped_count", skipped)
+	}
+	if truncated > 0 {
+		response += " " + tr(interaction, "queuefile.truncated", maxImportTracks, truncated)
+	}
+	manager.SendFollowup(ctx, interaction, "", response, false)
+}
+
+// fetchQueueExport downloads and parses the attachment referenced by an
+// attachment option.
+func (manager *Manager) fetchQueueExport(ctx context.Context, interaction *Interaction, attachmentID string) (*QueueExport, error) {
+	data, err := downloadAttachment(ctx, interaction, attachmentID, maxImportFileBytes)
+	if err != nil {
+		return nil, err
+	}
+	return parseQueueExport(data)
+}
+
+// downloadAttachment fetches the attachment referenced by an attachment
+// option, refusing files over limit bytes.
+func downloadAttachment(ctx context.Context, interaction *Interaction, attachmentID string, limit int) ([]byte, error) {
+	if interaction.Data.Resolved == nil {
+		return nil, errors.New("attachment missing")
+	}
+	attachment, ok := interaction.Data.Resolved.Attachments[attachmentID]
+	if !ok || attachment.URL == "" {
+		return nil, errors.New("attachment missing")
+	}
+	if attachment.Size > limit {
+		return nil, errors.New("file is too large")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, attachment.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestQueueCodeRoundTrip(t *testing.T) {
+	tracks := []QueueExportTrack{
+		{VideoID: "dQw4w9WgXcQ", Title: "Never Gonna Give You Up"},
+		{VideoID: "soundcloud:123", Title: "SC Track", Source: "soundcloud", URL: "https://soundcloud.com/a/b"},
+	}
+
+	code, err := encodeQueueCode(tracks)
+	if err != nil {
+		t.Fatalf("encodeQueueCode() error = %v", err)
+	}
+	if !strings.HasPrefix(code, queueCodePrefix) {
+		t.Errorf("code %q missing prefix %q", code, queueCodePrefix)
+	}
+
+	export, err := decodeQueueCode(code)
+	if err != nil {
+		t.Fatalf("decodeQueueCode() error = %v", err)
+	}
+	if len(export.Tracks) != 2 || export.Tracks[0] != tracks[0] || export.Tracks[1] != tracks[1] {
+		t.Errorf("round trip = %+v, want %+v", export.Tracks, tracks)
+	}
+}
+
+func TestDecodeQueueCodeRejectsGarbage(t *testing.T) {
+	for _, code := range []string{"", "hello", queueCodePrefix + "!!!"} {
+		if _, err := decodeQueueCode(code); err == nil {
+			t.Errorf("decodeQueueCode(%q) should fail", code)
+		}
+	}
+}
+
+func TestParseQueueExportDropsInvalidTracks(t *testing.T) {
+	data := []byte(`{"version":1,"tracks":[
+		{"video_id":"abc","title":"ok"},
+		{"video_id":"","title":"no id"},
+		{"video_id":"soundcloud:1","source":"soundcloud"},
+		{"video_id":"def"},
+		{"video_id":"file:1","source":"file","url":"file:///etc/passwd"},
+		{"video_id":"file:2","source":"file","url":"-o/tmp/x.mp3"},
+		{"video_id":"sc:2","source":"soundcloud","url":"--exec=id https://soundcloud.com/a/b"},
+		{"video_id":"x:1","source":"mystery","url":"https://example.com/a.mp3"},
+		{"video_id":"ghi","source":"youtube","url":"file:///etc/passwd"},
+		{"video_id":"file:3","source":"file","url":"https://cdn.example.com/song.mp3"}
+	]}`)
+
+	export, err := parseQueueExport(data)
+	if err != nil {
+		t.Fatalf("parseQueueExport() error = %v", err)
+	}
+	if len(export.Tracks) != 4 {
+		t.Fatalf("len(Tracks) = %d, want 4: %+v", len(export.Tracks), export.Tracks)
+	}
+	if export.Tracks[1].Title != "def" {
+		t.Errorf("missing title should fall back to the ID, got %q", export.Tracks[1].Title)
+	}
+	if yt := export.Tracks[2]; yt.VideoID != "ghi" || yt.Source != "" || yt.URL != "" {
+		t.Errorf("YouTube track kept its source or link: %+v", yt)
+	}
+	if file := export.Tracks[3]; file.URL != "https://cdn.example.com/song.mp3" {
+		t.Errorf("audio file link dropped: %+v", file)
+	}
+
+	if _, err := parseQueueExport([]byte(`{"version":99,"tracks":[]}`)); err == nil {
+		t.Error("parseQueueExport should reject newer versions")
+	}
+}
+
+func TestInteractionOptionValueTypes(t *testing.T) {
+	data := []byte(`{"name":"queue","options":[{"name":"import","type":1,"options":[
+		{"name":"code","type":3,"value":"bbq1:x"},
+		{"name":"count","type":4,"value":5},
+		{"name":"flag","type":5,"value":true}
+	]}]}`)
+
+	var payload InteractionData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	interaction := &Interaction{Data: payload}
+	if got := subcommandName(interaction); got != "import" {
+		t.Errorf("subcommandName() = %q, want import", got)
+	}
+	opts := commandOptions(interaction)
+	want := map[string]string{"code": "bbq1:x", "count": "5", "flag": "true"}
+	for _, opt := range opts {
+		if opt.Value != want[opt.Name] {
+			t.Errorf("option %s = %q, want %q", opt.Name, opt.Value, want[opt.Name])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+)
+
+// ListenReactions turns reactions on now-playing cards into playback
+// controls in servers with /reactions on. The returned function stops
+// listening.
+func (manager *Manager) ListenReactions(session *discordgo.Session) func() {
+	return session.AddHandler(func(s *discordgo.Session, event *discordgo.MessageReactionAdd) {
+		manager.onControlReaction(s, event)
+	})
+}
+
+// onControlReaction runs the control a reaction stands for, with the same
+// DJ and voice channel checks as the card's buttons. There's nowhere to
+// explain a refusal, so the reaction is just taken back.
+func (manager *Manager) onControlReaction(s *discordgo.Session, event *discordgo.MessageReactionAdd) {
+	if event.GuildID == "" || event.Member == nil || event.Member.User == nil || event.Member.User.Bot {
+		return
+	}
+	action, ok := controller.ReactionAction(event.Emoji.Name)
+	if !ok {
+		return
+	}
+	player, ok := manager.Controller.LookupPlayer(event.GuildID)
+	if !ok || !player.GetReactionControls() || !player.IsNowPlayingMessage(event.MessageID) {
+		return
+	}
+	defer player.RemoveReaction(event.ChannelID, event.MessageID, event.Emoji.APIName(), event.UserID)
+
+	// Discord doesn't send the member's permissions with a reaction; if
+	// they can't be worked out they're treated as having none.
+	var perms int64
+	if p, err := s.UserChannelPermissions(event.UserID, event.ChannelID); err == nil {
+		perms = p
+	} else {
+		log.Debugf("Couldn't work out permissions for reaction by %s: %v", event.UserID, err)
+	}
+	interaction := reactionInteraction(event, perms)
+	command := buttonCommands[action]
+	if _, ok := manager.requireDJ(interaction, command); !ok {
+		return
+	}
+	if _, ok := manager.requireListener(interaction, command); !ok {
+		return
+	}
+
+	log.Debugf("Control reaction: %s in guild %s", action, event.GuildID)
+	ctx := context.Background()
+	switch action {
+	case "playpause":
+		if player.Player.IsPaused() {
+			player.LastActivityAt = time.Now()
+			go player.Player.Resume(ctx)
+		} else if player.Player.IsPlaying() {
+			go player.Player.Pause(ctx)
+		}
+	case "stop":
+		if player.Player.IsPlaying() && !player.Player.IsPaused() {
+			go player.Player.Pause(ctx)
+		}
+	case "skip":
+		if player.Player.IsPlaying() || player.GetCurrentSong() != nil {
+			go player.Skip()
+		}
+	}
+}
+
+// reactionInteraction describes the member who reacted, with channel
+// permissions perms, the way an interaction would, so the button checks
+// apply unchanged.
+func reactionInteraction(event *discordgo.MessageReactionAdd, perms int64) *Interaction {
+	member := MemberData{
+		User: UserData{
+			ID:         event.Member.User.ID,
+			Username:   event.Member.User.Username,
+			GlobalName: event.Member.User.GlobalName,
+		},
+		Roles:       event.Member.Roles,
+		Permissions: strconv.FormatInt(perms, 10),
+	}
+	return &Interaction{
+		GuildID:   event.GuildID,
+		ChannelID: event.ChannelID,
+		Member:    member,
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestReactionInteraction(t *testing.T) {
+	event := &discordgo.MessageReactionAdd{
+		MessageReaction: &discordgo.MessageReaction{
+			UserID:    "u1",
+			ChannelID: "c1",
+			GuildID:   "g1",
+		},
+		Member: &discordgo.Member{
+			User:  &discordgo.User{ID: "u1", Username: "alice"},
+			Roles: []string{"r1"},
+		},
+	}
+	interaction := reactionInteraction(event, 0)
+	if interaction.GuildID != "g1" || interaction.ChannelID != "c1" {
+		t.Errorf("interaction is for %s/%s", interaction.GuildID, interaction.ChannelID)
+	}
+	if interaction.Member.User.ID != "u1" || interaction.Member.User.Username != "alice" || len(interaction.Member.Roles) != 1 {
+		t.Errorf("member = %+v", interaction.Member)
+	}
+	if canManageGuild(interaction.Member) {
+		t.Error("member without permissions can manage the guild")
+	}
+	if !canManageGuild(reactionInteraction(event, discordgo.PermissionManageServer).Member) {
+		t.Error("member with Manage Server can't manage the guild")
+	}
+}
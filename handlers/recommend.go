@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+	"beatbot/discord"
+	"beatbot/gemini"
+	"beatbot/i18n"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+const (
+	// recommendAction prefixes a "queue this" button's action; the video ID
+	// follows, so the buttons keep working after a restart.
+	recommendAction = "recommend_pick."
+	// maxRecommendations is how many picks /recommend offers, one button each.
+	maxRecommendations = 5
+	// recommendHistory is how many recent plays the AI is shown.
+	recommendHistory = 10
+)
+
+func (manager *Manager) handleRecommend(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleRecommend: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	if !config.Config.Gemini.Enabled {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "recommend.needs_gemini"), true)
+		return
+	}
+
+	db := manager.Controller.GetDB()
+	if db == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "recommend.no_db"), true)
+		return
+	}
+
+	history, err := db.GetHistory(interaction.GuildID, recommendHistory)
+	if err != nil {
+		log.Errorf("Error fetching history for recommend: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "recommend.history_failed"), true)
+		return
+	}
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	current := ""
+	if item := player.GetCurrentItem(); item != nil {
+		current = item.Video.Title
+	}
+
+	log.Infof("Recommend: fetched %d history records for guild %s", len(history), interaction.GuildID)
+
+	if len(history) < 3 && current == "" {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "recommend.need_history"), true)
+		return
+	}
+
+	var songTitles []string
+	exclude := make(map[string]bool)
+	for _, r := range history {
+		if r.Title != current {
+			songTitles = append(songTitles, r.Title)
+		}
+		if r.VideoID != "" {
+			exclude[r.VideoID] = true
+		}
+	}
+	if item := player.GetCurrentItem(); item != nil {
+		exclude[item.Video.VideoID] = true
+	}
+	for _, item := range player.GetQueueSnapshot() {
+		exclude[item.Video.VideoID] = true
+	}
+	// Leave out songs this server blocked or nearly always skips.
+	if blocked, err := db.GetBlockedVideoIDs(interaction.GuildID); err == nil {
+		for id := range blocked {
+			exclude[id] = true
+		}
+	}
+	if skipped, err := db.GetUsuallySkippedVideoIDs(interaction.GuildID); err == nil {
+		for id := range skipped {
+			exclude[id] = true
+		}
+	}
+
+	queries := gemini.GenerateSongRecommendations(ctx, current, songTitles)
+	if len(queries) == 0 {
+		log.Warnf("Recommend: AI returned no queries despite %d history records for guild %s", len(history), interaction.GuildID)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "recommend.ai_failed"), true)
+		return
+	}
+	if len(queries) > maxRecommendations {
+		queries = queries[:maxRecommendations]
+	}
+
+	picks := resolveRecommendations(ctx, queries, exclude)
+	log.Infof("Recommend: resolved %d of %d queries %q for guild %s", len(picks), len(queries), queries, interaction.GuildID)
+	if len(picks) == 0 {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "recommend.no_tracks"), true)
+		return
+	}
+
+	content, components := recommendationMessage(interaction, current, picks)
+	discord.SendFollowup(&discord.FollowUpRequest{
+		Token:      interaction.Token,
+		AppID:      manager.AppID,
+		UserID:     interaction.Member.User.ID,
+		Content:    content,
+		Components: components,
+	})
+}
+
+// resolveRecommendations looks each query up on YouTube at once and keeps
+// the first result per query that isn't excluded or already picked, in
+// the order the AI suggested them.
+func resolveRecommendations(ctx context.Context, queries []string, exclude map[string]bool) []youtube.VideoResponse {
+	var picks []youtube.VideoResponse
+	for _, video := range lookupQueries(ctx, queries, exclude) {
+		if video != nil {
+			picks = append(picks, *video)
+		}
+	}
+	return picks
+}
+
+// lookupQueries is resolveRecommendations keeping each pick at its query's
+// index, nil where a query found nothing usable.
+func lookupQueries(ctx context.Context, queries []string, exclude map[string]bool) []*youtube.VideoResponse {
+	results := make([][]youtube.VideoResponse, len(queries))
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = youtube.Query(ctx, query)
+		}()
+	}
+	wg.Wait()
+
+	picked := make(map[string]bool)
+	picks := make([]*youtube.VideoResponse, len(queries))
+	for i, videos := range results {
+		for _, video := range videos {
+			if video.Live || exclude[video.VideoID] || picked[video.VideoID] {
+				continue
+			}
+			picked[video.VideoID] = true
+			picks[i] = &video
+			break
+		}
+	}
+	return picks
+}
+
+// recommendationMessage lists the picks with a "queue this" button each.
+// The message is public, so anyone can queue any of them.
+func recommendationMessage(interaction *Interaction, current string, picks []youtube.VideoResponse) (string, []discordgo.MessageComponent) {
+	var sb strings.Builder
+	if current != "" {
+		sb.WriteString(i18n.T(interaction.GuildLocale, "recommend.picks_following", current) + "\n\n")
+	} else {
+		sb.WriteString(i18n.T(interaction.GuildLocale, "recommend.picks") + "\n\n")
+	}
+
+	buttons := make([]discordgo.MessageComponent, 0, len(picks))
+	for i, video := range picks {
+		line := fmt.Sprintf("**%d.** [%s](<%s>)", i+1, video.Title, video.PageURL())
+		if video.Duration > 0 {
+			line += " · " + discord.FormatDuration(video.Duration)
+		}
+		sb.WriteString(line + "\n")
+		buttons = append(buttons, discordgo.Button{
+			Label:    truncateRunes(fmt.Sprintf("%d. %s", i+1, video.Title), 80),
+			Style:    discordgo.SecondaryButton,
+			CustomID: discord.ButtonCustomID(recommendAction+video.VideoID, interaction.GuildID),
+			Emoji:    &discordgo.ComponentEmoji{Name: "➕"},
+		})
+	}
+	return sb.String(), []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// handleRecommendPick queues the song behind a /recommend button for
+// whoever clicked it, and marks the button as queued.
+func (manager *Manager) handleRecommendPick(interaction *Interaction, videoID string) Response {
+	go func() {
+		ctx, transaction := sentryhelper.StartCommandTransaction(
+			context.Background(),
+			"recommend_pick",
+			interaction.GuildID,
+			interaction.Member.User.ID,
+		)
+		defer func() {
+			if err := recover(); err != nil {
+				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleRecommendPick: %v", err))
+				transaction.Status = sentry.SpanStatusInternalError
+			}
+			transaction.Finish()
+		}()
+
+		video, err := youtube.GetVideoByID(ctx, videoID)
+		if err != nil {
+			log.Warnf("Recommend: couldn't load picked video %s: %v", videoID, err)
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "recommend.pick_unavailable"), true)
+			return
+		}
+		if len(manager.filterBlocked(interaction.GuildID, []youtube.VideoResponse{video})) == 0 {
+			manager.SendFollowup(ctx, interaction, "", tr(interaction, "favorites.blocked", video.Title), true)
+			return
+		}
+
+		player := manager.Controller.GetPlayer(interaction.GuildID)
+		if !manager.joinRequesterVoice(ctx, interaction, player) {
+			return
+		}
+		manager.queueVideo(ctx, interaction, player, video, nil, "AI DJ recommendation")
+	}()
+
+	if interaction.Message == nil {
+		return Response{Type: 6}
+	}
+	return Response{Type: 7, Data: ResponseData{
+		Content:    interaction.Message.Content,
+		Components: markRecommendationQueued(interaction.Message.Components, interaction.Data.CustomID),
+	}}
+}
+
+// markRecommendationQueued disables the clicked button so the same pick
+// isn't queued twice; the others stay available.
+func markRecommendationQueued(components []discordgo.MessageComponent, customID string) []discordgo.MessageComponent {
+	for _, component := range components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, c := range row.Components {
+			if button, ok := c.(*discordgo.Button); ok && button.CustomID == customID {
+				button.Disabled = true
+				button.Style = discordgo.SuccessButton
+				button.Emoji = &discordgo.ComponentEmoji{Name: "✅"}
+			}
+		}
+	}
+	return components
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"beatbot/discord"
+	"beatbot/youtube"
+)
+
+func TestRecommendationMessage(t *testing.T) {
+	picks := []youtube.VideoResponse{
+		{VideoID: "aaaaaaaaaaa", Title: "First Song", Duration: 3*time.Minute + 5*time.Second},
+		{VideoID: "bbbbbbbbbbb", Title: "Second Song"},
+	}
+	content, components := recommendationMessage(&Interaction{GuildID: "g1"}, "Now Playing", picks)
+
+	if !strings.Contains(content, "to follow **Now Playing**") ||
+		!strings.Contains(content, "**1.** [First Song](<https://www.youtube.com/watch?v=aaaaaaaaaaa>) · 3:05\n") ||
+		!strings.Contains(content, "**2.** [Second Song](<https://www.youtube.com/watch?v=bbbbbbbbbbb>)\n") {
+		t.Errorf("content = %q", content)
+	}
+
+	row := components[0].(discordgo.ActionsRow)
+	if len(row.Components) != 2 {
+		t.Fatalf("got %d buttons, want 2", len(row.Components))
+	}
+	button := row.Components[1].(discordgo.Button)
+	action, guildID, ok := discord.ParseButtonCustomID(button.CustomID)
+	if !ok || guildID != "g1" || action != recommendAction+"bbbbbbbbbbb" {
+		t.Errorf("button custom ID = %q", button.CustomID)
+	}
+	if button.Label != "2. Second Song" {
+		t.Errorf("button label = %q", button.Label)
+	}
+}
+
+func TestMarkRecommendationQueued(t *testing.T) {
+	_, sent := recommendationMessage(&Interaction{GuildID: "g1"}, "", []youtube.VideoResponse{
+		{VideoID: "aaaaaaaaaaa", Title: "First"},
+		{VideoID: "bbbbbbbbbbb", Title: "Second"},
+	})
+	// Round-trip through JSON, as the clicked message comes back from Discord.
+	data, err := json.Marshal(map[string]any{"components": sent})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var message discordgo.Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	clicked := discord.ButtonCustomID(recommendAction+"bbbbbbbbbbb", "g1")
+	row := markRecommendationQueued(message.Components, clicked)[0].(*discordgo.ActionsRow)
+	if first := row.Components[0].(*discordgo.Button); first.Disabled {
+		t.Error("the other pick was disabled too")
+	}
+	if second := row.Components[1].(*discordgo.Button); !second.Disabled {
+		t.Error("the clicked pick is still enabled")
+	}
+}
@@ -0,0 +1,385 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+	"beatbot/database"
+	"beatbot/discord"
+	"beatbot/i18n"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+const (
+	// maxScheduledPlays caps how many /playat entries a guild can have waiting.
+	maxScheduledPlays = 10
+	maxScheduleAhead  = 7 * 24 * time.Hour
+
+	scheduleCheckInterval = 15 * time.Second
+	// scheduleGracePeriod is how late a scheduled play may still start, e.g.
+	// after a restart. Anything older is dropped with a notice.
+	scheduleGracePeriod = 10 * time.Minute
+)
+
+var (
+	errScheduleFormat = errors.New("time should look like `21:00`, `9pm`, `9:30pm`, `30m` or `in 1h30m`")
+	errScheduleRange  = errors.New("time must be at least a minute from now and within the next 7 days")
+)
+
+// scheduleClockLayouts are the wall-clock forms /playat accepts.
+var scheduleClockLayouts = []string{"15:04", "3:04pm", "3pm", "3:04 pm", "3 pm"}
+
+// parseScheduleTime turns /playat's time option into an instant. A clock time
+// means its next occurrence in loc (today, or tomorrow if it has passed); a
+// duration like "30m" or "in 1h30m" counts from now.
+func parseScheduleTime(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	var runAt time.Time
+	if d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(input, "in "))); err == nil {
+		runAt = now.Add(d)
+	} else {
+		var clock time.Time
+		parsed := false
+		for _, layout := range scheduleClockLayouts {
+			if clock, err = time.Parse(layout, input); err == nil {
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			return time.Time{}, errScheduleFormat
+		}
+		local := now.In(loc)
+		runAt = time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if !runAt.After(now) {
+			runAt = runAt.AddDate(0, 0, 1)
+		}
+	}
+
+	if runAt.Sub(now) < time.Minute || runAt.Sub(now) > maxScheduleAhead {
+		return time.Time{}, errScheduleRange
+	}
+	return runAt, nil
+}
+
+// scheduleTimezone is the zone clock times in /playat are read in.
+func scheduleTimezone() *time.Location {
+	if config.Config != nil && config.Config.Options.ScheduleTimezone != nil {
+		return config.Config.Options.ScheduleTimezone
+	}
+	return time.Local
+}
+
+func scheduledPlayLabel(locale string, play database.ScheduledPlay) string {
+	if play.PlaylistID != 0 {
+		return i18n.T(locale, "schedule.playlist_label", play.Title)
+	}
+	return "**" + play.Title + "**"
+}
+
+// onPlayAt schedules a song or playlist for later: `/playat time:21:00
+// playlist:Movie Night`. The song is looked up now, so a bad query fails
+// straight away rather than at start time.
+func (manager *Manager) onPlayAt(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onPlayAt: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	db := manager.Controller.GetDB()
+	if db == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "common.db_unavailable"), true)
+		return
+	}
+
+	var timeOpt, query, playlistName string
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "time":
+			timeOpt = opt.Value
+		case "query":
+			query = strings.TrimSpace(opt.Value)
+		case "playlist":
+			playlistName = strings.TrimSpace(opt.Value)
+		}
+	}
+	if (query == "") == (playlistName == "") {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "schedule.song_or_playlist"), true)
+		return
+	}
+
+	runAt, err := parseScheduleTime(timeOpt, time.Now(), scheduleTimezone())
+	if err != nil {
+		key := "schedule.bad_format"
+		if errors.Is(err, errScheduleRange) {
+			key = "schedule.bad_range"
+		}
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, key), true)
+		return
+	}
+
+	existing, err := db.ListScheduledPlays(interaction.GuildID)
+	if err != nil {
+		log.Errorf("Error listing scheduled plays: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "schedule.add_failed"), true)
+		return
+	}
+	if len(existing) >= maxScheduledPlays {
+		manager.SendFollowup(ctx, interaction, "",
+			tr(interaction, "schedule.full", maxScheduledPlays), true)
+		return
+	}
+
+	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
+	if err != nil {
+		log.Errorf("Error getting voice state: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendError(interaction, tr(interaction, "common.voice_state_error", err.Error()), true)
+		return
+	}
+	if voiceState == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "schedule.join_first"), true)
+		return
+	}
+
+	play := database.ScheduledPlay{
+		GuildID:        interaction.GuildID,
+		VoiceChannelID: voiceState.ChannelID,
+		TextChannelID:  interaction.ChannelID,
+		UserID:         interaction.Member.User.ID,
+		RunAt:          runAt,
+	}
+	if playlistName != "" {
+		playlist, err := db.FindPlaylist(interaction.GuildID, interaction.Member.User.ID, playlistName)
+		if err != nil {
+			manager.SendFollowup(ctx, interaction, "", playlistLookupFailed(interaction, playlistName, err).Data.Content, true)
+			return
+		}
+		play.PlaylistID = playlist.ID
+		play.Title = playlist.Name
+	} else {
+		video, problem := manager.resolveSingleTrack(ctx, interaction, query)
+		if problem != "" {
+			manager.SendFollowup(ctx, interaction, "", problem, true)
+			return
+		}
+		play.VideoID = video.VideoID
+		play.Title = video.Title
+		play.URL = video.PageURL()
+		play.Source = video.Source
+	}
+
+	id, err := db.AddScheduledPlay(play)
+	if err != nil {
+		log.Errorf("Error adding scheduled play: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "schedule.add_failed"), true)
+		return
+	}
+
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "schedule",
+		Message:  fmt.Sprintf("Scheduled play %d for %s", id, runAt.UTC().Format(time.RFC3339)),
+		Level:    sentry.LevelInfo,
+	})
+
+	manager.SendFollowup(ctx, interaction, "",
+		i18n.T(interaction.GuildLocale, "schedule.added",
+			scheduledPlayLabel(interaction.GuildLocale, play), runAt.Unix(), runAt.Unix(), play.VoiceChannelID, id), false)
+}
+
+// handleSchedule routes /schedule list and /schedule cancel.
+func (manager *Manager) handleSchedule(interaction *Interaction) Response {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	plays, err := db.ListScheduledPlays(interaction.GuildID)
+	if err != nil {
+		log.Errorf("Error listing scheduled plays: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "schedule.fetch_failed"), Flags: 64}}
+	}
+
+	if subcommandName(interaction) == "cancel" {
+		return manager.handleScheduleCancel(db, interaction, plays)
+	}
+
+	if len(plays) == 0 {
+		return Response{Type: 4, Data: ResponseData{
+			Content: tr(interaction, "schedule.empty"),
+			Flags:   64,
+		}}
+	}
+	var sb strings.Builder
+	sb.WriteString(tr(interaction, "schedule.list_title") + "\n")
+	for _, play := range plays {
+		sb.WriteString(tr(interaction, "schedule.list_line",
+			play.ID, play.RunAt.Unix(), play.RunAt.Unix(), scheduledPlayLabel(interaction.locale(), play), play.VoiceChannelID, play.UserID) + "\n")
+	}
+	return Response{Type: 4, Data: ResponseData{
+		Content:         sb.String(),
+		Flags:           64,
+		AllowedMentions: noPings,
+	}}
+}
+
+// handleScheduleCancel removes a scheduled play. Whoever scheduled it or a
+// server manager can cancel it.
+func (manager *Manager) handleScheduleCancel(db *database.Database, interaction *Interaction, plays []database.ScheduledPlay) Response {
+	var id int64
+	for _, opt := range commandOptions(interaction) {
+		if opt.Name == "id" {
+			id, _ = strconv.ParseInt(opt.Value, 10, 64)
+		}
+	}
+
+	var play *database.ScheduledPlay
+	for i := range plays {
+		if plays[i].ID == id {
+			play = &plays[i]
+		}
+	}
+	if play == nil {
+		return Response{Type: 4, Data: ResponseData{
+			Content: tr(interaction, "schedule.no_such_play", id),
+			Flags:   64,
+		}}
+	}
+	if play.UserID != interaction.Member.User.ID && !canManageGuild(interaction.Member) {
+		return Response{Type: 4, Data: ResponseData{
+			Content:         tr(interaction, "schedule.not_yours", play.UserID),
+			Flags:           64,
+			AllowedMentions: noPings,
+		}}
+	}
+
+	if _, err := db.CancelScheduledPlay(interaction.GuildID, id); err != nil {
+		log.Errorf("Error cancelling scheduled play: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "schedule.cancel_failed"), Flags: 64}}
+	}
+	return Response{Type: 4, Data: ResponseData{
+		Content: i18n.T(interaction.GuildLocale, "schedule.cancelled", scheduledPlayLabel(interaction.GuildLocale, *play), play.RunAt.Unix()),
+	}}
+}
+
+// StartScheduler starts the background loop that runs /playat entries when
+// they come due. Entries live in the database, so ones scheduled before a
+// restart still run.
+func (manager *Manager) StartScheduler() {
+	go func() {
+		ticker := time.NewTicker(scheduleCheckInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			manager.runDueScheduledPlays()
+		}
+	}()
+}
+
+func (manager *Manager) runDueScheduledPlays() {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return
+	}
+	plays, err := db.TakeDueScheduledPlays(time.Now())
+	if err != nil {
+		log.Errorf("Error fetching due scheduled plays: %v", err)
+		sentry.CaptureException(err)
+		return
+	}
+	for _, play := range plays {
+		if time.Since(play.RunAt) > scheduleGracePeriod {
+			log.Infof("Skipping scheduled play %d in guild %s, due at %v", play.ID, play.GuildID, play.RunAt)
+			manager.announceScheduledPlay(play, "schedule.missed", play.RunAt.Unix())
+			continue
+		}
+		go manager.runScheduledPlay(play)
+	}
+}
+
+// announceScheduledPlay posts the message for key to the channel the play
+// was scheduled from, in the guild's language. The play's label is always
+// the message's first argument.
+func (manager *Manager) announceScheduledPlay(play database.ScheduledPlay, key string, args ...any) {
+	if play.TextChannelID == "" {
+		return
+	}
+	locale := manager.Controller.GetPlayer(play.GuildID).GetGuildLocale()
+	content := i18n.T(locale, key, append([]any{scheduledPlayLabel(locale, play)}, args...)...)
+	if _, err := discord.SendChannelMessage(play.TextChannelID, content, nil, nil); err != nil {
+		log.Errorf("Failed to announce scheduled play: %v", err)
+	}
+}
+
+// runScheduledPlay queues a due play, joining voice first if needed: the
+// scheduler's current channel if they're in one, otherwise the channel they
+// scheduled from.
+func (manager *Manager) runScheduledPlay(play database.ScheduledPlay) {
+	ctx, transaction := sentryhelper.StartCommandTransaction(context.Background(), "scheduled_play", play.GuildID, play.UserID)
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in runScheduledPlay: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	var videos []youtube.VideoResponse
+	if play.PlaylistID != 0 {
+		tracks, err := manager.Controller.GetDB().GetPlaylistTracks(play.PlaylistID)
+		if err != nil {
+			log.Errorf("Error fetching playlist tracks: %v", err)
+			sentryhelper.CaptureException(ctx, err)
+		}
+		for _, t := range tracks {
+			videos = append(videos, playlistTrackVideo(t))
+		}
+	} else {
+		videos = append(videos, savedVideo(play.VideoID, play.Title, play.URL, play.Source))
+	}
+
+	player := manager.Controller.GetPlayer(play.GuildID)
+	videosToQueue := dropQueued(player, manager.filterBlocked(play.GuildID, videos))
+	if len(videosToQueue) == 0 {
+		manager.announceScheduledPlay(play, "schedule.nothing_to_queue")
+		return
+	}
+
+	channelID := play.VoiceChannelID
+	if voiceState, err := discord.GetMemberVoiceState(&play.UserID, &play.GuildID); err == nil && voiceState != nil {
+		channelID = voiceState.ChannelID
+	}
+	if player.ShouldJoinVoice(channelID) {
+		if err := player.JoinChannel(channelID); err != nil {
+			sentryhelper.CaptureException(ctx, err)
+			manager.announceScheduledPlay(play, "schedule.join_failed", channelID, err.Error())
+			return
+		}
+	}
+
+	for _, video := range videosToQueue {
+		player.Add(ctx, video, play.UserID, "", manager.AppID, nil)
+	}
+
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "schedule",
+		Message:  fmt.Sprintf("Ran scheduled play %d, queued %d tracks", play.ID, len(videosToQueue)),
+		Level:    sentry.LevelInfo,
+	})
+
+	manager.announceScheduledPlay(play, "schedule.starting", play.UserID)
+}
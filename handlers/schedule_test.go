@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	loc := time.FixedZone("test", -5*60*60)
+	now := time.Date(2024, 6, 1, 18, 30, 0, 0, loc)
+
+	tests := []struct {
+		input string
+		want  time.Time
+		err   error
+	}{
+		{"21:00", time.Date(2024, 6, 1, 21, 0, 0, 0, loc), nil},
+		{"9pm", time.Date(2024, 6, 1, 21, 0, 0, 0, loc), nil},
+		{"9:15 PM", time.Date(2024, 6, 1, 21, 15, 0, 0, loc), nil},
+		{"08:00", time.Date(2024, 6, 2, 8, 0, 0, 0, loc), nil}, // passed today, so tomorrow
+		{"18:30", time.Date(2024, 6, 2, 18, 30, 0, 0, loc), nil},
+		{"30m", now.Add(30 * time.Minute), nil},
+		{"in 1h30m", now.Add(90 * time.Minute), nil},
+		{"10s", time.Time{}, errScheduleRange},
+		{"200h", time.Time{}, errScheduleRange},
+		{"-1h", time.Time{}, errScheduleRange},
+		{"tonight", time.Time{}, errScheduleFormat},
+		{"25:00", time.Time{}, errScheduleFormat},
+	}
+	for _, tt := range tests {
+		got, err := parseScheduleTime(tt.input, now, loc)
+		if err != tt.err || !got.Equal(tt.want) {
+			t.Errorf("parseScheduleTime(%q) = %v, %v; want %v, %v", tt.input, got, err, tt.want, tt.err)
+		}
+	}
+}
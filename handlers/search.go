@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+const (
+	// searchChoices is how many results the /search menu offers.
+	searchChoices = 5
+	// searchTTL is how long a search menu stays pickable.
+	searchTTL = 10 * time.Minute
+	// selectTextLimit is Discord's cap on select option labels and descriptions.
+	selectTextLimit = 100
+)
+
+// pendingSearch holds the results a user is choosing from.
+type pendingSearch struct {
+	videos      []youtube.VideoResponse
+	sourceLabel string
+	expires     time.Time
+}
+
+// searchCache keeps each user's latest search per guild until they pick a
+// result or it expires. A new search replaces the old menu's results.
+type searchCache struct {
+	mu      sync.Mutex
+	entries map[string]pendingSearch
+}
+
+func newSearchCache() *searchCache {
+	return &searchCache{entries: make(map[string]pendingSearch)}
+}
+
+func searchKey(guildID, userID string) string {
+	return guildID + ":" + userID
+}
+
+func (c *searchCache) put(guildID, userID string, search pendingSearch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[searchKey(guildID, userID)] = search
+}
+
+// take removes and returns the user's pending search, if it hasn't expired.
+func (c *searchCache) take(guildID, userID string) (pendingSearch, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := searchKey(guildID, userID)
+	search, ok := c.entries[key]
+	delete(c.entries, key)
+	if !ok || time.Now().After(search.expires) {
+		return pendingSearch{}, false
+	}
+	return search, true
+}
+
+// searchMenuOptions builds one select option per result, labeled with the
+// title and described by channel and length.
+func searchMenuOptions(interaction *Interaction, videos []youtube.VideoResponse) []discordgo.SelectMenuOption {
+	options := make([]discordgo.SelectMenuOption, 0, len(videos))
+	for i, video := range videos {
+		description := video.ChannelName
+		length := ""
+		switch {
+		case video.Live:
+			length = tr(interaction, "search.live")
+		case video.Duration > 0:
+			length = discord.FormatDuration(video.Duration)
+		}
+		if length != "" {
+			if description != "" {
+				description += " · "
+			}
+			description += length
+		}
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       truncateRunes(video.Title, selectTextLimit),
+			Value:       strconv.Itoa(i),
+			Description: truncateRunes(description, selectTextLimit),
+		})
+	}
+	return options
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// offerSearchResults sends the top results as a select menu instead of
+// queueing the first hit.
+func (manager *Manager) offerSearchResults(interaction *Interaction, query string, videos []youtube.VideoResponse, sourceLabel string) {
+	if len(videos) > searchChoices {
+		videos = videos[:searchChoices]
+	}
+	manager.searches.put(interaction.GuildID, interaction.Member.User.ID, pendingSearch{
+		videos:      videos,
+		sourceLabel: sourceLabel,
+		expires:     time.Now().Add(searchTTL),
+	})
+
+	discord.SendFollowup(&discord.FollowUpRequest{
+		Token:      interaction.Token,
+		AppID:      manager.AppID,
+		UserID:     interaction.Member.User.ID,
+		Content:    tr(interaction, "search.results", query),
+		Components: discord.SelectMenu(interaction.GuildID, "search_pick", tr(interaction, "search.placeholder"), searchMenuOptions(interaction, videos)),
+	})
+}
+
+func (manager *Manager) handleSearch(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
+	go manager.QueryAndQueue(ctx, transaction, interaction)
+
+	// Deferred ephemeral: only the searcher sees the menu.
+	return Response{
+		Type: 5,
+		Data: ResponseData{
+			Flags: 64,
+		},
+	}
+}
+
+// handleSearchPick queues the result picked from a /search menu. The menu
+// is replaced right away; joining voice and queueing happen afterwards.
+func (manager *Manager) handleSearchPick(interaction *Interaction) Response {
+	search, ok := manager.searches.take(interaction.GuildID, interaction.Member.User.ID)
+	if !ok {
+		return Response{
+			Type: 4,
+			Data: ResponseData{
+				Content: tr(interaction, "search.expired"),
+				Flags:   64,
+			},
+		}
+	}
+
+	index := -1
+	if len(interaction.Data.Values) == 1 {
+		index, _ = strconv.Atoi(interaction.Data.Values[0])
+	}
+	if index < 0 || index >= len(search.videos) {
+		return Response{Type: 7, Data: ResponseData{
+			Content:    tr(interaction, "search.bad_pick"),
+			Components: discord.DisabledButton(tr(interaction, "search.bad_pick_button")),
+		}}
+	}
+	video := search.videos[index]
+
+	go func() {
+		ctx, transaction := sentryhelper.StartCommandTransaction(
+			context.Background(),
+			"search_pick",
+			interaction.GuildID,
+			interaction.Member.User.ID,
+		)
+		defer func() {
+			if err := recover(); err != nil {
+				sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleSearchPick: %v", err))
+				transaction.Status = sentry.SpanStatusInternalError
+			}
+			transaction.Finish()
+		}()
+
+		player := manager.Controller.GetPlayer(interaction.GuildID)
+		if !manager.joinRequesterVoice(ctx, interaction, player) {
+			return
+		}
+		log.WithFields(log.Fields{
+			"module":   "handlers",
+			"guild_id": interaction.GuildID,
+			"video_id": video.VideoID,
+			"choice":   index + 1,
+			"user_id":  interaction.Member.User.ID,
+		}).Info("Queued search pick")
+		// The user chose this exact result, so no fallbacks.
+		manager.queueVideo(ctx, interaction, player, video, nil, search.sourceLabel)
+	}()
+
+	return Response{Type: 7, Data: ResponseData{
+		Content:    tr(interaction, "search.picked", video.Title),
+		Components: discord.DisabledButton(tr(interaction, "search.picked_button")),
+	}}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/youtube"
+)
+
+func TestSearchCacheTake(t *testing.T) {
+	cache := newSearchCache()
+	videos := []youtube.VideoResponse{{VideoID: "a"}, {VideoID: "b"}}
+	cache.put("g", "u", pendingSearch{videos: videos, expires: time.Now().Add(time.Minute)})
+
+	if _, ok := cache.take("g", "other"); ok {
+		t.Error("another user should not see the search")
+	}
+	search, ok := cache.take("g", "u")
+	if !ok || len(search.videos) != 2 {
+		t.Fatalf("take() = %+v, %v; want the stored search", search, ok)
+	}
+	if _, ok := cache.take("g", "u"); ok {
+		t.Error("a search should only be pickable once")
+	}
+
+	cache.put("g", "u", pendingSearch{videos: videos, expires: time.Now().Add(-time.Second)})
+	if _, ok := cache.take("g", "u"); ok {
+		t.Error("expired search should not be returned")
+	}
+}
+
+func TestSearchMenuOptions(t *testing.T) {
+	videos := []youtube.VideoResponse{
+		{Title: "Song", ChannelName: "Artist", Duration: 3*time.Minute + 5*time.Second},
+		{Title: strings.Repeat("x", 150)},
+	}
+
+	options := searchMenuOptions(&Interaction{}, videos)
+	if len(options) != 2 {
+		t.Fatalf("len(options) = %d, want 2", len(options))
+	}
+	if options[0].Value != "0" || options[0].Label != "Song" || options[0].Description != "Artist · 3:05" {
+		t.Errorf("options[0] = %+v", options[0])
+	}
+	if n := len([]rune(options[1].Label)); n != selectTextLimit {
+		t.Errorf("long label has %d runes, want %d", n, selectTextLimit)
+	}
+	if options[1].Description != "" {
+		t.Errorf("options[1].Description = %q, want empty", options[1].Description)
+	}
+}
@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+	"beatbot/discord"
+	"beatbot/gemini"
+	"beatbot/resolver"
+)
+
+const (
+	// maxTrackDurationMinutes is the highest song length limit /settings accepts.
+	maxTrackDurationMinutes = 600
+	// maxDefaultVolume matches the range /volume documents.
+	maxDefaultVolume = 100
+)
+
+// voiceControlCommands are the commands (and the buttons that follow them)
+// that require being in the bot's voice channel when a guild enforces it.
+var voiceControlCommands = map[string]bool{
+	"skip":       true,
+	"pause":      true,
+	"resume":     true,
+	"volume":     true,
+	"clear":      true,
+	"remove":     true,
+	"jump":       true,
+	"undo":       true,
+	"reset":      true,
+	"disconnect": true,
+	"shuffle":    true,
+	"loop":       true,
+}
+
+// guildSettings is what the /settings panel shows and edits.
+type guildSettings struct {
+	AIStyle        string
+	DefaultVolume  string // "" when unset
+	MaxDuration    time.Duration
+	MusicChannelID string
+	EnforceVoice   string // "on", "off" or "" for the bot default
+	QuietResponses bool
+	SearchSource   string // "" for the automatic order
+}
+
+func (manager *Manager) loadGuildSettings(player *controller.GuildPlayer) guildSettings {
+	settings := guildSettings{
+		AIStyle:        player.GetAIStyle(),
+		MaxDuration:    player.GetMaxTrackDuration(),
+		MusicChannelID: player.GetMusicChannelID(),
+		EnforceVoice:   player.GetEnforceVoiceMode(),
+		QuietResponses: player.GetQuietResponses(),
+		SearchSource:   player.GetSearchSource(),
+	}
+	if player.DB != nil {
+		settings.DefaultVolume, _ = player.DB.GetGuildSetting(player.GuildID, "default_volume")
+	}
+	return settings
+}
+
+// searchSourceOptions are the defaults /settings offers for where /play
+// searches. Spotify is only listed when the integration is on.
+func searchSourceOptions(interaction *Interaction, current string) []discordgo.SelectMenuOption {
+	options := []discordgo.SelectMenuOption{
+		{Label: tr(interaction, "settings.source_auto"), Value: resolver.SourceAuto},
+		{Label: tr(interaction, "settings.source_youtube"), Value: resolver.SourceYouTube},
+		{Label: tr(interaction, "settings.source_soundcloud"), Value: resolver.SourceSoundCloud},
+	}
+	if resolver.Enabled(resolver.SourceSpotify) || current == resolver.SourceSpotify {
+		options = append(options, discordgo.SelectMenuOption{Label: tr(interaction, "settings.source_spotify"), Value: resolver.SourceSpotify})
+	}
+	for i := range options {
+		options[i].Default = options[i].Value == current ||
+			(options[i].Value == resolver.SourceAuto && current == "")
+	}
+	return options
+}
+
+// settingsPanel renders the /settings message: a summary plus controls for
+// the music channel, voice enforcement, reply visibility and search source,
+// and a button that opens the modal for the free-text settings.
+func settingsPanel(interaction *Interaction, settings guildSettings) (string, []discordgo.MessageComponent) {
+	guildID := interaction.GuildID
+	style := tr(interaction, "settings.style_default")
+	if settings.AIStyle != "" {
+		style = "“" + settings.AIStyle + "”"
+	}
+	volume := tr(interaction, "settings.volume_default")
+	if settings.DefaultVolume != "" {
+		volume = settings.DefaultVolume
+	}
+	maxDuration := tr(interaction, "settings.no_limit")
+	if settings.MaxDuration > 0 {
+		maxDuration = tr(interaction, "settings.minutes", int(settings.MaxDuration.Minutes()))
+	}
+	channel := tr(interaction, "settings.channel_default")
+	if settings.MusicChannelID != "" {
+		channel = "<#" + settings.MusicChannelID + ">"
+	}
+	enforce := map[string]string{
+		"on":  tr(interaction, "common.yes"),
+		"off": tr(interaction, "common.no"),
+		"":    tr(interaction, "settings.bot_default"),
+	}[settings.EnforceVoice]
+	replies := tr(interaction, "settings.replies_public")
+	if settings.QuietResponses {
+		replies = tr(interaction, "settings.replies_quiet")
+	}
+	search := tr(interaction, "settings.source_auto")
+	for _, opt := range searchSourceOptions(interaction, settings.SearchSource) {
+		if opt.Default {
+			search = opt.Label
+		}
+	}
+
+	content := tr(interaction, "settings.panel", style, volume, maxDuration, channel, enforce, replies, search)
+
+	zero := 0
+	channelMenu := discordgo.SelectMenu{
+		MenuType:     discordgo.ChannelSelectMenu,
+		CustomID:     discord.ButtonCustomID("settings_channel", guildID),
+		Placeholder:  tr(interaction, "settings.channel_placeholder"),
+		MinValues:    &zero,
+		MaxValues:    1,
+		ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+	}
+	if settings.MusicChannelID != "" {
+		channelMenu.DefaultValues = []discordgo.SelectMenuDefaultValue{
+			{ID: settings.MusicChannelID, Type: discordgo.SelectMenuDefaultValueChannel},
+		}
+	}
+
+	voiceOptions := []discordgo.SelectMenuOption{
+		{Label: tr(interaction, "settings.voice_on"), Value: "on"},
+		{Label: tr(interaction, "settings.voice_off"), Value: "off"},
+		{Label: tr(interaction, "settings.voice_default"), Value: "default"},
+	}
+	for i := range voiceOptions {
+		voiceOptions[i].Default = voiceOptions[i].Value == settings.EnforceVoice ||
+			(voiceOptions[i].Value == "default" && settings.EnforceVoice == "")
+	}
+
+	responseOptions := []discordgo.SelectMenuOption{
+		{Label: tr(interaction, "settings.responses_public"), Value: "public", Default: !settings.QuietResponses},
+		{Label: tr(interaction, "settings.responses_quiet"), Value: "quiet", Default: settings.QuietResponses},
+	}
+
+	return content, []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{channelMenu}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				MenuType:    discordgo.StringSelectMenu,
+				CustomID:    discord.ButtonCustomID("settings_voice", guildID),
+				Placeholder: tr(interaction, "settings.voice_placeholder"),
+				MaxValues:   1,
+				Options:     voiceOptions,
+			},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				MenuType:    discordgo.StringSelectMenu,
+				CustomID:    discord.ButtonCustomID("settings_responses", guildID),
+				Placeholder: tr(interaction, "settings.responses_placeholder"),
+				MaxValues:   1,
+				Options:     responseOptions,
+			},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				MenuType:    discordgo.StringSelectMenu,
+				CustomID:    discord.ButtonCustomID("settings_source", guildID),
+				Placeholder: tr(interaction, "settings.source_placeholder"),
+				MaxValues:   1,
+				Options:     searchSourceOptions(interaction, settings.SearchSource),
+			},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    tr(interaction, "settings.edit_button"),
+				Style:    discordgo.PrimaryButton,
+				CustomID: discord.ButtonCustomID("settings_edit", guildID),
+				Emoji:    &discordgo.ComponentEmoji{Name: "✏️"},
+			},
+		}},
+	}
+}
+
+// settingsModal asks for the free-text settings, prefilled with the current
+// values.
+func settingsModal(interaction *Interaction, settings guildSettings) Response {
+	maxDuration := ""
+	if settings.MaxDuration > 0 {
+		maxDuration = strconv.Itoa(int(settings.MaxDuration.Minutes()))
+	}
+	field := func(input discordgo.TextInput) discordgo.MessageComponent {
+		return discordgo.ActionsRow{Components: []discordgo.MessageComponent{input}}
+	}
+	return Response{
+		Type: 9,
+		Data: ResponseData{
+			CustomID: discord.ButtonCustomID("settings_modal", interaction.GuildID),
+			Title:    tr(interaction, "settings.modal_title"),
+			Components: []discordgo.MessageComponent{
+				field(discordgo.TextInput{
+					CustomID:    "ai_style",
+					Label:       tr(interaction, "settings.style_label"),
+					Style:       discordgo.TextInputParagraph,
+					Placeholder: tr(interaction, "settings.style_placeholder"),
+					Value:       settings.AIStyle,
+					MaxLength:   gemini.MaxGuildStyleLength,
+				}),
+				field(discordgo.TextInput{
+					CustomID:  "default_volume",
+					Label:     tr(interaction, "settings.volume_label", maxDefaultVolume),
+					Style:     discordgo.TextInputShort,
+					Value:     settings.DefaultVolume,
+					MaxLength: 3,
+				}),
+				field(discordgo.TextInput{
+					CustomID:  "max_duration",
+					Label:     tr(interaction, "settings.max_length_label"),
+					Style:     discordgo.TextInputShort,
+					Value:     maxDuration,
+					MaxLength: 3,
+				}),
+			},
+		},
+	}
+}
+
+// parseSettingsModal validates the submitted modal fields. volume is -1 when
+// left blank.
+func parseSettingsModal(interaction *Interaction, values map[string]string) (style string, volume int, maxDuration time.Duration, problem string) {
+	style = strings.TrimSpace(values["ai_style"])
+	if problem := gemini.CheckGuildStyle(style); problem != "" {
+		return "", 0, 0, problem
+	}
+
+	volume = -1
+	if v := strings.TrimSpace(values["default_volume"]); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxDefaultVolume {
+			return "", 0, 0, tr(interaction, "settings.bad_volume", maxDefaultVolume)
+		}
+		volume = n
+	}
+
+	if v := strings.TrimSpace(values["max_duration"]); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxTrackDurationMinutes {
+			return "", 0, 0, tr(interaction, "settings.bad_max_length", maxTrackDurationMinutes)
+		}
+		maxDuration = time.Duration(n) * time.Minute
+	}
+	return style, volume, maxDuration, ""
+}
+
+// modalValues flattens a submitted modal into custom ID → value.
+func modalValues(interaction *Interaction) map[string]string {
+	values := make(map[string]string)
+	for _, row := range interaction.Data.Components {
+		for _, field := range row.Components {
+			values[field.CustomID] = field.Value
+		}
+	}
+	return values
+}
+
+// handleSettings shows the settings panel. Restricted to Manage Server via
+// DefaultMemberPermissions in Commands.
+func (manager *Manager) handleSettings(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	content, components := settingsPanel(interaction, manager.loadGuildSettings(player))
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content:    content,
+			Components: components,
+			Flags:      64,
+		},
+	}
+}
+
+// settingsDenied guards the panel's components and modal, which only server
+// managers should be able to reach anyway.
+func settingsDenied(interaction *Interaction) (Response, bool) {
+	if canManageGuild(interaction.Member) {
+		return Response{}, false
+	}
+	return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "settings.managers_only"), Flags: 64}}, true
+}
+
+// saveGuildSetting persists one setting, logging failures. The in-memory
+// value is applied by the caller either way so the change takes effect.
+func saveGuildSetting(player *controller.GuildPlayer, key, value string) bool {
+	if player.DB == nil {
+		return true
+	}
+	if err := player.DB.SetGuildSetting(player.GuildID, key, value); err != nil {
+		log.Errorf("Failed to save %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// updatedSettingsPanel re-renders the panel in place after a change.
+func (manager *Manager) updatedSettingsPanel(interaction *Interaction, player *controller.GuildPlayer, saved bool) Response {
+	content, components := settingsPanel(interaction, manager.loadGuildSettings(player))
+	if !saved {
+		content += "\n\n" + tr(interaction, "settings.not_saved")
+	}
+	return Response{Type: 7, Data: ResponseData{Content: content, Components: components}}
+}
+
+func (manager *Manager) handleSettingsChannel(interaction *Interaction) Response {
+	if refusal, denied := settingsDenied(interaction); denied {
+		return refusal
+	}
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	var channelID string
+	if len(interaction.Data.Values) == 1 {
+		channelID = interaction.Data.Values[0]
+	}
+	saved := saveGuildSetting(player, "music_channel_id", channelID)
+	player.SetMusicChannelID(channelID)
+	return manager.updatedSettingsPanel(interaction, player, saved)
+}
+
+func (manager *Manager) handleSettingsVoice(interaction *Interaction) Response {
+	if refusal, denied := settingsDenied(interaction); denied {
+		return refusal
+	}
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	var mode string
+	if len(interaction.Data.Values) == 1 && interaction.Data.Values[0] != "default" {
+		mode = interaction.Data.Values[0]
+	}
+	saved := saveGuildSetting(player, "enforce_voice_channel", mode)
+	player.SetEnforceVoiceMode(mode)
+	return manager.updatedSettingsPanel(interaction, player, saved)
+}
+
+func (manager *Manager) handleSettingsResponses(interaction *Interaction) Response {
+	if refusal, denied := settingsDenied(interaction); denied {
+		return refusal
+	}
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	quiet := len(interaction.Data.Values) == 1 && interaction.Data.Values[0] == "quiet"
+	saved := saveGuildSetting(player, "quiet_responses", strconv.FormatBool(quiet))
+	player.SetQuietResponses(quiet)
+	return manager.updatedSettingsPanel(interaction, player, saved)
+}
+
+func (manager *Manager) handleSettingsSource(interaction *Interaction) Response {
+	if refusal, denied := settingsDenied(interaction); denied {
+		return refusal
+	}
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	var source string
+	if len(interaction.Data.Values) == 1 && interaction.Data.Values[0] != resolver.SourceAuto {
+		source = interaction.Data.Values[0]
+	}
+	saved := saveGuildSetting(player, "search_source", source)
+	player.SetSearchSource(source)
+	return manager.updatedSettingsPanel(interaction, player, saved)
+}
+
+func (manager *Manager) handleSettingsEdit(interaction *Interaction) Response {
+	if refusal, denied := settingsDenied(interaction); denied {
+		return refusal
+	}
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	return settingsModal(interaction, manager.loadGuildSettings(player))
+}
+
+func (manager *Manager) handleSettingsModal(interaction *Interaction) Response {
+	if refusal, denied := settingsDenied(interaction); denied {
+		return refusal
+	}
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	style, volume, maxDuration, problem := parseSettingsModal(interaction, modalValues(interaction))
+	if problem != "" {
+		return Response{Type: 4, Data: ResponseData{Content: problem, Flags: 64}}
+	}
+
+	volumeSetting := ""
+	if volume >= 0 {
+		volumeSetting = strconv.Itoa(volume)
+	}
+	saved := saveGuildSetting(player, "ai_style", style)
+	saved = saveGuildSetting(player, "default_volume", volumeSetting) && saved
+	saved = saveGuildSetting(player, "max_duration_minutes", strconv.Itoa(int(maxDuration.Minutes()))) && saved
+
+	player.SetAIStyle(style)
+	player.SetMaxTrackDuration(maxDuration)
+	if volume >= 0 {
+		player.Player.SetVolume(volume)
+		// A new default replaces whatever volume was last set by hand
+		if err := player.ForgetVolume(); err != nil {
+			log.Errorf("Failed to reset remembered volume: %v", err)
+			saved = false
+		}
+	}
+	return manager.updatedSettingsPanel(interaction, player, saved)
+}
+
+// handleModalSubmit routes modal submissions (Type 5) by the modal's custom ID.
+func (manager *Manager) handleModalSubmit(interaction *Interaction) Response {
+	action, guildID, ok := discord.ParseButtonCustomID(interaction.Data.CustomID)
+	if !ok || guildID != interaction.GuildID {
+		log.Errorf("Invalid modal custom_id: %s", interaction.Data.CustomID)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "settings.invalid_form"), Flags: 64}}
+	}
+
+	switch action {
+	case "settings_modal":
+		return manager.handleSettingsModal(interaction)
+	default:
+		log.Errorf("Unknown modal: %s", action)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "settings.unknown_form"), Flags: 64}}
+	}
+}
+
+// requireListener refuses playback controls from members outside the bot's
+// voice channel when the guild enforces it. Server managers are exempt, and
+// nothing is enforced while the bot isn't in voice. ok is false when the
+// caller should return the refusal.
+func (manager *Manager) requireListener(interaction *Interaction, command string) (refusal Response, ok bool) {
+	if !voiceControlCommands[command] {
+		return Response{}, true
+	}
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if !player.EnforcesVoiceChannel() || canManageGuild(interaction.Member) {
+		return Response{}, true
+	}
+	botChannel := player.GetVoiceChannelID()
+	if botChannel == nil || *botChannel == "" {
+		return Response{}, true
+	}
+
+	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
+	if err != nil {
+		// Don't lock people out because of a failed lookup.
+		log.Warnf("Voice channel check failed, allowing /%s: %v", command, err)
+		return Response{}, true
+	}
+	if voiceState != nil && voiceState.ChannelID == *botChannel {
+		return Response{}, true
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: tr(interaction, "settings.join_to_control", *botChannel),
+			Flags:   64,
+		},
+	}, false
+}
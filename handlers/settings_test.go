@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseSettingsModal(t *testing.T) {
+	style, volume, maxDuration, problem := parseSettingsModal(&Interaction{}, map[string]string{
+		"ai_style":       "  talk like a pirate ",
+		"default_volume": "40",
+		"max_duration":   "10",
+	})
+	if problem != "" || style != "talk like a pirate" || volume != 40 || maxDuration != 10*time.Minute {
+		t.Errorf("got %q, %d, %v, %q", style, volume, maxDuration, problem)
+	}
+
+	_, volume, maxDuration, problem = parseSettingsModal(&Interaction{}, map[string]string{})
+	if problem != "" || volume != -1 || maxDuration != 0 {
+		t.Errorf("blank fields should mean unset, got %d, %v, %q", volume, maxDuration, problem)
+	}
+
+	for _, bad := range []map[string]string{
+		{"default_volume": "loud"},
+		{"default_volume": "101"},
+		{"max_duration": "-5"},
+		{"max_duration": "9999"},
+		{"ai_style": strings.Repeat("x", 301)},
+	} {
+		if _, _, _, problem := parseSettingsModal(&Interaction{}, bad); problem == "" {
+			t.Errorf("parseSettingsModal(%v) should be rejected", bad)
+		}
+	}
+}
+
+func TestModalValues(t *testing.T) {
+	var interaction Interaction
+	payload := `{"type":5,"data":{"custom_id":"np:settings_modal:g1","components":[
+		{"type":1,"components":[{"type":4,"custom_id":"ai_style","value":"chill"}]},
+		{"type":1,"components":[{"type":4,"custom_id":"max_duration","value":"8"}]}]}}`
+	if err := json.Unmarshal([]byte(payload), &interaction); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	values := modalValues(&interaction)
+	if values["ai_style"] != "chill" || values["max_duration"] != "8" {
+		t.Errorf("modalValues = %v", values)
+	}
+}
+
+func TestSettingsModalFitsDiscordLimits(t *testing.T) {
+	modal := settingsModal(&Interaction{GuildID: "g1"}, guildSettings{MaxDuration: 15 * time.Minute})
+	if modal.Type != 9 || modal.Data.CustomID != "np:settings_modal:g1" {
+		t.Fatalf("modal = %+v", modal)
+	}
+	for _, row := range modal.Data.Components {
+		input := row.(discordgo.ActionsRow).Components[0].(discordgo.TextInput)
+		if n := len([]rune(input.Label)); n > 45 {
+			t.Errorf("label %q is %d chars, Discord allows 45", input.Label, n)
+		}
+		if input.CustomID == "max_duration" && input.Value != "15" {
+			t.Errorf("max_duration prefilled with %q, want 15", input.Value)
+		}
+	}
+}
+
+func TestSettingsPanel(t *testing.T) {
+	content, components := settingsPanel(&Interaction{GuildID: "g1"}, guildSettings{MusicChannelID: "c1", EnforceVoice: "on"})
+	if !strings.Contains(content, "<#c1>") || !strings.Contains(content, "no limit") {
+		t.Errorf("content = %q", content)
+	}
+	if len(components) != 5 {
+		t.Fatalf("got %d rows, want 5", len(components))
+	}
+	voice := components[1].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+	for _, opt := range voice.Options {
+		if opt.Default != (opt.Value == "on") {
+			t.Errorf("option %q default = %v", opt.Value, opt.Default)
+		}
+	}
+}
+
+func TestSettingsPanelQuietResponses(t *testing.T) {
+	content, components := settingsPanel(&Interaction{GuildID: "g1"}, guildSettings{QuietResponses: true})
+	if !strings.Contains(content, "only whoever asked") {
+		t.Errorf("content = %q", content)
+	}
+	responses := components[2].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+	if responses.CustomID != "np:settings_responses:g1" {
+		t.Errorf("custom ID = %q", responses.CustomID)
+	}
+	for _, opt := range responses.Options {
+		if opt.Default != (opt.Value == "quiet") {
+			t.Errorf("option %q default = %v", opt.Value, opt.Default)
+		}
+		if n := len([]rune(opt.Label)); n > selectTextLimit {
+			t.Errorf("label %q is %d chars", opt.Label, n)
+		}
+	}
+}
+
+func TestSettingsPanelSearchSource(t *testing.T) {
+	content, components := settingsPanel(&Interaction{GuildID: "g1"}, guildSettings{})
+	if !strings.Contains(content, "**/play searches:** Search YouTube, then SoundCloud") {
+		t.Errorf("content = %q", content)
+	}
+
+	content, components = settingsPanel(&Interaction{GuildID: "g1"}, guildSettings{SearchSource: "soundcloud"})
+	if !strings.Contains(content, "Search SoundCloud") {
+		t.Errorf("content = %q", content)
+	}
+	source := components[3].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+	if source.CustomID != "np:settings_source:g1" {
+		t.Errorf("custom ID = %q", source.CustomID)
+	}
+	for _, opt := range source.Options {
+		if opt.Default != (opt.Value == "soundcloud") {
+			t.Errorf("option %q default = %v", opt.Value, opt.Default)
+		}
+		if n := len([]rune(opt.Label)); n > selectTextLimit {
+			t.Errorf("label %q is %d chars", opt.Label, n)
+		}
+	}
+
+	// A Spotify default stays visible even if the integration is later off.
+	options := searchSourceOptions(&Interaction{}, "spotify")
+	if last := options[len(options)-1]; last.Value != "spotify" || !last.Default {
+		t.Errorf("options = %+v, want spotify selected", options)
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/audio"
+	"beatbot/controller"
+	"beatbot/database"
+	"beatbot/discord"
+	"beatbot/sentryhelper"
+)
+
+const (
+	// maxSounds caps a server's soundboard; it's also all that fits in the
+	// name autocomplete.
+	maxSounds         = autocompleteLimit
+	maxSoundFileBytes = 8 << 20
+	soundNameLimit    = 32
+)
+
+// handleSoundboard routes /soundboard subcommands. Uploads and plays reply
+// later, once the file is converted or the sound has started.
+func (manager *Manager) handleSoundboard(ctx context.Context, transaction *sentry.Span, interaction *Interaction) Response {
+	switch subcommandName(interaction) {
+	case "upload":
+		go manager.onSoundUpload(ctx, transaction, interaction)
+		return Response{Type: 5}
+	default:
+		go manager.onSoundPlay(ctx, transaction, interaction)
+		return Response{Type: 5, Data: ResponseData{Flags: 64}}
+	}
+}
+
+// handleSoundboardList lists the server's sounds for /soundboard list.
+func (manager *Manager) handleSoundboardList(interaction *Interaction) Response {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+	sounds, err := db.ListSounds(interaction.GuildID)
+	if err != nil {
+		log.Errorf("Failed to list sounds: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "soundboard.load_failed"), Flags: 64}}
+	}
+	if len(sounds) == 0 {
+		return Response{Type: 4, Data: ResponseData{
+			Content: tr(interaction, "soundboard.empty"),
+			Flags:   64,
+		}}
+	}
+
+	var sb strings.Builder
+	for _, s := range sounds {
+		sb.WriteString(fmt.Sprintf("🔊 **%s** · %.1fs · <@%s>\n", s.Name, s.Duration.Seconds(), s.UploadedBy))
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Embeds: []*discordgo.MessageEmbed{{
+				Title:       tr(interaction, "soundboard.title", len(sounds), maxSounds),
+				Description: sb.String(),
+				Color:       0x1DB954,
+				Footer:      &discordgo.MessageEmbedFooter{Text: tr(interaction, "soundboard.footer")},
+			}},
+			Flags:           64,
+			AllowedMentions: noPings,
+		},
+	}
+}
+
+// onSoundUpload converts an uploaded file and adds it to the soundboard,
+// replacing a sound of the same name if the uploader added it or manages the
+// server.
+func (manager *Manager) onSoundUpload(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onSoundUpload: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	db := manager.Controller.GetDB()
+	if db == nil {
+		manager.SendRequest(interaction, tr(interaction, "common.db_unavailable"), true)
+		return
+	}
+	var name, attachmentID string
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "name":
+			name = strings.TrimSpace(opt.Value)
+		case "file":
+			attachmentID = opt.Value
+		}
+	}
+	if problem := soundNameProblem(interaction, name); problem != "" {
+		manager.SendRequest(interaction, problem, true)
+		return
+	}
+
+	userID := interaction.Member.User.ID
+	existing, err := db.FindSound(interaction.GuildID, name)
+	switch {
+	case err == nil:
+		if existing.UploadedBy != userID && !canManageGuild(interaction.Member) {
+			manager.SendRequest(interaction, tr(interaction, "soundboard.not_yours", existing.Name), true)
+			return
+		}
+	case errors.Is(err, database.ErrSoundNotFound):
+		sounds, err := db.ListSounds(interaction.GuildID)
+		if err != nil {
+			log.Errorf("Failed to list sounds: %v", err)
+			manager.SendError(interaction, tr(interaction, "soundboard.save_failed"), true)
+			return
+		}
+		if len(sounds) >= maxSounds {
+			manager.SendRequest(interaction, tr(interaction, "soundboard.full", maxSounds), true)
+			return
+		}
+	default:
+		log.Errorf("Failed to look up sound: %v", err)
+		manager.SendError(interaction, tr(interaction, "soundboard.save_failed"), true)
+		return
+	}
+
+	data, err := downloadAttachment(ctx, interaction, attachmentID, maxSoundFileBytes)
+	if err != nil {
+		log.Warnf("Sound upload failed: %v", err)
+		manager.SendRequest(interaction, tr(interaction, "soundboard.download_failed", err, maxSoundFileBytes>>20), true)
+		return
+	}
+	samples, err := audio.ConvertSound(data)
+	if errors.Is(err, audio.ErrSoundTooLong) {
+		manager.SendRequest(interaction, tr(interaction, "soundboard.too_long", int(audio.MaxSoundLength.Seconds())), true)
+		return
+	}
+	if err != nil {
+		log.Warnf("Sound conversion failed: %v", err)
+		manager.SendRequest(interaction, tr(interaction, "soundboard.not_audio"), true)
+		return
+	}
+
+	sound, replaced, err := db.SaveSound(interaction.GuildID, name, userID, audio.SoundLength(samples), samples)
+	if err != nil {
+		log.Errorf("Failed to save sound: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendError(interaction, tr(interaction, "soundboard.save_failed"), true)
+		return
+	}
+	key := "soundboard.added"
+	if replaced {
+		key = "soundboard.replaced"
+	}
+	manager.SendRequest(interaction, tr(interaction, key, sound.Name, sound.Duration.Seconds()), false)
+}
+
+// onSoundPlay plays a sound in the bot's voice channel. Only members in
+// that channel can play one.
+func (manager *Manager) onSoundPlay(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onSoundPlay: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if player.DB == nil {
+		manager.SendRequest(interaction, tr(interaction, "common.db_unavailable"), true)
+		return
+	}
+	botChannel := player.GetVoiceChannelID()
+	if botChannel == nil || *botChannel == "" {
+		manager.SendRequest(interaction, tr(interaction, "soundboard.not_in_voice"), true)
+		return
+	}
+	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
+	if err != nil || voiceState == nil || voiceState.ChannelID != *botChannel {
+		manager.SendRequest(interaction, tr(interaction, "soundboard.join_channel", *botChannel), true)
+		return
+	}
+
+	var name string
+	for _, opt := range commandOptions(interaction) {
+		if opt.Name == "name" {
+			name = opt.Value
+		}
+	}
+	sound, err := player.DB.FindSound(interaction.GuildID, name)
+	if errors.Is(err, database.ErrSoundNotFound) {
+		manager.SendRequest(interaction, tr(interaction, "soundboard.not_found", strings.TrimSpace(name)), true)
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to look up sound: %v", err)
+		manager.SendError(interaction, tr(interaction, "soundboard.play_failed"), true)
+		return
+	}
+
+	switch err := player.PlaySound(sound); {
+	case err == nil:
+		manager.SendRequest(interaction, tr(interaction, "soundboard.played", sound.Name), true)
+	case errors.Is(err, audio.ErrPlayerBusy):
+		manager.SendRequest(interaction, tr(interaction, "soundboard.busy"), true)
+	case errors.Is(err, controller.ErrNotInVoice):
+		manager.SendRequest(interaction, tr(interaction, "soundboard.disconnected"), true)
+	default:
+		log.Errorf("Failed to play sound: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendError(interaction, tr(interaction, "soundboard.play_failed"), true)
+	}
+}
+
+// soundNameProblem explains what's wrong with a sound name, or returns ""
+// when it's fine.
+func soundNameProblem(interaction *Interaction, name string) string {
+	switch {
+	case name == "":
+		return tr(interaction, "soundboard.name_missing")
+	case utf8.RuneCountInString(name) > soundNameLimit:
+		return tr(interaction, "soundboard.name_too_long", soundNameLimit)
+	case strings.ContainsAny(name, "\n\r`*_~|"):
+		return tr(interaction, "soundboard.name_formatting")
+	}
+	return ""
+}
+
+// soundChoices lists the server's sounds whose names contain typed.
+func soundChoices(db *database.Database, guildID, typed string) []*discordgo.ApplicationCommandOptionChoice {
+	if db == nil {
+		return nil
+	}
+	sounds, err := db.ListSounds(guildID)
+	if err != nil {
+		log.Warnf("Failed to list sounds for autocomplete: %v", err)
+		return nil
+	}
+	typed = strings.ToLower(typed)
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, s := range sounds {
+		if !strings.Contains(strings.ToLower(s.Name), typed) {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("%s (%.1fs)", s.Name, s.Duration.Seconds()),
+			Value: s.Name,
+		})
+		if len(choices) == autocompleteLimit {
+			break
+		}
+	}
+	return choices
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/database"
+)
+
+func TestSoundNameProblem(t *testing.T) {
+	for _, name := range []string{"airhorn", "Sad Trombone", "🎺 bruh"} {
+		if problem := soundNameProblem(&Interaction{}, name); problem != "" {
+			t.Errorf("soundNameProblem(%q) = %q, want ok", name, problem)
+		}
+	}
+	for _, name := range []string{"", strings.Repeat("a", soundNameLimit+1), "two\nlines", "**bold**"} {
+		if soundNameProblem(&Interaction{}, name) == "" {
+			t.Errorf("soundNameProblem(%q) = ok, want a problem", name)
+		}
+	}
+}
+
+func TestSoundChoices(t *testing.T) {
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := database.New()
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	defer db.Close()
+
+	for _, name := range []string{"airhorn", "rimshot", "Air raid"} {
+		if _, _, err := db.SaveSound("g1", name, "u1", 1500*time.Millisecond, []int16{1, 2}); err != nil {
+			t.Fatalf("SaveSound: %v", err)
+		}
+	}
+
+	choices := soundChoices(db, "g1", "AIR")
+	if len(choices) != 2 || choices[0].Value != "Air raid" || choices[1].Value != "airhorn" {
+		t.Fatalf("soundChoices(AIR) = %+v; want Air raid, airhorn", choices)
+	}
+	if choices[1].Name != "airhorn (1.5s)" {
+		t.Errorf("choice name = %q, want %q", choices[1].Name, "airhorn (1.5s)")
+	}
+	if got := soundChoices(db, "g2", ""); len(got) != 0 {
+		t.Errorf("other guild = %+v, want none", got)
+	}
+	if got := soundChoices(nil, "g1", ""); got != nil {
+		t.Errorf("no database = %+v, want nil", got)
+	}
+}
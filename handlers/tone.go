@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sentry "github.com/getsentry/sentry-go"
+
+	"beatbot/config"
+	"beatbot/gemini"
+	"beatbot/sentryhelper"
+)
+
+// handleTone routes /tone: set, show or reset the guild's AI style notes,
+// the same setting the /settings panel edits. Setting a style previews it
+// in the background, so async reports whether a goroutine will finish
+// transaction.
+func (manager *Manager) handleTone(ctx context.Context, transaction *sentry.Span, interaction *Interaction) (response Response, async bool) {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+
+	switch subcommandName(interaction) {
+	case "show":
+		return Response{Type: 4, Data: ResponseData{Content: toneSummary(interaction, player.GetAIStyle()), Flags: 64}}, false
+	case "reset":
+		if refusal, denied := settingsDenied(interaction); denied {
+			return refusal, false
+		}
+		saved := saveGuildSetting(player, "ai_style", "")
+		player.SetAIStyle("")
+		return Response{Type: 4, Data: ResponseData{Content: toneSaved(interaction, tr(interaction, "tone.reset"), saved), Flags: 64}}, false
+	case "set":
+		if refusal, denied := settingsDenied(interaction); denied {
+			return refusal, false
+		}
+		var style string
+		for _, opt := range commandOptions(interaction) {
+			if opt.Name == "style" {
+				style = strings.TrimSpace(opt.Value)
+			}
+		}
+		if style == "" {
+			return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "tone.style_missing"), Flags: 64}}, false
+		}
+		if problem := gemini.CheckGuildStyle(style); problem != "" {
+			return Response{Type: 4, Data: ResponseData{Content: problem, Flags: 64}}, false
+		}
+		saved := saveGuildSetting(player, "ai_style", style)
+		player.SetAIStyle(style)
+		if !config.Config.Gemini.Enabled {
+			return Response{Type: 4, Data: ResponseData{Content: toneSaved(interaction, toneSummary(interaction, style), saved), Flags: 64}}, false
+		}
+		go manager.previewTone(ctx, transaction, interaction, style, saved)
+		return Response{Type: 5, Data: ResponseData{Flags: 64}}, true
+	}
+	return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "tone.unknown_subcommand"), Flags: 64}}, false
+}
+
+// previewTone follows up a /tone set with a sample line in the new style.
+func (manager *Manager) previewTone(ctx context.Context, transaction *sentry.Span, interaction *Interaction, style string, saved bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in previewTone: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	content := toneSummary(interaction, style)
+	if preview := gemini.PreviewGuildStyle(ctx, style); preview != "" {
+		content += "\n\n" + tr(interaction, "tone.preview") + "\n> " + strings.ReplaceAll(preview, "\n", "\n> ")
+	} else {
+		content += "\n\n" + tr(interaction, "tone.preview_failed")
+	}
+	manager.SendFollowup(ctx, interaction, "", toneSaved(interaction, content, saved), true)
+}
+
+// toneSummary describes the guild's current AI style.
+func toneSummary(interaction *Interaction, style string) string {
+	if style == "" {
+		return tr(interaction, "tone.default")
+	}
+	return tr(interaction, "tone.summary", len([]rune(style)), gemini.MaxGuildStyleLength, style)
+}
+
+// toneSaved adds a warning to content when the change couldn't be saved.
+func toneSaved(interaction *Interaction, content string, saved bool) string {
+	if !saved {
+		content += "\n\n" + tr(interaction, "settings.not_saved")
+	}
+	return content
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToneSummary(t *testing.T) {
+	if got := toneSummary(&Interaction{}, ""); !strings.Contains(got, "default personality") {
+		t.Errorf("toneSummary(\"\") = %q, want the default personality", got)
+	}
+	got := toneSummary(&Interaction{}, "extra dry")
+	if !strings.Contains(got, "“extra dry”") || !strings.Contains(got, "(9/300 characters)") {
+		t.Errorf("toneSummary = %q", got)
+	}
+}
+
+func TestToneSaved(t *testing.T) {
+	if got := toneSaved(&Interaction{}, "done", true); got != "done" {
+		t.Errorf("toneSaved(saved) = %q, want unchanged", got)
+	}
+	if got := toneSaved(&Interaction{}, "done", false); !strings.Contains(got, "Couldn't save") {
+		t.Errorf("toneSaved(unsaved) = %q, want a warning", got)
+	}
+}
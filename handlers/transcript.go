@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/database"
+	"beatbot/discord"
+	"beatbot/i18n"
+	"beatbot/sentryhelper"
+)
+
+const (
+	// sessionGap is how long the bot can go without playing anything before
+	// the next song counts as a new listening session.
+	sessionGap = 45 * time.Minute
+	// transcriptLookback bounds how far back /transcript searches for the
+	// start of the current session.
+	transcriptLookback = 24 * time.Hour
+	maxTranscriptPlays = 500
+	// Leave room under Discord's 2000 character limit for the header.
+	maxTranscriptMessage = 1900
+)
+
+// currentSession returns the trailing run of plays (oldest first) with no
+// gap longer than sessionGap between one song ending and the next starting.
+func currentSession(records []database.SongHistoryRecord) []database.SongHistoryRecord {
+	if len(records) == 0 {
+		return nil
+	}
+	start := len(records) - 1
+	for start > 0 {
+		prev := records[start-1]
+		prevEnd := prev.PlayedAt.Add(time.Duration(prev.DurationSeconds) * time.Second)
+		if records[start].PlayedAt.Sub(prevEnd) > sessionGap {
+			break
+		}
+		start--
+	}
+	return records[start:]
+}
+
+// transcriptRequester returns the display name for a play's requester.
+func transcriptRequester(db *database.Database, locale, guildID string, r database.SongHistoryRecord) string {
+	if r.RequestedByUsername != "" {
+		return r.RequestedByUsername
+	}
+	if r.RequestedByUserID != "" {
+		return db.GetOrFetchUsername(guildID, r.RequestedByUserID)
+	}
+	return i18n.T(locale, "transcript.radio")
+}
+
+// formatTranscriptMessage renders the session for chat, using Discord
+// timestamps so every reader sees their own timezone.
+func formatTranscriptMessage(locale string, plays []database.SongHistoryRecord, requesters []string) string {
+	var sb strings.Builder
+	first, last := plays[0].PlayedAt, plays[len(plays)-1].PlayedAt
+	sb.WriteString(i18n.T(locale, "transcript.header", first.Unix(), last.Unix(), len(plays)) + "\n\n")
+	for i, r := range plays {
+		sb.WriteString(fmt.Sprintf("`<t:%d:t>` **%d.** %s — %s\n", r.PlayedAt.Unix(), i+1, r.Title, requesters[i]))
+	}
+	return sb.String()
+}
+
+// formatTranscriptMarkdown renders the session as a standalone markdown
+// document for archiving.
+func formatTranscriptMarkdown(locale string, plays []database.SongHistoryRecord, requesters []string) string {
+	var sb strings.Builder
+	first := plays[0].PlayedAt.UTC()
+	sb.WriteString(i18n.T(locale, "transcript.md_title", first.Format("Mon Jan 2, 2006")) + "\n\n")
+	sb.WriteString(i18n.T(locale, "transcript.md_summary", len(plays), first.Format("15:04"), plays[len(plays)-1].PlayedAt.UTC().Format("15:04")) + "\n\n")
+	sb.WriteString(i18n.T(locale, "transcript.md_columns") + "\n|---|---|---|---|\n")
+	for i, r := range plays {
+		title := strings.ReplaceAll(r.Title, "|", "\\|")
+		if r.URL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, r.URL)
+		}
+		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s |\n", i+1, r.PlayedAt.UTC().Format("15:04"), title, strings.ReplaceAll(requesters[i], "|", "\\|")))
+	}
+	return sb.String()
+}
+
+func (manager *Manager) handleTranscript(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in handleTranscript: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	db := manager.Controller.GetDB()
+	if db == nil {
+		manager.SendRequest(interaction, tr(interaction, "common.db_unavailable"), true)
+		return
+	}
+
+	asFile := false
+	for _, opt := range interaction.Data.Options {
+		if opt.Name == "file" {
+			asFile = opt.Value == "true"
+		}
+	}
+
+	records, err := db.GetHistorySince(interaction.GuildID, time.Now().Add(-transcriptLookback), maxTranscriptPlays)
+	if err != nil {
+		log.Errorf("Error fetching history for transcript: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendRequest(interaction, tr(interaction, "history.fetch_failed"), true)
+		return
+	}
+
+	plays := currentSession(records)
+	if len(plays) == 0 {
+		manager.SendRequest(interaction, tr(interaction, "transcript.empty"), true)
+		return
+	}
+
+	requesters := make([]string, len(plays))
+	for i, r := range plays {
+		requesters[i] = transcriptRequester(db, interaction.GuildLocale, interaction.GuildID, r)
+	}
+
+	if !asFile {
+		if msg := formatTranscriptMessage(interaction.GuildLocale, plays, requesters); len(msg) <= maxTranscriptMessage {
+			manager.SendRequest(interaction, msg, false)
+			return
+		}
+	}
+
+	filename := fmt.Sprintf("session-%s.md", plays[0].PlayedAt.UTC().Format("2006-01-02-1504"))
+	err = discord.SendFollowupWithFile(&discord.FollowUpRequest{
+		Token:   interaction.Token,
+		AppID:   manager.AppID,
+		Content: i18n.T(interaction.GuildLocale, "transcript.file_header", len(plays), plays[0].PlayedAt.Unix()),
+	}, filename, []byte(formatTranscriptMarkdown(interaction.GuildLocale, plays, requesters)))
+	if err != nil {
+		log.Errorf("Error sending transcript: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/database"
+)
+
+func TestCurrentSession(t *testing.T) {
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	play := func(id string, at time.Duration, seconds int) database.SongHistoryRecord {
+		return database.SongHistoryRecord{VideoID: id, PlayedAt: base.Add(at), DurationSeconds: seconds}
+	}
+
+	records := []database.SongHistoryRecord{
+		play("old", 0, 200),
+		play("a", 2*time.Hour, 0),
+		play("b", 2*time.Hour+4*time.Minute, 0),
+		// a 50 minute track followed by a 20 minute break is still one session
+		play("long", 2*time.Hour+40*time.Minute, 50*60),
+		play("c", 3*time.Hour+50*time.Minute, 0),
+	}
+
+	session := currentSession(records)
+	if len(session) != 4 || session[0].VideoID != "a" || session[3].VideoID != "c" {
+		var ids []string
+		for _, r := range session {
+			ids = append(ids, r.VideoID)
+		}
+		t.Errorf("currentSession() = %v, want [a b long c]", ids)
+	}
+
+	if got := currentSession(nil); got != nil {
+		t.Errorf("currentSession(nil) = %v, want nil", got)
+	}
+}
+
+func TestFormatTranscriptMarkdown(t *testing.T) {
+	at := time.Date(2026, 3, 1, 20, 5, 0, 0, time.UTC)
+	plays := []database.SongHistoryRecord{
+		{Title: "A | B", URL: "https://www.youtube.com/watch?v=x", PlayedAt: at},
+		{Title: "Radio Song", PlayedAt: at.Add(4 * time.Minute)},
+	}
+
+	md := formatTranscriptMarkdown("", plays, []string{"alice", "radio"})
+	for _, want := range []string{
+		"# Listening session — Sun Mar 1, 2026",
+		"2 songs, 20:05 – 20:09 UTC",
+		"| 1 | 20:05 | [A \\| B](https://www.youtube.com/watch?v=x) | alice |",
+		"| 2 | 20:09 | Radio Song | radio |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
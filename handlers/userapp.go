@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/discord"
+	"beatbot/lyrics"
+	"beatbot/resolver"
+	"beatbot/sentryhelper"
+)
+
+// userAppCommands can be used wherever a user has installed the bot for
+// themselves: DMs, group DMs, and servers the bot isn't in. None of them need
+// a voice channel; the rest only answer in servers the bot is in.
+var userAppCommands = map[string]bool{
+	"ping":        true,
+	"help":        true,
+	"lyrics":      true,
+	"search":      true,
+	"stats":       true,
+	"preferences": true,
+	"forgetme":    true,
+}
+
+// init opens userAppCommands to user installs and every chat they can be
+// used from.
+func init() {
+	for _, cmd := range Commands {
+		if !userAppCommands[cmd.Name] {
+			continue
+		}
+		cmd.IntegrationTypes = &[]discordgo.ApplicationIntegrationType{
+			discordgo.ApplicationIntegrationGuildInstall,
+			discordgo.ApplicationIntegrationUserInstall,
+		}
+		cmd.Contexts = &[]discordgo.InteractionContextType{
+			discordgo.InteractionContextGuild,
+			discordgo.InteractionContextBotDM,
+			discordgo.InteractionContextPrivateChannel,
+		}
+	}
+}
+
+// guildInstallOwner is the authorizing_integration_owners key for a server
+// install; its value is the server's ID.
+const guildInstallOwner = "0"
+
+// adoptUser fills in Member.User from the top-level user Discord sends
+// instead of a member outside servers, so handlers can read the invoker
+// the same way everywhere.
+func (i *Interaction) adoptUser() {
+	if i.Member.User.ID == "" && i.User != nil {
+		i.Member.User = *i.User
+	}
+}
+
+// inBotGuild reports whether the interaction came from a server the bot is
+// installed in, rather than a DM or a server where only the user installed
+// it. Payloads without installation owners predate user installs and only
+// come from servers the bot is in.
+func (i *Interaction) inBotGuild() bool {
+	if i.GuildID == "" {
+		return false
+	}
+	if len(i.AuthorizingIntegrationOwners) == 0 {
+		return true
+	}
+	return i.AuthorizingIntegrationOwners[guildInstallOwner] == i.GuildID
+}
+
+// userAppRefusal answers commands that need the bot in the server.
+func userAppRefusal(interaction *Interaction) Response {
+	return Response{Type: 4, Data: ResponseData{
+		Content: tr(interaction, "userapp.needs_server"),
+		Flags:   64,
+	}}
+}
+
+// onUserAppSearch lists results as links for /search outside the bot's
+// servers, where there's no queue to pick into.
+func (manager *Manager) onUserAppSearch(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onUserAppSearch: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	var query string
+	source := resolver.SourceAuto
+	for _, opt := range commandOptions(interaction) {
+		switch opt.Name {
+		case "query":
+			query = opt.Value
+		case "source":
+			source = opt.Value
+		}
+	}
+	if query == "" {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "userapp.query_missing"), true)
+		return
+	}
+
+	videos, used, err := resolver.Search(ctx, query, source)
+	if err != nil {
+		log.Errorf("Error resolving query %q (source %q): %v", query, source, err)
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.search_failed", err), true)
+		return
+	}
+	if len(videos) == 0 {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "playlists.nothing_found", query), true)
+		return
+	}
+	if len(videos) > searchChoices {
+		videos = videos[:searchChoices]
+	}
+
+	var sb strings.Builder
+	for i, video := range videos {
+		sb.WriteString(fmt.Sprintf("**%d.** [%s](%s)", i+1, video.Title, video.PageURL()))
+		if video.ChannelName != "" {
+			sb.WriteString(" · " + video.ChannelName)
+		}
+		if video.Duration > 0 {
+			sb.WriteString(" · " + discord.FormatDuration(video.Duration))
+		}
+		sb.WriteString("\n")
+	}
+	title := "🔎 " + query
+	if used != nil && used.Name() != resolver.SourceYouTube {
+		title = tr(interaction, "userapp.results_on", query, used.DisplayName())
+	}
+	manager.sendEmbedFollowup(interaction, &discordgo.MessageEmbed{
+		Title:       truncateRunes(title, 256),
+		Description: sb.String(),
+		Color:       0x7289DA,
+		Footer:      &discordgo.MessageEmbedFooter{Text: tr(interaction, "userapp.add_me")},
+	}, true)
+}
+
+// lyricsSongOption is the song /lyrics was asked to look up by name, which
+// outside the bot's servers is the only way to name one.
+func lyricsSongOption(interaction *Interaction) string {
+	for _, opt := range commandOptions(interaction) {
+		if opt.Name == "song" {
+			return strings.TrimSpace(opt.Value)
+		}
+	}
+	return ""
+}
+
+// sendLyricsFor looks up lyrics by name and sends them as pages.
+func (manager *Manager) sendLyricsFor(ctx context.Context, interaction *Interaction, query string) {
+	result, err := lyrics.New().Find(query)
+	if err != nil {
+		log.Warnf("Lyrics search for %q failed: %v", query, err)
+	}
+	if result == nil || result.Plain() == "" {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "lyrics.not_found", query), false)
+		return
+	}
+	manager.sendLyricsPages(interaction, result)
+}
+
+// handleUserStats shows the invoker's own listening across every server,
+// for /stats where there's no server of the bot's to report on.
+func (manager *Manager) handleUserStats(interaction *Interaction) Response {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "common.db_unavailable"), Flags: 64}}
+	}
+
+	stats, err := db.GetUserStats(interaction.Member.User.ID)
+	if err != nil {
+		log.Errorf("Error fetching user stats: %v", err)
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "stats.fetch_failed"), Flags: 64}}
+	}
+	if stats.SongsPlayed == 0 {
+		return Response{Type: 4, Data: ResponseData{Content: tr(interaction, "userapp.stats_empty"), Flags: 64}}
+	}
+
+	skipRate := 100 * stats.Skips / stats.SongsPlayed
+	servers := tr(interaction, "userapp.server")
+	if stats.Servers != 1 {
+		servers = tr(interaction, "userapp.servers")
+	}
+	return Response{Type: 4, Data: ResponseData{Flags: 64, Embeds: []*discordgo.MessageEmbed{{
+		Title: tr(interaction, "userapp.stats_title"),
+		Color: 0x1DB954,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: tr(interaction, "userapp.songs_requested"), Value: strconv.Itoa(stats.SongsPlayed), Inline: true},
+			{Name: tr(interaction, "stats.unique_songs"), Value: strconv.Itoa(stats.UniqueSongs), Inline: true},
+			{Name: tr(interaction, "stats.listening_time"), Value: formatPlaytime(stats.Playtime), Inline: true},
+			{Name: tr(interaction, "stats.skips"), Value: fmt.Sprintf("%d (%d%%)", stats.Skips, skipRate), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: tr(interaction, "userapp.across", stats.Servers, servers)},
+	}}}}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestInteractionInBotGuild(t *testing.T) {
+	tests := []struct {
+		name   string
+		guild  string
+		owners map[string]string
+		want   bool
+	}{
+		{"dm", "", map[string]string{"1": "u1"}, false},
+		{"server install", "g1", map[string]string{"0": "g1"}, true},
+		{"both installs", "g1", map[string]string{"0": "g1", "1": "u1"}, true},
+		{"user install only", "g1", map[string]string{"1": "u1"}, false},
+		{"no owners", "g1", nil, true},
+	}
+	for _, tt := range tests {
+		interaction := &Interaction{GuildID: tt.guild, AuthorizingIntegrationOwners: tt.owners}
+		if got := interaction.inBotGuild(); got != tt.want {
+			t.Errorf("%s: inBotGuild = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAdoptUserFromDM(t *testing.T) {
+	raw := `{"type": 2, "context": 1, "user": {"id": "u1", "username": "alice"},
+		"authorizing_integration_owners": {"1": "u1"}, "data": {"name": "lyrics"}}`
+	var interaction Interaction
+	if err := json.Unmarshal([]byte(raw), &interaction); err != nil {
+		t.Fatal(err)
+	}
+	interaction.adoptUser()
+	if interaction.Member.User.ID != "u1" || interaction.Member.User.Username != "alice" {
+		t.Errorf("member user = %+v, want u1/alice", interaction.Member.User)
+	}
+	if interaction.inBotGuild() {
+		t.Error("a DM isn't in the bot's server")
+	}
+}
+
+func TestUserAppRefusesServerCommands(t *testing.T) {
+	manager := &Manager{}
+	response := manager.HandleInteraction(&Interaction{
+		Type: 2,
+		User: &UserData{ID: "u1"},
+		Data: InteractionData{Name: "play"},
+	})
+	if response.Type != 4 || response.Data.Flags != 64 {
+		t.Errorf("response = %+v, want an ephemeral refusal", response)
+	}
+}
+
+func TestUserAppCommandsRegistered(t *testing.T) {
+	for _, cmd := range Commands {
+		opened := cmd.IntegrationTypes != nil
+		if opened != userAppCommands[cmd.Name] {
+			t.Errorf("%s: open to user installs = %v, want %v", cmd.Name, opened, userAppCommands[cmd.Name])
+			continue
+		}
+		if opened && !containsInstall(*cmd.IntegrationTypes, discordgo.ApplicationIntegrationUserInstall) {
+			t.Errorf("%s: missing the user install type", cmd.Name)
+		}
+	}
+}
+
+func containsInstall(types []discordgo.ApplicationIntegrationType, want discordgo.ApplicationIntegrationType) bool {
+	for _, t := range types {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/controller"
+	"beatbot/database"
+	"beatbot/discord"
+	"beatbot/sentryhelper"
+	"beatbot/youtube"
+)
+
+// savedQueueSetting is the guild setting /disconnect stores the queue under,
+// in the same JSON format as /queue export.
+const savedQueueSetting = "saved_queue"
+
+// saveQueue stores tracks for /summon restore. An empty list clears it.
+func saveQueue(db *database.Database, guildID string, tracks []QueueExportTrack) error {
+	if len(tracks) == 0 {
+		return db.SetGuildSetting(guildID, savedQueueSetting, "")
+	}
+	data, err := json.Marshal(QueueExport{
+		Version:    queueExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Tracks:     tracks,
+	})
+	if err != nil {
+		return err
+	}
+	return db.SetGuildSetting(guildID, savedQueueSetting, string(data))
+}
+
+// loadSavedQueue returns the tracks saved by the last /disconnect, if any.
+func loadSavedQueue(db *database.Database, guildID string) ([]QueueExportTrack, error) {
+	value, err := db.GetGuildSetting(guildID, savedQueueSetting)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	export, err := parseQueueExport([]byte(value))
+	if err != nil {
+		return nil, err
+	}
+	return export.Tracks, nil
+}
+
+// saveQueueOnLeave saves the queue when the player leaves voice on its own,
+// and returns how many songs it saved.
+func (manager *Manager) saveQueueOnLeave(player *controller.GuildPlayer) int {
+	db := manager.Controller.GetDB()
+	tracks := queueExportTracks(player)
+	if db == nil || len(tracks) == 0 {
+		return 0
+	}
+	if err := saveQueue(db, player.GuildID, tracks); err != nil {
+		log.Errorf("Error saving queue for guild %s before leaving: %v", player.GuildID, err)
+		sentry.CaptureException(err)
+		return 0
+	}
+	return len(tracks)
+}
+
+// onDisconnect saves the queue (current song first) and leaves voice.
+func (manager *Manager) onDisconnect(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onDisconnect: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	if player.GetVoiceChannelID() == nil {
+		manager.SendRequest(interaction, tr(interaction, "voice.not_connected"), true)
+		return
+	}
+
+	tracks := queueExportTracks(player)
+	saved := false
+	if db := manager.Controller.GetDB(); db != nil && len(tracks) > 0 {
+		if err := saveQueue(db, interaction.GuildID, tracks); err != nil {
+			log.Errorf("Error saving queue on disconnect: %v", err)
+			sentryhelper.CaptureException(ctx, err)
+		} else {
+			saved = true
+		}
+	}
+
+	player.Disconnect()
+
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "voice",
+		Message:  fmt.Sprintf("Disconnected by command, saved %d tracks", len(tracks)),
+		Level:    sentry.LevelInfo,
+	})
+
+	response := tr(interaction, "voice.left")
+	prompt := "The user told the bot to leave the voice channel"
+	if saved {
+		response += " " + tr(interaction, "voice.saved_queue", len(tracks), pluralSongs(interaction, len(tracks)))
+		prompt += fmt.Sprintf(". The %d queued songs were saved and can be brought back with /summon restore:True", len(tracks))
+	}
+	manager.SendFollowup(ctx, interaction, prompt, response, false)
+}
+
+// handleFollow toggles moving with the active requester when they switch
+// voice channels.
+func (manager *Manager) handleFollow(interaction *Interaction) Response {
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	enabled := !player.GetFollowRequester()
+	player.SetFollowRequester(enabled)
+
+	if player.DB != nil {
+		if err := player.DB.SetGuildSetting(interaction.GuildID, "follow_requester", strconv.FormatBool(enabled)); err != nil {
+			log.Errorf("Failed to save follow setting: %v", err)
+		}
+	}
+
+	msg := "📍 Follow **disabled** — I'll stay in my channel until someone queues from another one while I'm stopped"
+	if enabled {
+		msg = "🎧 Follow **enabled** — when whoever queued the song switches voice channels, I'll go with them, as long as nobody's left listening where I am"
+	}
+	return Response{
+		Type: 4,
+		Data: ResponseData{
+			Content: msg,
+		},
+	}
+}
+
+// onSummon joins (or moves to) the caller's voice channel without queueing
+// anything, unless restore asks for the queue saved by /disconnect.
+func (manager *Manager) onSummon(ctx context.Context, transaction *sentry.Span, interaction *Interaction) {
+	defer func() {
+		if err := recover(); err != nil {
+			sentryhelper.CaptureException(ctx, fmt.Errorf("panic in onSummon: %v", err))
+			transaction.Status = sentry.SpanStatusInternalError
+		}
+		transaction.Finish()
+	}()
+
+	restore := false
+	for _, opt := range commandOptions(interaction) {
+		if opt.Name == "restore" {
+			restore = opt.Value == "true"
+		}
+	}
+
+	voiceState, err := discord.GetMemberVoiceState(&interaction.Member.User.ID, &interaction.GuildID)
+	if err != nil {
+		log.Errorf("Error getting voice state: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		manager.SendError(interaction, tr(interaction, "common.voice_state_error", err.Error()), true)
+		return
+	}
+	if voiceState == nil {
+		manager.SendFollowup(ctx, interaction, "", tr(interaction, "voice.summon_first"), true)
+		return
+	}
+
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	response := ""
+	if player.ShouldJoinVoice(voiceState.ChannelID) {
+		if err := player.JoinVoiceChannel(interaction.Member.User.ID); err != nil {
+			sentryhelper.CaptureException(ctx, err)
+			manager.SendError(interaction, tr(interaction, "common.join_voice_error", err.Error()), true)
+			return
+		}
+		response = tr(interaction, "voice.joined", voiceState.ChannelID)
+	} else if manager.refuseBusy(interaction, player, voiceState.ChannelID) {
+		return
+	} else {
+		response = tr(interaction, "voice.already_here")
+	}
+
+	if restore {
+		response += " " + manager.restoreSavedQueue(ctx, interaction)
+	}
+	manager.SendFollowup(ctx, interaction, "", response, false)
+}
+
+// restoreSavedQueue queues the tracks saved by /disconnect and forgets them,
+// returning a sentence describing what happened.
+func (manager *Manager) restoreSavedQueue(ctx context.Context, interaction *Interaction) string {
+	db := manager.Controller.GetDB()
+	if db == nil {
+		return tr(interaction, "voice.restore_no_db")
+	}
+	tracks, err := loadSavedQueue(db, interaction.GuildID)
+	if err != nil {
+		log.Errorf("Error loading saved queue: %v", err)
+		sentryhelper.CaptureException(ctx, err)
+		return tr(interaction, "voice.restore_failed")
+	}
+	if len(tracks) == 0 {
+		return tr(interaction, "voice.restore_none")
+	}
+
+	videos := make([]youtube.VideoResponse, 0, len(tracks))
+	for _, t := range tracks {
+		videos = append(videos, t.video())
+	}
+	player := manager.Controller.GetPlayer(interaction.GuildID)
+	videosToQueue := dropQueued(player, manager.filterBlocked(interaction.GuildID, videos))
+	for _, video := range videosToQueue {
+		player.Add(ctx, video, interaction.Member.User.ID, interaction.Token, manager.AppID, nil)
+	}
+
+	if err := saveQueue(db, interaction.GuildID, nil); err != nil {
+		log.Errorf("Error clearing saved queue: %v", err)
+	}
+
+	sentryhelper.AddBreadcrumb(ctx, &sentry.Breadcrumb{
+		Category: "queue",
+		Message:  fmt.Sprintf("Restored %d saved tracks", len(videosToQueue)),
+		Level:    sentry.LevelInfo,
+	})
+
+	if len(videosToQueue) == 0 {
+		return tr(interaction, "voice.restore_all_skipped")
+	}
+	return tr(interaction, "voice.restored", len(videosToQueue), pluralSongs(interaction, len(videosToQueue)))
+}
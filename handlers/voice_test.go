@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"beatbot/database"
+)
+
+func TestSavedQueueRoundTrip(t *testing.T) {
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := database.New()
+	if err != nil {
+		t.Fatalf("database.New: %v", err)
+	}
+	defer db.Close()
+
+	if tracks, err := loadSavedQueue(db, "g1"); err != nil || len(tracks) != 0 {
+		t.Fatalf("fresh guild = %v, %v; want nothing saved", tracks, err)
+	}
+
+	want := []QueueExportTrack{
+		{VideoID: "abc", Title: "First"},
+		{VideoID: "123", Title: "Second", Source: "soundcloud", URL: "https://soundcloud.com/a/b"},
+	}
+	if err := saveQueue(db, "g1", want); err != nil {
+		t.Fatalf("saveQueue: %v", err)
+	}
+	got, err := loadSavedQueue(db, "g1")
+	if err != nil || len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("loadSavedQueue = %+v, %v; want %+v", got, err, want)
+	}
+
+	if err := saveQueue(db, "g1", nil); err != nil {
+		t.Fatalf("saveQueue(nil): %v", err)
+	}
+	if tracks, _ := loadSavedQueue(db, "g1"); len(tracks) != 0 {
+		t.Errorf("cleared queue still has %d tracks", len(tracks))
+	}
+}
@@ -0,0 +1,32 @@
+package i18n
+
+var german = map[string]string{
+	"common.db_unavailable":    "Die Datenbank ist nicht verfügbar.",
+	"common.voice_state_error": "Fehler beim Abrufen des Sprachstatus: %s",
+	"common.join_voice_error":  "Fehler beim Betreten des Sprachkanals: %s",
+	"common.join_voice_first":  "Tritt zuerst einem Sprachkanal bei! 🎤",
+	"common.nothing_playing":   "Gerade läuft nichts.",
+	"common.queue_empty":       "die Warteschlange ist leer",
+
+	"voice.not_connected": "Ich bin in keinem Sprachkanal.",
+	"voice.left":          "👋 Sprachkanal verlassen.",
+	"voice.summon_first":  "Tritt einem Sprachkanal bei und ruf mich dann.",
+	"voice.joined":        "🎧 <#%s> beigetreten.",
+	"voice.already_here":  "Ich bin schon da.",
+
+	"cooldown.slow_down": "Langsam! Du kannst /%s in %d s wieder nutzen.",
+
+	"help.music":     "Musiksteuerung",
+	"help.queue":     "Warteschlange",
+	"help.favorites": "Favoriten",
+	"help.playlists": "Playlists",
+	"help.discovery": "Radio & Entdecken",
+	"help.stats":     "Verlauf & Statistiken",
+	"help.voice":     "DJ-Stimme",
+	"help.server":    "Servereinstellungen",
+	"help.other":     "Sonstiges",
+
+	"help.message_command": "Apps → %s (Rechtsklick auf eine Nachricht)",
+	"help.managers_only":   "(Serververwaltung)",
+	"help.dj_only":         "(nur DJs)",
+}
@@ -0,0 +1,589 @@
+package i18n
+
+// english is the source catalog. Every key used anywhere must be here.
+var english = map[string]string{
+	"common.db_unavailable":    "Database is not available.",
+	"common.voice_state_error": "Error getting voice state: %s",
+	"common.join_voice_error":  "Error joining voice channel: %s",
+	"common.join_voice_first":  "Join a voice channel first! 🎤",
+	"common.nothing_playing":   "Nothing is currently playing.",
+	"common.queue_empty":       "the queue is empty",
+	"common.and_more":          "…and %d more",
+	"common.canceled_button":   "Canceled",
+	"common.skipped_count":     "(%d already queued or blocked)",
+	"common.song":              "song",
+	"common.songs":             "songs",
+	"common.unknown":           "Unknown",
+	"common.play":              "play",
+	"common.plays":             "plays",
+	"common.and":               "and",
+	"common.yes":               "yes",
+	"common.no":                "no",
+
+	"voice.not_connected":       "I'm not in a voice channel.",
+	"voice.left":                "👋 Left the voice channel.",
+	"voice.summon_first":        "Join a voice channel first, then summon me.",
+	"voice.joined":              "🎧 Joined <#%s>.",
+	"voice.already_here":        "I'm already here.",
+	"voice.saved_queue":         "Saved %d %s — `/summon restore:True` picks up where we left off.",
+	"voice.restore_no_db":       "Database is not available, so there's no saved queue.",
+	"voice.restore_failed":      "Couldn't load the saved queue.",
+	"voice.restore_none":        "There's no saved queue to restore.",
+	"voice.restore_all_skipped": "Everything in the saved queue is already queued or blocked.",
+	"voice.restored":            "Restored **%d** %s from the saved queue.",
+
+	"cooldown.slow_down": "Slow down! You can use /%s again in %ds.",
+
+	"help.music":     "Music Control",
+	"help.queue":     "Queue Management",
+	"help.favorites": "Favorites",
+	"help.playlists": "Playlists",
+	"help.discovery": "Radio & Discovery",
+	"help.stats":     "History & Stats",
+	"help.voice":     "DJ Voice",
+	"help.server":    "Server Settings",
+	"help.other":     "Other",
+
+	"help.message_command": "Apps → %s (right-click a message)",
+	"help.managers_only":   "(server managers)",
+	"help.dj_only":         "(DJs only)",
+
+	"clip.off":                "Clips are off in this server — an admin can turn them on with `/clips`.",
+	"clip.not_in_voice":       "I'm not in a voice channel, so there's nothing to clip.",
+	"clip.join_channel":       "Join <#%s> to clip it.",
+	"clip.nothing_heard":      "Nobody who opted in has said anything lately. Members can opt in with `/clip optin`.",
+	"clip.failed":             "Failed to make the clip.",
+	"clip.posted":             "🎙️ Clip from <#%s>, saved by <@%s>. Only members who opted in with `/clip optin` are in it.",
+	"clip.consent_failed":     "Failed to save that — try again.",
+	"clip.opted_out":          "🔇 You're out of clips in this server. Anything of yours I was holding on to is gone.",
+	"clip.opted_in":           "🎙️ You're in — while clips are on, what you say in my voice channel here can end up in a `/clip` for 30 seconds. `/clip optout` takes it back.",
+	"clip.opted_in_while_off": "Clips are off in this server right now, so nothing is kept until an admin turns them on.",
+	"clip.disabled":           "🔇 Clips **disabled** — I've dropped any audio I was holding and won't listen to the voice channel.",
+	"clip.enabled":            "🎙️ Clips **enabled** — the next time I join voice I'll keep the last 30 seconds of what members say, so `/clip save` can post it. Only members who opt in with `/clip optin` are ever recorded.",
+
+	"forgetme.confirm":        "Really delete your data? This removes your favorites, personal playlists, preferences and scheduled plays in every server, and takes your name off play history and anything you added to shared playlists or soundboards. It can't be undone.\n-# Server audit logs keep the commands you ran for moderators until they expire after 90 days.",
+	"forgetme.confirm_button": "Delete my data",
+	"forgetme.failed":         "❌ Something went wrong deleting your data, so nothing was removed. Please try again later.",
+	"forgetme.failed_button":  "Failed",
+	"forgetme.deleted":        "🗑️ Done — your data has been deleted.",
+	"forgetme.deleted_button": "Deleted",
+	"forgetme.kept":           "Kept your data as is.",
+
+	"soundboard.load_failed":     "Failed to load the soundboard.",
+	"soundboard.empty":           "The soundboard is empty. Add a sound with `/soundboard upload`.",
+	"soundboard.title":           "Soundboard (%d/%d)",
+	"soundboard.footer":          "Play one with /soundboard play",
+	"soundboard.not_yours":       "**%s** is someone else's sound — pick another name.",
+	"soundboard.save_failed":     "Failed to save the sound.",
+	"soundboard.full":            "The soundboard is full (%d sounds). Re-upload one of yours under the same name to replace it.",
+	"soundboard.download_failed": "Couldn't get that file (%v). Sound files can be up to %d MB.",
+	"soundboard.too_long":        "That's too long — sounds can be up to %d seconds.",
+	"soundboard.not_audio":       "Couldn't read any audio from that file — upload an mp3, ogg, wav or similar.",
+	"soundboard.added":           "🔊 **%s** (%.1fs) added to the soundboard. Play it with `/soundboard play`.",
+	"soundboard.replaced":        "🔊 **%s** (%.1fs) replaced on the soundboard. Play it with `/soundboard play`.",
+	"soundboard.not_in_voice":    "I'm not in a voice channel — start something with `/play` or `/summon` me first.",
+	"soundboard.join_channel":    "Join <#%s> to play sounds.",
+	"soundboard.not_found":       "There's no sound called **%s**. See them all with `/soundboard list`.",
+	"soundboard.play_failed":     "Failed to play the sound.",
+	"soundboard.played":          "🔊 Played **%s**.",
+	"soundboard.busy":            "I'm in the middle of an announcement — try again in a moment.",
+	"soundboard.disconnected":    "I'm not connected to voice right now.",
+	"soundboard.name_missing":    "Give the sound a name.",
+	"soundboard.name_too_long":   "Sound names can be up to %d characters.",
+	"soundboard.name_formatting": "Sound names can't have line breaks or formatting characters.",
+
+	"favorites.title":            "❤️ **Your Favorites**",
+	"favorites.pick_hint":        "Pick one below to queue it, or `/playfavorites` to queue them all.",
+	"favorites.pick_placeholder": "Play a favorite",
+	"favorites.fetch_failed":     "Failed to fetch favorites.",
+	"favorites.gone":             "That song isn't in your favorites anymore — run `/favorites` again.",
+	"favorites.blocked":          "**%s** is blocked in this server.",
+	"favorites.none":             "You haven't saved any favorites yet. Use `/favorite` while a song is playing!",
+	"favorites.all_skipped":      "All your favorites are already queued or blocked.",
+	"favorites.queued":           "❤️ Queued **%d** of your favorites.",
+	"favorites.queued_shuffled":  "❤️ Queued **%d** of your favorites in random order.",
+	"favorites.already_saved":    "**%s** is already in your favorites.",
+	"favorites.save_failed":      "Failed to save favorite. Try again.",
+	"favorites.saved":            "❤️ Added **%s** to your favorites.",
+	"favorites.number_missing":   "Please provide a song number from `/favorites`.",
+	"favorites.number_unknown":   "That number isn't in your favorites list.",
+	"favorites.removed":          "Removed **%s** from your favorites.",
+
+	"playlists.owner_server":     "server playlist",
+	"playlists.owner_yours":      "your playlist",
+	"playlists.title":            "📁 **Playlists**",
+	"playlists.yours":            "**Yours**",
+	"playlists.server":           "**Server**",
+	"playlists.empty_list":       "Nothing here yet. Add songs with `/playlist add`.",
+	"playlists.unknown_command":  "Unknown playlist command.",
+	"playlists.name_missing":     "Give the playlist a name.",
+	"playlists.exists":           "There's already a playlist called **%s**.",
+	"playlists.create_failed":    "Failed to create the playlist. Try again.",
+	"playlists.created":          "📁 Created %s **%s**. Add songs with `/playlist add`.",
+	"playlists.invalid_position": "Invalid song number.",
+	"playlists.no_position":      "**%s** doesn't have a song %d.",
+	"playlists.removed":          "Removed **%s** from **%s**.",
+	"playlists.list_failed":      "Failed to fetch playlists.",
+	"playlists.none":             "No playlists yet. Make one with `/playlist create`.",
+	"playlists.fetch_failed":     "Failed to fetch the playlist.",
+	"playlists.not_found":        "No playlist called **%s** — see `/playlist list`.",
+	"playlists.nothing_playing":  "Nothing is playing — give a song to add.",
+	"playlists.link_failed":      "Couldn't open that link: %v",
+	"playlists.single_song_only": "That needs a single song, not a playlist or album — paste a track link or search by name.",
+	"playlists.search_failed":    "Error searching for that song: %v",
+	"playlists.nothing_found":    "Nothing found for %s",
+	"playlists.full":             "**%s** is full (%d songs). Remove some first.",
+	"playlists.add_failed":       "Failed to add the song. Try again.",
+	"playlists.already_added":    "**%s** is already on **%s**.",
+	"playlists.added":            "📁 Added **%s** to **%s**.",
+	"playlists.empty":            "**%s** is empty. Add songs with `/playlist add`.",
+	"playlists.all_skipped":      "Everything on **%s** is already queued or blocked.",
+	"playlists.queued":           "📁 Queued **%d** %s from **%s**.",
+	"playlists.queued_shuffled":  "📁 Queued **%d** %s from **%s** in random order.",
+
+	"queue.query_and_file":         "Give me a query or a file, not both.",
+	"queue.nothing_given":          "Give me something to play — a search, a link, or an audio file.",
+	"queue.nothing_found":          "No videos found for the given query",
+	"queue.blocked":                "**%s** is blocked from playing.",
+	"queue.resolve_failed":         "Couldn't play that: %v",
+	"queue.file_missing":           "Couldn't find that file — try uploading it again.",
+	"queue.file_not_audio":         "**%s** isn't an audio file I can play — upload an mp3, ogg, flac, wav or m4a.",
+	"queue.file_too_big":           "**%s** is too big — audio files can be up to %d MB.",
+	"queue.bad_start":              "Couldn't read the start time `%s` — use something like `1:30` or `90s`.",
+	"queue.bad_end":                "Couldn't read the end time `%s` — use something like `3:45` or `225s`.",
+	"queue.end_before_start":       "The end time has to be after the start time.",
+	"queue.clip_live":              "**%s** is a live stream, so there's no start or end to jump to.",
+	"queue.clip_too_short":         "**%s** is only %s long.",
+	"queue.clip_from":              "(from %s)",
+	"queue.join_voice_first":       "Hey dummy, join a voice channel first",
+	"queue.too_long":               "**%s** is %s long — this server caps songs at %s.",
+	"queue.now_playing":            "Now playing the %s titled: **%s**",
+	"queue.first_song_loading":     "(Playback will start shortly - first song needs to load)",
+	"queue.collection_all_skipped": "Everything from %s is already queued, blocked, or too long for this server.",
+	"queue.collection_queued":      "**Queued %d %s from %s:**",
+	"queue.collection_missing":     "%d couldn't be found on YouTube",
+	"queue.collection_skipped":     "%d already queued, blocked, or too long",
+	"queue.collection_truncated":   "only the first %d of %d were fetched",
+	"queue.collection_link":        "a %s link",
+	"queue.view_empty":             "The queue is empty and nothing is playing",
+	"queue.view_total":             "%d tracks · ~%s total",
+	"queue.view_now_playing":       "Now playing: **%s**",
+	"queue.filter_from":            "from <@%s>",
+	"queue.filter_no_match":        "Nothing in the queue matches %s.",
+	"queue.filter_header":          "🔎 **%d** of %d queued %s match %s",
+	"queue.eta_next":               "up next",
+	"queue.eta":                    "plays in ~%s",
+	"queue.nothing_to_skip":        "Nothing to skip",
+	"queue.skipped":                "@%s skipped **%s**",
+	"queue.skipped_next":           "Now playing **%s**",
+	"queue.skipped_last":           "No more songs in queue",
+	"queue.purged":                 "Queue purged",
+	"queue.nothing_to_clear":       "Nothing to clear!",
+	"queue.clear_confirm":          "Really clear **%d** queued %s? The current song keeps playing.",
+	"queue.clear_confirm_button":   "Clear queue",
+	"queue.undo_hint":              "-# Changed your mind? `/undo` within 60s puts it back.",
+	"queue.already_empty":          "The queue is already empty.",
+	"queue.already_empty_button":   "Nothing to clear",
+	"queue.cleared":                "Queue cleared.",
+	"queue.cleared_button":         "Cleared",
+	"queue.clear_canceled":         "Kept the queue as is.",
+	"queue.reset_confirm":          "Really reset the player? This stops playback and leaves voice.",
+	"queue.reset_confirm_queue":    "Really reset the player? This stops playback and leaves voice, and drops **%d** queued %s.",
+	"queue.reset_confirm_button":   "Reset player",
+	"queue.resetting":              "Resetting the player…",
+	"queue.reset_button":           "Reset",
+	"queue.reset_canceled":         "Left the player alone.",
+	"queue.invalid_index":          "Invalid index",
+	"queue.removed":                "@%s removed **%s**",
+	"queue.bad_range":              "Pick a range between 1 and %d — see `/view` for the queue.",
+	"queue.changed":                "The queue changed — check `/view` and try again.",
+	"queue.removed_range":          "@%s removed %d %s (#%d–#%d)",
+	"queue.starting":               "**%s** is starting right now — use `/skip` once it's playing.",
+	"queue.nothing_to_undo":        "Nothing to undo — `/undo` only works for a minute after `/clear`, `/remove` with a range, or `/shuffle`.",
+	"queue.undid_shuffle":          "@%s undid /%s and put the queue back in its old order",
+	"queue.undid":                  "@%s undid /%s and restored the queue (%d %s)",
+	"queue.nowhere_to_jump":        "the queue is empty, nowhere to jump",
+	"queue.bad_position":           "Pick a position between 1 and %d — see `/view` for the queue.",
+	"queue.jumped":                 "@%s jumped to **%s**",
+	"queue.jumped_moved":           "(%d %s moved to the end of the queue)",
+	"queue.jumped_dropped":         "(%d %s dropped)",
+	"queue.shuffle_empty":          "the queue is empty, nothing to shuffle",
+	"queue.shuffle_one":            "only one song in the queue, nothing to shuffle",
+	"queue.emoji_kept":             "kept",
+	"queue.emoji_stripped":         "stripped",
+	"queue.settings":               "Queue mode is **%s** and emoji in titles are **%s**. Use `/queuesettings mode:fair` to take turns by requester, or `mode:fifo` for first come, first served. Use `strip_emoji:true` to drop emoji from song titles.",
+	"queue.unknown_mode":           "Unknown queue mode — pick `fifo` or `fair`.",
+	"queue.mode_fair":              "📋 Queue mode set to **fair** — requests now take turns by requester",
+	"queue.mode_fifo":              "📋 Queue mode set to **fifo** — first come, first served",
+	"queue.strip_emoji_on":         "✂️ Emoji will be stripped from titles of newly queued songs",
+	"queue.strip_emoji_off":        "✂️ Emoji in song titles will be kept",
+
+	"playback.invalid_volume":        "Invalid volume",
+	"playback.volume":                "Volume %d%%",
+	"playback.nothing_playing":       "Nothing is playing right now",
+	"playback.nothing_playing_lower": "nothing is playing",
+	"playback.card_posted":           "🎶 Posted the now-playing card",
+	"playback.card_song_ended":       "The song ended before I could post the card",
+	"playback.card_no_permission":    "I can't post in this channel — I need Send Messages and Embed Links",
+	"playback.card_failed":           "Couldn't post the now-playing card, try again",
+	"playback.paused":                "@%s paused",
+	"playback.loop_enabled":          "Loop mode **enabled** — %s",
+	"playback.loop_disabled":         "Loop mode **disabled** — %s",
+	"playback.loop_current":          "(current: **%s**)",
+	"playback.radio_join_first":      "📻 Join a voice channel first, then try again.",
+	"playback.radio_join_failed":     "📻 Couldn't join your voice channel: %v",
+	"playback.radio_genre":           "📻 Radio mode **enabled** — genre: *%s* — %s",
+	"playback.radio_artist":          "📻 Radio mode **enabled** — artist: *%s* — %s",
+	"playback.radio_theme":           "📻 Radio mode **enabled** — vibing to *%s* — %s",
+	"playback.radio_enabled":         "📻 Radio mode **enabled** — %s",
+	"playback.radio_needs_history":   "*Queue a few songs first so I have something to go off of.*",
+	"playback.radio_disabled":        "📻 Radio mode **disabled** — %s",
+	"playback.request_empty":         "Tell me what you want to hear! 🎵",
+	"playback.request_no_ideas":      "Couldn't come up with anything for that. Try a different suggestion? 🤔",
+	"playback.request_no_results":    "Found nothing good for that. Try something else? 🎵",
+	"playback.request_header":        "🎧 **DJ Request:** *%s*",
+	"playback.request_radio":         "📻 Radio mode set to this vibe — I'll keep it going.",
+	"playback.unknown_voice":         "Unknown voice **%s**. Available voices: %s",
+	"playback.voice_set":             "🎙️ DJ voice set to **%s**",
+	"playback.voice_set_enabled":     "announcements enabled",
+	"playback.announce_enabled":      "🎙️ Voice announcements **enabled** — %s",
+	"playback.announce_disabled":     "🔇 Voice announcements **disabled** — %s",
+	"playback.translate_unavailable": "Title translation needs Gemini, which isn't enabled on this bot.",
+	"playback.translate_disabled":    "🈚 Title translation **disabled**",
+	"playback.translate_enabled":     "🈯 Title translation **enabled** — foreign-language titles get an English reading in /view and the now-playing card",
+	"playback.demo_script":           "Testing, testing. Your DJ is live and ready to drop some beats.",
+	"playback.tts_unavailable":       "TTS provider not configured.",
+	"playback.tts_failed":            "TTS generation failed: %v",
+	"playback.tts_convert_failed":    "Audio conversion failed: %v",
+	"playback.encoder_failed":        "Error creating audio encoder",
+	"playback.demo_busy":             "Stop the music before previewing a voice",
+	"playback.demo":                  "🎙️ Voice preview (**%s**): *%s*",
+	"playback.voices_header":         "🎙️ **Available TTS Voices** — %s (current: **%s**)",
+	"playback.voices_active":         "active",
+	"playback.voices_footer":         "Use `/announce voice:<name>` to switch",
+
+	"history.fetch_failed": "Failed to fetch history.",
+	"history.empty":        "🎵 No songs played yet!",
+	"history.title":        "🎵 **Recently Played**",
+	"history.line":         "**%d.** %s\n　　↳ requested by **%s** · %s",
+
+	"leaderboard.fetch_failed":  "Failed to fetch leaderboard.",
+	"leaderboard.empty":         "🏆 No songs played yet!",
+	"leaderboard.title":         "🏆 **Most Played Songs**",
+	"leaderboard.line":          "%s %s\n　　↳ **%d** %s · last played %s",
+	"leaderboard.none_skipped":  "⏭️ Nothing's been skipped yet!",
+	"leaderboard.skipped_title": "⏭️ **Most Skipped Songs**",
+	"leaderboard.skipped_line":  "**%d.** %s\n　　↳ skipped **%d** of %d %s",
+	"leaderboard.skipped_at":    " · usually %d%% in",
+
+	"stats.fetch_failed":   "Failed to fetch stats.",
+	"stats.empty":          "📊 No songs played yet!",
+	"stats.title":          "📊 Server Stats",
+	"stats.songs_played":   "Songs played",
+	"stats.unique_songs":   "Unique songs",
+	"stats.listening_time": "Listening time",
+	"stats.skips":          "Skips",
+	"stats.top_requesters": "Top requesters",
+	"stats.skipped_line":   "**%d.** %s · skipped %d×",
+	"stats.most_skipped":   "Most skipped",
+
+	"neverplay.already_blocked": "**%s** is already on the never-play list.",
+	"neverplay.failed":          "Failed to block song. Try again.",
+	"neverplay.blocked":         "🚫 **%s** has been blocked and will never play again.",
+
+	"time.just_now":  "just now",
+	"time.min_ago":   "1 min ago",
+	"time.mins_ago":  "%d mins ago",
+	"time.hour_ago":  "1 hour ago",
+	"time.hours_ago": "%d hours ago",
+	"time.day_ago":   "1 day ago",
+	"time.days_ago":  "%d days ago",
+
+	"dj.unknown_command":         "Unknown djrole command.",
+	"dj.role_save_failed":        "Couldn't save the DJ role, try again in a bit.",
+	"dj.role_cleared":            "🎧 DJ role cleared — everyone's requests are treated equally",
+	"dj.role_set":                "🎧 DJ role set to <@&%s> — their requests now jump ahead of regular ones",
+	"dj.role_set_restricted":     ", and only they and server managers can use %s",
+	"dj.role_cleared_restricted": ". %s stay limited to server managers",
+	"dj.not_restrictable":        "That command can't be restricted.",
+	"dj.restrict_save_failed":    "Couldn't save that, try again in a bit.",
+	"dj.unrestricted":            "🎧 Everyone can use skip, clear, reset and volume again",
+	"dj.restricted_managers":     "🎧 %s now limited to server managers — set a DJ role with `/djrole set` to let DJs use them too",
+	"dj.restricted":              "🎧 %s now limited to <@&%s> and server managers",
+	"dj.managers_only":           "Only server managers can use /%s here.",
+	"dj.djs_only":                "Only <@&%s> and server managers can use /%s here.",
+	"dj.guest_not_allowed":       "Only DJs or server managers can hand out guest DJ sessions.",
+	"dj.guest_no_user":           "Pick someone to hand the decks to.",
+	"dj.guest_not_guest":         "<@%s> isn't a guest DJ right now.",
+	"dj.guest_revoked":           "🎧 <@%s>'s guest DJ session has ended.",
+	"dj.guest_bad_duration":      "Duration should look like `30m`, `1h` or `1h30m` (at least a minute), or `off` to end a session.",
+	"dj.guest_expired":           "🎧 <@%s>'s guest DJ session is over — thanks for spinning!",
+	"dj.guest_granted":           "🎧 <@%s> is guest DJ for the next **%s** (until <t:%d:t>) — skips, queue reordering and volume are all theirs.",
+
+	"search.live":            "🔴 Live",
+	"search.results":         "Top results for **%s** — pick one to queue:",
+	"search.placeholder":     "Choose a song",
+	"search.expired":         "That search expired or isn't yours — run `/search` again.",
+	"search.bad_pick":        "That pick didn't match any result — run `/search` again.",
+	"search.bad_pick_button": "Nothing queued",
+	"search.picked":          "Picked **%s**",
+	"search.picked_button":   "Queued",
+
+	"settings.source_auto":           "Search YouTube, then SoundCloud",
+	"settings.source_youtube":        "Search YouTube only",
+	"settings.source_soundcloud":     "Search SoundCloud",
+	"settings.source_spotify":        "Search Spotify, play the YouTube match",
+	"settings.style_default":         "_default personality_",
+	"settings.volume_default":        "100 (default)",
+	"settings.no_limit":              "no limit",
+	"settings.minutes":               "%d min",
+	"settings.channel_default":       "wherever the bot was last used",
+	"settings.bot_default":           "bot default",
+	"settings.replies_public":        "everyone in the channel",
+	"settings.replies_quiet":         "only whoever asked",
+	"settings.panel":                 "⚙️ **Server settings**\n**AI style:** %s\n**Default volume:** %s\n**Max song length:** %s\n**Announce channel:** %s\n**Must be in my voice channel to control playback:** %s\n**AI confirmations & tips shown to:** %s\n**/play searches:** %s",
+	"settings.channel_placeholder":   "Announce channel (clear to unbind)",
+	"settings.voice_on":              "Require listeners to be in my voice channel",
+	"settings.voice_off":             "Let anyone control playback",
+	"settings.voice_default":         "Use the bot default",
+	"settings.responses_public":      "Post AI confirmations & tips in the channel",
+	"settings.responses_quiet":       "Show AI confirmations only to whoever asked",
+	"settings.voice_placeholder":     "Voice channel requirement",
+	"settings.responses_placeholder": "Who sees AI confirmations",
+	"settings.source_placeholder":    "Where /play searches",
+	"settings.edit_button":           "Edit AI style, volume & max length",
+	"settings.modal_title":           "Server settings",
+	"settings.style_label":           "AI style (blank for the default personality)",
+	"settings.style_placeholder":     "e.g. keep it wholesome, lots of 80s references",
+	"settings.volume_label":          "Default volume, 0-%d (blank for 100)",
+	"settings.max_length_label":      "Max song length in minutes (blank for none)",
+	"settings.bad_volume":            "Default volume should be a number from 0 to %d.",
+	"settings.bad_max_length":        "Max song length should be a number of minutes up to %d, or blank for none.",
+	"settings.managers_only":         "Only server managers can change settings.",
+	"settings.not_saved":             "⚠️ Couldn't save that change — it applies until the bot restarts.",
+	"settings.invalid_form":          "Invalid form submission",
+	"settings.unknown_form":          "Unknown form",
+	"settings.join_to_control":       "Join <#%s> to control playback.",
+
+	"grab.listen":     "[Listen from where you grabbed it](%s)",
+	"grab.grabbed_at": "Grabbed at",
+	"grab.footer":     "💾 Saved with /grab",
+	"grab.channel":    "Channel",
+	"grab.sent":       "📬 Sent **%s** to your DMs",
+	"grab.dms_closed": "I couldn't DM you — allow direct messages from server members and try again",
+	"grab.failed":     "Couldn't send the DM, try again",
+
+	"misc.pong":            "Pong! 🏓",
+	"misc.command_error":   "An error occurred while processing your command",
+	"misc.unknown_command": "Sorry, I don't know how to handle this type of interaction",
+
+	"topsongs.spotify_disabled": "Spotify integration is not enabled. Ask the bot admin to set SPOTIFY_ENABLED=true.",
+	"topsongs.artist_not_found": "Couldn't find artist **%s** on Spotify.",
+	"topsongs.fetching":         "Found **%s** on Spotify, fetching top songs...",
+
+	"lyrics.song_missing":     "Tell me which song — `/lyrics song:` followed by its name.",
+	"lyrics.not_found":        "Couldn't find lyrics for **%s**.",
+	"lyrics.no_synced":        "No synced lyrics for **%s** — here are the plain ones.",
+	"lyrics.title":            "Lyrics: %s",
+	"lyrics.footer":           "Lyrics provided by lrclib.net",
+	"lyrics.karaoke_finished": "🎤 **%s**\n-# Song finished",
+	"lyrics.karaoke_stopped":  "🎤 **%s**\n-# Stopped following — run `/lyrics synced:True` to pick it back up",
+
+	"buttons.invalid":        "Invalid button interaction",
+	"buttons.guild_mismatch": "Guild mismatch",
+	"buttons.unknown":        "Unknown button action",
+
+	"musicchannel.save_failed":             "Couldn't save the music channel, try again in a bit.",
+	"musicchannel.unbound":                 "📣 Music channel unbound — updates go to wherever the bot was last used",
+	"musicchannel.bound":                   "📣 Now-playing cards and queue updates will be posted in <#%s>",
+	"musicchannel.dashboard_needs_channel": "The dashboard lives in the music channel — bind one with `/musicchannel` first.",
+	"musicchannel.dashboard_disabled":      "🗑️ Dashboard **disabled** — the pinned message is gone",
+	"musicchannel.dashboard_enabled":       "📺 Dashboard **enabled** — a pinned message in <#%s> will always show what's playing and what's next",
+	"musicchannel.threads_disabled":        "🧵 Session threads **disabled** — updates go straight to the channel again once this session ends",
+	"musicchannel.threads_enabled":         "🧵 Session threads **enabled** — each listening session gets its own thread for now-playing cards and queue updates, archived when I leave voice. I need the **Create Public Threads** permission.",
+	"musicchannel.reactions_disabled":      "⏯️ Reaction controls **disabled** — now-playing cards won't get reactions",
+	"musicchannel.reactions_enabled":       "⏯️ Reaction controls **enabled** — from the next song, react ⏯️ to pause or resume, ⏭️ to skip and ⏹️ to stop on the now-playing card. The same DJ and voice channel rules as buttons apply. I need **Add Reactions**, and **Manage Messages** to take reactions back so they can be used again.",
+
+	"notifyme.save_failed": "Couldn't save that, try again.",
+	"notifyme.disabled":    "🔕 Up-next DMs **disabled**",
+	"notifyme.enabled":     "🔔 Up-next DMs **enabled** — I'll message you when a song you queued is next, in any server. Make sure you allow DMs from server members.",
+
+	"busy.elsewhere":       "🎧 I'm busy in <#%s> — join that channel to listen along, or queue once it's done.",
+	"busy.already_there":   "🎧 I'm already in <#%s>.",
+	"busy.moved_button":    "Moved",
+	"busy.still_listening": "People are still listening in <#%s> — only a server manager can move me away.",
+	"busy.moved":           "🎧 Moved to <#%s> — the queue came along.",
+
+	"confirm.timed_out":      "⌛ Timed out — nothing was changed.",
+	"confirm.expired_button": "Expired",
+	"confirm.expired":        "This confirmation expired — run `/%s` again.",
+
+	"contextmenu.no_link": "That message doesn't have a YouTube, Spotify, Apple Music, SoundCloud, Bandcamp or Twitch link to queue.",
+
+	"userapp.needs_server":    "That command only works in servers I'm in — try `/search`, `/lyrics` or `/stats` here.",
+	"userapp.query_missing":   "Tell me what to search for.",
+	"userapp.results_on":      "🔎 %s on %s",
+	"userapp.add_me":          "Add me to a server to play these in voice",
+	"userapp.stats_empty":     "📊 You haven't requested any songs yet!",
+	"userapp.server":          "server",
+	"userapp.servers":         "servers",
+	"userapp.stats_title":     "📊 Your Stats",
+	"userapp.songs_requested": "Songs requested",
+	"userapp.across":          "Across %d %s",
+
+	"listenalong.failed": "Failed to get a link to the song.",
+	"listenalong.live":   "🎧 **%s** is a live stream — [open it](%s) and you'll be in sync.",
+	"listenalong.seek":   "On YouTube? [This link starts at %s](%s).",
+	"listenalong.footer": "-# The now-playing card keeps a link and the time for each song until I leave voice.",
+
+	"branding.bad_color":    "That isn't a hex color — try something like `#FF5500`.",
+	"branding.color_set":    "🎨 Now-playing cards will be **#%06X** while playing.",
+	"branding.bad_bar":      "Progress bar characters need to be a single character or emoji (up to %d code points, no spaces). Server emoji don't show in card footers.",
+	"branding.bar_set":      "🎨 Progress bars will look like %s",
+	"branding.bad_button":   "Pick one of the listed buttons.",
+	"branding.bad_emoji":    "That doesn't look like an emoji — use a unicode emoji or pick one of this server's from the emoji menu.",
+	"branding.emoji_set":    "🎨 The %s button will show %s.",
+	"branding.reset":        "🎨 Branding reset — cards and buttons are back to the default look.",
+	"branding.next_card":    "Takes effect on the next card.",
+	"branding.sample_title": "Artist - Song Title",
+	"branding.preview":      "🎨 Branding preview",
+	"branding.default_look": "Using the default look. Change it with `/branding color`, `/branding bar` or `/branding emoji`.",
+	"branding.color":        "**Color:** #%06X",
+	"branding.bar":          "**Progress bar:** %s %s",
+
+	"charts.disabled":     "Charts aren't enabled on this bot right now.",
+	"charts.fetch_failed": "Couldn't fetch the charts right now: %s",
+	"charts.empty":        "No chart data available right now. Try again later.",
+	"charts.none_found":   "Couldn't find any of the trending tracks on YouTube. Try again later.",
+	"charts.queued":       "📊 **Queued %d trending tracks:**",
+	"charts.title":        "📊 **What's Trending**",
+	"charts.play_hint":    "*Use `/charts play:true` to queue these tracks.*",
+
+	"djrequest.needs_gemini":   "The AI DJ requires Gemini to be enabled.",
+	"djrequest.not_understood": "Couldn't make sense of that one. Try saying it another way? 🤔",
+	"djrequest.header":         "🎧 **DJ:** *%s*",
+
+	"hints.radio":       "Pro tip: /radio auto-queues similar songs when the queue is empty",
+	"hints.leaderboard": "Pro tip: /leaderboard shows the most played songs in this server",
+	"hints.favorites":   "Pro tip: /favorites lets you save songs for later",
+	"hints.shuffle":     "Pro tip: /shuffle randomizes the current queue",
+	"hints.loop":        "Pro tip: /loop repeats the current song",
+	"hints.history":     "Pro tip: /history shows recently played songs",
+	"hints.recommend":   "Pro tip: /recommend lets the AI pick a song based on your taste",
+	"hints.volume":      "Pro tip: /volume adjusts the playback volume (0-150)",
+	"hints.lyrics":      "Pro tip: /lyrics shows lyrics for the currently playing song",
+	"hints.favorite":    "Pro tip: /favorite saves the current song to your favorites",
+	"hints.announce":    "Pro tip: /announce toggles the DJ voice announcements between songs",
+	"hints.request":     "Pro tip: Use /request to tell the DJ what vibe you're going for",
+
+	"preferences.offset_range":    "The volume offset goes from -%d to +%d.",
+	"preferences.save_failed":     "Couldn't save that, try again.",
+	"preferences.load_failed":     "Couldn't load your preferences, try again.",
+	"preferences.server_language": "the server's",
+	"preferences.volume_default":  "as everyone else's",
+	"preferences.header":          "⚙️ **Your preferences** — they follow you into every server",
+	"preferences.language":        "🌐 Language: **%s**",
+	"preferences.private_replies": "🤫 Private replies: **%s**",
+	"preferences.volume":          "🔉 Volume of songs you queue: **%s**",
+	"preferences.notify_up_next":  "🔔 Up-next DMs: **%s**",
+	"preferences.footer":          "-# Change any of them with `/preferences`.",
+	"preferences.on":              "on",
+	"preferences.off":             "off",
+
+	"queuefile.empty":            "Nothing to export — the queue is empty.",
+	"queuefile.export_failed":    "Failed to export the queue.",
+	"queuefile.exported":         "Exported %d tracks. Load them anywhere with `/queue import file:` and this attachment.",
+	"queuefile.nothing_attached": "Attach a queue file or paste a queue code to import.",
+	"queuefile.unreadable":       "Couldn't read that queue: %s",
+	"queuefile.join_first":       "Join a voice channel first, then import the queue.",
+	"queuefile.all_skipped":      "Everything in that queue is already queued or blocked.",
+	"queuefile.imported":         "Imported **%d** tracks into the queue.",
+	"queuefile.truncated":        "Only the first %d were imported; %d left out.",
+	"queuefile.share_code":       "Or share this code with `/queue import code:`",
+
+	"recommend.needs_gemini":     "AI recommendations require Gemini to be enabled.",
+	"recommend.no_db":            "No database available. Play some songs first to build listening history!",
+	"recommend.history_failed":   "Failed to fetch listening history.",
+	"recommend.need_history":     "Need at least 3 songs in history to make a smart recommendation. Play some more first! 🎵",
+	"recommend.ai_failed":        "Couldn't generate a recommendation right now. Try again in a moment! 🤖",
+	"recommend.no_tracks":        "No suitable tracks found for this recommendation. Try again soon! 🔍",
+	"recommend.pick_unavailable": "Couldn't load that song anymore — try `/recommend` again.",
+	"recommend.picks_following":  "🎧 **AI DJ picks** to follow **%s**:",
+	"recommend.picks":            "🎧 **AI DJ picks** based on what's been playing:",
+
+	"transcript.empty":       "🎵 Nothing's been played recently — no session to transcribe.",
+	"transcript.radio":       "radio",
+	"transcript.header":      "📜 **Session transcript** · <t:%d:f> – <t:%d:t> · %d songs",
+	"transcript.file_header": "📜 **Session transcript** · %d songs since <t:%d:t>",
+	"transcript.md_title":    "# Listening session — %s",
+	"transcript.md_summary":  "%d songs, %s – %s UTC",
+	"transcript.md_columns":  "| # | Time (UTC) | Song | Requested by |",
+
+	"schedule.playlist_label":   "playlist **%s**",
+	"schedule.song_or_playlist": "Give either a song (`query`) or a `playlist` to schedule.",
+	"schedule.bad_format":       "The time should look like `21:00`, `9pm`, `9:30pm`, `30m` or `in 1h30m`.",
+	"schedule.bad_range":        "The time must be at least a minute from now and within the next 7 days.",
+	"schedule.add_failed":       "Failed to schedule that. Try again.",
+	"schedule.full":             "This server already has %d plays scheduled. Cancel one with `/schedule cancel` first.",
+	"schedule.join_first":       "Join the voice channel it should play in first.",
+	"schedule.added":            "⏰ %s starts <t:%d:t> (<t:%d:R>) in <#%s>. Cancel with `/schedule cancel id:%d`.",
+	"schedule.fetch_failed":     "Failed to fetch the schedule.",
+	"schedule.empty":            "Nothing is scheduled. Schedule something with `/playat`.",
+	"schedule.list_title":       "⏰ **Scheduled plays:**",
+	"schedule.list_line":        "`#%d` <t:%d:f> (<t:%d:R>) — %s in <#%s>, by <@%s>",
+	"schedule.no_such_play":     "There's no scheduled play #%d — see `/schedule list`.",
+	"schedule.not_yours":        "Only <@%s> or a server manager can cancel that.",
+	"schedule.cancel_failed":    "Failed to cancel it. Try again.",
+	"schedule.cancelled":        "⏰ Cancelled %s (was due <t:%d:R>).",
+	"schedule.missed":           "⏰ Missed the scheduled start of %s (<t:%d:f>) while I was offline.",
+	"schedule.nothing_to_queue": "⏰ It's time for %s, but there's nothing left to queue — it's empty, already queued, or blocked.",
+	"schedule.join_failed":      "⏰ Couldn't start %s: joining <#%s> failed: %s",
+	"schedule.starting":         "⏰ Starting %s, scheduled by <@%s>.",
+
+	"podcast.not_a_link":     "That doesn't look like a link — give me the show's RSS feed URL.",
+	"podcast.feed_failed":    "Couldn't load that feed: %s",
+	"podcast.untitled":       "that podcast",
+	"podcast.no_episodes":    "**%s** has no audio episodes.",
+	"podcast.all_too_long":   "Every episode of **%s** runs over %d minutes, longer than I can play.",
+	"podcast.menu":           "Recent episodes of **%s** — pick one to queue:",
+	"podcast.some_too_long":  "(%d %s over %d minutes left out — too long for me to play)",
+	"podcast.placeholder":    "Choose an episode",
+	"podcast.episode":        "episode",
+	"podcast.episodes":       "episodes",
+	"podcast.expired":        "That episode list expired or isn't yours — run `/podcast` again.",
+	"podcast.bad_pick":       "That pick didn't match any episode — run `/podcast` again.",
+	"podcast.unplayable":     "Can't play that episode: %s",
+	"podcast.unknown_length": "Can't tell how long that episode is, so I can't play it.",
+
+	"chapters.queue_all":             "Queue all %d chapters",
+	"chapters.queue_all_description": "Each chapter becomes its own song in the queue",
+	"chapters.not_youtube_link":      "That isn't a YouTube video link.",
+	"chapters.nothing_playing":       "Nothing is playing — give me a YouTube link to split into chapters.",
+	"chapters.youtube_only":          "Chapters only work for YouTube videos.",
+	"chapters.lookup_failed":         "Couldn't look up that video: %s",
+	"chapters.live":                  "**%s** is a live stream, so it has no chapters to pick from.",
+	"chapters.read_failed":           "Couldn't read that video's chapters: %s",
+	"chapters.none":                  "**%s** has no chapters. Use `/play` with `start` and `end` to play part of it.",
+	"chapters.menu":                  "**%s** has %d chapters — pick one to queue, or queue them all:",
+	"chapters.truncated":             "(Only the first %d are listed; \"Queue all\" queues every chapter.)",
+	"chapters.placeholder":           "Choose a chapter",
+	"chapters.expired":               "That chapter list expired or isn't yours — run `/chapters` again.",
+	"chapters.bad_pick":              "That pick didn't match any chapter — run `/chapters` again.",
+	"chapters.blocked":               "That video is blocked from playing.",
+	"chapters.all":                   "all chapters",
+	"chapters.picked":                "Picked %s",
+
+	"audit.empty":   "📋 No matching commands in the last 90 days.",
+	"audit.title":   "📋 **Audit Log**",
+	"audit.line":    "**%d.** `%s` by **%s** · %s",
+	"audit.refused": "　　↳ ⛔ refused: %s",
+	"audit.failed":  "　　↳ ⚠️ failed: %s",
+
+	"tone.reset":              "🎭 Back to the default personality.",
+	"tone.style_missing":      "Describe the style you want, or use `/tone reset` for the default personality.",
+	"tone.unknown_subcommand": "Unknown /tone subcommand.",
+	"tone.preview":            "**Preview:**",
+	"tone.preview_failed":     "-# Couldn't write a preview right now, but the style is set.",
+	"tone.default":            "🎭 The DJ is using its **default personality**. Set a style with `/tone set`.",
+	"tone.summary":            "🎭 **AI style** (%d/%d characters):\n“%s”",
+}
@@ -0,0 +1,32 @@
+package i18n
+
+var spanish = map[string]string{
+	"common.db_unavailable":    "La base de datos no está disponible.",
+	"common.voice_state_error": "Error al obtener el estado de voz: %s",
+	"common.join_voice_error":  "Error al unirse al canal de voz: %s",
+	"common.join_voice_first":  "¡Primero únete a un canal de voz! 🎤",
+	"common.nothing_playing":   "No se está reproduciendo nada.",
+	"common.queue_empty":       "la cola está vacía",
+
+	"voice.not_connected": "No estoy en un canal de voz.",
+	"voice.left":          "👋 Salí del canal de voz.",
+	"voice.summon_first":  "Únete a un canal de voz y luego llámame.",
+	"voice.joined":        "🎧 Me uní a <#%s>.",
+	"voice.already_here":  "Ya estoy aquí.",
+
+	"cooldown.slow_down": "¡Más despacio! Podrás usar /%s de nuevo en %d s.",
+
+	"help.music":     "Control de música",
+	"help.queue":     "Gestión de la cola",
+	"help.favorites": "Favoritos",
+	"help.playlists": "Listas de reproducción",
+	"help.discovery": "Radio y descubrimiento",
+	"help.stats":     "Historial y estadísticas",
+	"help.voice":     "Voz del DJ",
+	"help.server":    "Ajustes del servidor",
+	"help.other":     "Otros",
+
+	"help.message_command": "Aplicaciones → %s (clic derecho en un mensaje)",
+	"help.managers_only":   "(administradores del servidor)",
+	"help.dj_only":         "(solo DJs)",
+}
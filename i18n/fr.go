@@ -0,0 +1,32 @@
+package i18n
+
+var french = map[string]string{
+	"common.db_unavailable":    "La base de données n'est pas disponible.",
+	"common.voice_state_error": "Erreur lors de la lecture de l'état vocal : %s",
+	"common.join_voice_error":  "Erreur en rejoignant le salon vocal : %s",
+	"common.join_voice_first":  "Rejoins d'abord un salon vocal ! 🎤",
+	"common.nothing_playing":   "Rien n'est en cours de lecture.",
+	"common.queue_empty":       "la file d'attente est vide",
+
+	"voice.not_connected": "Je ne suis pas dans un salon vocal.",
+	"voice.left":          "👋 J'ai quitté le salon vocal.",
+	"voice.summon_first":  "Rejoins un salon vocal, puis appelle-moi.",
+	"voice.joined":        "🎧 J'ai rejoint <#%s>.",
+	"voice.already_here":  "Je suis déjà là.",
+
+	"cooldown.slow_down": "Doucement ! Tu pourras réutiliser /%s dans %d s.",
+
+	"help.music":     "Contrôle de la musique",
+	"help.queue":     "Gestion de la file",
+	"help.favorites": "Favoris",
+	"help.playlists": "Playlists",
+	"help.discovery": "Radio et découverte",
+	"help.stats":     "Historique et statistiques",
+	"help.voice":     "Voix du DJ",
+	"help.server":    "Paramètres du serveur",
+	"help.other":     "Autres",
+
+	"help.message_command": "Applications → %s (clic droit sur un message)",
+	"help.managers_only":   "(gestionnaires du serveur)",
+	"help.dj_only":         "(DJ uniquement)",
+}
@@ -0,0 +1,65 @@
+// Package i18n serves user-facing strings in a guild's language. Discord
+// sends the guild's locale with every interaction; anything without a
+// translation falls back to English.
+//
+// Only en carries every key. The other catalogs translate the most common
+// replies (joining, queueing, playback) and deliberately leave the rest to
+// the English fallback, so a new string never needs five edits to ship.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// catalogs maps a base language (the part of a Discord locale before the
+// dash) to its translations.
+var catalogs = map[string]map[string]string{
+	"en": english,
+	"es": spanish,
+	"fr": french,
+	"de": german,
+	"pt": portuguese,
+}
+
+// languageNames are the English names Gemini is told to reply in.
+var languageNames = map[string]string{
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"pt": "Portuguese",
+}
+
+// base reduces a Discord locale such as "pt-BR" or "es-419" to its language.
+func base(locale string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(locale)), "-")
+	return lang
+}
+
+// T returns the message for key in locale, formatted with args. Missing
+// translations fall back to English, and unknown keys to the key itself so
+// a typo shows up rather than an empty message.
+func T(locale, key string, args ...any) string {
+	msg, ok := catalogs[base(locale)][key]
+	if !ok {
+		if msg, ok = english[key]; !ok {
+			return key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Supported reports whether locale has its own catalog.
+func Supported(locale string) bool {
+	_, ok := catalogs[base(locale)]
+	return ok
+}
+
+// LanguageName returns the English name of locale's language for AI prompts,
+// or "" for English and languages the bot doesn't translate into.
+func LanguageName(locale string) string {
+	return languageNames[base(locale)]
+}
@@ -0,0 +1,56 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		locale, key string
+		args        []any
+		want        string
+	}{
+		{"en-US", "voice.already_here", nil, "I'm already here."},
+		{"es-ES", "voice.already_here", nil, "Ya estoy aquí."},
+		{"es-419", "voice.already_here", nil, "Ya estoy aquí."},
+		{"pt-BR", "voice.joined", []any{"42"}, "🎧 Entrei em <#42>."},
+		{"ja", "voice.already_here", nil, "I'm already here."},
+		{"", "voice.already_here", nil, "I'm already here."},
+		{"fr", "no.such.key", nil, "no.such.key"},
+	}
+	for _, tt := range tests {
+		if got := T(tt.locale, tt.key, tt.args...); got != tt.want {
+			t.Errorf("T(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+}
+
+// Translations must not invent keys English lacks, and must take the same
+// format arguments as the English message.
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key, msg := range catalog {
+			en, ok := english[key]
+			if !ok {
+				t.Errorf("%s: key %q is not in the English catalog", lang, key)
+				continue
+			}
+			if strings.Count(msg, "%") != strings.Count(en, "%") {
+				t.Errorf("%s: %q has different format verbs than English", lang, key)
+			}
+		}
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	if got := LanguageName("de"); got != "German" {
+		t.Errorf("LanguageName(de) = %q", got)
+	}
+	if got := LanguageName("en-GB"); got != "" {
+		t.Errorf("LanguageName(en-GB) = %q, want empty", got)
+	}
+	if !Supported("pt-BR") || Supported("ja") {
+		t.Error("Supported disagrees with the catalogs")
+	}
+}
@@ -0,0 +1,32 @@
+package i18n
+
+var portuguese = map[string]string{
+	"common.db_unavailable":    "O banco de dados não está disponível.",
+	"common.voice_state_error": "Erro ao obter o estado de voz: %s",
+	"common.join_voice_error":  "Erro ao entrar no canal de voz: %s",
+	"common.join_voice_first":  "Entre em um canal de voz primeiro! 🎤",
+	"common.nothing_playing":   "Nada está tocando agora.",
+	"common.queue_empty":       "a fila está vazia",
+
+	"voice.not_connected": "Não estou em um canal de voz.",
+	"voice.left":          "👋 Saí do canal de voz.",
+	"voice.summon_first":  "Entre em um canal de voz e depois me chame.",
+	"voice.joined":        "🎧 Entrei em <#%s>.",
+	"voice.already_here":  "Já estou aqui.",
+
+	"cooldown.slow_down": "Calma! Você pode usar /%s de novo em %d s.",
+
+	"help.music":     "Controle de música",
+	"help.queue":     "Gerenciamento da fila",
+	"help.favorites": "Favoritos",
+	"help.playlists": "Playlists",
+	"help.discovery": "Rádio e descobertas",
+	"help.stats":     "Histórico e estatísticas",
+	"help.voice":     "Voz do DJ",
+	"help.server":    "Configurações do servidor",
+	"help.other":     "Outros",
+
+	"help.message_command": "Apps → %s (clique com o botão direito numa mensagem)",
+	"help.managers_only":   "(gerentes do servidor)",
+	"help.dj_only":         "(somente DJs)",
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
 	"time"
 )
@@ -31,42 +30,49 @@ func New() *Client {
 	}
 }
 
-func (c *Client) Search(query string) (string, string, error) {
+// Find returns lrclib's best match for query, or nil when nothing matches.
+func (c *Client) Find(query string) (*SearchResult, error) {
 	u := fmt.Sprintf("https://lrclib.net/api/search?q=%s", url.QueryEscape(query))
 	resp, err := c.httpClient.Get(u)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("lrclib API returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("lrclib API returned status %d", resp.StatusCode)
 	}
 
 	var results []SearchResult
 	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-		return "", "", err
+		return nil, err
 	}
 
 	if len(results) == 0 {
-		return "", "", nil
+		return nil, nil
 	}
+	return &results[0], nil
+}
 
-	res := results[0]
+// TrackInfo is the "Track — Artist" heading for a result.
+func (r *SearchResult) TrackInfo() string {
+	return r.TrackName + " — " + r.ArtistName
+}
 
-	var lyricsText string
-	if res.PlainLyrics != "" {
-		lyricsText = res.PlainLyrics
-	} else if res.SyncedLyrics != "" {
-		re := regexp.MustCompile(`\[\d+:\d+\.\d+\]`)
-		lyricsText = re.ReplaceAllString(res.SyncedLyrics, "")
-		lyricsText = strings.TrimSpace(lyricsText)
+// Plain returns the unsynced lyrics, falling back to the synced ones with
+// their timestamps stripped. "" means lrclib has the track but no lyrics
+// (e.g. an instrumental).
+func (r *SearchResult) Plain() string {
+	if r.PlainLyrics != "" {
+		return r.PlainLyrics
 	}
+	return strings.TrimSpace(timestampPattern.ReplaceAllString(r.SyncedLyrics, ""))
+}
 
-	if lyricsText == "" {
-		return "", res.TrackName + " — " + res.ArtistName, nil
+func (c *Client) Search(query string) (string, string, error) {
+	res, err := c.Find(query)
+	if err != nil || res == nil {
+		return "", "", err
 	}
-
-	trackInfo := res.TrackName + " — " + res.ArtistName
-	return lyricsText, trackInfo, nil
+	return res.Plain(), res.TrackInfo(), nil
 }
@@ -0,0 +1,95 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// bracketed matches "(Official Video)", "[HD]", "【MV】" and the like.
+	bracketed = regexp.MustCompile(`\s*[(\[【][^)\]】]*[)\]】]`)
+	// featuring matches a "feat. X" tail, which lrclib rarely indexes.
+	featuring = regexp.MustCompile(`(?i)\s+(ft\.?|feat\.?|featuring)\s+.*$`)
+)
+
+// Query builds an lrclib search query from a track title as it came from
+// YouTube or another source. Bracketed noise, "feat." credits and anything
+// after a " | " are dropped. artist may be "" when unknown; if the title looks
+// like "Artist - Song", that split is used instead.
+func Query(title, artist string) string {
+	title = bracketed.ReplaceAllString(title, "")
+	if i := strings.Index(title, " | "); i > 0 {
+		title = title[:i]
+	}
+
+	song := title
+	if parts := strings.SplitN(title, " - ", 2); len(parts) == 2 {
+		if artist == "" {
+			artist = parts[0]
+		}
+		song = parts[1]
+	}
+	song = featuring.ReplaceAllString(strings.TrimSpace(song), "")
+	artist = featuring.ReplaceAllString(strings.TrimSpace(artist), "")
+
+	return strings.Join(strings.Fields(artist+" "+song), " ")
+}
+
+// Pages splits lyrics into chunks of at most limit characters, breaking
+// between stanzas where possible, then between lines. A single line longer
+// than limit is cut mid-line.
+func Pages(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	var pages []string
+	var page []rune
+	flush := func() {
+		if s := strings.TrimSpace(string(page)); s != "" {
+			pages = append(pages, s)
+		}
+		page = page[:0]
+	}
+
+	for _, stanza := range strings.Split(text, "\n\n") {
+		runes := []rune(stanza)
+		sep := 0
+		if len(page) > 0 {
+			sep = 2
+		}
+		if len(page)+sep+len(runes) <= limit {
+			if sep > 0 {
+				page = append(page, '\n', '\n')
+			}
+			page = append(page, runes...)
+			continue
+		}
+
+		// The stanza doesn't fit: start it on a fresh page, line by line.
+		flush()
+		for _, line := range strings.Split(stanza, "\n") {
+			lineRunes := []rune(line)
+			for len(lineRunes) > limit {
+				flush()
+				pages = append(pages, string(lineRunes[:limit]))
+				lineRunes = lineRunes[limit:]
+			}
+			sep := 0
+			if len(page) > 0 {
+				sep = 1
+			}
+			if len(page)+sep+len(lineRunes) > limit {
+				flush()
+				sep = 0
+			}
+			if sep > 0 {
+				page = append(page, '\n')
+			}
+			page = append(page, lineRunes...)
+		}
+	}
+	flush()
+	return pages
+}
@@ -0,0 +1,54 @@
+package lyrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		title  string
+		artist string
+		want   string
+	}{
+		{"Rick Astley - Never Gonna Give You Up (Official Music Video)", "", "Rick Astley Never Gonna Give You Up"},
+		{"Daft Punk - Get Lucky ft. Pharrell Williams [HD]", "", "Daft Punk Get Lucky"},
+		{"Blinding Lights (Official Audio)", "The Weeknd", "The Weeknd Blinding Lights"},
+		{"Artist Name - Song | Live at Wembley", "Deezer Artist", "Deezer Artist Song"},
+		{"YOASOBI「アイドル」 Official Music Video", "", "YOASOBI「アイドル」 Official Music Video"},
+		{"Plain Title", "", "Plain Title"},
+	}
+	for _, tt := range tests {
+		if got := Query(tt.title, tt.artist); got != tt.want {
+			t.Errorf("Query(%q, %q) = %q, want %q", tt.title, tt.artist, got, tt.want)
+		}
+	}
+}
+
+func TestPages(t *testing.T) {
+	stanza := strings.Repeat("la la la la\n", 9) + "la la la la" // 119 chars
+	text := strings.Join([]string{stanza, stanza, stanza}, "\n\n")
+
+	pages := Pages(text, 250)
+	if len(pages) != 2 {
+		t.Fatalf("got %d pages, want 2", len(pages))
+	}
+	if pages[0] != stanza+"\n\n"+stanza || pages[1] != stanza {
+		t.Errorf("pages should break between stanzas, got %q", pages)
+	}
+
+	for _, page := range Pages(text, 50) {
+		if n := len([]rune(page)); n > 50 {
+			t.Errorf("page of %d chars exceeds limit", n)
+		}
+	}
+
+	long := strings.Repeat("x", 120)
+	if got := Pages(long, 50); len(got) != 3 || got[2] != strings.Repeat("x", 20) {
+		t.Errorf("long line split = %q", got)
+	}
+
+	if got := Pages("  \n ", 50); got != nil {
+		t.Errorf("blank lyrics should give no pages, got %q", got)
+	}
+}
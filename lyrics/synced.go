@@ -0,0 +1,60 @@
+package lyrics
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampPattern matches an LRC timestamp like [01:23.45] or [01:23.456].
+var timestampPattern = regexp.MustCompile(`\[(\d+):(\d+)(?:\.(\d+))?\]`)
+
+// Line is one timed line of synced lyrics.
+type Line struct {
+	At   time.Duration
+	Text string
+}
+
+// ParseSynced parses LRC-formatted lyrics into lines ordered by time. A line
+// carrying several timestamps (a repeated chorus) appears once per stamp;
+// metadata tags like [ar:...] and untimed lines are skipped.
+func ParseSynced(lrc string) []Line {
+	var lines []Line
+	for _, raw := range strings.Split(lrc, "\n") {
+		stamps := timestampPattern.FindAllStringSubmatchIndex(raw, -1)
+		if len(stamps) == 0 || stamps[0][0] != 0 {
+			continue
+		}
+		text := strings.TrimSpace(raw[stamps[len(stamps)-1][1]:])
+		for _, m := range stamps {
+			lines = append(lines, Line{At: parseTimestamp(raw, m), Text: text})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].At < lines[j].At })
+	return lines
+}
+
+func parseTimestamp(raw string, m []int) time.Duration {
+	minutes, _ := strconv.Atoi(raw[m[2]:m[3]])
+	seconds, _ := strconv.Atoi(raw[m[4]:m[5]])
+	at := time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	if m[6] >= 0 {
+		// Fractions are hundredths or thousandths depending on the source.
+		frac := raw[m[6]:m[7]]
+		n, _ := strconv.Atoi(frac)
+		scale := time.Second
+		for range frac {
+			scale /= 10
+		}
+		at += time.Duration(n) * scale
+	}
+	return at
+}
+
+// LineAt returns the index of the line being sung at pos, or -1 before the
+// first line starts.
+func LineAt(lines []Line, pos time.Duration) int {
+	return sort.Search(len(lines), func(i int) bool { return lines[i].At > pos }) - 1
+}
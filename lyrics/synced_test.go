@@ -0,0 +1,50 @@
+package lyrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSynced(t *testing.T) {
+	lrc := "[ar:Someone]\n" +
+		"[00:12.50] First line\n" +
+		"[00:15.123]Second line\n" +
+		"[00:20.00][01:05.00] Chorus\n" +
+		"[00:30.00]\n" +
+		"untimed line\n"
+
+	lines := ParseSynced(lrc)
+	want := []Line{
+		{12500 * time.Millisecond, "First line"},
+		{15123 * time.Millisecond, "Second line"},
+		{20 * time.Second, "Chorus"},
+		{30 * time.Second, ""},
+		{65 * time.Second, "Chorus"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(lines), len(want), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	lines := []Line{{10 * time.Second, "a"}, {20 * time.Second, "b"}, {30 * time.Second, "c"}}
+	tests := []struct {
+		pos  time.Duration
+		want int
+	}{
+		{0, -1},
+		{10 * time.Second, 0},
+		{25 * time.Second, 1},
+		{time.Hour, 2},
+	}
+	for _, tt := range tests {
+		if got := LineAt(lines, tt.pos); got != tt.want {
+			t.Errorf("LineAt(%v) = %d, want %d", tt.pos, got, tt.want)
+		}
+	}
+}
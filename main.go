@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
 	_ "embed"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 
@@ -27,6 +32,7 @@ import (
 	"beatbot/pages"
 	"beatbot/tts"
 	"beatbot/youtube"
+	"beatbot/ytdlp"
 )
 
 //go:embed web/index.html
@@ -35,6 +41,10 @@ var indexHTML []byte
 //go:embed web/styles.css
 var stylesCSS []byte
 
+// maxBroadcastLength leaves room under Discord's 2000 character limit for
+// what Broadcast adds to the message.
+const maxBroadcastLength = 1900
+
 func main() {
 	log.SetFormatter(&nested.Formatter{
 		HideKeys:     true,
@@ -60,7 +70,12 @@ func main() {
 	}
 
 	appConfig.NewConfig()
-	if err := run(context.Background()); err != nil {
+
+	// SIGTERM is how deploys stop the bot; catching it lets run save what's
+	// playing first.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx); err != nil {
 		sentry.CaptureException(err)
 		log.Fatal(err)
 	}
@@ -72,6 +87,9 @@ func run(ctx context.Context) error {
 		log.Warnf("Failed to initialize database (continuing without persistence): %v", err)
 	} else {
 		defer db.Close()
+		db.StartHistoryPruning(ctx, appConfig.Config.Options.HistoryRetention)
+		db.StartAuditPruning(ctx)
+		db.StartGuildCleanup(ctx)
 	}
 
 	// Initialize the Gemini client once at startup (no-op when disabled).
@@ -84,6 +102,15 @@ func run(ctx context.Context) error {
 		log.Warnf("Failed to initialize TTS provider (voice announcements disabled): %v", err)
 	}
 
+	if cookies := appConfig.Config.Youtube.CookiesFile; cookies != "" {
+		if _, err := os.Stat(cookies); err != nil {
+			log.Warnf("YTDLP_COOKIES_FILE can't be read (age-restricted videos won't play): %v", err)
+		}
+	}
+
+	ytdlp.Init(ctx, appConfig.Config.Youtube.YtdlpVersion)
+	ytdlp.StartAutoUpdate(ctx, appConfig.Config.Youtube.YtdlpUpdate)
+
 	controller, err := controller.NewController(db)
 	if err != nil {
 		sentry.CaptureException(err)
@@ -91,9 +118,19 @@ func run(ctx context.Context) error {
 		return err
 	}
 
+	if appConfig.Config.Options.RegisterCommands {
+		if err := handlers.RegisterCommands(appConfig.Config.Discord.BotToken, appConfig.Config.Discord.AppID); err != nil {
+			sentry.CaptureException(err)
+			log.Warnf("Failed to register slash commands (keeping the existing ones): %v", err)
+		}
+	}
+
 	// Manager is stateless (holds only config strings + shared controller pointer).
 	// Construct once and reuse across requests instead of allocating per-request.
 	manager := handlers.NewManager(os.Getenv("DISCORD_APP_ID"), controller)
+	manager.StartScheduler()
+	manager.ListenReactions(controller.GetSession())
+	controller.RestoreVoiceSessions(manager.AppID)
 
 	router := gin.New()
 
@@ -110,6 +147,7 @@ func run(ctx context.Context) error {
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status": "ok",
+			"ytdlp":  ytdlp.GetStatus(),
 		})
 	})
 
@@ -173,6 +211,52 @@ func run(ctx context.Context) error {
 		})
 	})
 
+	// Operators can check here whether searches are on the yt-dlp fallback
+	// because the YouTube Data API key is over its quota.
+	router.GET("/youtube/quota", func(c *gin.Context) {
+		c.JSON(http.StatusOK, youtube.GetQuotaStatus())
+	})
+
+	// Operator endpoints, off unless ADMIN_TOKEN is set.
+	if adminToken := appConfig.Config.Options.AdminToken; adminToken != "" {
+		admin := router.Group("/admin", func(c *gin.Context) {
+			given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(given), []byte(adminToken)) != 1 {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token"})
+			}
+		})
+
+		// Update yt-dlp without a redeploy when YouTube breaks it.
+		admin.POST("/ytdlp/update", func(c *gin.Context) {
+			// Finish the update even if the caller hangs up.
+			before, after, err := ytdlp.Update(context.WithoutCancel(c.Request.Context()))
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "version": after})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"previous": before, "version": after})
+		})
+
+		// Announce something to every server, like a restart for
+		// maintenance, optionally pausing everything that's playing.
+		admin.POST("/broadcast", func(c *gin.Context) {
+			var req struct {
+				Message string `json:"message"`
+				Pause   bool   `json:"pause"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Expected JSON with a message"})
+				return
+			}
+			req.Message = strings.TrimSpace(req.Message)
+			if req.Message == "" || utf8.RuneCountInString(req.Message) > maxBroadcastLength {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Message must be 1-%d characters", maxBroadcastLength)})
+				return
+			}
+			c.JSON(http.StatusOK, controller.Broadcast(req.Message, req.Pause))
+		})
+	}
+
 	router.GET("/youtube/search", func(c *gin.Context) {
 		query := c.Query("query")
 		videos := youtube.Query(c.Request.Context(), query)
@@ -209,7 +293,7 @@ func run(ctx context.Context) error {
 				return
 			}
 			prompt := string(bodyBytes)
-			response := gemini.GenerateHelpfulResponse(c.Request.Context(), prompt)
+			response := gemini.GenerateHelpfulResponse(c.Request.Context(), handlers.HelpMenu("", nil), prompt)
 			c.JSON(http.StatusOK, gin.H{
 				"response": response,
 			})
@@ -231,42 +315,51 @@ func run(ctx context.Context) error {
 		})
 	})
 
-	router.POST("/discord/interactions", func(c *gin.Context) {
-		signature := c.GetHeader("X-Signature-Ed25519")
-		timestamp := c.GetHeader("X-Signature-Timestamp")
+	// In gateway mode interactions come in over the bot's websocket, so
+	// Discord never needs to reach this server. Leave the app's Interactions
+	// Endpoint URL empty in the developer portal: the endpoint below is only
+	// served in webhook mode, where DISCORD_PUBLIC_KEY verifies each request.
+	if appConfig.Config.Discord.UseGateway() {
+		manager.ListenGateway(controller.GetSession())
+		log.Info("Receiving interactions over the Discord gateway")
+	} else {
+		router.POST("/discord/interactions", func(c *gin.Context) {
+			signature := c.GetHeader("X-Signature-Ed25519")
+			timestamp := c.GetHeader("X-Signature-Timestamp")
 
-		var bodyBytes []byte
-		bodyBytes, err := c.GetRawData()
-		if err != nil {
-			log.Errorf("Error reading body: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
-			return
-		}
+			var bodyBytes []byte
+			bodyBytes, err := c.GetRawData()
+			if err != nil {
+				log.Errorf("Error reading body: %v", err)
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+				return
+			}
 
-		if !manager.VerifyDiscordRequest(signature, timestamp, bodyBytes) {
-			sentry.CaptureMessage("Invalid request signature")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid request signature"})
-			return
-		}
+			if !manager.VerifyDiscordRequest(signature, timestamp, bodyBytes) {
+				sentry.CaptureMessage("Invalid request signature")
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid request signature"})
+				return
+			}
 
-		interaction, err := manager.ParseInteraction(bodyBytes)
+			interaction, err := manager.ParseInteraction(bodyBytes)
 
-		if interaction.Type == 1 {
-			c.JSON(http.StatusOK, gin.H{
-				"type": 1,
-			})
-			return
-		}
+			if interaction.Type == 1 {
+				c.JSON(http.StatusOK, gin.H{
+					"type": 1,
+				})
+				return
+			}
 
-		if err != nil {
-			log.Errorf("Error parsing interaction: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse interaction"})
-			return
-		}
+			if err != nil {
+				log.Errorf("Error parsing interaction: %v", err)
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse interaction"})
+				return
+			}
 
-		response := manager.HandleInteraction(interaction)
-		c.JSON(http.StatusOK, response)
-	})
+			response := manager.HandleInteraction(interaction)
+			c.JSON(http.StatusOK, response)
+		})
+	}
 
 	port := appConfig.Config.Options.Port
 	if port == "" {
@@ -277,11 +370,31 @@ func run(ctx context.Context) error {
 		log.Infof("Using Cloudflare Tunnel at %s", appConfig.Config.Tunnel.CloudflareTunnelURL)
 		router.SetTrustedProxies([]string{"127.0.0.1", "localhost"})
 		log.Infof("Starting server on :%s (Cloudflare tunnel handles external traffic)", port)
-		return router.Run(":" + port)
+		return serve(ctx, router, port, controller)
 	}
 
 	router.SetTrustedProxies([]string{"127.0.0.1", "localhost"})
 
 	log.Infof("Starting server on :%s", port)
-	return router.Run(":" + port)
+	return serve(ctx, router, port, controller)
+}
+
+// serve runs the HTTP server until ctx is done, then saves the sessions that
+// are playing so the next start rejoins and carries on.
+func serve(ctx context.Context, handler http.Handler, port string, c *controller.Controller) error {
+	server := &http.Server{Addr: ":" + port, Handler: handler}
+	errs := make(chan error, 1)
+	go func() { errs <- server.ListenAndServe() }()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Info("Shutting down")
+	c.SaveVoiceSessions()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
 }
@@ -0,0 +1,255 @@
+// Package podcast reads podcast RSS and Atom feeds.
+package podcast
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFeedBytes caps how much of a feed is read. Long-running shows put
+// every episode they've ever published in the feed.
+const maxFeedBytes = 10 << 20
+
+var httpClient = &http.Client{
+	Timeout: 15 * time.Second,
+}
+
+// ErrNotFeed is returned when a link isn't an RSS or Atom feed.
+var ErrNotFeed = errors.New("that link isn't an RSS or Atom feed")
+
+// Episode is one playable episode of a feed.
+type Episode struct {
+	Title     string
+	AudioURL  string        // the enclosure
+	Published time.Time     // zero when the feed didn't say
+	Duration  time.Duration // 0 when the feed didn't say
+}
+
+// Feed is a podcast and its episodes, newest first.
+type Feed struct {
+	Title    string
+	Episodes []Episode
+}
+
+// Fetch downloads and parses the feed at feedURL.
+func Fetch(ctx context.Context, feedURL string) (*Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed returned HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading feed: %w", err)
+	}
+	return Parse(data, feedURL)
+}
+
+type rssDoc struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			Title     string `xml:"title"`
+			PubDate   string `xml:"pubDate"`
+			Duration  string `xml:"duration"` // itunes:duration
+			Enclosure struct {
+				URL  string `xml:"url,attr"`
+				Type string `xml:"type,attr"`
+			} `xml:"enclosure"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomDoc struct {
+	Title   string `xml:"title"`
+	Entries []struct {
+		Title     string `xml:"title"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		Duration  string `xml:"duration"`
+		Links     []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+// Parse reads an RSS 2.0 or Atom feed fetched from feedURL. Entries without
+// an audio enclosure (video episodes, blog posts), or whose enclosure isn't
+// an http(s) link, are left out.
+func Parse(data []byte, feedURL string) (*Feed, error) {
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, ErrNotFeed
+	}
+	base, _ := url.Parse(feedURL)
+
+	feed := &Feed{}
+	switch root {
+	case "rss":
+		var doc rssDoc
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotFeed, err)
+		}
+		feed.Title = strings.TrimSpace(doc.Channel.Title)
+		for _, item := range doc.Channel.Items {
+			audioURL := enclosureURL(base, item.Enclosure.URL)
+			if audioURL == "" || !isAudio(audioURL, item.Enclosure.Type) {
+				continue
+			}
+			feed.Episodes = append(feed.Episodes, Episode{
+				Title:     strings.TrimSpace(item.Title),
+				AudioURL:  audioURL,
+				Published: parseDate(item.PubDate),
+				Duration:  ParseDuration(item.Duration),
+			})
+		}
+	case "feed":
+		var doc atomDoc
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotFeed, err)
+		}
+		feed.Title = strings.TrimSpace(doc.Title)
+		for _, entry := range doc.Entries {
+			for _, link := range entry.Links {
+				audioURL := enclosureURL(base, link.Href)
+				if link.Rel != "enclosure" || audioURL == "" || !isAudio(audioURL, link.Type) {
+					continue
+				}
+				published := parseDate(entry.Published)
+				if published.IsZero() {
+					published = parseDate(entry.Updated)
+				}
+				feed.Episodes = append(feed.Episodes, Episode{
+					Title:     strings.TrimSpace(entry.Title),
+					AudioURL:  audioURL,
+					Published: published,
+					Duration:  ParseDuration(entry.Duration),
+				})
+				break
+			}
+		}
+	default:
+		return nil, ErrNotFeed
+	}
+
+	// Most feeds are newest first already; some list oldest first.
+	sort.SliceStable(feed.Episodes, func(i, j int) bool {
+		return feed.Episodes[i].Published.After(feed.Episodes[j].Published)
+	})
+	return feed, nil
+}
+
+// rootElement returns the name of the document's first element.
+func rootElement(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// isAudio reports whether an enclosure is audio, going by its MIME type, or
+// by its extension when the feed left the type out.
+// enclosureURL resolves an enclosure link against the feed's own URL.
+// Feeds are untrusted and the link goes to ffprobe and ffmpeg, so anything
+// that doesn't end up an http(s) link with a host, like file:// or a
+// would-be command line option, is "".
+func enclosureURL(base *url.URL, href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return ""
+	}
+	return u.String()
+}
+
+func isAudio(url, mimeType string) bool {
+	if url == "" {
+		return false
+	}
+	if mimeType != "" {
+		return strings.HasPrefix(strings.ToLower(mimeType), "audio/")
+	}
+	switch strings.ToLower(path.Ext(strings.SplitN(url, "?", 2)[0])) {
+	case ".mp3", ".m4a", ".aac", ".ogg", ".opus", ".wav", ".flac":
+		return true
+	}
+	return false
+}
+
+// dateLayouts are the pubDate spellings seen in the wild. RSS asks for
+// RFC 822, which plenty of feeds only loosely follow.
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+}
+
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ParseDuration reads an itunes:duration, which is either a number of seconds
+// or [H:]MM:SS. Returns 0 for anything else.
+func ParseDuration(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0
+	}
+	var total float64
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total * float64(time.Second)).Round(time.Second)
+}
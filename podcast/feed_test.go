@@ -0,0 +1,129 @@
+package podcast
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Song Exploder</title>
+    <item>
+      <title>Older episode</title>
+      <pubDate>Mon, 02 Mar 2026 08:00:00 +0000</pubDate>
+      <itunes:duration>1:02:03</itunes:duration>
+      <enclosure url="https://cdn.example.com/old.mp3" length="1000" type="audio/mpeg"/>
+    </item>
+    <item>
+      <title>Video bonus</title>
+      <pubDate>Tue, 10 Mar 2026 08:00:00 +0000</pubDate>
+      <enclosure url="https://cdn.example.com/bonus.mp4" type="video/mp4"/>
+    </item>
+    <item>
+      <title>Newest episode</title>
+      <pubDate>Mon, 9 Mar 2026 08:00:00 GMT</pubDate>
+      <itunes:duration>1520</itunes:duration>
+      <enclosure url="https://cdn.example.com/new.mp3?source=rss" type="audio/mpeg"/>
+    </item>
+  </channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Show</title>
+  <entry>
+    <title>Pilot</title>
+    <updated>2026-01-05T10:00:00Z</updated>
+    <link rel="alternate" href="https://example.com/pilot"/>
+    <link rel="enclosure" href="https://example.com/pilot.m4a"/>
+  </entry>
+  <entry>
+    <title>Text post</title>
+    <updated>2026-01-06T10:00:00Z</updated>
+    <link rel="alternate" href="https://example.com/post"/>
+  </entry>
+</feed>`
+
+func TestParseRSS(t *testing.T) {
+	feed, err := Parse([]byte(rssFeed), "https://example.com/feed.xml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if feed.Title != "Song Exploder" {
+		t.Errorf("Title = %q", feed.Title)
+	}
+	if len(feed.Episodes) != 2 {
+		t.Fatalf("got %d episodes, want 2 (video left out): %+v", len(feed.Episodes), feed.Episodes)
+	}
+	newest := feed.Episodes[0]
+	if newest.Title != "Newest episode" || newest.Duration != 1520*time.Second ||
+		newest.AudioURL != "https://cdn.example.com/new.mp3?source=rss" {
+		t.Errorf("newest = %+v", newest)
+	}
+	if d := feed.Episodes[1].Duration; d != time.Hour+2*time.Minute+3*time.Second {
+		t.Errorf("older duration = %v", d)
+	}
+}
+
+func TestParseAtom(t *testing.T) {
+	feed, err := Parse([]byte(atomFeed), "https://example.com/feed.xml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if feed.Title != "Atom Show" || len(feed.Episodes) != 1 {
+		t.Fatalf("feed = %+v", feed)
+	}
+	if ep := feed.Episodes[0]; ep.AudioURL != "https://example.com/pilot.m4a" || ep.Published.IsZero() {
+		t.Errorf("episode = %+v", ep)
+	}
+}
+
+func TestParseNotFeed(t *testing.T) {
+	for _, data := range []string{"<html><body>hi</body></html>", "not xml at all", ""} {
+		if _, err := Parse([]byte(data), ""); !errors.Is(err, ErrNotFeed) {
+			t.Errorf("Parse(%q) error = %v, want ErrNotFeed", data, err)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":         0,
+		"95":       95 * time.Second,
+		"12:30":    12*time.Minute + 30*time.Second,
+		"01:00:05": time.Hour + 5*time.Second,
+		"1:2:3:4":  0,
+		"soon":     0,
+	}
+	for in, want := range tests {
+		if got := ParseDuration(in); got != want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestParseEnclosureLinks(t *testing.T) {
+	const feedXML = `<rss version="2.0"><channel><title>Show</title>
+<item><title>Local file</title><pubDate>Mon, 02 Mar 2026 08:00:00 +0000</pubDate><enclosure url="file:///etc/passwd" type="audio/mpeg"/></item>
+<item><title>Option</title><pubDate>Mon, 02 Mar 2026 08:00:00 +0000</pubDate><enclosure url="-i/etc/passwd" type="audio/mpeg"/></item>
+<item><title>Relative</title><pubDate>Tue, 03 Mar 2026 08:00:00 +0000</pubDate><enclosure url="/episodes/2.mp3" type="audio/mpeg"/></item>
+</channel></rss>`
+
+	feed, err := Parse([]byte(feedXML), "https://example.com/shows/feed.xml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// The file:// episode is dropped; relative links, option-looking ones
+	// included, become links on the feed's host.
+	if len(feed.Episodes) != 2 {
+		t.Fatalf("got %d episodes, want 2: %+v", len(feed.Episodes), feed.Episodes)
+	}
+	if got := feed.Episodes[0].AudioURL; got != "https://example.com/episodes/2.mp3" {
+		t.Errorf("relative enclosure resolved to %q", got)
+	}
+	if got := feed.Episodes[1].AudioURL; got != "https://example.com/shows/-i/etc/passwd" {
+		t.Errorf("option-looking enclosure resolved to %q", got)
+	}
+}
@@ -0,0 +1,138 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/applemusic"
+	"beatbot/config"
+	"beatbot/youtube"
+)
+
+// appleMusicPlaylistLimit caps how many tracks of an Apple Music playlist
+// are queued.
+const appleMusicPlaylistLimit = 15
+
+// appleMusicResolver plays Apple Music links by matching their tracks on
+// YouTube. Apple Music has no public search, so it's links only.
+type appleMusicResolver struct{}
+
+func (appleMusicResolver) Name() string        { return SourceAppleMusic }
+func (appleMusicResolver) DisplayName() string { return "Apple Music" }
+
+func (appleMusicResolver) MatchesURL(query string) bool {
+	return strings.HasPrefix(query, "https://music.apple.com/") ||
+		strings.HasPrefix(query, "https://itunes.apple.com/")
+}
+
+func (appleMusicResolver) Search(_ context.Context, _ string) ([]youtube.VideoResponse, error) {
+	return nil, ErrSearchUnsupported
+}
+
+// IsCollection reports whether query links to a playlist, album or artist
+// rather than a single track. An album link with ?i= points at one of its
+// tracks.
+func (appleMusicResolver) IsCollection(query string) bool {
+	req, err := applemusic.ParseAppleMusicURL(query)
+	return err == nil && (req.PlaylistID != "" || req.ArtistID != "" || req.TrackID == "")
+}
+
+func (appleMusicResolver) Resolve(ctx context.Context, query string) (*Resolution, error) {
+	req, err := applemusic.ParseAppleMusicURL(query)
+	if err != nil {
+		return nil, refuse(err, "Invalid Apple Music URL: %v", err)
+	}
+	// Use default country if not specified
+	if req.Country == "" {
+		req.Country = "us"
+	}
+
+	switch {
+	case req.PlaylistID != "":
+		return appleMusicPlaylist(ctx, req)
+	case req.AlbumID != "" && req.TrackID == "":
+		return appleMusicAlbum(ctx, req)
+	case req.ArtistID != "":
+		// Artist links queue their top songs
+		return appleMusicArtist(ctx, req)
+	case req.TrackID != "":
+		return appleMusicTrack(ctx, req)
+	}
+	return nil, refuse(nil, "Invalid Apple Music URL type.")
+}
+
+// appleMusicError turns a failed Apple Music lookup of a kind ("playlist",
+// "album") into a Refusal when it's something the user can fix.
+func appleMusicError(kind string, err error) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "404"):
+		return refuse(err, "That %s doesn't exist or has been deleted.", kind)
+	case strings.Contains(errMsg, "403"), strings.Contains(errMsg, "private"):
+		return refuse(err, "That %s is private or not accessible.", kind)
+	case strings.Contains(errMsg, "no playable tracks"):
+		return refuse(err, "That %s contains no playable tracks.", kind)
+	}
+	return fmt.Errorf("error fetching %s from Apple Music: %w", kind, err)
+}
+
+// appleMusicSongs are the songs Apple Music tracks are matched on YouTube
+// as. Apple Music doesn't report lengths, so any official upload counts.
+func appleMusicSongs(tracks []applemusic.PlaylistTrackInfo) []song {
+	songs := make([]song, len(tracks))
+	for i, track := range tracks {
+		songs[i] = song{Title: track.Title, Artists: track.Artists}
+	}
+	return songs
+}
+
+func appleMusicTrack(ctx context.Context, req applemusic.AppleMusicRequest) (*Resolution, error) {
+	log.Debugf("Processing Apple Music track: country=%s, album=%s, track=%s", req.Country, req.AlbumID, req.TrackID)
+	track, err := applemusic.GetTrack(ctx, req.Country, req.AlbumID, req.TrackID)
+	if err != nil {
+		return nil, appleMusicError("track", err)
+	}
+	return matchSong(ctx, "Apple Music", song{Title: track.Title, Artists: track.Artists}, "YouTube video")
+}
+
+func appleMusicAlbum(ctx context.Context, req applemusic.AppleMusicRequest) (*Resolution, error) {
+	log.Debugf("Processing Apple Music album: country=%s, album=%s", req.Country, req.AlbumID)
+	progress(ctx, "Found an Apple Music album, fetching tracks...")
+
+	album, err := applemusic.GetAlbumTracks(ctx, req.Country, req.AlbumID)
+	if err != nil {
+		return nil, appleMusicError("album", err)
+	}
+	return matchCollection(ctx, "Apple Music",
+		Collection{Kind: "album", Name: albumName(album.Name, album.Artist), Total: album.TotalTracks},
+		appleMusicSongs(album.Tracks))
+}
+
+func appleMusicPlaylist(ctx context.Context, req applemusic.AppleMusicRequest) (*Resolution, error) {
+	log.Debugf("Processing Apple Music playlist: country=%s, playlist=%s", req.Country, req.PlaylistID)
+	progress(ctx, "Found an Apple Music playlist, fetching tracks...")
+
+	playlist, err := applemusic.GetPlaylistTracks(ctx, req.Country, req.PlaylistID, appleMusicPlaylistLimit)
+	if err != nil {
+		return nil, appleMusicError("playlist", err)
+	}
+	return matchCollection(ctx, "Apple Music",
+		Collection{Kind: "playlist", Name: playlist.Name, Total: playlist.TotalTracks},
+		appleMusicSongs(playlist.Tracks))
+}
+
+func appleMusicArtist(ctx context.Context, req applemusic.AppleMusicRequest) (*Resolution, error) {
+	log.Debugf("Processing Apple Music artist: country=%s, artist=%s", req.Country, req.ArtistID)
+	progress(ctx, "Found an Apple Music artist, fetching top songs...")
+
+	artist, err := applemusic.GetArtistTopSongs(ctx, req.Country, req.ArtistID, config.Config.Options.ArtistTopTracks)
+	if err != nil {
+		return nil, appleMusicError("artist", err)
+	}
+	return matchCollection(ctx, "Apple Music",
+		Collection{Kind: "top songs", Name: artist.Name, Total: len(artist.Tracks)},
+		appleMusicSongs(artist.Tracks))
+}
@@ -0,0 +1,126 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+	"beatbot/deezer"
+	"beatbot/youtube"
+)
+
+// deezerPlaylistLimit caps how many tracks of a Deezer playlist are queued.
+const deezerPlaylistLimit = 15
+
+// deezerResolver plays Deezer links, share links included, by matching
+// their tracks on YouTube.
+type deezerResolver struct{}
+
+func (deezerResolver) Name() string                 { return SourceDeezer }
+func (deezerResolver) DisplayName() string          { return "Deezer" }
+func (deezerResolver) MatchesURL(query string) bool { return deezer.IsDeezerURL(query) }
+
+func (deezerResolver) Enabled() bool {
+	return config.Config != nil && config.Config.Deezer.Enabled
+}
+
+func (deezerResolver) Search(_ context.Context, _ string) ([]youtube.VideoResponse, error) {
+	return nil, ErrSearchUnsupported
+}
+
+// IsCollection reports whether query links to an album or playlist. Share
+// links only say once they're opened.
+func (deezerResolver) IsCollection(query string) bool {
+	req, err := deezer.ParseDeezerURL(query)
+	return err == nil && (req.PlaylistID != 0 || req.AlbumID != 0)
+}
+
+func (r deezerResolver) Resolve(ctx context.Context, query string) (*Resolution, error) {
+	if !r.Enabled() {
+		return nil, refuse(nil, "Deezer integration is not enabled. Ask the bot admin to set DEEZER_ENABLED=true.")
+	}
+
+	// Share links (deezer.page.link) redirect to the real deezer.com URL.
+	deezerURL, err := deezer.ResolveShortLink(ctx, query)
+	if err != nil {
+		return nil, refuse(err, "Couldn't open that Deezer share link: %v", err)
+	}
+	req, err := deezer.ParseDeezerURL(deezerURL)
+	if err != nil {
+		return nil, refuse(err, "Invalid Deezer URL: %v", err)
+	}
+
+	switch {
+	case req.PlaylistID != 0:
+		return deezerPlaylist(ctx, req.PlaylistID)
+	case req.AlbumID != 0:
+		return deezerAlbum(ctx, req.AlbumID)
+	}
+	return deezerTrack(ctx, req.TrackID)
+}
+
+// deezerSong is the song a Deezer track is matched on YouTube as.
+func deezerSong(track deezer.Track) song {
+	return song{Title: track.Title, Artists: []string{track.Artist.Name}, Duration: time.Duration(track.Duration) * time.Second}
+}
+
+func deezerSongs(tracks []deezer.Track) []song {
+	songs := make([]song, len(tracks))
+	for i, track := range tracks {
+		songs[i] = deezerSong(track)
+	}
+	return songs
+}
+
+// deezerError turns a failed Deezer lookup of a kind ("playlist", "album")
+// into a Refusal when it's something the user can fix. Deezer answers a
+// missing or private item with a DataException rather than a 404.
+func deezerError(kind string, err error) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "DataException") || strings.Contains(errMsg, "no data"):
+		return refuse(err, "That %s doesn't exist, has been deleted, or is private.", kind)
+	case strings.Contains(errMsg, "no playable tracks"):
+		return refuse(err, "That %s contains no playable tracks.", kind)
+	}
+	return fmt.Errorf("error fetching %s from Deezer: %w", kind, err)
+}
+
+func deezerTrack(ctx context.Context, trackID int) (*Resolution, error) {
+	log.Debugf("Processing Deezer track: %d", trackID)
+	detail, err := deezer.GetTrack(ctx, trackID)
+	if err != nil {
+		return nil, deezerError("track", err)
+	}
+	return matchSong(ctx, "Deezer", deezerSong(detail.Track), "YouTube video")
+}
+
+func deezerAlbum(ctx context.Context, albumID int) (*Resolution, error) {
+	log.Debugf("Processing Deezer album: %d", albumID)
+	progress(ctx, "Found a Deezer album, fetching tracks...")
+
+	album, err := deezer.GetAlbum(ctx, albumID)
+	if err != nil {
+		return nil, deezerError("album", err)
+	}
+	return matchCollection(ctx, "Deezer",
+		Collection{Kind: "album", Name: albumName(album.Name, album.Artist), Total: album.TotalTracks},
+		deezerSongs(album.Tracks))
+}
+
+func deezerPlaylist(ctx context.Context, playlistID int) (*Resolution, error) {
+	log.Debugf("Processing Deezer playlist: %d", playlistID)
+	progress(ctx, "Found a Deezer playlist, fetching tracks...")
+
+	playlist, err := deezer.GetPlaylist(ctx, playlistID, deezerPlaylistLimit)
+	if err != nil {
+		return nil, deezerError("playlist", err)
+	}
+	return matchCollection(ctx, "Deezer",
+		Collection{Kind: "playlist", Name: playlist.Name, Total: playlist.TotalTracks},
+		deezerSongs(playlist.Tracks))
+}
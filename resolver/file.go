@@ -0,0 +1,220 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/youtube"
+)
+
+const (
+	// MaxAudioFileBytes caps direct audio files. The loader decodes the
+	// whole track into memory, so this is about RAM, not bandwidth.
+	MaxAudioFileBytes = 100 << 20
+	// MaxAudioFileDuration caps how long a direct audio file can run.
+	MaxAudioFileDuration = 30 * time.Minute
+
+	fileProbeTimeout = 15 * time.Second
+)
+
+// ErrAudioFileLimit is returned for audio files over MaxAudioFileBytes or
+// MaxAudioFileDuration. The wrapped message says which limit and by how much.
+var ErrAudioFileLimit = errors.New("audio file is over the limit")
+
+// ErrNotAudio is returned when ffprobe can't read a link as audio.
+var ErrNotAudio = errors.New("that link isn't an audio file or stream I can read")
+
+// audioFileExtensions are the file types fileResolver claims links for.
+// .aac and .m3u8 are mostly radio and HLS streams.
+var audioFileExtensions = map[string]bool{
+	".mp3":  true,
+	".ogg":  true,
+	".oga":  true,
+	".opus": true,
+	".flac": true,
+	".wav":  true,
+	".m4a":  true,
+	".aac":  true,
+	".m3u8": true,
+}
+
+// streamMounts are the usual last path segments of Icecast and SHOUTcast
+// stream links, which often have no file extension.
+var streamMounts = map[string]bool{
+	"stream": true,
+	"live":   true,
+	"listen": true,
+	";":      true,
+}
+
+// fileResolver plays direct links to audio files (including Discord
+// attachments) and internet radio streams without going through yt-dlp. It
+// probes the link up front: files over the size or length limits are refused
+// before they're queued, and anything without a length is a live stream.
+type fileResolver struct{}
+
+func (fileResolver) Name() string        { return youtube.SourceFile }
+func (fileResolver) DisplayName() string { return "Audio file" }
+
+func (fileResolver) MatchesURL(query string) bool {
+	u, err := url.Parse(strings.TrimSpace(query))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return false
+	}
+	return audioFileExtensions[strings.ToLower(path.Ext(u.Path))] ||
+		streamMounts[strings.ToLower(path.Base(u.Path))]
+}
+
+func (r fileResolver) Search(ctx context.Context, query string) ([]youtube.VideoResponse, error) {
+	if !r.MatchesURL(query) {
+		return nil, ErrSearchUnsupported
+	}
+	video, err := ProbeAudio(ctx, strings.TrimSpace(query))
+	if err != nil {
+		return nil, err
+	}
+	return []youtube.VideoResponse{video}, nil
+}
+
+// ProbeAudio builds the track for an audio link whatever its URL looks like,
+// e.g. a podcast enclosure behind a tracking redirect. Like links /play
+// recognises, it's refused with ErrAudioFileLimit when too big or too long
+// and ErrNotAudio when ffprobe can't read it.
+func ProbeAudio(ctx context.Context, query string) (youtube.VideoResponse, error) {
+	// The link goes to ffprobe and later ffmpeg, which would read local
+	// files or take a leading "-" as an option.
+	if u, err := url.Parse(query); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return youtube.VideoResponse{}, ErrNotAudio
+	}
+
+	span := sentry.StartSpan(ctx, "resolver.file")
+	span.Description = "Probe direct audio file or stream"
+	defer span.Finish()
+
+	ctx, cancel := context.WithTimeout(span.Context(), fileProbeTimeout)
+	defer cancel()
+	logger := log.WithFields(log.Fields{"module": "resolver", "source": youtube.SourceFile})
+
+	if size, err := remoteFileSize(ctx, query); err != nil {
+		logger.Warnf("HEAD request for audio file failed: %v", err)
+	} else if size > MaxAudioFileBytes {
+		span.Status = sentry.SpanStatusInvalidArgument
+		return youtube.VideoResponse{}, fmt.Errorf("%w: it's %d MB and files can be up to %d MB", ErrAudioFileLimit, size>>20, MaxAudioFileBytes>>20)
+	}
+
+	output, err := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration:format_tags=title,artist,icy-name",
+		"-of", "default=noprint_wrappers=1",
+		query).Output()
+	if err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		logger.Warnf("ffprobe failed: %v", err)
+		return youtube.VideoResponse{}, ErrNotAudio
+	}
+
+	probe := parseFileProbe(string(output))
+	if probe.duration > MaxAudioFileDuration {
+		span.Status = sentry.SpanStatusInvalidArgument
+		return youtube.VideoResponse{}, fmt.Errorf("%w: it's %d minutes long and files can be up to %d minutes", ErrAudioFileLimit,
+			int(probe.duration.Minutes()), int(MaxAudioFileDuration.Minutes()))
+	}
+
+	span.Status = sentry.SpanStatusOK
+	return fileVideo(query, probe), nil
+}
+
+// remoteFileSize asks the server how big the file is. Returns -1 when the
+// server doesn't say.
+func remoteFileSize(ctx context.Context, fileURL string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, fileURL, nil)
+	if err != nil {
+		return -1, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return -1, fmt.Errorf("HEAD returned %s", resp.Status)
+	}
+	return resp.ContentLength, nil
+}
+
+// fileProbe is what ffprobe reported about an audio file. A zero duration
+// means a live stream.
+type fileProbe struct {
+	duration    time.Duration
+	title       string
+	artist      string
+	stationName string // icy-name, sent by Icecast and SHOUTcast servers
+}
+
+// parseFileProbe parses ffprobe's key=value output (duration=…, TAG:title=…).
+func parseFileProbe(output string) fileProbe {
+	var probe fileProbe
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "duration":
+			if secs, err := strconv.ParseFloat(value, 64); err == nil {
+				probe.duration = time.Duration(secs * float64(time.Second)).Round(time.Second)
+			}
+		case "tag:title":
+			probe.title = value
+		case "tag:artist":
+			probe.artist = value
+		case "tag:icy-name":
+			probe.stationName = value
+		}
+	}
+	return probe
+}
+
+// fileVideo builds the track for an audio file or stream link. The ID hashes
+// the link without its query string, so the same file is recognised as
+// already queued even when a signed URL's parameters differ.
+func fileVideo(fileURL string, probe fileProbe) youtube.VideoResponse {
+	u, _ := url.Parse(fileURL)
+	sum := sha1.Sum([]byte(u.Host + u.Path))
+	live := probe.duration <= 0
+
+	title, artist := probe.title, probe.artist
+	switch {
+	case live && probe.stationName != "":
+		// A stream's tags describe whatever song is on right now.
+		title, artist = probe.stationName, ""
+	case live && title == "":
+		title = u.Host
+	case title == "":
+		name := path.Base(u.Path)
+		title = strings.TrimSpace(strings.ReplaceAll(strings.TrimSuffix(name, path.Ext(name)), "_", " "))
+	}
+
+	return youtube.VideoResponse{
+		VideoID:     youtube.SourceFile + ":" + hex.EncodeToString(sum[:6]),
+		Title:       title,
+		ChannelName: artist,
+		Duration:    probe.duration,
+		Source:      youtube.SourceFile,
+		URL:         fileURL,
+		Live:        live,
+	}
+}
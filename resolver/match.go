@@ -0,0 +1,270 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/youtube"
+)
+
+// song is a track from a music service that doesn't give out audio
+// (Spotify, Apple Music, Deezer). It's played by finding it on YouTube.
+type song struct {
+	Title    string
+	Artists  []string
+	Duration time.Duration // 0 when the service didn't report one
+	ISRC     string        // "" when unknown
+}
+
+// query is the YouTube search for the song.
+func (s song) query() string {
+	return strings.Join(s.Artists, ", ") + " - " + s.Title
+}
+
+// matchTolerance is how far a YouTube result's length may be from the
+// song's for it to count as the same recording.
+const matchTolerance = 10 * time.Second
+
+// lengthsMatch reports whether two known lengths are within matchTolerance
+// of each other.
+func lengthsMatch(a, b time.Duration) bool {
+	if a <= 0 || b <= 0 {
+		return false
+	}
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= matchTolerance
+}
+
+// preferDuration moves the first of the top few results whose length is
+// within matchTolerance of target to the front, so a search that ranks a
+// music video or extended cut first still queues the album version. The
+// rest keep their order. Returns videos unchanged when target is unknown or
+// nothing matches.
+func preferDuration(videos []youtube.VideoResponse, target time.Duration) []youtube.VideoResponse {
+	if target <= 0 {
+		return videos
+	}
+	for i, video := range videos[:min(len(videos), 5)] {
+		if lengthsMatch(video.Duration, target) {
+			return moveToFront(videos, i)
+		}
+	}
+	return videos
+}
+
+// moveToFront returns videos with the i'th moved to the front and the rest
+// in their original order.
+func moveToFront(videos []youtube.VideoResponse, i int) []youtube.VideoResponse {
+	if i == 0 {
+		return videos
+	}
+	reordered := make([]youtube.VideoResponse, 0, len(videos))
+	reordered = append(reordered, videos[i])
+	reordered = append(reordered, videos[:i]...)
+	return append(reordered, videos[i+1:]...)
+}
+
+// channelKey lowercases a channel or artist name and drops the decorations
+// labels add to official channels ("Adele - Topic", "AdeleVEVO", "Adele
+// Official"), so the two can be compared.
+func channelKey(name string) string {
+	name = strings.ToLower(name)
+	name = strings.TrimSuffix(name, " - topic")
+	name = strings.TrimSuffix(name, "vevo")
+	name = strings.TrimSuffix(name, " official")
+	return strings.Join(strings.Fields(name), "")
+}
+
+// isOfficialUpload reports whether video comes from one of the artists' own
+// channels, including YouTube's auto-generated "Artist - Topic" channel that
+// hosts label-provided album audio.
+func isOfficialUpload(video youtube.VideoResponse, artists []string) bool {
+	channel := channelKey(video.ChannelName)
+	if channel == "" {
+		return false
+	}
+	for _, artist := range artists {
+		if channelKey(artist) == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// preferOfficial moves the first of the top few results that's an official
+// upload of the right length to the front, so an import queues the artist's
+// own audio rather than a fan re-upload. Without one it falls back to
+// preferDuration. When target is unknown any official upload counts.
+func preferOfficial(videos []youtube.VideoResponse, artists []string, target time.Duration) []youtube.VideoResponse {
+	for i, video := range videos[:min(len(videos), 5)] {
+		if isOfficialUpload(video, artists) && (target <= 0 || lengthsMatch(video.Duration, target)) {
+			return moveToFront(videos, i)
+		}
+	}
+	return preferDuration(videos, target)
+}
+
+// matchOnYouTube searches YouTube for a song and ranks the results with
+// preferOfficial. When the best result still isn't an official upload of
+// the right length and the song has an ISRC, it also searches the ISRC:
+// label uploads list it in their description, so that search usually finds
+// the exact recording. An ISRC hit only wins if its length matches too.
+func matchOnYouTube(ctx context.Context, s song) []youtube.VideoResponse {
+	videos := preferOfficial(youtube.Query(ctx, s.query()), s.Artists, s.Duration)
+	if s.ISRC == "" || s.Duration <= 0 {
+		return videos
+	}
+	if len(videos) > 0 && isOfficialUpload(videos[0], s.Artists) && lengthsMatch(videos[0].Duration, s.Duration) {
+		return videos
+	}
+
+	for _, candidate := range youtube.Query(ctx, s.ISRC) {
+		if !lengthsMatch(candidate.Duration, s.Duration) {
+			continue
+		}
+		log.Debugf("ISRC %s matched %q (%s)", s.ISRC, candidate.Title, candidate.VideoID)
+		matched := []youtube.VideoResponse{candidate}
+		for _, video := range videos {
+			if video.VideoID != candidate.VideoID {
+				matched = append(matched, video)
+			}
+		}
+		return matched
+	}
+	return videos
+}
+
+// Match confidence grades for song → YouTube matches, logged per track.
+const (
+	matchHigh   = "high"
+	matchMedium = "medium"
+	matchLow    = "low"
+)
+
+// matchConfidence grades how likely a YouTube result is the song: high when
+// the title contains the song's name and the lengths agree, medium when only
+// one of those holds (or the length is unknown but the title matches), low
+// otherwise.
+func matchConfidence(s song, video youtube.VideoResponse) string {
+	titleMatch := strings.Contains(strings.ToLower(video.Title), strings.ToLower(s.Title))
+	lengthMatch := lengthsMatch(video.Duration, s.Duration)
+
+	switch {
+	case titleMatch && lengthMatch:
+		return matchHigh
+	case titleMatch || lengthMatch:
+		return matchMedium
+	}
+	return matchLow
+}
+
+// albumName names an album with its artist, when known.
+func albumName(name, artist string) string {
+	if artist == "" {
+		return name
+	}
+	return name + " by " + artist
+}
+
+// matchSong resolves a single song link: it reports the song, finds it on
+// YouTube, and labels the match with label.
+func matchSong(ctx context.Context, service string, s song, label string) (*Resolution, error) {
+	artists := strings.Join(s.Artists, ", ")
+	log.Debugf("Converted %s track '%s' by '%s' to YouTube query: %s", service, s.Title, artists, s.query())
+	progress(ctx, "Found **%s** by **%s** on %s, searching YouTube...", s.Title, artists, service)
+
+	videos := matchOnYouTube(ctx, s)
+	if len(videos) == 0 {
+		log.Warnf("No YouTube results found for %s track: %s", service, s.query())
+		return nil, refuse(nil, "Couldn't find **%s** by **%s** on YouTube", s.Title, artists)
+	}
+	log.Debugf("Found YouTube match: %s (ID: %s)", videos[0].Title, videos[0].VideoID)
+	return &Resolution{Tracks: videos, Label: label}, nil
+}
+
+// matchProgressInterval is how often a collection import reports how many
+// tracks have been matched.
+const matchProgressInterval = 2 * time.Second
+
+// matchCollection finds each of a collection's songs on YouTube, ten at a
+// time, and returns the collection's resolution with the best matches in
+// order. A long playlist reports its progress now and then so it doesn't
+// look stuck.
+func matchCollection(ctx context.Context, service string, c Collection, songs []song) (*Resolution, error) {
+	searchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	span := sentry.StartSpan(searchCtx, "youtube.parallel_search")
+	span.Description = fmt.Sprintf("Parallel YouTube search for %s %s tracks", service, c.Kind)
+	span.SetTag("track_count", strconv.Itoa(len(songs)))
+	defer span.Finish()
+
+	matches := make([]*youtube.VideoResponse, len(songs))
+	done := make(chan struct{}, len(songs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 10) // Limit to 10 concurrent YouTube API searches
+
+	for i, s := range songs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { done <- struct{}{} }()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			videos := matchOnYouTube(searchCtx, s)
+			if len(videos) == 0 {
+				log.Warnf("No YouTube results for %s %s track: %s", service, c.Kind, s.query())
+				return
+			}
+			if confidence := matchConfidence(s, videos[0]); confidence == matchLow {
+				log.Warnf("Low-confidence match for %s %s track %d %q: %q (%s, expected %s)",
+					service, c.Kind, i+1, s.query(), videos[0].Title, videos[0].Duration, s.Duration)
+			} else {
+				log.Debugf("%s match for %s %s track %d %q: %q", confidence, service, c.Kind, i+1, s.query(), videos[0].Title)
+			}
+			matches[i] = &videos[0]
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	finished := 0
+	lastProgress := time.Now()
+	for range done {
+		finished++
+		if finished < len(songs) && time.Since(lastProgress) >= matchProgressInterval {
+			lastProgress = time.Now()
+			progress(ctx, "Matching **%s** on YouTube... %d/%d", c.Name, finished, len(songs))
+		}
+	}
+
+	var videos []youtube.VideoResponse
+	for _, match := range matches {
+		if match != nil {
+			videos = append(videos, *match)
+		}
+	}
+	c.Missing = len(songs) - len(videos)
+	span.SetData("found_count", len(videos))
+	span.SetData("not_found_count", c.Missing)
+	log.Debugf("Found %d/%d tracks on YouTube for %s %s '%s'", len(videos), len(songs), service, c.Kind, c.Name)
+
+	if len(videos) == 0 {
+		return nil, refuse(nil, "Couldn't find any tracks from **%s** on YouTube.", c.Name)
+	}
+	return &Resolution{Tracks: videos, Collection: &c}, nil
+}
@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"beatbot/youtube"
+)
+
+func TestPreferDuration(t *testing.T) {
+	videos := []youtube.VideoResponse{
+		{VideoID: "mv", Duration: 5 * time.Minute},
+		{VideoID: "live", Duration: 4*time.Minute + 30*time.Second},
+		{VideoID: "audio", Duration: 3*time.Minute + 35*time.Second},
+		{VideoID: "lyrics", Duration: 3*time.Minute + 32*time.Second},
+	}
+	ids := func(vs []youtube.VideoResponse) string {
+		s := ""
+		for _, v := range vs {
+			s += v.VideoID + " "
+		}
+		return s
+	}
+
+	if got := ids(preferDuration(videos, 3*time.Minute+30*time.Second)); got != "audio mv live lyrics " {
+		t.Errorf("preferDuration = %s, want the closest-length result first", got)
+	}
+	if got := ids(preferDuration(videos, 0)); got != "mv live audio lyrics " {
+		t.Errorf("unknown length reordered results: %s", got)
+	}
+	if got := ids(preferDuration(videos, 10*time.Minute)); got != "mv live audio lyrics " {
+		t.Errorf("no match reordered results: %s", got)
+	}
+}
+
+func TestIsOfficialUpload(t *testing.T) {
+	artists := []string{"Daft Punk", "Pharrell Williams"}
+	tests := []struct {
+		channel string
+		want    bool
+	}{
+		{"Daft Punk - Topic", true},
+		{"daftpunkVEVO", true},
+		{"Pharrell Williams Official", true},
+		{"Daft Punk Fan Uploads", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isOfficialUpload(youtube.VideoResponse{ChannelName: tt.channel}, artists); got != tt.want {
+			t.Errorf("isOfficialUpload(%q) = %v, want %v", tt.channel, got, tt.want)
+		}
+	}
+}
+
+func TestPreferOfficial(t *testing.T) {
+	artists := []string{"Adele"}
+	videos := []youtube.VideoResponse{
+		{VideoID: "fan", ChannelName: "Lyrics Hub", Duration: 4*time.Minute + 55*time.Second},
+		{VideoID: "mv", ChannelName: "AdeleVEVO", Duration: 6 * time.Minute},
+		{VideoID: "topic", ChannelName: "Adele - Topic", Duration: 4*time.Minute + 55*time.Second},
+	}
+	ids := func(vs []youtube.VideoResponse) string {
+		s := ""
+		for _, v := range vs {
+			s += v.VideoID + " "
+		}
+		return s
+	}
+
+	if got := ids(preferOfficial(videos, artists, 4*time.Minute+55*time.Second)); got != "topic fan mv " {
+		t.Errorf("preferOfficial = %s, want the official upload of the right length first", got)
+	}
+	// Unknown length: the first official upload wins.
+	if got := ids(preferOfficial(videos, artists, 0)); got != "mv fan topic " {
+		t.Errorf("unknown length = %s", got)
+	}
+	// No official upload: same as preferDuration.
+	if got := ids(preferOfficial(videos, []string{"Someone Else"}, 6*time.Minute)); got != "mv fan topic " {
+		t.Errorf("no official upload = %s", got)
+	}
+}
+
+func TestMatchConfidence(t *testing.T) {
+	track := song{Title: "Blue Monday", Duration: 7*time.Minute + 29*time.Second}
+	tests := []struct {
+		video youtube.VideoResponse
+		want  string
+	}{
+		{youtube.VideoResponse{Title: "New Order - Blue Monday (Official)", Duration: 7*time.Minute + 30*time.Second}, matchHigh},
+		{youtube.VideoResponse{Title: "New Order - Blue Monday '88", Duration: 4 * time.Minute}, matchMedium},
+		{youtube.VideoResponse{Title: "New Order - Blue Monday"}, matchMedium},
+		{youtube.VideoResponse{Title: "Synthpop mix", Duration: 7*time.Minute + 25*time.Second}, matchMedium},
+		{youtube.VideoResponse{Title: "Synthpop mix", Duration: time.Hour}, matchLow},
+	}
+	for _, tt := range tests {
+		if got := matchConfidence(track, tt.video); got != tt.want {
+			t.Errorf("matchConfidence(%q, %v) = %s, want %s", tt.video.Title, tt.video.Duration, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/youtube"
+)
+
+// Source identifiers accepted by the /play source option.
+const (
+	SourceAuto       = "auto"
+	SourceYouTube    = "youtube"
+	SourceSoundCloud = "soundcloud"
+	SourceBandcamp   = "bandcamp"
+	SourceTwitch     = "twitch"
+	SourceSpotify    = "spotify"
+	SourceAppleMusic = "applemusic"
+	SourceDeezer     = "deezer"
+)
+
+// ErrSearchUnsupported is returned when a resolver can only handle direct
+// links (e.g. Bandcamp) and was asked to run a free-text search.
+var ErrSearchUnsupported = errors.New("source does not support search")
+
+// Resolver turns a user query into playable tracks for one source.
+type Resolver interface {
+	// Name returns the source identifier ("youtube", "soundcloud", ...).
+	Name() string
+
+	// DisplayName returns the user-facing source name ("SoundCloud").
+	DisplayName() string
+
+	// MatchesURL reports whether query is a link this resolver owns.
+	MatchesURL(query string) bool
+
+	// Search resolves a free-text query or a matching URL into tracks,
+	// best match first.
+	Search(ctx context.Context, query string) ([]youtube.VideoResponse, error)
+}
+
+// LinkResolver is implemented by resolvers whose links take more than a
+// search to play, like music services matched on YouTube and playlists that
+// are queued whole.
+type LinkResolver interface {
+	// Resolve turns a link this resolver owns into tracks.
+	Resolve(ctx context.Context, query string) (*Resolution, error)
+}
+
+// Toggle is implemented by resolvers that only work once the bot admin
+// turns them on.
+type Toggle interface {
+	Enabled() bool
+}
+
+// Collection describes a playlist, album or set whose tracks are all
+// queued.
+type Collection struct {
+	// Kind is what the link points at ("playlist", "album").
+	Kind string
+
+	// Name is the collection's title, with the artist for albums.
+	Name string
+
+	// Total is how many tracks the collection holds, which can be more
+	// than were fetched.
+	Total int
+
+	// Missing counts tracks that couldn't be matched to anything playable.
+	Missing int
+}
+
+// Resolution is what a query resolved to: the candidates for one song, best
+// first, or every track of a collection.
+type Resolution struct {
+	Tracks []youtube.VideoResponse
+
+	// Resolver is the source that produced Tracks.
+	Resolver Resolver
+
+	// Collection is set when every track should be queued.
+	Collection *Collection
+
+	// Search reports a free-text query rather than a link, where the other
+	// results make fair fallbacks and picker choices.
+	Search bool
+
+	// Label describes a single track in replies ("SoundCloud track").
+	Label string
+
+	// Start is where the link asked playback to begin.
+	Start time.Duration
+}
+
+// Refusal is an error whose message is written for the user, like a
+// private playlist or a source that can't search. Other errors are
+// failures.
+type Refusal struct {
+	Reason string
+	Err    error
+}
+
+func (r *Refusal) Error() string { return r.Reason }
+func (r *Refusal) Unwrap() error { return r.Err }
+
+// refuse returns a Refusal for err with a formatted reason.
+func refuse(err error, format string, args ...any) error {
+	return &Refusal{Reason: fmt.Sprintf(format, args...), Err: err}
+}
+
+// CollectionResolver is implemented by resolvers whose links can point at
+// several tracks at once, like SoundCloud sets or Bandcamp albums.
+type CollectionResolver interface {
+	IsCollection(query string) bool
+}
+
+// Registry holds resolvers in priority order. The first entry is the
+// default for auto searches.
+type Registry struct {
+	resolvers []Resolver
+}
+
+// NewRegistry creates a registry with the given resolvers in priority order.
+func NewRegistry(resolvers ...Resolver) *Registry {
+	return &Registry{resolvers: resolvers}
+}
+
+// Register appends a resolver to the registry.
+func (r *Registry) Register(res Resolver) {
+	r.resolvers = append(r.resolvers, res)
+}
+
+// Get returns the resolver with the given name, or nil.
+func (r *Registry) Get(name string) Resolver {
+	for _, res := range r.resolvers {
+		if res.Name() == name {
+			return res
+		}
+	}
+	return nil
+}
+
+// ForURL returns the resolver that owns the given link, or nil.
+func (r *Registry) ForURL(query string) Resolver {
+	for _, res := range r.resolvers {
+		if res.MatchesURL(query) {
+			return res
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether the named resolver exists and is switched on.
+func (r *Registry) Enabled(name string) bool {
+	res := r.Get(name)
+	if res == nil {
+		return false
+	}
+	toggle, ok := res.(Toggle)
+	return !ok || toggle.Enabled()
+}
+
+// IsCollection reports whether query is a link to a set or album, whose
+// Search results should all be queued rather than just the first.
+func (r *Registry) IsCollection(query string) bool {
+	res, ok := r.ForURL(query).(CollectionResolver)
+	return ok && res.IsCollection(query)
+}
+
+// Search resolves query honoring the preferred source. Links always go to
+// the resolver that owns them since they're unambiguous. A forced source is
+// used as-is; auto tries each searchable resolver in priority order until
+// one returns results. Returns the resolver that produced the results.
+func (r *Registry) Search(ctx context.Context, query string, preference string) ([]youtube.VideoResponse, Resolver, error) {
+	videos, res, err := r.search(ctx, query, preference)
+	// Clean titles once at resolve time; the raw title is kept on the track.
+	for i := range videos {
+		videos[i].Normalize(false)
+	}
+	return videos, res, err
+}
+
+// Resolve turns query into tracks to queue. Links go to the resolver that
+// owns them, which may return a whole collection; anything else searches
+// for one song, honoring preference as in Search. Problems the user can
+// act on, like a private playlist, come back as a *Refusal.
+func (r *Registry) Resolve(ctx context.Context, query string, preference string) (*Resolution, error) {
+	resolution, err := r.resolve(ctx, query, preference)
+	if err != nil {
+		return nil, err
+	}
+	finish(resolution)
+	return resolution, nil
+}
+
+func (r *Registry) resolve(ctx context.Context, query string, preference string) (*Resolution, error) {
+	res := r.ForURL(query)
+	if res == nil {
+		videos, used, err := r.search(ctx, query, preference)
+		if err != nil {
+			return nil, searchRefusal(err, used)
+		}
+		return &Resolution{Tracks: videos, Resolver: used, Search: true}, nil
+	}
+
+	if link, ok := res.(LinkResolver); ok {
+		resolution, err := link.Resolve(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		resolution.Resolver = res
+		return resolution, nil
+	}
+
+	videos, err := res.Search(ctx, query)
+	if err != nil {
+		return nil, searchRefusal(err, res)
+	}
+	resolution := &Resolution{Tracks: videos, Resolver: res}
+	// SoundCloud sets and Bandcamp albums queue every track they hold.
+	if r.IsCollection(query) && len(videos) > 1 {
+		resolution.Collection = &Collection{Total: len(videos)}
+	}
+	return resolution, nil
+}
+
+// finish cleans the resolution's titles and labels a single track by its
+// source when the resolver didn't. The raw title is kept on the track.
+func finish(resolution *Resolution) {
+	for i := range resolution.Tracks {
+		resolution.Tracks[i].Normalize(false)
+	}
+	if resolution.Label != "" {
+		return
+	}
+	switch res := resolution.Resolver; {
+	case len(resolution.Tracks) > 0 && resolution.Tracks[0].Live:
+		resolution.Label = "live stream"
+	case res == nil || res.Name() == SourceYouTube:
+		resolution.Label = "YouTube video"
+	case res.Name() == youtube.SourceFile:
+		resolution.Label = "audio file"
+	default:
+		resolution.Label = res.DisplayName() + " track"
+	}
+}
+
+// searchRefusal turns the search errors a user can act on into a Refusal.
+func searchRefusal(err error, used Resolver) error {
+	switch {
+	case errors.Is(err, ErrSearchUnsupported) && used != nil:
+		return refuse(err, "%s doesn't support search — paste a %s link instead.", used.DisplayName(), used.DisplayName())
+	case errors.Is(err, ErrAudioFileLimit), errors.Is(err, ErrNotAudio):
+		return refuse(err, "Can't play that: %v.", err)
+	}
+	return err
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context in which a slow link, like a playlist that
+// has to be matched on YouTube track by track, reports what it's doing to
+// report.
+func WithProgress(ctx context.Context, report func(status string)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// progress reports a status line to ctx's WithProgress callback, if any.
+func progress(ctx context.Context, format string, args ...any) {
+	if report, ok := ctx.Value(progressKey{}).(func(string)); ok {
+		report(fmt.Sprintf(format, args...))
+	}
+}
+
+func (r *Registry) search(ctx context.Context, query string, preference string) ([]youtube.VideoResponse, Resolver, error) {
+	if res := r.ForURL(query); res != nil {
+		videos, err := res.Search(ctx, query)
+		return videos, res, err
+	}
+
+	preference = strings.ToLower(strings.TrimSpace(preference))
+	if preference != "" && preference != SourceAuto {
+		res := r.Get(preference)
+		if res == nil {
+			return nil, nil, fmt.Errorf("unknown source %q", preference)
+		}
+		videos, err := res.Search(ctx, query)
+		return videos, res, err
+	}
+
+	var lastErr error
+	for _, res := range r.resolvers {
+		if toggle, ok := res.(Toggle); ok && !toggle.Enabled() {
+			continue
+		}
+		videos, err := res.Search(ctx, query)
+		if errors.Is(err, ErrSearchUnsupported) {
+			continue
+		}
+		if err != nil {
+			log.WithFields(log.Fields{"module": "resolver", "source": res.Name()}).Warnf("search failed: %v", err)
+			lastErr = err
+			continue
+		}
+		if len(videos) > 0 {
+			return videos, res, nil
+		}
+	}
+	if len(r.resolvers) == 0 {
+		return nil, nil, nil
+	}
+	return nil, r.resolvers[0], lastErr
+}
+
+var defaultRegistry = NewRegistry(
+	youtubeResolver{},
+	newYtDlpResolver(SourceSoundCloud, "SoundCloud", "scsearch", "soundcloud.com", "/sets/"),
+	newYtDlpResolver(SourceBandcamp, "Bandcamp", "", "bandcamp.com", "/album/"),
+	newYtDlpResolver(SourceTwitch, "Twitch", "", "twitch.tv", ""),
+	fileResolver{},
+	spotifyResolver{},
+	appleMusicResolver{},
+	deezerResolver{},
+)
+
+// Register adds a resolver to the default registry.
+func Register(res Resolver) { defaultRegistry.Register(res) }
+
+// Get returns the named resolver from the default registry, or nil.
+func Get(name string) Resolver { return defaultRegistry.Get(name) }
+
+// ForURL returns the default registry's resolver for a link, or nil.
+func ForURL(query string) Resolver { return defaultRegistry.ForURL(query) }
+
+// Enabled reports whether the named resolver in the default registry is
+// switched on.
+func Enabled(name string) bool { return defaultRegistry.Enabled(name) }
+
+// IsCollection reports whether query is a set or album link in the default
+// registry. See Registry.IsCollection.
+func IsCollection(query string) bool { return defaultRegistry.IsCollection(query) }
+
+// Search resolves query against the default registry. See Registry.Search.
+func Search(ctx context.Context, query string, preference string) ([]youtube.VideoResponse, Resolver, error) {
+	return defaultRegistry.Search(ctx, query, preference)
+}
+
+// Resolve resolves query against the default registry. See
+// Registry.Resolve.
+func Resolve(ctx context.Context, query string, preference string) (*Resolution, error) {
+	return defaultRegistry.Resolve(ctx, query, preference)
+}
@@ -0,0 +1,353 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"beatbot/youtube"
+)
+
+type fakeResolver struct {
+	name    string
+	host    string
+	results []youtube.VideoResponse
+	err     error
+	calls   int
+}
+
+func (f *fakeResolver) Name() string        { return f.name }
+func (f *fakeResolver) DisplayName() string { return strings.ToUpper(f.name) }
+func (f *fakeResolver) MatchesURL(query string) bool {
+	return f.host != "" && strings.Contains(query, f.host)
+}
+func (f *fakeResolver) Search(_ context.Context, _ string) ([]youtube.VideoResponse, error) {
+	f.calls++
+	return f.results, f.err
+}
+
+func TestRegistrySearchAutoFallsThrough(t *testing.T) {
+	yt := &fakeResolver{name: SourceYouTube}
+	bc := &fakeResolver{name: SourceBandcamp, host: "bandcamp.com/", err: ErrSearchUnsupported}
+	sc := &fakeResolver{name: SourceSoundCloud, host: "soundcloud.com/", results: []youtube.VideoResponse{{VideoID: "soundcloud:1"}}}
+	reg := NewRegistry(yt, bc, sc)
+
+	videos, used, err := reg.Search(context.Background(), "some song", SourceAuto)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if used != sc || len(videos) != 1 {
+		t.Errorf("Search() used %v with %d results, want soundcloud with 1", used, len(videos))
+	}
+	if yt.calls != 1 || bc.calls != 1 {
+		t.Errorf("expected youtube and bandcamp to be tried first, got calls yt=%d bc=%d", yt.calls, bc.calls)
+	}
+}
+
+func TestRegistrySearchHonorsPreference(t *testing.T) {
+	yt := &fakeResolver{name: SourceYouTube, results: []youtube.VideoResponse{{VideoID: "abc"}}}
+	sc := &fakeResolver{name: SourceSoundCloud, host: "soundcloud.com/", results: []youtube.VideoResponse{{VideoID: "soundcloud:1"}}}
+	reg := NewRegistry(yt, sc)
+
+	_, used, err := reg.Search(context.Background(), "some song", SourceSoundCloud)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if used != sc || yt.calls != 0 {
+		t.Errorf("Search() used %v (yt calls %d), want soundcloud only", used, yt.calls)
+	}
+}
+
+func TestRegistrySearchURLOverridesPreference(t *testing.T) {
+	yt := &fakeResolver{name: SourceYouTube}
+	sc := &fakeResolver{name: SourceSoundCloud, host: "soundcloud.com/", results: []youtube.VideoResponse{{VideoID: "soundcloud:1"}}}
+	reg := NewRegistry(yt, sc)
+
+	_, used, _ := reg.Search(context.Background(), "https://soundcloud.com/artist/track", SourceYouTube)
+	if used != sc {
+		t.Errorf("Search() used %v, want the resolver owning the link", used)
+	}
+}
+
+func TestRegistrySearchForcedLinkOnlySource(t *testing.T) {
+	bc := &fakeResolver{name: SourceBandcamp, host: "bandcamp.com/", err: ErrSearchUnsupported}
+	reg := NewRegistry(bc)
+
+	_, _, err := reg.Search(context.Background(), "some song", SourceBandcamp)
+	if !errors.Is(err, ErrSearchUnsupported) {
+		t.Errorf("Search() error = %v, want ErrSearchUnsupported", err)
+	}
+}
+
+// fakeLinkResolver resolves its links to a fixed resolution.
+type fakeLinkResolver struct {
+	fakeResolver
+	resolution *Resolution
+	enabled    bool
+}
+
+func (f *fakeLinkResolver) Resolve(_ context.Context, _ string) (*Resolution, error) {
+	return f.resolution, f.err
+}
+
+func (f *fakeLinkResolver) Enabled() bool { return f.enabled }
+
+func TestRegistryResolveLinks(t *testing.T) {
+	playlist := &fakeLinkResolver{
+		fakeResolver: fakeResolver{name: SourceSpotify, host: "open.spotify.com/"},
+		resolution: &Resolution{
+			Tracks:     []youtube.VideoResponse{{VideoID: "a"}, {VideoID: "b"}},
+			Collection: &Collection{Kind: "playlist", Name: "Mix", Total: 2},
+		},
+		enabled: true,
+	}
+	sc := &fakeResolver{name: SourceSoundCloud, host: "soundcloud.com/", results: []youtube.VideoResponse{{VideoID: "soundcloud:1"}}}
+	reg := NewRegistry(&fakeResolver{name: SourceYouTube}, sc, playlist)
+
+	got, err := reg.Resolve(context.Background(), "https://open.spotify.com/playlist/1", SourceAuto)
+	if err != nil {
+		t.Fatalf("Resolve(playlist) error = %v", err)
+	}
+	if got.Resolver != playlist || got.Collection == nil || len(got.Tracks) != 2 || got.Search {
+		t.Errorf("Resolve(playlist) = %+v, want the link resolver's collection", got)
+	}
+
+	got, err = reg.Resolve(context.Background(), "https://soundcloud.com/artist/track", SourceAuto)
+	if err != nil {
+		t.Fatalf("Resolve(track) error = %v", err)
+	}
+	if got.Resolver != sc || got.Collection != nil || got.Search || got.Label != "SOUNDCLOUD track" {
+		t.Errorf("Resolve(track) = %+v, want one labelled SoundCloud track", got)
+	}
+
+	got, err = reg.Resolve(context.Background(), "some song", SourceSoundCloud)
+	if err != nil {
+		t.Fatalf("Resolve(search) error = %v", err)
+	}
+	if !got.Search || got.Resolver != sc {
+		t.Errorf("Resolve(search) = %+v, want a SoundCloud search", got)
+	}
+}
+
+func TestRegistryResolveRefusals(t *testing.T) {
+	bc := &fakeResolver{name: SourceBandcamp, host: "bandcamp.com/", err: ErrSearchUnsupported}
+	reg := NewRegistry(bc)
+
+	_, err := reg.Resolve(context.Background(), "some song", SourceBandcamp)
+	var refusal *Refusal
+	if !errors.As(err, &refusal) || !errors.Is(err, ErrSearchUnsupported) {
+		t.Fatalf("Resolve() error = %v, want a Refusal wrapping ErrSearchUnsupported", err)
+	}
+	if !strings.Contains(refusal.Reason, "BANDCAMP doesn't support search") {
+		t.Errorf("Refusal reason = %q", refusal.Reason)
+	}
+
+	failing := &fakeResolver{name: SourceSoundCloud, err: errors.New("boom")}
+	_, err = NewRegistry(failing).Resolve(context.Background(), "some song", SourceSoundCloud)
+	if err == nil || errors.As(err, &refusal) {
+		t.Errorf("Resolve() error = %v, want a plain failure", err)
+	}
+}
+
+func TestRegistrySearchSkipsDisabled(t *testing.T) {
+	yt := &fakeResolver{name: SourceYouTube}
+	off := &fakeLinkResolver{fakeResolver: fakeResolver{name: SourceSpotify, results: []youtube.VideoResponse{{VideoID: "x"}}}}
+	reg := NewRegistry(yt, off)
+
+	if _, _, err := reg.Search(context.Background(), "some song", SourceAuto); err != nil || off.calls != 0 {
+		t.Errorf("Search() err = %v, disabled resolver called %d times", err, off.calls)
+	}
+	if reg.Enabled(SourceSpotify) || !reg.Enabled(SourceYouTube) || reg.Enabled(SourceAuto) {
+		t.Error("Enabled() should only report registered resolvers that are switched on")
+	}
+}
+
+func TestParseYtDlpTracks(t *testing.T) {
+	output := "123\tSong A\t185.4\tArtist\thttps://soundcloud.com/artist/song-a\tFalse\n" +
+		"456\tSong B\tNA\tNA\thttps://soundcloud.com/artist/song-b\tNA\n" +
+		"garbage line\n"
+
+	videos := parseYtDlpTracks(SourceSoundCloud, output)
+	if len(videos) != 2 {
+		t.Fatalf("len(videos) = %d, want 2", len(videos))
+	}
+	first := videos[0]
+	if first.VideoID != "soundcloud:123" || first.Source != SourceSoundCloud || first.URL != "https://soundcloud.com/artist/song-a" {
+		t.Errorf("unexpected first track: %+v", first)
+	}
+	if first.Duration != 185*time.Second || first.ChannelName != "Artist" {
+		t.Errorf("first track duration/channel = %v/%q, want 3m5s/Artist", first.Duration, first.ChannelName)
+	}
+	if videos[1].Duration != 0 || videos[1].ChannelName != "" {
+		t.Errorf("NA fields should be empty, got %+v", videos[1])
+	}
+	if first.Live || videos[1].Live {
+		t.Error("tracks should not be live")
+	}
+
+	live := parseYtDlpTracks(SourceTwitch, "v1\tJust Chatting\t0.0\tstreamer\thttps://www.twitch.tv/streamer\tTrue\n")
+	if len(live) != 1 || !live[0].Live || live[0].Duration != 0 {
+		t.Errorf("twitch live stream = %+v, want live with no duration", live)
+	}
+}
+
+func TestIsCollection(t *testing.T) {
+	reg := NewRegistry(
+		&fakeResolver{name: SourceYouTube},
+		newYtDlpResolver(SourceSoundCloud, "SoundCloud", "scsearch", "soundcloud.com", "/sets/"),
+		newYtDlpResolver(SourceBandcamp, "Bandcamp", "", "bandcamp.com", "/album/"),
+	)
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"https://soundcloud.com/artist/sets/summer-mix", true},
+		{"https://soundcloud.com/artist/track", false},
+		{"https://soundcloud.com/artist/track?in=artist/sets/summer-mix", false},
+		{"https://artist.bandcamp.com/album/debut", true},
+		{"https://artist.bandcamp.com/track/song", false},
+		{"https://www.youtube.com/watch?v=abc", false},
+		{"lofi sets/mix", false},
+	}
+	for _, tt := range tests {
+		if got := reg.IsCollection(tt.query); got != tt.want {
+			t.Errorf("IsCollection(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFileResolverMatchesURL(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"https://example.com/music/song.mp3", true},
+		{"https://cdn.discordapp.com/attachments/1/2/Track_01.FLAC?ex=abc&hm=def", true},
+		{"http://example.com/a.ogg", true},
+		{"https://example.com/a.wav#t=10", true},
+		{"http://radio.example.com:8000/stream", true},
+		{"http://radio.example.com:8000/;", true},
+		{"https://example.com/hls/station.m3u8", true},
+		{"https://example.com/livestreams", false},
+		{"https://example.com/song.mp3.html", false},
+		{"https://example.com/page?file=song.mp3", false},
+		{"ftp://example.com/song.mp3", false},
+		{"song.mp3", false},
+		{"https://soundcloud.com/artist/track", false},
+	}
+	for _, tt := range tests {
+		if got := (fileResolver{}).MatchesURL(tt.query); got != tt.want {
+			t.Errorf("MatchesURL(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestProbeAudioRefusesNonHTTP(t *testing.T) {
+	for _, link := range []string{"file:///etc/passwd", "-i/etc/passwd", "/etc/passwd", "https://"} {
+		if _, err := ProbeAudio(context.Background(), link); !errors.Is(err, ErrNotAudio) {
+			t.Errorf("ProbeAudio(%q) error = %v, want ErrNotAudio", link, err)
+		}
+	}
+}
+
+func TestParseFileProbe(t *testing.T) {
+	probe := parseFileProbe("duration=187.466000\nTAG:title=Song Name\nTAG:artist=Some Band\n")
+	if probe.duration != 187*time.Second || probe.title != "Song Name" || probe.artist != "Some Band" {
+		t.Errorf("parseFileProbe() = %+v", probe)
+	}
+
+	probe = parseFileProbe("duration=N/A\n")
+	if probe.duration != 0 || probe.title != "" {
+		t.Errorf("parseFileProbe(N/A) = %+v, want zero", probe)
+	}
+}
+
+func TestFileVideo(t *testing.T) {
+	a := fileVideo("https://cdn.discordapp.com/attachments/1/2/My_Demo%20Mix.mp3?ex=1", fileProbe{duration: time.Minute})
+	if a.Title != "My Demo Mix" || a.Source != youtube.SourceFile || a.Duration != time.Minute {
+		t.Errorf("fileVideo() = %+v", a)
+	}
+	if !strings.HasPrefix(a.VideoID, youtube.SourceFile+":") {
+		t.Errorf("fileVideo() VideoID = %q, want file: prefix", a.VideoID)
+	}
+
+	// Re-signed links to the same file get the same ID.
+	b := fileVideo("https://cdn.discordapp.com/attachments/1/2/My_Demo%20Mix.mp3?ex=2", fileProbe{title: "Tagged", duration: time.Minute})
+	if b.VideoID != a.VideoID {
+		t.Errorf("VideoID differs across query strings: %q vs %q", a.VideoID, b.VideoID)
+	}
+	if b.Title != "Tagged" {
+		t.Errorf("fileVideo() Title = %q, want the tag title", b.Title)
+	}
+	if a.Live || b.Live {
+		t.Error("files with a duration should not be live")
+	}
+}
+
+func TestFileVideoLive(t *testing.T) {
+	station := fileVideo("http://radio.example.com:8000/stream", fileProbe{
+		title: "Current Song", artist: "Current Artist", stationName: "Groove Salad",
+	})
+	if !station.Live || station.Title != "Groove Salad" || station.ChannelName != "" {
+		t.Errorf("fileVideo(station) = %+v, want live Groove Salad with no artist", station)
+	}
+
+	bare := fileVideo("http://radio.example.com:8000/;", fileProbe{})
+	if !bare.Live || bare.Title != "radio.example.com:8000" {
+		t.Errorf("fileVideo(bare stream) = %+v, want live titled by host", bare)
+	}
+}
+
+func TestTwitchLinks(t *testing.T) {
+	for _, link := range []string{
+		"https://www.twitch.tv/somestreamer",
+		"https://www.twitch.tv/videos/123456789",
+		"https://clips.twitch.tv/FunnyClipName",
+		"https://m.twitch.tv/somestreamer",
+	} {
+		res := ForURL(link)
+		if res == nil || res.Name() != SourceTwitch {
+			t.Errorf("ForURL(%q) = %v, want the Twitch resolver", link, res)
+		}
+	}
+}
+
+func TestDefaultRegistryOwnsLinks(t *testing.T) {
+	tests := []struct {
+		query      string
+		want       string
+		collection bool
+	}{
+		{"https://open.spotify.com/track/42", SourceSpotify, false},
+		{"https://open.spotify.com/playlist/42", SourceSpotify, true},
+		{"https://music.apple.com/us/album/x/1?i=2", SourceAppleMusic, false},
+		{"https://itunes.apple.com/us/album/x/1", SourceAppleMusic, true},
+		{"https://www.deezer.com/en/album/302127", SourceDeezer, true},
+		{"https://deezer.page.link/abc", SourceDeezer, false},
+		{"https://youtube.com/playlist?list=PL1", SourceYouTube, true},
+		{"https://music.youtube.com/playlist?list=RDCLAK5uy_x", SourceYouTube, true},
+		{"https://www.youtube.com/watch?v=abc123", SourceYouTube, false},
+		{"https://music.youtube.com/watch?v=abc123&list=RDAMVMabc123", SourceYouTube, false},
+		{"https://soundcloud.com/a/b", SourceSoundCloud, false},
+		{"https://on.soundcloud.com/abc", SourceSoundCloud, false},
+		{"https://nottwitch.tv/x", "", false},
+		{"https://evil.example/?u=soundcloud.com/a", "", false},
+		{"https://soundcloud.com.evil.example/a", "", false},
+		{"ftp://soundcloud.com/a/b", "", false},
+		{"never gonna give you up", "", false},
+	}
+	for _, tt := range tests {
+		var got string
+		if res := ForURL(tt.query); res != nil {
+			got = res.Name()
+		}
+		if got != tt.want {
+			t.Errorf("ForURL(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+		if IsCollection(tt.query) != tt.collection {
+			t.Errorf("IsCollection(%q) = %v, want %v", tt.query, !tt.collection, tt.collection)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+	"beatbot/spotify"
+	"beatbot/youtube"
+)
+
+// spotifyResolver plays Spotify links and searches. Spotify doesn't give out
+// audio, so each track's title and artists become a YouTube search, and
+// official uploads close to its length are preferred.
+type spotifyResolver struct{}
+
+func (spotifyResolver) Name() string        { return SourceSpotify }
+func (spotifyResolver) DisplayName() string { return "Spotify" }
+
+func (spotifyResolver) MatchesURL(query string) bool {
+	return strings.HasPrefix(query, "https://open.spotify.com/")
+}
+
+func (spotifyResolver) Enabled() bool {
+	return config.Config != nil && config.Config.Spotify.Enabled
+}
+
+// IsCollection reports whether query links to a playlist, album or artist
+// rather than a single track.
+func (spotifyResolver) IsCollection(query string) bool {
+	req, err := spotify.ParseSpotifyURL(query)
+	return err == nil && req.TrackID == ""
+}
+
+// errSpotifyDisabled refuses Spotify links and searches until the bot
+// admin turns the integration on.
+var errSpotifyDisabled = refuse(nil, "Spotify integration is not enabled. Ask the bot admin to set SPOTIFY_ENABLED=true.")
+
+// Search matches the top Spotify result for query on YouTube.
+func (r spotifyResolver) Search(ctx context.Context, query string) ([]youtube.VideoResponse, error) {
+	if !r.Enabled() {
+		return nil, errSpotifyDisabled
+	}
+	track, err := spotify.SearchTrack(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error searching Spotify: %w", err)
+	}
+	if track == nil {
+		return nil, nil
+	}
+	resolution, err := matchSong(ctx, "Spotify", spotifySong(*track), "")
+	if err != nil {
+		return nil, err
+	}
+	return resolution.Tracks, nil
+}
+
+func (r spotifyResolver) Resolve(ctx context.Context, query string) (*Resolution, error) {
+	if !r.Enabled() {
+		return nil, errSpotifyDisabled
+	}
+	req, err := spotify.ParseSpotifyURL(query)
+	if err != nil {
+		return nil, refuse(err, "Invalid Spotify URL: %v", err)
+	}
+
+	switch {
+	case req.PlaylistID != "":
+		return spotifyPlaylist(ctx, req.PlaylistID)
+	case req.AlbumID != "":
+		return spotifyAlbum(ctx, req.AlbumID)
+	case req.ArtistID != "":
+		// Artist links queue their top tracks
+		return spotifyArtist(ctx, req.ArtistID)
+	case req.TrackID != "":
+		return spotifyTrack(ctx, req.TrackID)
+	}
+	return nil, refuse(nil, "Invalid Spotify URL. Please use a track, playlist, or album URL.")
+}
+
+// spotifySong is the song a Spotify track is matched on YouTube as.
+func spotifySong(track spotify.TrackInfo) song {
+	return song{Title: track.Title, Artists: track.Artists, Duration: track.Duration, ISRC: track.ISRC}
+}
+
+// spotifyError turns a failed Spotify lookup of a kind ("playlist",
+// "album") into a Refusal when it's something the user can fix.
+func spotifyError(kind string, err error) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "not found"), strings.Contains(errMsg, "invalid id"):
+		return refuse(err, "That %s doesn't exist or has been deleted.", kind)
+	case strings.Contains(errMsg, "private"), strings.Contains(errMsg, "not accessible"):
+		return refuse(err, "That %s is private. Make it public or use track URLs instead.", kind)
+	case strings.Contains(errMsg, "empty"):
+		return refuse(err, "That %s is empty.", kind)
+	case strings.Contains(errMsg, "no playable tracks"):
+		return refuse(err, "That %s contains no playable tracks.", kind)
+	}
+	return fmt.Errorf("error fetching %s from Spotify: %w", kind, err)
+}
+
+func spotifyTrack(ctx context.Context, trackID string) (*Resolution, error) {
+	log.Tracef("Fetching Spotify track: %s", trackID)
+	track, err := spotify.GetTrack(ctx, trackID)
+	if err != nil {
+		return nil, spotifyError("track", err)
+	}
+	return matchSong(ctx, "Spotify", spotifySong(*track),
+		fmt.Sprintf("YouTube match for the Spotify track \"%s\" by %s,", track.Title, strings.Join(track.Artists, ", ")))
+}
+
+func spotifyPlaylist(ctx context.Context, playlistID string) (*Resolution, error) {
+	log.Debugf("Processing Spotify playlist: %s", playlistID)
+	progress(ctx, "Found a Spotify playlist, fetching tracks...")
+
+	playlist, err := spotify.GetPlaylistTracks(ctx, playlistID, config.Config.Spotify.PlaylistLimit)
+	if err != nil {
+		return nil, spotifyError("playlist", err)
+	}
+	return matchCollection(ctx, "Spotify",
+		Collection{Kind: "playlist", Name: playlist.Name, Total: playlist.TotalTracks},
+		spotifySongs(playlist.Tracks))
+}
+
+func spotifyAlbum(ctx context.Context, albumID string) (*Resolution, error) {
+	log.Debugf("Processing Spotify album: %s", albumID)
+	progress(ctx, "Found a Spotify album, fetching tracks...")
+
+	album, err := spotify.GetAlbumTracks(ctx, albumID, config.Config.Spotify.PlaylistLimit)
+	if err != nil {
+		return nil, spotifyError("album", err)
+	}
+	return matchCollection(ctx, "Spotify",
+		Collection{Kind: "album", Name: albumName(album.Name, album.Artist), Total: album.TotalTracks},
+		spotifySongs(album.Tracks))
+}
+
+func spotifyArtist(ctx context.Context, artistID string) (*Resolution, error) {
+	log.Debugf("Processing Spotify artist: %s", artistID)
+	progress(ctx, "Found a Spotify artist, fetching top songs...")
+
+	artistName, err := spotify.GetArtistName(ctx, artistID)
+	if err != nil {
+		return nil, spotifyError("artist", err)
+	}
+	return spotifyTopSongs(ctx, artistID, artistName)
+}
+
+// SpotifyTopSongs resolves an artist's top config.Options.ArtistTopTracks
+// tracks, matched on YouTube. Shared by /topsongs and Spotify artist links.
+func SpotifyTopSongs(ctx context.Context, artistID, artistName string) (*Resolution, error) {
+	resolution, err := spotifyTopSongs(ctx, artistID, artistName)
+	if err != nil {
+		return nil, err
+	}
+	resolution.Resolver = spotifyResolver{}
+	finish(resolution)
+	return resolution, nil
+}
+
+func spotifyTopSongs(ctx context.Context, artistID, artistName string) (*Resolution, error) {
+	tracks, err := spotify.GetArtistTopSongs(ctx, artistID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching top songs from Spotify: %w", err)
+	}
+	if len(tracks) == 0 {
+		return nil, refuse(nil, "No top songs found for **%s**.", artistName)
+	}
+	if limit := config.Config.Options.ArtistTopTracks; limit > 0 && len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+
+	songs := make([]song, len(tracks))
+	for i, track := range tracks {
+		songs[i] = spotifySong(track)
+	}
+	return matchCollection(ctx, "Spotify",
+		Collection{Kind: "top songs", Name: artistName, Total: len(songs)}, songs)
+}
+
+func spotifySongs(tracks []spotify.PlaylistTrackInfo) []song {
+	songs := make([]song, len(tracks))
+	for i, track := range tracks {
+		songs[i] = spotifySong(track.TrackInfo)
+	}
+	return songs
+}
@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/config"
+	"beatbot/youtube"
+)
+
+// youtubeResolver searches via the YouTube Data API and owns YouTube links:
+// a video plays on its own, a playlist queues its videos.
+type youtubeResolver struct{}
+
+func (youtubeResolver) Name() string        { return SourceYouTube }
+func (youtubeResolver) DisplayName() string { return "YouTube" }
+
+func (youtubeResolver) MatchesURL(query string) bool {
+	return youtube.ParseYouTubeURL(query) != youtube.YouTubeURLResult{}
+}
+
+func (youtubeResolver) Search(ctx context.Context, query string) ([]youtube.VideoResponse, error) {
+	return youtube.Query(ctx, query), nil
+}
+
+// IsCollection reports whether query links to a playlist.
+func (youtubeResolver) IsCollection(query string) bool {
+	return youtube.ParseYouTubeURL(query).PlaylistID != ""
+}
+
+func (youtubeResolver) Resolve(ctx context.Context, query string) (*Resolution, error) {
+	link := youtube.ParseYouTubeURL(query)
+	if link.PlaylistID != "" {
+		return youtubePlaylist(ctx, link.PlaylistID)
+	}
+
+	video, err := youtube.GetVideoByID(ctx, link.VideoID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting video stream: %w", err)
+	}
+	return &Resolution{Tracks: []youtube.VideoResponse{video}, Start: link.Start}, nil
+}
+
+// youtubePlaylist resolves a playlist or YouTube Music radio link to its
+// first config.Config.Youtube.PlaylistLimit videos.
+func youtubePlaylist(ctx context.Context, playlistID string) (*Resolution, error) {
+	log.Debugf("Processing YouTube playlist: %s", playlistID)
+	if youtube.IsRadioPlaylist(playlistID) {
+		progress(ctx, "Found a YouTube Music radio playlist, fetching songs...")
+	} else {
+		progress(ctx, "Found a YouTube playlist, fetching videos...")
+	}
+
+	playlist, err := youtube.GetPlaylistVideos(ctx, playlistID, config.Config.Youtube.PlaylistLimit)
+	if err != nil {
+		errMsg := err.Error()
+		switch {
+		case strings.Contains(errMsg, "not found"):
+			return nil, refuse(err, "That playlist doesn't exist or has been deleted.")
+		case strings.Contains(errMsg, "private"):
+			return nil, refuse(err, "That playlist is private.")
+		case strings.Contains(errMsg, "empty"):
+			return nil, refuse(err, "That playlist is empty or contains no accessible videos.")
+		}
+		return nil, fmt.Errorf("error fetching playlist from YouTube: %w", err)
+	}
+
+	videos := make([]youtube.VideoResponse, 0, len(playlist.Videos))
+	for _, v := range playlist.Videos {
+		videos = append(videos, youtube.VideoResponse{
+			Title:       v.Title,
+			VideoID:     v.VideoID,
+			Duration:    v.Duration,
+			ChannelName: v.ChannelName,
+		})
+	}
+	return &Resolution{
+		Tracks:     videos,
+		Collection: &Collection{Kind: "playlist", Name: playlist.Name, Total: playlist.TotalVideos},
+	}, nil
+}
@@ -0,0 +1,135 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+
+	"beatbot/youtube"
+)
+
+const (
+	ytDlpSearchResults = 3
+	ytDlpTimeout       = 30 * time.Second
+	// Caps how many tracks a single album/set link can queue.
+	ytDlpMaxEntries = 10
+)
+
+// ytDlpResolver resolves tracks for any site yt-dlp supports. searchPrefix
+// is the yt-dlp search key (e.g. "scsearch"); empty means links only.
+// collectionPath marks links to several tracks (e.g. "/sets/"), which yt-dlp
+// expands into up to ytDlpMaxEntries tracks. Links match on domain or any
+// of its subdomains.
+type ytDlpResolver struct {
+	name           string
+	displayName    string
+	searchPrefix   string
+	domain         string
+	collectionPath string
+}
+
+func newYtDlpResolver(name, displayName, searchPrefix, domain, collectionPath string) *ytDlpResolver {
+	return &ytDlpResolver{name: name, displayName: displayName, searchPrefix: searchPrefix, domain: domain, collectionPath: collectionPath}
+}
+
+func (r *ytDlpResolver) Name() string        { return r.name }
+func (r *ytDlpResolver) DisplayName() string { return r.displayName }
+
+func (r *ytDlpResolver) MatchesURL(query string) bool {
+	u, err := url.Parse(strings.TrimSpace(query))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == r.domain || strings.HasSuffix(host, "."+r.domain)
+}
+
+// IsCollection reports whether query links to a set or album rather than a
+// single track. Only the path counts: a SoundCloud track opened from a set
+// carries the set in its query string.
+func (r *ytDlpResolver) IsCollection(query string) bool {
+	if r.collectionPath == "" || !r.MatchesURL(query) {
+		return false
+	}
+	u, err := url.Parse(query)
+	return err == nil && strings.Contains(u.Path, r.collectionPath)
+}
+
+func (r *ytDlpResolver) Search(ctx context.Context, query string) ([]youtube.VideoResponse, error) {
+	target := query
+	if !r.MatchesURL(query) {
+		if r.searchPrefix == "" {
+			return nil, ErrSearchUnsupported
+		}
+		target = fmt.Sprintf("%s%d:%s", r.searchPrefix, ytDlpSearchResults, query)
+	}
+
+	span := sentry.StartSpan(ctx, "resolver.ytdlp")
+	span.Description = "Resolve " + r.displayName + " tracks via yt-dlp"
+	span.SetTag("source", r.name)
+	defer span.Finish()
+
+	ctx, cancel := context.WithTimeout(span.Context(), ytDlpTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "yt-dlp",
+		"--skip-download",
+		"--no-playlist",
+		"--playlist-end", strconv.Itoa(ytDlpMaxEntries),
+		"--socket-timeout", "10",
+		"--no-warnings",
+		"--print", "%(id)s\t%(title)s\t%(duration)s\t%(uploader)s\t%(webpage_url)s\t%(is_live)s",
+		"--", target)
+
+	output, err := cmd.Output()
+	if err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		log.WithFields(log.Fields{"module": "resolver", "source": r.name}).Errorf("yt-dlp lookup failed: %v", err)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			// e.g. "The channel is not currently live"
+			return nil, fmt.Errorf("%s lookup failed: %s", r.displayName, youtube.ExtractYtDlpReason(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("%s lookup failed: %w", r.displayName, err)
+	}
+
+	videos := parseYtDlpTracks(r.name, string(output))
+	span.Status = sentry.SpanStatusOK
+	return videos, nil
+}
+
+// parseYtDlpTracks parses the tab-separated --print output (one track per
+// line) into VideoResponses tagged with the given source. Live streams (a
+// Twitch channel that's on air) come back with Live set and no duration.
+func parseYtDlpTracks(source, output string) []youtube.VideoResponse {
+	var videos []youtube.VideoResponse
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) != 6 || fields[0] == "" || fields[4] == "" {
+			continue
+		}
+		video := youtube.VideoResponse{
+			VideoID: source + ":" + fields[0],
+			Title:   fields[1],
+			Source:  source,
+			URL:     fields[4],
+			Live:    fields[5] == "True",
+		}
+		if secs, err := strconv.ParseFloat(fields[2], 64); err == nil && !video.Live {
+			video.Duration = time.Duration(secs * float64(time.Second)).Round(time.Second)
+		}
+		if fields[3] != "NA" {
+			video.ChannelName = fields[3]
+		}
+		videos = append(videos, video)
+	}
+	return videos
+}
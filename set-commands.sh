@@ -1,18 +1,15 @@
 #!/bin/bash
+set -e
 
-is_dev=false
-
+set -a
 if [ "$1" == "dev" ]; then
   source .env.dev
   echo "Setting commands for dev"
 else
   source .env
 fi
+set +a
 
-curl -s -X PUT \
-  -H "Authorization: Bot $DISCORD_BOT_TOKEN" \
-  -H "Content-Type: application/json" \
-  -d @commands.json \
-  "https://discord.com/api/v10/applications/$DISCORD_APP_ID/commands"
+go run ./cmd/register-commands
 
-echo "Commands set successfully"
\ No newline at end of file
+echo "Commands set successfully"
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"time"
 
 	sentry "github.com/getsentry/sentry-go"
 	log "github.com/sirupsen/logrus"
@@ -23,8 +24,10 @@ type SpotifyRequest struct {
 }
 
 type TrackInfo struct {
-	Title   string
-	Artists []string
+	Title    string
+	Artists  []string
+	Duration time.Duration // 0 when Spotify didn't report one
+	ISRC     string        // International Standard Recording Code, "" when unknown
 }
 
 type PlaylistTrackInfo struct {
@@ -79,6 +82,30 @@ func Search(ctx context.Context, query string) (spotifyclient.SearchResult, erro
 	return *results, nil
 }
 
+// SearchTrack returns the best Spotify match for a free-text query, or nil
+// when Spotify has nothing.
+func SearchTrack(ctx context.Context, query string) (*TrackInfo, error) {
+	results, err := Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if results.Tracks == nil || len(results.Tracks.Tracks) == 0 {
+		return nil, nil
+	}
+
+	track := results.Tracks.Tracks[0]
+	artists := []string{}
+	for _, artist := range track.Artists {
+		artists = append(artists, artist.Name)
+	}
+	return &TrackInfo{
+		Title:    track.Name,
+		Artists:  artists,
+		Duration: track.TimeDuration(),
+		ISRC:     track.ExternalIDs["isrc"],
+	}, nil
+}
+
 func GetTrack(ctx context.Context, trackID string) (*TrackInfo, error) {
 	log.Tracef("Fetching track from Spotify API: %s", trackID)
 
@@ -104,8 +131,10 @@ func GetTrack(ctx context.Context, trackID string) (*TrackInfo, error) {
 	log.Debugf("Successfully fetched Spotify track: '%s' by %v", track.Name, artists)
 	span.Status = sentry.SpanStatusOK
 	return &TrackInfo{
-		Title:   track.Name,
-		Artists: artists,
+		Title:    track.Name,
+		Artists:  artists,
+		Duration: track.TimeDuration(),
+		ISRC:     track.ExternalIDs["isrc"],
 	}, nil
 }
 
@@ -132,6 +161,24 @@ func SearchArtist(ctx context.Context, query string) (string, string, error) {
 	return string(artist.ID), artist.Name, nil
 }
 
+// GetArtistName looks up an artist's display name by ID.
+func GetArtistName(ctx context.Context, artistID string) (string, error) {
+	span := sentry.StartSpan(ctx, "spotify.get_artist")
+	span.Description = "Get artist from Spotify API"
+	span.SetTag("artist_id", artistID)
+	defer span.Finish()
+
+	artist, err := Spotify.GetArtist(ctx, spotifyclient.ID(artistID))
+	if err != nil {
+		sentry.CaptureException(err)
+		span.Status = sentry.SpanStatusInternalError
+		return "", err
+	}
+
+	span.Status = sentry.SpanStatusOK
+	return artist.Name, nil
+}
+
 func GetArtistTopSongs(ctx context.Context, artistID string) ([]TrackInfo, error) {
 	// Start span for Spotify artist top tracks
 	span := sentry.StartSpan(ctx, "spotify.get_artist_top_songs")
@@ -153,8 +200,10 @@ func GetArtistTopSongs(ctx context.Context, artistID string) ([]TrackInfo, error
 			artists = append(artists, a.Name)
 		}
 		tracks = append(tracks, TrackInfo{
-			Title:   track.Name,
-			Artists: artists,
+			Title:    track.Name,
+			Artists:  artists,
+			Duration: track.TimeDuration(),
+			ISRC:     track.ExternalIDs["isrc"],
 		})
 	}
 
@@ -162,6 +211,8 @@ func GetArtistTopSongs(ctx context.Context, artistID string) ([]TrackInfo, error
 	return tracks, nil
 }
 
+// GetPlaylistTracks fetches a playlist's name and first limit tracks,
+// skipping podcast episodes.
 func GetPlaylistTracks(ctx context.Context, playlistID string, limit int) (*PlaylistResult, error) {
 	log.Tracef("Fetching playlist tracks from Spotify API: %s (limit: %d)", playlistID, limit)
 
@@ -222,8 +273,10 @@ func GetPlaylistTracks(ctx context.Context, playlistID string, limit int) (*Play
 
 		tracks = append(tracks, PlaylistTrackInfo{
 			TrackInfo: TrackInfo{
-				Title:   track.Name,
-				Artists: artists,
+				Title:    track.Name,
+				Artists:  artists,
+				Duration: track.TimeDuration(),
+				ISRC:     track.ExternalIDs["isrc"],
 			},
 			Position: i,
 		})
@@ -248,8 +301,9 @@ func GetPlaylistTracks(ctx context.Context, playlistID string, limit int) (*Play
 	}, nil
 }
 
-func GetAlbumTracks(ctx context.Context, albumID string) (*AlbumResult, error) {
-	log.Tracef("Fetching album tracks from Spotify API: %s", albumID)
+// GetAlbumTracks fetches an album's name, artist and first limit tracks.
+func GetAlbumTracks(ctx context.Context, albumID string, limit int) (*AlbumResult, error) {
+	log.Tracef("Fetching album tracks from Spotify API: %s (limit: %d)", albumID, limit)
 
 	span := sentry.StartSpan(ctx, "spotify.get_album_tracks")
 	span.Description = "Get album tracks from Spotify API"
@@ -288,9 +342,7 @@ func GetAlbumTracks(ctx context.Context, albumID string) (*AlbumResult, error) {
 		return nil, errors.New("album is empty")
 	}
 
-	// Fetch album tracks with limit of 15
-	const albumLimit = 15
-	albumTracks, err := Spotify.GetAlbumTracks(ctx, spotifyclient.ID(albumID), spotifyclient.Limit(albumLimit))
+	albumTracks, err := Spotify.GetAlbumTracks(ctx, spotifyclient.ID(albumID), spotifyclient.Limit(limit))
 	if err != nil {
 		log.Errorf("Failed to fetch Spotify album tracks %s: %v", albumID, err)
 		sentry.CaptureException(err)
@@ -298,7 +350,7 @@ func GetAlbumTracks(ctx context.Context, albumID string) (*AlbumResult, error) {
 		return nil, err
 	}
 
-	tracks := make([]PlaylistTrackInfo, 0, albumLimit)
+	tracks := make([]PlaylistTrackInfo, 0, limit)
 	for i, track := range albumTracks.Tracks {
 		artists := make([]string, 0, len(track.Artists))
 		for _, artist := range track.Artists {
@@ -307,8 +359,10 @@ func GetAlbumTracks(ctx context.Context, albumID string) (*AlbumResult, error) {
 
 		tracks = append(tracks, PlaylistTrackInfo{
 			TrackInfo: TrackInfo{
-				Title:   track.Name,
-				Artists: artists,
+				Title:    track.Name,
+				Artists:  artists,
+				Duration: track.TimeDuration(),
+				ISRC:     track.ExternalIDs.ISRC,
 			},
 			Position: i,
 		})
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"beatbot/config"
+	"beatbot/gemini"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -51,6 +52,9 @@ func Init() error {
 		defaultProvider = p
 		staleVoices = (&geminiProvider{}).Voices()
 	case "gemini", "":
+		if config.Config.Gemini.Enabled && !gemini.TTSReady() {
+			return fmt.Errorf("gemini TTS needs GEMINI_API_KEY (or TTS_PROVIDER=grok)")
+		}
 		defaultProvider = newGeminiProvider()
 		staleVoices = grokBuiltinVoices
 	default:
@@ -0,0 +1,171 @@
+// Package unfurl expands the shortened links phones share (spotify.link,
+// apple.co, song.link) into the music service link they stand for.
+package unfurl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxPageBytes caps how much of a short link's landing page is read when it
+// doesn't redirect on its own.
+const maxPageBytes = 1 << 20
+
+var httpClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+// odesliAPI looks up song.link pages. Without a key it allows about ten
+// lookups a minute, which is plenty for links pasted by hand.
+var odesliAPI = "https://api.song.link/v1-alpha.1/links"
+
+// redirectHosts are shorteners that redirect to the service's own link.
+var redirectHosts = map[string]bool{
+	"spotify.link": true,
+	"spoti.fi":     true,
+	"apple.co":     true,
+}
+
+// odesliHosts are Odesli pages that link to a song on every service.
+var odesliHosts = map[string]bool{
+	"song.link":  true,
+	"album.link": true,
+	"odesli.co":  true,
+}
+
+// odesliPlatforms are the services an Odesli page can be swapped for, most
+// preferred first. YouTube plays without matching, so it goes first.
+var odesliPlatforms = []string{"youtube", "youtubeMusic", "spotify", "appleMusic", "deezer", "soundcloud"}
+
+// ogURL finds the page's canonical link on landing pages that redirect with
+// script rather than HTTP.
+var ogURL = regexp.MustCompile(`<meta[^>]+property="og:url"[^>]+content="([^"]+)"`)
+
+// IsShortLink reports whether rawURL is a link Expand would follow.
+func IsShortLink(rawURL string) bool {
+	host := shortHost(rawURL)
+	return redirectHosts[host] || odesliHosts[host]
+}
+
+// Expand returns the link a short link stands for. Anything else is
+// returned unchanged, so callers can pass every query through it.
+func Expand(ctx context.Context, rawURL string) (string, error) {
+	host := shortHost(rawURL)
+	switch {
+	case redirectHosts[host]:
+		return followRedirects(ctx, strings.TrimSpace(rawURL))
+	case odesliHosts[host]:
+		return lookupOdesli(ctx, strings.TrimSpace(rawURL))
+	}
+	return rawURL, nil
+}
+
+func shortHost(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+}
+
+// followRedirects opens a shortener link and returns where it ends up.
+func followRedirects(ctx context.Context, link string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("following %s: %w", shortHost(link), err)
+	}
+	defer resp.Body.Close()
+
+	final := resp.Request.URL
+	if !redirectHosts[strings.TrimPrefix(strings.ToLower(final.Host), "www.")] {
+		log.WithField("module", "unfurl").Debugf("Expanded %s to %s", link, final)
+		return final.String(), nil
+	}
+
+	// Some shorteners answer with a landing page instead of a redirect.
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", final.Host, err)
+	}
+	if match := ogURL.FindSubmatch(page); match != nil && !IsShortLink(string(match[1])) {
+		return string(match[1]), nil
+	}
+	return "", fmt.Errorf("%s didn't lead anywhere", final.Host)
+}
+
+type odesliResponse struct {
+	PageURL         string `json:"pageUrl"`
+	LinksByPlatform map[string]struct {
+		URL string `json:"url"`
+	} `json:"linksByPlatform"`
+}
+
+// lookupOdesli swaps a song.link page for the song on a service the bot
+// plays.
+func lookupOdesli(ctx context.Context, link string) (string, error) {
+	result, err := queryOdesli(ctx, link)
+	if err != nil {
+		return "", err
+	}
+	for _, platform := range odesliPlatforms {
+		if found := result.LinksByPlatform[platform].URL; found != "" {
+			log.WithField("module", "unfurl").Debugf("Expanded %s to %s link %s", link, platform, found)
+			return found, nil
+		}
+	}
+	return "", errors.New("song.link has no link to a service I can play")
+}
+
+// SongLink returns the song.link page for a track link, which opens the
+// song on whatever service the listener uses.
+func SongLink(ctx context.Context, link string) (string, error) {
+	result, err := queryOdesli(ctx, link)
+	if err != nil {
+		return "", err
+	}
+	if result.PageURL == "" {
+		return "", errors.New("song.link has no page for that song")
+	}
+	return result.PageURL, nil
+}
+
+func queryOdesli(ctx context.Context, link string) (*odesliResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, odesliAPI+"?url="+url.QueryEscape(link), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("looking up song.link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest:
+		return nil, errors.New("song.link doesn't know that song")
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, errors.New("song.link is busy, try again in a minute")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("song.link lookup failed: %s", resp.Status)
+	}
+
+	var result odesliResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("reading song.link lookup: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,185 @@
+package unfurl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTransport answers each request from routes, keyed by host.
+type fakeTransport struct {
+	routes   map[string]func(*http.Request) *http.Response
+	requests []string
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.requests = append(f.requests, req.URL.String())
+	return f.routes[req.URL.Host](req), nil
+}
+
+func respond(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func useTransport(t *testing.T, transport *fakeTransport) {
+	t.Helper()
+	orig := httpClient
+	t.Cleanup(func() { httpClient = orig })
+	httpClient = &http.Client{Transport: transport, Timeout: 10 * time.Second}
+}
+
+func TestIsShortLink(t *testing.T) {
+	tests := map[string]bool{
+		"https://spotify.link/AbCdEf123":             true,
+		"https://apple.co/3xYz":                      true,
+		"https://song.link/s/4cOdK2wGLETKBW3PvgPWqT": true,
+		"https://album.link/i/1440857781":            true,
+		"https://open.spotify.com/track/abc":         false,
+		"https://youtu.be/dQw4w9WgXcQ":               false,
+		"song.link is great":                         false,
+	}
+	for link, want := range tests {
+		if got := IsShortLink(link); got != want {
+			t.Errorf("IsShortLink(%q) = %v, want %v", link, got, want)
+		}
+	}
+}
+
+func TestExpandRedirect(t *testing.T) {
+	transport := &fakeTransport{routes: map[string]func(*http.Request) *http.Response{
+		"spotify.link": func(req *http.Request) *http.Response {
+			return respond(req, http.StatusTemporaryRedirect,
+				http.Header{"Location": {"https://open.spotify.com/track/4cOdK2wGLETKBW3PvgPWqT?si=x"}}, "")
+		},
+		"open.spotify.com": func(req *http.Request) *http.Response {
+			return respond(req, http.StatusOK, nil, "<html></html>")
+		},
+	}}
+	useTransport(t, transport)
+
+	got, err := Expand(context.Background(), "https://spotify.link/AbCdEf123")
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if got != "https://open.spotify.com/track/4cOdK2wGLETKBW3PvgPWqT?si=x" {
+		t.Errorf("Expand = %q", got)
+	}
+}
+
+func TestExpandLandingPage(t *testing.T) {
+	transport := &fakeTransport{routes: map[string]func(*http.Request) *http.Response{
+		"spotify.link": func(req *http.Request) *http.Response {
+			return respond(req, http.StatusOK, nil,
+				`<html><head><meta property="og:url" content="https://open.spotify.com/album/1DFixLWuPkv3KT3TnV35m3" /></head></html>`)
+		},
+	}}
+	useTransport(t, transport)
+
+	got, err := Expand(context.Background(), "https://spotify.link/AbCdEf123")
+	if err != nil || got != "https://open.spotify.com/album/1DFixLWuPkv3KT3TnV35m3" {
+		t.Errorf("Expand = %q, %v", got, err)
+	}
+}
+
+func TestExpandOdesli(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "prefers youtube",
+			status: http.StatusOK,
+			body: `{"linksByPlatform": {
+				"spotify": {"url": "https://open.spotify.com/track/abc"},
+				"youtube": {"url": "https://www.youtube.com/watch?v=dQw4w9WgXcQ"}
+			}}`,
+			want: "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		},
+		{
+			name:   "falls back to spotify",
+			status: http.StatusOK,
+			body:   `{"linksByPlatform": {"tidal": {"url": "https://tidal.com/x"}, "spotify": {"url": "https://open.spotify.com/track/abc"}}}`,
+			want:   "https://open.spotify.com/track/abc",
+		},
+		{
+			name:    "nothing playable",
+			status:  http.StatusOK,
+			body:    `{"linksByPlatform": {"tidal": {"url": "https://tidal.com/x"}}}`,
+			wantErr: "no link",
+		},
+		{
+			name:    "unknown song",
+			status:  http.StatusNotFound,
+			body:    `{"statusCode": 404}`,
+			wantErr: "doesn't know",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fakeTransport{routes: map[string]func(*http.Request) *http.Response{
+				"api.song.link": func(req *http.Request) *http.Response {
+					return respond(req, tt.status, nil, tt.body)
+				},
+			}}
+			useTransport(t, transport)
+
+			got, err := Expand(context.Background(), "https://song.link/s/4cOdK2wGLETKBW3PvgPWqT")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expand error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expand = %q, %v; want %q", got, err, tt.want)
+			}
+			if want := "https://api.song.link/v1-alpha.1/links?url=https%3A%2F%2Fsong.link%2Fs%2F4cOdK2wGLETKBW3PvgPWqT"; transport.requests[0] != want {
+				t.Errorf("request = %q", transport.requests[0])
+			}
+		})
+	}
+}
+
+func TestExpandLeavesOtherLinks(t *testing.T) {
+	useTransport(t, &fakeTransport{})
+	for _, query := range []string{"https://open.spotify.com/track/abc", "never gonna give you up"} {
+		if got, err := Expand(context.Background(), query); err != nil || got != query {
+			t.Errorf("Expand(%q) = %q, %v", query, got, err)
+		}
+	}
+}
+
+func TestSongLink(t *testing.T) {
+	transport := &fakeTransport{routes: map[string]func(*http.Request) *http.Response{
+		"api.song.link": func(req *http.Request) *http.Response {
+			if req.URL.Query().Get("url") == "https://www.youtube.com/watch?v=missing" {
+				return respond(req, http.StatusOK, nil, `{"linksByPlatform": {}}`)
+			}
+			return respond(req, http.StatusOK, nil, `{"pageUrl": "https://song.link/y/dQw4w9WgXcQ", "linksByPlatform": {}}`)
+		},
+	}}
+	useTransport(t, transport)
+
+	got, err := SongLink(context.Background(), "https://www.youtube.com/watch?v=dQw4w9WgXcQ")
+	if err != nil || got != "https://song.link/y/dQw4w9WgXcQ" {
+		t.Errorf("SongLink = %q, %v", got, err)
+	}
+	if _, err := SongLink(context.Background(), "https://www.youtube.com/watch?v=missing"); err == nil {
+		t.Error("SongLink without a page: want an error")
+	}
+}
@@ -0,0 +1,119 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const chaptersTimeout = 20 * time.Second
+
+// Chapter is one titled section of a video, like a song in a full album
+// upload.
+type Chapter struct {
+	Title string
+	Start time.Duration
+	End   time.Duration
+}
+
+// GetChapters returns a video's chapters, in order, or none when it has
+// no chapters. yt-dlp reads them from the description timestamps as well as
+// YouTube's own chapter markers.
+func GetChapters(ctx context.Context, videoID string) ([]Chapter, error) {
+	logger := log.WithFields(log.Fields{"module": "youtube", "function": "GetChapters", "video_id": videoID})
+
+	span := sentry.StartSpan(ctx, "youtube.get_chapters")
+	span.Description = "Get video chapters via yt-dlp"
+	span.SetTag("video_id", videoID)
+	defer span.Finish()
+
+	ctx, cancel := context.WithTimeout(ctx, chaptersTimeout)
+	defer cancel()
+
+	args := append([]string{
+		"--no-playlist",
+		"--skip-download",
+		"--socket-timeout", "10",
+		"--no-warnings",
+		"--print", "%(chapters)j",
+	}, ytDlpAuthArgs()...)
+	args = append(args, "--", "https://www.youtube.com/watch?v="+videoID)
+	output, err := exec.CommandContext(ctx, "yt-dlp", args...).CombinedOutput()
+	if err != nil {
+		span.Status = sentry.SpanStatusInternalError
+		if restricted := restrictionError(string(output)); restricted != nil {
+			return nil, restricted
+		}
+		logger.Errorf("yt-dlp chapters failed: %v: %s", err, output)
+		return nil, fmt.Errorf("%s", ExtractYtDlpReason(string(output)))
+	}
+
+	chapters, err := parseChapters(string(output))
+	if err != nil {
+		logger.Errorf("reading chapters: %v", err)
+		span.Status = sentry.SpanStatusInternalError
+		return nil, err
+	}
+	span.Status = sentry.SpanStatusOK
+	span.SetData("chapters", len(chapters))
+	return chapters, nil
+}
+
+// parseChapters reads yt-dlp's %(chapters)j, which is "NA" or "null" for a
+// video without chapters.
+func parseChapters(output string) ([]Chapter, error) {
+	output = strings.TrimSpace(output)
+	if output == "" || output == "NA" || output == "null" {
+		return nil, nil
+	}
+
+	var raw []struct {
+		Title     string  `json:"title"`
+		StartTime float64 `json:"start_time"`
+		EndTime   float64 `json:"end_time"`
+	}
+	if err := json.Unmarshal([]byte(output), &raw); err != nil {
+		return nil, fmt.Errorf("reading chapters: %v", err)
+	}
+
+	chapters := make([]Chapter, 0, len(raw))
+	for i, c := range raw {
+		chapter := Chapter{
+			Title: strings.TrimSpace(c.Title),
+			Start: time.Duration(c.StartTime * float64(time.Second)).Round(time.Second),
+			End:   time.Duration(c.EndTime * float64(time.Second)).Round(time.Second),
+		}
+		if chapter.End <= chapter.Start {
+			continue
+		}
+		if chapter.Title == "" {
+			chapter.Title = fmt.Sprintf("Chapter %d", i+1)
+		}
+		chapters = append(chapters, chapter)
+	}
+	return chapters, nil
+}
+
+// ChapterTracks turns each chapter of video into its own track, clipped to
+// the chapter and titled after it. The last chapter plays out to the end.
+func ChapterTracks(video VideoResponse, chapters []Chapter) []VideoResponse {
+	tracks := make([]VideoResponse, 0, len(chapters))
+	for _, chapter := range chapters {
+		track := video
+		track.Title = chapter.Title
+		track.RawTitle = ""
+		end := chapter.End
+		if video.Duration > 0 && end >= video.Duration {
+			end = 0
+		}
+		track.Clip(chapter.Start, end)
+		tracks = append(tracks, track)
+	}
+	return tracks
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"beatbot/config"
+	"beatbot/ytdlp"
 
 	sentry "github.com/getsentry/sentry-go"
 	log "github.com/sirupsen/logrus"
@@ -25,11 +26,115 @@ import (
 // from generic yt-dlp errors (e.g. no Sentry capture, user-friendly message).
 var ErrAgeRestricted = errors.New("age restricted")
 
+// ErrRegionLocked is returned by GetVideoStream when the uploader has blocked
+// the video in the country the bot runs from. Handled like ErrAgeRestricted.
+var ErrRegionLocked = errors.New("not available in this region")
+
+// IsRestricted reports whether err means YouTube refused the video itself,
+// so retrying won't help but another upload of the same song might.
+func IsRestricted(err error) bool {
+	return errors.Is(err, ErrAgeRestricted) || errors.Is(err, ErrRegionLocked)
+}
+
+// restrictionError maps yt-dlp output to ErrAgeRestricted or ErrRegionLocked,
+// or nil when the failure was something else.
+func restrictionError(output string) error {
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "confirm your age"),
+		strings.Contains(lower, "age-restricted"),
+		strings.Contains(lower, "inappropriate for some users"):
+		return ErrAgeRestricted
+	case strings.Contains(lower, "available in your country"),
+		strings.Contains(lower, "available from your location"),
+		strings.Contains(lower, "blocked it in your country"):
+		return ErrRegionLocked
+	}
+	return nil
+}
+
+// ytDlpAuthArgs returns the yt-dlp flags for the configured cookies file and
+// PO token. Cookies from a signed-in account get past the age check; the PO
+// token gets past YouTube's "confirm you're not a bot" wall.
+func ytDlpAuthArgs() []string {
+	if config.Config == nil {
+		return nil
+	}
+	var args []string
+	if cookies := config.Config.Youtube.CookiesFile; cookies != "" {
+		args = append(args, "--cookies", cookies)
+	}
+	if token := config.Config.Youtube.POToken; token != "" {
+		args = append(args, "--extractor-args", "youtube:po_token="+token)
+	}
+	return args
+}
+
+// SourceFile marks tracks that are a direct link to an audio file or radio
+// stream. URL is the audio itself, so ffmpeg reads it without yt-dlp.
+const SourceFile = "file"
+
 type VideoResponse struct {
 	Title       string        `json:"title"`
 	VideoID     string        `json:"video_id"`
 	Duration    time.Duration `json:"duration"`
 	ChannelName string        `json:"channel_name"`
+	// RawTitle is the upstream title before Normalize, kept for links and
+	// lookups; empty when normalization didn't change anything.
+	RawTitle string `json:"raw_title,omitempty"`
+	// Source is the resolver that produced this track ("" means YouTube).
+	// Non-YouTube tracks carry their page URL in URL for yt-dlp to resolve.
+	Source string `json:"source,omitempty"`
+	URL    string `json:"url,omitempty"`
+	// StartAt and EndAt trim playback to part of the track (/play start:1:30
+	// end:3:45). Zero means from the beginning and to the end respectively.
+	StartAt time.Duration `json:"start_at,omitempty"`
+	EndAt   time.Duration `json:"end_at,omitempty"`
+	// Live marks an endless stream (a YouTube live stream or an internet
+	// radio station). It has no Duration and plays until skipped.
+	Live bool `json:"live,omitempty"`
+}
+
+// Clip limits playback to the part between start and end (0 for the end of
+// the track) and shortens Duration to match, so ETAs and the length cap see
+// what will actually play.
+func (v *VideoResponse) Clip(start, end time.Duration) {
+	v.StartAt, v.EndAt = start, end
+	switch {
+	case end > 0:
+		v.Duration = end - start
+	case v.Duration > 0:
+		v.Duration -= start
+	}
+}
+
+// Clipped reports whether only part of the track will play.
+func (v VideoResponse) Clipped() bool {
+	return v.StartAt > 0 || v.EndAt > 0
+}
+
+// IsYouTube reports whether the track is a YouTube video (VideoID is a real
+// YouTube ID).
+func (v VideoResponse) IsYouTube() bool {
+	return v.Source == "" || v.Source == "youtube"
+}
+
+// PageURL returns the canonical link for the track — the YouTube watch URL,
+// or the source page URL for tracks from other resolvers.
+func (v VideoResponse) PageURL() string {
+	if v.URL != "" {
+		return v.URL
+	}
+	return "https://www.youtube.com/watch?v=" + v.VideoID
+}
+
+// ThumbnailURL returns the YouTube thumbnail for the track, or "" for
+// non-YouTube tracks.
+func (v VideoResponse) ThumbnailURL() string {
+	if !v.IsYouTube() {
+		return ""
+	}
+	return "https://i.ytimg.com/vi/" + v.VideoID + "/hqdefault.jpg"
 }
 
 type YoutubeStream struct {
@@ -43,6 +148,7 @@ type PlaylistVideoInfo struct {
 	VideoID     string
 	Title       string
 	ChannelName string
+	Duration    time.Duration // 0 if YouTube didn't report one
 	Position    int
 }
 
@@ -58,41 +164,115 @@ type PlaylistResult struct {
 type YouTubeURLResult struct {
 	VideoID    string
 	PlaylistID string
+	Start      time.Duration // from a t= or start= parameter; 0 if absent or unreadable
 }
 
-func ParseYoutubeUrl(_url string) string {
-	parsedURL, err := url.Parse(_url)
-	if err != nil {
-		return ""
-	}
+// youtubeHosts serve youtube.com-style paths (/watch, /playlist, /shorts/...).
+var youtubeHosts = map[string]bool{
+	"youtube.com":       true,
+	"www.youtube.com":   true,
+	"m.youtube.com":     true,
+	"music.youtube.com": true,
+}
 
-	if parsedURL.Host == "www.youtube.com" || parsedURL.Host == "youtube.com" {
-		return parsedURL.Query().Get("v")
-	}
+// videoPathPrefixes are youtube.com paths followed by a video ID.
+var videoPathPrefixes = []string{"/shorts/", "/live/", "/embed/"}
 
-	return ""
+// ParseYoutubeUrl returns the video ID of a YouTube video link, or "".
+func ParseYoutubeUrl(_url string) string {
+	return ParseYouTubeURL(_url).VideoID
 }
 
 // ParseYouTubeURL parses a YouTube URL and returns video ID and playlist ID if present
 // Handles:
-// - youtube.com/watch?v=VIDEO_ID - single video
+// - youtube.com/watch?v=VIDEO_ID - single video (also on m. and music.youtube.com)
 // - youtube.com/watch?v=VIDEO_ID&list=PLAYLIST_ID - video in playlist context
 // - youtube.com/playlist?list=PLAYLIST_ID - playlist URL
+// - youtube.com/shorts/VIDEO_ID, /live/VIDEO_ID and /embed/VIDEO_ID
+// - youtu.be/VIDEO_ID - share links
+// - music.youtube.com/playlist?list=RDCLAK... - YouTube Music radio playlists
+// plus a t= or start= timestamp on any of the video forms. A video inside a
+// personal mix (list=RD..., the default on music.youtube.com) is returned as
+// just the video, since mixes can't be fetched as playlists.
 func ParseYouTubeURL(_url string) YouTubeURLResult {
 	parsedURL, err := url.Parse(_url)
 	if err != nil {
 		return YouTubeURLResult{}
 	}
 
-	if parsedURL.Host != "www.youtube.com" && parsedURL.Host != "youtube.com" {
+	query := parsedURL.Query()
+	var result YouTubeURLResult
+	switch host := strings.ToLower(parsedURL.Host); {
+	case host == "youtu.be":
+		result.VideoID = firstPathSegment(parsedURL.Path)
+	case youtubeHosts[host]:
+		result.VideoID = query.Get("v")
+		for _, prefix := range videoPathPrefixes {
+			if strings.HasPrefix(parsedURL.Path, prefix) {
+				result.VideoID = firstPathSegment(strings.TrimPrefix(parsedURL.Path, prefix))
+			}
+		}
+	default:
 		return YouTubeURLResult{}
 	}
+	result.PlaylistID = query.Get("list")
+	if isDynamicMix(result.PlaylistID) {
+		if result.VideoID == "" {
+			result.VideoID = mixSeed(result.PlaylistID)
+		}
+		result.PlaylistID = ""
+	}
 
-	query := parsedURL.Query()
-	return YouTubeURLResult{
-		VideoID:    query.Get("v"),
-		PlaylistID: query.Get("list"),
+	if result.VideoID != "" {
+		for _, key := range []string{"t", "start"} {
+			if value := query.Get(key); value != "" {
+				result.Start, _ = ParseTimestamp(value)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// firstPathSegment returns path's first segment without slashes.
+func firstPathSegment(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return segment
+}
+
+// ParseTimestamp reads a position in a track as written by people or in
+// YouTube links: "90", "1:30", "1:02:03", "90s" or "1m30s".
+func ParseTimestamp(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("empty timestamp")
 	}
+
+	var d time.Duration
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 || (i > 0 && n >= 60) {
+				return 0, fmt.Errorf("invalid timestamp %q", s)
+			}
+			d = d*60 + time.Duration(n)*time.Second
+		}
+		return d, nil
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		d = time.Duration(n) * time.Second
+	} else if d, err = time.ParseDuration(s); err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return d, nil
 }
 
 func GetVideoByID(ctx context.Context, videoID string) (VideoResponse, error) {
@@ -104,7 +284,7 @@ func GetVideoByID(ctx context.Context, videoID string) (VideoResponse, error) {
 		return VideoResponse{}, fmt.Errorf("error creating YouTube client: %v", err)
 	}
 
-	call := service.Videos.List([]string{"snippet"}).Id(videoID)
+	call := service.Videos.List([]string{"snippet", "contentDetails"}).Id(videoID)
 	response, err := call.Do()
 	if err != nil {
 		log.Errorf("error querying YouTube: %v", err)
@@ -113,17 +293,24 @@ func GetVideoByID(ctx context.Context, videoID string) (VideoResponse, error) {
 
 	if len(response.Items) > 0 {
 		log.Tracef("video found: %v", response.Items[0].Snippet.Title)
+		var duration time.Duration
+		if response.Items[0].ContentDetails != nil {
+			duration = parseYoutubeDuration(response.Items[0].ContentDetails.Duration)
+		}
 		return VideoResponse{
 			Title:       response.Items[0].Snippet.Title,
 			VideoID:     videoID,
+			Duration:    duration,
 			ChannelName: response.Items[0].Snippet.ChannelTitle,
+			Live:        response.Items[0].Snippet.LiveBroadcastContent == "live",
 		}, nil
 	}
 
 	return VideoResponse{}, fmt.Errorf("no video found")
 }
 
-// GetPlaylistVideos fetches videos from a YouTube playlist
+// GetPlaylistVideos fetches videos from a YouTube playlist. YouTube Music
+// radio playlists are read with yt-dlp instead of the Data API.
 func GetPlaylistVideos(ctx context.Context, playlistID string, limit int) (*PlaylistResult, error) {
 	logger := log.WithFields(log.Fields{"module": "youtube", "function": "GetPlaylistVideos", "playlist_id": playlistID})
 
@@ -134,6 +321,10 @@ func GetPlaylistVideos(ctx context.Context, playlistID string, limit int) (*Play
 	span.SetTag("limit", strconv.Itoa(limit))
 	defer span.Finish()
 
+	if IsRadioPlaylist(playlistID) {
+		return getRadioPlaylist(ctx, span, logger, playlistID, limit)
+	}
+
 	apiKey := config.Config.Youtube.APIKey
 	service, err := ytapi.NewService(ctx, option.WithAPIKey(apiKey))
 	if err != nil {
@@ -207,6 +398,26 @@ func GetPlaylistVideos(ctx context.Context, playlistID string, limit int) (*Play
 		return nil, fmt.Errorf("playlist is empty or contains no accessible videos")
 	}
 
+	// playlistItems doesn't include durations — look them up in one batched
+	// videos.list call so the queue can show ETAs. Best-effort: a failure here
+	// just leaves durations at 0.
+	videoIDs := make([]string, len(videos))
+	for i, v := range videos {
+		videoIDs[i] = v.VideoID
+	}
+	detailsResp, err := service.Videos.List([]string{"contentDetails"}).Id(videoIDs...).Do()
+	if err != nil {
+		logger.Warnf("error fetching playlist video durations: %v", err)
+	} else {
+		durations := make(map[string]time.Duration, len(detailsResp.Items))
+		for _, item := range detailsResp.Items {
+			durations[item.Id] = parseYoutubeDuration(item.ContentDetails.Duration)
+		}
+		for i := range videos {
+			videos[i].Duration = durations[videos[i].VideoID]
+		}
+	}
+
 	span.Status = sentry.SpanStatusOK
 	span.SetData("videos_fetched", len(videos))
 	span.SetData("total_videos", totalVideos)
@@ -324,15 +535,14 @@ func fetchMixViaYtdlp(ctx context.Context, seedVideoID string, logger *log.Entry
 	ytdlpCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ytdlpCtx,
-		"yt-dlp",
+	args := append([]string{
 		"--flat-playlist",
 		"--print", "%(id)s",
 		"--playlist-end", "25",
 		"--socket-timeout", "10",
 		"--no-warnings",
-		mixURL,
-	)
+	}, ytDlpAuthArgs()...)
+	cmd := exec.CommandContext(ytdlpCtx, "yt-dlp", append(args, "--", mixURL)...)
 
 	output, err := cmd.Output()
 	if err != nil {
@@ -382,7 +592,32 @@ func fetchMixViaYtdlp(ctx context.Context, seedVideoID string, logger *log.Entry
 	return videos, nil
 }
 
+// Query searches YouTube for music videos. It uses the Data API, and falls
+// back to a yt-dlp search while the API key is over its quota. Results are
+// reused for the same query for SEARCH_CACHE_HOURS, since each API search
+// costs 100 quota units and popular songs get searched again and again.
 func Query(ctx context.Context, query string) []VideoResponse {
+	var ttl time.Duration
+	if config.Config != nil {
+		ttl = config.Config.Youtube.SearchCache
+	}
+	key := searchKey(query)
+	if ttl > 0 {
+		if videos, ok := cachedSearch(key, time.Now()); ok {
+			log.WithFields(log.Fields{"module": "youtube", "function": "Query"}).Tracef("using cached results for %q", query)
+			return videos
+		}
+	}
+
+	videos := search(ctx, query)
+	if ttl > 0 && len(videos) > 0 {
+		cacheSearch(key, videos, time.Now().Add(ttl))
+	}
+	return videos
+}
+
+// search runs a search for Query.
+func search(ctx context.Context, query string) []VideoResponse {
 	logger := log.WithFields(log.Fields{"module": "youtube", "function": "Query"})
 
 	// Start span for YouTube API search
@@ -391,6 +626,10 @@ func Query(ctx context.Context, query string) []VideoResponse {
 	span.SetTag("query", query)
 	defer span.Finish()
 
+	if skipAPI(time.Now()) {
+		return queryFallback(span, logger, query)
+	}
+
 	api_key := config.Config.Youtube.APIKey
 
 	service, err := ytapi.NewService(ctx, option.WithAPIKey(api_key))
@@ -408,6 +647,10 @@ func Query(ctx context.Context, query string) []VideoResponse {
 		VideoCategoryId("10")
 
 	response, err := call.Do()
+	if isQuotaError(err) {
+		markQuotaExhausted(time.Now())
+		return queryFallback(span, logger, query)
+	}
 	if err != nil {
 		logger.Errorf("error querying YouTube: %v", err)
 		sentry.CaptureException(err)
@@ -419,12 +662,14 @@ func Query(ctx context.Context, query string) []VideoResponse {
 	videoIDs := make([]string, 0)
 	videoMap := make(map[string]string)
 	channelMap := make(map[string]string)
+	liveMap := make(map[string]bool)
 
 	for _, item := range response.Items {
 		if item.Id.Kind == "youtube#video" {
 			videoIDs = append(videoIDs, item.Id.VideoId)
 			videoMap[item.Id.VideoId] = html.UnescapeString(item.Snippet.Title)
 			channelMap[item.Id.VideoId] = html.UnescapeString(item.Snippet.ChannelTitle)
+			liveMap[item.Id.VideoId] = item.Snippet.LiveBroadcastContent == "live"
 		}
 	}
 
@@ -435,6 +680,10 @@ func Query(ctx context.Context, query string) []VideoResponse {
 
 	videoCall := service.Videos.List([]string{"contentDetails"}).Id(videoIDs...)
 	videoResponse, err := videoCall.Do()
+	if isQuotaError(err) {
+		markQuotaExhausted(time.Now())
+		return queryFallback(span, logger, query)
+	}
 	if err != nil {
 		logger.Errorf("error getting video details: %v", err)
 		sentry.CaptureException(err)
@@ -452,10 +701,12 @@ func Query(ctx context.Context, query string) []VideoResponse {
 				VideoID:     item.Id,
 				Duration:    ytDuration,
 				ChannelName: channelMap[item.Id],
+				Live:        liveMap[item.Id],
 			})
 		}
 	}
 
+	markQuotaAvailable()
 	span.Status = sentry.SpanStatusOK
 	span.SetData("results_count", len(videos))
 	logger.Tracef("found %d videos", len(videos))
@@ -471,13 +722,35 @@ func GetVideoStream(ctx context.Context, videoResponse VideoResponse) (*YoutubeS
 	span.SetTag("video_id", videoResponse.VideoID)
 	defer span.Finish()
 
+	if videoResponse.Source == SourceFile {
+		span.Status = sentry.SpanStatusOK
+		return &YoutubeStream{
+			StreamURL: videoResponse.URL,
+			Title:     videoResponse.Title,
+			VideoID:   videoResponse.VideoID,
+		}, nil
+	}
+
+	ytUrl := videoResponse.PageURL()
+	if !videoResponse.Live {
+		if streamUrl, ok := cachedStreamURL(ytUrl, time.Now()); ok {
+			logger.Tracef("using cached stream URL for %s", ytUrl)
+			span.SetTag("cached", "true")
+			span.Status = sentry.SpanStatusOK
+			return &YoutubeStream{
+				StreamURL: streamUrl,
+				Title:     videoResponse.Title,
+				VideoID:   videoResponse.VideoID,
+			}, nil
+		}
+	}
+
 	var output []byte
 	var err error
 
-	ytUrl := "https://www.youtube.com/watch?v=" + videoResponse.VideoID
 	logger.Tracef("getting video stream for %s", ytUrl)
 	for i := range 3 {
-		cmd := exec.Command("yt-dlp",
+		args := append([]string{
 			"-f", "bestaudio",
 			"--no-playlist",
 			"--socket-timeout", "10",
@@ -486,9 +759,15 @@ func GetVideoStream(ctx context.Context, videoResponse VideoResponse) (*YoutubeS
 			"--no-cache-dir",
 			"-g",
 			"--no-warnings",
-			ytUrl)
+		}, ytDlpAuthArgs()...)
+		// "--" ends the options, so a link can't be read as one.
+		cmd := exec.CommandContext(ctx, "yt-dlp", append(args, "--", ytUrl)...)
 
 		output, err = cmd.CombinedOutput()
+		if err != nil && ctx.Err() != nil {
+			span.Status = sentry.SpanStatusCanceled
+			return nil, ctx.Err()
+		}
 		if err != nil {
 			logger.WithFields(log.Fields{
 				"attempt": i + 1,
@@ -496,15 +775,21 @@ func GetVideoStream(ctx context.Context, videoResponse VideoResponse) (*YoutubeS
 				"output":  string(output),
 			}).Error("yt-dlp command failed")
 
+			// Age-restricted and region-locked videos are expected failures that
+			// retrying won't fix — return a typed sentinel so callers can show a
+			// user-friendly message without Sentry noise.
+			if restricted := restrictionError(string(output)); restricted != nil {
+				span.Status = sentry.SpanStatusPermissionDenied
+				if errors.Is(restricted, ErrAgeRestricted) && config.Config != nil && config.Config.Youtube.CookiesFile == "" {
+					logger.Info("set YTDLP_COOKIES_FILE to play age-restricted videos")
+				}
+				return nil, restricted
+			}
 			if i == 2 {
+				ytdlp.RecordExtraction(true)
 				span.Status = sentry.SpanStatusInternalError
-				// Age-restricted videos are expected failures — return a typed sentinel
-				// so callers can show a user-friendly message without Sentry noise.
-				if strings.Contains(string(output), "Sign in to confirm your age") {
-					return nil, ErrAgeRestricted
-				}
 				sentry.CaptureException(fmt.Errorf("yt-dlp error after 3 attempts: %v, output: %s", err, string(output)))
-				return nil, fmt.Errorf("%s", extractYtDlpReason(string(output)))
+				return nil, fmt.Errorf("%s", ExtractYtDlpReason(string(output)))
 			}
 			continue
 		}
@@ -512,6 +797,10 @@ func GetVideoStream(ctx context.Context, videoResponse VideoResponse) (*YoutubeS
 	}
 
 	streamUrl := strings.TrimSpace(string(output))
+	ytdlp.RecordExtraction(false)
+	if !videoResponse.Live {
+		cacheStreamURL(ytUrl, streamUrl, time.Now())
+	}
 
 	span.Status = sentry.SpanStatusOK
 	return &YoutubeStream{
@@ -521,11 +810,11 @@ func GetVideoStream(ctx context.Context, videoResponse VideoResponse) (*YoutubeS
 	}, nil
 }
 
-// extractYtDlpReason parses yt-dlp combined output for a user-readable error reason.
+// ExtractYtDlpReason parses yt-dlp combined output for a user-readable error reason.
 // For extractor errors ("ERROR: [youtube] VIDEO_ID: reason"), strips the prefix and
 // video ID to return just the human reason. For other errors, returns the message as-is.
 // Falls back to "video unavailable" if no ERROR line is found.
-func extractYtDlpReason(output string) string {
+func ExtractYtDlpReason(output string) string {
 	for _, line := range strings.Split(output, "\n") {
 		line = strings.TrimSpace(line)
 		if !strings.HasPrefix(line, "ERROR: ") {
@@ -580,6 +869,7 @@ func TestYoutubeDlpWithOutput() (string, error) {
 		"--no-check-formats",
 		"--no-check-certificates",
 		"--verbose",
+		"--",
 		"https://www.youtube.com/watch?v=dQw4w9WgXcQ")
 
 	output, err := cmd.Output()
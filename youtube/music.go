@@ -0,0 +1,135 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// Playlist ID prefixes YouTube and YouTube Music generate on the fly.
+const (
+	// radioPrefix marks YouTube Music's curated radio playlists. Unlike mixes
+	// they're the same for everyone, but the Data API doesn't serve them.
+	radioPrefix = "RDCLAK"
+	// mixPrefix marks personal mixes (RD<video>, RDAMVM<video>, RDMM, ...).
+	mixPrefix = "RD"
+)
+
+// IsRadioPlaylist reports whether id is a YouTube Music radio playlist
+// (list=RDCLAK...).
+func IsRadioPlaylist(id string) bool {
+	return strings.HasPrefix(id, radioPrefix)
+}
+
+// isDynamicMix reports whether id is a mix YouTube generates per listener.
+// These can't be fetched as a playlist, so a watch link inside one is
+// treated as a link to just the video.
+func isDynamicMix(id string) bool {
+	return strings.HasPrefix(id, mixPrefix) && !IsRadioPlaylist(id)
+}
+
+// mixSeed returns the video a mix was started from, for links that carry
+// only the mix (music.youtube.com/watch?list=RDAMVM<video>). Returns "" when
+// the mix isn't seeded by a video.
+func mixSeed(id string) string {
+	for _, prefix := range []string{"RDAMVM", "RDEM", mixPrefix} {
+		if seed := strings.TrimPrefix(id, prefix); seed != id && len(seed) == 11 {
+			return seed
+		}
+	}
+	return ""
+}
+
+// flatPlaylist is the part of yt-dlp's -J --flat-playlist output we read.
+type flatPlaylist struct {
+	Title         string `json:"title"`
+	PlaylistCount int    `json:"playlist_count"`
+	Entries       []struct {
+		ID       string  `json:"id"`
+		Title    string  `json:"title"`
+		Duration float64 `json:"duration"`
+		Channel  string  `json:"channel"`
+		Uploader string  `json:"uploader"`
+	} `json:"entries"`
+}
+
+// getRadioPlaylist fetches a YouTube Music radio playlist with yt-dlp, since
+// the Data API answers "not found" for them.
+func getRadioPlaylist(ctx context.Context, span *sentry.Span, logger *log.Entry, playlistID string, limit int) (*PlaylistResult, error) {
+	span.SetData("source", "yt-dlp")
+
+	ytdlpCtx, cancel := context.WithTimeout(ctx, ytSearchTimeout)
+	defer cancel()
+
+	args := append([]string{
+		"-J",
+		"--flat-playlist",
+		"--playlist-end", strconv.Itoa(limit),
+		"--socket-timeout", "10",
+		"--no-warnings",
+	}, ytDlpAuthArgs()...)
+	args = append(args, "--", "https://music.youtube.com/playlist?list="+playlistID)
+	output, err := exec.CommandContext(ytdlpCtx, "yt-dlp", args...).Output()
+	if err != nil {
+		logger.Errorf("yt-dlp radio playlist: %v", err)
+		span.Status = sentry.SpanStatusInternalError
+		if exitErr, ok := err.(*exec.ExitError); ok && strings.Contains(string(exitErr.Stderr), "does not exist") {
+			return nil, fmt.Errorf("playlist not found")
+		}
+		return nil, fmt.Errorf("error fetching playlist: %v", err)
+	}
+
+	result, err := parseFlatPlaylist(playlistID, output)
+	if err != nil {
+		span.Status = sentry.SpanStatusNotFound
+		return nil, err
+	}
+
+	span.Status = sentry.SpanStatusOK
+	span.SetData("videos_fetched", len(result.Videos))
+	logger.Debugf("Fetched %d videos from radio playlist via yt-dlp", len(result.Videos))
+	return result, nil
+}
+
+// parseFlatPlaylist reads yt-dlp's JSON for a playlist.
+func parseFlatPlaylist(playlistID string, output []byte) (*PlaylistResult, error) {
+	var playlist flatPlaylist
+	if err := json.Unmarshal(output, &playlist); err != nil {
+		return nil, fmt.Errorf("error reading playlist: %v", err)
+	}
+
+	videos := make([]PlaylistVideoInfo, 0, len(playlist.Entries))
+	for i, entry := range playlist.Entries {
+		if entry.ID == "" {
+			continue
+		}
+		channel := entry.Channel
+		if channel == "" {
+			channel = entry.Uploader
+		}
+		videos = append(videos, PlaylistVideoInfo{
+			VideoID:     entry.ID,
+			Title:       entry.Title,
+			ChannelName: channel,
+			Duration:    time.Duration(entry.Duration * float64(time.Second)).Round(time.Second),
+			Position:    i,
+		})
+	}
+	if len(videos) == 0 {
+		return nil, fmt.Errorf("playlist is empty or contains no accessible videos")
+	}
+
+	return &PlaylistResult{
+		ID:          playlistID,
+		Name:        playlist.Title,
+		Videos:      videos,
+		TotalVideos: max(playlist.PlaylistCount, len(videos)),
+	}, nil
+}
@@ -0,0 +1,187 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// quotaRetryInterval is how long searches skip the Data API after it
+	// reports the quota as spent. The quota resets daily, but checking again
+	// now and then costs little and picks up a raised limit or a new key.
+	quotaRetryInterval = 15 * time.Minute
+
+	ytSearchResults = 10
+	ytSearchTimeout = 20 * time.Second
+)
+
+// QuotaStatus describes whether searches are going to the YouTube Data API or
+// to the yt-dlp fallback. It's served to operators on /youtube/quota.
+type QuotaStatus struct {
+	Exhausted       bool      `json:"exhausted"`
+	ExhaustedSince  time.Time `json:"exhausted_since,omitempty"`
+	RetryAt         time.Time `json:"retry_at,omitempty"`
+	FallbackQueries int       `json:"fallback_queries"` // searches served by yt-dlp since the quota ran out
+}
+
+var quota struct {
+	sync.Mutex
+	since     time.Time // zero while the API is answering
+	lastTry   time.Time
+	fallbacks int
+}
+
+// GetQuotaStatus returns the current search quota state.
+func GetQuotaStatus() QuotaStatus {
+	quota.Lock()
+	defer quota.Unlock()
+	if quota.since.IsZero() {
+		return QuotaStatus{FallbackQueries: quota.fallbacks}
+	}
+	return QuotaStatus{
+		Exhausted:       true,
+		ExhaustedSince:  quota.since,
+		RetryAt:         quota.lastTry.Add(quotaRetryInterval),
+		FallbackQueries: quota.fallbacks,
+	}
+}
+
+// skipAPI reports whether Query should go straight to yt-dlp because the
+// quota ran out recently. When the retry interval has passed it lets one
+// search through to find out whether the quota is back.
+func skipAPI(now time.Time) bool {
+	quota.Lock()
+	defer quota.Unlock()
+	if quota.since.IsZero() || now.Sub(quota.lastTry) >= quotaRetryInterval {
+		quota.lastTry = now
+		return false
+	}
+	return true
+}
+
+// markQuotaExhausted records that the API refused a search for quota. Only
+// the first refusal is reported to Sentry, so operators get one warning per
+// outage rather than one per search.
+func markQuotaExhausted(now time.Time) {
+	quota.Lock()
+	first := quota.since.IsZero()
+	if first {
+		quota.since = now
+		quota.fallbacks = 0
+	}
+	quota.lastTry = now
+	quota.Unlock()
+
+	if first {
+		log.WithField("module", "youtube").Warn("YouTube Data API quota exhausted, searching with yt-dlp until it resets")
+		sentry.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(sentry.LevelWarning)
+			scope.SetTag("youtube_quota", "exhausted")
+			sentry.CaptureMessage("YouTube Data API quota exhausted; searches are falling back to yt-dlp")
+		})
+	}
+}
+
+// markQuotaAvailable records a search the API answered.
+func markQuotaAvailable() {
+	quota.Lock()
+	defer quota.Unlock()
+	if !quota.since.IsZero() {
+		log.WithField("module", "youtube").Infof("YouTube Data API quota is back after %d yt-dlp searches", quota.fallbacks)
+		quota.since = time.Time{}
+	}
+}
+
+// isQuotaError reports whether err is the Data API refusing a call because
+// the key's daily quota is used up.
+func isQuotaError(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusForbidden {
+		return false
+	}
+	for _, item := range apiErr.Errors {
+		if item.Reason == "quotaExceeded" || item.Reason == "dailyLimitExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
+// queryViaYtdlp searches YouTube with yt-dlp's ytsearch, which needs no API
+// key. It's slower than the Data API, so it's only used when the quota is
+// gone. Like Query, it drops anything over 12 minutes that isn't live.
+func queryViaYtdlp(ctx context.Context, query string) ([]VideoResponse, error) {
+	quota.Lock()
+	quota.fallbacks++
+	quota.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, ytSearchTimeout)
+	defer cancel()
+
+	args := append([]string{
+		"--flat-playlist",
+		"--socket-timeout", "10",
+		"--no-warnings",
+		"--print", "%(id)s\t%(title)s\t%(duration)s\t%(channel)s\t%(live_status)s",
+	}, ytDlpAuthArgs()...)
+	args = append(args, "--", fmt.Sprintf("ytsearch%d:%s", ytSearchResults, query))
+	output, err := exec.CommandContext(ctx, "yt-dlp", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("yt-dlp search: %v", err)
+	}
+	return parseYtSearch(string(output)), nil
+}
+
+// queryFallback answers Query with a yt-dlp search.
+func queryFallback(span *sentry.Span, logger *log.Entry, query string) []VideoResponse {
+	span.SetData("source", "yt-dlp")
+	videos, err := queryViaYtdlp(span.Context(), query)
+	if err != nil {
+		logger.Errorf("fallback search failed: %v", err)
+		sentry.CaptureException(err)
+		span.Status = sentry.SpanStatusInternalError
+		return []VideoResponse{}
+	}
+	span.Status = sentry.SpanStatusOK
+	span.SetData("results_count", len(videos))
+	return videos
+}
+
+// parseYtSearch parses queryViaYtdlp's tab-separated output, one video per
+// line.
+func parseYtSearch(output string) []VideoResponse {
+	videos := make([]VideoResponse, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) != 5 || fields[0] == "" || fields[0] == "NA" {
+			continue
+		}
+		video := VideoResponse{
+			VideoID: fields[0],
+			Title:   fields[1],
+			Live:    fields[4] == "is_live",
+		}
+		if secs, err := strconv.ParseFloat(fields[2], 64); err == nil && !video.Live {
+			video.Duration = time.Duration(secs * float64(time.Second)).Round(time.Second)
+		}
+		if video.Duration > 12*time.Minute {
+			continue
+		}
+		if fields[3] != "NA" {
+			video.ChannelName = fields[3]
+		}
+		videos = append(videos, video)
+	}
+	return videos
+}
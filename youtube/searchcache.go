@@ -0,0 +1,71 @@
+package youtube
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// searchCacheSize bounds how many queries' results are kept.
+const searchCacheSize = 1000
+
+type cachedSearchResult struct {
+	videos  []VideoResponse
+	expires time.Time
+}
+
+// searches caches Query's results by normalized query. Only searches that
+// found something are kept, so a failed search is tried again next time.
+var searches struct {
+	sync.Mutex
+	byQuery map[string]cachedSearchResult
+}
+
+// searchKey normalizes a query so differences in case and spacing share one
+// cache entry.
+func searchKey(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// cachedSearch returns a copy of the results cached for key, if they haven't
+// expired at now.
+func cachedSearch(key string, now time.Time) ([]VideoResponse, bool) {
+	searches.Lock()
+	defer searches.Unlock()
+	entry, ok := searches.byQuery[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(entry.expires) {
+		delete(searches.byQuery, key)
+		return nil, false
+	}
+	return slices.Clone(entry.videos), true
+}
+
+// cacheSearch keeps a copy of videos for key until expires.
+func cacheSearch(key string, videos []VideoResponse, expires time.Time) {
+	searches.Lock()
+	defer searches.Unlock()
+	if searches.byQuery == nil {
+		searches.byQuery = make(map[string]cachedSearchResult)
+	}
+	if _, ok := searches.byQuery[key]; !ok && len(searches.byQuery) >= searchCacheSize {
+		evictSearches()
+	}
+	searches.byQuery[key] = cachedSearchResult{videos: slices.Clone(videos), expires: expires}
+}
+
+// evictSearches drops the entry closest to expiring, which is also the
+// oldest since every entry lives as long. Callers hold searches.
+func evictSearches() {
+	var soonest string
+	var soonestExpires time.Time
+	for key, entry := range searches.byQuery {
+		if soonestExpires.IsZero() || entry.expires.Before(soonestExpires) {
+			soonest, soonestExpires = key, entry.expires
+		}
+	}
+	delete(searches.byQuery, soonest)
+}
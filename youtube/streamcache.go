@@ -0,0 +1,112 @@
+package youtube
+
+import (
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// streamCacheMargin is taken off a stream URL's expiry so a cached URL
+	// isn't handed out moments before it stops working. ffmpeg reads the
+	// whole track up front, so a few minutes is plenty.
+	streamCacheMargin = 5 * time.Minute
+
+	// streamCacheSize bounds how many resolved URLs are kept.
+	streamCacheSize = 500
+)
+
+type cachedStream struct {
+	url     string
+	expires time.Time
+}
+
+// streams caches yt-dlp's stream URL for each track page, so a replay or a
+// repeated request while the URL is still valid skips the yt-dlp call. URLs
+// are signed for the IP that resolved them, so there's no sharing them
+// between instances through the database.
+var streams struct {
+	sync.Mutex
+	byPage map[string]cachedStream
+}
+
+// streamExpiry reads when a stream URL stops working from its expire
+// parameter, as YouTube's googlevideo URLs carry. It reports false for URLs
+// without one, which aren't cached since there's no telling how long they
+// last.
+func streamExpiry(streamURL string) (time.Time, bool) {
+	u, err := url.Parse(streamURL)
+	if err != nil {
+		return time.Time{}, false
+	}
+	expire, err := strconv.ParseInt(u.Query().Get("expire"), 10, 64)
+	if err != nil || expire <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(expire, 0), true
+}
+
+// cachedStreamURL returns the cached stream URL for page if it's still good
+// at now.
+func cachedStreamURL(page string, now time.Time) (string, bool) {
+	streams.Lock()
+	defer streams.Unlock()
+	entry, ok := streams.byPage[page]
+	if !ok {
+		return "", false
+	}
+	if !now.Before(entry.expires) {
+		delete(streams.byPage, page)
+		return "", false
+	}
+	return entry.url, true
+}
+
+// cacheStreamURL keeps streamURL for page until shortly before it expires.
+func cacheStreamURL(page, streamURL string, now time.Time) {
+	expire, ok := streamExpiry(streamURL)
+	if !ok {
+		return
+	}
+	expires := expire.Add(-streamCacheMargin)
+	if !now.Before(expires) {
+		return
+	}
+
+	streams.Lock()
+	defer streams.Unlock()
+	if streams.byPage == nil {
+		streams.byPage = make(map[string]cachedStream)
+	}
+	if len(streams.byPage) >= streamCacheSize {
+		evictStreams(now)
+	}
+	streams.byPage[page] = cachedStream{url: streamURL, expires: expires}
+}
+
+// evictStreams drops expired URLs, and the one closest to expiring if that
+// doesn't make room. Callers hold streams.
+func evictStreams(now time.Time) {
+	var soonest string
+	for page, entry := range streams.byPage {
+		if !now.Before(entry.expires) {
+			delete(streams.byPage, page)
+			continue
+		}
+		if soonest == "" || entry.expires.Before(streams.byPage[soonest].expires) {
+			soonest = page
+		}
+	}
+	if len(streams.byPage) >= streamCacheSize && soonest != "" {
+		delete(streams.byPage, soonest)
+	}
+}
+
+// ForgetStream drops video's cached stream URL, for when playing it failed
+// in a way that another yt-dlp call might fix, like a 403.
+func ForgetStream(video VideoResponse) {
+	streams.Lock()
+	defer streams.Unlock()
+	delete(streams.byPage, video.PageURL())
+}
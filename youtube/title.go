@@ -0,0 +1,102 @@
+package youtube
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxCombiningMarks caps stacked diacritics per base character. Real scripts
+// rarely need more than two after NFC composition; zalgo text piles on dozens.
+const maxCombiningMarks = 2
+
+// NormalizeTitle cleans an upstream title for display and TTS: invalid UTF-8
+// and control/invisible characters are dropped, the text is NFC-normalized,
+// zalgo-style stacked diacritics are trimmed, and whitespace is collapsed.
+// With stripEmoji, emoji and pictographs are removed too. Falls back to the
+// emoji-preserving form if stripping would leave nothing.
+func NormalizeTitle(title string, stripEmoji bool) string {
+	cleaned := cleanTitle(title, false)
+	if !stripEmoji {
+		return cleaned
+	}
+	if stripped := cleanTitle(title, true); stripped != "" {
+		return stripped
+	}
+	return cleaned
+}
+
+func cleanTitle(title string, stripEmoji bool) string {
+	title = norm.NFC.String(strings.ToValidUTF8(title, ""))
+
+	var b strings.Builder
+	b.Grow(len(title))
+	marks := 0
+	for _, r := range title {
+		switch {
+		case r == '\u200d' || r == '\ufe0f' || r == '\u20e3':
+			// ZWJ, emoji presentation selector, and keycap glue emoji sequences together
+			if stripEmoji {
+				continue
+			}
+		case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r):
+			marks++
+			if marks > maxCombiningMarks {
+				continue
+			}
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			if r == '\t' || r == '\n' || r == '\r' {
+				b.WriteRune(' ')
+			}
+			continue
+		case stripEmoji && isEmoji(r):
+			continue
+		default:
+			marks = 0
+		}
+		b.WriteRune(r)
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// isEmoji reports whether r falls in the pictograph/emoji blocks. Plain
+// symbols like ♪ or © are left alone.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // mahjong/cards, enclosed, pictographs, emoticons, transport, flags
+		return true
+	case r >= 0x2600 && r <= 0x27BF && !isMusicSymbol(r):
+		return true // misc symbols and dingbats
+	case r >= 0x2B00 && r <= 0x2BFF: // arrows/stars like ⭐
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // tag sequences (subdivision flags)
+		return true
+	}
+	return false
+}
+
+func isMusicSymbol(r rune) bool {
+	return r == '♩' || r == '♪' || r == '♫' || r == '♬'
+}
+
+// Normalize cleans Title in place, keeping the upstream title in RawTitle.
+// Safe to call more than once; normalization always starts from RawTitle.
+func (v *VideoResponse) Normalize(stripEmoji bool) {
+	if v.RawTitle == "" {
+		v.RawTitle = v.Title
+	}
+	v.Title = NormalizeTitle(v.RawTitle, stripEmoji)
+	if v.Title == v.RawTitle {
+		v.RawTitle = ""
+	}
+}
+
+// OriginalTitle returns the title exactly as the source reported it.
+func (v VideoResponse) OriginalTitle() string {
+	if v.RawTitle != "" {
+		return v.RawTitle
+	}
+	return v.Title
+}
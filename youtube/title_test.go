@@ -0,0 +1,51 @@
+package youtube
+
+import "testing"
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		name       string
+		title      string
+		stripEmoji bool
+		want       string
+	}{
+		{"plain title unchanged", "Daft Punk - One More Time", false, "Daft Punk - One More Time"},
+		{"decomposed accents composed to NFC", "Beyonce\u0301 - Halo", false, "Beyonc\u00e9 - Halo"},
+		{"control and zero-width characters removed", "Song\u200b Title\u0007", false, "Song Title"},
+		{"whitespace collapsed", "  Song \t\n  Title  ", false, "Song Title"},
+		{"zalgo marks trimmed", "Z\u0300\u0301\u0302\u0303\u0304algo", false, "Z\u0300\u0301algo"},
+		{"invalid utf-8 dropped", "Song\xff Title", false, "Song Title"},
+		{"emoji kept by default", "🔥 Banger 🔥", false, "🔥 Banger 🔥"},
+		{"emoji stripped", "🔥🔥 Banger 🔥 (Official Video) ⭐", true, "Banger (Official Video)"},
+		{"zwj sequences stripped whole", "Family \U0001F468\u200d\U0001F469\u200d\U0001F467 Song", true, "Family Song"},
+		{"music notes survive stripping", "♪ Lullaby ♪", true, "♪ Lullaby ♪"},
+		{"all-emoji title falls back", "🎵🎶", true, "🎵🎶"},
+		{"non-latin scripts untouched", "真夜中のドア / Stay With Me", true, "真夜中のドア / Stay With Me"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTitle(tt.title, tt.stripEmoji); got != tt.want {
+				t.Errorf("NormalizeTitle(%q, %v) = %q, want %q", tt.title, tt.stripEmoji, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVideoResponseNormalize(t *testing.T) {
+	v := VideoResponse{Title: "🔥 Banger 🔥"}
+
+	v.Normalize(true)
+	if v.Title != "Banger" || v.RawTitle != "🔥 Banger 🔥" {
+		t.Errorf("after Normalize(true): Title=%q RawTitle=%q", v.Title, v.RawTitle)
+	}
+
+	// Re-normalizing starts from the raw title, so emoji can come back.
+	v.Normalize(false)
+	if v.Title != "🔥 Banger 🔥" || v.RawTitle != "" {
+		t.Errorf("after Normalize(false): Title=%q RawTitle=%q", v.Title, v.RawTitle)
+	}
+	if v.OriginalTitle() != "🔥 Banger 🔥" {
+		t.Errorf("OriginalTitle() = %q", v.OriginalTitle())
+	}
+}
@@ -1,8 +1,15 @@
 package youtube
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
+
+	"google.golang.org/api/googleapi"
+
+	"beatbot/config"
 )
 
 func TestParseYouTubeURL(t *testing.T) {
@@ -21,6 +28,16 @@ func TestParseYouTubeURL(t *testing.T) {
 			url:  "https://www.youtube.com/watch?v=abc123&list=PLdef456",
 			want: YouTubeURLResult{VideoID: "abc123", PlaylistID: "PLdef456"},
 		},
+		{
+			name: "watch video with start time",
+			url:  "https://www.youtube.com/watch?v=abc123&t=1m30s",
+			want: YouTubeURLResult{VideoID: "abc123", Start: 90 * time.Second},
+		},
+		{
+			name: "watch video with start seconds",
+			url:  "https://www.youtube.com/watch?v=abc123&t=75",
+			want: YouTubeURLResult{VideoID: "abc123", Start: 75 * time.Second},
+		},
 		{
 			name: "playlist",
 			url:  "https://youtube.com/playlist?list=PL123456",
@@ -29,6 +46,61 @@ func TestParseYouTubeURL(t *testing.T) {
 		{
 			name: "youtu.be short",
 			url:  "https://youtu.be/dQw4w9WgXcQ",
+			want: YouTubeURLResult{VideoID: "dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtu.be with share tracking and timestamp",
+			url:  "https://youtu.be/dQw4w9WgXcQ?si=abc&t=42",
+			want: YouTubeURLResult{VideoID: "dQw4w9WgXcQ", Start: 42 * time.Second},
+		},
+		{
+			name: "shorts",
+			url:  "https://www.youtube.com/shorts/abc123",
+			want: YouTubeURLResult{VideoID: "abc123"},
+		},
+		{
+			name: "live",
+			url:  "https://youtube.com/live/abc123?feature=share",
+			want: YouTubeURLResult{VideoID: "abc123"},
+		},
+		{
+			name: "embed with start",
+			url:  "https://www.youtube.com/embed/abc123?start=30",
+			want: YouTubeURLResult{VideoID: "abc123", Start: 30 * time.Second},
+		},
+		{
+			name: "YouTube Music",
+			url:  "https://music.youtube.com/watch?v=abc123&feature=share",
+			want: YouTubeURLResult{VideoID: "abc123"},
+		},
+		{
+			name: "YouTube Music video in a personal mix",
+			url:  "https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RDAMVMdQw4w9WgXcQ",
+			want: YouTubeURLResult{VideoID: "dQw4w9WgXcQ"},
+		},
+		{
+			name: "mix link without a video plays its seed",
+			url:  "https://music.youtube.com/watch?list=RDAMVMdQw4w9WgXcQ",
+			want: YouTubeURLResult{VideoID: "dQw4w9WgXcQ"},
+		},
+		{
+			name: "YouTube Music radio playlist",
+			url:  "https://music.youtube.com/playlist?list=RDCLAK5uy_kmPRjHDECIcuVwnKsx2Ng7fyNgFKWNJFs",
+			want: YouTubeURLResult{PlaylistID: "RDCLAK5uy_kmPRjHDECIcuVwnKsx2Ng7fyNgFKWNJFs"},
+		},
+		{
+			name: "YouTube Music album playlist",
+			url:  "https://music.youtube.com/playlist?list=OLAK5uy_abc",
+			want: YouTubeURLResult{PlaylistID: "OLAK5uy_abc"},
+		},
+		{
+			name: "mobile",
+			url:  "https://m.youtube.com/watch?v=abc123",
+			want: YouTubeURLResult{VideoID: "abc123"},
+		},
+		{
+			name: "channel page",
+			url:  "https://www.youtube.com/@channel",
 			want: YouTubeURLResult{},
 		},
 		{
@@ -56,6 +128,68 @@ func TestParseYouTubeURL(t *testing.T) {
 	}
 }
 
+func TestParseYoutubeUrl(t *testing.T) {
+	for _, link := range []string{
+		"https://www.youtube.com/watch?v=abc123",
+		"https://youtu.be/abc123",
+		"https://youtube.com/shorts/abc123/",
+		"https://music.youtube.com/watch?v=abc123",
+	} {
+		if got := ParseYoutubeUrl(link); got != "abc123" {
+			t.Errorf("ParseYoutubeUrl(%q) = %q, want abc123", link, got)
+		}
+	}
+	if got := ParseYoutubeUrl("https://example.com/watch?v=abc123"); got != "" {
+		t.Errorf("non-YouTube link gave %q", got)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"90", 90 * time.Second, true},
+		{"1:30", 90 * time.Second, true},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, true},
+		{"0:05", 5 * time.Second, true},
+		{"90s", 90 * time.Second, true},
+		{"1m30s", 90 * time.Second, true},
+		{"1:75", 0, false},
+		{"1:2:3:4", 0, false},
+		{"-5", 0, false},
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseTimestamp(%q) = %v, %v; want %v, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestVideoResponseClip(t *testing.T) {
+	v := VideoResponse{Duration: 5 * time.Minute}
+	v.Clip(90*time.Second, 0)
+	if v.Duration != 210*time.Second || !v.Clipped() {
+		t.Errorf("open-ended clip: Duration = %v, Clipped = %v", v.Duration, v.Clipped())
+	}
+
+	v = VideoResponse{Duration: 5 * time.Minute}
+	v.Clip(90*time.Second, 225*time.Second)
+	if v.Duration != 135*time.Second {
+		t.Errorf("bounded clip: Duration = %v, want 2m15s", v.Duration)
+	}
+
+	v = VideoResponse{}
+	v.Clip(30*time.Second, 0)
+	if v.Duration != 0 {
+		t.Errorf("unknown length: Duration = %v, want 0", v.Duration)
+	}
+}
+
 func TestParseYoutubeDuration(t *testing.T) {
 	tests := []struct {
 		name string
@@ -111,3 +245,223 @@ func TestParseYoutubeDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestIsQuotaError(t *testing.T) {
+	quotaErr := &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}
+	if !isQuotaError(fmt.Errorf("search: %w", quotaErr)) {
+		t.Error("wrapped quotaExceeded not detected")
+	}
+	forbidden := &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}}
+	if isQuotaError(forbidden) || isQuotaError(errors.New("quotaExceeded")) || isQuotaError(nil) {
+		t.Error("non-quota error reported as quota")
+	}
+}
+
+func TestParseYtSearch(t *testing.T) {
+	output := "abc123\tSong One\t215.0\tSome Artist\tnot_live\n" +
+		"live456\tLofi Radio\tNA\tLofi Girl\tis_live\n" +
+		"long789\tFull Album\t3600\tBand\tnot_live\n" +
+		"NA\tbroken\tNA\tNA\tNA\n" +
+		"def000\tNo Channel\t60\tNA\tNA\n"
+	videos := parseYtSearch(output)
+	if len(videos) != 3 {
+		t.Fatalf("got %d videos, want 3: %+v", len(videos), videos)
+	}
+	if v := videos[0]; v.VideoID != "abc123" || v.Duration != 215*time.Second || v.ChannelName != "Some Artist" || v.Live {
+		t.Errorf("first = %+v", v)
+	}
+	if v := videos[1]; !v.Live || v.Duration != 0 {
+		t.Errorf("live = %+v", v)
+	}
+	if v := videos[2]; v.ChannelName != "" {
+		t.Errorf("channel = %q, want empty", v.ChannelName)
+	}
+}
+
+func TestQuotaStatusLifecycle(t *testing.T) {
+	now := time.Now()
+	markQuotaExhausted(now)
+	t.Cleanup(markQuotaAvailable)
+
+	if status := GetQuotaStatus(); !status.Exhausted || !status.RetryAt.Equal(now.Add(quotaRetryInterval)) {
+		t.Errorf("status = %+v", status)
+	}
+	if !skipAPI(now.Add(time.Minute)) {
+		t.Error("API not skipped right after the quota ran out")
+	}
+	if skipAPI(now.Add(quotaRetryInterval)) {
+		t.Error("API still skipped after the retry interval")
+	}
+	markQuotaAvailable()
+	if GetQuotaStatus().Exhausted {
+		t.Error("still exhausted after the API answered")
+	}
+}
+
+func TestRestrictionError(t *testing.T) {
+	tests := []struct {
+		output string
+		want   error
+	}{
+		{"ERROR: [youtube] abc: Sign in to confirm your age. This video may be inappropriate for some users.", ErrAgeRestricted},
+		{"ERROR: [youtube] abc: Video unavailable. The uploader has not made this video available in your country", ErrRegionLocked},
+		{"ERROR: [youtube] abc: This video is not available from your location", ErrRegionLocked},
+		{"ERROR: [youtube] abc: Private video", nil},
+	}
+	for _, tt := range tests {
+		if got := restrictionError(tt.output); got != tt.want {
+			t.Errorf("restrictionError(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+	if !IsRestricted(fmt.Errorf("stream: %w", ErrRegionLocked)) || IsRestricted(errors.New("timeout")) {
+		t.Error("IsRestricted misclassified")
+	}
+}
+
+func TestYtDlpAuthArgs(t *testing.T) {
+	saved := config.Config
+	t.Cleanup(func() { config.Config = saved })
+
+	config.Config = &config.ConfigStruct{}
+	if args := ytDlpAuthArgs(); len(args) != 0 {
+		t.Errorf("no auth configured: args = %v", args)
+	}
+
+	config.Config.Youtube.CookiesFile = "/data/cookies.txt"
+	config.Config.Youtube.POToken = "web.gvs+tok"
+	want := []string{"--cookies", "/data/cookies.txt", "--extractor-args", "youtube:po_token=web.gvs+tok"}
+	if got := ytDlpAuthArgs(); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("args = %v, want %v", got, want)
+	}
+}
+
+func TestParseFlatPlaylist(t *testing.T) {
+	output := `{"title": "Chill Hits", "playlist_count": 50, "entries": [
+		{"id": "abc12345678", "title": "First", "duration": 201.4, "channel": "Artist - Topic"},
+		{"id": "", "title": "[Deleted video]"},
+		{"id": "def12345678", "title": "Second", "duration": null, "uploader": "Uploader"}
+	]}`
+	result, err := parseFlatPlaylist("RDCLAK5uy_x", []byte(output))
+	if err != nil {
+		t.Fatalf("parseFlatPlaylist: %v", err)
+	}
+	if result.Name != "Chill Hits" || result.TotalVideos != 50 || len(result.Videos) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	if v := result.Videos[0]; v.Duration != 201*time.Second || v.ChannelName != "Artist - Topic" {
+		t.Errorf("first = %+v", v)
+	}
+	if v := result.Videos[1]; v.Duration != 0 || v.ChannelName != "Uploader" || v.Position != 2 {
+		t.Errorf("second = %+v", v)
+	}
+
+	if _, err := parseFlatPlaylist("RDCLAK5uy_x", []byte(`{"title": "Empty", "entries": []}`)); err == nil ||
+		!strings.Contains(err.Error(), "empty") {
+		t.Errorf("empty playlist error = %v", err)
+	}
+}
+
+func TestParseChapters(t *testing.T) {
+	for _, output := range []string{"NA", "null", ""} {
+		if chapters, err := parseChapters(output); err != nil || chapters != nil {
+			t.Errorf("parseChapters(%q) = %v, %v; want none", output, chapters, err)
+		}
+	}
+
+	output := `[{"start_time": 0.0, "title": "Intro", "end_time": 95.0},
+		{"start_time": 95.0, "title": "  ", "end_time": 301.6},
+		{"start_time": 301.6, "title": "Broken", "end_time": 301.6},
+		{"start_time": 301.6, "title": "Outro", "end_time": 420.0}]`
+	chapters, err := parseChapters(output)
+	if err != nil {
+		t.Fatalf("parseChapters: %v", err)
+	}
+	want := []Chapter{
+		{Title: "Intro", Start: 0, End: 95 * time.Second},
+		{Title: "Chapter 2", Start: 95 * time.Second, End: 302 * time.Second},
+		{Title: "Outro", Start: 302 * time.Second, End: 420 * time.Second},
+	}
+	if fmt.Sprint(chapters) != fmt.Sprint(want) {
+		t.Errorf("parseChapters = %v, want %v", chapters, want)
+	}
+}
+
+func TestChapterTracks(t *testing.T) {
+	video := VideoResponse{Title: "Full Album", VideoID: "abc123", Duration: 420 * time.Second, ChannelName: "Band"}
+	tracks := ChapterTracks(video, []Chapter{
+		{Title: "Intro", Start: 0, End: 95 * time.Second},
+		{Title: "Outro", Start: 302 * time.Second, End: 420 * time.Second},
+	})
+	if len(tracks) != 2 {
+		t.Fatalf("got %d tracks", len(tracks))
+	}
+	if first := tracks[0]; first.Title != "Intro" || first.EndAt != 95*time.Second || first.Duration != 95*time.Second || first.ChannelName != "Band" {
+		t.Errorf("first = %+v", first)
+	}
+	// The last chapter plays out rather than stopping at its end mark.
+	if last := tracks[1]; last.StartAt != 302*time.Second || last.EndAt != 0 || last.Duration != 118*time.Second {
+		t.Errorf("last = %+v", last)
+	}
+}
+
+func TestStreamExpiry(t *testing.T) {
+	if got, ok := streamExpiry("https://rr1.googlevideo.com/videoplayback?expire=1700000000&ei=x"); !ok || !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("googlevideo URL: got %v, %v", got, ok)
+	}
+	for _, u := range []string{"https://cf-media.sndcdn.com/abc.mp3", "https://x/?expire=soon", "::"} {
+		if _, ok := streamExpiry(u); ok {
+			t.Errorf("streamExpiry(%q) reported an expiry", u)
+		}
+	}
+}
+
+func TestStreamCache(t *testing.T) {
+	t.Cleanup(func() { streams.byPage = nil })
+	now := time.Unix(1700000000, 0)
+	video := VideoResponse{VideoID: "abc"}
+	page := video.PageURL()
+	streamURL := fmt.Sprintf("https://rr1.googlevideo.com/videoplayback?expire=%d", now.Add(6*time.Hour).Unix())
+
+	cacheStreamURL(page, streamURL, now)
+	if got, ok := cachedStreamURL(page, now.Add(time.Hour)); !ok || got != streamURL {
+		t.Errorf("within the window: got %q, %v", got, ok)
+	}
+	if _, ok := cachedStreamURL(page, now.Add(6*time.Hour-streamCacheMargin)); ok {
+		t.Error("served inside the expiry margin")
+	}
+
+	cacheStreamURL(page, streamURL, now)
+	ForgetStream(video)
+	if _, ok := cachedStreamURL(page, now); ok {
+		t.Error("served after ForgetStream")
+	}
+
+	cacheStreamURL(page, "https://cf-media.sndcdn.com/abc.mp3", now)
+	if _, ok := cachedStreamURL(page, now); ok {
+		t.Error("cached a URL without an expiry")
+	}
+}
+
+func TestSearchCache(t *testing.T) {
+	t.Cleanup(func() { searches.byQuery = nil })
+	now := time.Now()
+	videos := []VideoResponse{{VideoID: "abc", Title: "Song"}}
+
+	if searchKey("  Daft  Punk\tOne More Time ") != "daft punk one more time" {
+		t.Errorf("searchKey = %q", searchKey("  Daft  Punk\tOne More Time "))
+	}
+	cacheSearch(searchKey("Daft Punk"), videos, now.Add(time.Hour))
+	videos[0].Title = "changed"
+
+	got, ok := cachedSearch(searchKey("daft punk"), now)
+	if !ok || len(got) != 1 || got[0].Title != "Song" {
+		t.Fatalf("cachedSearch = %+v, %v", got, ok)
+	}
+	got[0].Title = "changed"
+	if again, _ := cachedSearch(searchKey("daft punk"), now); again[0].Title != "Song" {
+		t.Error("a caller's edit leaked into the cache")
+	}
+	if _, ok := cachedSearch(searchKey("daft punk"), now.Add(time.Hour)); ok {
+		t.Error("served after expiry")
+	}
+}
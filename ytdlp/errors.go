@@ -0,0 +1,109 @@
+package ytdlp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// errorWindow is how far back the extraction failure rate looks.
+	errorWindow = 15 * time.Minute
+	// minExtractions keeps a couple of bad videos on a quiet night from
+	// counting as a spike.
+	minExtractions = 10
+	// alertRate is the failure rate that raises an alert. The alert clears
+	// once the rate falls below half of it.
+	alertRate = 0.5
+)
+
+type extraction struct {
+	at     time.Time
+	failed bool
+}
+
+var extractions struct {
+	sync.Mutex
+	recent   []extraction
+	alerting bool
+}
+
+// RecordExtraction notes whether yt-dlp found a stream for a video. Videos
+// YouTube refuses on purpose (age or region blocks) shouldn't be recorded:
+// a new yt-dlp won't fix them.
+func RecordExtraction(failed bool) {
+	recordExtraction(time.Now(), failed)
+}
+
+func recordExtraction(now time.Time, failed bool) {
+	extractions.Lock()
+	extractions.recent = append(pruneExtractions(now), extraction{at: now, failed: failed})
+	total, failures := countExtractions()
+	rate := float64(failures) / float64(total)
+
+	alert, recovered := false, false
+	switch {
+	case !extractions.alerting && total >= minExtractions && rate >= alertRate:
+		extractions.alerting = true
+		alert = true
+	case extractions.alerting && rate < alertRate/2:
+		extractions.alerting = false
+		recovered = true
+	}
+	extractions.Unlock()
+
+	if alert {
+		alertSpike(total, failures)
+	}
+	if recovered {
+		log.WithField("module", "ytdlp").Infof("yt-dlp extraction failures are back down to %d of %d", failures, total)
+	}
+}
+
+// errorRate returns the extractions and failures in the window, and whether
+// an alert is active.
+func errorRate(now time.Time) (total, failures int, alerting bool) {
+	extractions.Lock()
+	defer extractions.Unlock()
+	extractions.recent = pruneExtractions(now)
+	total, failures = countExtractions()
+	return total, failures, extractions.alerting
+}
+
+// pruneExtractions drops extractions older than errorWindow. Callers hold
+// the lock.
+func pruneExtractions(now time.Time) []extraction {
+	i := 0
+	for i < len(extractions.recent) && now.Sub(extractions.recent[i].at) > errorWindow {
+		i++
+	}
+	return extractions.recent[i:]
+}
+
+func countExtractions() (total, failures int) {
+	for _, e := range extractions.recent {
+		if e.failed {
+			failures++
+		}
+	}
+	return len(extractions.recent), failures
+}
+
+// alertSpike reports a failure spike to Sentry once, until the rate recovers.
+func alertSpike(total, failures int) {
+	state.Lock()
+	version := state.version
+	state.Unlock()
+
+	log.WithField("module", "ytdlp").Warnf("yt-dlp failed %d of the last %d extractions on %s", failures, total, version)
+	sentry.WithScope(func(scope *sentry.Scope) {
+		scope.SetLevel(sentry.LevelWarning)
+		scope.SetTag("ytdlp_version", version)
+		scope.SetContext("ytdlp", sentry.Context{"failures": failures, "extractions": total})
+		sentry.CaptureMessage(fmt.Sprintf("yt-dlp extraction failures spiking (%d of %d in %s); an update may fix it",
+			failures, total, errorWindow))
+	})
+}
@@ -0,0 +1,204 @@
+// Package ytdlp keeps the yt-dlp binary current. YouTube changes break
+// yt-dlp's extractors often, and the fix is almost always a new yt-dlp
+// release, so the bot checks the version at startup, updates it on a
+// schedule or when an operator asks, and warns when extraction starts
+// failing.
+package ytdlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	versionTimeout = 10 * time.Second
+	updateTimeout  = 2 * time.Minute
+)
+
+// Status is the yt-dlp state reported on /health.
+type Status struct {
+	Version     string    `json:"version"`
+	Pinned      string    `json:"pinned,omitempty"`
+	LastUpdate  time.Time `json:"last_update,omitempty"` // last update attempt
+	UpdateError string    `json:"update_error,omitempty"`
+	Extractions int       `json:"extractions"` // stream lookups in the error-rate window
+	Failures    int       `json:"failures"`
+	Alerting    bool      `json:"alerting"` // failure rate is over the alert threshold
+}
+
+var state struct {
+	sync.Mutex
+	version    string
+	pinned     string
+	lastUpdate time.Time
+	updateErr  string
+}
+
+// updating serializes updates, so a scheduled update and an admin request
+// don't both rewrite the binary.
+var updating sync.Mutex
+
+// runCommand runs yt-dlp and returns its trimmed combined output. Tests
+// replace it.
+var runCommand = func(ctx context.Context, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, "yt-dlp", args...).CombinedOutput()
+	return strings.TrimSpace(string(output)), err
+}
+
+// Init records the installed version and, when pinned is set, moves yt-dlp
+// to it. pinned is anything yt-dlp's --update-to accepts: a version
+// ("2025.01.26"), a channel ("nightly") or both ("stable@2025.01.26").
+func Init(ctx context.Context, pinned string) {
+	state.Lock()
+	state.pinned = pinned
+	state.Unlock()
+
+	version, err := readVersion(ctx)
+	if err != nil {
+		log.WithField("module", "ytdlp").Errorf("yt-dlp isn't runnable: %v", err)
+		sentry.CaptureException(fmt.Errorf("yt-dlp version check: %w", err))
+		return
+	}
+	log.WithField("module", "ytdlp").Infof("yt-dlp %s", version)
+
+	if pinned == "" || pinnedVersion(pinned) == version {
+		return
+	}
+	if _, _, err := Update(ctx); err != nil {
+		log.WithField("module", "ytdlp").Warnf("Couldn't move yt-dlp to pinned %s, staying on %s: %v", pinned, version, err)
+	}
+}
+
+// Update updates yt-dlp to the pinned version, or to the latest release when
+// nothing is pinned, and returns the versions before and after.
+func Update(ctx context.Context) (before, after string, err error) {
+	updating.Lock()
+	defer updating.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	state.Lock()
+	before = state.version
+	pinned := state.pinned
+	state.Unlock()
+
+	args := []string{"-U"}
+	if pinned != "" {
+		args = []string{"--update-to", pinned}
+	}
+	output, runErr := runCommand(ctx, args...)
+	if runErr != nil {
+		err = fmt.Errorf("yt-dlp %s: %v: %s", strings.Join(args, " "), runErr, lastLine(output))
+	}
+
+	after, versionErr := readVersion(ctx)
+	if err == nil && versionErr != nil {
+		err = versionErr
+	}
+
+	state.Lock()
+	state.lastUpdate = time.Now()
+	state.updateErr = ""
+	if err != nil {
+		state.updateErr = err.Error()
+	}
+	state.Unlock()
+
+	logger := log.WithField("module", "ytdlp")
+	switch {
+	case err != nil:
+		logger.Errorf("yt-dlp update failed: %v", err)
+		sentry.CaptureException(err)
+	case before != after:
+		logger.Infof("Updated yt-dlp from %s to %s", before, after)
+	default:
+		logger.Debugf("yt-dlp %s is up to date", after)
+	}
+	return before, after, err
+}
+
+// StartAutoUpdate updates yt-dlp every interval until ctx is done. It does
+// nothing when interval is 0 or a version is pinned.
+func StartAutoUpdate(ctx context.Context, interval time.Duration) {
+	state.Lock()
+	pinned := state.pinned
+	state.Unlock()
+	if interval <= 0 || pinned != "" {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				Update(ctx)
+			}
+		}
+	}()
+}
+
+// GetStatus returns the active version, the last update and the recent
+// extraction error rate.
+func GetStatus() Status {
+	state.Lock()
+	status := Status{
+		Version:     state.version,
+		Pinned:      state.pinned,
+		LastUpdate:  state.lastUpdate,
+		UpdateError: state.updateErr,
+	}
+	state.Unlock()
+
+	status.Extractions, status.Failures, status.Alerting = errorRate(time.Now())
+	return status
+}
+
+// readVersion asks yt-dlp for its version and records it.
+func readVersion(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+
+	output, err := runCommand(ctx, "--version")
+	if err != nil {
+		return "", fmt.Errorf("yt-dlp --version: %v", err)
+	}
+	version := lastLine(output)
+	if version == "" {
+		return "", errors.New("yt-dlp --version printed nothing")
+	}
+
+	state.Lock()
+	state.version = version
+	state.Unlock()
+	return version, nil
+}
+
+// pinnedVersion returns the exact version a pin names, or "" when it names
+// only a channel and so can't be compared with the installed version.
+func pinnedVersion(pinned string) string {
+	if _, version, ok := strings.Cut(pinned, "@"); ok {
+		pinned = version
+	}
+	if pinned == "" || pinned[0] < '0' || pinned[0] > '9' {
+		return ""
+	}
+	return pinned
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,148 @@
+package ytdlp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeYtdlp stands in for the binary: --version prints version, and an
+// update moves version to target.
+func fakeYtdlp(t *testing.T, version *string, target string, calls *[]string) {
+	t.Helper()
+	orig := runCommand
+	t.Cleanup(func() { runCommand = orig })
+	runCommand = func(ctx context.Context, args ...string) (string, error) {
+		*calls = append(*calls, strings.Join(args, " "))
+		switch args[0] {
+		case "--version":
+			return *version, nil
+		case "-U", "--update-to":
+			if target == "" {
+				return "ERROR: You installed yt-dlp with pip", errors.New("exit status 1")
+			}
+			*version = target
+			return "Updated yt-dlp to " + target, nil
+		}
+		return "", errors.New("unexpected args")
+	}
+}
+
+func TestInitPinned(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed string
+		pinned    string
+		wantCalls []string
+		want      string
+	}{
+		{
+			name:      "not pinned",
+			installed: "2025.01.15",
+			wantCalls: []string{"--version"},
+			want:      "2025.01.15",
+		},
+		{
+			name:      "already on pin",
+			installed: "2025.01.15",
+			pinned:    "stable@2025.01.15",
+			wantCalls: []string{"--version"},
+			want:      "2025.01.15",
+		},
+		{
+			name:      "moves to pin",
+			installed: "2025.03.01",
+			pinned:    "2025.01.15",
+			wantCalls: []string{"--version", "--update-to 2025.01.15", "--version"},
+			want:      "2025.01.15",
+		},
+		{
+			name:      "channel pin always updates",
+			installed: "2025.03.01",
+			pinned:    "nightly",
+			wantCalls: []string{"--version", "--update-to nightly", "--version"},
+			want:      "2025.03.02.232809",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, calls := tt.installed, []string{}
+			target := strings.TrimPrefix(tt.pinned, "stable@")
+			if tt.pinned == "nightly" {
+				target = "2025.03.02.232809"
+			}
+			fakeYtdlp(t, &version, target, &calls)
+
+			Init(context.Background(), tt.pinned)
+			if strings.Join(calls, "|") != strings.Join(tt.wantCalls, "|") {
+				t.Errorf("calls = %q, want %q", calls, tt.wantCalls)
+			}
+			if got := GetStatus(); got.Version != tt.want || got.Pinned != tt.pinned {
+				t.Errorf("status = %+v, want version %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateFailure(t *testing.T) {
+	version, calls := "2025.01.15", []string{}
+	fakeYtdlp(t, &version, "", &calls)
+	Init(context.Background(), "")
+
+	before, after, err := Update(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "pip") {
+		t.Fatalf("Update error = %v, want the pip message", err)
+	}
+	if before != "2025.01.15" || after != "2025.01.15" {
+		t.Errorf("versions = %s -> %s", before, after)
+	}
+	if status := GetStatus(); status.UpdateError == "" || status.LastUpdate.IsZero() {
+		t.Errorf("status = %+v, want the failed update recorded", status)
+	}
+}
+
+func TestExtractionAlert(t *testing.T) {
+	t.Cleanup(func() {
+		extractions.recent, extractions.alerting = nil, false
+	})
+	extractions.recent, extractions.alerting = nil, false
+	start := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+
+	// Failures below the minimum sample size don't alert.
+	for i := range minExtractions - 1 {
+		recordExtraction(start.Add(time.Duration(i)*time.Second), true)
+	}
+	if _, _, alerting := errorRate(start.Add(time.Minute)); alerting {
+		t.Fatal("alerting before minExtractions")
+	}
+
+	recordExtraction(start.Add(time.Minute), true)
+	total, failures, alerting := errorRate(start.Add(time.Minute))
+	if !alerting || total != minExtractions || failures != minExtractions {
+		t.Fatalf("errorRate = %d, %d, %v; want an alert", total, failures, alerting)
+	}
+
+	// Once the failures age out of the window, successes clear the alert.
+	later := start.Add(errorWindow + 2*time.Minute)
+	recordExtraction(later, false)
+	if total, failures, alerting := errorRate(later); alerting || total != 1 || failures != 0 {
+		t.Errorf("after recovery errorRate = %d, %d, %v", total, failures, alerting)
+	}
+}
+
+func TestPinnedVersion(t *testing.T) {
+	tests := map[string]string{
+		"2025.01.15":        "2025.01.15",
+		"stable@2025.01.15": "2025.01.15",
+		"nightly":           "",
+		"nightly@":          "",
+		"":                  "",
+	}
+	for in, want := range tests {
+		if got := pinnedVersion(in); got != want {
+			t.Errorf("pinnedVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}